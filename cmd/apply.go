@@ -0,0 +1,371 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	aply "github.com/plusworx/gmin/utils/apply"
+	btch "github.com/plusworx/gmin/utils/batch"
+	cmn "github.com/plusworx/gmin/utils/common"
+	cfg "github.com/plusworx/gmin/utils/config"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	grps "github.com/plusworx/gmin/utils/groups"
+	grpset "github.com/plusworx/gmin/utils/groupsettings"
+	lg "github.com/plusworx/gmin/utils/logging"
+	mems "github.com/plusworx/gmin/utils/members"
+	ous "github.com/plusworx/gmin/utils/orgunits"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+	gset "google.golang.org/api/groupssettings/v1"
+)
+
+var applyCmd = &cobra.Command{
+	Use:  "apply -f <state file>",
+	Args: cobra.NoArgs,
+	Example: `gmin apply -f state.yaml
+gmin apply -f state.yaml --dry-run
+gmin apply -f state.yaml --prune`,
+	Short: "Makes orgunits, groups, group settings and members match a state file",
+	Long: `Makes orgunits, groups, group settings and members match a YAML state file.
+
+The state file is compared with the tenant to produce a plan of creates, updates and deletes which is
+shown and then carried out. Parent orgunits are created before their children, groups are created before
+their settings and members are changed, and deletions are carried out last. Use the global --dry-run flag
+to show the plan without carrying it out.
+
+Only the attributes and settings given in the state file are compared. If a group has a members list then
+any members of the group that are not in the list are deleted. Orgunits and groups that are not in the
+state file are only deleted if the --prune flag is used.
+
+State file format
+-----------------
+orgunits:
+  - path: /Sales
+    description: Sales department
+  - path: /Sales/EMEA
+    blockInheritance: false
+groups:
+  - email: sales@mycompany.org
+    name: Sales
+    description: Sales team
+    settings:
+      whoCanJoin: invited_can_join
+      whoCanPostMessage: all_members_can_post
+    members:
+      - email: sales.manager@mycompany.org
+        role: owner
+      - email: sales.person@mycompany.org
+
+Member role defaults to member. Group settings names and values are the same as those used by the
+batch-manage group-settings command.`,
+	RunE: doApply,
+}
+
+func doApply(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doApply()",
+		"args", args)
+	defer lg.Debug("finished doApply()")
+
+	flgFileVal, err := cmd.Flags().GetString(flgnm.FLG_FILE)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgFileVal == "" {
+		err = errors.New(gmess.ERR_NOINPUTFILE)
+		lg.Error(err)
+		return err
+	}
+
+	flgPruneVal, err := cmd.Flags().GetBool(flgnm.FLG_PRUNE)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	flgQPSVal, err := cmd.Flags().GetFloat64(flgnm.FLG_QPS)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgQPSVal < 0 {
+		err = fmt.Errorf(gmess.ERR_INVALIDQPS, flgQPSVal)
+		lg.Error(err)
+		return err
+	}
+
+	state, err := aply.Load(flgFileVal)
+	if err != nil {
+		return err
+	}
+
+	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
+	if err != nil {
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryGroupScope, admin.AdminDirectoryOrgunitScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	srv, err = cmn.CreateService(cmn.SRVTYPEGRPSETTING, gset.AppsGroupsSettingsScope)
+	if err != nil {
+		return err
+	}
+	gss := srv.(*gset.Service)
+
+	current, err := applyCurrent(ds, gss, customerID, state)
+	if err != nil {
+		return err
+	}
+
+	plan, err := aply.Plan(state, current, flgPruneVal)
+	if err != nil {
+		return err
+	}
+
+	return applyPlan(ds, gss, customerID, plan, flgQPSVal)
+}
+
+// applyPlan shows a plan and then carries out its actions in order unless dry run is set
+func applyPlan(ds *admin.Service, gss *gset.Service, customerID string, plan []aply.Action, qps float64) error {
+	lg.Debugw("starting applyPlan()",
+		"qps", qps)
+	defer lg.Debug("finished applyPlan()")
+
+	if len(plan) == 0 {
+		fmt.Println(cmn.GminMessage(gmess.INFO_APPLYNOCHANGES))
+		lg.Info(gmess.INFO_APPLYNOCHANGES)
+		return nil
+	}
+
+	creates, updates, deletes := aply.Summary(plan)
+	fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_APPLYPLAN, creates, updates, deletes)))
+	lg.Infof(gmess.INFO_APPLYPLAN, creates, updates, deletes)
+	for _, action := range plan {
+		fmt.Println(action)
+	}
+
+	if cmn.DryRun {
+		return nil
+	}
+
+	dirQPS, gsQPS, ouQPS := btch.DIRECTORYQPS, btch.GRPSETTINGSQPS, btch.ORGUNITQPS
+	if qps > 0 {
+		dirQPS, gsQPS, ouQPS = qps, qps, qps
+	}
+	dirLimiter := btch.NewLimiter(dirQPS)
+	limiters := map[int]*btch.Limiter{
+		cmn.OBJTYPEGROUP:    dirLimiter,
+		cmn.OBJTYPEGRPALIAS: dirLimiter,
+		cmn.OBJTYPEGRPSET:   btch.NewLimiter(gsQPS),
+		cmn.OBJTYPEMEMBER:   dirLimiter,
+		cmn.OBJTYPEORGUNIT:  btch.NewLimiter(ouQPS),
+		cmn.OBJTYPEUSER:     dirLimiter,
+		cmn.OBJTYPEUSRALIAS: dirLimiter,
+	}
+
+	for _, action := range plan {
+		limiters[action.ObjType].Wait()
+
+		err := applyAction(ds, gss, customerID, action)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func applyAction(ds *admin.Service, gss *gset.Service, customerID string, action aply.Action) error {
+	lg.Debugw("starting applyAction()",
+		"key", action.Key)
+	defer lg.Debug("finished applyAction()")
+
+	var (
+		err error
+		msg string
+	)
+
+	switch action.ObjType {
+	case cmn.OBJTYPEGROUP:
+		switch action.CallType {
+		case cmn.CALLTYPECREATE:
+			gic := ds.Groups.Insert(action.Object.(*admin.Group))
+			_, err = gic.Do()
+			msg = fmt.Sprintf(gmess.INFO_GROUPCREATED, action.Key)
+		case cmn.CALLTYPEDELETE:
+			gdc := ds.Groups.Delete(action.Key)
+			err = gdc.Do()
+			msg = fmt.Sprintf(gmess.INFO_GROUPDELETED, action.Key)
+		case cmn.CALLTYPEUPDATE:
+			guc := ds.Groups.Update(action.Key, action.Object.(*admin.Group))
+			_, err = guc.Do()
+			msg = fmt.Sprintf(gmess.INFO_GROUPUPDATED, action.Key)
+		}
+	case cmn.OBJTYPEGRPALIAS:
+		gaic := ds.Groups.Aliases.Insert(action.Group, action.Object.(*admin.Alias))
+		_, err = gaic.Do()
+		msg = fmt.Sprintf(gmess.INFO_GROUPALIASCREATED, action.Key, action.Group)
+	case cmn.OBJTYPEGRPSET:
+		gsuc := gss.Groups.Update(action.Key, action.Object.(*gset.Groups))
+		_, err = gsuc.Do()
+		msg = fmt.Sprintf(gmess.INFO_GROUPSETTINGSCHANGED, action.Key)
+	case cmn.OBJTYPEMEMBER:
+		switch action.CallType {
+		case cmn.CALLTYPECREATE:
+			mic := ds.Members.Insert(action.Group, action.Object.(*admin.Member))
+			_, err = mic.Do()
+			msg = fmt.Sprintf(gmess.INFO_MEMBERCREATED, action.Key, action.Group)
+		case cmn.CALLTYPEDELETE:
+			mdc := ds.Members.Delete(action.Group, action.Key)
+			err = mdc.Do()
+			msg = fmt.Sprintf(gmess.INFO_MEMBERDELETED, action.Key, action.Group)
+		case cmn.CALLTYPEUPDATE:
+			muc := ds.Members.Update(action.Group, action.Key, action.Object.(*admin.Member))
+			_, err = muc.Do()
+			msg = fmt.Sprintf(gmess.INFO_MEMBERUPDATED, action.Key, action.Group)
+		}
+	case cmn.OBJTYPEORGUNIT:
+		ouPath := strings.TrimPrefix(action.Key, "/")
+		switch action.CallType {
+		case cmn.CALLTYPECREATE:
+			ouic := ds.Orgunits.Insert(customerID, action.Object.(*admin.OrgUnit))
+			_, err = ouic.Do()
+			msg = fmt.Sprintf(gmess.INFO_OUCREATED, action.Key)
+		case cmn.CALLTYPEDELETE:
+			oudc := ds.Orgunits.Delete(customerID, ouPath)
+			err = oudc.Do()
+			msg = fmt.Sprintf(gmess.INFO_OUDELETED, action.Key)
+		case cmn.CALLTYPEUPDATE:
+			ouuc := ds.Orgunits.Update(customerID, ouPath, action.Object.(*admin.OrgUnit))
+			_, err = ouuc.Do()
+			msg = fmt.Sprintf(gmess.INFO_OUUPDATED, action.Key)
+		}
+	case cmn.OBJTYPEUSER:
+		uuc := ds.Users.Update(action.Key, action.Object.(*admin.User))
+		_, err = uuc.Do()
+		msg = fmt.Sprintf(gmess.INFO_USERUPDATED, action.Key)
+	case cmn.OBJTYPEUSRALIAS:
+		uaic := ds.Users.Aliases.Insert(action.Group, action.Object.(*admin.Alias))
+		_, err = uaic.Do()
+		msg = fmt.Sprintf(gmess.INFO_USERALIASCREATED, action.Key, action.Group)
+	}
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	fmt.Println(cmn.GminMessage(msg))
+	lg.Info(msg)
+	return nil
+}
+
+// applyCurrent gets the tenant objects that are compared with desired state
+func applyCurrent(ds *admin.Service, gss *gset.Service, customerID string, state *aply.State) (*aply.Current, error) {
+	lg.Debug("starting applyCurrent()")
+	defer lg.Debug("finished applyCurrent()")
+
+	current := aply.NewCurrent()
+
+	oulc := ds.Orgunits.List(customerID)
+	oulc = ous.AddType(oulc, "all")
+	orgunits, err := ous.DoList(oulc)
+	if err != nil {
+		return nil, err
+	}
+	for _, ou := range orgunits.OrganizationUnits {
+		current.OrgUnits[ou.OrgUnitPath] = ou
+	}
+
+	glc := ds.Groups.List()
+	glc = grps.AddCustomer(glc, customerID)
+	for {
+		groups, err := grps.DoList(glc)
+		if err != nil {
+			return nil, err
+		}
+		for _, group := range groups.Groups {
+			current.Groups[strings.ToLower(group.Email)] = group
+		}
+		if groups.NextPageToken == "" {
+			break
+		}
+		glc = grps.AddPageToken(glc, groups.NextPageToken)
+	}
+
+	for _, grp := range state.Groups {
+		email := strings.ToLower(grp.Email)
+		if current.Groups[email] == nil {
+			continue
+		}
+
+		if grp.Settings != nil {
+			gsgc := gss.Groups.Get(grp.Email)
+			settings, err := grpset.DoGet(gsgc)
+			if err != nil {
+				return nil, err
+			}
+			current.GroupSettings[email] = settings
+		}
+
+		if grp.Members != nil {
+			current.Members[email] = map[string]*admin.Member{}
+
+			mlc := ds.Members.List(grp.Email)
+			for {
+				members, err := mems.DoList(mlc)
+				if err != nil {
+					return nil, err
+				}
+				for _, member := range members.Members {
+					current.Members[email][strings.ToLower(member.Email)] = member
+				}
+				if members.NextPageToken == "" {
+					break
+				}
+				mlc = mems.AddPageToken(mlc, members.NextPageToken)
+			}
+		}
+	}
+
+	return current, nil
+}
+
+func init() {
+	rootCmd.AddCommand(applyCmd)
+
+	applyCmd.Flags().StringVarP(&stateFile, flgnm.FLG_FILE, "f", "", "state file path")
+	applyCmd.Flags().StringVar(&logLevel, flgnm.FLG_LOGLEVEL, "info", "log level (debug, info, error, warn)")
+	applyCmd.Flags().BoolVar(&prune, flgnm.FLG_PRUNE, false, "delete orgunits and groups that are not in state file")
+	applyCmd.Flags().Float64Var(&qps, flgnm.FLG_QPS, 0, "maximum API calls per second (default is based on API quota)")
+	applyCmd.Flags().BoolVar(&silent, flgnm.FLG_SILENT, false, "suppress console output")
+
+	applyCmd.PreRunE = preRun
+}
@@ -0,0 +1,113 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+func TestApplyFakeServer(t *testing.T) {
+	fs := newFakeServer(t)
+	fs.AddOrgUnit(&admin.OrgUnit{Name: "Old"})
+	fs.AddGroup(&admin.Group{Email: "old@mycompany.org", Name: "Old"})
+	fs.AddGroup(&admin.Group{Email: "sales@mycompany.org", Name: "Sales"})
+	fs.AddMember("sales@mycompany.org", &admin.Member{Email: "a.person@mycompany.org", Role: "MEMBER"})
+	fs.AddMember("sales@mycompany.org", &admin.Member{Email: "b.person@mycompany.org", Role: "MEMBER"})
+	// Everyone in the customer has no email address and is left alone by --prune
+	fs.AddMember("sales@mycompany.org", &admin.Member{Id: "C01abc123", Type: "CUSTOMER"})
+
+	stateFile := filepath.Join(t.TempDir(), "state.yaml")
+	ioutil.WriteFile(stateFile, []byte(`orgunits:
+  - path: /Sales/EMEA
+  - path: /Sales
+    description: Sales department
+groups:
+  - email: sales@mycompany.org
+    name: Sales Team
+    settings:
+      whoCanJoin: invited_can_join
+    members:
+      - email: a.person@mycompany.org
+        role: owner
+      - email: c.person@mycompany.org
+`), 0644)
+
+	out, err := runGmin(t, "apply", "-f", stateFile, "--prune", "--dry-run")
+	if err != nil {
+		t.Fatalf("Got error: %v - expected error: nil", err)
+	}
+	if !strings.Contains(out, "plan: 3 to create, 3 to update, 3 to delete") {
+		t.Errorf("Got output: %v - expected output to contain: plan: 3 to create, 3 to update, 3 to delete", out)
+	}
+	for _, req := range fs.Requests {
+		if !strings.HasPrefix(req, "GET ") {
+			t.Errorf("Got request: %v - expected only GET requests", req)
+		}
+	}
+
+	_, err = runGmin(t, "apply", "-f", stateFile, "--prune", "--qps", "100")
+	if err != nil {
+		t.Fatalf("Got error: %v - expected error: nil", err)
+	}
+
+	if fs.OrgUnits["/Sales/EMEA"] == nil || fs.OrgUnits["/Sales"].Description != "Sales department" {
+		t.Errorf("Got orgunits: %v - expected orgunits: /Sales and /Sales/EMEA", fs.OrgUnits)
+	}
+	if fs.OrgUnits["/Old"] != nil {
+		t.Error("Got orgunit: /Old - expected orgunit: nil")
+	}
+	if fs.Group("old@mycompany.org") != nil {
+		t.Error("Got group: old@mycompany.org - expected group: nil")
+	}
+	if fs.Group("sales@mycompany.org").Name != "Sales Team" {
+		t.Errorf("Got group name: %v - expected group name: Sales Team", fs.Group("sales@mycompany.org").Name)
+	}
+	if fs.GroupSettings["sales@mycompany.org"].WhoCanJoin != "INVITED_CAN_JOIN" {
+		t.Errorf("Got whoCanJoin: %v - expected whoCanJoin: INVITED_CAN_JOIN", fs.GroupSettings["sales@mycompany.org"].WhoCanJoin)
+	}
+	if mem := fs.Member("sales@mycompany.org", "a.person@mycompany.org"); mem == nil || mem.Role != "OWNER" {
+		t.Errorf("Got member: %v - expected member: a.person@mycompany.org with role OWNER", mem)
+	}
+	if fs.Member("sales@mycompany.org", "b.person@mycompany.org") != nil {
+		t.Error("Got member: b.person@mycompany.org - expected member: nil")
+	}
+	if fs.Member("sales@mycompany.org", "c.person@mycompany.org") == nil {
+		t.Error("Got member: nil - expected member: c.person@mycompany.org")
+	}
+	if fs.Member("sales@mycompany.org", "C01abc123") == nil {
+		t.Error("Got member: nil - expected member: C01abc123")
+	}
+
+	out, err = runGmin(t, "apply", "-f", stateFile, "--prune")
+	if err != nil {
+		t.Fatalf("Got error: %v - expected error: nil", err)
+	}
+	if !strings.Contains(out, "no changes needed") {
+		t.Errorf("Got output: %v - expected output to contain: no changes needed", out)
+	}
+}
@@ -23,6 +23,7 @@ THE SOFTWARE.
 package cmd
 
 import (
+	btch "github.com/plusworx/gmin/utils/batch"
 	flgnm "github.com/plusworx/gmin/utils/flagnames"
 	"github.com/spf13/cobra"
 )
@@ -44,6 +45,10 @@ func init() {
 	rootCmd.AddCommand(batchCreateCmd)
 	batchCreateCmd.PersistentFlags().BoolVar(&silent, flgnm.FLG_SILENT, false, "suppress console output")
 	batchCreateCmd.PersistentFlags().StringVar(&logLevel, flgnm.FLG_LOGLEVEL, "info", "log level (debug, info, error, warn)")
+	batchCreateCmd.PersistentFlags().StringVar(&resultsDir, flgnm.FLG_RESULTSDIR, "", "directory for batch results and failed rows files (default is current directory)")
+	batchCreateCmd.PersistentFlags().StringVar(&resultsFormat, flgnm.FLG_RESULTSFORMAT, "json", "batch results file format (csv or json)")
+	batchCreateCmd.PersistentFlags().Float64Var(&qps, flgnm.FLG_QPS, 0, "maximum API calls per second (default is API quota based)")
+	batchCreateCmd.PersistentFlags().IntVar(&workers, flgnm.FLG_WORKERS, btch.DEFAULTWORKERS, "number of concurrent API workers")
 
 	batchCreateCmd.PersistentPreRunE = preRun
 }
@@ -0,0 +1,247 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	btch "github.com/plusworx/gmin/utils/batch"
+	cmn "github.com/plusworx/gmin/utils/common"
+	cfg "github.com/plusworx/gmin/utils/config"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	rsrcs "github.com/plusworx/gmin/utils/resources"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var batchCrtBuildingCmd = &cobra.Command{
+	Use:     "buildings -i <input file path>",
+	Aliases: []string{"building", "bldgs", "bldg"},
+	Example: `gmin batch-create buildings -i inputfile.json
+gmin bcrt bldgs -i inputfile.csv -f csv
+gmin bcrt bldg -i 1odyAIp3jGspd3M4xeepxWD6aeQIUuHBgrZB2OHSu8MI -s 'Sheet1!A1:K25' -f gsheet`,
+	Short: "Creates a batch of buildings",
+	Long: `Creates a batch of buildings where building details are provided in a Google Sheet, CSV/JSON input file or piped JSON.
+			  
+The contents of the JSON file or piped input should look something like this:
+
+{"buildingId":"hq","buildingName":"Head Office","floorNames":["G","1","2"],"address":{"addressLines":["1 High Street"],"locality":"London","regionCode":"GB"}}
+{"buildingId":"annex","buildingName":"Annex","floorNames":["1"],"coordinates":{"latitude":51.5,"longitude":-0.12}}
+
+N.B. buildingId and buildingName must be provided.
+
+CSV and Google sheets must have a header row with the following column names being the only ones that are valid:
+
+addressLines [separated by (~)]
+administrativeArea
+buildingId [required]
+buildingName [required]
+description
+floorNames [separated by (~)]
+languageCode
+latitude
+locality
+longitude
+postalCode
+regionCode
+sublocality
+
+The column names are case insensitive and can be in any order.`,
+	RunE: doBatchCrtBuilding,
+}
+
+func doBatchCrtBuilding(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doBatchCrtBuilding()",
+		"args", args)
+	defer lg.Debug("finished doBatchCrtBuilding()")
+
+	var (
+		buildings []*admin.Building
+		input     *btch.Input
+		objs      []interface{}
+	)
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryResourceCalendarScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	inputFlgVal, err := cmd.Flags().GetString(flgnm.FLG_INPUTFILE)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	scanner, err := cmn.InputFromStdIn(inputFlgVal)
+	if err != nil {
+		return err
+	}
+
+	if inputFlgVal == "" && scanner == nil {
+		err := errors.New(gmess.ERR_NOINPUTFILE)
+		lg.Error(err)
+		return err
+	}
+
+	formatFlgVal, err := cmd.Flags().GetString(flgnm.FLG_FORMAT)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	lwrFmt := strings.ToLower(formatFlgVal)
+
+	ok := cmn.SliceContainsStr(cmn.ValidFileFormats, lwrFmt)
+	if !ok {
+		err = fmt.Errorf(gmess.ERR_INVALIDFILEFORMAT, formatFlgVal)
+		lg.Error(err)
+		return err
+	}
+
+	callParams := btch.CallParams{CallType: cmn.CALLTYPECREATE, ObjectType: cmn.OBJTYPEBUILDING}
+
+	switch {
+	case lwrFmt == "csv":
+		objs, input, err = btch.ProcessCSVFile(callParams, inputFlgVal, rsrcs.BuildingAttrMap)
+		if err != nil {
+			return err
+		}
+	case lwrFmt == "json":
+		objs, input, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, rsrcs.BuildingAttrMap)
+		if err != nil {
+			return err
+		}
+	case lwrFmt == "gsheet":
+		rangeFlgVal, err := cmd.Flags().GetString(flgnm.FLG_SHEETRANGE)
+		if err != nil {
+			lg.Error(err)
+			return err
+		}
+
+		objs, input, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, rsrcs.BuildingAttrMap)
+		if err != nil {
+			return err
+		}
+	default:
+		err = fmt.Errorf(gmess.ERR_INVALIDFILEFORMAT, formatFlgVal)
+		lg.Error(err)
+		return err
+	}
+
+	for _, buildingObj := range objs {
+		buildings = append(buildings, buildingObj.(*admin.Building))
+	}
+
+	pool, err := newBatchPool(cmd, btch.DIRECTORYQPS)
+	if err != nil {
+		return err
+	}
+
+	report, err := newBatchReport(cmd, input)
+	if err != nil {
+		return err
+	}
+
+	err = bcbdProcessObjects(ds, pool, report, buildings)
+	if err != nil {
+		return err
+	}
+
+	err = writeBatchReport(report)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func bcbdCreate(building *admin.Building, rbic *admin.ResourcesBuildingsInsertCall) error {
+	lg.Debugw("starting bcbdCreate()",
+		"buildingId", building.BuildingId)
+	defer lg.Debug("finished bcbdCreate()")
+
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = 32 * time.Second
+
+	err := backoff.Retry(func() error {
+		var err error
+		_, err = rbic.Do()
+		if err == nil {
+			fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_BUILDINGCREATED, building.BuildingId)))
+			lg.Infof(gmess.INFO_BUILDINGCREATED, building.BuildingId)
+			return err
+		}
+		if !cmn.IsErrRetryable(err) {
+			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHBUILDING, err, building.BuildingId))
+		}
+		// Log the retries
+		lg.Warnw(err.Error(),
+			"retrying", b.GetElapsedTime().String(),
+			"building", building.BuildingId)
+		return fmt.Errorf(gmess.ERR_BATCHBUILDING, err, building.BuildingId)
+	}, b)
+	if err != nil {
+		// Log final error
+		lg.Error(err)
+		fmt.Println(cmn.GminMessage(err.Error()))
+	}
+	return err
+}
+
+func bcbdProcessObjects(ds *admin.Service, pool *btch.Pool, report *btch.Report, buildings []*admin.Building) error {
+	lg.Debug("starting bcbdProcessObjects()")
+	defer lg.Debug("finished bcbdProcessObjects()")
+
+	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
+	if err != nil {
+		return err
+	}
+
+	defer pool.Wait()
+
+	for idx, building := range buildings {
+		idx := idx
+		building := building
+		rbic := ds.Resources.Buildings.Insert(customerID, building)
+
+		pool.Submit(func() {
+			report.Add(idx, building.BuildingId, bcbdCreate(building, rbic))
+		})
+	}
+
+	return nil
+}
+
+func init() {
+	batchCreateCmd.AddCommand(batchCrtBuildingCmd)
+
+	batchCrtBuildingCmd.Flags().StringVarP(&inputFile, flgnm.FLG_INPUTFILE, "i", "", "filepath to building data file or sheet id")
+	batchCrtBuildingCmd.Flags().StringVarP(&format, flgnm.FLG_FORMAT, "f", "json", "building data file format")
+	batchCrtBuildingCmd.Flags().StringVarP(&sheetRange, flgnm.FLG_SHEETRANGE, "s", "", "building data gsheet range")
+}
@@ -0,0 +1,245 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	btch "github.com/plusworx/gmin/utils/batch"
+	cmn "github.com/plusworx/gmin/utils/common"
+	cfg "github.com/plusworx/gmin/utils/config"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	rsrcs "github.com/plusworx/gmin/utils/resources"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var batchCrtCalResCmd = &cobra.Command{
+	Use:     "calendar-resources -i <input file path>",
+	Aliases: []string{"calendar-resource", "cal-resources", "cal-resource", "calres", "cres"},
+	Example: `gmin batch-create calendar-resources -i inputfile.json
+gmin bcrt cres -i inputfile.csv -f csv
+gmin bcrt calres -i 1odyAIp3jGspd3M4xeepxWD6aeQIUuHBgrZB2OHSu8MI -s 'Sheet1!A1:K25' -f gsheet`,
+	Short: "Creates a batch of calendar resources",
+	Long: `Creates a batch of calendar resources where calendar resource details are provided in a Google Sheet, CSV/JSON input file or piped JSON.
+			  
+The contents of the JSON file or piped input should look something like this:
+
+{"resourceId":"room1","resourceName":"Meeting Room 1","buildingId":"hq","capacity":10,"floorName":"1"}
+{"resourceId":"room2","resourceName":"Meeting Room 2","featureInstances":[{"feature":{"name":"Whiteboard"}}]}
+
+N.B. resourceId and resourceName must be provided.
+
+CSV and Google sheets must have a header row with the following column names being the only ones that are valid:
+
+buildingId
+capacity
+featureInstances [feature names separated by (~)]
+floorName
+floorSection
+resourceCategory
+resourceDescription
+resourceId [required]
+resourceName [required]
+resourceType
+userVisibleDescription
+
+The column names are case insensitive and can be in any order.`,
+	RunE: doBatchCrtCalRes,
+}
+
+func doBatchCrtCalRes(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doBatchCrtCalRes()",
+		"args", args)
+	defer lg.Debug("finished doBatchCrtCalRes()")
+
+	var (
+		calendars []*admin.CalendarResource
+		input     *btch.Input
+		objs      []interface{}
+	)
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryResourceCalendarScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	inputFlgVal, err := cmd.Flags().GetString(flgnm.FLG_INPUTFILE)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	scanner, err := cmn.InputFromStdIn(inputFlgVal)
+	if err != nil {
+		return err
+	}
+
+	if inputFlgVal == "" && scanner == nil {
+		err := errors.New(gmess.ERR_NOINPUTFILE)
+		lg.Error(err)
+		return err
+	}
+
+	formatFlgVal, err := cmd.Flags().GetString(flgnm.FLG_FORMAT)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	lwrFmt := strings.ToLower(formatFlgVal)
+
+	ok := cmn.SliceContainsStr(cmn.ValidFileFormats, lwrFmt)
+	if !ok {
+		err = fmt.Errorf(gmess.ERR_INVALIDFILEFORMAT, formatFlgVal)
+		lg.Error(err)
+		return err
+	}
+
+	callParams := btch.CallParams{CallType: cmn.CALLTYPECREATE, ObjectType: cmn.OBJTYPECALRES}
+
+	switch {
+	case lwrFmt == "csv":
+		objs, input, err = btch.ProcessCSVFile(callParams, inputFlgVal, rsrcs.CalendarAttrMap)
+		if err != nil {
+			return err
+		}
+	case lwrFmt == "json":
+		objs, input, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, rsrcs.CalendarAttrMap)
+		if err != nil {
+			return err
+		}
+	case lwrFmt == "gsheet":
+		rangeFlgVal, err := cmd.Flags().GetString(flgnm.FLG_SHEETRANGE)
+		if err != nil {
+			lg.Error(err)
+			return err
+		}
+
+		objs, input, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, rsrcs.CalendarAttrMap)
+		if err != nil {
+			return err
+		}
+	default:
+		err = fmt.Errorf(gmess.ERR_INVALIDFILEFORMAT, formatFlgVal)
+		lg.Error(err)
+		return err
+	}
+
+	for _, calendarObj := range objs {
+		calendars = append(calendars, calendarObj.(*admin.CalendarResource))
+	}
+
+	pool, err := newBatchPool(cmd, btch.DIRECTORYQPS)
+	if err != nil {
+		return err
+	}
+
+	report, err := newBatchReport(cmd, input)
+	if err != nil {
+		return err
+	}
+
+	err = bccrProcessObjects(ds, pool, report, calendars)
+	if err != nil {
+		return err
+	}
+
+	err = writeBatchReport(report)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func bccrCreate(calendar *admin.CalendarResource, rcic *admin.ResourcesCalendarsInsertCall) error {
+	lg.Debugw("starting bccrCreate()",
+		"resourceId", calendar.ResourceId)
+	defer lg.Debug("finished bccrCreate()")
+
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = 32 * time.Second
+
+	err := backoff.Retry(func() error {
+		var err error
+		_, err = rcic.Do()
+		if err == nil {
+			fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_CALRESCREATED, calendar.ResourceId)))
+			lg.Infof(gmess.INFO_CALRESCREATED, calendar.ResourceId)
+			return err
+		}
+		if !cmn.IsErrRetryable(err) {
+			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHCALRESOURCE, err, calendar.ResourceId))
+		}
+		// Log the retries
+		lg.Warnw(err.Error(),
+			"retrying", b.GetElapsedTime().String(),
+			"calendar resource", calendar.ResourceId)
+		return fmt.Errorf(gmess.ERR_BATCHCALRESOURCE, err, calendar.ResourceId)
+	}, b)
+	if err != nil {
+		// Log final error
+		lg.Error(err)
+		fmt.Println(cmn.GminMessage(err.Error()))
+	}
+	return err
+}
+
+func bccrProcessObjects(ds *admin.Service, pool *btch.Pool, report *btch.Report, calendars []*admin.CalendarResource) error {
+	lg.Debug("starting bccrProcessObjects()")
+	defer lg.Debug("finished bccrProcessObjects()")
+
+	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
+	if err != nil {
+		return err
+	}
+
+	defer pool.Wait()
+
+	for idx, calendar := range calendars {
+		idx := idx
+		calendar := calendar
+		rcic := ds.Resources.Calendars.Insert(customerID, calendar)
+
+		pool.Submit(func() {
+			report.Add(idx, calendar.ResourceId, bccrCreate(calendar, rcic))
+		})
+	}
+
+	return nil
+}
+
+func init() {
+	batchCreateCmd.AddCommand(batchCrtCalResCmd)
+
+	batchCrtCalResCmd.Flags().StringVarP(&inputFile, flgnm.FLG_INPUTFILE, "i", "", "filepath to calendar resource data file or sheet id")
+	batchCrtCalResCmd.Flags().StringVarP(&format, flgnm.FLG_FORMAT, "f", "json", "calendar resource data file format")
+	batchCrtCalResCmd.Flags().StringVarP(&sheetRange, flgnm.FLG_SHEETRANGE, "s", "", "calendar resource data gsheet range")
+}
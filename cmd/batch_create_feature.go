@@ -0,0 +1,233 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	btch "github.com/plusworx/gmin/utils/batch"
+	cmn "github.com/plusworx/gmin/utils/common"
+	cfg "github.com/plusworx/gmin/utils/config"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	rsrcs "github.com/plusworx/gmin/utils/resources"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var batchCrtFeatureCmd = &cobra.Command{
+	Use:     "features -i <input file path>",
+	Aliases: []string{"feature", "feats", "feat"},
+	Example: `gmin batch-create features -i inputfile.json
+gmin bcrt feats -i inputfile.csv -f csv
+gmin bcrt feat -i 1odyAIp3jGspd3M4xeepxWD6aeQIUuHBgrZB2OHSu8MI -s 'Sheet1!A1:A25' -f gsheet`,
+	Short: "Creates a batch of calendar resource features",
+	Long: `Creates a batch of calendar resource features where feature details are provided in a Google Sheet, CSV/JSON input file or piped JSON.
+			  
+The contents of the JSON file or piped input should look something like this:
+
+{"name":"Whiteboard"}
+{"name":"Video Conferencing"}
+
+CSV and Google sheets must have a header row with the following column names being the only ones that are valid:
+
+name [required]
+
+The column names are case insensitive and can be in any order.`,
+	RunE: doBatchCrtFeature,
+}
+
+func doBatchCrtFeature(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doBatchCrtFeature()",
+		"args", args)
+	defer lg.Debug("finished doBatchCrtFeature()")
+
+	var (
+		features []*admin.Feature
+		input    *btch.Input
+		objs     []interface{}
+	)
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryResourceCalendarScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	inputFlgVal, err := cmd.Flags().GetString(flgnm.FLG_INPUTFILE)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	scanner, err := cmn.InputFromStdIn(inputFlgVal)
+	if err != nil {
+		return err
+	}
+
+	if inputFlgVal == "" && scanner == nil {
+		err := errors.New(gmess.ERR_NOINPUTFILE)
+		lg.Error(err)
+		return err
+	}
+
+	formatFlgVal, err := cmd.Flags().GetString(flgnm.FLG_FORMAT)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	lwrFmt := strings.ToLower(formatFlgVal)
+
+	ok := cmn.SliceContainsStr(cmn.ValidFileFormats, lwrFmt)
+	if !ok {
+		err = fmt.Errorf(gmess.ERR_INVALIDFILEFORMAT, formatFlgVal)
+		lg.Error(err)
+		return err
+	}
+
+	callParams := btch.CallParams{CallType: cmn.CALLTYPECREATE, ObjectType: cmn.OBJTYPEFEATURE}
+
+	switch {
+	case lwrFmt == "csv":
+		objs, input, err = btch.ProcessCSVFile(callParams, inputFlgVal, rsrcs.FeatureAttrMap)
+		if err != nil {
+			return err
+		}
+	case lwrFmt == "json":
+		objs, input, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, rsrcs.FeatureAttrMap)
+		if err != nil {
+			return err
+		}
+	case lwrFmt == "gsheet":
+		rangeFlgVal, err := cmd.Flags().GetString(flgnm.FLG_SHEETRANGE)
+		if err != nil {
+			lg.Error(err)
+			return err
+		}
+
+		objs, input, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, rsrcs.FeatureAttrMap)
+		if err != nil {
+			return err
+		}
+	default:
+		err = fmt.Errorf(gmess.ERR_INVALIDFILEFORMAT, formatFlgVal)
+		lg.Error(err)
+		return err
+	}
+
+	for _, featureObj := range objs {
+		features = append(features, featureObj.(*admin.Feature))
+	}
+
+	pool, err := newBatchPool(cmd, btch.DIRECTORYQPS)
+	if err != nil {
+		return err
+	}
+
+	report, err := newBatchReport(cmd, input)
+	if err != nil {
+		return err
+	}
+
+	err = bcftProcessObjects(ds, pool, report, features)
+	if err != nil {
+		return err
+	}
+
+	err = writeBatchReport(report)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func bcftCreate(feature *admin.Feature, rfic *admin.ResourcesFeaturesInsertCall) error {
+	lg.Debugw("starting bcftCreate()",
+		"name", feature.Name)
+	defer lg.Debug("finished bcftCreate()")
+
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = 32 * time.Second
+
+	err := backoff.Retry(func() error {
+		var err error
+		_, err = rfic.Do()
+		if err == nil {
+			fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_FEATURECREATED, feature.Name)))
+			lg.Infof(gmess.INFO_FEATURECREATED, feature.Name)
+			return err
+		}
+		if !cmn.IsErrRetryable(err) {
+			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHFEATURE, err, feature.Name))
+		}
+		// Log the retries
+		lg.Warnw(err.Error(),
+			"retrying", b.GetElapsedTime().String(),
+			"feature", feature.Name)
+		return fmt.Errorf(gmess.ERR_BATCHFEATURE, err, feature.Name)
+	}, b)
+	if err != nil {
+		// Log final error
+		lg.Error(err)
+		fmt.Println(cmn.GminMessage(err.Error()))
+	}
+	return err
+}
+
+func bcftProcessObjects(ds *admin.Service, pool *btch.Pool, report *btch.Report, features []*admin.Feature) error {
+	lg.Debug("starting bcftProcessObjects()")
+	defer lg.Debug("finished bcftProcessObjects()")
+
+	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
+	if err != nil {
+		return err
+	}
+
+	defer pool.Wait()
+
+	for idx, feature := range features {
+		idx := idx
+		feature := feature
+		rfic := ds.Resources.Features.Insert(customerID, feature)
+
+		pool.Submit(func() {
+			report.Add(idx, feature.Name, bcftCreate(feature, rfic))
+		})
+	}
+
+	return nil
+}
+
+func init() {
+	batchCreateCmd.AddCommand(batchCrtFeatureCmd)
+
+	batchCrtFeatureCmd.Flags().StringVarP(&inputFile, flgnm.FLG_INPUTFILE, "i", "", "filepath to feature data file or sheet id")
+	batchCrtFeatureCmd.Flags().StringVarP(&format, flgnm.FLG_FORMAT, "f", "json", "feature data file format")
+	batchCrtFeatureCmd.Flags().StringVarP(&sheetRange, flgnm.FLG_SHEETRANGE, "s", "", "feature data gsheet range")
+}
@@ -26,7 +26,6 @@ import (
 	"errors"
 	"fmt"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -72,6 +71,7 @@ func doBatchCrtGroup(cmd *cobra.Command, args []string) error {
 
 	var (
 		groups []*admin.Group
+		input  *btch.Input
 		objs   []interface{}
 	)
 
@@ -116,12 +116,12 @@ func doBatchCrtGroup(cmd *cobra.Command, args []string) error {
 
 	switch {
 	case lwrFmt == "csv":
-		objs, err = btch.ProcessCSVFile(callParams, inputFlgVal, grps.GroupAttrMap)
+		objs, input, err = btch.ProcessCSVFile(callParams, inputFlgVal, grps.GroupAttrMap)
 		if err != nil {
 			return err
 		}
 	case lwrFmt == "json":
-		objs, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, grps.GroupAttrMap)
+		objs, input, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, grps.GroupAttrMap)
 		if err != nil {
 			return err
 		}
@@ -131,7 +131,7 @@ func doBatchCrtGroup(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		objs, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, grps.GroupAttrMap)
+		objs, input, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, grps.GroupAttrMap)
 		if err != nil {
 			return err
 		}
@@ -145,7 +145,22 @@ func doBatchCrtGroup(cmd *cobra.Command, args []string) error {
 		groups = append(groups, grpObj.(*admin.Group))
 	}
 
-	err = bcgProcessObjects(ds, groups)
+	pool, err := newBatchPool(cmd, btch.DIRECTORYQPS)
+	if err != nil {
+		return err
+	}
+
+	report, err := newBatchReport(cmd, input)
+	if err != nil {
+		return err
+	}
+
+	err = bcgProcessObjects(ds, pool, report, groups)
+	if err != nil {
+		return err
+	}
+
+	err = writeBatchReport(report)
 	if err != nil {
 		return err
 	}
@@ -153,12 +168,10 @@ func doBatchCrtGroup(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func bcgCreate(group *admin.Group, wg *sync.WaitGroup, gic *admin.GroupsInsertCall) {
+func bcgCreate(group *admin.Group, gic *admin.GroupsInsertCall) error {
 	lg.Debug("starting bcgCreate()")
 	defer lg.Debug("finished bcgCreate()")
 
-	defer wg.Done()
-
 	b := backoff.NewExponentialBackOff()
 	b.MaxElapsedTime = 32 * time.Second
 
@@ -171,43 +184,45 @@ func bcgCreate(group *admin.Group, wg *sync.WaitGroup, gic *admin.GroupsInsertCa
 			return err
 		}
 		if !cmn.IsErrRetryable(err) {
-			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHGROUP, err.Error(), group.Email))
+			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHGROUP, err, group.Email))
 		}
 		// Log the retries
 		lg.Warnw(err.Error(),
 			"retrying", b.GetElapsedTime().String(),
 			"group", group.Email)
-		return fmt.Errorf(gmess.ERR_BATCHGROUP, err.Error(), group.Email)
+		return fmt.Errorf(gmess.ERR_BATCHGROUP, err, group.Email)
 	}, b)
 	if err != nil {
 		// Log final error
 		lg.Error(err)
 		fmt.Println(cmn.GminMessage(err.Error()))
 	}
+	return err
 }
 
-func bcgProcessObjects(ds *admin.Service, groups []*admin.Group) error {
+func bcgProcessObjects(ds *admin.Service, pool *btch.Pool, report *btch.Report, groups []*admin.Group) error {
 	lg.Debug("starting bcgProcessObjects()")
 	defer lg.Debug("finished bcgProcessObjects()")
 
-	wg := new(sync.WaitGroup)
+	defer pool.Wait()
 
-	for _, g := range groups {
+	for idx, g := range groups {
+		idx := idx
+		g := g
 		if g.Email == "" {
 			err := errors.New(gmess.ERR_NOGROUPEMAILADDRESS)
 			lg.Error(err)
-			return err
+			report.Add(idx, g.Email, err)
+			continue
 		}
 
 		gic := ds.Groups.Insert(g)
 
-		wg.Add(1)
-
-		go bcgCreate(g, wg, gic)
+		pool.Submit(func() {
+			report.Add(idx, g.Email, bcgCreate(g, gic))
+		})
 	}
 
-	wg.Wait()
-
 	return nil
 }
 
@@ -26,7 +26,6 @@ import (
 	"errors"
 	"fmt"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -72,6 +71,7 @@ func doBatchCrtMember(cmd *cobra.Command, args []string) error {
 	defer lg.Debug("finished doBatchCrtMember()")
 
 	var (
+		input   *btch.Input
 		members []*admin.Member
 		objs    []interface{}
 	)
@@ -119,12 +119,12 @@ func doBatchCrtMember(cmd *cobra.Command, args []string) error {
 
 	switch {
 	case lwrFmt == "csv":
-		objs, err = btch.ProcessCSVFile(callParams, inputFlgVal, mems.MemberAttrMap)
+		objs, input, err = btch.ProcessCSVFile(callParams, inputFlgVal, mems.MemberAttrMap)
 		if err != nil {
 			return err
 		}
 	case lwrFmt == "json":
-		objs, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, mems.MemberAttrMap)
+		objs, input, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, mems.MemberAttrMap)
 		if err != nil {
 			return err
 		}
@@ -134,7 +134,7 @@ func doBatchCrtMember(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		objs, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, mems.MemberAttrMap)
+		objs, input, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, mems.MemberAttrMap)
 		if err != nil {
 			return err
 		}
@@ -148,7 +148,22 @@ func doBatchCrtMember(cmd *cobra.Command, args []string) error {
 		members = append(members, memObj.(*admin.Member))
 	}
 
-	err = bcmProcessObjects(ds, groupKey, members)
+	pool, err := newBatchPool(cmd, btch.DIRECTORYQPS)
+	if err != nil {
+		return err
+	}
+
+	report, err := newBatchReport(cmd, input)
+	if err != nil {
+		return err
+	}
+
+	err = bcmProcessObjects(ds, pool, report, groupKey, members)
+	if err != nil {
+		return err
+	}
+
+	err = writeBatchReport(report)
 	if err != nil {
 		return err
 	}
@@ -156,13 +171,11 @@ func doBatchCrtMember(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func bcmCreate(member *admin.Member, groupKey string, wg *sync.WaitGroup, mic *admin.MembersInsertCall) {
+func bcmCreate(member *admin.Member, groupKey string, mic *admin.MembersInsertCall) error {
 	lg.Debugw("starting bcmCreate()",
 		"groupKey", groupKey)
 	defer lg.Debug("finished bcmCreate()")
 
-	defer wg.Done()
-
 	b := backoff.NewExponentialBackOff()
 	b.MaxElapsedTime = 32 * time.Second
 
@@ -175,45 +188,47 @@ func bcmCreate(member *admin.Member, groupKey string, wg *sync.WaitGroup, mic *a
 			return err
 		}
 		if !cmn.IsErrRetryable(err) {
-			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHMEMBER, err.Error(), member.Email, groupKey))
+			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHMEMBER, err, member.Email, groupKey))
 		}
 		// Log the retries
 		lg.Warnw(err.Error(),
 			"retrying", b.GetElapsedTime().String(),
 			"group", groupKey,
 			"member", member.Email)
-		return fmt.Errorf(gmess.ERR_BATCHMEMBER, err.Error(), member.Email, groupKey)
+		return fmt.Errorf(gmess.ERR_BATCHMEMBER, err, member.Email, groupKey)
 	}, b)
 	if err != nil {
 		// Log final error
 		lg.Error(err)
 		fmt.Println(cmn.GminMessage(err.Error()))
 	}
+	return err
 }
 
-func bcmProcessObjects(ds *admin.Service, groupKey string, members []*admin.Member) error {
+func bcmProcessObjects(ds *admin.Service, pool *btch.Pool, report *btch.Report, groupKey string, members []*admin.Member) error {
 	lg.Debugw("starting bcmProcessObjects()",
 		"groupKey", groupKey)
 	defer lg.Debug("finished bcmProcessObjects()")
 
-	wg := new(sync.WaitGroup)
+	defer pool.Wait()
 
-	for _, m := range members {
+	for idx, m := range members {
+		idx := idx
+		m := m
 		if m.Email == "" {
 			err := errors.New(gmess.ERR_NOMEMBEREMAILADDRESS)
 			lg.Error(err)
-			return err
+			report.Add(idx, m.Email, err)
+			continue
 		}
 
 		mic := ds.Members.Insert(groupKey, m)
 
-		wg.Add(1)
-
-		go bcmCreate(m, groupKey, wg, mic)
+		pool.Submit(func() {
+			report.Add(idx, m.Email, bcmCreate(m, groupKey, mic))
+		})
 	}
 
-	wg.Wait()
-
 	return nil
 }
 
@@ -26,7 +26,6 @@ import (
 	"errors"
 	"fmt"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -73,6 +72,7 @@ func doBatchCrtOrgUnit(cmd *cobra.Command, args []string) error {
 	defer lg.Debug("finished doBatchCrtOrgUnit()")
 
 	var (
+		input    *btch.Input
 		objs     []interface{}
 		orgunits []*admin.OrgUnit
 	)
@@ -118,12 +118,12 @@ func doBatchCrtOrgUnit(cmd *cobra.Command, args []string) error {
 
 	switch {
 	case lwrFmt == "csv":
-		objs, err = btch.ProcessCSVFile(callParams, inputFlgVal, ous.OrgUnitAttrMap)
+		objs, input, err = btch.ProcessCSVFile(callParams, inputFlgVal, ous.OrgUnitAttrMap)
 		if err != nil {
 			return err
 		}
 	case lwrFmt == "json":
-		objs, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, ous.OrgUnitAttrMap)
+		objs, input, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, ous.OrgUnitAttrMap)
 		if err != nil {
 			return err
 		}
@@ -133,7 +133,7 @@ func doBatchCrtOrgUnit(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		objs, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, ous.OrgUnitAttrMap)
+		objs, input, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, ous.OrgUnitAttrMap)
 		if err != nil {
 			return err
 		}
@@ -147,7 +147,22 @@ func doBatchCrtOrgUnit(cmd *cobra.Command, args []string) error {
 		orgunits = append(orgunits, ouObj.(*admin.OrgUnit))
 	}
 
-	err = bcoProcessObjects(ds, orgunits)
+	pool, err := newBatchPool(cmd, btch.ORGUNITQPS)
+	if err != nil {
+		return err
+	}
+
+	report, err := newBatchReport(cmd, input)
+	if err != nil {
+		return err
+	}
+
+	err = bcoProcessObjects(ds, pool, report, orgunits)
+	if err != nil {
+		return err
+	}
+
+	err = writeBatchReport(report)
 	if err != nil {
 		return err
 	}
@@ -155,12 +170,10 @@ func doBatchCrtOrgUnit(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func bcoCreate(orgunit *admin.OrgUnit, wg *sync.WaitGroup, ouic *admin.OrgunitsInsertCall) {
+func bcoCreate(orgunit *admin.OrgUnit, ouic *admin.OrgunitsInsertCall) error {
 	lg.Debug("starting bcoCreate()")
 	defer lg.Debug("finished bcoCreate()")
 
-	defer wg.Done()
-
 	b := backoff.NewExponentialBackOff()
 	b.MaxElapsedTime = 32 * time.Second
 
@@ -173,22 +186,23 @@ func bcoCreate(orgunit *admin.OrgUnit, wg *sync.WaitGroup, ouic *admin.OrgunitsI
 			return err
 		}
 		if !cmn.IsErrRetryable(err) {
-			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHOU, err.Error(), orgunit.Name))
+			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHOU, err, orgunit.Name))
 		}
 		// Log the retries
 		lg.Warnw(err.Error(),
 			"retrying", b.GetElapsedTime().String(),
 			"orgunit", orgunit.Name)
-		return fmt.Errorf(gmess.ERR_BATCHOU, err.Error(), orgunit.Name)
+		return fmt.Errorf(gmess.ERR_BATCHOU, err, orgunit.Name)
 	}, b)
 	if err != nil {
 		// Log final error
 		lg.Error(err)
 		fmt.Println(cmn.GminMessage(err.Error()))
 	}
+	return err
 }
 
-func bcoProcessObjects(ds *admin.Service, orgunits []*admin.OrgUnit) error {
+func bcoProcessObjects(ds *admin.Service, pool *btch.Pool, report *btch.Report, orgunits []*admin.OrgUnit) error {
 	lg.Debug("starting bcoProcessObjects()")
 	defer lg.Debug("finished bcoProcessObjects()")
 
@@ -197,28 +211,25 @@ func bcoProcessObjects(ds *admin.Service, orgunits []*admin.OrgUnit) error {
 		return err
 	}
 
-	wg := new(sync.WaitGroup)
+	defer pool.Wait()
 
-	for _, ou := range orgunits {
+	for idx, ou := range orgunits {
+		idx := idx
+		ou := ou
 		if ou.Name == "" || ou.ParentOrgUnitPath == "" {
 			err = errors.New(gmess.ERR_NONAMEOROUPATH)
 			lg.Error(err)
-			return err
+			report.Add(idx, ou.Name, err)
+			continue
 		}
 
 		ouic := ds.Orgunits.Insert(customerID, ou)
 
-		// Sleep for 2 seconds because only 1 orgunit can be created per second but 1 second interval
-		// still can result in rate limit errors
-		time.Sleep(2 * time.Second)
-
-		wg.Add(1)
-
-		go bcoCreate(ou, wg, ouic)
+		pool.Submit(func() {
+			report.Add(idx, ou.Name, bcoCreate(ou, ouic))
+		})
 	}
 
-	wg.Wait()
-
 	return nil
 }
 
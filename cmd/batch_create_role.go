@@ -0,0 +1,269 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	btch "github.com/plusworx/gmin/utils/batch"
+	cmn "github.com/plusworx/gmin/utils/common"
+	cfg "github.com/plusworx/gmin/utils/config"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	rls "github.com/plusworx/gmin/utils/roles"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var batchCrtRoleCmd = &cobra.Command{
+	Use:     "roles -i <input file path or google sheet id>",
+	Aliases: []string{"role"},
+	Example: `gmin batch-create roles -i inputfile.json
+gmin bcrt roles -i inputfile.csv -f csv
+gmin bcrt role -i 1odyAIp3jGspd3M4xeepxWD6aeQIUuHBgrZB2OHSu8MI -s 'Sheet1!A1:C25' -f gsheet`,
+	Short: "Creates a batch of custom admin roles",
+	Long: `Creates a batch of custom admin roles where role details are provided in a Google Sheet, CSV/JSON input file or piped JSON.
+			
+The contents of the JSON file or piped input should look something like this:
+
+{"roleName":"Helpdesk Admin","roleDescription":"Password resets","rolePrivileges":[{"privilegeName":"USERS_RETRIEVE"},{"privilegeName":"USERS_UPDATE"}]}
+{"roleName":"Group Reader","rolePrivileges":[{"privilegeName":"GROUPS_RETRIEVE","serviceId":"00haapch16h1ysv"}]}
+
+Service IDs are looked up from privilege names if they are not provided.
+
+CSV and Google sheets must have a header row with the following column names being the only ones that are valid:
+
+roleDescription
+roleName [required]
+rolePrivileges [required - privilege names separated by (~)]
+
+The column names are case insensitive and can be in any order.`,
+	RunE: doBatchCrtRole,
+}
+
+func doBatchCrtRole(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doBatchCrtRole()",
+		"args", args)
+	defer lg.Debug("finished doBatchCrtRole()")
+
+	var (
+		input *btch.Input
+		objs  []interface{}
+		roles []*admin.Role
+	)
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryRolemanagementScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	inputFlgVal, err := cmd.Flags().GetString(flgnm.FLG_INPUTFILE)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	scanner, err := cmn.InputFromStdIn(inputFlgVal)
+	if err != nil {
+		return err
+	}
+
+	if inputFlgVal == "" && scanner == nil {
+		err := errors.New(gmess.ERR_NOINPUTFILE)
+		lg.Error(err)
+		return err
+	}
+
+	formatFlgVal, err := cmd.Flags().GetString(flgnm.FLG_FORMAT)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	lwrFmt := strings.ToLower(formatFlgVal)
+
+	ok := cmn.SliceContainsStr(cmn.ValidFileFormats, lwrFmt)
+	if !ok {
+		err = fmt.Errorf(gmess.ERR_INVALIDFILEFORMAT, formatFlgVal)
+		lg.Error(err)
+		return err
+	}
+
+	callParams := btch.CallParams{CallType: cmn.CALLTYPECREATE, ObjectType: cmn.OBJTYPEROLE}
+
+	switch {
+	case lwrFmt == "csv":
+		objs, input, err = btch.ProcessCSVFile(callParams, inputFlgVal, rls.RoleAttrMap)
+		if err != nil {
+			return err
+		}
+	case lwrFmt == "json":
+		objs, input, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, rls.RoleAttrMap)
+		if err != nil {
+			return err
+		}
+	case lwrFmt == "gsheet":
+		rangeFlgVal, err := cmd.Flags().GetString(flgnm.FLG_SHEETRANGE)
+		if err != nil {
+			return err
+		}
+
+		objs, input, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, rls.RoleAttrMap)
+		if err != nil {
+			return err
+		}
+	default:
+		err = fmt.Errorf(gmess.ERR_INVALIDFILEFORMAT, formatFlgVal)
+		lg.Error(err)
+		return err
+	}
+
+	for _, roleObj := range objs {
+		roles = append(roles, roleObj.(*admin.Role))
+	}
+
+	pool, err := newBatchPool(cmd, btch.DIRECTORYQPS)
+	if err != nil {
+		return err
+	}
+
+	report, err := newBatchReport(cmd, input)
+	if err != nil {
+		return err
+	}
+
+	err = bcrlProcessObjects(ds, pool, report, roles)
+	if err != nil {
+		return err
+	}
+
+	err = writeBatchReport(report)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func bcrlCreate(role *admin.Role, ric *admin.RolesInsertCall) error {
+	lg.Debug("starting bcrlCreate()")
+	defer lg.Debug("finished bcrlCreate()")
+
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = 32 * time.Second
+
+	err := backoff.Retry(func() error {
+		var err error
+		newRole, err := ric.Do()
+		if err == nil {
+			roleStr := newRole.RoleName + " (" + strconv.FormatInt(newRole.RoleId, 10) + ")"
+			fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_ROLECREATED, roleStr)))
+			lg.Infof(gmess.INFO_ROLECREATED, roleStr)
+			return err
+		}
+		if !cmn.IsErrRetryable(err) {
+			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHROLE, err, role.RoleName))
+		}
+		// Log the retries
+		lg.Warnw(err.Error(),
+			"retrying", b.GetElapsedTime().String(),
+			"role", role.RoleName)
+		return fmt.Errorf(gmess.ERR_BATCHROLE, err, role.RoleName)
+	}, b)
+	if err != nil {
+		// Log final error
+		lg.Error(err)
+		fmt.Println(cmn.GminMessage(err.Error()))
+	}
+	return err
+}
+
+func bcrlProcessObjects(ds *admin.Service, pool *btch.Pool, report *btch.Report, roles []*admin.Role) error {
+	lg.Debug("starting bcrlProcessObjects()")
+	defer lg.Debug("finished bcrlProcessObjects()")
+
+	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
+	if err != nil {
+		return err
+	}
+
+	serviceIDs, err := roleServiceIDs(ds, customerID)
+	if err != nil {
+		return err
+	}
+
+	defer pool.Wait()
+
+	for idx, role := range roles {
+		idx := idx
+		role := role
+		if role.RoleName == "" || len(role.RolePrivileges) == 0 {
+			err = fmt.Errorf(gmess.ERR_EMPTYSTRING, "roleName and rolePrivileges")
+			lg.Error(err)
+			report.Add(idx, role.RoleName, err)
+			continue
+		}
+
+		err = bcrlServiceIDs(role, serviceIDs)
+		if err != nil {
+			report.Add(idx, role.RoleName, err)
+			continue
+		}
+
+		ric := ds.Roles.Insert(customerID, role)
+
+		pool.Submit(func() {
+			report.Add(idx, role.RoleName, bcrlCreate(role, ric))
+		})
+	}
+
+	return nil
+}
+
+func bcrlServiceIDs(role *admin.Role, serviceIDs map[string]string) error {
+	lg.Debugw("starting bcrlServiceIDs()",
+		"role", role.RoleName)
+	defer lg.Debug("finished bcrlServiceIDs()")
+
+	for _, rolePriv := range role.RolePrivileges {
+		err := rls.SetServiceID(rolePriv, serviceIDs)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func init() {
+	batchCreateCmd.AddCommand(batchCrtRoleCmd)
+
+	batchCrtRoleCmd.Flags().StringVarP(&inputFile, flgnm.FLG_INPUTFILE, "i", "", "filepath to role data file or sheet id")
+	batchCrtRoleCmd.Flags().StringVarP(&format, flgnm.FLG_FORMAT, "f", "json", "role data file format")
+	batchCrtRoleCmd.Flags().StringVarP(&sheetRange, flgnm.FLG_SHEETRANGE, "s", "", "role data gsheet range")
+}
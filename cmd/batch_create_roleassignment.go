@@ -0,0 +1,265 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	btch "github.com/plusworx/gmin/utils/batch"
+	cmn "github.com/plusworx/gmin/utils/common"
+	cfg "github.com/plusworx/gmin/utils/config"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	rls "github.com/plusworx/gmin/utils/roles"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var batchCrtRoleAssignCmd = &cobra.Command{
+	Use:     "role-assignments -i <input file path or google sheet id>",
+	Aliases: []string{"role-assignment", "role-asgmts", "role-asgmt", "rasgmts", "rasgmt", "ras", "ra"},
+	Example: `gmin batch-create role-assignments -i inputfile.json
+gmin bcrt ras -i inputfile.csv -f csv
+gmin bcrt ra -i 1odyAIp3jGspd3M4xeepxWD6aeQIUuHBgrZB2OHSu8MI -s 'Sheet1!A1:C25' -f gsheet`,
+	Short: "Creates a batch of admin role assignments",
+	Long: `Creates a batch of admin role assignments where assignment details are provided in a Google Sheet, CSV/JSON input file or piped JSON.
+			
+The contents of the JSON file or piped input should look something like this:
+
+{"assignedTo":"mickey.mouse@disney.com","roleKey":"Helpdesk Admin"}
+{"assignedTo":"minnie.mouse@disney.com","roleKey":"123456789","orgUnit":"/Sales"}
+
+CSV and Google sheets must have a header row with the following column names being the only ones that are valid:
+
+assignedTo [required - user email address or id]
+orgUnit [orgunit path or id - role applies to whole customer if not provided]
+roleKey [required - role name or id]
+
+The column names are case insensitive and can be in any order.`,
+	RunE: doBatchCrtRoleAssign,
+}
+
+func doBatchCrtRoleAssign(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doBatchCrtRoleAssign()",
+		"args", args)
+	defer lg.Debug("finished doBatchCrtRoleAssign()")
+
+	var (
+		input       *btch.Input
+		objs        []interface{}
+		roleAssigns []rls.RoleAssignmentParams
+	)
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryRolemanagementScope, admin.AdminDirectoryUserReadonlyScope,
+		admin.AdminDirectoryOrgunitReadonlyScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	inputFlgVal, err := cmd.Flags().GetString(flgnm.FLG_INPUTFILE)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	scanner, err := cmn.InputFromStdIn(inputFlgVal)
+	if err != nil {
+		return err
+	}
+
+	if inputFlgVal == "" && scanner == nil {
+		err := errors.New(gmess.ERR_NOINPUTFILE)
+		lg.Error(err)
+		return err
+	}
+
+	formatFlgVal, err := cmd.Flags().GetString(flgnm.FLG_FORMAT)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	lwrFmt := strings.ToLower(formatFlgVal)
+
+	ok := cmn.SliceContainsStr(cmn.ValidFileFormats, lwrFmt)
+	if !ok {
+		err = fmt.Errorf(gmess.ERR_INVALIDFILEFORMAT, formatFlgVal)
+		lg.Error(err)
+		return err
+	}
+
+	callParams := btch.CallParams{CallType: cmn.CALLTYPECREATE, ObjectType: cmn.OBJTYPEROLEASSIGN}
+
+	switch {
+	case lwrFmt == "csv":
+		objs, input, err = btch.ProcessCSVFile(callParams, inputFlgVal, rls.RoleAssignmentAttrMap)
+		if err != nil {
+			return err
+		}
+	case lwrFmt == "json":
+		objs, input, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, rls.RoleAssignmentAttrMap)
+		if err != nil {
+			return err
+		}
+	case lwrFmt == "gsheet":
+		rangeFlgVal, err := cmd.Flags().GetString(flgnm.FLG_SHEETRANGE)
+		if err != nil {
+			return err
+		}
+
+		objs, input, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, rls.RoleAssignmentAttrMap)
+		if err != nil {
+			return err
+		}
+	default:
+		err = fmt.Errorf(gmess.ERR_INVALIDFILEFORMAT, formatFlgVal)
+		lg.Error(err)
+		return err
+	}
+
+	for _, raObj := range objs {
+		roleAssigns = append(roleAssigns, raObj.(rls.RoleAssignmentParams))
+	}
+
+	pool, err := newBatchPool(cmd, btch.DIRECTORYQPS)
+	if err != nil {
+		return err
+	}
+
+	report, err := newBatchReport(cmd, input)
+	if err != nil {
+		return err
+	}
+
+	err = bcraProcessObjects(ds, pool, report, roleAssigns)
+	if err != nil {
+		return err
+	}
+
+	err = writeBatchReport(report)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func bcraCreate(raKey string, raic *admin.RoleAssignmentsInsertCall) error {
+	lg.Debugw("starting bcraCreate()",
+		"raKey", raKey)
+	defer lg.Debug("finished bcraCreate()")
+
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = 32 * time.Second
+
+	err := backoff.Retry(func() error {
+		var err error
+		newRoleAssign, err := raic.Do()
+		if err == nil {
+			raID := strconv.FormatInt(newRoleAssign.RoleAssignmentId, 10)
+			fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_RACREATED, raID)))
+			lg.Infof(gmess.INFO_RACREATED, raID)
+			return err
+		}
+		if !cmn.IsErrRetryable(err) {
+			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHROLEASSIGNMENT, err, raKey))
+		}
+		// Log the retries
+		lg.Warnw(err.Error(),
+			"retrying", b.GetElapsedTime().String(),
+			"role assignment", raKey)
+		return fmt.Errorf(gmess.ERR_BATCHROLEASSIGNMENT, err, raKey)
+	}, b)
+	if err != nil {
+		// Log final error
+		lg.Error(err)
+		fmt.Println(cmn.GminMessage(err.Error()))
+	}
+	return err
+}
+
+func bcraProcessObjects(ds *admin.Service, pool *btch.Pool, report *btch.Report, roleAssigns []rls.RoleAssignmentParams) error {
+	lg.Debug("starting bcraProcessObjects()")
+	defer lg.Debug("finished bcraProcessObjects()")
+
+	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
+	if err != nil {
+		return err
+	}
+
+	// Role names are looked up once rather than for every row
+	roleIDs := map[string]string{}
+
+	defer pool.Wait()
+
+	for idx, raParams := range roleAssigns {
+		idx := idx
+		raParams := raParams
+		raKey := raParams.AssignedTo + " - " + raParams.RoleKey
+
+		if raParams.RoleKey != "" {
+			lwrRoleKey := strings.ToLower(raParams.RoleKey)
+			roleID, ok := roleIDs[lwrRoleKey]
+			if !ok {
+				roleID, err = rls.RoleID(ds, customerID, raParams.RoleKey)
+				if err != nil {
+					err = fmt.Errorf(gmess.ERR_BATCHROLEASSIGNMENT, err, raKey)
+					fmt.Println(cmn.GminMessage(err.Error()))
+					report.Add(idx, raKey, err)
+					continue
+				}
+				roleIDs[lwrRoleKey] = roleID
+			}
+			raParams.RoleKey = roleID
+		}
+
+		pool.Submit(func() {
+			roleAssign, err := newRoleAssignment(ds, customerID, raParams)
+			if err != nil {
+				err = fmt.Errorf(gmess.ERR_BATCHROLEASSIGNMENT, err, raKey)
+				fmt.Println(cmn.GminMessage(err.Error()))
+				report.Add(idx, raKey, err)
+				return
+			}
+
+			raic := ds.RoleAssignments.Insert(customerID, roleAssign)
+			report.Add(idx, raKey, bcraCreate(raKey, raic))
+		})
+	}
+
+	return nil
+}
+
+func init() {
+	batchCreateCmd.AddCommand(batchCrtRoleAssignCmd)
+
+	batchCrtRoleAssignCmd.Flags().StringVarP(&inputFile, flgnm.FLG_INPUTFILE, "i", "", "filepath to role assignment data file or sheet id")
+	batchCrtRoleAssignCmd.Flags().StringVarP(&format, flgnm.FLG_FORMAT, "f", "json", "role assignment data file format")
+	batchCrtRoleAssignCmd.Flags().StringVarP(&sheetRange, flgnm.FLG_SHEETRANGE, "s", "", "role assignment data gsheet range")
+}
@@ -26,7 +26,6 @@ import (
 	"errors"
 	"fmt"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -35,6 +34,7 @@ import (
 	flgnm "github.com/plusworx/gmin/utils/flagnames"
 	gmess "github.com/plusworx/gmin/utils/gminmessages"
 	lg "github.com/plusworx/gmin/utils/logging"
+	pwds "github.com/plusworx/gmin/utils/passwords"
 	usrs "github.com/plusworx/gmin/utils/users"
 	"github.com/spf13/cobra"
 	admin "google.golang.org/api/admin/directory/v1"
@@ -59,6 +59,7 @@ CSV and Google sheets must have a header row with the following column names bei
 
 changePasswordAtNextLogin [value true or false]
 firstName [required]
+hashFunction [crypt, MD5 or SHA-1 - only if password is already hashed]
 includeInGlobalAddressList [value true or false]
 ipWhitelisted [value true or false]
 lastName [required]
@@ -69,7 +70,11 @@ recoveryEmail
 recoveryPhone [must start with '+' in E.164 format]
 suspended [value true or false]
 
-The column names are case insensitive and can be in any order. firstName can be replaced by givenName and lastName can be replaced by familyName.`,
+The column names are case insensitive and can be in any order. firstName can be replaced by givenName and lastName can be replaced by familyName.
+
+Passwords are hashed by the hasher given by --hasher, the hasher config value or crypt (SHA-512) by default. Passwords
+that are already hashed, for example when migrating from another system, can be imported by giving their hash function
+(crypt, MD5 or SHA-1) in hashFunction. They are checked but not hashed again.`,
 	RunE: doBatchCrtUser,
 }
 
@@ -79,10 +84,16 @@ func doBatchCrtUser(cmd *cobra.Command, args []string) error {
 	defer lg.Debug("finished doBatchCrtUser()")
 
 	var (
+		input *btch.Input
 		objs  []interface{}
 		users []*admin.User
 	)
 
+	hasher, err := passwordHasher(cmd)
+	if err != nil {
+		return err
+	}
+
 	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryUserScope)
 	if err != nil {
 		return err
@@ -125,12 +136,12 @@ func doBatchCrtUser(cmd *cobra.Command, args []string) error {
 
 	switch {
 	case lwrFmt == "csv":
-		objs, err = btch.ProcessCSVFile(callParams, inputFlgVal, usrs.UserAttrMap)
+		objs, input, err = btch.ProcessCSVFile(callParams, inputFlgVal, usrs.UserAttrMap)
 		if err != nil {
 			return err
 		}
 	case lwrFmt == "json":
-		objs, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, usrs.UserAttrMap)
+		objs, input, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, usrs.UserAttrMap)
 		if err != nil {
 			return err
 		}
@@ -141,7 +152,7 @@ func doBatchCrtUser(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		objs, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, usrs.UserAttrMap)
+		objs, input, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, usrs.UserAttrMap)
 		if err != nil {
 			return err
 		}
@@ -155,7 +166,22 @@ func doBatchCrtUser(cmd *cobra.Command, args []string) error {
 		users = append(users, userObj.(*admin.User))
 	}
 
-	err = bcuProcessObjects(ds, users)
+	pool, err := newBatchPool(cmd, btch.DIRECTORYQPS)
+	if err != nil {
+		return err
+	}
+
+	report, err := newBatchReport(cmd, input)
+	if err != nil {
+		return err
+	}
+
+	err = bcuProcessObjects(ds, pool, report, users, hasher)
+	if err != nil {
+		return err
+	}
+
+	err = writeBatchReport(report)
 	if err != nil {
 		return err
 	}
@@ -163,12 +189,10 @@ func doBatchCrtUser(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func bcuCreate(user *admin.User, wg *sync.WaitGroup, uic *admin.UsersInsertCall) {
+func bcuCreate(user *admin.User, uic *admin.UsersInsertCall) error {
 	lg.Debug("starting bcuCreate()")
 	defer lg.Debug("finished bcuCreate()")
 
-	defer wg.Done()
-
 	b := backoff.NewExponentialBackOff()
 	b.MaxElapsedTime = 32 * time.Second
 
@@ -181,56 +205,58 @@ func bcuCreate(user *admin.User, wg *sync.WaitGroup, uic *admin.UsersInsertCall)
 			return err
 		}
 		if !cmn.IsErrRetryable(err) {
-			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHUSER, err.Error(), user.PrimaryEmail))
+			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHUSER, err, user.PrimaryEmail))
 		}
 		// Log the retries
 		lg.Warnw(err.Error(),
 			"retrying", b.GetElapsedTime().String(),
 			"user", user.PrimaryEmail)
-		return fmt.Errorf(gmess.ERR_BATCHUSER, err.Error(), user.PrimaryEmail)
+		return fmt.Errorf(gmess.ERR_BATCHUSER, err, user.PrimaryEmail)
 	}, b)
 	if err != nil {
 		// Log final error
 		lg.Error(err)
 		fmt.Println(cmn.GminMessage(err.Error()))
 	}
+	return err
 }
 
-func bcuProcessObjects(ds *admin.Service, users []*admin.User) error {
+func bcuProcessObjects(ds *admin.Service, pool *btch.Pool, report *btch.Report, users []*admin.User, hasher pwds.Hasher) error {
 	lg.Debug("starting bcuProcessObjects()")
 	defer lg.Debug("finished bcuProcessObjects()")
 
-	wg := new(sync.WaitGroup)
+	defer pool.Wait()
 
-	for _, u := range users {
+	for idx, u := range users {
+		idx := idx
+		u := u
 		if u.PrimaryEmail == "" || u.Name.GivenName == "" || u.Name.FamilyName == "" || u.Password == "" {
 			err := errors.New(gmess.ERR_BATCHMISSINGUSERDATA)
 			lg.Error(err)
-			return err
+			report.Add(idx, u.PrimaryEmail, err)
+			continue
 		}
 
-		u.HashFunction = usrs.HASHFUNCTION
-		pwd, err := usrs.HashPassword(u.Password)
+		err := pwds.Prepare(u, hasher)
 		if err != nil {
-			return err
+			report.Add(idx, u.PrimaryEmail, err)
+			continue
 		}
-		u.Password = pwd
 
 		uic := ds.Users.Insert(u)
 
-		wg.Add(1)
-
-		go bcuCreate(u, wg, uic)
+		pool.Submit(func() {
+			report.Add(idx, u.PrimaryEmail, bcuCreate(u, uic))
+		})
 	}
 
-	wg.Wait()
-
 	return nil
 }
 
 func init() {
 	batchCreateCmd.AddCommand(batchCrtUserCmd)
 
+	batchCrtUserCmd.Flags().StringVar(&hasher, flgnm.FLG_HASHER, "", "password hasher (crypt, md5 or sha-1)")
 	batchCrtUserCmd.Flags().StringVarP(&inputFile, flgnm.FLG_INPUTFILE, "i", "", "filepath to user data file or sheet id")
 	batchCrtUserCmd.Flags().StringVarP(&format, flgnm.FLG_FORMAT, "f", "json", "user data file format")
 	batchCrtUserCmd.Flags().StringVarP(&sheetRange, flgnm.FLG_SHEETRANGE, "s", "", "user data gsheet range")
@@ -0,0 +1,149 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	btch "github.com/plusworx/gmin/utils/batch"
+	cmn "github.com/plusworx/gmin/utils/common"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var batchDelAspCmd = &cobra.Command{
+	Use:     "app-passwords [-i input file path]",
+	Aliases: []string{"app-password", "asps", "asp"},
+	Example: `gmin batch-delete app-passwords -i inputfile.txt
+gmin bdel asps -i inputfile.txt`,
+	Short: "Deletes all app passwords for a batch of users",
+	Long: `Deletes all app passwords (ASPs) for a batch of users where user details are provided in a text input file or from a pipe.
+			
+The input file or piped in data should provide the user email addresses, aliases or ids on separate lines like this:
+
+frank.castle@mycompany.com
+bruce.wayne@mycompany.com
+peter.parker@mycompany.com
+
+An input Google sheet must have a header row with the following column names being the only ones that are valid:
+
+userKey [required]
+
+The column name is case insensitive.`,
+	RunE: doBatchDelAsp,
+}
+
+func doBatchDelAsp(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doBatchDelAsp()",
+		"args", args)
+	defer lg.Debug("finished doBatchDelAsp()")
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryUserSecurityScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	users, input, err := batchUserKeys(cmd)
+	if err != nil {
+		return err
+	}
+
+	pool, err := newBatchPool(cmd, btch.DIRECTORYQPS)
+	if err != nil {
+		return err
+	}
+
+	report, err := newBatchReport(cmd, input)
+	if err != nil {
+		return err
+	}
+
+	err = bdaspProcessDeletion(ds, pool, report, users)
+	if err != nil {
+		return err
+	}
+
+	err = writeBatchReport(report)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// bdaspDelete deletes all app passwords for a user
+func bdaspDelete(ds *admin.Service, user string) error {
+	lg.Debugw("starting bdaspDelete()",
+		"user", user)
+	defer lg.Debug("finished bdaspDelete()")
+
+	asps, err := ds.Asps.List(user).Fields("items(codeId)").Do()
+	if err != nil {
+		return err
+	}
+
+	for _, asp := range asps.Items {
+		err = ds.Asps.Delete(user, asp.CodeId).Do()
+		if err != nil {
+			return err
+		}
+		lg.Infof(gmess.INFO_ASPDELETED, asp.CodeId, user)
+	}
+
+	fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_ASPSDELETED, len(asps.Items), user)))
+	lg.Infof(gmess.INFO_ASPSDELETED, len(asps.Items), user)
+
+	return nil
+}
+
+func bdaspProcessDeletion(ds *admin.Service, pool *btch.Pool, report *btch.Report, users []string) error {
+	lg.Debug("starting bdaspProcessDeletion()")
+	defer lg.Debug("finished bdaspProcessDeletion()")
+
+	defer pool.Wait()
+
+	for idx, user := range users {
+		idx := idx
+		user := user
+
+		pool.Submit(func() {
+			report.Add(idx, user, batchUserRetry(user, func() error {
+				return bdaspDelete(ds, user)
+			}))
+		})
+	}
+
+	return nil
+}
+
+func init() {
+	batchDelCmd.AddCommand(batchDelAspCmd)
+
+	batchDelAspCmd.Flags().StringVarP(&inputFile, flgnm.FLG_INPUTFILE, "i", "", "filepath to user data text file")
+	batchDelAspCmd.Flags().StringVarP(&delFormat, flgnm.FLG_FORMAT, "f", "text", "user data file format (text or gsheet)")
+	batchDelAspCmd.Flags().StringVarP(&sheetRange, flgnm.FLG_SHEETRANGE, "s", "", "user data gsheet range")
+}
@@ -26,7 +26,6 @@ import (
 	"errors"
 	"fmt"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -68,7 +67,10 @@ func doBatchDelGroup(cmd *cobra.Command, args []string) error {
 		"args", args)
 	defer lg.Debug("finished doBatchDelGroup()")
 
-	var groups []string
+	var (
+		groups []string
+		input  *btch.Input
+	)
 
 	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryGroupScope)
 	if err != nil {
@@ -109,7 +111,7 @@ func doBatchDelGroup(cmd *cobra.Command, args []string) error {
 
 	switch {
 	case lwrFmt == "text":
-		groups, err = btch.DeleteProcessTextFile(inputFlgVal, scanner)
+		groups, input, err = btch.DeleteProcessTextFile(inputFlgVal, scanner)
 		if err != nil {
 			return err
 		}
@@ -119,7 +121,7 @@ func doBatchDelGroup(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		groups, err = btch.DeleteProcessGSheet(inputFlgVal, rangeFlgVal, grps.GroupAttrMap, grps.KEYNAME)
+		groups, input, err = btch.DeleteProcessGSheet(inputFlgVal, rangeFlgVal, grps.GroupAttrMap, grps.KEYNAME)
 		if err != nil {
 			return err
 		}
@@ -129,7 +131,22 @@ func doBatchDelGroup(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	err = bdgProcessDeletion(ds, groups)
+	pool, err := newBatchPool(cmd, btch.DIRECTORYQPS)
+	if err != nil {
+		return err
+	}
+
+	report, err := newBatchReport(cmd, input)
+	if err != nil {
+		return err
+	}
+
+	err = bdgProcessDeletion(ds, pool, report, groups)
+	if err != nil {
+		return err
+	}
+
+	err = writeBatchReport(report)
 	if err != nil {
 		return err
 	}
@@ -137,13 +154,11 @@ func doBatchDelGroup(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func bdgDelete(wg *sync.WaitGroup, gdc *admin.GroupsDeleteCall, group string) {
+func bdgDelete(gdc *admin.GroupsDeleteCall, group string) error {
 	lg.Debugw("starting bdgDelete()",
 		"group", group)
 	defer lg.Debug("finished bdgDelete()")
 
-	defer wg.Done()
-
 	b := backoff.NewExponentialBackOff()
 	b.MaxElapsedTime = 32 * time.Second
 
@@ -157,37 +172,38 @@ func bdgDelete(wg *sync.WaitGroup, gdc *admin.GroupsDeleteCall, group string) {
 			return err
 		}
 		if !cmn.IsErrRetryable(err) {
-			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHGROUP, err.Error(), group))
+			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHGROUP, err, group))
 		}
 		// Log the retries
 		lg.Warnw(err.Error(),
 			"retrying", b.GetElapsedTime().String(),
 			"group", group)
-		return fmt.Errorf(gmess.ERR_BATCHGROUP, err.Error(), group)
+		return fmt.Errorf(gmess.ERR_BATCHGROUP, err, group)
 	}, b)
 	if err != nil {
 		// Log final error
 		lg.Error(err)
 		fmt.Println(cmn.GminMessage(err.Error()))
 	}
+	return err
 }
 
-func bdgProcessDeletion(ds *admin.Service, groups []string) error {
+func bdgProcessDeletion(ds *admin.Service, pool *btch.Pool, report *btch.Report, groups []string) error {
 	lg.Debug("starting bdgProcessDeletion()")
 	defer lg.Debug("finished bdgProcessDeletion()")
 
-	wg := new(sync.WaitGroup)
+	defer pool.Wait()
 
-	for _, group := range groups {
+	for idx, group := range groups {
+		idx := idx
+		group := group
 		gdc := ds.Groups.Delete(group)
 
-		wg.Add(1)
-
-		go bdgDelete(wg, gdc, group)
+		pool.Submit(func() {
+			report.Add(idx, group, bdgDelete(gdc, group))
+		})
 	}
 
-	wg.Wait()
-
 	return nil
 }
 
@@ -26,7 +26,6 @@ import (
 	"errors"
 	"fmt"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -69,7 +68,10 @@ func doBatchDelMember(cmd *cobra.Command, args []string) error {
 		"args", args)
 	defer lg.Debug("finished doBatchDelMember()")
 
-	var members []string
+	var (
+		input   *btch.Input
+		members []string
+	)
 
 	group := args[0]
 
@@ -112,7 +114,7 @@ func doBatchDelMember(cmd *cobra.Command, args []string) error {
 
 	switch {
 	case lwrFmt == "text":
-		members, err = btch.DeleteProcessTextFile(inputFlgVal, scanner)
+		members, input, err = btch.DeleteProcessTextFile(inputFlgVal, scanner)
 		if err != nil {
 			return err
 		}
@@ -122,7 +124,7 @@ func doBatchDelMember(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		members, err = btch.DeleteProcessGSheet(inputFlgVal, rangeFlgVal, mems.MemberAttrMap, mems.KEYNAME)
+		members, input, err = btch.DeleteProcessGSheet(inputFlgVal, rangeFlgVal, mems.MemberAttrMap, mems.KEYNAME)
 		if err != nil {
 			return err
 		}
@@ -132,7 +134,22 @@ func doBatchDelMember(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	err = bdmProcessDeletion(ds, group, members)
+	pool, err := newBatchPool(cmd, btch.DIRECTORYQPS)
+	if err != nil {
+		return err
+	}
+
+	report, err := newBatchReport(cmd, input)
+	if err != nil {
+		return err
+	}
+
+	err = bdmProcessDeletion(ds, pool, report, group, members)
+	if err != nil {
+		return err
+	}
+
+	err = writeBatchReport(report)
 	if err != nil {
 		return err
 	}
@@ -140,14 +157,12 @@ func doBatchDelMember(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func bdmDelete(wg *sync.WaitGroup, mdc *admin.MembersDeleteCall, member string, group string) {
+func bdmDelete(mdc *admin.MembersDeleteCall, member string, group string) error {
 	lg.Debugw("starting bdmDelete()",
 		"group", group,
 		"member", member)
 	defer lg.Debug("finished bdmDelete()")
 
-	defer wg.Done()
-
 	b := backoff.NewExponentialBackOff()
 	b.MaxElapsedTime = 32 * time.Second
 
@@ -160,37 +175,38 @@ func bdmDelete(wg *sync.WaitGroup, mdc *admin.MembersDeleteCall, member string,
 			return err
 		}
 		if !cmn.IsErrRetryable(err) {
-			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHMEMBER, err.Error(), member, group))
+			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHMEMBER, err, member, group))
 		}
 		lg.Warnw(err.Error(),
 			"retrying", b.GetElapsedTime().String(),
 			"group", group,
 			"member", member)
-		return fmt.Errorf(gmess.ERR_BATCHMEMBER, err.Error(), member, group)
+		return fmt.Errorf(gmess.ERR_BATCHMEMBER, err, member, group)
 	}, b)
 	if err != nil {
 		// Log final error
 		lg.Error(err)
 		fmt.Println(cmn.GminMessage(err.Error()))
 	}
+	return err
 }
 
-func bdmProcessDeletion(ds *admin.Service, group string, members []string) error {
+func bdmProcessDeletion(ds *admin.Service, pool *btch.Pool, report *btch.Report, group string, members []string) error {
 	lg.Debug("starting bdmProcessDeletion()")
 	defer lg.Debug("finished bdmProcessDeletion()")
 
-	wg := new(sync.WaitGroup)
+	defer pool.Wait()
 
-	for _, member := range members {
+	for idx, member := range members {
+		idx := idx
+		member := member
 		mdc := ds.Members.Delete(group, member)
 
-		wg.Add(1)
-
-		go bdmDelete(wg, mdc, member, group)
+		pool.Submit(func() {
+			report.Add(idx, member, bdmDelete(mdc, member, group))
+		})
 	}
 
-	wg.Wait()
-
 	return nil
 }
 
@@ -26,7 +26,6 @@ import (
 	"errors"
 	"fmt"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -69,7 +68,10 @@ func doBatchDelMobDev(cmd *cobra.Command, args []string) error {
 		"args", args)
 	defer lg.Debug("finished doBatchDelMobDev()")
 
-	var mobdevs []string
+	var (
+		input   *btch.Input
+		mobdevs []string
+	)
 
 	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryDeviceMobileScope)
 	if err != nil {
@@ -110,7 +112,7 @@ func doBatchDelMobDev(cmd *cobra.Command, args []string) error {
 
 	switch {
 	case lwrFmt == "text":
-		mobdevs, err = btch.DeleteProcessTextFile(inputFlgVal, scanner)
+		mobdevs, input, err = btch.DeleteProcessTextFile(inputFlgVal, scanner)
 		if err != nil {
 			return err
 		}
@@ -120,7 +122,7 @@ func doBatchDelMobDev(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		mobdevs, err = btch.DeleteProcessGSheet(inputFlgVal, rangeFlgVal, mdevs.MobDevAttrMap, mdevs.KEYNAME)
+		mobdevs, input, err = btch.DeleteProcessGSheet(inputFlgVal, rangeFlgVal, mdevs.MobDevAttrMap, mdevs.KEYNAME)
 		if err != nil {
 			return err
 		}
@@ -130,7 +132,22 @@ func doBatchDelMobDev(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	err = bdmdProcessDeletion(ds, mobdevs)
+	pool, err := newBatchPool(cmd, btch.DIRECTORYQPS)
+	if err != nil {
+		return err
+	}
+
+	report, err := newBatchReport(cmd, input)
+	if err != nil {
+		return err
+	}
+
+	err = bdmdProcessDeletion(ds, pool, report, mobdevs)
+	if err != nil {
+		return err
+	}
+
+	err = writeBatchReport(report)
 	if err != nil {
 		return err
 	}
@@ -138,13 +155,11 @@ func doBatchDelMobDev(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func bdmdDelete(wg *sync.WaitGroup, mdc *admin.MobiledevicesDeleteCall, resourceID string) {
+func bdmdDelete(mdc *admin.MobiledevicesDeleteCall, resourceID string) error {
 	lg.Debugw("starting bdmdDelete()",
 		"resourceID", resourceID)
 	defer lg.Debug("finished bdmdDelete()")
 
-	defer wg.Done()
-
 	b := backoff.NewExponentialBackOff()
 	b.MaxElapsedTime = 32 * time.Second
 
@@ -157,22 +172,23 @@ func bdmdDelete(wg *sync.WaitGroup, mdc *admin.MobiledevicesDeleteCall, resource
 			return err
 		}
 		if !cmn.IsErrRetryable(err) {
-			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHMOBILEDEVICE, err.Error(), resourceID))
+			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHMOBILEDEVICE, err, resourceID))
 		}
 		// Log the retries
 		lg.Warnw(err.Error(),
 			"retrying", b.GetElapsedTime().String(),
 			"mobile device", resourceID)
-		return fmt.Errorf(gmess.ERR_BATCHMOBILEDEVICE, err.Error(), resourceID)
+		return fmt.Errorf(gmess.ERR_BATCHMOBILEDEVICE, err, resourceID)
 	}, b)
 	if err != nil {
 		// Log final error
 		lg.Error(err)
 		fmt.Println(cmn.GminMessage(err.Error()))
 	}
+	return err
 }
 
-func bdmdProcessDeletion(ds *admin.Service, mobdevs []string) error {
+func bdmdProcessDeletion(ds *admin.Service, pool *btch.Pool, report *btch.Report, mobdevs []string) error {
 	lg.Debug("starting bdmdProcessDeletion()")
 	defer lg.Debug("finished bdmdProcessDeletion()")
 
@@ -182,18 +198,18 @@ func bdmdProcessDeletion(ds *admin.Service, mobdevs []string) error {
 		return err
 	}
 
-	wg := new(sync.WaitGroup)
+	defer pool.Wait()
 
-	for _, mobResID := range mobdevs {
+	for idx, mobResID := range mobdevs {
+		idx := idx
+		mobResID := mobResID
 		mdc := ds.Mobiledevices.Delete(customerID, mobResID)
 
-		wg.Add(1)
-
-		go bdmdDelete(wg, mdc, mobResID)
+		pool.Submit(func() {
+			report.Add(idx, mobResID, bdmdDelete(mdc, mobResID))
+		})
 	}
 
-	wg.Wait()
-
 	return nil
 }
 
@@ -26,7 +26,6 @@ import (
 	"errors"
 	"fmt"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -69,7 +68,10 @@ func doBatchDelOrgUnit(cmd *cobra.Command, args []string) error {
 		"args", args)
 	defer lg.Debug("finished doBatchDelOrgUnit()")
 
-	var orgunits []string
+	var (
+		input    *btch.Input
+		orgunits []string
+	)
 
 	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryOrgunitScope)
 	if err != nil {
@@ -110,7 +112,7 @@ func doBatchDelOrgUnit(cmd *cobra.Command, args []string) error {
 
 	switch {
 	case lwrFmt == "text":
-		orgunits, err = btch.DeleteProcessTextFile(inputFlgVal, scanner)
+		orgunits, input, err = btch.DeleteProcessTextFile(inputFlgVal, scanner)
 		if err != nil {
 			return err
 		}
@@ -120,7 +122,7 @@ func doBatchDelOrgUnit(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		orgunits, err = btch.DeleteProcessGSheet(inputFlgVal, rangeFlgVal, ous.OrgUnitAttrMap, ous.KEYNAME)
+		orgunits, input, err = btch.DeleteProcessGSheet(inputFlgVal, rangeFlgVal, ous.OrgUnitAttrMap, ous.KEYNAME)
 		if err != nil {
 			return err
 		}
@@ -130,7 +132,22 @@ func doBatchDelOrgUnit(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	err = bdoProcessDeletion(ds, orgunits)
+	pool, err := newBatchPool(cmd, btch.ORGUNITQPS)
+	if err != nil {
+		return err
+	}
+
+	report, err := newBatchReport(cmd, input)
+	if err != nil {
+		return err
+	}
+
+	err = bdoProcessDeletion(ds, pool, report, orgunits)
+	if err != nil {
+		return err
+	}
+
+	err = writeBatchReport(report)
 	if err != nil {
 		return err
 	}
@@ -138,13 +155,11 @@ func doBatchDelOrgUnit(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func bdoDelete(wg *sync.WaitGroup, oudc *admin.OrgunitsDeleteCall, ouPath string) {
+func bdoDelete(oudc *admin.OrgunitsDeleteCall, ouPath string) error {
 	lg.Debugw("starting bdoDelete()",
 		"ouPath", ouPath)
 	defer lg.Debug("finished bdoDelete()")
 
-	defer wg.Done()
-
 	b := backoff.NewExponentialBackOff()
 	b.MaxElapsedTime = 32 * time.Second
 
@@ -158,22 +173,23 @@ func bdoDelete(wg *sync.WaitGroup, oudc *admin.OrgunitsDeleteCall, ouPath string
 			return err
 		}
 		if !cmn.IsErrRetryable(err) {
-			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHOU, err.Error(), ouPath))
+			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHOU, err, ouPath))
 		}
 		// Log the retries
 		lg.Warnw(err.Error(),
 			"retrying", b.GetElapsedTime().String(),
 			"orgunit", ouPath)
-		return fmt.Errorf(gmess.ERR_BATCHOU, err.Error(), ouPath)
+		return fmt.Errorf(gmess.ERR_BATCHOU, err, ouPath)
 	}, b)
 	if err != nil {
 		// Log final error
 		lg.Error(err)
 		fmt.Println(cmn.GminMessage(err.Error()))
 	}
+	return err
 }
 
-func bdoProcessDeletion(ds *admin.Service, orgunits []string) error {
+func bdoProcessDeletion(ds *admin.Service, pool *btch.Pool, report *btch.Report, orgunits []string) error {
 	lg.Debug("starting bdoProcessDeletion()")
 	defer lg.Debug("finished bdoProcessDeletion()")
 
@@ -182,26 +198,22 @@ func bdoProcessDeletion(ds *admin.Service, orgunits []string) error {
 		return err
 	}
 
-	wg := new(sync.WaitGroup)
+	defer pool.Wait()
 
-	for _, orgunit := range orgunits {
+	for idx, orgunit := range orgunits {
+		idx := idx
+		orgunit := orgunit
 		if orgunit[0] == '/' {
 			orgunit = orgunit[1:]
 		}
 
 		oudc := ds.Orgunits.Delete(customerID, orgunit)
 
-		// Sleep for 2 seconds because only 1 orgunit can be deleted per second but 1 second interval
-		// still results in rate limit errors
-		time.Sleep(2 * time.Second)
-
-		wg.Add(1)
-
-		go bdoDelete(wg, oudc, orgunit)
+		pool.Submit(func() {
+			report.Add(idx, orgunit, bdoDelete(oudc, orgunit))
+		})
 	}
 
-	wg.Wait()
-
 	return nil
 }
 
@@ -0,0 +1,231 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	btch "github.com/plusworx/gmin/utils/batch"
+	cmn "github.com/plusworx/gmin/utils/common"
+	cfg "github.com/plusworx/gmin/utils/config"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	rls "github.com/plusworx/gmin/utils/roles"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var batchDelRoleCmd = &cobra.Command{
+	Use:     "roles [-i input file path]",
+	Aliases: []string{"role"},
+	Example: `gmin batch-delete roles -i inputfile.txt
+gmin bdel roles -i inputfile.txt
+gmin ls roles -a roleid | jq '.items[] | .roleId' -r | gmin bdel role`,
+	Short: "Deletes a batch of custom admin roles",
+	Long: `Deletes a batch of custom admin roles where role details are provided in a text input file or through a pipe.
+			
+The input file or piped in data should provide the role names or ids to be deleted on separate lines like this:
+
+Helpdesk Admin
+Group Reader
+123456789
+
+An input Google sheet must have a header row with the following column names being the only ones that are valid:
+
+roleKey [required]
+
+The column name is case insensitive.`,
+	RunE: doBatchDelRole,
+}
+
+func doBatchDelRole(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doBatchDelRole()",
+		"args", args)
+	defer lg.Debug("finished doBatchDelRole()")
+
+	var (
+		input *btch.Input
+		roles []string
+	)
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryRolemanagementScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	inputFlgVal, err := cmd.Flags().GetString(flgnm.FLG_INPUTFILE)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	scanner, err := cmn.InputFromStdIn(inputFlgVal)
+	if err != nil {
+		return err
+	}
+
+	if inputFlgVal == "" && scanner == nil {
+		err := errors.New(gmess.ERR_NOINPUTFILE)
+		lg.Error(err)
+		return err
+	}
+
+	formatFlgVal, err := cmd.Flags().GetString(flgnm.FLG_FORMAT)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	lwrFmt := strings.ToLower(formatFlgVal)
+
+	ok := cmn.SliceContainsStr(cmn.ValidFileFormats, lwrFmt)
+	if !ok {
+		err = fmt.Errorf(gmess.ERR_INVALIDFILEFORMAT, formatFlgVal)
+		lg.Error(err)
+		return err
+	}
+
+	switch {
+	case lwrFmt == "text":
+		roles, input, err = btch.DeleteProcessTextFile(inputFlgVal, scanner)
+		if err != nil {
+			return err
+		}
+	case lwrFmt == "gsheet":
+		rangeFlgVal, err := cmd.Flags().GetString(flgnm.FLG_SHEETRANGE)
+		if err != nil {
+			return err
+		}
+
+		roles, input, err = btch.DeleteProcessGSheet(inputFlgVal, rangeFlgVal, rls.RoleAttrMap, rls.KEYNAME)
+		if err != nil {
+			return err
+		}
+	default:
+		err = fmt.Errorf(gmess.ERR_INVALIDFILEFORMAT, formatFlgVal)
+		lg.Error(err)
+		return err
+	}
+
+	pool, err := newBatchPool(cmd, btch.DIRECTORYQPS)
+	if err != nil {
+		return err
+	}
+
+	report, err := newBatchReport(cmd, input)
+	if err != nil {
+		return err
+	}
+
+	err = bdrlProcessDeletion(ds, pool, report, roles)
+	if err != nil {
+		return err
+	}
+
+	err = writeBatchReport(report)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func bdrlDelete(rdc *admin.RolesDeleteCall, roleKey string) error {
+	lg.Debugw("starting bdrlDelete()",
+		"roleKey", roleKey)
+	defer lg.Debug("finished bdrlDelete()")
+
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = 32 * time.Second
+
+	err := backoff.Retry(func() error {
+		var err error
+
+		err = rdc.Do()
+		if err == nil {
+			fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_ROLEDELETED, roleKey)))
+			lg.Infof(gmess.INFO_ROLEDELETED, roleKey)
+			return err
+		}
+		if !cmn.IsErrRetryable(err) {
+			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHROLE, err, roleKey))
+		}
+		// Log the retries
+		lg.Warnw(err.Error(),
+			"retrying", b.GetElapsedTime().String(),
+			"role", roleKey)
+		return fmt.Errorf(gmess.ERR_BATCHROLE, err, roleKey)
+	}, b)
+	if err != nil {
+		// Log final error
+		lg.Error(err)
+		fmt.Println(cmn.GminMessage(err.Error()))
+	}
+	return err
+}
+
+func bdrlProcessDeletion(ds *admin.Service, pool *btch.Pool, report *btch.Report, roles []string) error {
+	lg.Debug("starting bdrlProcessDeletion()")
+	defer lg.Debug("finished bdrlProcessDeletion()")
+
+	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
+	if err != nil {
+		return err
+	}
+
+	defer pool.Wait()
+
+	for idx, roleKey := range roles {
+		idx := idx
+		roleKey := roleKey
+
+		roleID, err := rls.RoleID(ds, customerID, roleKey)
+		if err != nil {
+			err = fmt.Errorf(gmess.ERR_BATCHROLE, err, roleKey)
+			fmt.Println(cmn.GminMessage(err.Error()))
+			report.Add(idx, roleKey, err)
+			continue
+		}
+
+		rdc := ds.Roles.Delete(customerID, roleID)
+
+		pool.Submit(func() {
+			report.Add(idx, roleKey, bdrlDelete(rdc, roleKey))
+		})
+	}
+
+	return nil
+}
+
+func init() {
+	batchDelCmd.AddCommand(batchDelRoleCmd)
+
+	batchDelRoleCmd.Flags().StringVarP(&inputFile, flgnm.FLG_INPUTFILE, "i", "", "filepath to role data text file")
+	batchDelRoleCmd.Flags().StringVarP(&delFormat, flgnm.FLG_FORMAT, "f", "text", "role data file format (text or gsheet)")
+	batchDelRoleCmd.Flags().StringVarP(&sheetRange, flgnm.FLG_SHEETRANGE, "s", "", "role data gsheet range")
+}
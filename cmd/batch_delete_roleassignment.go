@@ -0,0 +1,223 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	btch "github.com/plusworx/gmin/utils/batch"
+	cmn "github.com/plusworx/gmin/utils/common"
+	cfg "github.com/plusworx/gmin/utils/config"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	rls "github.com/plusworx/gmin/utils/roles"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var batchDelRoleAssignCmd = &cobra.Command{
+	Use:     "role-assignments [-i input file path]",
+	Aliases: []string{"role-assignment", "role-asgmts", "role-asgmt", "rasgmts", "rasgmt", "ras", "ra"},
+	Example: `gmin batch-delete role-assignments -i inputfile.txt
+gmin bdel ras -i inputfile.txt
+gmin ls ras -r "Helpdesk Admin" -a roleassignmentid | jq '.items[] | .roleAssignmentId' -r | gmin bdel ra`,
+	Short: "Deletes a batch of admin role assignments",
+	Long: `Deletes a batch of admin role assignments where role assignment details are provided in a text input file or through a pipe.
+			
+The input file or piped in data should provide the role assignment ids to be deleted on separate lines like this:
+
+123456789
+234567890
+345678901
+
+An input Google sheet must have a header row with the following column names being the only ones that are valid:
+
+roleAssignmentId [required]
+
+The column name is case insensitive.`,
+	RunE: doBatchDelRoleAssign,
+}
+
+func doBatchDelRoleAssign(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doBatchDelRoleAssign()",
+		"args", args)
+	defer lg.Debug("finished doBatchDelRoleAssign()")
+
+	var (
+		input       *btch.Input
+		roleAssigns []string
+	)
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryRolemanagementScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	inputFlgVal, err := cmd.Flags().GetString(flgnm.FLG_INPUTFILE)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	scanner, err := cmn.InputFromStdIn(inputFlgVal)
+	if err != nil {
+		return err
+	}
+
+	if inputFlgVal == "" && scanner == nil {
+		err := errors.New(gmess.ERR_NOINPUTFILE)
+		lg.Error(err)
+		return err
+	}
+
+	formatFlgVal, err := cmd.Flags().GetString(flgnm.FLG_FORMAT)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	lwrFmt := strings.ToLower(formatFlgVal)
+
+	ok := cmn.SliceContainsStr(cmn.ValidFileFormats, lwrFmt)
+	if !ok {
+		err = fmt.Errorf(gmess.ERR_INVALIDFILEFORMAT, formatFlgVal)
+		lg.Error(err)
+		return err
+	}
+
+	switch {
+	case lwrFmt == "text":
+		roleAssigns, input, err = btch.DeleteProcessTextFile(inputFlgVal, scanner)
+		if err != nil {
+			return err
+		}
+	case lwrFmt == "gsheet":
+		rangeFlgVal, err := cmd.Flags().GetString(flgnm.FLG_SHEETRANGE)
+		if err != nil {
+			return err
+		}
+
+		roleAssigns, input, err = btch.DeleteProcessGSheet(inputFlgVal, rangeFlgVal, rls.RoleAssignmentAttrMap, rls.RAKEYNAME)
+		if err != nil {
+			return err
+		}
+	default:
+		err = fmt.Errorf(gmess.ERR_INVALIDFILEFORMAT, formatFlgVal)
+		lg.Error(err)
+		return err
+	}
+
+	pool, err := newBatchPool(cmd, btch.DIRECTORYQPS)
+	if err != nil {
+		return err
+	}
+
+	report, err := newBatchReport(cmd, input)
+	if err != nil {
+		return err
+	}
+
+	err = bdraProcessDeletion(ds, pool, report, roleAssigns)
+	if err != nil {
+		return err
+	}
+
+	err = writeBatchReport(report)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func bdraDelete(radc *admin.RoleAssignmentsDeleteCall, raID string) error {
+	lg.Debugw("starting bdraDelete()",
+		"raID", raID)
+	defer lg.Debug("finished bdraDelete()")
+
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = 32 * time.Second
+
+	err := backoff.Retry(func() error {
+		var err error
+
+		err = radc.Do()
+		if err == nil {
+			fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_RADELETED, raID)))
+			lg.Infof(gmess.INFO_RADELETED, raID)
+			return err
+		}
+		if !cmn.IsErrRetryable(err) {
+			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHROLEASSIGNMENT, err, raID))
+		}
+		// Log the retries
+		lg.Warnw(err.Error(),
+			"retrying", b.GetElapsedTime().String(),
+			"role assignment", raID)
+		return fmt.Errorf(gmess.ERR_BATCHROLEASSIGNMENT, err, raID)
+	}, b)
+	if err != nil {
+		// Log final error
+		lg.Error(err)
+		fmt.Println(cmn.GminMessage(err.Error()))
+	}
+	return err
+}
+
+func bdraProcessDeletion(ds *admin.Service, pool *btch.Pool, report *btch.Report, roleAssigns []string) error {
+	lg.Debug("starting bdraProcessDeletion()")
+	defer lg.Debug("finished bdraProcessDeletion()")
+
+	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
+	if err != nil {
+		return err
+	}
+
+	defer pool.Wait()
+
+	for idx, raID := range roleAssigns {
+		idx := idx
+		raID := raID
+
+		radc := ds.RoleAssignments.Delete(customerID, raID)
+
+		pool.Submit(func() {
+			report.Add(idx, raID, bdraDelete(radc, raID))
+		})
+	}
+
+	return nil
+}
+
+func init() {
+	batchDelCmd.AddCommand(batchDelRoleAssignCmd)
+
+	batchDelRoleAssignCmd.Flags().StringVarP(&inputFile, flgnm.FLG_INPUTFILE, "i", "", "filepath to role assignment data text file")
+	batchDelRoleAssignCmd.Flags().StringVarP(&delFormat, flgnm.FLG_FORMAT, "f", "text", "role assignment data file format (text or gsheet)")
+	batchDelRoleAssignCmd.Flags().StringVarP(&sheetRange, flgnm.FLG_SHEETRANGE, "s", "", "role assignment data gsheet range")
+}
@@ -0,0 +1,170 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	btch "github.com/plusworx/gmin/utils/batch"
+	cmn "github.com/plusworx/gmin/utils/common"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var batchDelTokenCmd = &cobra.Command{
+	Use:     "tokens [-i input file path]",
+	Aliases: []string{"token", "toks", "tok"},
+	Example: `gmin batch-delete tokens -i inputfile.txt
+gmin bdel toks -i inputfile.txt --client-id 123456789.apps.googleusercontent.com
+gmin ls user -a primaryemail -q orgunitpath=/Leavers | jq '.users[] | .primaryEmail' -r | gmin bdel toks`,
+	Short: "Deletes OAuth tokens for a batch of users",
+	Long: `Deletes OAuth tokens issued to third-party applications for a batch of users where user details are provided in a text input file or from a pipe.
+
+If --client-id is provided then only the token issued to that client is deleted for each user, otherwise all of each user's tokens are deleted.
+			
+The input file or piped in data should provide the user email addresses, aliases or ids on separate lines like this:
+
+frank.castle@mycompany.com
+bruce.wayne@mycompany.com
+peter.parker@mycompany.com
+
+An input Google sheet must have a header row with the following column names being the only ones that are valid:
+
+userKey [required]
+
+The column name is case insensitive.`,
+	RunE: doBatchDelToken,
+}
+
+func doBatchDelToken(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doBatchDelToken()",
+		"args", args)
+	defer lg.Debug("finished doBatchDelToken()")
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryUserSecurityScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	flgClientIDVal, err := cmd.Flags().GetString(flgnm.FLG_CLIENTID)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	users, input, err := batchUserKeys(cmd)
+	if err != nil {
+		return err
+	}
+
+	pool, err := newBatchPool(cmd, btch.DIRECTORYQPS)
+	if err != nil {
+		return err
+	}
+
+	report, err := newBatchReport(cmd, input)
+	if err != nil {
+		return err
+	}
+
+	err = bdtokProcessDeletion(ds, pool, report, users, flgClientIDVal)
+	if err != nil {
+		return err
+	}
+
+	err = writeBatchReport(report)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// bdtokDelete deletes the token issued to clientID or, if clientID is empty, all tokens for a user
+func bdtokDelete(ds *admin.Service, user string, clientID string) error {
+	lg.Debugw("starting bdtokDelete()",
+		"user", user,
+		"clientID", clientID)
+	defer lg.Debug("finished bdtokDelete()")
+
+	if clientID != "" {
+		err := ds.Tokens.Delete(user, clientID).Do()
+		if err != nil {
+			return err
+		}
+		fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_TOKENDELETED, clientID, user)))
+		lg.Infof(gmess.INFO_TOKENDELETED, clientID, user)
+		return nil
+	}
+
+	tokens, err := ds.Tokens.List(user).Fields("items(clientId)").Do()
+	if err != nil {
+		return err
+	}
+
+	for _, token := range tokens.Items {
+		err = ds.Tokens.Delete(user, token.ClientId).Do()
+		if err != nil {
+			return err
+		}
+		lg.Infof(gmess.INFO_TOKENDELETED, token.ClientId, user)
+	}
+
+	fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_TOKENSDELETED, len(tokens.Items), user)))
+	lg.Infof(gmess.INFO_TOKENSDELETED, len(tokens.Items), user)
+
+	return nil
+}
+
+func bdtokProcessDeletion(ds *admin.Service, pool *btch.Pool, report *btch.Report, users []string, clientID string) error {
+	lg.Debug("starting bdtokProcessDeletion()")
+	defer lg.Debug("finished bdtokProcessDeletion()")
+
+	defer pool.Wait()
+
+	for idx, user := range users {
+		idx := idx
+		user := user
+
+		pool.Submit(func() {
+			report.Add(idx, user, batchUserRetry(user, func() error {
+				return bdtokDelete(ds, user, clientID)
+			}))
+		})
+	}
+
+	return nil
+}
+
+func init() {
+	batchDelCmd.AddCommand(batchDelTokenCmd)
+
+	batchDelTokenCmd.Flags().StringVarP(&clientID, flgnm.FLG_CLIENTID, "", "", "client id of token to delete (default is all tokens)")
+	batchDelTokenCmd.Flags().StringVarP(&inputFile, flgnm.FLG_INPUTFILE, "i", "", "filepath to user data text file")
+	batchDelTokenCmd.Flags().StringVarP(&delFormat, flgnm.FLG_FORMAT, "f", "text", "user data file format (text or gsheet)")
+	batchDelTokenCmd.Flags().StringVarP(&sheetRange, flgnm.FLG_SHEETRANGE, "s", "", "user data gsheet range")
+}
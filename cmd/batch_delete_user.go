@@ -26,7 +26,6 @@ import (
 	"errors"
 	"fmt"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -68,7 +67,10 @@ func doBatchDelUser(cmd *cobra.Command, args []string) error {
 		"args", args)
 	defer lg.Debug("finished doBatchDelUser()")
 
-	var users []string
+	var (
+		input *btch.Input
+		users []string
+	)
 
 	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryUserScope)
 	if err != nil {
@@ -109,7 +111,7 @@ func doBatchDelUser(cmd *cobra.Command, args []string) error {
 
 	switch {
 	case lwrFmt == "text":
-		users, err = btch.DeleteProcessTextFile(inputFlgVal, scanner)
+		users, input, err = btch.DeleteProcessTextFile(inputFlgVal, scanner)
 		if err != nil {
 			return err
 		}
@@ -119,7 +121,7 @@ func doBatchDelUser(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		users, err = btch.DeleteProcessGSheet(inputFlgVal, rangeFlgVal, usrs.UserAttrMap, usrs.KEYNAME)
+		users, input, err = btch.DeleteProcessGSheet(inputFlgVal, rangeFlgVal, usrs.UserAttrMap, usrs.KEYNAME)
 		if err != nil {
 			return err
 		}
@@ -129,7 +131,22 @@ func doBatchDelUser(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	err = bduProcessDeletion(ds, users)
+	pool, err := newBatchPool(cmd, btch.DIRECTORYQPS)
+	if err != nil {
+		return err
+	}
+
+	report, err := newBatchReport(cmd, input)
+	if err != nil {
+		return err
+	}
+
+	err = bduProcessDeletion(ds, pool, report, users)
+	if err != nil {
+		return err
+	}
+
+	err = writeBatchReport(report)
 	if err != nil {
 		return err
 	}
@@ -137,13 +154,11 @@ func doBatchDelUser(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func bduDelete(wg *sync.WaitGroup, udc *admin.UsersDeleteCall, user string) {
+func bduDelete(udc *admin.UsersDeleteCall, user string) error {
 	lg.Debugw("starting bduDelete()",
 		"user", user)
 	defer lg.Debug("finished bduDelete()")
 
-	defer wg.Done()
-
 	b := backoff.NewExponentialBackOff()
 	b.MaxElapsedTime = 32 * time.Second
 
@@ -157,37 +172,38 @@ func bduDelete(wg *sync.WaitGroup, udc *admin.UsersDeleteCall, user string) {
 			return err
 		}
 		if !cmn.IsErrRetryable(err) {
-			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHUSER, err.Error(), user))
+			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHUSER, err, user))
 		}
 		// Log the retries
 		lg.Warnw(err.Error(),
 			"retrying", b.GetElapsedTime().String(),
 			"user", user)
-		return fmt.Errorf(gmess.ERR_BATCHUSER, err.Error(), user)
+		return fmt.Errorf(gmess.ERR_BATCHUSER, err, user)
 	}, b)
 	if err != nil {
 		// Log final error
 		lg.Error(err)
 		fmt.Println(cmn.GminMessage(err.Error()))
 	}
+	return err
 }
 
-func bduProcessDeletion(ds *admin.Service, users []string) error {
+func bduProcessDeletion(ds *admin.Service, pool *btch.Pool, report *btch.Report, users []string) error {
 	lg.Debug("starting bduProcessDeletion()")
 	defer lg.Debug("finished bduProcessDeletion()")
 
-	wg := new(sync.WaitGroup)
+	defer pool.Wait()
 
-	for _, user := range users {
+	for idx, user := range users {
+		idx := idx
+		user := user
 		udc := ds.Users.Delete(user)
 
-		wg.Add(1)
-
-		go bduDelete(wg, udc, user)
+		pool.Submit(func() {
+			report.Add(idx, user, bduDelete(udc, user))
+		})
 	}
 
-	wg.Wait()
-
 	return nil
 }
 
@@ -23,6 +23,7 @@ THE SOFTWARE.
 package cmd
 
 import (
+	btch "github.com/plusworx/gmin/utils/batch"
 	flgnm "github.com/plusworx/gmin/utils/flagnames"
 	"github.com/spf13/cobra"
 )
@@ -31,8 +32,8 @@ var batchManageCmd = &cobra.Command{
 	Use:     "batch-manage",
 	Aliases: []string{"bmanage", "bmng"},
 	Args:    cobra.NoArgs,
-	Short:   "Manages a batch of Google Workspace devices",
-	Long:    "Manages a batch of Google Workspace devices.",
+	Short:   "Manages a batch of Google Workspace objects",
+	Long:    "Manages a batch of Google Workspace objects.",
 	Run:     doBatchManage,
 }
 
@@ -44,6 +45,10 @@ func init() {
 	rootCmd.AddCommand(batchManageCmd)
 	batchManageCmd.PersistentFlags().BoolVar(&silent, flgnm.FLG_SILENT, false, "suppress console output")
 	batchManageCmd.PersistentFlags().StringVar(&logLevel, flgnm.FLG_LOGLEVEL, "info", "log level (debug, info, error, warn)")
+	batchManageCmd.PersistentFlags().StringVar(&resultsDir, flgnm.FLG_RESULTSDIR, "", "directory for batch results and failed rows files (default is current directory)")
+	batchManageCmd.PersistentFlags().StringVar(&resultsFormat, flgnm.FLG_RESULTSFORMAT, "json", "batch results file format (csv or json)")
+	batchManageCmd.PersistentFlags().Float64Var(&qps, flgnm.FLG_QPS, 0, "maximum API calls per second (default is API quota based)")
+	batchManageCmd.PersistentFlags().IntVar(&workers, flgnm.FLG_WORKERS, btch.DEFAULTWORKERS, "number of concurrent API workers")
 
 	batchManageCmd.PersistentPreRunE = preRun
 }
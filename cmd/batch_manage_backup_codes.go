@@ -0,0 +1,139 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	btch "github.com/plusworx/gmin/utils/batch"
+	cmn "github.com/plusworx/gmin/utils/common"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	usec "github.com/plusworx/gmin/utils/usersecurity"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var batchMngBCodesCmd = &cobra.Command{
+	Use:     "backup-codes <action> [-i input file path]",
+	Aliases: []string{"backup-code", "bcodes", "bcode"},
+	Args:    cobra.ExactArgs(1),
+	Example: `gmin batch-manage backup-codes generate -i inputfile.txt
+gmin bmng bcodes invalidate -i inputfile.txt`,
+	Short: "Generates or invalidates backup codes for a batch of users",
+	Long: `Generates or invalidates 2-step verification backup codes for a batch of users where user details are provided in a text input file or from a pipe.
+			
+The input file or piped in data should provide the user email addresses, aliases or ids on separate lines like this:
+
+frank.castle@mycompany.com
+bruce.wayne@mycompany.com
+peter.parker@mycompany.com
+
+An input Google sheet must have a header row with the following column names being the only ones that are valid:
+
+userKey [required]
+
+The column name is case insensitive.
+
+Valid actions are:
+generate - generates a new set of backup codes, invalidating any existing codes
+invalidate - invalidates all current backup codes`,
+	RunE: doBatchMngBCodes,
+}
+
+func doBatchMngBCodes(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doBatchMngBCodes()",
+		"args", args)
+	defer lg.Debug("finished doBatchMngBCodes()")
+
+	action := strings.ToLower(args[0])
+	ok := cmn.SliceContainsStr(usec.ValidBackupCodeActions, action)
+	if !ok {
+		err := fmt.Errorf(gmess.ERR_INVALIDACTIONTYPE, args[0])
+		lg.Error(err)
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryUserSecurityScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	users, input, err := batchUserKeys(cmd)
+	if err != nil {
+		return err
+	}
+
+	pool, err := newBatchPool(cmd, btch.DIRECTORYQPS)
+	if err != nil {
+		return err
+	}
+
+	report, err := newBatchReport(cmd, input)
+	if err != nil {
+		return err
+	}
+
+	err = bmngbcProcessObjects(ds, pool, report, users, action)
+	if err != nil {
+		return err
+	}
+
+	err = writeBatchReport(report)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func bmngbcProcessObjects(ds *admin.Service, pool *btch.Pool, report *btch.Report, users []string, action string) error {
+	lg.Debug("starting bmngbcProcessObjects()")
+	defer lg.Debug("finished bmngbcProcessObjects()")
+
+	defer pool.Wait()
+
+	for idx, user := range users {
+		idx := idx
+		user := user
+
+		pool.Submit(func() {
+			report.Add(idx, user, batchUserRetry(user, func() error {
+				return mbcPerformAction(ds, user, action)
+			}))
+		})
+	}
+
+	return nil
+}
+
+func init() {
+	batchManageCmd.AddCommand(batchMngBCodesCmd)
+
+	batchMngBCodesCmd.Flags().StringVarP(&inputFile, flgnm.FLG_INPUTFILE, "i", "", "filepath to user data text file")
+	batchMngBCodesCmd.Flags().StringVarP(&delFormat, flgnm.FLG_FORMAT, "f", "text", "user data file format (text or gsheet)")
+	batchMngBCodesCmd.Flags().StringVarP(&sheetRange, flgnm.FLG_SHEETRANGE, "s", "", "user data gsheet range")
+}
@@ -26,7 +26,6 @@ import (
 	"errors"
 	"fmt"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -85,6 +84,7 @@ func doBatchMngCrOSDev(cmd *cobra.Command, args []string) error {
 	defer lg.Debug("finished doBatchMngCrOSDev()")
 
 	var (
+		input       *btch.Input
 		managedDevs []cdevs.ManagedDevice
 		objs        []interface{}
 	)
@@ -130,12 +130,12 @@ func doBatchMngCrOSDev(cmd *cobra.Command, args []string) error {
 
 	switch {
 	case lwrFmt == "csv":
-		objs, err = btch.ProcessCSVFile(callParams, inputFlgVal, cdevs.CrOSDevAttrMap)
+		objs, input, err = btch.ProcessCSVFile(callParams, inputFlgVal, cdevs.CrOSDevAttrMap)
 		if err != nil {
 			return err
 		}
 	case lwrFmt == "json":
-		objs, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, cdevs.CrOSDevAttrMap)
+		objs, input, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, cdevs.CrOSDevAttrMap)
 		if err != nil {
 			return err
 		}
@@ -145,7 +145,7 @@ func doBatchMngCrOSDev(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		objs, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, cdevs.CrOSDevAttrMap)
+		objs, input, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, cdevs.CrOSDevAttrMap)
 		if err != nil {
 			return err
 		}
@@ -159,7 +159,22 @@ func doBatchMngCrOSDev(cmd *cobra.Command, args []string) error {
 		managedDevs = append(managedDevs, cdevObj.(cdevs.ManagedDevice))
 	}
 
-	err = bmngcProcessObjects(ds, managedDevs)
+	pool, err := newBatchPool(cmd, btch.DIRECTORYQPS)
+	if err != nil {
+		return err
+	}
+
+	report, err := newBatchReport(cmd, input)
+	if err != nil {
+		return err
+	}
+
+	err = bmngcProcessObjects(ds, pool, report, managedDevs)
+	if err != nil {
+		return err
+	}
+
+	err = writeBatchReport(report)
 	if err != nil {
 		return err
 	}
@@ -167,14 +182,12 @@ func doBatchMngCrOSDev(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func bmngcPerformAction(deviceID string, action string, wg *sync.WaitGroup, cdac *admin.ChromeosdevicesActionCall) {
+func bmngcPerformAction(deviceID string, action string, cdac *admin.ChromeosdevicesActionCall) error {
 	lg.Debugw("starting bmngcPerformAction()",
 		"action", action,
 		"deviceID", deviceID)
 	defer lg.Debug("finished bmngcPerformAction()")
 
-	defer wg.Done()
-
 	b := backoff.NewExponentialBackOff()
 	b.MaxElapsedTime = 32 * time.Second
 
@@ -187,22 +200,23 @@ func bmngcPerformAction(deviceID string, action string, wg *sync.WaitGroup, cdac
 			return err
 		}
 		if !cmn.IsErrRetryable(err) {
-			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHCHROMEOSDEVICE, err.Error(), deviceID))
+			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHCHROMEOSDEVICE, err, deviceID))
 		}
 		// Log the retries
 		lg.Warnw(err.Error(),
 			"retrying", b.GetElapsedTime().String(),
 			"ChromeOS device", deviceID)
-		return fmt.Errorf(gmess.ERR_BATCHCHROMEOSDEVICE, err.Error(), deviceID)
+		return fmt.Errorf(gmess.ERR_BATCHCHROMEOSDEVICE, err, deviceID)
 	}, b)
 	if err != nil {
 		// Log final error
 		lg.Error(err)
 		fmt.Println(cmn.GminMessage(err.Error()))
 	}
+	return err
 }
 
-func bmngcProcessObjects(ds *admin.Service, managedDevs []cdevs.ManagedDevice) error {
+func bmngcProcessObjects(ds *admin.Service, pool *btch.Pool, report *btch.Report, managedDevs []cdevs.ManagedDevice) error {
 	lg.Debug("starting bmngcProcessObjects()")
 	defer lg.Debug("finished bmngcProcessObjects()")
 
@@ -211,9 +225,11 @@ func bmngcProcessObjects(ds *admin.Service, managedDevs []cdevs.ManagedDevice) e
 		return err
 	}
 
-	wg := new(sync.WaitGroup)
+	defer pool.Wait()
 
-	for _, md := range managedDevs {
+	for idx, md := range managedDevs {
+		idx := idx
+		md := md
 		devAction := admin.ChromeOsDeviceAction{}
 
 		devAction.Action = md.Action
@@ -221,13 +237,11 @@ func bmngcProcessObjects(ds *admin.Service, managedDevs []cdevs.ManagedDevice) e
 
 		cdac := ds.Chromeosdevices.Action(customerID, md.DeviceId, &devAction)
 
-		wg.Add(1)
-
-		go bmngcPerformAction(md.DeviceId, md.Action, wg, cdac)
+		pool.Submit(func() {
+			report.Add(idx, md.DeviceId, bmngcPerformAction(md.DeviceId, md.Action, cdac))
+		})
 	}
 
-	wg.Wait()
-
 	return nil
 }
 
@@ -26,7 +26,6 @@ import (
 	"errors"
 	"fmt"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -99,6 +98,7 @@ func doBatchMngGrpSettings(cmd *cobra.Command, args []string) error {
 
 	var (
 		grpParams []grpset.GroupParams
+		input     *btch.Input
 		objs      []interface{}
 	)
 
@@ -143,12 +143,12 @@ func doBatchMngGrpSettings(cmd *cobra.Command, args []string) error {
 
 	switch {
 	case lwrFmt == "csv":
-		objs, err = btch.ProcessCSVFile(callParams, inputFlgVal, grpset.GroupSettingsAttrMap)
+		objs, input, err = btch.ProcessCSVFile(callParams, inputFlgVal, grpset.GroupSettingsAttrMap)
 		if err != nil {
 			return err
 		}
 	case lwrFmt == "json":
-		objs, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, grpset.GroupSettingsAttrMap)
+		objs, input, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, grpset.GroupSettingsAttrMap)
 		if err != nil {
 			return err
 		}
@@ -159,7 +159,7 @@ func doBatchMngGrpSettings(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		objs, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, grpset.GroupSettingsAttrMap)
+		objs, input, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, grpset.GroupSettingsAttrMap)
 		if err != nil {
 			return err
 		}
@@ -173,7 +173,22 @@ func doBatchMngGrpSettings(cmd *cobra.Command, args []string) error {
 		grpParams = append(grpParams, gpObj.(grpset.GroupParams))
 	}
 
-	err = bmnggProcessObjects(gs, grpParams)
+	pool, err := newBatchPool(cmd, btch.GRPSETTINGSQPS)
+	if err != nil {
+		return err
+	}
+
+	report, err := newBatchReport(cmd, input)
+	if err != nil {
+		return err
+	}
+
+	err = bmnggProcessObjects(gs, pool, report, grpParams)
+	if err != nil {
+		return err
+	}
+
+	err = writeBatchReport(report)
 	if err != nil {
 		return err
 	}
@@ -181,13 +196,11 @@ func doBatchMngGrpSettings(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func bmnggPerformUpdate(grpSetting *gset.Groups, groupKey string, wg *sync.WaitGroup, gsuc *gset.GroupsUpdateCall) {
+func bmnggPerformUpdate(grpSetting *gset.Groups, groupKey string, gsuc *gset.GroupsUpdateCall) error {
 	lg.Debugw("starting bmnggPerformUpdate()",
 		"groupKey", groupKey)
 	defer lg.Debug("finished bmnggPerformUpdate()")
 
-	defer wg.Done()
-
 	b := backoff.NewExponentialBackOff()
 	b.MaxElapsedTime = 32 * time.Second
 
@@ -200,38 +213,39 @@ func bmnggPerformUpdate(grpSetting *gset.Groups, groupKey string, wg *sync.WaitG
 			return err
 		}
 		if !cmn.IsErrRetryable(err) {
-			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHGROUPSETTINGS, err.Error(), groupKey))
+			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHGROUPSETTINGS, err, groupKey))
 		}
 		// Log the retries
 		lg.Warnw(err.Error(),
 			"retrying", b.GetElapsedTime().String(),
 			"group", groupKey)
-		return fmt.Errorf(gmess.ERR_BATCHGROUPSETTINGS, err.Error(), groupKey)
+		return fmt.Errorf(gmess.ERR_BATCHGROUPSETTINGS, err, groupKey)
 	}, b)
 	if err != nil {
 		// Log final error
 		lg.Error(err)
 		fmt.Println(cmn.GminMessage(err.Error()))
 	}
+	return err
 }
 
-func bmnggProcessObjects(gss *gset.Service, grpParams []grpset.GroupParams) error {
+func bmnggProcessObjects(gss *gset.Service, pool *btch.Pool, report *btch.Report, grpParams []grpset.GroupParams) error {
 	lg.Debugw("starting bmnggProcessObjects()",
 		"grpParams", grpParams)
 	defer lg.Debug("finished bmnggProcessObjects()")
 
-	wg := new(sync.WaitGroup)
+	defer pool.Wait()
 
-	for _, gp := range grpParams {
+	for idx, gp := range grpParams {
+		idx := idx
+		gp := gp
 		gsuc := gss.Groups.Update(gp.GroupKey, gp.Settings)
 
-		wg.Add(1)
-
-		go bmnggPerformUpdate(gp.Settings, gp.GroupKey, wg, gsuc)
+		pool.Submit(func() {
+			report.Add(idx, gp.GroupKey, bmnggPerformUpdate(gp.Settings, gp.GroupKey, gsuc))
+		})
 	}
 
-	wg.Wait()
-
 	return nil
 }
 
@@ -26,7 +26,6 @@ import (
 	"errors"
 	"fmt"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -81,6 +80,7 @@ func doBatchMngMobDev(cmd *cobra.Command, args []string) error {
 	defer lg.Debug("finished doBatchMngMobDev()")
 
 	var (
+		input       *btch.Input
 		managedDevs []mdevs.ManagedDevice
 		objs        []interface{}
 	)
@@ -126,12 +126,12 @@ func doBatchMngMobDev(cmd *cobra.Command, args []string) error {
 
 	switch {
 	case lwrFmt == "csv":
-		objs, err = btch.ProcessCSVFile(callParams, inputFlgVal, mdevs.MobDevAttrMap)
+		objs, input, err = btch.ProcessCSVFile(callParams, inputFlgVal, mdevs.MobDevAttrMap)
 		if err != nil {
 			return err
 		}
 	case lwrFmt == "json":
-		objs, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, mdevs.MobDevAttrMap)
+		objs, input, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, mdevs.MobDevAttrMap)
 		if err != nil {
 			return err
 		}
@@ -142,7 +142,7 @@ func doBatchMngMobDev(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		objs, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, mdevs.MobDevAttrMap)
+		objs, input, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, mdevs.MobDevAttrMap)
 		if err != nil {
 			return err
 		}
@@ -156,7 +156,22 @@ func doBatchMngMobDev(cmd *cobra.Command, args []string) error {
 		managedDevs = append(managedDevs, mdevObj.(mdevs.ManagedDevice))
 	}
 
-	err = bmngmProcessObjects(ds, managedDevs)
+	pool, err := newBatchPool(cmd, btch.DIRECTORYQPS)
+	if err != nil {
+		return err
+	}
+
+	report, err := newBatchReport(cmd, input)
+	if err != nil {
+		return err
+	}
+
+	err = bmngmProcessObjects(ds, pool, report, managedDevs)
+	if err != nil {
+		return err
+	}
+
+	err = writeBatchReport(report)
 	if err != nil {
 		return err
 	}
@@ -164,14 +179,12 @@ func doBatchMngMobDev(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func bmngmPerformAction(resourceID string, action string, wg *sync.WaitGroup, mdac *admin.MobiledevicesActionCall) {
+func bmngmPerformAction(resourceID string, action string, mdac *admin.MobiledevicesActionCall) error {
 	lg.Debugw("starting bmngmPerformAction()",
 		"action", action,
 		"resourceID", resourceID)
 	defer lg.Debug("finished bmngmPerformAction()")
 
-	defer wg.Done()
-
 	b := backoff.NewExponentialBackOff()
 	b.MaxElapsedTime = 32 * time.Second
 
@@ -184,22 +197,23 @@ func bmngmPerformAction(resourceID string, action string, wg *sync.WaitGroup, md
 			return err
 		}
 		if !cmn.IsErrRetryable(err) {
-			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHMOBILEDEVICE, err.Error(), resourceID))
+			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHMOBILEDEVICE, err, resourceID))
 		}
 		// Log the retries
 		lg.Warnw(err.Error(),
 			"retrying", b.GetElapsedTime().String(),
 			"mobile device", resourceID)
-		return fmt.Errorf(gmess.ERR_BATCHMOBILEDEVICE, err.Error(), resourceID)
+		return fmt.Errorf(gmess.ERR_BATCHMOBILEDEVICE, err, resourceID)
 	}, b)
 	if err != nil {
 		// Log final error
 		lg.Error(err)
 		fmt.Println(cmn.GminMessage(err.Error()))
 	}
+	return err
 }
 
-func bmngmProcessObjects(ds *admin.Service, managedDevs []mdevs.ManagedDevice) error {
+func bmngmProcessObjects(ds *admin.Service, pool *btch.Pool, report *btch.Report, managedDevs []mdevs.ManagedDevice) error {
 	lg.Debug("starting bmngmProcessObjects()")
 	defer lg.Debug("finished bmngmProcessObjects()")
 
@@ -209,22 +223,22 @@ func bmngmProcessObjects(ds *admin.Service, managedDevs []mdevs.ManagedDevice) e
 		return err
 	}
 
-	wg := new(sync.WaitGroup)
+	defer pool.Wait()
 
-	for _, md := range managedDevs {
+	for idx, md := range managedDevs {
+		idx := idx
+		md := md
 		devAction := admin.MobileDeviceAction{}
 
 		devAction.Action = md.Action
 
 		mdac := ds.Mobiledevices.Action(customerID, md.ResourceId, &devAction)
 
-		wg.Add(1)
-
-		go bmngmPerformAction(md.ResourceId, md.Action, wg, mdac)
+		pool.Submit(func() {
+			report.Add(idx, md.ResourceId, bmngmPerformAction(md.ResourceId, md.Action, mdac))
+		})
 	}
 
-	wg.Wait()
-
 	return nil
 }
 
@@ -0,0 +1,235 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	btch "github.com/plusworx/gmin/utils/batch"
+	cmn "github.com/plusworx/gmin/utils/common"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	usrs "github.com/plusworx/gmin/utils/users"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var batchMngUserCmd = &cobra.Command{
+	Use:     "users <action> [-i input file path]",
+	Aliases: []string{"user", "usrs", "usr"},
+	Args:    cobra.ExactArgs(1),
+	Example: `gmin batch-manage users signout -i inputfile.txt
+gmin bmng usr signout -i 1odyAIp3jGspd3M4xeepxWD6aeQIUuHBgrZB2OHSu8MI -s 'Sheet1!A1:A25' -f gsheet
+gmin ls user -a primaryemail -q orgunitpath=/TestOU | jq '.users[] | .primaryEmail' -r | gmin bmng usr signout`,
+	Short: "Performs an action on a batch of users",
+	Long: `Performs an action on a batch of users where user details are provided in a text input file or from a pipe.
+			
+The input file or piped in data should provide the user email addresses, aliases or ids on separate lines like this:
+
+frank.castle@mycompany.com
+bruce.wayne@mycompany.com
+peter.parker@mycompany.com
+
+An input Google sheet must have a header row with the following column names being the only ones that are valid:
+
+userKey [required]
+
+The column name is case insensitive.
+
+Valid actions are:
+make-admin - makes the users super administrators
+revoke-admin - revokes the users' super administrator status
+signout - signs the users out of all web and device sessions and resets their sign-in cookies`,
+	RunE: doBatchMngUser,
+}
+
+func doBatchMngUser(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doBatchMngUser()",
+		"args", args)
+	defer lg.Debug("finished doBatchMngUser()")
+
+	action := strings.ToLower(args[0])
+	ok := cmn.SliceContainsStr(usrs.ValidActions, action)
+	if !ok {
+		err := fmt.Errorf(gmess.ERR_INVALIDACTIONTYPE, args[0])
+		lg.Error(err)
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, muActionScope(action))
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	users, input, err := batchUserKeys(cmd)
+	if err != nil {
+		return err
+	}
+
+	pool, err := newBatchPool(cmd, btch.DIRECTORYQPS)
+	if err != nil {
+		return err
+	}
+
+	report, err := newBatchReport(cmd, input)
+	if err != nil {
+		return err
+	}
+
+	err = bmnguProcessObjects(ds, pool, report, users, action)
+	if err != nil {
+		return err
+	}
+
+	err = writeBatchReport(report)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// batchUserKeys reads user email addresses, aliases or ids from a text file, pipe or Google sheet
+func batchUserKeys(cmd *cobra.Command) ([]string, *btch.Input, error) {
+	lg.Debug("starting batchUserKeys()")
+	defer lg.Debug("finished batchUserKeys()")
+
+	var (
+		input *btch.Input
+		users []string
+	)
+
+	inputFlgVal, err := cmd.Flags().GetString(flgnm.FLG_INPUTFILE)
+	if err != nil {
+		lg.Error(err)
+		return nil, nil, err
+	}
+
+	scanner, err := cmn.InputFromStdIn(inputFlgVal)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if inputFlgVal == "" && scanner == nil {
+		err := errors.New(gmess.ERR_NOINPUTFILE)
+		lg.Error(err)
+		return nil, nil, err
+	}
+
+	formatFlgVal, err := cmd.Flags().GetString(flgnm.FLG_FORMAT)
+	if err != nil {
+		lg.Error(err)
+		return nil, nil, err
+	}
+	lwrFmt := strings.ToLower(formatFlgVal)
+
+	switch lwrFmt {
+	case "text":
+		users, input, err = btch.DeleteProcessTextFile(inputFlgVal, scanner)
+		if err != nil {
+			return nil, nil, err
+		}
+	case "gsheet":
+		rangeFlgVal, err := cmd.Flags().GetString(flgnm.FLG_SHEETRANGE)
+		if err != nil {
+			lg.Error(err)
+			return nil, nil, err
+		}
+
+		users, input, err = btch.DeleteProcessGSheet(inputFlgVal, rangeFlgVal, usrs.UserAttrMap, usrs.KEYNAME)
+		if err != nil {
+			return nil, nil, err
+		}
+	default:
+		err = fmt.Errorf(gmess.ERR_INVALIDFILEFORMAT, formatFlgVal)
+		lg.Error(err)
+		return nil, nil, err
+	}
+
+	return users, input, nil
+}
+
+// batchUserRetry calls fn with exponential backoff, wrapping any error with the user key
+func batchUserRetry(user string, fn func() error) error {
+	lg.Debugw("starting batchUserRetry()",
+		"user", user)
+	defer lg.Debug("finished batchUserRetry()")
+
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = 32 * time.Second
+
+	err := backoff.Retry(func() error {
+		err := fn()
+		if err == nil {
+			return err
+		}
+		if !cmn.IsErrRetryable(err) {
+			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHUSER, err, user))
+		}
+		// Log the retries
+		lg.Warnw(err.Error(),
+			"retrying", b.GetElapsedTime().String(),
+			"user", user)
+		return fmt.Errorf(gmess.ERR_BATCHUSER, err, user)
+	}, b)
+	if err != nil {
+		// Log final error
+		lg.Error(err)
+		fmt.Println(cmn.GminMessage(err.Error()))
+	}
+	return err
+}
+
+func bmnguProcessObjects(ds *admin.Service, pool *btch.Pool, report *btch.Report, users []string, action string) error {
+	lg.Debug("starting bmnguProcessObjects()")
+	defer lg.Debug("finished bmnguProcessObjects()")
+
+	defer pool.Wait()
+
+	for idx, user := range users {
+		idx := idx
+		user := user
+
+		pool.Submit(func() {
+			report.Add(idx, user, batchUserRetry(user, func() error {
+				return muPerformAction(ds, user, action)
+			}))
+		})
+	}
+
+	return nil
+}
+
+func init() {
+	batchManageCmd.AddCommand(batchMngUserCmd)
+
+	batchMngUserCmd.Flags().StringVarP(&inputFile, flgnm.FLG_INPUTFILE, "i", "", "filepath to user data text file")
+	batchMngUserCmd.Flags().StringVarP(&delFormat, flgnm.FLG_FORMAT, "f", "text", "user data file format (text or gsheet)")
+	batchMngUserCmd.Flags().StringVarP(&sheetRange, flgnm.FLG_SHEETRANGE, "s", "", "user data gsheet range")
+}
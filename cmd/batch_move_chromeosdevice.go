@@ -26,7 +26,6 @@ import (
 	"errors"
 	"fmt"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -71,6 +70,7 @@ func doBatchMoveCrOSDev(cmd *cobra.Command, args []string) error {
 	defer lg.Debug("finished doBatchMoveCrOSDev()")
 
 	var (
+		input     *btch.Input
 		movedDevs []cdevs.MovedDevice
 		objs      []interface{}
 	)
@@ -116,12 +116,12 @@ func doBatchMoveCrOSDev(cmd *cobra.Command, args []string) error {
 
 	switch {
 	case lwrFmt == "csv":
-		objs, err = btch.ProcessCSVFile(callParams, inputFlgVal, cdevs.CrOSDevAttrMap)
+		objs, input, err = btch.ProcessCSVFile(callParams, inputFlgVal, cdevs.CrOSDevAttrMap)
 		if err != nil {
 			return err
 		}
 	case lwrFmt == "json":
-		objs, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, cdevs.CrOSDevAttrMap)
+		objs, input, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, cdevs.CrOSDevAttrMap)
 		if err != nil {
 			return err
 		}
@@ -132,7 +132,7 @@ func doBatchMoveCrOSDev(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		objs, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, cdevs.CrOSDevAttrMap)
+		objs, input, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, cdevs.CrOSDevAttrMap)
 		if err != nil {
 			return err
 		}
@@ -144,7 +144,22 @@ func doBatchMoveCrOSDev(cmd *cobra.Command, args []string) error {
 		movedDevs = append(movedDevs, cdevObj.(cdevs.MovedDevice))
 	}
 
-	err = bmvcProcessObjects(ds, movedDevs)
+	pool, err := newBatchPool(cmd, btch.DIRECTORYQPS)
+	if err != nil {
+		return err
+	}
+
+	report, err := newBatchReport(cmd, input)
+	if err != nil {
+		return err
+	}
+
+	err = bmvcProcessObjects(ds, pool, report, movedDevs)
+	if err != nil {
+		return err
+	}
+
+	err = writeBatchReport(report)
 	if err != nil {
 		return err
 	}
@@ -152,14 +167,12 @@ func doBatchMoveCrOSDev(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func bmvcPerformMove(deviceID string, ouPath string, wg *sync.WaitGroup, cdmc *admin.ChromeosdevicesMoveDevicesToOuCall) {
+func bmvcPerformMove(deviceID string, ouPath string, cdmc *admin.ChromeosdevicesMoveDevicesToOuCall) error {
 	lg.Debugw("starting bmvcPerformMove()",
 		"deviceID", deviceID,
 		"ouPath", ouPath)
 	defer lg.Debug("finished bmvcPerformMove()")
 
-	defer wg.Done()
-
 	b := backoff.NewExponentialBackOff()
 	b.MaxElapsedTime = 32 * time.Second
 
@@ -172,23 +185,24 @@ func bmvcPerformMove(deviceID string, ouPath string, wg *sync.WaitGroup, cdmc *a
 			return err
 		}
 		if !cmn.IsErrRetryable(err) {
-			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHCHROMEOSDEVICE, err.Error(), deviceID))
+			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHCHROMEOSDEVICE, err, deviceID))
 		}
 		// Log the retries
 		lg.Warnw(err.Error(),
 			"retrying", b.GetElapsedTime().String(),
 			"ChromeOS device", deviceID,
 			"orgunit", ouPath)
-		return fmt.Errorf(gmess.ERR_BATCHCHROMEOSDEVICE, err.Error(), deviceID)
+		return fmt.Errorf(gmess.ERR_BATCHCHROMEOSDEVICE, err, deviceID)
 	}, b)
 	if err != nil {
 		// Log final error
 		lg.Error(err)
 		fmt.Println(cmn.GminMessage(err.Error()))
 	}
+	return err
 }
 
-func bmvcProcessObjects(ds *admin.Service, movedDevs []cdevs.MovedDevice) error {
+func bmvcProcessObjects(ds *admin.Service, pool *btch.Pool, report *btch.Report, movedDevs []cdevs.MovedDevice) error {
 	lg.Debug("starting bmvcProcessObjects()")
 	defer lg.Debug("finished bmvcProcessObjects()")
 
@@ -198,9 +212,11 @@ func bmvcProcessObjects(ds *admin.Service, movedDevs []cdevs.MovedDevice) error
 		return err
 	}
 
-	wg := new(sync.WaitGroup)
+	defer pool.Wait()
 
-	for _, md := range movedDevs {
+	for idx, md := range movedDevs {
+		idx := idx
+		md := md
 		move := admin.ChromeOsMoveDevicesToOu{}
 		deviceIDs := []string{}
 
@@ -209,13 +225,11 @@ func bmvcProcessObjects(ds *admin.Service, movedDevs []cdevs.MovedDevice) error
 
 		cdmc := ds.Chromeosdevices.MoveDevicesToOu(customerID, md.OrgUnitPath, &move)
 
-		wg.Add(1)
-
-		go bmvcPerformMove(md.DeviceId, md.OrgUnitPath, wg, cdmc)
+		pool.Submit(func() {
+			report.Add(idx, md.DeviceId, bmvcPerformMove(md.DeviceId, md.OrgUnitPath, cdmc))
+		})
 	}
 
-	wg.Wait()
-
 	return nil
 }
 
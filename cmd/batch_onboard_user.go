@@ -0,0 +1,285 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	btch "github.com/plusworx/gmin/utils/batch"
+	cmn "github.com/plusworx/gmin/utils/common"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	onb "github.com/plusworx/gmin/utils/onboard"
+	pwds "github.com/plusworx/gmin/utils/passwords"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var batchOnbUserCmd = &cobra.Command{
+	Use:     "users -i <input file path or google sheet id> --template-file <template file path>",
+	Aliases: []string{"user", "usrs", "usr"},
+	Example: `gmin batch-onboard users -i inputfile.json --template-file starters.yaml
+gmin bonb users -i inputfile.csv -f csv -t sales --template-file starters.yaml
+gmin bonb user -i 1odyAIp3jGspd3M4xeepxWD6aeQIUuHBgrZB2OHSu8MI -s 'Sheet1!A1:C25' -f gsheet -t sales --template-file starters.yaml`,
+	Short: "Onboards a batch of users using templates",
+	Long: `Onboards a batch of users using templates where user details are provided in a Google Sheet, CSV/JSON input file or piped JSON.
+
+Each user is onboarded in the same way as the onboard user command. See gmin onboard user -h for the
+template file format.
+			
+The contents of the JSON file or piped input should look something like this:
+
+{"firstName":"Peter","lastName":"Parker","template":"sales"}
+{"firstName":"Mary","lastName":"Jane","primaryEmail":"mj@mycompany.com","template":"marketing"}
+
+CSV and Google sheets must have a header row with the following column names being the only ones that are valid:
+
+firstName [required]
+lastName [required]
+primaryEmail [required if the template has no primaryEmail pattern]
+template [required if --template is not given]
+
+The column names are case insensitive and can be in any order. A template given in the input overrides
+the --template flag.
+
+The email addresses and passwords of all the users that are created are written to one CSV handoff file
+that only the file owner can read. The default handoff file is gmin_credentials_<timestamp>.csv in the
+current directory. No handoff file is written by a dry run.`,
+	RunE: doBatchOnbUser,
+}
+
+func doBatchOnbUser(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doBatchOnbUser()",
+		"args", args)
+	defer lg.Debug("finished doBatchOnbUser()")
+
+	var (
+		input    *btch.Input
+		objs     []interface{}
+		starters []*onb.Starter
+	)
+
+	tmpls, err := onbuTemplates(cmd)
+	if err != nil {
+		return err
+	}
+
+	flgTemplateVal, err := cmd.Flags().GetString(flgnm.FLG_TEMPLATE)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	flgPwdLenVal, err := cmd.Flags().GetInt(flgnm.FLG_PWDLENGTH)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	// Check password length before any users are created
+	_, err = onb.GeneratePassword(flgPwdLenVal)
+	if err != nil {
+		return err
+	}
+
+	handoffPath, err := onbuHandoffPath(cmd)
+	if err != nil {
+		return err
+	}
+
+	hasher, err := passwordHasher(cmd)
+	if err != nil {
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, onbuScopes...)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	inputFlgVal, err := cmd.Flags().GetString(flgnm.FLG_INPUTFILE)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	scanner, err := cmn.InputFromStdIn(inputFlgVal)
+	if err != nil {
+		return err
+	}
+
+	if inputFlgVal == "" && scanner == nil {
+		err := errors.New(gmess.ERR_NOINPUTFILE)
+		lg.Error(err)
+		return err
+	}
+
+	formatFlgVal, err := cmd.Flags().GetString(flgnm.FLG_FORMAT)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	lwrFmt := strings.ToLower(formatFlgVal)
+
+	ok := cmn.SliceContainsStr(cmn.ValidFileFormats, lwrFmt)
+	if !ok {
+		err = fmt.Errorf(gmess.ERR_INVALIDFILEFORMAT, formatFlgVal)
+		lg.Error(err)
+		return err
+	}
+
+	callParams := btch.CallParams{CallType: cmn.CALLTYPECREATE, ObjectType: cmn.OBJTYPESTARTER}
+
+	switch {
+	case lwrFmt == "csv":
+		objs, input, err = btch.ProcessCSVFile(callParams, inputFlgVal, onb.StarterAttrMap)
+		if err != nil {
+			return err
+		}
+	case lwrFmt == "json":
+		objs, input, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, onb.StarterAttrMap)
+		if err != nil {
+			return err
+		}
+	case lwrFmt == "gsheet":
+		rangeFlgVal, err := cmd.Flags().GetString(flgnm.FLG_SHEETRANGE)
+		if err != nil {
+			return err
+		}
+
+		objs, input, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, onb.StarterAttrMap)
+		if err != nil {
+			return err
+		}
+	default:
+		err = fmt.Errorf(gmess.ERR_INVALIDFILEFORMAT, formatFlgVal)
+		lg.Error(err)
+		return err
+	}
+
+	for _, starterObj := range objs {
+		starters = append(starters, starterObj.(*onb.Starter))
+	}
+
+	pool, err := newBatchPool(cmd, btch.DIRECTORYQPS)
+	if err != nil {
+		return err
+	}
+
+	report, err := newBatchReport(cmd, input)
+	if err != nil {
+		return err
+	}
+
+	creds := bonbuProcessObjects(ds, pool, report, tmpls, starters, flgTemplateVal, flgPwdLenVal, hasher)
+
+	err = onbuWriteHandoff(handoffPath, creds)
+	if err != nil {
+		return err
+	}
+
+	err = writeBatchReport(report)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func init() {
+	batchOnboardCmd.AddCommand(batchOnbUserCmd)
+
+	batchOnbUserCmd.Flags().StringVarP(&inputFile, flgnm.FLG_INPUTFILE, "i", "", "filepath to user data file or sheet id")
+	batchOnbUserCmd.Flags().StringVarP(&format, flgnm.FLG_FORMAT, "f", "json", "user data file format")
+	batchOnbUserCmd.Flags().StringVar(&handoffFile, flgnm.FLG_HANDOFFFILE, "", "filepath to credentials handoff file")
+	batchOnbUserCmd.Flags().StringVar(&hasher, flgnm.FLG_HASHER, "", "password hasher (crypt, md5 or sha-1)")
+	batchOnbUserCmd.Flags().IntVar(&pwdLength, flgnm.FLG_PWDLENGTH, onb.DEFAULTPASSWORDLENGTH, "length of generated passwords")
+	batchOnbUserCmd.Flags().StringVarP(&sheetRange, flgnm.FLG_SHEETRANGE, "s", "", "user data gsheet range")
+	batchOnbUserCmd.Flags().StringVarP(&templateName, flgnm.FLG_TEMPLATE, "t", "", "name of onboarding template used when input has no template")
+	batchOnbUserCmd.Flags().StringVar(&templateFile, flgnm.FLG_TEMPLATEFILE, "", "filepath to onboarding template file")
+	batchOnbUserCmd.MarkFlagRequired(flgnm.FLG_TEMPLATEFILE)
+}
+
+// bonbuProcessObjects onboards starters and returns the credentials of the users that were created
+func bonbuProcessObjects(ds *admin.Service, pool *btch.Pool, report *btch.Report, tmpls *onb.Templates, starters []*onb.Starter, tmplName string, pwdLength int, hasher pwds.Hasher) []onb.Credential {
+	lg.Debug("starting bonbuProcessObjects()")
+	defer lg.Debug("finished bonbuProcessObjects()")
+
+	var (
+		creds []onb.Credential
+		mu    sync.Mutex
+	)
+
+	for idx, starter := range starters {
+		idx := idx
+		starter := starter
+
+		key := starter.PrimaryEmail
+		if key == "" {
+			key = starter.FirstName + " " + starter.LastName
+		}
+
+		name := starter.Template
+		if name == "" {
+			name = tmplName
+		}
+		if name == "" {
+			err := errors.New(gmess.ERR_NOTEMPLATE)
+			lg.Error(err)
+			report.Add(idx, key, err)
+			continue
+		}
+
+		tmpl, err := tmpls.Get(name)
+		if err != nil {
+			report.Add(idx, key, err)
+			continue
+		}
+
+		user, err := tmpl.NewUser(starter)
+		if err != nil {
+			report.Add(idx, key, err)
+			continue
+		}
+		key = user.PrimaryEmail
+
+		pool.Submit(func() {
+			cred, err := onbuOnboard(ds, tmpl, starter, user, pwdLength, hasher, func(fn func() error) error {
+				return batchUserRetry(key, fn)
+			})
+			if cred != nil {
+				mu.Lock()
+				creds = append(creds, *cred)
+				mu.Unlock()
+			}
+			report.Add(idx, key, err)
+		})
+	}
+
+	pool.Wait()
+	return creds
+}
@@ -0,0 +1,141 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+func TestBatchCmdsFakeServer(t *testing.T) {
+	fs := newFakeServer(t)
+	fs.AddGroup(&admin.Group{Email: "cartoons@disney.com", Name: "Cartoons"})
+	fs.AddMember("cartoons@disney.com", &admin.Member{Email: "goofy@disney.com"})
+	fs.AddMember("cartoons@disney.com", &admin.Member{Email: "pluto@disney.com"})
+	fs.AddSheet("sheet1", "Sheet1!A1:D3", [][]interface{}{
+		{"primaryEmail", "firstName", "lastName", "password"},
+		{"minnie.mouse@disney.com", "Minnie", "Mouse", "VeryStrongPassword"},
+		{"daisy.duck@disney.com", "Daisy", "Duck", "VeryStrongPassword"},
+	})
+
+	dir := t.TempDir()
+	usersCSV := filepath.Join(dir, "users.csv")
+	ioutil.WriteFile(usersCSV, []byte("primaryEmail,firstName,lastName,password\nmickey.mouse@disney.com,Mickey,Mouse,VeryStrongPassword\ndonald.duck@disney.com,Donald,Duck,VeryStrongPassword\n"), 0644)
+	delUsers := filepath.Join(dir, "users.txt")
+	ioutil.WriteFile(delUsers, []byte("donald.duck@disney.com\n"), 0644)
+	failUsers := filepath.Join(dir, "failusers.txt")
+	ioutil.WriteFile(failUsers, []byte("mickey.mouse@disney.com\nhuey.duck@disney.com\n"), 0644)
+	membersCSV := filepath.Join(dir, "members.csv")
+	ioutil.WriteFile(membersCSV, []byte("memberKey,role\ngoofy@disney.com,MANAGER\npluto@disney.com,OWNER\n"), 0644)
+
+	cases := []struct {
+		args        []string
+		expectedErr string
+	}{
+		{
+			args: []string{"batch-create", "users", "-i", usersCSV, "-f", "csv", "--results-dir", t.TempDir()},
+		},
+		{
+			args: []string{"batch-create", "users", "-i", "sheet1", "-s", "Sheet1!A1:D3", "-f", "gsheet", "--results-dir", t.TempDir()},
+		},
+		{
+			args: []string{"batch-delete", "users", "-i", delUsers, "--workers", "2", "--qps", "5", "--results-dir", t.TempDir()},
+		},
+		{
+			args:        []string{"batch-delete", "users", "-i", delUsers, "--workers", "0", "--results-dir", t.TempDir()},
+			expectedErr: "workers must be at least 1: 0",
+		},
+		{
+			args:        []string{"batch-delete", "users", "-i", delUsers, "--results-format", "xml"},
+			expectedErr: "invalid file format: xml",
+		},
+		{
+			args: []string{"batch-update", "group-members", "cartoons@disney.com", "-i", membersCSV, "-f", "csv", "--results-dir", t.TempDir()},
+		},
+	}
+
+	for _, c := range cases {
+		_, got := runGmin(t, c.args...)
+
+		gotErr := ""
+		if got != nil {
+			gotErr = got.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("Got error: %v - expected error: %v", gotErr, c.expectedErr)
+		}
+	}
+
+	for _, email := range []string{"mickey.mouse@disney.com", "minnie.mouse@disney.com", "daisy.duck@disney.com"} {
+		if fs.User(email) == nil {
+			t.Errorf("Got user: nil - expected user: %v", email)
+		}
+	}
+	if fs.User("donald.duck@disney.com") != nil {
+		t.Error("Got user: donald.duck@disney.com - expected user: nil")
+	}
+
+	resultsDir := t.TempDir()
+	_, err := runGmin(t, "batch-delete", "users", "-i", failUsers, "--results-dir", resultsDir, "--results-format", "csv")
+	if err == nil || err.Error() != "1 of 2 batch rows failed" {
+		t.Errorf("Got error: %v - expected error: 1 of 2 batch rows failed", err)
+	}
+
+	expFiles := []struct {
+		pattern  string
+		expected string
+	}{
+		{
+			pattern:  "gmin_results_*.csv",
+			expected: "row,key,status,errorCode,errorMessage\n1,mickey.mouse@disney.com,success,,\n2,huey.duck@disney.com,failed,404,Resource Not Found: huey.duck@disney.com\n",
+		},
+		{
+			pattern:  "gmin_failed_*.txt",
+			expected: "huey.duck@disney.com\n",
+		},
+	}
+
+	for _, ef := range expFiles {
+		paths, _ := filepath.Glob(filepath.Join(resultsDir, ef.pattern))
+		if len(paths) != 1 {
+			t.Errorf("Got %v files: %v - expected 1 file", ef.pattern, len(paths))
+			continue
+		}
+		got, _ := ioutil.ReadFile(paths[0])
+		if strings.TrimSpace(string(got)) != strings.TrimSpace(ef.expected) {
+			t.Errorf("Got file contents: %v - expected file contents: %v", string(got), ef.expected)
+		}
+	}
+
+	expRoles := map[string]string{"goofy@disney.com": "MANAGER", "pluto@disney.com": "OWNER"}
+	for email, role := range expRoles {
+		member := fs.Member("cartoons@disney.com", email)
+		if member.Role != role {
+			t.Errorf("Got member role: %v - expected member role: %v", member.Role, role)
+		}
+	}
+}
@@ -26,7 +26,6 @@ import (
 	"errors"
 	"fmt"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -72,6 +71,7 @@ func doBatchUndelUser(cmd *cobra.Command, args []string) error {
 	defer lg.Debug("finished doBatchUndelUser()")
 
 	var (
+		input      *btch.Input
 		objs       []interface{}
 		undelUsers []usrs.UndeleteUser
 	)
@@ -117,12 +117,12 @@ func doBatchUndelUser(cmd *cobra.Command, args []string) error {
 
 	switch {
 	case lwrFmt == "csv":
-		objs, err = btch.ProcessCSVFile(callParams, inputFlgVal, usrs.UserAttrMap)
+		objs, input, err = btch.ProcessCSVFile(callParams, inputFlgVal, usrs.UserAttrMap)
 		if err != nil {
 			return err
 		}
 	case lwrFmt == "json":
-		objs, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, usrs.UserAttrMap)
+		objs, input, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, usrs.UserAttrMap)
 		if err != nil {
 			return err
 		}
@@ -133,7 +133,7 @@ func doBatchUndelUser(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		objs, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, usrs.UserAttrMap)
+		objs, input, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, usrs.UserAttrMap)
 		if err != nil {
 			return err
 		}
@@ -147,7 +147,22 @@ func doBatchUndelUser(cmd *cobra.Command, args []string) error {
 		undelUsers = append(undelUsers, uuObj.(usrs.UndeleteUser))
 	}
 
-	err = bunduProcessObjects(ds, undelUsers)
+	pool, err := newBatchPool(cmd, btch.DIRECTORYQPS)
+	if err != nil {
+		return err
+	}
+
+	report, err := newBatchReport(cmd, input)
+	if err != nil {
+		return err
+	}
+
+	err = bunduProcessObjects(ds, pool, report, undelUsers)
+	if err != nil {
+		return err
+	}
+
+	err = writeBatchReport(report)
 	if err != nil {
 		return err
 	}
@@ -155,13 +170,15 @@ func doBatchUndelUser(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func bunduProcessObjects(ds *admin.Service, undelUsers []usrs.UndeleteUser) error {
+func bunduProcessObjects(ds *admin.Service, pool *btch.Pool, report *btch.Report, undelUsers []usrs.UndeleteUser) error {
 	lg.Debug("starting bunduProcessObjects()")
 	defer lg.Debug("finished bunduProcessObjects()")
 
-	wg := new(sync.WaitGroup)
+	defer pool.Wait()
 
-	for _, u := range undelUsers {
+	for idx, u := range undelUsers {
+		idx := idx
+		u := u
 		userUndelete := admin.UserUndelete{}
 
 		if u.OrgUnitPath == "" {
@@ -172,23 +189,19 @@ func bunduProcessObjects(ds *admin.Service, undelUsers []usrs.UndeleteUser) erro
 
 		uuc := ds.Users.Undelete(u.UserKey, &userUndelete)
 
-		wg.Add(1)
-
-		go bunduUndelete(u.UserKey, wg, uuc)
+		pool.Submit(func() {
+			report.Add(idx, u.UserKey, bunduUndelete(u.UserKey, uuc))
+		})
 	}
 
-	wg.Wait()
-
 	return nil
 }
 
-func bunduUndelete(userKey string, wg *sync.WaitGroup, uuc *admin.UsersUndeleteCall) {
+func bunduUndelete(userKey string, uuc *admin.UsersUndeleteCall) error {
 	lg.Debugw("starting bunduUndelete()",
 		"userKey", userKey)
 	defer lg.Debug("finished bunduUndelete()")
 
-	defer wg.Done()
-
 	b := backoff.NewExponentialBackOff()
 	b.MaxElapsedTime = 32 * time.Second
 
@@ -201,19 +214,20 @@ func bunduUndelete(userKey string, wg *sync.WaitGroup, uuc *admin.UsersUndeleteC
 			return err
 		}
 		if !cmn.IsErrRetryable(err) {
-			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHUSER, err.Error(), userKey))
+			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHUSER, err, userKey))
 		}
 		// Log the retries
 		lg.Warnw(err.Error(),
 			"retrying", b.GetElapsedTime().String(),
 			"user", userKey)
-		return fmt.Errorf(gmess.ERR_BATCHUSER, err.Error(), userKey)
+		return fmt.Errorf(gmess.ERR_BATCHUSER, err, userKey)
 	}, b)
 	if err != nil {
 		// Log final error
 		lg.Error(err)
 		fmt.Println(cmn.GminMessage(err.Error()))
 	}
+	return err
 }
 
 func init() {
@@ -26,7 +26,6 @@ import (
 	"errors"
 	"fmt"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -78,6 +77,7 @@ func doBatchUpdCrOSDev(cmd *cobra.Command, args []string) error {
 
 	var (
 		crosdevs []*admin.ChromeOsDevice
+		input    *btch.Input
 		objs     []interface{}
 	)
 
@@ -122,12 +122,12 @@ func doBatchUpdCrOSDev(cmd *cobra.Command, args []string) error {
 
 	switch {
 	case lwrFmt == "csv":
-		objs, err = btch.ProcessCSVFile(callParams, inputFlgVal, cdevs.CrOSDevAttrMap)
+		objs, input, err = btch.ProcessCSVFile(callParams, inputFlgVal, cdevs.CrOSDevAttrMap)
 		if err != nil {
 			return err
 		}
 	case lwrFmt == "json":
-		objs, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, cdevs.CrOSDevAttrMap)
+		objs, input, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, cdevs.CrOSDevAttrMap)
 		if err != nil {
 			return err
 		}
@@ -138,7 +138,7 @@ func doBatchUpdCrOSDev(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		objs, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, cdevs.CrOSDevAttrMap)
+		objs, input, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, cdevs.CrOSDevAttrMap)
 		if err != nil {
 			return err
 		}
@@ -152,7 +152,22 @@ func doBatchUpdCrOSDev(cmd *cobra.Command, args []string) error {
 		crosdevs = append(crosdevs, cdevObj.(*admin.ChromeOsDevice))
 	}
 
-	err = bucProcessObjects(ds, crosdevs)
+	pool, err := newBatchPool(cmd, btch.DIRECTORYQPS)
+	if err != nil {
+		return err
+	}
+
+	report, err := newBatchReport(cmd, input)
+	if err != nil {
+		return err
+	}
+
+	err = bucProcessObjects(ds, pool, report, crosdevs)
+	if err != nil {
+		return err
+	}
+
+	err = writeBatchReport(report)
 	if err != nil {
 		return err
 	}
@@ -160,36 +175,35 @@ func doBatchUpdCrOSDev(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func bucProcessObjects(ds *admin.Service, crosdevs []*admin.ChromeOsDevice) error {
+func bucProcessObjects(ds *admin.Service, pool *btch.Pool, report *btch.Report, crosdevs []*admin.ChromeOsDevice) error {
 	lg.Debug("starting bucProcessObjects()")
-	wg := new(sync.WaitGroup)
 	defer lg.Debug("finished bucProcessObjects()")
 
+	defer pool.Wait()
+
 	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
 	if err != nil {
 		lg.Error(err)
 		return err
 	}
 
-	for _, c := range crosdevs {
+	for idx, c := range crosdevs {
+		idx := idx
+		c := c
 		cduc := ds.Chromeosdevices.Update(customerID, c.DeviceId, c)
 
-		wg.Add(1)
-
-		go bucUpdate(c, wg, cduc)
+		pool.Submit(func() {
+			report.Add(idx, c.DeviceId, bucUpdate(c, cduc))
+		})
 	}
 
-	wg.Wait()
-
 	return nil
 }
 
-func bucUpdate(crosdev *admin.ChromeOsDevice, wg *sync.WaitGroup, cduc *admin.ChromeosdevicesUpdateCall) {
+func bucUpdate(crosdev *admin.ChromeOsDevice, cduc *admin.ChromeosdevicesUpdateCall) error {
 	lg.Debug("starting bucUpdate()")
 	defer lg.Debug("finished bucUpdate()")
 
-	defer wg.Done()
-
 	b := backoff.NewExponentialBackOff()
 	b.MaxElapsedTime = 32 * time.Second
 
@@ -202,19 +216,20 @@ func bucUpdate(crosdev *admin.ChromeOsDevice, wg *sync.WaitGroup, cduc *admin.Ch
 			return err
 		}
 		if !cmn.IsErrRetryable(err) {
-			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHCHROMEOSDEVICE, err.Error(), crosdev.DeviceId))
+			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHCHROMEOSDEVICE, err, crosdev.DeviceId))
 		}
 		// Log the retries
 		lg.Warnw(err.Error(),
 			"retrying", b.GetElapsedTime().String(),
 			"ChromeOS device", crosdev.DeviceId)
-		return fmt.Errorf(gmess.ERR_BATCHCHROMEOSDEVICE, err.Error(), crosdev.DeviceId)
+		return fmt.Errorf(gmess.ERR_BATCHCHROMEOSDEVICE, err, crosdev.DeviceId)
 	}, b)
 	if err != nil {
 		// Log final error
 		lg.Error(err)
 		fmt.Println(cmn.GminMessage(err.Error()))
 	}
+	return err
 }
 
 func init() {
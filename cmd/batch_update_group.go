@@ -26,7 +26,6 @@ import (
 	"errors"
 	"fmt"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -75,6 +74,7 @@ func doBatchUpdGrp(cmd *cobra.Command, args []string) error {
 
 	var (
 		grpParams []grps.GroupParams
+		input     *btch.Input
 		objs      []interface{}
 	)
 
@@ -119,12 +119,12 @@ func doBatchUpdGrp(cmd *cobra.Command, args []string) error {
 
 	switch {
 	case lwrFmt == "csv":
-		objs, err = btch.ProcessCSVFile(callParams, inputFlgVal, grps.GroupAttrMap)
+		objs, input, err = btch.ProcessCSVFile(callParams, inputFlgVal, grps.GroupAttrMap)
 		if err != nil {
 			return err
 		}
 	case lwrFmt == "json":
-		objs, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, grps.GroupAttrMap)
+		objs, input, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, grps.GroupAttrMap)
 		if err != nil {
 			return err
 		}
@@ -135,7 +135,7 @@ func doBatchUpdGrp(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		objs, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, grps.GroupAttrMap)
+		objs, input, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, grps.GroupAttrMap)
 		if err != nil {
 			return err
 		}
@@ -149,7 +149,22 @@ func doBatchUpdGrp(cmd *cobra.Command, args []string) error {
 		grpParams = append(grpParams, gpObj.(grps.GroupParams))
 	}
 
-	err = bugProcessObjects(ds, grpParams)
+	pool, err := newBatchPool(cmd, btch.DIRECTORYQPS)
+	if err != nil {
+		return err
+	}
+
+	report, err := newBatchReport(cmd, input)
+	if err != nil {
+		return err
+	}
+
+	err = bugProcessObjects(ds, pool, report, grpParams)
+	if err != nil {
+		return err
+	}
+
+	err = writeBatchReport(report)
 	if err != nil {
 		return err
 	}
@@ -157,33 +172,31 @@ func doBatchUpdGrp(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func bugProcessObjects(ds *admin.Service, grpParams []grps.GroupParams) error {
+func bugProcessObjects(ds *admin.Service, pool *btch.Pool, report *btch.Report, grpParams []grps.GroupParams) error {
 	lg.Debugw("starting bugProcessObjects()",
 		"grpParams", grpParams)
 	defer lg.Debug("finished bugProcessObjects()")
 
-	wg := new(sync.WaitGroup)
+	defer pool.Wait()
 
-	for _, gp := range grpParams {
+	for idx, gp := range grpParams {
+		idx := idx
+		gp := gp
 		guc := ds.Groups.Update(gp.GroupKey, gp.Group)
 
-		wg.Add(1)
-
-		go bugUpdate(gp.Group, wg, guc, gp.GroupKey)
+		pool.Submit(func() {
+			report.Add(idx, gp.GroupKey, bugUpdate(gp.Group, guc, gp.GroupKey))
+		})
 	}
 
-	wg.Wait()
-
 	return nil
 }
 
-func bugUpdate(group *admin.Group, wg *sync.WaitGroup, guc *admin.GroupsUpdateCall, groupKey string) {
+func bugUpdate(group *admin.Group, guc *admin.GroupsUpdateCall, groupKey string) error {
 	lg.Debugw("starting bugUpdate()",
 		"groupKey", groupKey)
 	defer lg.Debug("finished bugUpdate()")
 
-	defer wg.Done()
-
 	b := backoff.NewExponentialBackOff()
 	b.MaxElapsedTime = 32 * time.Second
 
@@ -196,19 +209,20 @@ func bugUpdate(group *admin.Group, wg *sync.WaitGroup, guc *admin.GroupsUpdateCa
 			return err
 		}
 		if !cmn.IsErrRetryable(err) {
-			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHGROUP, err.Error(), groupKey))
+			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHGROUP, err, groupKey))
 		}
 		// Log the retries
 		lg.Warnw(err.Error(),
 			"retrying", b.GetElapsedTime().String(),
 			"group", groupKey)
-		return fmt.Errorf(gmess.ERR_BATCHGROUP, err.Error(), groupKey)
+		return fmt.Errorf(gmess.ERR_BATCHGROUP, err, groupKey)
 	}, b)
 	if err != nil {
 		// Log final error
 		lg.Error(err)
 		fmt.Println(cmn.GminMessage(err.Error()))
 	}
+	return err
 }
 
 func init() {
@@ -26,7 +26,6 @@ import (
 	"errors"
 	"fmt"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -74,6 +73,7 @@ func doBatchUpdMember(cmd *cobra.Command, args []string) error {
 	defer lg.Debug("finished doBatchUpdMember()")
 
 	var (
+		input     *btch.Input
 		memParams []mems.MemberParams
 		objs      []interface{}
 	)
@@ -121,12 +121,12 @@ func doBatchUpdMember(cmd *cobra.Command, args []string) error {
 
 	switch {
 	case lwrFmt == "csv":
-		objs, err = btch.ProcessCSVFile(callParams, inputFlgVal, mems.MemberAttrMap)
+		objs, input, err = btch.ProcessCSVFile(callParams, inputFlgVal, mems.MemberAttrMap)
 		if err != nil {
 			return err
 		}
 	case lwrFmt == "json":
-		objs, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, mems.MemberAttrMap)
+		objs, input, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, mems.MemberAttrMap)
 		if err != nil {
 			return err
 		}
@@ -137,7 +137,7 @@ func doBatchUpdMember(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		objs, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, mems.MemberAttrMap)
+		objs, input, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, mems.MemberAttrMap)
 		if err != nil {
 			return err
 		}
@@ -151,7 +151,22 @@ func doBatchUpdMember(cmd *cobra.Command, args []string) error {
 		memParams = append(memParams, memObj.(mems.MemberParams))
 	}
 
-	err = bumProcessObjects(ds, groupKey, memParams)
+	pool, err := newBatchPool(cmd, btch.DIRECTORYQPS)
+	if err != nil {
+		return err
+	}
+
+	report, err := newBatchReport(cmd, input)
+	if err != nil {
+		return err
+	}
+
+	err = bumProcessObjects(ds, pool, report, groupKey, memParams)
+	if err != nil {
+		return err
+	}
+
+	err = writeBatchReport(report)
 	if err != nil {
 		return err
 	}
@@ -159,35 +174,33 @@ func doBatchUpdMember(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func bumProcessObjects(ds *admin.Service, groupKey string, memParams []mems.MemberParams) error {
+func bumProcessObjects(ds *admin.Service, pool *btch.Pool, report *btch.Report, groupKey string, memParams []mems.MemberParams) error {
 	lg.Debugw("starting bumProcessObjects()",
 		"groupKey", groupKey,
 		"memParams", memParams)
 	defer lg.Debug("finished bumProcessObjects()")
 
-	wg := new(sync.WaitGroup)
+	defer pool.Wait()
 
-	for _, mp := range memParams {
+	for idx, mp := range memParams {
+		idx := idx
+		mp := mp
 		muc := ds.Members.Update(groupKey, mp.MemberKey, mp.Member)
 
-		wg.Add(1)
-
-		go bumUpdate(mp.Member, groupKey, wg, muc, mp.MemberKey)
+		pool.Submit(func() {
+			report.Add(idx, mp.MemberKey, bumUpdate(mp.Member, groupKey, muc, mp.MemberKey))
+		})
 	}
 
-	wg.Wait()
-
 	return nil
 }
 
-func bumUpdate(member *admin.Member, groupKey string, wg *sync.WaitGroup, muc *admin.MembersUpdateCall, memKey string) {
+func bumUpdate(member *admin.Member, groupKey string, muc *admin.MembersUpdateCall, memKey string) error {
 	lg.Debugw("starting bumUpdate()",
 		"groupKey", groupKey,
 		"memKey", memKey)
 	defer lg.Debug("finished bumUpdate()")
 
-	defer wg.Done()
-
 	b := backoff.NewExponentialBackOff()
 	b.MaxElapsedTime = 32 * time.Second
 
@@ -200,20 +213,21 @@ func bumUpdate(member *admin.Member, groupKey string, wg *sync.WaitGroup, muc *a
 			return err
 		}
 		if !cmn.IsErrRetryable(err) {
-			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHMEMBER, err.Error(), memKey, groupKey))
+			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHMEMBER, err, memKey, groupKey))
 		}
 		// Log the retries
 		lg.Warnw(err.Error(),
 			"retrying", b.GetElapsedTime().String(),
 			"group", groupKey,
 			"member", memKey)
-		return fmt.Errorf(gmess.ERR_BATCHMEMBER, err.Error(), memKey, groupKey)
+		return fmt.Errorf(gmess.ERR_BATCHMEMBER, err, memKey, groupKey)
 	}, b)
 	if err != nil {
 		// Log final error
 		lg.Error(err)
 		fmt.Println(cmn.GminMessage(err.Error()))
 	}
+	return err
 }
 
 func init() {
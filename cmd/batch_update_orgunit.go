@@ -26,7 +26,6 @@ import (
 	"errors"
 	"fmt"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -76,8 +75,9 @@ func doBatchUpdOU(cmd *cobra.Command, args []string) error {
 	defer lg.Debug("finished doBatchUpdOU()")
 
 	var (
-		ouParams []ous.OrgUnitParams
+		input    *btch.Input
 		objs     []interface{}
+		ouParams []ous.OrgUnitParams
 	)
 
 	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryOrgunitScope)
@@ -121,12 +121,12 @@ func doBatchUpdOU(cmd *cobra.Command, args []string) error {
 
 	switch {
 	case lwrFmt == "csv":
-		objs, err = btch.ProcessCSVFile(callParams, inputFlgVal, ous.OrgUnitAttrMap)
+		objs, input, err = btch.ProcessCSVFile(callParams, inputFlgVal, ous.OrgUnitAttrMap)
 		if err != nil {
 			return err
 		}
 	case lwrFmt == "json":
-		objs, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, ous.OrgUnitAttrMap)
+		objs, input, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, ous.OrgUnitAttrMap)
 		if err != nil {
 			return err
 		}
@@ -137,7 +137,7 @@ func doBatchUpdOU(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		objs, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, ous.OrgUnitAttrMap)
+		objs, input, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, ous.OrgUnitAttrMap)
 		if err != nil {
 			return err
 		}
@@ -151,7 +151,22 @@ func doBatchUpdOU(cmd *cobra.Command, args []string) error {
 		ouParams = append(ouParams, opObj.(ous.OrgUnitParams))
 	}
 
-	err = buoProcessObjects(ds, ouParams)
+	pool, err := newBatchPool(cmd, btch.ORGUNITQPS)
+	if err != nil {
+		return err
+	}
+
+	report, err := newBatchReport(cmd, input)
+	if err != nil {
+		return err
+	}
+
+	err = buoProcessObjects(ds, pool, report, ouParams)
+	if err != nil {
+		return err
+	}
+
+	err = writeBatchReport(report)
 	if err != nil {
 		return err
 	}
@@ -159,12 +174,12 @@ func doBatchUpdOU(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func buoProcessObjects(ds *admin.Service, ouParams []ous.OrgUnitParams) error {
+func buoProcessObjects(ds *admin.Service, pool *btch.Pool, report *btch.Report, ouParams []ous.OrgUnitParams) error {
 	lg.Debugw("starting buoProcessObjects()",
 		"ouParams", ouParams)
 	defer lg.Debug("finished buoProcessObjects()")
 
-	wg := new(sync.WaitGroup)
+	defer pool.Wait()
 
 	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
 	if err != nil {
@@ -172,30 +187,24 @@ func buoProcessObjects(ds *admin.Service, ouParams []ous.OrgUnitParams) error {
 		return err
 	}
 
-	for _, op := range ouParams {
+	for idx, op := range ouParams {
+		idx := idx
+		op := op
 		ouuc := ds.Orgunits.Update(customerID, op.OUKey, op.OrgUnit)
 
-		wg.Add(1)
-
-		// Sleep for 2 seconds because only 1 orgunit can be updated per second but 1 second interval
-		// still can result in rate limit errors
-		time.Sleep(2 * time.Second)
-
-		go buoUpdate(op.OrgUnit, wg, ouuc, op.OUKey)
+		pool.Submit(func() {
+			report.Add(idx, op.OUKey, buoUpdate(op.OrgUnit, ouuc, op.OUKey))
+		})
 	}
 
-	wg.Wait()
-
 	return nil
 }
 
-func buoUpdate(orgunit *admin.OrgUnit, wg *sync.WaitGroup, ouuc *admin.OrgunitsUpdateCall, ouKey string) {
+func buoUpdate(orgunit *admin.OrgUnit, ouuc *admin.OrgunitsUpdateCall, ouKey string) error {
 	lg.Debugw("starting buoUpdate()",
 		"ouKey", ouKey)
 	defer lg.Debug("finished buoUpdate()")
 
-	defer wg.Done()
-
 	b := backoff.NewExponentialBackOff()
 	b.MaxElapsedTime = 32 * time.Second
 
@@ -208,19 +217,20 @@ func buoUpdate(orgunit *admin.OrgUnit, wg *sync.WaitGroup, ouuc *admin.OrgunitsU
 			return err
 		}
 		if !cmn.IsErrRetryable(err) {
-			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHOU, err.Error(), ouKey))
+			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHOU, err, ouKey))
 		}
 		// Log the retries
 		lg.Warnw(err.Error(),
 			"retrying", b.GetElapsedTime().String(),
 			"orgunit", ouKey)
-		return fmt.Errorf(gmess.ERR_BATCHOU, err.Error(), ouKey)
+		return fmt.Errorf(gmess.ERR_BATCHOU, err, ouKey)
 	}, b)
 	if err != nil {
 		// Log final error
 		lg.Error(err)
 		fmt.Println(cmn.GminMessage(err.Error()))
 	}
+	return err
 }
 
 func init() {
@@ -26,7 +26,6 @@ import (
 	"errors"
 	"fmt"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -35,6 +34,7 @@ import (
 	flgnm "github.com/plusworx/gmin/utils/flagnames"
 	gmess "github.com/plusworx/gmin/utils/gminmessages"
 	lg "github.com/plusworx/gmin/utils/logging"
+	pwds "github.com/plusworx/gmin/utils/passwords"
 	usrs "github.com/plusworx/gmin/utils/users"
 	"github.com/spf13/cobra"
 	admin "google.golang.org/api/admin/directory/v1"
@@ -61,6 +61,7 @@ CSV and Google sheets must have a header row with the following column names bei
 
 changePasswordAtNextLogin [value true or false]
 firstName
+hashFunction [crypt, MD5 or SHA-1 - only if password is already hashed]
 includeInGlobalAddressList [value true or false]
 ipWhitelisted [value true or false]
 lastName
@@ -72,7 +73,11 @@ recoveryPhone [must start with '+' in E.164 format]
 suspended [value true or false]
 userKey [required]
 
-The column names are case insensitive and can be in any order. firstName can be replaced by givenName and lastName can be replaced by familyName.`,
+The column names are case insensitive and can be in any order. firstName can be replaced by givenName and lastName can be replaced by familyName.
+
+Passwords are hashed by the hasher given by --hasher, the hasher config value or crypt (SHA-512) by default. Passwords
+that are already hashed, for example when migrating from another system, can be imported by giving their hash function
+(crypt, MD5 or SHA-1) in hashFunction. They are checked but not hashed again.`,
 	RunE: doBatchUpdUser,
 }
 
@@ -82,10 +87,16 @@ func doBatchUpdUser(cmd *cobra.Command, args []string) error {
 	defer lg.Debug("finished doBatchUpdUser()")
 
 	var (
-		userParams []usrs.UserParams
+		input      *btch.Input
 		objs       []interface{}
+		userParams []usrs.UserParams
 	)
 
+	hasher, err := passwordHasher(cmd)
+	if err != nil {
+		return err
+	}
+
 	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryUserScope)
 	if err != nil {
 		return err
@@ -128,12 +139,12 @@ func doBatchUpdUser(cmd *cobra.Command, args []string) error {
 
 	switch {
 	case lwrFmt == "csv":
-		objs, err = btch.ProcessCSVFile(callParams, inputFlgVal, usrs.UserAttrMap)
+		objs, input, err = btch.ProcessCSVFile(callParams, inputFlgVal, usrs.UserAttrMap)
 		if err != nil {
 			return err
 		}
 	case lwrFmt == "json":
-		objs, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, usrs.UserAttrMap)
+		objs, input, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, usrs.UserAttrMap)
 		if err != nil {
 			return err
 		}
@@ -144,7 +155,7 @@ func doBatchUpdUser(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		objs, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, usrs.UserAttrMap)
+		objs, input, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, usrs.UserAttrMap)
 		if err != nil {
 			return err
 		}
@@ -158,7 +169,17 @@ func doBatchUpdUser(cmd *cobra.Command, args []string) error {
 		userParams = append(userParams, uObj.(usrs.UserParams))
 	}
 
-	err = bupduProcessObjects(ds, userParams)
+	pool, err := newBatchPool(cmd, btch.DIRECTORYQPS)
+	if err != nil {
+		return err
+	}
+
+	report, err := newBatchReport(cmd, input)
+	if err != nil {
+		return err
+	}
+
+	err = bupduProcessObjects(ds, pool, report, userParams, hasher)
 	if err != nil {
 		lg.Error(err)
 		return err
@@ -167,43 +188,36 @@ func doBatchUpdUser(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func bupduProcessObjects(ds *admin.Service, userParams []usrs.UserParams) error {
-	lg.Debugw("starting bupduProcessObjects()",
-		"userParams", userParams)
+func bupduProcessObjects(ds *admin.Service, pool *btch.Pool, report *btch.Report, userParams []usrs.UserParams, hasher pwds.Hasher) error {
+	lg.Debug("starting bupduProcessObjects()")
 	defer lg.Debug("finished bupduProcessObjects()")
 
-	wg := new(sync.WaitGroup)
-
-	for _, up := range userParams {
-		if up.User.Password != "" {
-			up.User.HashFunction = usrs.HASHFUNCTION
-			pwd, err := usrs.HashPassword(up.User.Password)
-			if err != nil {
-				lg.Error(err)
-				return err
-			}
-			up.User.Password = pwd
+	defer pool.Wait()
+
+	for idx, up := range userParams {
+		idx := idx
+		up := up
+		err := pwds.Prepare(up.User, hasher)
+		if err != nil {
+			report.Add(idx, up.UserKey, err)
+			continue
 		}
 
 		uuc := ds.Users.Update(up.UserKey, up.User)
 
-		wg.Add(1)
-
-		go bupduUpdate(up.User, wg, uuc, up.UserKey)
+		pool.Submit(func() {
+			report.Add(idx, up.UserKey, bupduUpdate(up.User, uuc, up.UserKey))
+		})
 	}
 
-	wg.Wait()
-
 	return nil
 }
 
-func bupduUpdate(user *admin.User, wg *sync.WaitGroup, uuc *admin.UsersUpdateCall, userKey string) {
+func bupduUpdate(user *admin.User, uuc *admin.UsersUpdateCall, userKey string) error {
 	lg.Debugw("starting bupduUpdate()",
 		"userKey", userKey)
 	defer lg.Debug("finished bupduUpdate()")
 
-	defer wg.Done()
-
 	b := backoff.NewExponentialBackOff()
 	b.MaxElapsedTime = 32 * time.Second
 
@@ -216,24 +230,26 @@ func bupduUpdate(user *admin.User, wg *sync.WaitGroup, uuc *admin.UsersUpdateCal
 			return err
 		}
 		if !cmn.IsErrRetryable(err) {
-			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHUSER, err.Error(), userKey))
+			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHUSER, err, userKey))
 		}
 		// Log the retries
 		lg.Warnw(err.Error(),
 			"retrying", b.GetElapsedTime().String(),
 			"user", userKey)
-		return fmt.Errorf(gmess.ERR_BATCHUSER, err.Error(), userKey)
+		return fmt.Errorf(gmess.ERR_BATCHUSER, err, userKey)
 	}, b)
 	if err != nil {
 		// Log final error
 		lg.Error(err)
 		fmt.Println(cmn.GminMessage(err.Error()))
 	}
+	return err
 }
 
 func init() {
 	batchUpdateCmd.AddCommand(batchUpdUserCmd)
 
+	batchUpdUserCmd.Flags().StringVar(&hasher, flgnm.FLG_HASHER, "", "password hasher (crypt, md5 or sha-1)")
 	batchUpdUserCmd.Flags().StringVarP(&inputFile, flgnm.FLG_INPUTFILE, "i", "", "filepath to user data file or sheet id")
 	batchUpdUserCmd.Flags().StringVarP(&format, flgnm.FLG_FORMAT, "f", "json", "user data file format")
 	batchUpdUserCmd.Flags().StringVarP(&sheetRange, flgnm.FLG_SHEETRANGE, "s", "", "user data gsheet range")
@@ -0,0 +1,202 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	btch "github.com/plusworx/gmin/utils/batch"
+	cmn "github.com/plusworx/gmin/utils/common"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	usrs "github.com/plusworx/gmin/utils/users"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var batchUpdUserPhotoCmd = &cobra.Command{
+	Use:     "user-photos -d <photo directory path>",
+	Aliases: []string{"user-photo", "usr-photos", "usr-photo", "uphotos", "uphoto"},
+	Example: `gmin batch-update user-photos -d ./photos
+gmin bupd uphotos -d /staff/photos --workers 4`,
+	Short: "Sets the photos of a batch of users from a directory of image files",
+	Long: `Sets the photos of a batch of users from a directory of JPEG or PNG image files.
+
+Each file must be named after the primary email address of the user like this:
+
+frank.castle@mycompany.com.jpg
+bruce.wayne@mycompany.com.jpeg
+peter.parker@mycompany.com.png
+
+Files with other extensions are ignored. Images larger than 96x96 pixels are scaled down, keeping their aspect ratio,
+and all images are uploaded as JPEG.`,
+	RunE: doBatchUpdUserPhoto,
+}
+
+func doBatchUpdUserPhoto(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doBatchUpdUserPhoto()",
+		"args", args)
+	defer lg.Debug("finished doBatchUpdUserPhoto()")
+
+	var (
+		files []string
+		input = &btch.Input{Format: "text"}
+		users []string
+	)
+
+	flgDirVal, err := cmd.Flags().GetString(flgnm.FLG_DIR)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgDirVal == "" {
+		err = fmt.Errorf(gmess.ERR_EMPTYSTRING, flgnm.FLG_DIR)
+		lg.Error(err)
+		return err
+	}
+
+	fileInfos, err := ioutil.ReadDir(flgDirVal)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	for _, fi := range fileInfos {
+		if fi.IsDir() {
+			continue
+		}
+		user, ok := usrs.PhotoUserKey(fi.Name())
+		if !ok {
+			continue
+		}
+		files = append(files, filepath.Join(flgDirVal, fi.Name()))
+		users = append(users, user)
+		input.Rows = append(input.Rows, []string{fi.Name()})
+	}
+
+	if len(files) == 0 {
+		err = fmt.Errorf(gmess.ERR_NOPHOTOFILES, flgDirVal)
+		lg.Error(err)
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryUserScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	pool, err := newBatchPool(cmd, btch.DIRECTORYQPS)
+	if err != nil {
+		return err
+	}
+
+	report, err := newBatchReport(cmd, input)
+	if err != nil {
+		return err
+	}
+
+	err = bupphProcessObjects(ds, pool, report, users, files)
+	if err != nil {
+		return err
+	}
+
+	err = writeBatchReport(report)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func bupphProcessObjects(ds *admin.Service, pool *btch.Pool, report *btch.Report, users []string, files []string) error {
+	lg.Debug("starting bupphProcessObjects()")
+	defer lg.Debug("finished bupphProcessObjects()")
+
+	defer pool.Wait()
+
+	for idx, user := range users {
+		idx := idx
+		user := user
+		file := files[idx]
+
+		pool.Submit(func() {
+			report.Add(idx, user, bupphUpdate(ds, user, file))
+		})
+	}
+
+	return nil
+}
+
+func bupphUpdate(ds *admin.Service, user string, file string) error {
+	lg.Debugw("starting bupphUpdate()",
+		"user", user,
+		"file", file)
+	defer lg.Debug("finished bupphUpdate()")
+
+	photo, err := usrs.PhotoFromFile(file)
+	if err != nil {
+		err = fmt.Errorf(gmess.ERR_BATCHPHOTO, err, file)
+		fmt.Println(cmn.GminMessage(err.Error()))
+		return err
+	}
+
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = 32 * time.Second
+
+	err = backoff.Retry(func() error {
+		var err error
+
+		_, err = ds.Users.Photos.Update(user, photo).Do()
+		if err == nil {
+			fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_PHOTOUPDATED, user)))
+			lg.Infof(gmess.INFO_PHOTOUPDATED, user)
+			return err
+		}
+		if !cmn.IsErrRetryable(err) {
+			return backoff.Permanent(fmt.Errorf(gmess.ERR_BATCHUSER, err, user))
+		}
+		// Log the retries
+		lg.Warnw(err.Error(),
+			"retrying", b.GetElapsedTime().String(),
+			"user", user)
+		return fmt.Errorf(gmess.ERR_BATCHUSER, err, user)
+	}, b)
+	if err != nil {
+		// Log final error
+		lg.Error(err)
+		fmt.Println(cmn.GminMessage(err.Error()))
+	}
+	return err
+}
+
+func init() {
+	batchUpdateCmd.AddCommand(batchUpdUserPhotoCmd)
+
+	batchUpdUserPhotoCmd.Flags().StringVarP(&photoDir, flgnm.FLG_DIR, "d", "", "path of directory containing user photo files")
+}
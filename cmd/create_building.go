@@ -0,0 +1,158 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	cfg "github.com/plusworx/gmin/utils/config"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	rsrcs "github.com/plusworx/gmin/utils/resources"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var createBuildingCmd = &cobra.Command{
+	Use:     "building <building id>",
+	Aliases: []string{"bldg"},
+	Args:    cobra.ExactArgs(1),
+	Example: `gmin create building hq -n "Head Office" --floor-names G~1~2
+gmin crt bldg annex -n Annex -d "Annex building" --floor-names 1`,
+	Short: "Creates a building",
+	Long: `Creates a building.
+
+Floor names are separated by (~) and are ordered from the lowest floor to the highest floor. Building address
+and coordinates can be set with the batch-create and batch-update building commands.`,
+	RunE: doCreateBuilding,
+}
+
+func doCreateBuilding(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doCreateBuilding()",
+		"args", args)
+	defer lg.Debug("finished doCreateBuilding()")
+
+	var (
+		building    *admin.Building
+		flagsPassed []string
+	)
+
+	building = new(admin.Building)
+
+	building.BuildingId = args[0]
+
+	// Collect names of command flags passed in
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		flagsPassed = append(flagsPassed, f.Name)
+	})
+
+	// Process command flags
+	err := processBuildingFlags(cmd, building, flagsPassed)
+	if err != nil {
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryResourceCalendarScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
+	if err != nil {
+		return err
+	}
+
+	rbic := ds.Resources.Buildings.Insert(customerID, building)
+	newBuilding, err := rbic.Do()
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_BUILDINGCREATED, newBuilding.BuildingId)))
+	lg.Infof(gmess.INFO_BUILDINGCREATED, newBuilding.BuildingId)
+
+	return nil
+}
+
+func init() {
+	createCmd.AddCommand(createBuildingCmd)
+
+	createBuildingCmd.Flags().StringVarP(&resourceDesc, flgnm.FLG_DESCRIPTION, "d", "", "building description")
+	createBuildingCmd.Flags().StringVar(&floorNames, flgnm.FLG_FLOORNAMES, "", "building floor names separated by (~)")
+	createBuildingCmd.Flags().StringVarP(&resourceName, flgnm.FLG_NAME, "n", "", "building name")
+	createBuildingCmd.MarkFlagRequired(flgnm.FLG_FLOORNAMES)
+	createBuildingCmd.MarkFlagRequired(flgnm.FLG_NAME)
+}
+
+// processBuildingFlags sets building attributes from the command flags passed in
+func processBuildingFlags(cmd *cobra.Command, building *admin.Building, flagNames []string) error {
+	lg.Debugw("starting processBuildingFlags()",
+		"flagNames", flagNames)
+	defer lg.Debug("finished processBuildingFlags()")
+
+	for _, flName := range flagNames {
+		if flName == flgnm.FLG_DESCRIPTION {
+			flgDescriptionVal, err := cmd.Flags().GetString(flgnm.FLG_DESCRIPTION)
+			if err != nil {
+				lg.Error(err)
+				return err
+			}
+			if flgDescriptionVal == "" {
+				building.ForceSendFields = append(building.ForceSendFields, "Description")
+			}
+			building.Description = flgDescriptionVal
+		}
+		if flName == flgnm.FLG_FLOORNAMES {
+			flgFloorNamesVal, err := cmd.Flags().GetString(flgnm.FLG_FLOORNAMES)
+			if err != nil {
+				lg.Error(err)
+				return err
+			}
+			building.FloorNames = rsrcs.FloorNames(flgFloorNamesVal)
+			if len(building.FloorNames) == 0 {
+				err = fmt.Errorf(gmess.ERR_EMPTYSTRING, "--"+flName)
+				lg.Error(err)
+				return err
+			}
+		}
+		if flName == flgnm.FLG_NAME {
+			flgNameVal, err := cmd.Flags().GetString(flgnm.FLG_NAME)
+			if err != nil {
+				lg.Error(err)
+				return err
+			}
+			if flgNameVal == "" {
+				err = fmt.Errorf(gmess.ERR_EMPTYSTRING, "--"+flName)
+				lg.Error(err)
+				return err
+			}
+			building.BuildingName = flgNameVal
+		}
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	cfg "github.com/plusworx/gmin/utils/config"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	rsrcs "github.com/plusworx/gmin/utils/resources"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var createCalResCmd = &cobra.Command{
+	Use:     "calendar-resource <resource id>",
+	Aliases: []string{"cal-resource", "calres", "cres"},
+	Args:    cobra.ExactArgs(1),
+	Example: `gmin create calendar-resource room1 -n "Meeting Room 1" -b hq --floor-name 2 -c 10
+gmin crt cres projector1 -n "Projector 1" -t Projector --category OTHER`,
+	Short: "Creates a calendar resource",
+	Long: `Creates a calendar resource.
+
+Features are given by feature name separated by (~) and must already exist. Valid resource categories are
+CATEGORY_UNKNOWN, CONFERENCE_ROOM and OTHER.`,
+	RunE: doCreateCalRes,
+}
+
+func doCreateCalRes(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doCreateCalRes()",
+		"args", args)
+	defer lg.Debug("finished doCreateCalRes()")
+
+	var (
+		calendar    *admin.CalendarResource
+		flagsPassed []string
+	)
+
+	calendar = new(admin.CalendarResource)
+
+	calendar.ResourceId = args[0]
+
+	// Collect names of command flags passed in
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		flagsPassed = append(flagsPassed, f.Name)
+	})
+
+	// Process command flags
+	err := processCalResFlags(cmd, calendar, flagsPassed)
+	if err != nil {
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryResourceCalendarScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
+	if err != nil {
+		return err
+	}
+
+	rcic := ds.Resources.Calendars.Insert(customerID, calendar)
+	newCalendar, err := rcic.Do()
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_CALRESCREATED, newCalendar.ResourceId)))
+	lg.Infof(gmess.INFO_CALRESCREATED, newCalendar.ResourceId)
+
+	return nil
+}
+
+func init() {
+	createCmd.AddCommand(createCalResCmd)
+
+	createCalResCmd.Flags().StringVarP(&buildingID, flgnm.FLG_BUILDINGID, "b", "", "id of building containing the resource")
+	createCalResCmd.Flags().Int64VarP(&capacity, flgnm.FLG_CAPACITY, "c", 0, "capacity of the resource")
+	createCalResCmd.Flags().StringVar(&resourceCategory, flgnm.FLG_CATEGORY, "", "resource category")
+	createCalResCmd.Flags().StringVarP(&resourceDesc, flgnm.FLG_DESCRIPTION, "d", "", "resource description for admins")
+	createCalResCmd.Flags().StringVar(&features, flgnm.FLG_FEATURES, "", "resource feature names separated by (~)")
+	createCalResCmd.Flags().StringVar(&floorName, flgnm.FLG_FLOORNAME, "", "name of floor containing the resource")
+	createCalResCmd.Flags().StringVar(&floorSection, flgnm.FLG_FLOORSECTION, "", "name of floor section containing the resource")
+	createCalResCmd.Flags().StringVarP(&resourceName, flgnm.FLG_NAME, "n", "", "resource name")
+	createCalResCmd.Flags().StringVarP(&resourceType, flgnm.FLG_RESOURCETYPE, "t", "", "resource type")
+	createCalResCmd.Flags().StringVarP(&userDesc, flgnm.FLG_USERDESC, "u", "", "resource description for users")
+	createCalResCmd.MarkFlagRequired(flgnm.FLG_NAME)
+}
+
+// calResStrFlags are the calendar resource string attribute flags
+var calResStrFlags = []string{
+	flgnm.FLG_BUILDINGID,
+	flgnm.FLG_CATEGORY,
+	flgnm.FLG_DESCRIPTION,
+	flgnm.FLG_FLOORNAME,
+	flgnm.FLG_FLOORSECTION,
+	flgnm.FLG_NAME,
+	flgnm.FLG_RESOURCETYPE,
+	flgnm.FLG_USERDESC,
+}
+
+// processCalResFlags sets calendar resource attributes from the command flags passed in
+func processCalResFlags(cmd *cobra.Command, calendar *admin.CalendarResource, flagNames []string) error {
+	lg.Debugw("starting processCalResFlags()",
+		"flagNames", flagNames)
+	defer lg.Debug("finished processCalResFlags()")
+
+	for _, flName := range flagNames {
+		if flName == flgnm.FLG_CAPACITY {
+			flgCapacityVal, err := cmd.Flags().GetInt64(flgnm.FLG_CAPACITY)
+			if err != nil {
+				lg.Error(err)
+				return err
+			}
+			if flgCapacityVal == 0 {
+				calendar.ForceSendFields = append(calendar.ForceSendFields, "Capacity")
+			}
+			calendar.Capacity = flgCapacityVal
+			continue
+		}
+		if flName == flgnm.FLG_FEATURES {
+			flgFeaturesVal, err := cmd.Flags().GetString(flgnm.FLG_FEATURES)
+			if err != nil {
+				lg.Error(err)
+				return err
+			}
+			calendar.FeatureInstances = rsrcs.FeatureInstances(flgFeaturesVal)
+			if flgFeaturesVal == "" {
+				calendar.ForceSendFields = append(calendar.ForceSendFields, "FeatureInstances")
+			}
+			continue
+		}
+
+		if !cmn.SliceContainsStr(calResStrFlags, flName) {
+			continue
+		}
+
+		flgVal, err := cmd.Flags().GetString(flName)
+		if err != nil {
+			lg.Error(err)
+			return err
+		}
+
+		var field string
+
+		switch flName {
+		case flgnm.FLG_BUILDINGID:
+			calendar.BuildingId = flgVal
+			field = "BuildingId"
+		case flgnm.FLG_CATEGORY:
+			calendar.ResourceCategory = flgVal
+			field = "ResourceCategory"
+		case flgnm.FLG_DESCRIPTION:
+			calendar.ResourceDescription = flgVal
+			field = "ResourceDescription"
+		case flgnm.FLG_FLOORNAME:
+			calendar.FloorName = flgVal
+			field = "FloorName"
+		case flgnm.FLG_FLOORSECTION:
+			calendar.FloorSection = flgVal
+			field = "FloorSection"
+		case flgnm.FLG_NAME:
+			if flgVal == "" {
+				err = fmt.Errorf(gmess.ERR_EMPTYSTRING, "--"+flName)
+				lg.Error(err)
+				return err
+			}
+			calendar.ResourceName = flgVal
+		case flgnm.FLG_RESOURCETYPE:
+			calendar.ResourceType = flgVal
+			field = "ResourceType"
+		case flgnm.FLG_USERDESC:
+			calendar.UserVisibleDescription = flgVal
+			field = "UserVisibleDescription"
+		}
+
+		if flgVal == "" && field != "" {
+			calendar.ForceSendFields = append(calendar.ForceSendFields, field)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	cfg "github.com/plusworx/gmin/utils/config"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var createDomainCmd = &cobra.Command{
+	Use:     "domain <domain name>",
+	Aliases: []string{"dom"},
+	Args:    cobra.ExactArgs(1),
+	Example: `gmin create domain mycompany.net
+gmin crt dom mycompany.net`,
+	Short: "Adds a secondary domain to the customer",
+	Long: `Adds a secondary domain to the customer.
+
+The domain must be verified before users and groups can be created in it.`,
+	RunE: doCreateDomain,
+}
+
+func doCreateDomain(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doCreateDomain()",
+		"args", args)
+	defer lg.Debug("finished doCreateDomain()")
+
+	var domain *admin.Domains
+
+	domain = new(admin.Domains)
+
+	domain.DomainName = args[0]
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryDomainScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
+	if err != nil {
+		return err
+	}
+
+	dic := ds.Domains.Insert(customerID, domain)
+	newDomain, err := dic.Do()
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_DOMAINCREATED, newDomain.DomainName)))
+	lg.Infof(gmess.INFO_DOMAINCREATED, newDomain.DomainName)
+
+	return nil
+}
+
+func init() {
+	createCmd.AddCommand(createDomainCmd)
+}
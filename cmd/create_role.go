@@ -0,0 +1,134 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	cfg "github.com/plusworx/gmin/utils/config"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	rls "github.com/plusworx/gmin/utils/roles"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var createRoleCmd = &cobra.Command{
+	Use:  "role <role name>",
+	Args: cobra.ExactArgs(1),
+	Example: `gmin create role "Helpdesk Admin" -d "Password resets" -p USERS_RETRIEVE~USERS_UPDATE
+gmin crt role "Group Reader" -p GROUPS_RETRIEVE`,
+	Short: "Creates a custom admin role",
+	Long: `Creates a custom admin role.
+
+Privileges are given by privilege name separated by (~). Valid privilege names can be found with the list privileges
+command.`,
+	RunE: doCreateRole,
+}
+
+func doCreateRole(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doCreateRole()",
+		"args", args)
+	defer lg.Debug("finished doCreateRole()")
+
+	var role *admin.Role
+
+	role = new(admin.Role)
+
+	role.RoleName = args[0]
+
+	flgDescVal, err := cmd.Flags().GetString(flgnm.FLG_DESCRIPTION)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgDescVal != "" {
+		role.RoleDescription = flgDescVal
+	}
+
+	flgPrivsVal, err := cmd.Flags().GetString(flgnm.FLG_PRIVILEGES)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryRolemanagementScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
+	if err != nil {
+		return err
+	}
+
+	serviceIDs, err := roleServiceIDs(ds, customerID)
+	if err != nil {
+		return err
+	}
+
+	role.RolePrivileges, err = rls.ParsePrivileges(flgPrivsVal, serviceIDs)
+	if err != nil {
+		return err
+	}
+
+	ric := ds.Roles.Insert(customerID, role)
+	newRole, err := ric.Do()
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	roleStr := newRole.RoleName + " (" + strconv.FormatInt(newRole.RoleId, 10) + ")"
+	fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_ROLECREATED, roleStr)))
+	lg.Infof(gmess.INFO_ROLECREATED, roleStr)
+
+	return nil
+}
+
+// roleServiceIDs returns a map of privilege names to service IDs for the customer
+func roleServiceIDs(ds *admin.Service, customerID string) (map[string]string, error) {
+	lg.Debugw("starting roleServiceIDs()",
+		"customerID", customerID)
+	defer lg.Debug("finished roleServiceIDs()")
+
+	plc := ds.Privileges.List(customerID)
+	privileges, err := rls.DoListPrivileges(plc)
+	if err != nil {
+		return nil, err
+	}
+
+	return rls.ServiceIDs(privileges.Items), nil
+}
+
+func init() {
+	createCmd.AddCommand(createRoleCmd)
+
+	createRoleCmd.Flags().StringVarP(&roleDesc, flgnm.FLG_DESCRIPTION, "d", "", "role description")
+	createRoleCmd.Flags().StringVarP(&privileges, flgnm.FLG_PRIVILEGES, "p", "", "role privilege names separated by (~)")
+	createRoleCmd.MarkFlagRequired(flgnm.FLG_PRIVILEGES)
+}
@@ -0,0 +1,158 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	cfg "github.com/plusworx/gmin/utils/config"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	rls "github.com/plusworx/gmin/utils/roles"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var createRoleAssignCmd = &cobra.Command{
+	Use:     "role-assignment <user email address or id>",
+	Aliases: []string{"role-asgmt", "rasgmt", "ra"},
+	Args:    cobra.ExactArgs(1),
+	Example: `gmin create role-assignment mickey.mouse@disney.com -r "Helpdesk Admin"
+gmin crt ra mickey.mouse@disney.com -r 123456789 -o /Sales`,
+	Short: "Assigns an admin role to a user",
+	Long: `Assigns an admin role to a user.
+
+The role applies to the whole customer unless an orgunit path or id is given, in which case the role can
+only be used for that orgunit and its children.`,
+	RunE: doCreateRoleAssign,
+}
+
+func doCreateRoleAssign(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doCreateRoleAssign()",
+		"args", args)
+	defer lg.Debug("finished doCreateRoleAssign()")
+
+	raParams := rls.RoleAssignmentParams{AssignedTo: args[0]}
+
+	flgRoleVal, err := cmd.Flags().GetString(flgnm.FLG_ROLE)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	raParams.RoleKey = flgRoleVal
+
+	flgOUVal, err := cmd.Flags().GetString(flgnm.FLG_ORGUNIT)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	raParams.OrgUnit = flgOUVal
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryRolemanagementScope, admin.AdminDirectoryUserReadonlyScope,
+		admin.AdminDirectoryOrgunitReadonlyScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
+	if err != nil {
+		return err
+	}
+
+	roleAssign, err := newRoleAssignment(ds, customerID, raParams)
+	if err != nil {
+		return err
+	}
+
+	raic := ds.RoleAssignments.Insert(customerID, roleAssign)
+	newRoleAssign, err := raic.Do()
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	raID := strconv.FormatInt(newRoleAssign.RoleAssignmentId, 10)
+	fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_RACREATED, raID)))
+	lg.Infof(gmess.INFO_RACREATED, raID)
+
+	return nil
+}
+
+// newRoleAssignment makes a role assignment from the user, role and orgunit given by name, email
+// address, path or id
+func newRoleAssignment(ds *admin.Service, customerID string, raParams rls.RoleAssignmentParams) (*admin.RoleAssignment, error) {
+	lg.Debugw("starting newRoleAssignment()",
+		"raParams", raParams)
+	defer lg.Debug("finished newRoleAssignment()")
+
+	if raParams.AssignedTo == "" || raParams.RoleKey == "" {
+		err := errors.New(gmess.ERR_NOROLEORASSIGNEE)
+		lg.Error(err)
+		return nil, err
+	}
+
+	roleAssign := &admin.RoleAssignment{ScopeType: rls.SCOPECUSTOMER}
+
+	roleID, err := rls.RoleID(ds, customerID, raParams.RoleKey)
+	if err != nil {
+		return nil, err
+	}
+	roleAssign.RoleId, err = strconv.ParseInt(roleID, 10, 64)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	user, err := ds.Users.Get(raParams.AssignedTo).Fields("id").Do()
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+	roleAssign.AssignedTo = user.Id
+
+	if raParams.OrgUnit != "" {
+		ou, err := ds.Orgunits.Get(customerID, strings.TrimPrefix(raParams.OrgUnit, "/")).Fields("orgUnitId").Do()
+		if err != nil {
+			lg.Error(err)
+			return nil, err
+		}
+		roleAssign.OrgUnitId = strings.TrimPrefix(ou.OrgUnitId, "id:")
+		roleAssign.ScopeType = rls.SCOPEORGUNIT
+	}
+
+	return roleAssign, nil
+}
+
+func init() {
+	createCmd.AddCommand(createRoleAssignCmd)
+
+	createRoleAssignCmd.Flags().StringVarP(&orgUnit, flgnm.FLG_ORGUNIT, "o", "", "path or id of orgunit that the role is restricted to")
+	createRoleAssignCmd.Flags().StringVarP(&role, flgnm.FLG_ROLE, "r", "", "name or id of role to assign")
+	createRoleAssignCmd.MarkFlagRequired(flgnm.FLG_ROLE)
+}
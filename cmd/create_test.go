@@ -23,9 +23,11 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"strings"
 	"testing"
 
 	lg "github.com/plusworx/gmin/utils/logging"
+	admin "google.golang.org/api/admin/directory/v1"
 )
 
 func TestDoCreateUser(t *testing.T) {
@@ -87,3 +89,81 @@ func TestDoCreateUser(t *testing.T) {
 		}
 	}
 }
+
+func TestCreateCmdsFakeServer(t *testing.T) {
+	fs := newFakeServer(t)
+	fs.AddUser(&admin.User{PrimaryEmail: "donald.duck@disney.com", Name: &admin.UserName{GivenName: "Donald", FamilyName: "Duck"}})
+	fs.AddDomain(&admin.Domains{DomainName: "disney.com", IsPrimary: true})
+
+	cases := []struct {
+		args        []string
+		expectedErr string
+	}{
+		{
+			args: []string{"create", "user", "mickey.mouse@disney.com", "-f", "Mickey", "-l", "Mouse", "-p", "VeryStrongPassword"},
+		},
+		{
+			args:        []string{"create", "user", "donald.duck@disney.com", "-f", "Donald", "-l", "Duck", "-p", "VeryStrongPassword"},
+			expectedErr: "googleapi: Error 409: Entity already exists., duplicate",
+		},
+		{
+			args: []string{"create", "group", "cartoons@disney.com", "-n", "Cartoons"},
+		},
+		{
+			args: []string{"create", "group-member", "mickey.mouse@disney.com", "cartoons@disney.com", "-r", "OWNER"},
+		},
+		{
+			args: []string{"create", "orgunit", "Characters"},
+		},
+		{
+			args: []string{"create", "user-alias", "mickey@disney.com", "mickey.mouse@disney.com"},
+		},
+		{
+			args: []string{"create", "domain", "pixar.com"},
+		},
+		{
+			args: []string{"create", "domain-alias", "pixar.co.uk", "pixar.com"},
+		},
+		{
+			args:        []string{"create", "domain-alias", "marvel.co.uk", "marvel.com"},
+			expectedErr: "googleapi: Error 404: Resource Not Found: marvel.com, notFound",
+		},
+	}
+
+	for _, c := range cases {
+		_, got := runGmin(t, c.args...)
+
+		gotErr := ""
+		if got != nil {
+			gotErr = got.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("Got error: %v - expected error: %v", gotErr, c.expectedErr)
+		}
+	}
+
+	user := fs.User("mickey.mouse@disney.com")
+	if user == nil {
+		t.Fatal("Got user: nil - expected user: mickey.mouse@disney.com")
+	}
+	if user.Name.GivenName != "Mickey" || user.HashFunction != "crypt" || !strings.HasPrefix(user.Password, "$6$") {
+		t.Errorf("Got user: %v %v - expected user: Mickey crypt", user.Name.GivenName, user.HashFunction)
+	}
+	if fs.User("mickey@disney.com") != user {
+		t.Errorf("Got alias user: %v - expected alias user: %v", fs.User("mickey@disney.com"), user.PrimaryEmail)
+	}
+
+	member := fs.Member("cartoons@disney.com", "mickey.mouse@disney.com")
+	if member == nil || member.Role != "OWNER" {
+		t.Errorf("Got member: %v - expected member with role: OWNER", member)
+	}
+
+	if fs.OrgUnits["/Characters"] == nil {
+		t.Error("Got orgunit: nil - expected orgunit: /Characters")
+	}
+
+	alias := fs.DomainAliases["pixar.co.uk"]
+	if fs.Domains["pixar.com"] == nil || alias == nil || alias.ParentDomainName != "pixar.com" {
+		t.Errorf("Got domain alias: %v - expected domain alias: pixar.co.uk for domain: pixar.com", alias)
+	}
+}
@@ -31,6 +31,7 @@ import (
 	flgnm "github.com/plusworx/gmin/utils/flagnames"
 	gmess "github.com/plusworx/gmin/utils/gminmessages"
 	lg "github.com/plusworx/gmin/utils/logging"
+	pwds "github.com/plusworx/gmin/utils/passwords"
 	usrs "github.com/plusworx/gmin/utils/users"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -47,8 +48,11 @@ var createUserCmd = &cobra.Command{
 	Example: `gmin create user another.user@mycompany.com  -f Another -l User -p strongpassword
 gmin crt user finance.person@mycompany.com -f Finance -l Person -p greatpassword -c`,
 	Short: "Creates a user",
-	Long:  `Creates a user.`,
-	RunE:  doCreateUser,
+	Long: `Creates a user.
+
+The password is hashed by the hasher given by --hasher, the hasher config value or crypt (SHA-512) by default. An
+already hashed password can be given in the attributes along with its hashFunction (crypt, MD5 or SHA-1).`,
+	RunE: doCreateUser,
 }
 
 func doCreateUser(cmd *cobra.Command, args []string) error {
@@ -126,14 +130,9 @@ func doCreateUser(cmd *cobra.Command, args []string) error {
 			attrUser.ForceSendFields = emptyVals.ForceSendFields
 		}
 
-		if user.Password == "" && attrUser.Password != "" {
-			pwd, err := usrs.HashPassword(attrUser.Password)
-			if err != nil {
-				lg.Error(err)
-				return err
-			}
-			attrUser.Password = pwd
-			attrUser.HashFunction = usrs.HASHFUNCTION
+		// A hash function only applies to a password given in the attributes
+		if user.Password != "" {
+			attrUser.HashFunction = ""
 		}
 
 		err = mergo.Merge(user, attrUser)
@@ -149,6 +148,16 @@ func doCreateUser(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	hasher, err := passwordHasher(cmd)
+	if err != nil {
+		return err
+	}
+
+	err = pwds.Prepare(user, hasher)
+	if err != nil {
+		return err
+	}
+
 	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryUserScope)
 	if err != nil {
 		return err
@@ -177,6 +186,7 @@ func init() {
 	createUserCmd.Flags().StringVarP(&firstName, flgnm.FLG_FIRSTNAME, "f", "", "user's first name")
 	createUserCmd.Flags().StringVar(&forceSend, flgnm.FLG_FORCE, "", "field list for ForceSendFields separated by (~)")
 	createUserCmd.Flags().BoolVarP(&gal, flgnm.FLG_GAL, "g", false, "user is included in Global Address List")
+	createUserCmd.Flags().StringVar(&hasher, flgnm.FLG_HASHER, "", "password hasher (crypt, md5 or sha-1)")
 	createUserCmd.Flags().StringVarP(&lastName, flgnm.FLG_LASTNAME, "l", "", "user's last name")
 	createUserCmd.Flags().StringVarP(&orgUnit, flgnm.FLG_ORGUNIT, "o", "", "user's orgunit")
 	createUserCmd.Flags().StringVarP(&password, flgnm.FLG_PASSWORD, "p", "", "user's password")
@@ -260,13 +270,7 @@ func cuPasswordFlag(user *admin.User, flagName string, flgVal string) error {
 		lg.Error(err)
 		return err
 	}
-	pwd, err := usrs.HashPassword(flgVal)
-	if err != nil {
-		lg.Error(err)
-		return err
-	}
-	user.Password = pwd
-	user.HashFunction = usrs.HASHFUNCTION
+	user.Password = flgVal
 	lg.Debug("finished cuPasswordFlag()")
 	return nil
 }
@@ -24,8 +24,65 @@ package cmd
 
 import (
 	"testing"
+
+	admin "google.golang.org/api/admin/directory/v1"
 )
 
 func TestDoDeleteMember(t *testing.T) {
+	fs := newFakeServer(t)
+	fs.AddGroup(&admin.Group{Email: "cartoons@disney.com", Name: "Cartoons"})
+	fs.AddMember("cartoons@disney.com", &admin.Member{Email: "mickey.mouse@disney.com"})
+
+	cases := []struct {
+		args        []string
+		expectedErr string
+	}{
+		{
+			args: []string{"delete", "group-member", "mickey.mouse@disney.com", "cartoons@disney.com"},
+		},
+		{
+			args:        []string{"delete", "group-member", "mickey.mouse@disney.com", "cartoons@disney.com"},
+			expectedErr: "googleapi: Error 404: Resource Not Found: mickey.mouse@disney.com, notFound",
+		},
+	}
+
+	for _, c := range cases {
+		_, got := runGmin(t, c.args...)
+
+		gotErr := ""
+		if got != nil {
+			gotErr = got.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("Got error: %v - expected error: %v", gotErr, c.expectedErr)
+		}
+	}
+
+	if fs.Member("cartoons@disney.com", "mickey.mouse@disney.com") != nil {
+		t.Error("Got member: mickey.mouse@disney.com - expected member: nil")
+	}
+}
+
+func TestDeleteUndeleteUserFakeServer(t *testing.T) {
+	fs := newFakeServer(t)
+	fs.AddUser(&admin.User{PrimaryEmail: "mickey.mouse@disney.com", Name: &admin.UserName{GivenName: "Mickey", FamilyName: "Mouse"}})
+	fs.AddOrgUnit(&admin.OrgUnit{Name: "Characters"})
+	userID := fs.User("mickey.mouse@disney.com").Id
+
+	_, err := runGmin(t, "delete", "user", "mickey.mouse@disney.com")
+	if err != nil {
+		t.Fatalf("Got error: %v - expected error: nil", err)
+	}
+	if fs.User("mickey.mouse@disney.com") != nil || fs.DeletedUsers[userID] == nil {
+		t.Fatal("Got user: not deleted - expected user: deleted")
+	}
 
+	_, err = runGmin(t, "undelete", "user", userID, "-o", "/Characters")
+	if err != nil {
+		t.Fatalf("Got error: %v - expected error: nil", err)
+	}
+	user := fs.User("mickey.mouse@disney.com")
+	if user == nil || user.OrgUnitPath != "/Characters" {
+		t.Errorf("Got user: %v - expected user in orgunit: /Characters", user)
+	}
 }
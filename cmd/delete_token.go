@@ -0,0 +1,74 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var deleteTokenCmd = &cobra.Command{
+	Use:     "token <user email address, alias or id> <client id>",
+	Aliases: []string{"tok"},
+	Args:    cobra.ExactArgs(2),
+	Example: `gmin delete token mickey.mouse@disney.com 123456789.apps.googleusercontent.com
+gmin del tok mickey.mouse@disney.com 123456789.apps.googleusercontent.com`,
+	Short: "Deletes an OAuth token issued to a third-party application by a user",
+	Long: `Deletes an OAuth token issued to a third-party application by a user, revoking the application's access
+to the user's data.`,
+	RunE: doDeleteToken,
+}
+
+func doDeleteToken(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doDeleteToken()",
+		"args", args)
+	defer lg.Debug("finished doDeleteToken()")
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryUserSecurityScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	tdc := ds.Tokens.Delete(args[0], args[1])
+
+	err = tdc.Do()
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_TOKENDELETED, args[1], args[0])))
+	lg.Infof(gmess.INFO_TOKENDELETED, args[1], args[0])
+
+	return nil
+}
+
+func init() {
+	deleteCmd.AddCommand(deleteTokenCmd)
+}
@@ -0,0 +1,413 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+
+	cdevs "github.com/plusworx/gmin/utils/chromeosdevices"
+	cmn "github.com/plusworx/gmin/utils/common"
+	cfg "github.com/plusworx/gmin/utils/config"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	grps "github.com/plusworx/gmin/utils/groups"
+	grpset "github.com/plusworx/gmin/utils/groupsettings"
+	lg "github.com/plusworx/gmin/utils/logging"
+	mems "github.com/plusworx/gmin/utils/members"
+	mdevs "github.com/plusworx/gmin/utils/mobiledevices"
+	ous "github.com/plusworx/gmin/utils/orgunits"
+	scs "github.com/plusworx/gmin/utils/schemas"
+	snap "github.com/plusworx/gmin/utils/snapshot"
+	usrs "github.com/plusworx/gmin/utils/users"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+	gset "google.golang.org/api/groupssettings/v1"
+)
+
+var exportCmd = &cobra.Command{
+	Use:  "export",
+	Args: cobra.NoArgs,
+	Example: `gmin export
+gmin export -d /backups/gmin/20201201`,
+	Short: "Exports directory objects to a snapshot directory",
+	Long: `Exports schemas, orgunits, users (including custom schema values), groups, group settings, group members,
+user and group aliases, ChromeOS devices and mobile devices to a snapshot directory.
+
+Each object type is written to its own file with one JSON object per line (e.g. users.jsonl) and
+manifest.json gives the snapshot format version, gmin version, customer ID, start and completion times and
+the number of objects in each file. Group members are written with the email address of their group and
+aliases are written with their primary email address and type (user or group).
+
+If a directory isn't given then a directory called gmin_export_<timestamp> is created in the current
+directory. The directory must be empty or not exist.`,
+	RunE: doExport,
+}
+
+func doExport(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doExport()",
+		"args", args)
+	defer lg.Debug("finished doExport()")
+
+	flgDirVal, err := cmd.Flags().GetString(flgnm.FLG_DIR)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgDirVal == "" {
+		flgDirVal = "gmin_export_" + time.Now().Format(snap.TIMEFORMAT)
+	}
+
+	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
+	if err != nil {
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryDeviceChromeosReadonlyScope,
+		admin.AdminDirectoryDeviceMobileReadonlyScope, admin.AdminDirectoryGroupReadonlyScope,
+		admin.AdminDirectoryOrgunitReadonlyScope, admin.AdminDirectoryUserReadonlyScope,
+		admin.AdminDirectoryUserschemaReadonlyScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	srv, err = cmn.CreateService(cmn.SRVTYPEGRPSETTING, gset.AppsGroupsSettingsScope)
+	if err != nil {
+		return err
+	}
+	gss := srv.(*gset.Service)
+
+	w, err := snap.NewWriter(flgDirVal, customerID, rootCmd.Version)
+	if err != nil {
+		return err
+	}
+
+	err = exportObjects(ds, gss, w, customerID)
+	if err != nil {
+		w.Abort(err)
+		return err
+	}
+
+	err = w.Close()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_EXPORTCOMPLETED, flgDirVal)))
+	lg.Infof(gmess.INFO_EXPORTCOMPLETED, flgDirVal)
+
+	return nil
+}
+
+func exportObjects(ds *admin.Service, gss *gset.Service, w *snap.Writer, customerID string) error {
+	lg.Debug("starting exportObjects()")
+	defer lg.Debug("finished exportObjects()")
+
+	var (
+		orgunits *admin.OrgUnits
+		schemas  *admin.Schemas
+	)
+
+	sclc := ds.Schemas.List(customerID)
+	err := exportRetry(snap.SCHEMAS, func() error {
+		var err error
+		schemas, err = scs.DoList(sclc)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	for _, schema := range schemas.Schemas {
+		err = w.Write(snap.SCHEMAS, schema)
+		if err != nil {
+			return err
+		}
+	}
+	exportProgress(w, snap.SCHEMAS)
+
+	oulc := ds.Orgunits.List(customerID)
+	oulc = ous.AddType(oulc, "all")
+	err = exportRetry(snap.ORGUNITS, func() error {
+		var err error
+		orgunits, err = ous.DoList(oulc)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	for _, ou := range orgunits.OrganizationUnits {
+		err = w.Write(snap.ORGUNITS, ou)
+		if err != nil {
+			return err
+		}
+	}
+	exportProgress(w, snap.ORGUNITS)
+
+	err = exportUsers(ds, w, customerID)
+	if err != nil {
+		return err
+	}
+
+	err = exportGroups(ds, gss, w, customerID)
+	if err != nil {
+		return err
+	}
+
+	err = exportDevices(ds, w, customerID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func exportDevices(ds *admin.Service, w *snap.Writer, customerID string) error {
+	lg.Debug("starting exportDevices()")
+	defer lg.Debug("finished exportDevices()")
+
+	cdlc := ds.Chromeosdevices.List(customerID)
+	listCall := cdevs.AddProjection(cdlc, "full")
+	cdlc = listCall.(*admin.ChromeosdevicesListCall)
+	for {
+		var crosdevs *admin.ChromeOsDevices
+		err := exportRetry(snap.CROSDEVICES, func() error {
+			var err error
+			crosdevs, err = cdevs.DoList(cdlc)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		for _, crosdev := range crosdevs.Chromeosdevices {
+			err = w.Write(snap.CROSDEVICES, crosdev)
+			if err != nil {
+				return err
+			}
+		}
+		if crosdevs.NextPageToken == "" {
+			break
+		}
+		cdlc = cdevs.AddPageToken(cdlc, crosdevs.NextPageToken)
+	}
+	exportProgress(w, snap.CROSDEVICES)
+
+	mdlc := ds.Mobiledevices.List(customerID)
+	listCall = mdevs.AddProjection(mdlc, "full")
+	mdlc = listCall.(*admin.MobiledevicesListCall)
+	for {
+		var mobdevs *admin.MobileDevices
+		err := exportRetry(snap.MOBDEVICES, func() error {
+			var err error
+			mobdevs, err = mdevs.DoList(mdlc)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		for _, mobdev := range mobdevs.Mobiledevices {
+			err = w.Write(snap.MOBDEVICES, mobdev)
+			if err != nil {
+				return err
+			}
+		}
+		if mobdevs.NextPageToken == "" {
+			break
+		}
+		mdlc = mdevs.AddPageToken(mdlc, mobdevs.NextPageToken)
+	}
+	exportProgress(w, snap.MOBDEVICES)
+
+	return nil
+}
+
+func exportGroups(ds *admin.Service, gss *gset.Service, w *snap.Writer, customerID string) error {
+	lg.Debug("starting exportGroups()")
+	defer lg.Debug("finished exportGroups()")
+
+	glc := ds.Groups.List()
+	glc = grps.AddCustomer(glc, customerID)
+	for {
+		var groups *admin.Groups
+		err := exportRetry(snap.GROUPS, func() error {
+			var err error
+			groups, err = grps.DoList(glc)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, group := range groups.Groups {
+			err = w.Write(snap.GROUPS, group)
+			if err != nil {
+				return err
+			}
+
+			for _, alias := range group.Aliases {
+				err = w.Write(snap.ALIASES, snap.Alias{Alias: alias, PrimaryEmail: group.Email, Type: snap.ALIASTYPEGROUP})
+				if err != nil {
+					return err
+				}
+			}
+
+			var settings *gset.Groups
+			gsgc := gss.Groups.Get(group.Email)
+			err = exportRetry(snap.GROUPSETTINGS, func() error {
+				var err error
+				settings, err = grpset.DoGet(gsgc)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			err = w.Write(snap.GROUPSETTINGS, settings)
+			if err != nil {
+				return err
+			}
+
+			mlc := ds.Members.List(group.Email)
+			for {
+				var members *admin.Members
+				err := exportRetry(snap.MEMBERS, func() error {
+					var err error
+					members, err = mems.DoList(mlc)
+					return err
+				})
+				if err != nil {
+					return err
+				}
+				for _, member := range members.Members {
+					err = w.Write(snap.MEMBERS, snap.Member{Group: group.Email, Member: member})
+					if err != nil {
+						return err
+					}
+				}
+				if members.NextPageToken == "" {
+					break
+				}
+				mlc = mems.AddPageToken(mlc, members.NextPageToken)
+			}
+		}
+
+		if groups.NextPageToken == "" {
+			break
+		}
+		glc = grps.AddPageToken(glc, groups.NextPageToken)
+	}
+	exportProgress(w, snap.GROUPS)
+	exportProgress(w, snap.GROUPSETTINGS)
+	exportProgress(w, snap.MEMBERS)
+	exportProgress(w, snap.ALIASES)
+
+	return nil
+}
+
+func exportProgress(w *snap.Writer, objType string) {
+	fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_EXPORTED, w.Count(objType), objType)))
+	lg.Infof(gmess.INFO_EXPORTED, w.Count(objType), objType)
+}
+
+// exportRetry calls fn, which gets objects of objType, with exponential backoff when it fails with a
+// retryable error
+func exportRetry(objType string, fn func() error) error {
+	lg.Debugw("starting exportRetry()",
+		"objType", objType)
+	defer lg.Debug("finished exportRetry()")
+
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = cmn.PAGEMAXRETRYTIME
+
+	err := backoff.Retry(func() error {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		if !cmn.IsErrRetryable(err) {
+			return backoff.Permanent(err)
+		}
+		// Log the retries
+		lg.Warnw(err.Error(),
+			"retrying", b.GetElapsedTime().String(),
+			"objType", objType)
+		return err
+	}, b)
+	if perr, ok := err.(*backoff.PermanentError); ok {
+		err = perr.Err
+	}
+	return err
+}
+
+func exportUsers(ds *admin.Service, w *snap.Writer, customerID string) error {
+	lg.Debug("starting exportUsers()")
+	defer lg.Debug("finished exportUsers()")
+
+	ulc := ds.Users.List()
+	ulc = usrs.AddCustomer(ulc, customerID)
+	// Full projection includes custom schema values
+	listCall := usrs.AddProjection(ulc, "full")
+	ulc = listCall.(*admin.UsersListCall)
+	for {
+		var users *admin.Users
+		err := exportRetry(snap.USERS, func() error {
+			var err error
+			users, err = usrs.DoList(ulc)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		for _, user := range users.Users {
+			err = w.Write(snap.USERS, user)
+			if err != nil {
+				return err
+			}
+
+			for _, alias := range user.Aliases {
+				err = w.Write(snap.ALIASES, snap.Alias{Alias: alias, PrimaryEmail: user.PrimaryEmail, Type: snap.ALIASTYPEUSER})
+				if err != nil {
+					return err
+				}
+			}
+		}
+		if users.NextPageToken == "" {
+			break
+		}
+		ulc = usrs.AddPageToken(ulc, users.NextPageToken)
+	}
+	exportProgress(w, snap.USERS)
+
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().StringVarP(&snapshotDir, flgnm.FLG_DIR, "d", "", "snapshot directory path")
+	exportCmd.Flags().StringVar(&logLevel, flgnm.FLG_LOGLEVEL, "info", "log level (debug, info, error, warn)")
+	exportCmd.Flags().BoolVar(&silent, flgnm.FLG_SILENT, false, "suppress console output")
+
+	exportCmd.PreRunE = preRun
+}
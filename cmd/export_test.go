@@ -0,0 +1,129 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	lg "github.com/plusworx/gmin/utils/logging"
+	snap "github.com/plusworx/gmin/utils/snapshot"
+	admin "google.golang.org/api/admin/directory/v1"
+	"google.golang.org/api/googleapi"
+)
+
+func TestExportFakeServer(t *testing.T) {
+	fs := newFakeServer(t)
+	fs.AddUser(&admin.User{PrimaryEmail: "mickey.mouse@disney.com", Aliases: []string{"mickey@disney.com"}})
+	fs.AddUser(&admin.User{PrimaryEmail: "donald.duck@disney.com"})
+	fs.AddGroup(&admin.Group{Email: "cartoons@disney.com", Name: "Cartoons"})
+	fs.AddMember("cartoons@disney.com", &admin.Member{Email: "mickey.mouse@disney.com", Role: "OWNER"})
+	fs.AddOrgUnit(&admin.OrgUnit{Name: "Characters"})
+	fs.AddCrOSDevice(&admin.ChromeOsDevice{DeviceId: "cros1", OrgUnitPath: "/"})
+
+	dir := filepath.Join(t.TempDir(), "export")
+
+	out, err := runGmin(t, "export", "-d", dir)
+	if err != nil {
+		t.Fatalf("Got error: %v - expected error: nil", err)
+	}
+	if !strings.Contains(out, "export written to: "+dir) {
+		t.Errorf("Got output: %v - expected output to contain: export written to: %v", out, dir)
+	}
+
+	manifest, err := snap.ReadManifest(dir)
+	if err != nil {
+		t.Fatalf("Got error: %v - expected error: nil", err)
+	}
+	if manifest.CustomerID != "my_customer" {
+		t.Errorf("Got customer ID: %v - expected customer ID: my_customer", manifest.CustomerID)
+	}
+
+	expCounts := map[string]int{
+		snap.ALIASES:       1,
+		snap.CROSDEVICES:   1,
+		snap.GROUPS:        1,
+		snap.GROUPSETTINGS: 1,
+		snap.MEMBERS:       1,
+		snap.MOBDEVICES:    0,
+		snap.ORGUNITS:      1,
+		snap.SCHEMAS:       0,
+		snap.USERS:         2,
+	}
+	for objType, expCount := range expCounts {
+		if manifest.Objects[objType].Count != expCount {
+			t.Errorf("Got %v count: %v - expected %v count: %v", objType, manifest.Objects[objType].Count, objType, expCount)
+		}
+	}
+
+	memData, _ := ioutil.ReadFile(filepath.Join(dir, manifest.Objects[snap.MEMBERS].File))
+	if !strings.Contains(string(memData), `"group":"cartoons@disney.com"`) {
+		t.Errorf("Got members data: %v - expected members data to contain: \"group\":\"cartoons@disney.com\"", string(memData))
+	}
+
+	_, err = runGmin(t, "export", "-d", dir)
+	if err == nil {
+		t.Errorf("Got error: nil - expected error: directory is not empty: %v", dir)
+	}
+}
+
+func TestExportRetry(t *testing.T) {
+	lg.InitLogging("info")
+
+	cases := []struct {
+		errs          []error
+		expectedCalls int
+		expectedErr   string
+	}{
+		{
+			errs:          []error{&googleapi.Error{Code: 429, Body: "rateLimitExceeded"}, nil},
+			expectedCalls: 2,
+		},
+		{
+			errs:          []error{&googleapi.Error{Code: 404, Message: "Resource Not Found: users"}, nil},
+			expectedCalls: 1,
+			expectedErr:   "googleapi: Error 404: Resource Not Found: users",
+		},
+	}
+
+	for _, c := range cases {
+		calls := 0
+		err := exportRetry(snap.USERS, func() error {
+			calls++
+			return c.errs[calls-1]
+		})
+
+		gotErr := ""
+		if err != nil {
+			gotErr = err.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("Got error: %v - expected error: %v", gotErr, c.expectedErr)
+		}
+		if calls != c.expectedCalls {
+			t.Errorf("Got calls: %v - expected calls: %v", calls, c.expectedCalls)
+		}
+	}
+}
@@ -42,6 +42,7 @@ func doGet(cmd *cobra.Command, args []string) {
 func init() {
 	rootCmd.AddCommand(getCmd)
 	getCmd.PersistentFlags().StringVar(&logLevel, flgnm.FLG_LOGLEVEL, "info", "log level (debug, info, error, warn)")
+	getCmd.PersistentFlags().StringVar(&output, flgnm.FLG_OUTPUT, "json", "output format (csv, json, jsonl, table, tsv, yaml)")
 
 	getCmd.PersistentPreRunE = preRunForDisplayCmds
 }
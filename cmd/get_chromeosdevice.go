@@ -23,14 +23,15 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
 
 	cdevs "github.com/plusworx/gmin/utils/chromeosdevices"
 	cmn "github.com/plusworx/gmin/utils/common"
 	cfg "github.com/plusworx/gmin/utils/config"
 	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	fmtrs "github.com/plusworx/gmin/utils/formatters"
 	gmess "github.com/plusworx/gmin/utils/gminmessages"
 	gpars "github.com/plusworx/gmin/utils/gminparsers"
 	lg "github.com/plusworx/gmin/utils/logging"
@@ -55,10 +56,15 @@ func doGetCrOSDev(cmd *cobra.Command, args []string) error {
 	defer lg.Debug("finished doGetCrOSDev()")
 
 	var (
-		jsonData []byte
-		crosdev  *admin.ChromeOsDevice
+		formattedAttrs string
+		crosdev        *admin.ChromeOsDevice
 	)
 
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
 	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryDeviceChromeosReadonlyScope)
 	if err != nil {
 		return err
@@ -77,7 +83,7 @@ func doGetCrOSDev(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	if flgAttrsVal != "" {
-		formattedAttrs, err := gpars.ParseOutputAttrs(flgAttrsVal, cdevs.CrOSDevAttrMap)
+		formattedAttrs, err = gpars.ParseOutputAttrs(flgAttrsVal, cdevs.CrOSDevAttrMap)
 		if err != nil {
 			return err
 		}
@@ -109,14 +115,11 @@ func doGetCrOSDev(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	jsonData, err = json.MarshalIndent(crosdev, "", "    ")
+	err = fmtrs.Output(os.Stdout, outputFmt, crosdev, "", formattedAttrs)
 	if err != nil {
-		lg.Error(err)
 		return err
 	}
 
-	fmt.Println(string(jsonData))
-
 	return nil
 }
 
@@ -0,0 +1,109 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"os"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	cfg "github.com/plusworx/gmin/utils/config"
+	doms "github.com/plusworx/gmin/utils/domains"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	fmtrs "github.com/plusworx/gmin/utils/formatters"
+	gpars "github.com/plusworx/gmin/utils/gminparsers"
+	lg "github.com/plusworx/gmin/utils/logging"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var getDomainCmd = &cobra.Command{
+	Use:     "domain <domain name>",
+	Aliases: []string{"dom"},
+	Args:    cobra.ExactArgs(1),
+	Example: `gmin get domain mycompany.com
+gmin get dom mycompany.com -a verified~isprimary`,
+	Short: "Outputs information about a domain",
+	Long:  `Outputs information about a domain.`,
+	RunE:  doGetDomain,
+}
+
+func doGetDomain(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doGetDomain()",
+		"args", args)
+	defer lg.Debug("finished doGetDomain()")
+
+	var (
+		domain         *admin.Domains
+		formattedAttrs string
+	)
+
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryDomainReadonlyScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
+	if err != nil {
+		return err
+	}
+
+	dgc := ds.Domains.Get(customerID, args[0])
+
+	flgAttrsVal, err := cmd.Flags().GetString(flgnm.FLG_ATTRIBUTES)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgAttrsVal != "" {
+		formattedAttrs, err = gpars.ParseOutputAttrs(flgAttrsVal, doms.DomainAttrMap)
+		if err != nil {
+			return err
+		}
+		getCall := doms.AddFields(dgc, formattedAttrs)
+		dgc = getCall.(*admin.DomainsGetCall)
+	}
+
+	domain, err = doms.DoGet(dgc)
+	if err != nil {
+		return err
+	}
+
+	err = fmtrs.Output(os.Stdout, outputFmt, domain, "", formattedAttrs)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func init() {
+	getCmd.AddCommand(getDomainCmd)
+
+	getDomainCmd.Flags().StringVarP(&attrs, flgnm.FLG_ATTRIBUTES, "a", "", "required domain attributes (separated by ~)")
+}
@@ -23,11 +23,11 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
-	"fmt"
+	"os"
 
 	cmn "github.com/plusworx/gmin/utils/common"
 	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	fmtrs "github.com/plusworx/gmin/utils/formatters"
 	gpars "github.com/plusworx/gmin/utils/gminparsers"
 	grps "github.com/plusworx/gmin/utils/groups"
 	lg "github.com/plusworx/gmin/utils/logging"
@@ -52,10 +52,15 @@ func doGetGroup(cmd *cobra.Command, args []string) error {
 	defer lg.Debug("finished doGetGroup()")
 
 	var (
-		jsonData []byte
-		group    *admin.Group
+		formattedAttrs string
+		group          *admin.Group
 	)
 
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
 	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryGroupReadonlyScope)
 	if err != nil {
 		return err
@@ -70,7 +75,7 @@ func doGetGroup(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	if flgAttrsVal != "" {
-		formattedAttrs, err := gpars.ParseOutputAttrs(flgAttrsVal, grps.GroupAttrMap)
+		formattedAttrs, err = gpars.ParseOutputAttrs(flgAttrsVal, grps.GroupAttrMap)
 		if err != nil {
 			return err
 		}
@@ -84,14 +89,11 @@ func doGetGroup(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	jsonData, err = json.MarshalIndent(group, "", "    ")
+	err = fmtrs.Output(os.Stdout, outputFmt, group, "", formattedAttrs)
 	if err != nil {
-		lg.Error(err)
 		return err
 	}
 
-	fmt.Println(string(jsonData))
-
 	return nil
 }
 
@@ -23,11 +23,11 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
-	"fmt"
+	"os"
 
 	cmn "github.com/plusworx/gmin/utils/common"
 	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	fmtrs "github.com/plusworx/gmin/utils/formatters"
 	gpars "github.com/plusworx/gmin/utils/gminparsers"
 	grpset "github.com/plusworx/gmin/utils/groupsettings"
 	lg "github.com/plusworx/gmin/utils/logging"
@@ -52,10 +52,15 @@ func doGetGroupSettings(cmd *cobra.Command, args []string) error {
 	defer lg.Debug("finished doGetGroupSettings()")
 
 	var (
-		jsonData []byte
-		group    *gset.Groups
+		formattedAttrs string
+		group          *gset.Groups
 	)
 
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
 	srv, err := cmn.CreateService(cmn.SRVTYPEGRPSETTING, gset.AppsGroupsSettingsScope)
 	if err != nil {
 		return err
@@ -70,7 +75,7 @@ func doGetGroupSettings(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	if flgAttrsVal != "" {
-		formattedAttrs, err := gpars.ParseOutputAttrs(flgAttrsVal, grpset.GroupSettingsAttrMap)
+		formattedAttrs, err = gpars.ParseOutputAttrs(flgAttrsVal, grpset.GroupSettingsAttrMap)
 		if err != nil {
 			return err
 		}
@@ -84,14 +89,11 @@ func doGetGroupSettings(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	jsonData, err = json.MarshalIndent(group, "", "    ")
+	err = fmtrs.Output(os.Stdout, outputFmt, group, "", formattedAttrs)
 	if err != nil {
-		lg.Error(err)
 		return err
 	}
 
-	fmt.Println(string(jsonData))
-
 	return nil
 }
 
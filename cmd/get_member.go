@@ -23,11 +23,11 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
-	"fmt"
+	"os"
 
 	cmn "github.com/plusworx/gmin/utils/common"
 	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	fmtrs "github.com/plusworx/gmin/utils/formatters"
 	gpars "github.com/plusworx/gmin/utils/gminparsers"
 	lg "github.com/plusworx/gmin/utils/logging"
 	mems "github.com/plusworx/gmin/utils/members"
@@ -50,7 +50,10 @@ func doGetMember(cmd *cobra.Command, args []string) error {
 	lg.Debugw("starting doGetMember()",
 		"args", args)
 
-	var jsonData []byte
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
 
 	flgAttrsVal, err := cmd.Flags().GetString(flgnm.FLG_ATTRIBUTES)
 	if err != nil {
@@ -58,13 +61,16 @@ func doGetMember(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	jsonData, err = processGroupMember(args[0], attrs, args[1], flgAttrsVal)
+	member, formattedAttrs, err := processGroupMember(args[0], attrs, args[1], flgAttrsVal)
 	if err != nil {
 		lg.Error(err)
 		return err
 	}
 
-	fmt.Println(string(jsonData))
+	err = fmtrs.Output(os.Stdout, outputFmt, member, "", formattedAttrs)
+	if err != nil {
+		return err
+	}
 
 	lg.Debug("finished doGetMember()")
 	return nil
@@ -75,7 +81,7 @@ func init() {
 	getMemberCmd.Flags().StringVarP(&attrs, flgnm.FLG_ATTRIBUTES, "a", "", "required group attributes (separated by ~)")
 }
 
-func processGroupMember(memID string, attrs string, groupEmail string, flgAttrsVal string) ([]byte, error) {
+func processGroupMember(memID string, attrs string, groupEmail string, flgAttrsVal string) (*admin.Member, string, error) {
 	lg.Debugw("starting processGroupMember()",
 		"flgAttrsVal", flgAttrsVal,
 		"groupEmail", groupEmail,
@@ -83,22 +89,22 @@ func processGroupMember(memID string, attrs string, groupEmail string, flgAttrsV
 	defer lg.Debug("finished processGroupMember()")
 
 	var (
-		jsonData []byte
-		member   *admin.Member
+		formattedAttrs string
+		member         *admin.Member
 	)
 
 	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryGroupMemberReadonlyScope)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	ds := srv.(*admin.Service)
 
 	mgc := ds.Members.Get(groupEmail, memID)
 
 	if flgAttrsVal != "" {
-		formattedAttrs, err := gpars.ParseOutputAttrs(flgAttrsVal, mems.MemberAttrMap)
+		formattedAttrs, err = gpars.ParseOutputAttrs(flgAttrsVal, mems.MemberAttrMap)
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 
 		getCall := mems.AddFields(mgc, formattedAttrs)
@@ -107,14 +113,8 @@ func processGroupMember(memID string, attrs string, groupEmail string, flgAttrsV
 
 	member, err = mems.DoGet(mgc)
 	if err != nil {
-		return nil, err
-	}
-
-	jsonData, err = json.MarshalIndent(member, "", "    ")
-	if err != nil {
-		lg.Error(err)
-		return nil, err
+		return nil, "", err
 	}
 
-	return jsonData, nil
+	return member, formattedAttrs, nil
 }
@@ -23,13 +23,14 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
 
 	cmn "github.com/plusworx/gmin/utils/common"
 	cfg "github.com/plusworx/gmin/utils/config"
 	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	fmtrs "github.com/plusworx/gmin/utils/formatters"
 	gmess "github.com/plusworx/gmin/utils/gminmessages"
 	gpars "github.com/plusworx/gmin/utils/gminparsers"
 	lg "github.com/plusworx/gmin/utils/logging"
@@ -55,10 +56,15 @@ func doGetMobDev(cmd *cobra.Command, args []string) error {
 	defer lg.Debug("finished doGetMobDev()")
 
 	var (
-		jsonData []byte
-		mobdev   *admin.MobileDevice
+		formattedAttrs string
+		mobdev         *admin.MobileDevice
 	)
 
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
 	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryDeviceMobileReadonlyScope)
 	if err != nil {
 		return err
@@ -77,7 +83,7 @@ func doGetMobDev(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	if flgAttrsVal != "" {
-		formattedAttrs, err := gpars.ParseOutputAttrs(flgAttrsVal, mdevs.MobDevAttrMap)
+		formattedAttrs, err = gpars.ParseOutputAttrs(flgAttrsVal, mdevs.MobDevAttrMap)
 		if err != nil {
 			return err
 		}
@@ -109,14 +115,11 @@ func doGetMobDev(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	jsonData, err = json.MarshalIndent(mobdev, "", "    ")
+	err = fmtrs.Output(os.Stdout, outputFmt, mobdev, "", formattedAttrs)
 	if err != nil {
-		lg.Error(err)
 		return err
 	}
 
-	fmt.Println(string(jsonData))
-
 	return nil
 }
 
@@ -23,12 +23,12 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
-	"fmt"
+	"os"
 
 	cmn "github.com/plusworx/gmin/utils/common"
 	cfg "github.com/plusworx/gmin/utils/config"
 	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	fmtrs "github.com/plusworx/gmin/utils/formatters"
 	gpars "github.com/plusworx/gmin/utils/gminparsers"
 	lg "github.com/plusworx/gmin/utils/logging"
 	ous "github.com/plusworx/gmin/utils/orgunits"
@@ -53,10 +53,15 @@ func doGetOrgUnit(cmd *cobra.Command, args []string) error {
 	defer lg.Debug("finished doGetOrgUnit()")
 
 	var (
-		jsonData []byte
-		orgUnit  *admin.OrgUnit
+		formattedAttrs string
+		orgUnit        *admin.OrgUnit
 	)
 
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
 	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryOrgunitReadonlyScope)
 	if err != nil {
 		return err
@@ -82,7 +87,7 @@ func doGetOrgUnit(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	if flgAttrsVal != "" {
-		formattedAttrs, err := gpars.ParseOutputAttrs(flgAttrsVal, ous.OrgUnitAttrMap)
+		formattedAttrs, err = gpars.ParseOutputAttrs(flgAttrsVal, ous.OrgUnitAttrMap)
 		if err != nil {
 			return err
 		}
@@ -95,14 +100,11 @@ func doGetOrgUnit(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	jsonData, err = json.MarshalIndent(orgUnit, "", "    ")
+	err = fmtrs.Output(os.Stdout, outputFmt, orgUnit, "", formattedAttrs)
 	if err != nil {
-		lg.Error(err)
 		return err
 	}
 
-	fmt.Println(string(jsonData))
-
 	return nil
 }
 
@@ -23,12 +23,12 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
-	"fmt"
+	"os"
 
 	cmn "github.com/plusworx/gmin/utils/common"
 	cfg "github.com/plusworx/gmin/utils/config"
 	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	fmtrs "github.com/plusworx/gmin/utils/formatters"
 	gpars "github.com/plusworx/gmin/utils/gminparsers"
 	lg "github.com/plusworx/gmin/utils/logging"
 	scs "github.com/plusworx/gmin/utils/schemas"
@@ -53,10 +53,15 @@ func doGetSchema(cmd *cobra.Command, args []string) error {
 	defer lg.Debug("finished doGetSchema()")
 
 	var (
-		jsonData []byte
-		schema   *admin.Schema
+		formattedAttrs string
+		schema         *admin.Schema
 	)
 
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
 	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryUserschemaReadonlyScope)
 	if err != nil {
 		return err
@@ -76,7 +81,7 @@ func doGetSchema(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	if flgAttrsVal != "" {
-		formattedAttrs, err := gpars.ParseOutputAttrs(flgAttrsVal, scs.SchemaAttrMap)
+		formattedAttrs, err = gpars.ParseOutputAttrs(flgAttrsVal, scs.SchemaAttrMap)
 		if err != nil {
 			return err
 		}
@@ -89,14 +94,11 @@ func doGetSchema(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	jsonData, err = json.MarshalIndent(schema, "", "    ")
+	err = fmtrs.Output(os.Stdout, outputFmt, schema, "", formattedAttrs)
 	if err != nil {
-		lg.Error(err)
 		return err
 	}
 
-	fmt.Println(string(jsonData))
-
 	return nil
 }
 
@@ -0,0 +1,94 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+func TestGetCmdsFakeServer(t *testing.T) {
+	fs := newFakeServer(t)
+	fs.AddUser(&admin.User{PrimaryEmail: "mickey.mouse@disney.com", Name: &admin.UserName{GivenName: "Mickey", FamilyName: "Mouse"}, OrgUnitPath: "/Characters"})
+	fs.AddGroup(&admin.Group{Email: "cartoons@disney.com", Name: "Cartoons"})
+	fs.AddMember("cartoons@disney.com", &admin.Member{Email: "mickey.mouse@disney.com", Role: "MANAGER"})
+	fs.AddOrgUnit(&admin.OrgUnit{Name: "Characters"})
+	fs.AddDomain(&admin.Domains{DomainName: "disney.com", IsPrimary: true})
+	fs.AddDomainAlias(&admin.DomainAlias{DomainAliasName: "disney.co.uk", ParentDomainName: "disney.com"})
+
+	cases := []struct {
+		args        []string
+		expectedErr string
+		expectedOut string
+	}{
+		{
+			args:        []string{"get", "user", "mickey.mouse@disney.com", "-a", "primaryemail~orgunitpath", "--output", "csv"},
+			expectedOut: "primaryEmail,orgUnitPath\nmickey.mouse@disney.com,/Characters\n",
+		},
+		{
+			args:        []string{"get", "user", "minnie.mouse@disney.com"},
+			expectedErr: "googleapi: Error 404: Resource Not Found: minnie.mouse@disney.com, notFound",
+		},
+		{
+			args:        []string{"get", "group", "cartoons@disney.com", "-a", "name", "--output", "jsonl"},
+			expectedOut: "{\"name\":\"Cartoons\"}\n",
+		},
+		{
+			args:        []string{"get", "group-member", "mickey.mouse@disney.com", "cartoons@disney.com", "-a", "role", "--output", "tsv"},
+			expectedOut: "role\nMANAGER\n",
+		},
+		{
+			args:        []string{"get", "group-settings", "cartoons@disney.com", "-a", "whocanjoin", "--output", "csv"},
+			expectedOut: "whoCanJoin\nCAN_REQUEST_TO_JOIN\n",
+		},
+		{
+			args:        []string{"get", "orgunit", "Characters", "-a", "orgunitpath", "--output", "csv"},
+			expectedOut: "orgUnitPath\n/Characters\n",
+		},
+		{
+			args:        []string{"get", "domain", "disney.com", "-a", "domainname~isprimary", "--output", "csv"},
+			expectedOut: "domainName,isPrimary\ndisney.com,true\n",
+		},
+		{
+			args:        []string{"get", "domain-alias", "disney.co.uk", "-a", "parentdomainname", "--output", "csv"},
+			expectedOut: "parentDomainName\ndisney.com\n",
+		},
+	}
+
+	for _, c := range cases {
+		out, got := runGmin(t, c.args...)
+
+		gotErr := ""
+		if got != nil {
+			gotErr = got.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("Got error: %v - expected error: %v", gotErr, c.expectedErr)
+		}
+		if c.expectedErr == "" && !strings.HasPrefix(out, c.expectedOut) {
+			t.Errorf("Got output: %v - expected output: %v", out, c.expectedOut)
+		}
+	}
+}
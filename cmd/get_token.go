@@ -0,0 +1,103 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"os"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	fmtrs "github.com/plusworx/gmin/utils/formatters"
+	gpars "github.com/plusworx/gmin/utils/gminparsers"
+	lg "github.com/plusworx/gmin/utils/logging"
+	usec "github.com/plusworx/gmin/utils/usersecurity"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var getTokenCmd = &cobra.Command{
+	Use:     "token <user email address, alias or id> <client id>",
+	Aliases: []string{"tok"},
+	Args:    cobra.ExactArgs(2),
+	Example: `gmin get token mickey.mouse@disney.com 123456789.apps.googleusercontent.com
+gmin get tok mickey.mouse@disney.com 123456789.apps.googleusercontent.com -a displaytext~scopes`,
+	Short: "Outputs information about an OAuth token issued to a third-party application by a user",
+	Long:  `Outputs information about an OAuth token issued to a third-party application by a user.`,
+	RunE:  doGetToken,
+}
+
+func doGetToken(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doGetToken()",
+		"args", args)
+	defer lg.Debug("finished doGetToken()")
+
+	var (
+		formattedAttrs string
+		token          *admin.Token
+	)
+
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryUserSecurityScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	tgc := ds.Tokens.Get(args[0], args[1])
+
+	flgAttrsVal, err := cmd.Flags().GetString(flgnm.FLG_ATTRIBUTES)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgAttrsVal != "" {
+		formattedAttrs, err = gpars.ParseOutputAttrs(flgAttrsVal, usec.TokenAttrMap)
+		if err != nil {
+			return err
+		}
+		getCall := usec.AddFields(tgc, formattedAttrs)
+		tgc = getCall.(*admin.TokensGetCall)
+	}
+
+	token, err = usec.DoGetToken(tgc)
+	if err != nil {
+		return err
+	}
+
+	err = fmtrs.Output(os.Stdout, outputFmt, token, "", formattedAttrs)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func init() {
+	getCmd.AddCommand(getTokenCmd)
+
+	getTokenCmd.Flags().StringVarP(&attrs, flgnm.FLG_ATTRIBUTES, "a", "", "required token attributes (separated by ~)")
+}
@@ -23,13 +23,14 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 
 	cmn "github.com/plusworx/gmin/utils/common"
 	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	fmtrs "github.com/plusworx/gmin/utils/formatters"
 	gmess "github.com/plusworx/gmin/utils/gminmessages"
 	gpars "github.com/plusworx/gmin/utils/gminparsers"
 	lg "github.com/plusworx/gmin/utils/logging"
@@ -54,10 +55,15 @@ func doGetUser(cmd *cobra.Command, args []string) error {
 		"args", args)
 
 	var (
-		jsonData []byte
-		user     *admin.User
+		formattedAttrs string
+		user           *admin.User
 	)
 
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
 	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryUserReadonlyScope)
 	if err != nil {
 		return err
@@ -72,7 +78,7 @@ func doGetUser(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	if flgAttrsVal != "" {
-		formattedAttrs, err := gpars.ParseOutputAttrs(flgAttrsVal, usrs.UserAttrMap)
+		formattedAttrs, err = gpars.ParseOutputAttrs(flgAttrsVal, usrs.UserAttrMap)
 		if err != nil {
 			lg.Error(err)
 			return err
@@ -141,14 +147,11 @@ func doGetUser(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	jsonData, err = json.MarshalIndent(user, "", "    ")
+	err = fmtrs.Output(os.Stdout, outputFmt, user, "", formattedAttrs)
 	if err != nil {
-		lg.Error(err)
 		return err
 	}
 
-	fmt.Println(string(jsonData))
-
 	lg.Debug("finished doGetUser()")
 	return nil
 }
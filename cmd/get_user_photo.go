@@ -0,0 +1,122 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	fmtrs "github.com/plusworx/gmin/utils/formatters"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	gpars "github.com/plusworx/gmin/utils/gminparsers"
+	lg "github.com/plusworx/gmin/utils/logging"
+	usrs "github.com/plusworx/gmin/utils/users"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var getUserPhotoCmd = &cobra.Command{
+	Use:     "user-photo <user email address, alias or id>",
+	Aliases: []string{"usr-photo", "uphoto"},
+	Args:    cobra.ExactArgs(1),
+	Example: `gmin get user-photo mickey.mouse@disney.com -a height~width~mimetype
+gmin get uphoto mickey.mouse@disney.com --file mickey.jpg`,
+	Short: "Outputs information about a user photo or saves it to a file",
+	Long:  `Outputs information about a user photo or, if --file is provided, saves the photo image to a file.`,
+	RunE:  doGetUserPhoto,
+}
+
+func doGetUserPhoto(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doGetUserPhoto()",
+		"args", args)
+	defer lg.Debug("finished doGetUserPhoto()")
+
+	var (
+		formattedAttrs string
+		photo          *admin.UserPhoto
+	)
+
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryUserReadonlyScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	upgc := ds.Users.Photos.Get(args[0])
+
+	flgFileVal, err := cmd.Flags().GetString(flgnm.FLG_FILE)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	flgAttrsVal, err := cmd.Flags().GetString(flgnm.FLG_ATTRIBUTES)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgAttrsVal != "" && flgFileVal == "" {
+		formattedAttrs, err = gpars.ParseOutputAttrs(flgAttrsVal, usrs.PhotoAttrMap)
+		if err != nil {
+			return err
+		}
+		getCall := usrs.AddFields(upgc, formattedAttrs)
+		upgc = getCall.(*admin.UsersPhotosGetCall)
+	}
+
+	photo, err = usrs.DoGetPhoto(upgc)
+	if err != nil {
+		return err
+	}
+
+	if flgFileVal != "" {
+		err = usrs.SavePhoto(photo, flgFileVal)
+		if err != nil {
+			return err
+		}
+		fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_PHOTOSAVED, args[0], flgFileVal)))
+		lg.Infof(gmess.INFO_PHOTOSAVED, args[0], flgFileVal)
+		return nil
+	}
+
+	err = fmtrs.Output(os.Stdout, outputFmt, photo, "", formattedAttrs)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func init() {
+	getCmd.AddCommand(getUserPhotoCmd)
+
+	getUserPhotoCmd.Flags().StringVarP(&attrs, flgnm.FLG_ATTRIBUTES, "a", "", "required user photo attributes (separated by ~)")
+	getUserPhotoCmd.Flags().StringVarP(&photoFile, flgnm.FLG_FILE, "", "", "file path to save photo image to")
+}
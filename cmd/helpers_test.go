@@ -0,0 +1,118 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	fake "github.com/plusworx/gmin/tests/fakeserver"
+	cfg "github.com/plusworx/gmin/utils/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// newFakeServer starts a fake API server and points gmin configuration at it
+func newFakeServer(t *testing.T) *fake.Server {
+	t.Helper()
+
+	fs := fake.New()
+
+	initConfig()
+	viper.Set(cfg.CONFIGADMIN, "admin@example.com")
+	viper.Set(cfg.CONFIGCUSTID, "my_customer")
+	viper.Set(cfg.CONFIGENDPOINT, fs.URL)
+	viper.Set(cfg.CONFIGLOGPATH, t.TempDir())
+	viper.Set(cfg.CONFIGLOGROTATIONCOUNT, 1)
+	viper.Set(cfg.CONFIGLOGROTATIONTIME, 86400)
+
+	t.Cleanup(func() {
+		viper.Set(cfg.CONFIGENDPOINT, "")
+		fs.Close()
+	})
+	return fs
+}
+
+// resetFlags returns all command flags to their default values and unset state so that commands
+// can be run repeatedly
+func resetFlags(cmd *cobra.Command) {
+	var (
+		local      []*pflag.Flag
+		persistent []*pflag.Flag
+	)
+
+	cmd.LocalNonPersistentFlags().VisitAll(func(f *pflag.Flag) {
+		local = append(local, f)
+	})
+	cmd.PersistentFlags().VisitAll(func(f *pflag.Flag) {
+		persistent = append(persistent, f)
+	})
+
+	// Flag sets are recreated because pflag has no way of clearing the flags visited by Visit
+	cmd.ResetFlags()
+	for _, f := range local {
+		f.Value.Set(f.DefValue)
+		f.Changed = false
+		cmd.Flags().AddFlag(f)
+	}
+	for _, f := range persistent {
+		f.Value.Set(f.DefValue)
+		f.Changed = false
+		cmd.PersistentFlags().AddFlag(f)
+	}
+
+	for _, c := range cmd.Commands() {
+		resetFlags(c)
+	}
+}
+
+// runGmin executes a gmin command line and returns what was written to standard output
+func runGmin(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	resetFlags(rootCmd)
+	rootCmd.SetArgs(args)
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	origStdout := os.Stdout
+	os.Stdout = w
+
+	outC := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		outC <- buf.String()
+	}()
+
+	_, err = rootCmd.ExecuteC()
+
+	w.Close()
+	os.Stdout = origStdout
+	return <-outC, err
+}
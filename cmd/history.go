@@ -0,0 +1,94 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"os"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	fmtrs "github.com/plusworx/gmin/utils/formatters"
+	lg "github.com/plusworx/gmin/utils/logging"
+	"github.com/spf13/cobra"
+)
+
+var historyCmd = &cobra.Command{
+	Use:  "history",
+	Args: cobra.NoArgs,
+	Example: `gmin history
+gmin history -m 5 --output json`,
+	Short: "Outputs a list of recent runs that made changes",
+	Long: `Outputs a list of recent runs that made changes, most recent first, giving the run ID, time, command and
+number of changes made.
+
+Before each change is made the object being changed is recorded in a journal in the log path, so that the
+run can be reverted with the undo command.`,
+	RunE: doHistory,
+}
+
+func doHistory(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doHistory()",
+		"args", args)
+	defer lg.Debug("finished doHistory()")
+
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	flgMaxResultsVal, err := cmd.Flags().GetInt64(flgnm.FLG_MAXRESULTS)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	journalDir, err := cmn.JournalDir()
+	if err != nil {
+		return err
+	}
+
+	runs, err := cmn.JournalRuns(journalDir)
+	if err != nil {
+		return err
+	}
+	if flgMaxResultsVal > 0 && int64(len(runs)) > flgMaxResultsVal {
+		runs = runs[:flgMaxResultsVal]
+	}
+
+	err = fmtrs.Output(os.Stdout, outputFmt, map[string]interface{}{"runs": runs}, "runs", "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+
+	historyCmd.Flags().StringVar(&logLevel, flgnm.FLG_LOGLEVEL, "info", "log level (debug, info, error, warn)")
+	historyCmd.Flags().Int64VarP(&maxResults, flgnm.FLG_MAXRESULTS, "m", 20, "maximum number of runs to return (0 returns all)")
+	historyCmd.Flags().StringVar(&output, flgnm.FLG_OUTPUT, "table", "output format (csv, json, jsonl, table, tsv, yaml)")
+
+	historyCmd.PreRunE = preRunForDisplayCmds
+}
@@ -24,6 +24,9 @@ package cmd
 
 import (
 	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	fmtrs "github.com/plusworx/gmin/utils/formatters"
+	gpars "github.com/plusworx/gmin/utils/gminparsers"
+	lg "github.com/plusworx/gmin/utils/logging"
 	"github.com/spf13/cobra"
 )
 
@@ -32,17 +35,77 @@ var listCmd = &cobra.Command{
 	Aliases: []string{"ls"},
 	Args:    cobra.NoArgs,
 	Short:   "Outputs list of Google Workspace entities",
-	Long:    "Outputs list of Google Workspace entities.",
-	Run:     doList,
+	Long: `Outputs list of Google Workspace entities.
+
+The --where flag filters results after they have been fetched using an expression that can compare
+any attribute, including nested ones such as addresses.type, with =, !=, <, <=, >, >=, ~ (regular
+expression match) and !~. Expressions can be combined with and, or, not and brackets, exists(attribute)
+checks whether an attribute is set and now can have durations such as 90d or 12h added or subtracted.
+The --sort-by flag sorts results by one or more attributes separated by ~, each of which can end with
+:desc to sort in descending order. Attributes used by either flag must be included in --attributes
+when it is given.`,
+	Run: doList,
 }
 
 func doList(cmd *cobra.Command, args []string) {
 	cmd.Help()
 }
 
+// filterList applies the --where and --sort-by flags to the listKey results in obj
+func filterList(cmd *cobra.Command, obj interface{}, listKey string) error {
+	lg.Debugw("starting filterList()",
+		"listKey", listKey)
+	defer lg.Debug("finished filterList()")
+
+	whereExpr, sortKeys, err := listSelection(cmd)
+	if err != nil {
+		return err
+	}
+	return fmtrs.FilterList(obj, listKey, whereExpr, sortKeys)
+}
+
+// listSelection parses the --where and --sort-by flags of list commands
+func listSelection(cmd *cobra.Command) (gpars.WhereExpr, []gpars.SortKey, error) {
+	lg.Debug("starting listSelection()")
+	defer lg.Debug("finished listSelection()")
+
+	var (
+		sortKeys  []gpars.SortKey
+		whereExpr gpars.WhereExpr
+	)
+
+	flgWhereVal, err := cmd.Flags().GetString(flgnm.FLG_WHERE)
+	if err != nil {
+		lg.Error(err)
+		return nil, nil, err
+	}
+	if flgWhereVal != "" {
+		whereExpr, err = gpars.ParseWhere(flgWhereVal)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	flgSortByVal, err := cmd.Flags().GetString(flgnm.FLG_SORTBY)
+	if err != nil {
+		lg.Error(err)
+		return nil, nil, err
+	}
+	if flgSortByVal != "" {
+		sortKeys, err = gpars.ParseSortBy(flgSortByVal)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return whereExpr, sortKeys, nil
+}
+
 func init() {
 	rootCmd.AddCommand(listCmd)
 	listCmd.PersistentFlags().StringVar(&logLevel, flgnm.FLG_LOGLEVEL, "info", "log level (debug, info, error, warn)")
+	listCmd.PersistentFlags().StringVar(&output, flgnm.FLG_OUTPUT, "json", "output format (csv, json, jsonl, table, tsv, yaml)")
+	listCmd.PersistentFlags().StringVar(&sortBy, flgnm.FLG_SORTBY, "", "attributes to sort results by (separated by ~)")
+	listCmd.PersistentFlags().StringVar(&where, flgnm.FLG_WHERE, "", "expression that results must match")
 
 	listCmd.PersistentPreRunE = preRunForDisplayCmds
 }
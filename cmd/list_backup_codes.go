@@ -0,0 +1,121 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	fmtrs "github.com/plusworx/gmin/utils/formatters"
+	gpars "github.com/plusworx/gmin/utils/gminparsers"
+	lg "github.com/plusworx/gmin/utils/logging"
+	usec "github.com/plusworx/gmin/utils/usersecurity"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var listBackupCodesCmd = &cobra.Command{
+	Use:     "backup-codes <user email address, alias or id>",
+	Aliases: []string{"backup-code", "bcodes", "bcode"},
+	Args:    cobra.ExactArgs(1),
+	Example: `gmin list backup-codes mickey.mouse@disney.com
+gmin ls bcodes mickey.mouse@disney.com -a verificationcode`,
+	Short: "Outputs a list of user 2-step verification backup codes",
+	Long:  `Outputs a list of the current set of valid 2-step verification backup codes for a user.`,
+	RunE:  doListBackupCodes,
+}
+
+func doListBackupCodes(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doListBackupCodes()",
+		"args", args)
+	defer lg.Debug("finished doListBackupCodes()")
+
+	var (
+		codes     *admin.VerificationCodes
+		listAttrs string
+	)
+
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryUserSecurityScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	vclc := ds.VerificationCodes.List(args[0])
+
+	flgAttrsVal, err := cmd.Flags().GetString(flgnm.FLG_ATTRIBUTES)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgAttrsVal != "" {
+		listAttrs, err = gpars.ParseOutputAttrs(flgAttrsVal, usec.VerCodeAttrMap)
+		if err != nil {
+			return err
+		}
+		formattedAttrs := usec.STARTITEMSFIELD + listAttrs + usec.ENDFIELD
+
+		listCall := usec.AddFields(vclc, formattedAttrs)
+		vclc = listCall.(*admin.VerificationCodesListCall)
+	}
+
+	codes, err = usec.DoListVerCodes(vclc)
+	if err != nil {
+		return err
+	}
+
+	err = filterList(cmd, codes, usec.LISTKEY)
+	if err != nil {
+		return err
+	}
+
+	flgCountVal, err := cmd.Flags().GetBool(flgnm.FLG_COUNT)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgCountVal {
+		fmt.Println(len(codes.Items))
+	} else {
+		err = fmtrs.Output(os.Stdout, outputFmt, codes, usec.LISTKEY, listAttrs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func init() {
+	listCmd.AddCommand(listBackupCodesCmd)
+
+	listBackupCodesCmd.Flags().StringVarP(&attrs, flgnm.FLG_ATTRIBUTES, "a", "", "required backup code attributes (separated by ~)")
+	listBackupCodesCmd.Flags().BoolVarP(&count, flgnm.FLG_COUNT, "", false, "count number of entities returned")
+}
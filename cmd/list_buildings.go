@@ -0,0 +1,228 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	cfg "github.com/plusworx/gmin/utils/config"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	fmtrs "github.com/plusworx/gmin/utils/formatters"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	gpars "github.com/plusworx/gmin/utils/gminparsers"
+	lg "github.com/plusworx/gmin/utils/logging"
+	rsrcs "github.com/plusworx/gmin/utils/resources"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var listBuildingsCmd = &cobra.Command{
+	Use:     "buildings",
+	Aliases: []string{"building", "bldgs", "bldg"},
+	Args:    cobra.NoArgs,
+	Example: `gmin list buildings -a buildingid~buildingname
+gmin ls bldgs -p all --count`,
+	Short: "Outputs a list of buildings",
+	Long:  `Outputs a list of buildings.`,
+	RunE:  doListBuildings,
+}
+
+func doListBuildings(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doListBuildings()",
+		"args", args)
+	defer lg.Debug("finished doListBuildings()")
+
+	var (
+		buildings *admin.Buildings
+		listAttrs string
+	)
+
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryResourceCalendarReadonlyScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
+	if err != nil {
+		return err
+	}
+
+	rblc := ds.Resources.Buildings.List(customerID)
+
+	flgAttrsVal, err := cmd.Flags().GetString(flgnm.FLG_ATTRIBUTES)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgAttrsVal != "" {
+		listAttrs, err = gpars.ParseOutputAttrs(flgAttrsVal, rsrcs.BuildingAttrMap)
+		if err != nil {
+			return err
+		}
+		formattedAttrs := rsrcs.STARTBUILDINGSFIELD + listAttrs + rsrcs.ENDFIELD
+
+		listCall := rsrcs.AddFields(rblc, formattedAttrs)
+		rblc = listCall.(*admin.ResourcesBuildingsListCall)
+	}
+
+	flgMaxResultsVal, err := cmd.Flags().GetInt64(flgnm.FLG_MAXRESULTS)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	listCall := rsrcs.AddMaxResults(rblc, flgMaxResultsVal)
+	rblc = listCall.(*admin.ResourcesBuildingsListCall)
+
+	buildings, err = rsrcs.DoListBuildings(rblc)
+	if err != nil {
+		return err
+	}
+
+	flgPagesVal, err := cmd.Flags().GetString(flgnm.FLG_PAGES)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgPagesVal != "" {
+		err = doBuildingPages(rblc, buildings, flgPagesVal)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = filterList(cmd, buildings, rsrcs.BUILDINGLISTKEY)
+	if err != nil {
+		return err
+	}
+
+	flgCountVal, err := cmd.Flags().GetBool(flgnm.FLG_COUNT)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgCountVal {
+		fmt.Println(len(buildings.Buildings))
+	} else {
+		err = fmtrs.Output(os.Stdout, outputFmt, buildings, rsrcs.BUILDINGLISTKEY, listAttrs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func doBuildingAllPages(rblc *admin.ResourcesBuildingsListCall, buildings *admin.Buildings) error {
+	lg.Debug("starting doBuildingAllPages()")
+	defer lg.Debug("finished doBuildingAllPages()")
+
+	if buildings.NextPageToken != "" {
+		listCall := rsrcs.AddPageToken(rblc, buildings.NextPageToken)
+		rblc = listCall.(*admin.ResourcesBuildingsListCall)
+		nxtBuildings, err := rsrcs.DoListBuildings(rblc)
+		if err != nil {
+			return err
+		}
+		buildings.Buildings = append(buildings.Buildings, nxtBuildings.Buildings...)
+		buildings.Etag = nxtBuildings.Etag
+		buildings.NextPageToken = nxtBuildings.NextPageToken
+
+		if nxtBuildings.NextPageToken != "" {
+			return doBuildingAllPages(rblc, buildings)
+		}
+	}
+
+	return nil
+}
+
+func doBuildingNumPages(rblc *admin.ResourcesBuildingsListCall, buildings *admin.Buildings, numPages int) error {
+	lg.Debugw("starting doBuildingNumPages()",
+		"numPages", numPages)
+	defer lg.Debug("finished doBuildingNumPages()")
+
+	if buildings.NextPageToken != "" && numPages > 0 {
+		listCall := rsrcs.AddPageToken(rblc, buildings.NextPageToken)
+		rblc = listCall.(*admin.ResourcesBuildingsListCall)
+		nxtBuildings, err := rsrcs.DoListBuildings(rblc)
+		if err != nil {
+			return err
+		}
+		buildings.Buildings = append(buildings.Buildings, nxtBuildings.Buildings...)
+		buildings.Etag = nxtBuildings.Etag
+		buildings.NextPageToken = nxtBuildings.NextPageToken
+
+		if nxtBuildings.NextPageToken != "" {
+			return doBuildingNumPages(rblc, buildings, numPages-1)
+		}
+	}
+
+	return nil
+}
+
+func doBuildingPages(rblc *admin.ResourcesBuildingsListCall, buildings *admin.Buildings, pages string) error {
+	lg.Debugw("starting doBuildingPages()",
+		"pages", pages)
+	defer lg.Debug("finished doBuildingPages()")
+
+	if pages == "all" {
+		err := doBuildingAllPages(rblc, buildings)
+		if err != nil {
+			return err
+		}
+	} else {
+		numPages, err := strconv.Atoi(pages)
+		if err != nil {
+			err = errors.New(gmess.ERR_INVALIDPAGESARGUMENT)
+			lg.Error(err)
+			return err
+		}
+
+		if numPages > 1 {
+			err = doBuildingNumPages(rblc, buildings, numPages-1)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func init() {
+	listCmd.AddCommand(listBuildingsCmd)
+
+	listBuildingsCmd.Flags().StringVarP(&attrs, flgnm.FLG_ATTRIBUTES, "a", "", "required building attributes (separated by ~)")
+	listBuildingsCmd.Flags().BoolVarP(&count, flgnm.FLG_COUNT, "", false, "count number of entities returned")
+	listBuildingsCmd.Flags().Int64VarP(&maxResults, flgnm.FLG_MAXRESULTS, "m", 100, "maximum number of results to return per page")
+	listBuildingsCmd.Flags().StringVarP(&pages, flgnm.FLG_PAGES, "p", "", "number of pages of results to be returned ('all' or a number)")
+}
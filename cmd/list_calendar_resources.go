@@ -0,0 +1,291 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	cfg "github.com/plusworx/gmin/utils/config"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	fmtrs "github.com/plusworx/gmin/utils/formatters"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	gpars "github.com/plusworx/gmin/utils/gminparsers"
+	lg "github.com/plusworx/gmin/utils/logging"
+	rsrcs "github.com/plusworx/gmin/utils/resources"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var listCalResCmd = &cobra.Command{
+	Use:     "calendar-resources",
+	Aliases: []string{"calendar-resource", "cal-resources", "cal-resource", "calres", "cres"},
+	Args:    cobra.NoArgs,
+	Example: `gmin list calendar-resources -a resourceid~resourcename~capacity
+gmin ls cres -q buildingid=hq~capacity>=10 -o capacity -s desc`,
+	Short: "Outputs a list of calendar resources",
+	Long: `Outputs a list of calendar resources.
+
+Query clauses are separated by (~) and must all be satisfied. Valid query attributes are buildingid, capacity,
+featurename, floorname, generatedresourcename and resourcename.
+
+Valid order by values are buildingid, capacity, floorname, resourceid and resourcename.`,
+	RunE: doListCalRes,
+}
+
+func doListCalRes(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doListCalRes()",
+		"args", args)
+	defer lg.Debug("finished doListCalRes()")
+
+	var (
+		calendars *admin.CalendarResources
+		listAttrs string
+	)
+
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryResourceCalendarReadonlyScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
+	if err != nil {
+		return err
+	}
+
+	rclc := ds.Resources.Calendars.List(customerID)
+
+	flgAttrsVal, err := cmd.Flags().GetString(flgnm.FLG_ATTRIBUTES)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgAttrsVal != "" {
+		listAttrs, err = gpars.ParseOutputAttrs(flgAttrsVal, rsrcs.CalendarAttrMap)
+		if err != nil {
+			return err
+		}
+		formattedAttrs := rsrcs.STARTCALENDARSFIELD + listAttrs + rsrcs.ENDFIELD
+
+		listCall := rsrcs.AddFields(rclc, formattedAttrs)
+		rclc = listCall.(*admin.ResourcesCalendarsListCall)
+	}
+
+	flgQueryVal, err := cmd.Flags().GetString(flgnm.FLG_QUERY)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgQueryVal != "" {
+		formattedQuery, err := rsrcs.ParseQuery(flgQueryVal)
+		if err != nil {
+			lg.Error(err)
+			return err
+		}
+
+		rclc = rsrcs.AddQuery(rclc, formattedQuery)
+	}
+
+	flgOrderByVal, err := cmd.Flags().GetString(flgnm.FLG_ORDERBY)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgOrderByVal != "" {
+		ob := strings.ToLower(flgOrderByVal)
+		ok := cmn.SliceContainsStr(rsrcs.ValidOrderByStrs, ob)
+		if !ok {
+			err = fmt.Errorf(gmess.ERR_INVALIDORDERBY, flgOrderByVal)
+			lg.Error(err)
+			return err
+		}
+
+		validOrderBy, err := cmn.IsValidAttr(ob, rsrcs.CalendarAttrMap)
+		if err != nil {
+			lg.Error(err)
+			return err
+		}
+
+		var validSortOrder string
+
+		flgSrtOrdByVal, err := cmd.Flags().GetString(flgnm.FLG_SORTORDER)
+		if err != nil {
+			lg.Error(err)
+			return err
+		}
+		if flgSrtOrdByVal != "" {
+			so := strings.ToLower(flgSrtOrdByVal)
+			validSortOrder, err = cmn.IsValidAttr(so, cmn.ValidSortOrders)
+			if err != nil {
+				lg.Error(err)
+				return err
+			}
+		}
+
+		rclc = rsrcs.AddOrderBy(rclc, validOrderBy, validSortOrder)
+	}
+
+	flgMaxResultsVal, err := cmd.Flags().GetInt64(flgnm.FLG_MAXRESULTS)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	listCall := rsrcs.AddMaxResults(rclc, flgMaxResultsVal)
+	rclc = listCall.(*admin.ResourcesCalendarsListCall)
+
+	calendars, err = rsrcs.DoListCalendars(rclc)
+	if err != nil {
+		return err
+	}
+
+	flgPagesVal, err := cmd.Flags().GetString(flgnm.FLG_PAGES)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgPagesVal != "" {
+		err = doCalResPages(rclc, calendars, flgPagesVal)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = filterList(cmd, calendars, rsrcs.CALENDARLISTKEY)
+	if err != nil {
+		return err
+	}
+
+	flgCountVal, err := cmd.Flags().GetBool(flgnm.FLG_COUNT)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgCountVal {
+		fmt.Println(len(calendars.Items))
+	} else {
+		err = fmtrs.Output(os.Stdout, outputFmt, calendars, rsrcs.CALENDARLISTKEY, listAttrs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func doCalResAllPages(rclc *admin.ResourcesCalendarsListCall, calendars *admin.CalendarResources) error {
+	lg.Debug("starting doCalResAllPages()")
+	defer lg.Debug("finished doCalResAllPages()")
+
+	if calendars.NextPageToken != "" {
+		listCall := rsrcs.AddPageToken(rclc, calendars.NextPageToken)
+		rclc = listCall.(*admin.ResourcesCalendarsListCall)
+		nxtCalendars, err := rsrcs.DoListCalendars(rclc)
+		if err != nil {
+			return err
+		}
+		calendars.Items = append(calendars.Items, nxtCalendars.Items...)
+		calendars.Etag = nxtCalendars.Etag
+		calendars.NextPageToken = nxtCalendars.NextPageToken
+
+		if nxtCalendars.NextPageToken != "" {
+			return doCalResAllPages(rclc, calendars)
+		}
+	}
+
+	return nil
+}
+
+func doCalResNumPages(rclc *admin.ResourcesCalendarsListCall, calendars *admin.CalendarResources, numPages int) error {
+	lg.Debugw("starting doCalResNumPages()",
+		"numPages", numPages)
+	defer lg.Debug("finished doCalResNumPages()")
+
+	if calendars.NextPageToken != "" && numPages > 0 {
+		listCall := rsrcs.AddPageToken(rclc, calendars.NextPageToken)
+		rclc = listCall.(*admin.ResourcesCalendarsListCall)
+		nxtCalendars, err := rsrcs.DoListCalendars(rclc)
+		if err != nil {
+			return err
+		}
+		calendars.Items = append(calendars.Items, nxtCalendars.Items...)
+		calendars.Etag = nxtCalendars.Etag
+		calendars.NextPageToken = nxtCalendars.NextPageToken
+
+		if nxtCalendars.NextPageToken != "" {
+			return doCalResNumPages(rclc, calendars, numPages-1)
+		}
+	}
+
+	return nil
+}
+
+func doCalResPages(rclc *admin.ResourcesCalendarsListCall, calendars *admin.CalendarResources, pages string) error {
+	lg.Debugw("starting doCalResPages()",
+		"pages", pages)
+	defer lg.Debug("finished doCalResPages()")
+
+	if pages == "all" {
+		err := doCalResAllPages(rclc, calendars)
+		if err != nil {
+			return err
+		}
+	} else {
+		numPages, err := strconv.Atoi(pages)
+		if err != nil {
+			err = errors.New(gmess.ERR_INVALIDPAGESARGUMENT)
+			lg.Error(err)
+			return err
+		}
+
+		if numPages > 1 {
+			err = doCalResNumPages(rclc, calendars, numPages-1)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func init() {
+	listCmd.AddCommand(listCalResCmd)
+
+	listCalResCmd.Flags().StringVarP(&attrs, flgnm.FLG_ATTRIBUTES, "a", "", "required calendar resource attributes (separated by ~)")
+	listCalResCmd.Flags().BoolVarP(&count, flgnm.FLG_COUNT, "", false, "count number of entities returned")
+	listCalResCmd.Flags().Int64VarP(&maxResults, flgnm.FLG_MAXRESULTS, "m", 100, "maximum number of results to return per page")
+	listCalResCmd.Flags().StringVarP(&orderBy, flgnm.FLG_ORDERBY, "o", "", "field by which results will be ordered")
+	listCalResCmd.Flags().StringVarP(&pages, flgnm.FLG_PAGES, "p", "", "number of pages of results to be returned ('all' or a number)")
+	listCalResCmd.Flags().StringVarP(&query, flgnm.FLG_QUERY, "q", "", "selection criteria to get calendar resources (separated by ~)")
+	listCalResCmd.Flags().StringVarP(&sortOrder, flgnm.FLG_SORTORDER, "s", "", "sort order of returned results")
+}
@@ -23,10 +23,7 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
-	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 
 	cdevs "github.com/plusworx/gmin/utils/chromeosdevices"
@@ -45,10 +42,17 @@ var listCrOSDevsCmd = &cobra.Command{
 	Aliases: []string{"chromeos-device", "cros-devices", "cros-device", "cros-devs", "cros-dev", "cdevs", "cdev"},
 	Args:    cobra.NoArgs,
 	Example: `gmin list chromeos-devices --pages all --count
-gmin ls cdevs --pages all`,
+gmin ls cdevs --pages all
+gmin ls cdevs --pages all --output csv --parallel 4`,
 	Short: "Outputs a list of ChromeOS devices",
-	Long:  `Outputs a list of ChromeOS devices.`,
-	RunE:  doListCrOSDevs,
+	Long: `Outputs a list of ChromeOS devices.
+
+JSONL, CSV and TSV output and counts are written as each page of results arrives. CSV and TSV columns
+come from --attributes and the first page of results and listing stops with an error if a later page
+has attributes that are not in the columns. If listing stops with an error, --page-token resumes it
+from the page given in the error message. --parallel lists all pages of every orgunit with up to the
+given number of orgunits at a time.`,
+	RunE: doListCrOSDevs,
 }
 
 func doListCrOSDevs(cmd *cobra.Command, args []string) error {
@@ -56,9 +60,10 @@ func doListCrOSDevs(cmd *cobra.Command, args []string) error {
 		"args", args)
 	defer lg.Debug("finished doListCrOSDevs()")
 
-	var (
-		crosdevs *admin.ChromeOsDevices
-	)
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
 
 	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryDeviceChromeosReadonlyScope)
 	if err != nil {
@@ -71,19 +76,80 @@ func doListCrOSDevs(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	cdlc, listAttrs, err := lcdListCall(cmd, ds, customerID, "")
+	if err != nil {
+		return err
+	}
+
+	shards, err := parallelOrgUnits(cmd)
+	if err != nil {
+		return err
+	}
+
+	crosdevs := new(admin.ChromeOsDevices)
+	pl := pagedList{
+		fields:  listAttrs,
+		listKey: cdevs.LISTKEY,
+		merge: func(page interface{}) {
+			pgCrOSDevs := page.(*admin.ChromeOsDevices)
+			crosdevs.Chromeosdevices = append(crosdevs.Chromeosdevices, pgCrOSDevs.Chromeosdevices...)
+			crosdevs.Etag = pgCrOSDevs.Etag
+			crosdevs.Kind = pgCrOSDevs.Kind
+			crosdevs.NextPageToken = pgCrOSDevs.NextPageToken
+		},
+		result: crosdevs,
+		size: func(page interface{}) int {
+			return len(page.(*admin.ChromeOsDevices).Chromeosdevices)
+		},
+	}
+
+	return listPages(cmd, outputFmt, pl, lcdFetch(cdlc), shards, func(shard string) cmn.PageFunc {
+		shardCDLC, _, err := lcdListCall(cmd, ds, customerID, shard)
+		if err != nil {
+			return func(pageToken string) (interface{}, string, error) {
+				return nil, "", err
+			}
+		}
+		return lcdFetch(shardCDLC)
+	})
+}
+
+// lcdFetch returns a function that gets a page of ChromeOS devices
+func lcdFetch(cdlc *admin.ChromeosdevicesListCall) cmn.PageFunc {
+	return func(pageToken string) (interface{}, string, error) {
+		if pageToken != "" {
+			cdlc = cdevs.AddPageToken(cdlc, pageToken)
+		}
+		crosdevs, err := cdevs.DoList(cdlc)
+		if err != nil {
+			return nil, "", err
+		}
+		return crosdevs, crosdevs.NextPageToken, nil
+	}
+}
+
+// lcdListCall sets up a ChromeOS devices list call from command flags. Devices are listed from shardOU
+// when it is given and otherwise from the orgunit flag or the whole customer.
+func lcdListCall(cmd *cobra.Command, ds *admin.Service, customerID string, shardOU string) (*admin.ChromeosdevicesListCall, string, error) {
+	lg.Debugw("starting lcdListCall()",
+		"shardOU", shardOU)
+	defer lg.Debug("finished lcdListCall()")
+
+	var listAttrs string
+
 	cdlc := ds.Chromeosdevices.List(customerID)
 
 	flgAttrsVal, err := cmd.Flags().GetString(flgnm.FLG_ATTRIBUTES)
 	if err != nil {
 		lg.Error(err)
-		return err
+		return nil, "", err
 	}
 	if flgAttrsVal != "" {
-		listAttrs, err := gpars.ParseOutputAttrs(flgAttrsVal, cdevs.CrOSDevAttrMap)
+		listAttrs, err = gpars.ParseOutputAttrs(flgAttrsVal, cdevs.CrOSDevAttrMap)
 		if err != nil {
-			return err
+			return nil, "", err
 		}
-		formattedAttrs := cdevs.STARTCHROMEDEVICESFIELD + listAttrs + cdevs.ENDFIELD
+		formattedAttrs := "nextPageToken," + cdevs.STARTCHROMEDEVICESFIELD + listAttrs + cdevs.ENDFIELD
 		listCall := cdevs.AddFields(cdlc, formattedAttrs)
 		cdlc = listCall.(*admin.ChromeosdevicesListCall)
 	}
@@ -91,7 +157,7 @@ func doListCrOSDevs(cmd *cobra.Command, args []string) error {
 	flgOrderByVal, err := cmd.Flags().GetString(flgnm.FLG_ORDERBY)
 	if err != nil {
 		lg.Error(err)
-		return err
+		return nil, "", err
 	}
 	if flgOrderByVal != "" {
 		ob := strings.ToLower(flgOrderByVal)
@@ -99,12 +165,12 @@ func doListCrOSDevs(cmd *cobra.Command, args []string) error {
 		if !ok {
 			err = fmt.Errorf(gmess.ERR_INVALIDORDERBY, flgOrderByVal)
 			lg.Error(err)
-			return err
+			return nil, "", err
 		}
 
 		validOrderBy, err := cmn.IsValidAttr(ob, cdevs.CrOSDevAttrMap)
 		if err != nil {
-			return err
+			return nil, "", err
 		}
 
 		cdlc = cdevs.AddOrderBy(cdlc, validOrderBy)
@@ -112,13 +178,13 @@ func doListCrOSDevs(cmd *cobra.Command, args []string) error {
 		flgSrtOrdVal, err := cmd.Flags().GetString(flgnm.FLG_SORTORDER)
 		if err != nil {
 			lg.Error(err)
-			return err
+			return nil, "", err
 		}
 		if flgSrtOrdVal != "" {
 			so := strings.ToLower(flgSrtOrdVal)
 			validSortOrder, err := cmn.IsValidAttr(so, cmn.ValidSortOrders)
 			if err != nil {
-				return err
+				return nil, "", err
 			}
 
 			cdlc = cdevs.AddSortOrder(cdlc, validSortOrder)
@@ -128,16 +194,18 @@ func doListCrOSDevs(cmd *cobra.Command, args []string) error {
 	flgOUVal, err := cmd.Flags().GetString(flgnm.FLG_ORGUNITPATH)
 	if err != nil {
 		lg.Error(err)
-		return err
+		return nil, "", err
 	}
-	if flgOUVal != "" {
-		cdlc = cdevs.AddQuery(cdlc, flgOUVal)
+	if shardOU != "" {
+		cdlc = cdevs.AddOrgUnitPath(cdlc, shardOU)
+	} else if flgOUVal != "" {
+		cdlc = cdevs.AddOrgUnitPath(cdlc, flgOUVal)
 	}
 
 	flgProjectionVal, err := cmd.Flags().GetString(flgnm.FLG_PROJECTION)
 	if err != nil {
 		lg.Error(err)
-		return err
+		return nil, "", err
 	}
 	if flgProjectionVal != "" {
 		proj := strings.ToLower(flgProjectionVal)
@@ -145,7 +213,7 @@ func doListCrOSDevs(cmd *cobra.Command, args []string) error {
 		if !ok {
 			err = fmt.Errorf(gmess.ERR_INVALIDPROJECTIONTYPE, flgProjectionVal)
 			lg.Error(err)
-			return err
+			return nil, "", err
 		}
 
 		listCall := cdevs.AddProjection(cdlc, proj)
@@ -155,12 +223,12 @@ func doListCrOSDevs(cmd *cobra.Command, args []string) error {
 	flgQueryVal, err := cmd.Flags().GetString(flgnm.FLG_QUERY)
 	if err != nil {
 		lg.Error(err)
-		return err
+		return nil, "", err
 	}
 	if flgQueryVal != "" {
 		formattedQuery, err := gpars.ParseQuery(flgQueryVal, cdevs.QueryAttrMap)
 		if err != nil {
-			return err
+			return nil, "", err
 		}
 
 		cdlc = cdevs.AddQuery(cdlc, formattedQuery)
@@ -169,119 +237,11 @@ func doListCrOSDevs(cmd *cobra.Command, args []string) error {
 	flgMaxResultsVal, err := cmd.Flags().GetInt64(flgnm.FLG_MAXRESULTS)
 	if err != nil {
 		lg.Error(err)
-		return err
+		return nil, "", err
 	}
 	cdlc = cdevs.AddMaxResults(cdlc, flgMaxResultsVal)
 
-	crosdevs, err = cdevs.DoList(cdlc)
-	if err != nil {
-		return err
-	}
-
-	flgPagesVal, err := cmd.Flags().GetString(flgnm.FLG_PAGES)
-	if err != nil {
-		lg.Error(err)
-		return err
-	}
-	if flgPagesVal != "" {
-		err = doCrOSDevPages(cdlc, crosdevs, flgPagesVal)
-		if err != nil {
-			return err
-		}
-	}
-
-	jsonData, err := json.MarshalIndent(crosdevs, "", "    ")
-	if err != nil {
-		lg.Error(err)
-		return err
-	}
-
-	flgCountVal, err := cmd.Flags().GetBool(flgnm.FLG_COUNT)
-	if err != nil {
-		lg.Error(err)
-		return err
-	}
-	if flgCountVal {
-		fmt.Println(len(crosdevs.Chromeosdevices))
-	} else {
-		fmt.Println(string(jsonData))
-	}
-
-	return nil
-}
-
-func doCrOSDevAllPages(cdlc *admin.ChromeosdevicesListCall, crosdevs *admin.ChromeOsDevices) error {
-	lg.Debug("starting doCrOSDevAllPages()")
-	defer lg.Debug("finished doCrOSDevAllPages()")
-
-	if crosdevs.NextPageToken != "" {
-		cdlc = cdevs.AddPageToken(cdlc, crosdevs.NextPageToken)
-		nxtCrOSDevs, err := cdevs.DoList(cdlc)
-		if err != nil {
-			return err
-		}
-		crosdevs.Chromeosdevices = append(crosdevs.Chromeosdevices, nxtCrOSDevs.Chromeosdevices...)
-		crosdevs.Etag = nxtCrOSDevs.Etag
-		crosdevs.NextPageToken = nxtCrOSDevs.NextPageToken
-
-		if nxtCrOSDevs.NextPageToken != "" {
-			doCrOSDevAllPages(cdlc, crosdevs)
-		}
-	}
-
-	return nil
-}
-
-func doCrOSDevNumPages(cdlc *admin.ChromeosdevicesListCall, crosdevs *admin.ChromeOsDevices, numPages int) error {
-	lg.Debugw("starting doCrOSDevNumPages()",
-		"numPages", numPages)
-	defer lg.Debug("finished doCrOSDevNumPages()")
-
-	if crosdevs.NextPageToken != "" && numPages > 0 {
-		cdlc = cdevs.AddPageToken(cdlc, crosdevs.NextPageToken)
-		nxtCrOSDevs, err := cdevs.DoList(cdlc)
-		if err != nil {
-			return err
-		}
-		crosdevs.Chromeosdevices = append(crosdevs.Chromeosdevices, nxtCrOSDevs.Chromeosdevices...)
-		crosdevs.Etag = nxtCrOSDevs.Etag
-		crosdevs.NextPageToken = nxtCrOSDevs.NextPageToken
-
-		if nxtCrOSDevs.NextPageToken != "" {
-			doCrOSDevNumPages(cdlc, crosdevs, numPages-1)
-		}
-	}
-
-	return nil
-}
-
-func doCrOSDevPages(cdlc *admin.ChromeosdevicesListCall, crosdevs *admin.ChromeOsDevices, pages string) error {
-	lg.Debugw("starting doCrOSDevPages()",
-		"pages", pages)
-	defer lg.Debug("finished doCrOSDevPages()")
-
-	if pages == "all" {
-		err := doCrOSDevAllPages(cdlc, crosdevs)
-		if err != nil {
-			return err
-		}
-	} else {
-		numPages, err := strconv.Atoi(pages)
-		if err != nil {
-			err = errors.New(gmess.ERR_INVALIDPAGESARGUMENT)
-			lg.Error(err)
-			return err
-		}
-
-		if numPages > 1 {
-			err = doCrOSDevNumPages(cdlc, crosdevs, numPages-1)
-			if err != nil {
-				return err
-			}
-		}
-	}
-
-	return nil
+	return cdlc, listAttrs, nil
 }
 
 func init() {
@@ -291,7 +251,9 @@ func init() {
 	listCrOSDevsCmd.Flags().BoolVarP(&count, flgnm.FLG_COUNT, "", false, "count number of entities returned")
 	listCrOSDevsCmd.Flags().Int64VarP(&maxResults, flgnm.FLG_MAXRESULTS, "m", 200, "maximum number of results to return per page")
 	listCrOSDevsCmd.Flags().StringVarP(&orderBy, flgnm.FLG_ORDERBY, "o", "", "field by which results will be ordered")
+	listCrOSDevsCmd.Flags().StringVar(&pageToken, flgnm.FLG_PAGETOKEN, "", "page token to resume listing from")
 	listCrOSDevsCmd.Flags().StringVarP(&pages, flgnm.FLG_PAGES, "p", "", "number of pages of results to be returned ('all' or a number)")
+	listCrOSDevsCmd.Flags().IntVar(&parallel, flgnm.FLG_PARALLEL, 0, "number of orgunits to list in parallel")
 	listCrOSDevsCmd.Flags().StringVarP(&projection, flgnm.FLG_PROJECTION, "j", "", "type of projection")
 	listCrOSDevsCmd.Flags().StringVarP(&query, flgnm.FLG_QUERY, "q", "", "selection criteria to get devices (separated by ~)")
 	listCrOSDevsCmd.Flags().StringVarP(&sortOrder, flgnm.FLG_SORTORDER, "s", "", "sort order of returned results")
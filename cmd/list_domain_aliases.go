@@ -0,0 +1,141 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	cfg "github.com/plusworx/gmin/utils/config"
+	doms "github.com/plusworx/gmin/utils/domains"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	fmtrs "github.com/plusworx/gmin/utils/formatters"
+	gpars "github.com/plusworx/gmin/utils/gminparsers"
+	lg "github.com/plusworx/gmin/utils/logging"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var listDomainAliasesCmd = &cobra.Command{
+	Use:     "domain-aliases",
+	Aliases: []string{"domain-alias", "dom-aliases", "dom-alias", "daliases", "dalias", "da"},
+	Args:    cobra.NoArgs,
+	Example: `gmin list domain-aliases -a domainaliasname~parentdomainname
+gmin ls da -d mycompany.com`,
+	Short: "Outputs a list of the customer's domain aliases",
+	Long:  `Outputs a list of the customer's domain aliases.`,
+	RunE:  doListDomainAliases,
+}
+
+func doListDomainAliases(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doListDomainAliases()",
+		"args", args)
+	defer lg.Debug("finished doListDomainAliases()")
+
+	var (
+		aliases   *admin.DomainAliases
+		listAttrs string
+	)
+
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryDomainReadonlyScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
+	if err != nil {
+		return err
+	}
+
+	dalc := ds.DomainAliases.List(customerID)
+
+	flgAttrsVal, err := cmd.Flags().GetString(flgnm.FLG_ATTRIBUTES)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgAttrsVal != "" {
+		listAttrs, err = gpars.ParseOutputAttrs(flgAttrsVal, doms.DomainAliasAttrMap)
+		if err != nil {
+			return err
+		}
+		formattedAttrs := doms.STARTALIASESFIELD + listAttrs + doms.ENDFIELD
+
+		listCall := doms.AddFields(dalc, formattedAttrs)
+		dalc = listCall.(*admin.DomainAliasesListCall)
+	}
+
+	flgDomainVal, err := cmd.Flags().GetString(flgnm.FLG_DOMAIN)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgDomainVal != "" {
+		err = validateDomain(flgDomainVal)
+		if err != nil {
+			return err
+		}
+		dalc = doms.AddParentDomainName(dalc, flgDomainVal)
+	}
+
+	aliases, err = doms.DoListAliases(dalc)
+	if err != nil {
+		return err
+	}
+
+	err = filterList(cmd, aliases, doms.ALIASLISTKEY)
+	if err != nil {
+		return err
+	}
+
+	flgCountVal, err := cmd.Flags().GetBool(flgnm.FLG_COUNT)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgCountVal {
+		fmt.Println(len(aliases.DomainAliases))
+	} else {
+		err = fmtrs.Output(os.Stdout, outputFmt, aliases, doms.ALIASLISTKEY, listAttrs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func init() {
+	listCmd.AddCommand(listDomainAliasesCmd)
+
+	listDomainAliasesCmd.Flags().StringVarP(&attrs, flgnm.FLG_ATTRIBUTES, "a", "", "required domain alias attributes (separated by ~)")
+	listDomainAliasesCmd.Flags().BoolVarP(&count, flgnm.FLG_COUNT, "", false, "count number of entities returned")
+	listDomainAliasesCmd.Flags().StringVarP(&domain, flgnm.FLG_DOMAIN, "d", "", "parent domain of aliases")
+}
@@ -0,0 +1,127 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	cfg "github.com/plusworx/gmin/utils/config"
+	doms "github.com/plusworx/gmin/utils/domains"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	fmtrs "github.com/plusworx/gmin/utils/formatters"
+	gpars "github.com/plusworx/gmin/utils/gminparsers"
+	lg "github.com/plusworx/gmin/utils/logging"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var listDomainsCmd = &cobra.Command{
+	Use:     "domains",
+	Aliases: []string{"domain", "doms", "dom"},
+	Args:    cobra.NoArgs,
+	Example: `gmin list domains -a domainname~verified
+gmin ls doms --count`,
+	Short: "Outputs a list of the customer's domains",
+	Long:  `Outputs a list of the customer's domains.`,
+	RunE:  doListDomains,
+}
+
+func doListDomains(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doListDomains()",
+		"args", args)
+	defer lg.Debug("finished doListDomains()")
+
+	var (
+		domains   *admin.Domains2
+		listAttrs string
+	)
+
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryDomainReadonlyScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
+	if err != nil {
+		return err
+	}
+
+	dlc := ds.Domains.List(customerID)
+
+	flgAttrsVal, err := cmd.Flags().GetString(flgnm.FLG_ATTRIBUTES)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgAttrsVal != "" {
+		listAttrs, err = gpars.ParseOutputAttrs(flgAttrsVal, doms.DomainAttrMap)
+		if err != nil {
+			return err
+		}
+		formattedAttrs := doms.STARTDOMAINSFIELD + listAttrs + doms.ENDFIELD
+
+		listCall := doms.AddFields(dlc, formattedAttrs)
+		dlc = listCall.(*admin.DomainsListCall)
+	}
+
+	domains, err = doms.DoList(dlc)
+	if err != nil {
+		return err
+	}
+
+	err = filterList(cmd, domains, doms.LISTKEY)
+	if err != nil {
+		return err
+	}
+
+	flgCountVal, err := cmd.Flags().GetBool(flgnm.FLG_COUNT)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgCountVal {
+		fmt.Println(len(domains.Domains))
+	} else {
+		err = fmtrs.Output(os.Stdout, outputFmt, domains, doms.LISTKEY, listAttrs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func init() {
+	listCmd.AddCommand(listDomainsCmd)
+
+	listDomainsCmd.Flags().StringVarP(&attrs, flgnm.FLG_ATTRIBUTES, "a", "", "required domain attributes (separated by ~)")
+	listDomainsCmd.Flags().BoolVarP(&count, flgnm.FLG_COUNT, "", false, "count number of entities returned")
+}
@@ -0,0 +1,228 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	cfg "github.com/plusworx/gmin/utils/config"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	fmtrs "github.com/plusworx/gmin/utils/formatters"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	gpars "github.com/plusworx/gmin/utils/gminparsers"
+	lg "github.com/plusworx/gmin/utils/logging"
+	rsrcs "github.com/plusworx/gmin/utils/resources"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var listFeaturesCmd = &cobra.Command{
+	Use:     "features",
+	Aliases: []string{"feature", "feats", "feat"},
+	Args:    cobra.NoArgs,
+	Example: `gmin list features
+gmin ls feats -p all --count`,
+	Short: "Outputs a list of calendar resource features",
+	Long:  `Outputs a list of calendar resource features.`,
+	RunE:  doListFeatures,
+}
+
+func doListFeatures(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doListFeatures()",
+		"args", args)
+	defer lg.Debug("finished doListFeatures()")
+
+	var (
+		features  *admin.Features
+		listAttrs string
+	)
+
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryResourceCalendarReadonlyScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
+	if err != nil {
+		return err
+	}
+
+	rflc := ds.Resources.Features.List(customerID)
+
+	flgAttrsVal, err := cmd.Flags().GetString(flgnm.FLG_ATTRIBUTES)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgAttrsVal != "" {
+		listAttrs, err = gpars.ParseOutputAttrs(flgAttrsVal, rsrcs.FeatureAttrMap)
+		if err != nil {
+			return err
+		}
+		formattedAttrs := rsrcs.STARTFEATURESFIELD + listAttrs + rsrcs.ENDFIELD
+
+		listCall := rsrcs.AddFields(rflc, formattedAttrs)
+		rflc = listCall.(*admin.ResourcesFeaturesListCall)
+	}
+
+	flgMaxResultsVal, err := cmd.Flags().GetInt64(flgnm.FLG_MAXRESULTS)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	listCall := rsrcs.AddMaxResults(rflc, flgMaxResultsVal)
+	rflc = listCall.(*admin.ResourcesFeaturesListCall)
+
+	features, err = rsrcs.DoListFeatures(rflc)
+	if err != nil {
+		return err
+	}
+
+	flgPagesVal, err := cmd.Flags().GetString(flgnm.FLG_PAGES)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgPagesVal != "" {
+		err = doFeaturePages(rflc, features, flgPagesVal)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = filterList(cmd, features, rsrcs.FEATURELISTKEY)
+	if err != nil {
+		return err
+	}
+
+	flgCountVal, err := cmd.Flags().GetBool(flgnm.FLG_COUNT)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgCountVal {
+		fmt.Println(len(features.Features))
+	} else {
+		err = fmtrs.Output(os.Stdout, outputFmt, features, rsrcs.FEATURELISTKEY, listAttrs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func doFeatureAllPages(rflc *admin.ResourcesFeaturesListCall, features *admin.Features) error {
+	lg.Debug("starting doFeatureAllPages()")
+	defer lg.Debug("finished doFeatureAllPages()")
+
+	if features.NextPageToken != "" {
+		listCall := rsrcs.AddPageToken(rflc, features.NextPageToken)
+		rflc = listCall.(*admin.ResourcesFeaturesListCall)
+		nxtFeatures, err := rsrcs.DoListFeatures(rflc)
+		if err != nil {
+			return err
+		}
+		features.Features = append(features.Features, nxtFeatures.Features...)
+		features.Etag = nxtFeatures.Etag
+		features.NextPageToken = nxtFeatures.NextPageToken
+
+		if nxtFeatures.NextPageToken != "" {
+			return doFeatureAllPages(rflc, features)
+		}
+	}
+
+	return nil
+}
+
+func doFeatureNumPages(rflc *admin.ResourcesFeaturesListCall, features *admin.Features, numPages int) error {
+	lg.Debugw("starting doFeatureNumPages()",
+		"numPages", numPages)
+	defer lg.Debug("finished doFeatureNumPages()")
+
+	if features.NextPageToken != "" && numPages > 0 {
+		listCall := rsrcs.AddPageToken(rflc, features.NextPageToken)
+		rflc = listCall.(*admin.ResourcesFeaturesListCall)
+		nxtFeatures, err := rsrcs.DoListFeatures(rflc)
+		if err != nil {
+			return err
+		}
+		features.Features = append(features.Features, nxtFeatures.Features...)
+		features.Etag = nxtFeatures.Etag
+		features.NextPageToken = nxtFeatures.NextPageToken
+
+		if nxtFeatures.NextPageToken != "" {
+			return doFeatureNumPages(rflc, features, numPages-1)
+		}
+	}
+
+	return nil
+}
+
+func doFeaturePages(rflc *admin.ResourcesFeaturesListCall, features *admin.Features, pages string) error {
+	lg.Debugw("starting doFeaturePages()",
+		"pages", pages)
+	defer lg.Debug("finished doFeaturePages()")
+
+	if pages == "all" {
+		err := doFeatureAllPages(rflc, features)
+		if err != nil {
+			return err
+		}
+	} else {
+		numPages, err := strconv.Atoi(pages)
+		if err != nil {
+			err = errors.New(gmess.ERR_INVALIDPAGESARGUMENT)
+			lg.Error(err)
+			return err
+		}
+
+		if numPages > 1 {
+			err = doFeatureNumPages(rflc, features, numPages-1)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func init() {
+	listCmd.AddCommand(listFeaturesCmd)
+
+	listFeaturesCmd.Flags().StringVarP(&attrs, flgnm.FLG_ATTRIBUTES, "a", "", "required feature attributes (separated by ~)")
+	listFeaturesCmd.Flags().BoolVarP(&count, flgnm.FLG_COUNT, "", false, "count number of entities returned")
+	listFeaturesCmd.Flags().Int64VarP(&maxResults, flgnm.FLG_MAXRESULTS, "m", 100, "maximum number of results to return per page")
+	listFeaturesCmd.Flags().StringVarP(&pages, flgnm.FLG_PAGES, "p", "", "number of pages of results to be returned ('all' or a number)")
+}
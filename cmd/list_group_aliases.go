@@ -23,11 +23,11 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
-	"fmt"
+	"os"
 
 	cmn "github.com/plusworx/gmin/utils/common"
 	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	fmtrs "github.com/plusworx/gmin/utils/formatters"
 	gpars "github.com/plusworx/gmin/utils/gminparsers"
 	gas "github.com/plusworx/gmin/utils/groupaliases"
 	lg "github.com/plusworx/gmin/utils/logging"
@@ -51,7 +51,15 @@ func doListGroupAliases(cmd *cobra.Command, args []string) error {
 		"args", args)
 	defer lg.Debug("finished doListGroupAliases()")
 
-	var aliases *admin.Aliases
+	var (
+		aliases   *admin.Aliases
+		listAttrs string
+	)
+
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
 
 	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryGroupReadonlyScope)
 	if err != nil {
@@ -67,7 +75,7 @@ func doListGroupAliases(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	if flgAttrsVal != "" {
-		listAttrs, err := gpars.ParseOutputAttrs(flgAttrsVal, gas.GroupAliasAttrMap)
+		listAttrs, err = gpars.ParseOutputAttrs(flgAttrsVal, gas.GroupAliasAttrMap)
 		if err != nil {
 			return err
 		}
@@ -81,13 +89,15 @@ func doListGroupAliases(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	jsonData, err := json.MarshalIndent(aliases, "", "    ")
+	err = filterList(cmd, aliases, gas.LISTKEY)
 	if err != nil {
-		lg.Error(err)
 		return err
 	}
 
-	fmt.Println(string(jsonData))
+	err = fmtrs.Output(os.Stdout, outputFmt, aliases, gas.LISTKEY, listAttrs)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -23,10 +23,8 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 
 	cmn "github.com/plusworx/gmin/utils/common"
@@ -47,8 +45,14 @@ var listGroupsCmd = &cobra.Command{
 	Example: `gmin list groups -a email~description~id
 gmin ls grp -q email=mygroup@domain.com`,
 	Short: "Outputs a list of groups",
-	Long:  `Outputs a list of groups.`,
-	RunE:  doListGroups,
+	Long: `Outputs a list of groups.
+
+JSONL, CSV and TSV output and counts are written as each page of results arrives. CSV and TSV columns
+come from --attributes and the first page of results and listing stops with an error if a later page
+has attributes that are not in the columns. If listing stops with an error, --page-token resumes it
+from the page given in the error message. --parallel lists all pages of every domain with up to the
+given number of domains at a time.`,
+	RunE: doListGroups,
 }
 
 func doListGroups(cmd *cobra.Command, args []string) error {
@@ -56,11 +60,10 @@ func doListGroups(cmd *cobra.Command, args []string) error {
 		"args", args)
 	defer lg.Debug("finished doListGroups()")
 
-	var (
-		groups       *admin.Groups
-		jsonData     []byte
-		validOrderBy string
-	)
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
 
 	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryGroupReadonlyScope)
 	if err != nil {
@@ -68,35 +71,104 @@ func doListGroups(cmd *cobra.Command, args []string) error {
 	}
 	ds := srv.(*admin.Service)
 
+	glc, listAttrs, err := lgrpListCall(cmd, ds, "")
+	if err != nil {
+		return err
+	}
+
+	shards, err := parallelDomains(cmd)
+	if err != nil {
+		return err
+	}
+
+	groups := new(admin.Groups)
+	pl := pagedList{
+		fields:  listAttrs,
+		listKey: grps.LISTKEY,
+		merge: func(page interface{}) {
+			pgGroups := page.(*admin.Groups)
+			groups.Groups = append(groups.Groups, pgGroups.Groups...)
+			groups.Etag = pgGroups.Etag
+			groups.Kind = pgGroups.Kind
+			groups.NextPageToken = pgGroups.NextPageToken
+		},
+		result: groups,
+		size: func(page interface{}) int {
+			return len(page.(*admin.Groups).Groups)
+		},
+	}
+
+	err = listPages(cmd, outputFmt, pl, lgrpFetch(glc), shards, func(shard string) cmn.PageFunc {
+		shardGLC, _, err := lgrpListCall(cmd, ds, shard)
+		if err != nil {
+			return func(pageToken string) (interface{}, string, error) {
+				return nil, "", err
+			}
+		}
+		return lgrpFetch(shardGLC)
+	})
+	if err != nil {
+		return listDomainError(cmd, err)
+	}
+	return nil
+}
+
+// lgrpFetch returns a function that gets a page of groups
+func lgrpFetch(glc *admin.GroupsListCall) cmn.PageFunc {
+	return func(pageToken string) (interface{}, string, error) {
+		if pageToken != "" {
+			glc = grps.AddPageToken(glc, pageToken)
+		}
+		groups, err := grps.DoList(glc)
+		if err != nil {
+			return nil, "", err
+		}
+		return groups, groups.NextPageToken, nil
+	}
+}
+
+// lgrpListCall sets up a groups list call from command flags. Groups are listed from shardDomain when
+// it is given and otherwise from the domain flag, the domain of the active profile or the whole customer.
+func lgrpListCall(cmd *cobra.Command, ds *admin.Service, shardDomain string) (*admin.GroupsListCall, string, error) {
+	lg.Debugw("starting lgrpListCall()",
+		"shardDomain", shardDomain)
+	defer lg.Debug("finished lgrpListCall()")
+
+	var (
+		listAttrs    string
+		validOrderBy string
+	)
+
 	glc := ds.Groups.List()
 
 	flgAttrsVal, err := cmd.Flags().GetString(flgnm.FLG_ATTRIBUTES)
 	if err != nil {
 		lg.Error(err)
-		return err
+		return nil, "", err
 	}
 	if flgAttrsVal != "" {
-		listAttrs, err := gpars.ParseOutputAttrs(flgAttrsVal, grps.GroupAttrMap)
+		listAttrs, err = gpars.ParseOutputAttrs(flgAttrsVal, grps.GroupAttrMap)
 		if err != nil {
-			return err
+			return nil, "", err
 		}
-		formattedAttrs := grps.STARTGROUPSFIELD + listAttrs + grps.ENDFIELD
+		formattedAttrs := "nextPageToken," + grps.STARTGROUPSFIELD + listAttrs + grps.ENDFIELD
 
 		listCall := grps.AddFields(glc, formattedAttrs)
 		glc = listCall.(*admin.GroupsListCall)
 	}
 
-	flgDomainVal, err := cmd.Flags().GetString(flgnm.FLG_DOMAIN)
+	flgDomainVal, err := listDomain(cmd)
 	if err != nil {
-		lg.Error(err)
-		return err
+		return nil, "", err
 	}
-	if flgDomainVal != "" {
+	if shardDomain != "" {
+		glc = grps.AddDomain(glc, shardDomain)
+	} else if flgDomainVal != "" {
 		glc = grps.AddDomain(glc, flgDomainVal)
 	} else {
 		customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
 		if err != nil {
-			return err
+			return nil, "", err
 		}
 		glc = grps.AddCustomer(glc, customerID)
 	}
@@ -104,12 +176,12 @@ func doListGroups(cmd *cobra.Command, args []string) error {
 	flgQueryVal, err := cmd.Flags().GetString(flgnm.FLG_QUERY)
 	if err != nil {
 		lg.Error(err)
-		return err
+		return nil, "", err
 	}
 	if flgQueryVal != "" {
 		formattedQuery, err := gpars.ParseQuery(flgQueryVal, grps.QueryAttrMap)
 		if err != nil {
-			return err
+			return nil, "", err
 		}
 
 		glc = grps.AddQuery(glc, formattedQuery)
@@ -118,7 +190,7 @@ func doListGroups(cmd *cobra.Command, args []string) error {
 	flgOrderByVal, err := cmd.Flags().GetString(flgnm.FLG_ORDERBY)
 	if err != nil {
 		lg.Error(err)
-		return err
+		return nil, "", err
 	}
 	if flgOrderByVal != "" {
 		ob := strings.ToLower(flgOrderByVal)
@@ -126,12 +198,12 @@ func doListGroups(cmd *cobra.Command, args []string) error {
 		if !ok {
 			err = fmt.Errorf(gmess.ERR_INVALIDORDERBY, flgOrderByVal)
 			lg.Error(err)
-			return err
+			return nil, "", err
 		}
 
 		validOrderBy, err = cmn.IsValidAttr(ob, grps.GroupAttrMap)
 		if err != nil {
-			return err
+			return nil, "", err
 		}
 
 		glc = grps.AddOrderBy(glc, validOrderBy)
@@ -139,13 +211,13 @@ func doListGroups(cmd *cobra.Command, args []string) error {
 		flgSrtOrdVal, err := cmd.Flags().GetString(flgnm.FLG_SORTORDER)
 		if err != nil {
 			lg.Error(err)
-			return err
+			return nil, "", err
 		}
 		if flgSrtOrdVal != "" {
 			so := strings.ToLower(flgSrtOrdVal)
 			validSortOrder, err := cmn.IsValidAttr(so, cmn.ValidSortOrders)
 			if err != nil {
-				return err
+				return nil, "", err
 			}
 
 			glc = grps.AddSortOrder(glc, validSortOrder)
@@ -155,7 +227,7 @@ func doListGroups(cmd *cobra.Command, args []string) error {
 	flgUserKeyVal, err := cmd.Flags().GetString(flgnm.FLG_USERKEY)
 	if err != nil {
 		lg.Error(err)
-		return err
+		return nil, "", err
 	}
 	if flgUserKeyVal != "" {
 		if flgDomainVal != "" {
@@ -163,126 +235,18 @@ func doListGroups(cmd *cobra.Command, args []string) error {
 		} else {
 			err = errors.New(gmess.ERR_NODOMAINWITHUSERKEY)
 			lg.Error(err)
-			return err
+			return nil, "", err
 		}
 	}
 
 	flgMaxResultsVal, err := cmd.Flags().GetInt64(flgnm.FLG_MAXRESULTS)
 	if err != nil {
 		lg.Error(err)
-		return err
+		return nil, "", err
 	}
 	glc = grps.AddMaxResults(glc, flgMaxResultsVal)
 
-	groups, err = grps.DoList(glc)
-	if err != nil {
-		return err
-	}
-
-	flgPagesVal, err := cmd.Flags().GetString(flgnm.FLG_PAGES)
-	if err != nil {
-		lg.Error(err)
-		return err
-	}
-	if flgPagesVal != "" {
-		err = doGrpPages(glc, groups, flgPagesVal)
-		if err != nil {
-			return err
-		}
-	}
-
-	jsonData, err = json.MarshalIndent(groups, "", "    ")
-	if err != nil {
-		lg.Error(err)
-		return err
-	}
-
-	flgCountVal, err := cmd.Flags().GetBool(flgnm.FLG_COUNT)
-	if err != nil {
-		lg.Error(err)
-		return err
-	}
-	if flgCountVal {
-		fmt.Println(len(groups.Groups))
-	} else {
-		fmt.Println(string(jsonData))
-	}
-
-	return nil
-}
-
-func doGrpAllPages(glc *admin.GroupsListCall, groups *admin.Groups) error {
-	lg.Debug("starting doGrpAllPages()")
-	defer lg.Debug("finished doGrpAllPages()")
-
-	if groups.NextPageToken != "" {
-		glc = grps.AddPageToken(glc, groups.NextPageToken)
-		nxtGroups, err := grps.DoList(glc)
-		if err != nil {
-			return err
-		}
-		groups.Groups = append(groups.Groups, nxtGroups.Groups...)
-		groups.Etag = nxtGroups.Etag
-		groups.NextPageToken = nxtGroups.NextPageToken
-
-		if nxtGroups.NextPageToken != "" {
-			doGrpAllPages(glc, groups)
-		}
-	}
-
-	return nil
-}
-
-func doGrpNumPages(glc *admin.GroupsListCall, groups *admin.Groups, numPages int) error {
-	lg.Debugw("starting doGrpNumPages()",
-		"numPages", numPages)
-	defer lg.Debug("finished doGrpNumPages()")
-
-	if groups.NextPageToken != "" && numPages > 0 {
-		glc = grps.AddPageToken(glc, groups.NextPageToken)
-		nxtGroups, err := grps.DoList(glc)
-		if err != nil {
-			return err
-		}
-		groups.Groups = append(groups.Groups, nxtGroups.Groups...)
-		groups.Etag = nxtGroups.Etag
-		groups.NextPageToken = nxtGroups.NextPageToken
-
-		if nxtGroups.NextPageToken != "" {
-			doGrpNumPages(glc, groups, numPages-1)
-		}
-	}
-
-	return nil
-}
-
-func doGrpPages(glc *admin.GroupsListCall, groups *admin.Groups, pages string) error {
-	lg.Debugw("starting doGrpPages()",
-		"pages", pages)
-	defer lg.Debug("finished doGrpPages()")
-
-	if pages == "all" {
-		err := doGrpAllPages(glc, groups)
-		if err != nil {
-			return err
-		}
-	} else {
-		numPages, err := strconv.Atoi(pages)
-		if err != nil {
-			err = errors.New(gmess.ERR_INVALIDPAGESARGUMENT)
-			lg.Error(err)
-			return err
-		}
-
-		if numPages > 1 {
-			err = doGrpNumPages(glc, groups, numPages-1)
-			if err != nil {
-				return err
-			}
-		}
-	}
-
-	return nil
+	return glc, listAttrs, nil
 }
 
 func init() {
@@ -290,10 +254,12 @@ func init() {
 
 	listGroupsCmd.Flags().StringVarP(&attrs, flgnm.FLG_ATTRIBUTES, "a", "", "required group attributes (separated by ~)")
 	listGroupsCmd.Flags().BoolVarP(&count, flgnm.FLG_COUNT, "", false, "count number of entities returned")
-	listGroupsCmd.Flags().StringVarP(&domain, flgnm.FLG_DOMAIN, "d", "", "domain from which to get groups")
+	listGroupsCmd.Flags().StringVarP(&domain, flgnm.FLG_DOMAIN, "d", "", "domain from which to get groups (default is the domain of the active profile)")
 	listGroupsCmd.Flags().Int64VarP(&maxResults, flgnm.FLG_MAXRESULTS, "m", 200, "maximum number of results to return per page")
 	listGroupsCmd.Flags().StringVarP(&orderBy, flgnm.FLG_ORDERBY, "o", "", "field by which results will be ordered")
+	listGroupsCmd.Flags().StringVar(&pageToken, flgnm.FLG_PAGETOKEN, "", "page token to resume listing from")
 	listGroupsCmd.Flags().StringVarP(&pages, flgnm.FLG_PAGES, "p", "", "number of pages of results to be returned ('all' or a number)")
+	listGroupsCmd.Flags().IntVar(&parallel, flgnm.FLG_PARALLEL, 0, "number of domains to list in parallel")
 	listGroupsCmd.Flags().StringVarP(&query, flgnm.FLG_QUERY, "q", "", "selection criteria to get groups (separated by ~)")
 	listGroupsCmd.Flags().StringVarP(&sortOrder, flgnm.FLG_SORTORDER, "s", "", "sort order of returned results")
 	listGroupsCmd.Flags().StringVarP(&userKey, flgnm.FLG_USERKEY, "u", "", "email address or id of user who belongs to returned groups")
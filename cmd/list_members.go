@@ -23,13 +23,15 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"strconv"
+	"strings"
 
 	cmn "github.com/plusworx/gmin/utils/common"
 	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	fmtrs "github.com/plusworx/gmin/utils/formatters"
 	gmess "github.com/plusworx/gmin/utils/gminmessages"
 	gpars "github.com/plusworx/gmin/utils/gminparsers"
 	lg "github.com/plusworx/gmin/utils/logging"
@@ -43,10 +45,16 @@ var listMembersCmd = &cobra.Command{
 	Aliases: []string{"group-member", "grp-members", "grp-member", "grp-mems", "grp-mem", "gmembers", "gmember", "gmems", "gmem"},
 	Args:    cobra.ExactArgs(1),
 	Example: `gmin list group-members mygroup@mycompany.com -r OWNER~MANAGER
-gmin ls gmems mygroup@mycompany.com -a email`,
+gmin ls gmems mygroup@mycompany.com -a email
+gmin ls gmems mygroup@mycompany.com --recursive -o table`,
 	Short: "Outputs a list of group members",
-	Long:  `Outputs a list of group members. Must specify a group email address or id.`,
-	RunE:  doListMembers,
+	Long: `Outputs a list of group members. Must specify a group email address or id.
+
+--recursive also lists the members of nested groups. Each member is shown once with the path of groups
+that leads from the group to the group that they belong to directly. Groups that are members of
+themselves, directly or through other groups, are listed as cycles and are only expanded once.
+--attributes and --pages cannot be used with --recursive.`,
+	RunE: doListMembers,
 }
 
 func doListMembers(cmd *cobra.Command, args []string) error {
@@ -55,16 +63,30 @@ func doListMembers(cmd *cobra.Command, args []string) error {
 	defer lg.Debug("finished doListMembers()")
 
 	var (
-		jsonData []byte
-		members  *admin.Members
+		listAttrs string
+		members   *admin.Members
 	)
 
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
 	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryGroupMemberReadonlyScope)
 	if err != nil {
 		return err
 	}
 	ds := srv.(*admin.Service)
 
+	flgRecursiveVal, err := cmd.Flags().GetBool(flgnm.FLG_RECURSIVE)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgRecursiveVal {
+		return lmRecursive(cmd, ds, args[0], outputFmt)
+	}
+
 	mlc := ds.Members.List(args[0])
 
 	flgAttrsVal, err := cmd.Flags().GetString(flgnm.FLG_ATTRIBUTES)
@@ -73,7 +95,7 @@ func doListMembers(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	if flgAttrsVal != "" {
-		listAttrs, err := gpars.ParseOutputAttrs(flgAttrsVal, mems.MemberAttrMap)
+		listAttrs, err = gpars.ParseOutputAttrs(flgAttrsVal, mems.MemberAttrMap)
 		if err != nil {
 			return err
 		}
@@ -120,9 +142,8 @@ func doListMembers(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	jsonData, err = json.MarshalIndent(members, "", "    ")
+	err = filterList(cmd, members, mems.LISTKEY)
 	if err != nil {
-		lg.Error(err)
 		return err
 	}
 
@@ -134,7 +155,10 @@ func doListMembers(cmd *cobra.Command, args []string) error {
 	if flgCountVal {
 		fmt.Println(len(members.Members))
 	} else {
-		fmt.Println(string(jsonData))
+		err = fmtrs.Output(os.Stdout, outputFmt, members, mems.LISTKEY, listAttrs)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -221,5 +245,89 @@ func init() {
 	listMembersCmd.Flags().BoolVarP(&count, flgnm.FLG_COUNT, "", false, "count number of entities returned")
 	listMembersCmd.Flags().Int64VarP(&maxResults, flgnm.FLG_MAXRESULTS, "m", 200, "maximum number or results to return")
 	listMembersCmd.Flags().StringVarP(&pages, flgnm.FLG_PAGES, "p", "", "number of pages of results to be returned ('all' or a number)")
+	listMembersCmd.Flags().BoolVar(&recursive, flgnm.FLG_RECURSIVE, false, "include members of nested groups")
 	listMembersCmd.Flags().StringVarP(&role, flgnm.FLG_ROLES, "r", "", "roles to filter results by (separated by ~)")
 }
+
+// lmLister returns a function that lists all of the members of a group
+func lmLister(ds *admin.Service) mems.MemberLister {
+	return func(groupKey string) ([]*admin.Member, error) {
+		lg.Debugw("starting lmLister()",
+			"groupKey", groupKey)
+		defer lg.Debug("finished lmLister()")
+
+		var members []*admin.Member
+
+		mlc := ds.Members.List(groupKey)
+		mlc = mems.AddFields(mlc, "nextPageToken,members(email,id,role,status,type)").(*admin.MembersListCall)
+		for {
+			resp, err := mems.DoList(mlc)
+			if err != nil {
+				return nil, err
+			}
+			members = append(members, resp.Members...)
+			if resp.NextPageToken == "" {
+				break
+			}
+			mlc = mlc.PageToken(resp.NextPageToken)
+		}
+		return members, nil
+	}
+}
+
+func lmRecursive(cmd *cobra.Command, ds *admin.Service, groupKey string, outputFmt string) error {
+	lg.Debugw("starting lmRecursive()",
+		"groupKey", groupKey)
+	defer lg.Debug("finished lmRecursive()")
+
+	for _, flName := range []string{flgnm.FLG_ATTRIBUTES, flgnm.FLG_PAGES} {
+		if cmd.Flags().Changed(flName) {
+			err := fmt.Errorf(gmess.ERR_RECURSIVEFLAG, flName)
+			lg.Error(err)
+			return err
+		}
+	}
+
+	nested, err := mems.Expand(groupKey, lmLister(ds), cmn.LOOKUPWORKERS)
+	if err != nil {
+		return err
+	}
+
+	flgRolesVal, err := cmd.Flags().GetString(flgnm.FLG_ROLES)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgRolesVal != "" {
+		formattedRoles, err := gpars.ParseOutputAttrs(flgRolesVal, mems.RoleMap)
+		if err != nil {
+			return err
+		}
+		roles := strings.Split(formattedRoles, ",")
+
+		filtered := []*mems.NestedMember{}
+		for _, member := range nested.Members {
+			if cmn.SliceContainsStr(roles, member.Role) {
+				filtered = append(filtered, member)
+			}
+		}
+		nested.Members = filtered
+	}
+
+	err = filterList(cmd, nested, mems.LISTKEY)
+	if err != nil {
+		return err
+	}
+
+	flgCountVal, err := cmd.Flags().GetBool(flgnm.FLG_COUNT)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgCountVal {
+		fmt.Println(len(nested.Members))
+		return nil
+	}
+
+	return fmtrs.Output(os.Stdout, outputFmt, nested, mems.LISTKEY, "")
+}
@@ -23,10 +23,7 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
-	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 
 	cmn "github.com/plusworx/gmin/utils/common"
@@ -47,8 +44,13 @@ var listMobDevsCmd = &cobra.Command{
 	Example: `gmin list mobile-devices --pages all --count
 gmin ls mdevs --pages all`,
 	Short: "Outputs a list of mobile devices",
-	Long:  `Outputs a list of mobile devices.`,
-	RunE:  doListMobDevs,
+	Long: `Outputs a list of mobile devices.
+
+JSONL, CSV and TSV output and counts are written as each page of results arrives. CSV and TSV columns
+come from --attributes and the first page of results and listing stops with an error if a later page
+has attributes that are not in the columns. If listing stops with an error, --page-token resumes it
+from the page given in the error message.`,
+	RunE: doListMobDevs,
 }
 
 func doListMobDevs(cmd *cobra.Command, args []string) error {
@@ -56,9 +58,12 @@ func doListMobDevs(cmd *cobra.Command, args []string) error {
 		"args", args)
 	defer lg.Debug("finished doListMobDevs()")
 
-	var (
-		mobdevs *admin.MobileDevices
-	)
+	var listAttrs string
+
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
 
 	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryDeviceMobileReadonlyScope)
 	if err != nil {
@@ -79,11 +84,11 @@ func doListMobDevs(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	if flgAttrsVal != "" {
-		listAttrs, err := gpars.ParseOutputAttrs(flgAttrsVal, mdevs.MobDevAttrMap)
+		listAttrs, err = gpars.ParseOutputAttrs(flgAttrsVal, mdevs.MobDevAttrMap)
 		if err != nil {
 			return err
 		}
-		formattedAttrs := mdevs.STARTMOBDEVICESFIELD + listAttrs + mdevs.ENDFIELD
+		formattedAttrs := "nextPageToken," + mdevs.STARTMOBDEVICESFIELD + listAttrs + mdevs.ENDFIELD
 		listCall := mdevs.AddFields(mdlc, formattedAttrs)
 		mdlc = listCall.(*admin.MobiledevicesListCall)
 	}
@@ -164,115 +169,38 @@ func doListMobDevs(cmd *cobra.Command, args []string) error {
 	}
 	mdlc = mdevs.AddMaxResults(mdlc, flgMaxResultsVal)
 
-	mobdevs, err = mdevs.DoList(mdlc)
-	if err != nil {
-		return err
-	}
-
-	flgPagesVal, err := cmd.Flags().GetString(flgnm.FLG_PAGES)
-	if err != nil {
-		lg.Error(err)
-		return err
-	}
-	if flgPagesVal != "" {
-		err = doMobDevPages(mdlc, mobdevs, flgPagesVal)
-		if err != nil {
-			return err
-		}
-	}
-
-	jsonData, err := json.MarshalIndent(mobdevs, "", "    ")
-	if err != nil {
-		lg.Error(err)
-		return err
-	}
-
-	flgCountVal, err := cmd.Flags().GetBool(flgnm.FLG_COUNT)
-	if err != nil {
-		lg.Error(err)
-		return err
-	}
-	if flgCountVal {
-		fmt.Println(len(mobdevs.Mobiledevices))
-	} else {
-		fmt.Println(string(jsonData))
-	}
-
-	return nil
-}
-
-func doMobDevAllPages(mdlc *admin.MobiledevicesListCall, mobdevs *admin.MobileDevices) error {
-	lg.Debug("starting doMobDevAllPages()")
-	defer lg.Debug("finished doMobDevAllPages()")
-
-	if mobdevs.NextPageToken != "" {
-		mdlc = mdevs.AddPageToken(mdlc, mobdevs.NextPageToken)
-		nxtMobDevs, err := mdevs.DoList(mdlc)
-		if err != nil {
-			return err
-		}
-		mobdevs.Mobiledevices = append(mobdevs.Mobiledevices, nxtMobDevs.Mobiledevices...)
-		mobdevs.Etag = nxtMobDevs.Etag
-		mobdevs.NextPageToken = nxtMobDevs.NextPageToken
-
-		if nxtMobDevs.NextPageToken != "" {
-			doMobDevAllPages(mdlc, mobdevs)
-		}
-	}
-
-	return nil
-}
-
-func doMobDevNumPages(mdlc *admin.MobiledevicesListCall, mobdevs *admin.MobileDevices, numPages int) error {
-	lg.Debugw("starting doMobDevNumPages()",
-		"numPages", numPages)
-	defer lg.Debug("finished doMobDevNumPages()")
-
-	if mobdevs.NextPageToken != "" && numPages > 0 {
-		mdlc = mdevs.AddPageToken(mdlc, mobdevs.NextPageToken)
-		nxtMobDevs, err := mdevs.DoList(mdlc)
-		if err != nil {
-			return err
-		}
-		mobdevs.Mobiledevices = append(mobdevs.Mobiledevices, nxtMobDevs.Mobiledevices...)
-		mobdevs.Etag = nxtMobDevs.Etag
-		mobdevs.NextPageToken = nxtMobDevs.NextPageToken
-
-		if nxtMobDevs.NextPageToken != "" {
-			doMobDevNumPages(mdlc, mobdevs, numPages-1)
-		}
-	}
-
-	return nil
+	mobdevs := new(admin.MobileDevices)
+	pl := pagedList{
+		fields:  listAttrs,
+		listKey: mdevs.LISTKEY,
+		merge: func(page interface{}) {
+			pgMobDevs := page.(*admin.MobileDevices)
+			mobdevs.Mobiledevices = append(mobdevs.Mobiledevices, pgMobDevs.Mobiledevices...)
+			mobdevs.Etag = pgMobDevs.Etag
+			mobdevs.Kind = pgMobDevs.Kind
+			mobdevs.NextPageToken = pgMobDevs.NextPageToken
+		},
+		result: mobdevs,
+		size: func(page interface{}) int {
+			return len(page.(*admin.MobileDevices).Mobiledevices)
+		},
+	}
+
+	return listPages(cmd, outputFmt, pl, lmdFetch(mdlc), nil, nil)
 }
 
-func doMobDevPages(mdlc *admin.MobiledevicesListCall, mobdevs *admin.MobileDevices, pages string) error {
-	lg.Debugw("starting doMobDevPages()",
-		"pages", pages)
-	defer lg.Debug("finished doMobDevPages()")
-
-	if pages == "all" {
-		err := doMobDevAllPages(mdlc, mobdevs)
-		if err != nil {
-			return err
+// lmdFetch returns a function that gets a page of mobile devices
+func lmdFetch(mdlc *admin.MobiledevicesListCall) cmn.PageFunc {
+	return func(pageToken string) (interface{}, string, error) {
+		if pageToken != "" {
+			mdlc = mdevs.AddPageToken(mdlc, pageToken)
 		}
-	} else {
-		numPages, err := strconv.Atoi(pages)
+		mobdevs, err := mdevs.DoList(mdlc)
 		if err != nil {
-			err = errors.New(gmess.ERR_INVALIDPAGESARGUMENT)
-			lg.Error(err)
-			return err
-		}
-
-		if numPages > 1 {
-			err = doMobDevNumPages(mdlc, mobdevs, numPages-1)
-			if err != nil {
-				return err
-			}
+			return nil, "", err
 		}
+		return mobdevs, mobdevs.NextPageToken, nil
 	}
-
-	return nil
 }
 
 func init() {
@@ -282,6 +210,7 @@ func init() {
 	listMobDevsCmd.Flags().BoolVarP(&count, flgnm.FLG_COUNT, "", false, "count number of entities returned")
 	listMobDevsCmd.Flags().Int64VarP(&maxResults, flgnm.FLG_MAXRESULTS, "m", 100, "maximum number of results to return per page")
 	listMobDevsCmd.Flags().StringVarP(&orderBy, flgnm.FLG_ORDERBY, "o", "", "field by which results will be ordered")
+	listMobDevsCmd.Flags().StringVar(&pageToken, flgnm.FLG_PAGETOKEN, "", "page token to resume listing from")
 	listMobDevsCmd.Flags().StringVarP(&pages, flgnm.FLG_PAGES, "p", "", "number of pages of results to be returned ('all' or a number)")
 	listMobDevsCmd.Flags().StringVarP(&projection, flgnm.FLG_PROJECTION, "j", "", "type of projection")
 	listMobDevsCmd.Flags().StringVarP(&query, flgnm.FLG_QUERY, "q", "", "selection criteria to get devices (separated by ~)")
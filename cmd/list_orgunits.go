@@ -23,13 +23,14 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
 
 	cmn "github.com/plusworx/gmin/utils/common"
 	cfg "github.com/plusworx/gmin/utils/config"
 	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	fmtrs "github.com/plusworx/gmin/utils/formatters"
 	gmess "github.com/plusworx/gmin/utils/gminmessages"
 	gpars "github.com/plusworx/gmin/utils/gminparsers"
 	lg "github.com/plusworx/gmin/utils/logging"
@@ -55,10 +56,15 @@ func doListOUs(cmd *cobra.Command, args []string) error {
 	defer lg.Debug("finished doListOUs()")
 
 	var (
-		jsonData []byte
-		orgUnits *admin.OrgUnits
+		listAttrs string
+		orgUnits  *admin.OrgUnits
 	)
 
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
 	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryOrgunitReadonlyScope)
 	if err != nil {
 		return err
@@ -78,7 +84,7 @@ func doListOUs(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	if flgAttrsVal != "" {
-		listAttrs, err := gpars.ParseOutputAttrs(flgAttrsVal, ous.OrgUnitAttrMap)
+		listAttrs, err = gpars.ParseOutputAttrs(flgAttrsVal, ous.OrgUnitAttrMap)
 		if err != nil {
 			return err
 		}
@@ -117,9 +123,8 @@ func doListOUs(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	jsonData, err = json.MarshalIndent(orgUnits, "", "    ")
+	err = filterList(cmd, orgUnits, ous.LISTKEY)
 	if err != nil {
-		lg.Error(err)
 		return err
 	}
 
@@ -131,7 +136,10 @@ func doListOUs(cmd *cobra.Command, args []string) error {
 	if flgCountVal {
 		fmt.Println(len(orgUnits.OrganizationUnits))
 	} else {
-		fmt.Println(string(jsonData))
+		err = fmtrs.Output(os.Stdout, outputFmt, orgUnits, ous.LISTKEY, listAttrs)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -0,0 +1,127 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	cfg "github.com/plusworx/gmin/utils/config"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	fmtrs "github.com/plusworx/gmin/utils/formatters"
+	gpars "github.com/plusworx/gmin/utils/gminparsers"
+	lg "github.com/plusworx/gmin/utils/logging"
+	rls "github.com/plusworx/gmin/utils/roles"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var listPrivsCmd = &cobra.Command{
+	Use:     "privileges",
+	Aliases: []string{"privilege", "privs", "priv"},
+	Args:    cobra.NoArgs,
+	Example: `gmin list privileges -a privilegename~servicename
+gmin ls privs --count`,
+	Short: "Outputs a list of privileges that can be given to admin roles",
+	Long:  `Outputs a list of privileges that can be given to admin roles.`,
+	RunE:  doListPrivs,
+}
+
+func doListPrivs(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doListPrivs()",
+		"args", args)
+	defer lg.Debug("finished doListPrivs()")
+
+	var (
+		listAttrs  string
+		privileges *admin.Privileges
+	)
+
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryRolemanagementReadonlyScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
+	if err != nil {
+		return err
+	}
+
+	plc := ds.Privileges.List(customerID)
+
+	flgAttrsVal, err := cmd.Flags().GetString(flgnm.FLG_ATTRIBUTES)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgAttrsVal != "" {
+		listAttrs, err = gpars.ParseOutputAttrs(flgAttrsVal, rls.PrivilegeAttrMap)
+		if err != nil {
+			return err
+		}
+		formattedAttrs := rls.STARTITEMSFIELD + listAttrs + rls.ENDFIELD
+
+		listCall := rls.AddFields(plc, formattedAttrs)
+		plc = listCall.(*admin.PrivilegesListCall)
+	}
+
+	privileges, err = rls.DoListPrivileges(plc)
+	if err != nil {
+		return err
+	}
+
+	err = filterList(cmd, privileges, rls.LISTKEY)
+	if err != nil {
+		return err
+	}
+
+	flgCountVal, err := cmd.Flags().GetBool(flgnm.FLG_COUNT)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgCountVal {
+		fmt.Println(len(privileges.Items))
+	} else {
+		err = fmtrs.Output(os.Stdout, outputFmt, privileges, rls.LISTKEY, listAttrs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func init() {
+	listCmd.AddCommand(listPrivsCmd)
+
+	listPrivsCmd.Flags().StringVarP(&attrs, flgnm.FLG_ATTRIBUTES, "a", "", "required privilege attributes (separated by ~)")
+	listPrivsCmd.Flags().BoolVarP(&count, flgnm.FLG_COUNT, "", false, "count number of entities returned")
+}
@@ -0,0 +1,252 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	cfg "github.com/plusworx/gmin/utils/config"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	fmtrs "github.com/plusworx/gmin/utils/formatters"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	gpars "github.com/plusworx/gmin/utils/gminparsers"
+	lg "github.com/plusworx/gmin/utils/logging"
+	rls "github.com/plusworx/gmin/utils/roles"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var listRoleAssignsCmd = &cobra.Command{
+	Use:     "role-assignments",
+	Aliases: []string{"role-assignment", "role-asgmts", "role-asgmt", "rasgmts", "rasgmt", "ras", "ra"},
+	Args:    cobra.NoArgs,
+	Example: `gmin list role-assignments -r "Helpdesk Admin"
+gmin ls ras -u mickey.mouse@disney.com -a roleid~scopetype~orgunitid`,
+	Short: "Outputs a list of admin role assignments",
+	Long:  `Outputs a list of admin role assignments, optionally for one role or one user.`,
+	RunE:  doListRoleAssigns,
+}
+
+func doListRoleAssigns(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doListRoleAssigns()",
+		"args", args)
+	defer lg.Debug("finished doListRoleAssigns()")
+
+	var (
+		listAttrs   string
+		roleAssigns *admin.RoleAssignments
+	)
+
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryRolemanagementReadonlyScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
+	if err != nil {
+		return err
+	}
+
+	ralc := ds.RoleAssignments.List(customerID)
+
+	flgAttrsVal, err := cmd.Flags().GetString(flgnm.FLG_ATTRIBUTES)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgAttrsVal != "" {
+		listAttrs, err = gpars.ParseOutputAttrs(flgAttrsVal, rls.RoleAssignmentAttrMap)
+		if err != nil {
+			return err
+		}
+		formattedAttrs := rls.STARTITEMSFIELD + listAttrs + rls.ENDFIELD
+
+		listCall := rls.AddFields(ralc, formattedAttrs)
+		ralc = listCall.(*admin.RoleAssignmentsListCall)
+	}
+
+	flgRoleVal, err := cmd.Flags().GetString(flgnm.FLG_ROLE)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgRoleVal != "" {
+		roleID, err := rls.RoleID(ds, customerID, flgRoleVal)
+		if err != nil {
+			return err
+		}
+		ralc = ralc.RoleId(roleID)
+	}
+
+	flgUserKeyVal, err := cmd.Flags().GetString(flgnm.FLG_USERKEY)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgUserKeyVal != "" {
+		ralc = ralc.UserKey(flgUserKeyVal)
+	}
+
+	flgMaxResultsVal, err := cmd.Flags().GetInt64(flgnm.FLG_MAXRESULTS)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	listCall := rls.AddMaxResults(ralc, flgMaxResultsVal)
+	ralc = listCall.(*admin.RoleAssignmentsListCall)
+
+	roleAssigns, err = rls.DoListAssignments(ralc)
+	if err != nil {
+		return err
+	}
+
+	flgPagesVal, err := cmd.Flags().GetString(flgnm.FLG_PAGES)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgPagesVal != "" {
+		err = doRoleAssignPages(ralc, roleAssigns, flgPagesVal)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = filterList(cmd, roleAssigns, rls.LISTKEY)
+	if err != nil {
+		return err
+	}
+
+	flgCountVal, err := cmd.Flags().GetBool(flgnm.FLG_COUNT)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgCountVal {
+		fmt.Println(len(roleAssigns.Items))
+	} else {
+		err = fmtrs.Output(os.Stdout, outputFmt, roleAssigns, rls.LISTKEY, listAttrs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func doRoleAssignAllPages(ralc *admin.RoleAssignmentsListCall, roleAssigns *admin.RoleAssignments) error {
+	lg.Debug("starting doRoleAssignAllPages()")
+	defer lg.Debug("finished doRoleAssignAllPages()")
+
+	if roleAssigns.NextPageToken != "" {
+		listCall := rls.AddPageToken(ralc, roleAssigns.NextPageToken)
+		ralc = listCall.(*admin.RoleAssignmentsListCall)
+		nxtRoleAssigns, err := rls.DoListAssignments(ralc)
+		if err != nil {
+			return err
+		}
+		roleAssigns.Items = append(roleAssigns.Items, nxtRoleAssigns.Items...)
+		roleAssigns.Etag = nxtRoleAssigns.Etag
+		roleAssigns.NextPageToken = nxtRoleAssigns.NextPageToken
+
+		if nxtRoleAssigns.NextPageToken != "" {
+			return doRoleAssignAllPages(ralc, roleAssigns)
+		}
+	}
+
+	return nil
+}
+
+func doRoleAssignNumPages(ralc *admin.RoleAssignmentsListCall, roleAssigns *admin.RoleAssignments, numPages int) error {
+	lg.Debugw("starting doRoleAssignNumPages()",
+		"numPages", numPages)
+	defer lg.Debug("finished doRoleAssignNumPages()")
+
+	if roleAssigns.NextPageToken != "" && numPages > 0 {
+		listCall := rls.AddPageToken(ralc, roleAssigns.NextPageToken)
+		ralc = listCall.(*admin.RoleAssignmentsListCall)
+		nxtRoleAssigns, err := rls.DoListAssignments(ralc)
+		if err != nil {
+			return err
+		}
+		roleAssigns.Items = append(roleAssigns.Items, nxtRoleAssigns.Items...)
+		roleAssigns.Etag = nxtRoleAssigns.Etag
+		roleAssigns.NextPageToken = nxtRoleAssigns.NextPageToken
+
+		if nxtRoleAssigns.NextPageToken != "" {
+			return doRoleAssignNumPages(ralc, roleAssigns, numPages-1)
+		}
+	}
+
+	return nil
+}
+
+func doRoleAssignPages(ralc *admin.RoleAssignmentsListCall, roleAssigns *admin.RoleAssignments, pages string) error {
+	lg.Debugw("starting doRoleAssignPages()",
+		"pages", pages)
+	defer lg.Debug("finished doRoleAssignPages()")
+
+	if pages == "all" {
+		err := doRoleAssignAllPages(ralc, roleAssigns)
+		if err != nil {
+			return err
+		}
+	} else {
+		numPages, err := strconv.Atoi(pages)
+		if err != nil {
+			err = errors.New(gmess.ERR_INVALIDPAGESARGUMENT)
+			lg.Error(err)
+			return err
+		}
+
+		if numPages > 1 {
+			err = doRoleAssignNumPages(ralc, roleAssigns, numPages-1)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func init() {
+	listCmd.AddCommand(listRoleAssignsCmd)
+
+	listRoleAssignsCmd.Flags().StringVarP(&attrs, flgnm.FLG_ATTRIBUTES, "a", "", "required role assignment attributes (separated by ~)")
+	listRoleAssignsCmd.Flags().BoolVarP(&count, flgnm.FLG_COUNT, "", false, "count number of entities returned")
+	listRoleAssignsCmd.Flags().Int64VarP(&maxResults, flgnm.FLG_MAXRESULTS, "m", 100, "maximum number of results to return per page")
+	listRoleAssignsCmd.Flags().StringVarP(&pages, flgnm.FLG_PAGES, "p", "", "number of pages of results to be returned ('all' or a number)")
+	listRoleAssignsCmd.Flags().StringVarP(&role, flgnm.FLG_ROLE, "r", "", "name or id of role whose assignments are returned")
+	listRoleAssignsCmd.Flags().StringVarP(&userKey, flgnm.FLG_USERKEY, "u", "", "email address or id of user whose assignments are returned")
+}
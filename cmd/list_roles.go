@@ -0,0 +1,228 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	cfg "github.com/plusworx/gmin/utils/config"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	fmtrs "github.com/plusworx/gmin/utils/formatters"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	gpars "github.com/plusworx/gmin/utils/gminparsers"
+	lg "github.com/plusworx/gmin/utils/logging"
+	rls "github.com/plusworx/gmin/utils/roles"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var listRolesCmd = &cobra.Command{
+	Use:     "roles",
+	Aliases: []string{"role"},
+	Args:    cobra.NoArgs,
+	Example: `gmin list roles -a rolename~roleid
+gmin ls roles -p all --count`,
+	Short: "Outputs a list of admin roles",
+	Long:  `Outputs a list of admin roles.`,
+	RunE:  doListRoles,
+}
+
+func doListRoles(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doListRoles()",
+		"args", args)
+	defer lg.Debug("finished doListRoles()")
+
+	var (
+		listAttrs string
+		roles     *admin.Roles
+	)
+
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryRolemanagementReadonlyScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
+	if err != nil {
+		return err
+	}
+
+	rlc := ds.Roles.List(customerID)
+
+	flgAttrsVal, err := cmd.Flags().GetString(flgnm.FLG_ATTRIBUTES)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgAttrsVal != "" {
+		listAttrs, err = gpars.ParseOutputAttrs(flgAttrsVal, rls.RoleAttrMap)
+		if err != nil {
+			return err
+		}
+		formattedAttrs := rls.STARTITEMSFIELD + listAttrs + rls.ENDFIELD
+
+		listCall := rls.AddFields(rlc, formattedAttrs)
+		rlc = listCall.(*admin.RolesListCall)
+	}
+
+	flgMaxResultsVal, err := cmd.Flags().GetInt64(flgnm.FLG_MAXRESULTS)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	listCall := rls.AddMaxResults(rlc, flgMaxResultsVal)
+	rlc = listCall.(*admin.RolesListCall)
+
+	roles, err = rls.DoList(rlc)
+	if err != nil {
+		return err
+	}
+
+	flgPagesVal, err := cmd.Flags().GetString(flgnm.FLG_PAGES)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgPagesVal != "" {
+		err = doRolePages(rlc, roles, flgPagesVal)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = filterList(cmd, roles, rls.LISTKEY)
+	if err != nil {
+		return err
+	}
+
+	flgCountVal, err := cmd.Flags().GetBool(flgnm.FLG_COUNT)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgCountVal {
+		fmt.Println(len(roles.Items))
+	} else {
+		err = fmtrs.Output(os.Stdout, outputFmt, roles, rls.LISTKEY, listAttrs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func doRoleAllPages(rlc *admin.RolesListCall, roles *admin.Roles) error {
+	lg.Debug("starting doRoleAllPages()")
+	defer lg.Debug("finished doRoleAllPages()")
+
+	if roles.NextPageToken != "" {
+		listCall := rls.AddPageToken(rlc, roles.NextPageToken)
+		rlc = listCall.(*admin.RolesListCall)
+		nxtRoles, err := rls.DoList(rlc)
+		if err != nil {
+			return err
+		}
+		roles.Items = append(roles.Items, nxtRoles.Items...)
+		roles.Etag = nxtRoles.Etag
+		roles.NextPageToken = nxtRoles.NextPageToken
+
+		if nxtRoles.NextPageToken != "" {
+			return doRoleAllPages(rlc, roles)
+		}
+	}
+
+	return nil
+}
+
+func doRoleNumPages(rlc *admin.RolesListCall, roles *admin.Roles, numPages int) error {
+	lg.Debugw("starting doRoleNumPages()",
+		"numPages", numPages)
+	defer lg.Debug("finished doRoleNumPages()")
+
+	if roles.NextPageToken != "" && numPages > 0 {
+		listCall := rls.AddPageToken(rlc, roles.NextPageToken)
+		rlc = listCall.(*admin.RolesListCall)
+		nxtRoles, err := rls.DoList(rlc)
+		if err != nil {
+			return err
+		}
+		roles.Items = append(roles.Items, nxtRoles.Items...)
+		roles.Etag = nxtRoles.Etag
+		roles.NextPageToken = nxtRoles.NextPageToken
+
+		if nxtRoles.NextPageToken != "" {
+			return doRoleNumPages(rlc, roles, numPages-1)
+		}
+	}
+
+	return nil
+}
+
+func doRolePages(rlc *admin.RolesListCall, roles *admin.Roles, pages string) error {
+	lg.Debugw("starting doRolePages()",
+		"pages", pages)
+	defer lg.Debug("finished doRolePages()")
+
+	if pages == "all" {
+		err := doRoleAllPages(rlc, roles)
+		if err != nil {
+			return err
+		}
+	} else {
+		numPages, err := strconv.Atoi(pages)
+		if err != nil {
+			err = errors.New(gmess.ERR_INVALIDPAGESARGUMENT)
+			lg.Error(err)
+			return err
+		}
+
+		if numPages > 1 {
+			err = doRoleNumPages(rlc, roles, numPages-1)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func init() {
+	listCmd.AddCommand(listRolesCmd)
+
+	listRolesCmd.Flags().StringVarP(&attrs, flgnm.FLG_ATTRIBUTES, "a", "", "required role attributes (separated by ~)")
+	listRolesCmd.Flags().BoolVarP(&count, flgnm.FLG_COUNT, "", false, "count number of entities returned")
+	listRolesCmd.Flags().Int64VarP(&maxResults, flgnm.FLG_MAXRESULTS, "m", 100, "maximum number of results to return per page")
+	listRolesCmd.Flags().StringVarP(&pages, flgnm.FLG_PAGES, "p", "", "number of pages of results to be returned ('all' or a number)")
+}
@@ -23,12 +23,13 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
+	"os"
 
 	cmn "github.com/plusworx/gmin/utils/common"
 	cfg "github.com/plusworx/gmin/utils/config"
 	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	fmtrs "github.com/plusworx/gmin/utils/formatters"
 	gpars "github.com/plusworx/gmin/utils/gminparsers"
 	lg "github.com/plusworx/gmin/utils/logging"
 	scs "github.com/plusworx/gmin/utils/schemas"
@@ -53,10 +54,15 @@ func doListSchemas(cmd *cobra.Command, args []string) error {
 	defer lg.Debug("finished doListSchemas()")
 
 	var (
-		jsonData []byte
-		schemas  *admin.Schemas
+		listAttrs string
+		schemas   *admin.Schemas
 	)
 
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
 	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryUserschemaReadonlyScope)
 	if err != nil {
 		return err
@@ -76,7 +82,7 @@ func doListSchemas(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	if flgAttrsVal != "" {
-		listAttrs, err := gpars.ParseOutputAttrs(flgAttrsVal, scs.SchemaAttrMap)
+		listAttrs, err = gpars.ParseOutputAttrs(flgAttrsVal, scs.SchemaAttrMap)
 		if err != nil {
 			return err
 		}
@@ -91,9 +97,8 @@ func doListSchemas(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	jsonData, err = json.MarshalIndent(schemas, "", "    ")
+	err = filterList(cmd, schemas, scs.LISTKEY)
 	if err != nil {
-		lg.Error(err)
 		return err
 	}
 
@@ -105,7 +110,10 @@ func doListSchemas(cmd *cobra.Command, args []string) error {
 	if flgCountVal {
 		fmt.Println(len(schemas.Schemas))
 	} else {
-		fmt.Println(string(jsonData))
+		err = fmtrs.Output(os.Stdout, outputFmt, schemas, scs.LISTKEY, listAttrs)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
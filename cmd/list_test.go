@@ -23,7 +23,10 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"strings"
 	"testing"
+
+	admin "google.golang.org/api/admin/directory/v1"
 )
 
 func TestDoListUser(t *testing.T) {
@@ -50,3 +53,359 @@ func TestDoListUser(t *testing.T) {
 		}
 	}
 }
+
+func TestListCmdsFakeServer(t *testing.T) {
+	fs := newFakeServer(t)
+	fs.AddUser(&admin.User{PrimaryEmail: "mickey.mouse@disney.com", Name: &admin.UserName{GivenName: "Mickey", FamilyName: "Mouse"}, OrgUnitPath: "/Characters"})
+	fs.AddUser(&admin.User{PrimaryEmail: "minnie.mouse@disney.com", Name: &admin.UserName{GivenName: "Minnie", FamilyName: "Mouse"}, Suspended: true})
+	fs.AddGroup(&admin.Group{Email: "cartoons@disney.com", Name: "Cartoons"})
+	fs.AddGroup(&admin.Group{Email: "villains@disney.com", Name: "Villains"})
+	fs.AddMember("cartoons@disney.com", &admin.Member{Email: "mickey.mouse@disney.com", Role: "OWNER"})
+	fs.AddMember("cartoons@disney.com", &admin.Member{Email: "minnie.mouse@disney.com"})
+	fs.AddOrgUnit(&admin.OrgUnit{Name: "Characters"})
+	fs.AddDomain(&admin.Domains{DomainName: "disney.com", IsPrimary: true})
+	fs.AddDomain(&admin.Domains{DomainName: "pixar.com"})
+	fs.AddDomainAlias(&admin.DomainAlias{DomainAliasName: "disney.co.uk", ParentDomainName: "disney.com"})
+
+	cases := []struct {
+		args        []string
+		expectedErr string
+		expectedOut string
+	}{
+		{
+			args:        []string{"list", "users", "-a", "primaryemail", "--output", "csv"},
+			expectedOut: "primaryEmail\nmickey.mouse@disney.com\nminnie.mouse@disney.com\n",
+		},
+		{
+			args:        []string{"list", "users", "-a", "primaryemail", "-q", "issuspended=true", "--output", "csv"},
+			expectedOut: "primaryEmail\nminnie.mouse@disney.com\n",
+		},
+		{
+			args:        []string{"list", "users", "--count"},
+			expectedOut: "2\n",
+		},
+		{
+			args:        []string{"list", "groups", "-a", "email", "-m", "1", "--output", "csv"},
+			expectedOut: "email\ncartoons@disney.com\n",
+		},
+		{
+			args:        []string{"list", "groups", "-m", "1", "-p", "all", "--count"},
+			expectedOut: "2\n",
+		},
+		{
+			args:        []string{"list", "group-members", "cartoons@disney.com", "-a", "email", "-r", "owner", "--output", "csv"},
+			expectedOut: "email\nmickey.mouse@disney.com\n",
+		},
+		{
+			args:        []string{"list", "orgunits", "-a", "orgunitpath", "--output", "csv"},
+			expectedOut: "orgUnitPath\n/Characters\n",
+		},
+		{
+			args:        []string{"list", "users", "-d", "disney.com", "--count"},
+			expectedOut: "2\n",
+		},
+		{
+			args:        []string{"list", "groups", "-d", "marvel.com"},
+			expectedErr: "domain not found: marvel.com",
+		},
+		{
+			args:        []string{"list", "domains", "-a", "domainname", "--output", "csv"},
+			expectedOut: "domainName\ndisney.com\npixar.com\n",
+		},
+		{
+			args:        []string{"list", "domain-aliases", "-d", "pixar.com", "--count"},
+			expectedOut: "0\n",
+		},
+		{
+			args:        []string{"list", "domain-aliases", "-d", "disney.com", "-a", "domainaliasname", "--output", "csv"},
+			expectedOut: "domainAliasName\ndisney.co.uk\n",
+		},
+		{
+			args:        []string{"list", "users", "--output", "xml"},
+			expectedErr: "invalid output format: xml",
+		},
+	}
+
+	for _, c := range cases {
+		out, got := runGmin(t, c.args...)
+
+		gotErr := ""
+		if got != nil {
+			gotErr = got.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("Got error: %v - expected error: %v", gotErr, c.expectedErr)
+		}
+		if out != c.expectedOut {
+			t.Errorf("Got output: %v - expected output: %v", out, c.expectedOut)
+		}
+	}
+}
+
+func TestListDomainFakeServer(t *testing.T) {
+	fs := newFakeServer(t)
+	fs.AddUser(&admin.User{PrimaryEmail: "mickey.mouse@disney.com"})
+	fs.AddGroup(&admin.Group{Email: "cartoons@disney.com", Name: "Cartoons"})
+	fs.AddDomain(&admin.Domains{DomainName: "disney.com", IsPrimary: true})
+	fs.AddDomainAlias(&admin.DomainAlias{DomainAliasName: "disney.co.uk", ParentDomainName: "disney.com"})
+
+	cases := []struct {
+		args           []string
+		expectedErr    string
+		expectedOut    string
+		expDomainsRead bool
+	}{
+		{
+			args:        []string{"list", "users", "-d", "disney.com", "--count"},
+			expectedOut: "1\n",
+		},
+		{
+			args:        []string{"list", "groups", "-d", "disney.co.uk", "--count"},
+			expectedOut: "0\n",
+		},
+		{
+			args:           []string{"list", "users", "-d", "marvel.com", "--count"},
+			expectedErr:    "domain not found: marvel.com",
+			expDomainsRead: true,
+		},
+		{
+			args:           []string{"list", "groups", "-d", "marvel.com", "--count"},
+			expectedErr:    "domain not found: marvel.com",
+			expDomainsRead: true,
+		},
+	}
+
+	for _, c := range cases {
+		fs.Requests = nil
+
+		out, got := runGmin(t, c.args...)
+
+		gotErr := ""
+		if got != nil {
+			gotErr = got.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("%v - got error: %v - expected error: %v", c.args, gotErr, c.expectedErr)
+		}
+		if out != c.expectedOut {
+			t.Errorf("%v - got output: %v - expected output: %v", c.args, out, c.expectedOut)
+		}
+
+		domainsRead := false
+		for _, req := range fs.Requests {
+			if strings.HasSuffix(req, "/domains") {
+				domainsRead = true
+			}
+		}
+		if domainsRead != c.expDomainsRead {
+			t.Errorf("%v - got domains read: %v - expected domains read: %v", c.args, domainsRead, c.expDomainsRead)
+		}
+	}
+}
+
+func TestListNestedFakeServer(t *testing.T) {
+	fs := newFakeServer(t)
+	fs.AddUser(&admin.User{PrimaryEmail: "mickey.mouse@disney.com"})
+	fs.AddUser(&admin.User{PrimaryEmail: "donald.duck@disney.com"})
+	fs.AddGroup(&admin.Group{Email: "cartoons@disney.com", Name: "Cartoons"})
+	fs.AddGroup(&admin.Group{Email: "mice@disney.com", Name: "Mice"})
+	fs.AddGroup(&admin.Group{Email: "classics@disney.com", Name: "Classics"})
+	fs.AddMember("cartoons@disney.com", &admin.Member{Email: "mice@disney.com", Role: "MEMBER", Type: "GROUP"})
+	fs.AddMember("cartoons@disney.com", &admin.Member{Email: "donald.duck@disney.com", Role: "OWNER", Type: "USER"})
+	fs.AddMember("mice@disney.com", &admin.Member{Email: "mickey.mouse@disney.com", Role: "MEMBER", Type: "USER"})
+	fs.AddMember("mice@disney.com", &admin.Member{Email: "classics@disney.com", Role: "MEMBER", Type: "GROUP"})
+	fs.AddMember("classics@disney.com", &admin.Member{Email: "cartoons@disney.com", Role: "MEMBER", Type: "GROUP"})
+	fs.AddMember("classics@disney.com", &admin.Member{Email: "donald.duck@disney.com", Role: "MEMBER", Type: "USER"})
+
+	cases := []struct {
+		args        []string
+		expectedErr string
+		expectedOut string
+	}{
+		{
+			args:        []string{"list", "group-members", "cartoons@disney.com", "--recursive", "--count"},
+			expectedOut: "4\n",
+		},
+		{
+			args:        []string{"list", "group-members", "cartoons@disney.com", "--recursive", "-r", "owner", "--output", "csv"},
+			expectedOut: "email,id,path,role,status,type\ndonald.duck@disney.com,100000000000000002,cartoons@disney.com,OWNER,ACTIVE,USER\n",
+		},
+		{
+			args:        []string{"list", "group-members", "cartoons@disney.com", "--recursive", "-a", "email"},
+			expectedErr: "--recursive cannot be used with --attributes",
+		},
+		{
+			args:        []string{"list", "user-groups", "mickey.mouse@disney.com", "--count"},
+			expectedOut: "3\n",
+		},
+		{
+			args: []string{"list", "user-groups", "mickey.mouse@disney.com", "--output", "csv"},
+			expectedOut: "direct,email,id,name,path\n" +
+				"true,mice@disney.com,100000000000000004,Mice,mice@disney.com\n" +
+				"false,cartoons@disney.com,100000000000000003,Cartoons,mice@disney.com;cartoons@disney.com\n" +
+				"false,classics@disney.com,100000000000000005,Classics,mice@disney.com;cartoons@disney.com;classics@disney.com\n",
+		},
+	}
+
+	for _, c := range cases {
+		out, got := runGmin(t, c.args...)
+
+		gotErr := ""
+		if got != nil {
+			gotErr = got.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("Got error: %v - expected error: %v", gotErr, c.expectedErr)
+		}
+		if out != c.expectedOut {
+			t.Errorf("Got output: %v - expected output: %v", out, c.expectedOut)
+		}
+	}
+}
+
+func TestListPagesFakeServer(t *testing.T) {
+	fs := newFakeServer(t)
+	fs.AddUser(&admin.User{PrimaryEmail: "mickey.mouse@disney.com"})
+	fs.AddUser(&admin.User{PrimaryEmail: "minnie.mouse@disney.com"})
+	fs.AddUser(&admin.User{PrimaryEmail: "buzz.lightyear@pixar.com"})
+	fs.AddDomain(&admin.Domains{DomainName: "disney.com", IsPrimary: true})
+	fs.AddDomain(&admin.Domains{DomainName: "pixar.com"})
+	fs.AddOrgUnit(&admin.OrgUnit{Name: "Characters"})
+	fs.AddCrOSDevice(&admin.ChromeOsDevice{SerialNumber: "ABC123", OrgUnitPath: "/"})
+	fs.AddCrOSDevice(&admin.ChromeOsDevice{SerialNumber: "DEF456", OrgUnitPath: "/Characters"})
+	fs.AddCrOSDevice(&admin.ChromeOsDevice{SerialNumber: "GHI789", Model: "Chromebook A", OrgUnitPath: "/Characters"})
+
+	cases := []struct {
+		args        []string
+		expectedErr string
+		expectedOut string
+	}{
+		{
+			args:        []string{"list", "chromeos-devices", "-t", "/Characters", "-m", "1", "-p", "all", "--output", "csv"},
+			expectedErr: "csv output stopped because later results have attributes that are not in the header: model - use jsonl output, --attributes or --sort-by",
+			expectedOut: "deviceId,orgUnitPath,serialNumber\n100000000000000006,/Characters,DEF456\n",
+		},
+		{
+			args:        []string{"list", "chromeos-devices", "-t", "/Characters", "-m", "1", "-p", "all", "--sort-by", "serialnumber", "--output", "csv"},
+			expectedOut: "deviceId,model,orgUnitPath,serialNumber\n100000000000000006,,/Characters,DEF456\n100000000000000007,Chromebook A,/Characters,GHI789\n",
+		},
+		{
+			args:        []string{"list", "chromeos-devices", "-t", "/Characters", "-a", "serialnumber~model", "-m", "1", "-p", "all", "--output", "tsv"},
+			expectedOut: "serialNumber\tmodel\nDEF456\t\nGHI789\tChromebook A\n",
+		},
+		{
+			args:        []string{"list", "users", "--where", "primaryEmail = 'nobody@disney.com'", "--output", "csv"},
+			expectedOut: "\n",
+		},
+		{
+			args:        []string{"list", "users", "--where", "primaryEmail = 'nobody@disney.com'", "-a", "primaryemail", "--output", "csv"},
+			expectedOut: "primaryEmail\n",
+		},
+		{
+			args:        []string{"list", "users", "--where", "primaryEmail = 'nobody@disney.com'", "-a", "primaryemail", "--sort-by", "primaryemail", "--output", "csv"},
+			expectedOut: "primaryEmail\n",
+		},
+		{
+			args:        []string{"list", "users", "-a", "primaryemail", "-m", "1", "-p", "all", "--output", "csv"},
+			expectedOut: "primaryEmail\nbuzz.lightyear@pixar.com\nmickey.mouse@disney.com\nminnie.mouse@disney.com\n",
+		},
+		{
+			args:        []string{"list", "users", "-a", "primaryemail", "-m", "1", "-p", "2", "--output", "jsonl"},
+			expectedOut: "{\"primaryEmail\":\"buzz.lightyear@pixar.com\"}\n{\"primaryEmail\":\"mickey.mouse@disney.com\"}\n",
+		},
+		{
+			args:        []string{"list", "users", "-a", "primaryemail", "-m", "1", "-p", "all", "--page-token", "2", "--output", "csv"},
+			expectedOut: "primaryEmail\nminnie.mouse@disney.com\n",
+		},
+		{
+			args:        []string{"list", "users", "-m", "1", "--parallel", "2", "--count"},
+			expectedOut: "3\n",
+		},
+		{
+			args:        []string{"list", "users", "-d", "pixar.com", "--parallel", "2", "--count"},
+			expectedOut: "1\n",
+		},
+		{
+			args:        []string{"list", "users", "--parallel", "2", "-p", "2"},
+			expectedErr: "--parallel cannot be used with --pages",
+		},
+		{
+			args:        []string{"list", "groups", "--parallel", "2", "--page-token", "1"},
+			expectedErr: "--parallel cannot be used with --page-token",
+		},
+		{
+			args:        []string{"list", "chromeos-devices", "-m", "1", "--parallel", "2", "--count"},
+			expectedOut: "3\n",
+		},
+		{
+			args:        []string{"list", "chromeos-devices", "-t", "/Characters", "-a", "serialnumber", "--output", "csv"},
+			expectedOut: "serialNumber\nDEF456\nGHI789\n",
+		},
+	}
+
+	for _, c := range cases {
+		out, got := runGmin(t, c.args...)
+
+		gotErr := ""
+		if got != nil {
+			gotErr = got.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("%v - got error: %v - expected error: %v", c.args, gotErr, c.expectedErr)
+		}
+		if out != c.expectedOut {
+			t.Errorf("%v - got output: %v - expected output: %v", c.args, out, c.expectedOut)
+		}
+	}
+}
+
+func TestListWhereFakeServer(t *testing.T) {
+	fs := newFakeServer(t)
+	fs.AddUser(&admin.User{PrimaryEmail: "mickey.mouse@disney.com", OrgUnitPath: "/Characters", IsEnrolledIn2Sv: true})
+	fs.AddUser(&admin.User{PrimaryEmail: "minnie.mouse@disney.com", OrgUnitPath: "/Characters", Suspended: true})
+	fs.AddUser(&admin.User{PrimaryEmail: "donald.duck@disney.com", OrgUnitPath: "/"})
+	fs.AddOrgUnit(&admin.OrgUnit{Name: "Characters"})
+	fs.AddOrgUnit(&admin.OrgUnit{Name: "Villains"})
+
+	cases := []struct {
+		args        []string
+		expectedErr string
+		expectedOut string
+	}{
+		{
+			args:        []string{"list", "users", "--where", "isEnrolledIn2Sv = false and orgUnitPath = '/Characters'", "-a", "primaryemail~isenrolledin2sv~orgunitpath", "--output", "csv"},
+			expectedOut: "primaryEmail,isEnrolledIn2Sv,orgUnitPath\nminnie.mouse@disney.com,,/Characters\n",
+		},
+		{
+			args:        []string{"list", "users", "-m", "1", "-p", "all", "--where", "not suspended", "--count"},
+			expectedOut: "2\n",
+		},
+		{
+			args:        []string{"list", "users", "--sort-by", "orgunitpath:desc~primaryemail:desc", "-a", "primaryemail", "--output", "csv"},
+			expectedOut: "primaryEmail\nminnie.mouse@disney.com\nmickey.mouse@disney.com\ndonald.duck@disney.com\n",
+		},
+		{
+			args:        []string{"list", "orgunits", "--where", "name ~ '^V'", "-a", "name~orgunitpath", "--output", "csv"},
+			expectedOut: "name,orgUnitPath\nVillains,/Villains\n",
+		},
+		{
+			args:        []string{"list", "users", "--where", "suspended ="},
+			expectedErr: "unexpected end of expression found in where expression",
+		},
+	}
+
+	for _, c := range cases {
+		out, got := runGmin(t, c.args...)
+
+		gotErr := ""
+		if got != nil {
+			gotErr = got.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("%v - got error: %v - expected error: %v", c.args, gotErr, c.expectedErr)
+		}
+		if out != c.expectedOut {
+			t.Errorf("%v - got output: %v - expected output: %v", c.args, out, c.expectedOut)
+		}
+	}
+}
@@ -23,11 +23,11 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
-	"fmt"
+	"os"
 
 	cmn "github.com/plusworx/gmin/utils/common"
 	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	fmtrs "github.com/plusworx/gmin/utils/formatters"
 	gpars "github.com/plusworx/gmin/utils/gminparsers"
 	lg "github.com/plusworx/gmin/utils/logging"
 	uas "github.com/plusworx/gmin/utils/useraliases"
@@ -51,7 +51,15 @@ func doListUserAliases(cmd *cobra.Command, args []string) error {
 		"args", args)
 	defer lg.Debug("finished doListUserAliases()")
 
-	var aliases *admin.Aliases
+	var (
+		aliases   *admin.Aliases
+		listAttrs string
+	)
+
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
 
 	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryUserAliasReadonlyScope)
 	if err != nil {
@@ -67,7 +75,7 @@ func doListUserAliases(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	if flgAttrsVal != "" {
-		listAttrs, err := gpars.ParseOutputAttrs(flgAttrsVal, uas.UserAliasAttrMap)
+		listAttrs, err = gpars.ParseOutputAttrs(flgAttrsVal, uas.UserAliasAttrMap)
 		if err != nil {
 			return err
 		}
@@ -81,13 +89,15 @@ func doListUserAliases(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	jsonData, err := json.MarshalIndent(aliases, "", "    ")
+	err = filterList(cmd, aliases, uas.LISTKEY)
 	if err != nil {
-		lg.Error(err)
 		return err
 	}
 
-	fmt.Println(string(jsonData))
+	err = fmtrs.Output(os.Stdout, outputFmt, aliases, uas.LISTKEY, listAttrs)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
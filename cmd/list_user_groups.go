@@ -0,0 +1,125 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	fmtrs "github.com/plusworx/gmin/utils/formatters"
+	grps "github.com/plusworx/gmin/utils/groups"
+	lg "github.com/plusworx/gmin/utils/logging"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var listUserGroupsCmd = &cobra.Command{
+	Use:     "user-groups <user email address, alias or id>",
+	Aliases: []string{"user-group", "ugroups", "ugroup", "ugrps", "ugrp"},
+	Args:    cobra.ExactArgs(1),
+	Example: `gmin list user-groups myuser@mycompany.com
+gmin ls ugrps myuser@mycompany.com -o table`,
+	Short: "Outputs a list of the groups that a user belongs to",
+	Long: `Outputs a list of the groups that a user belongs to, either directly or through membership of
+nested groups. Each group is shown once with the path of groups that leads from the group that
+the user belongs to directly up to that group. Group membership cycles are listed separately.`,
+	RunE: doListUserGroups,
+}
+
+func doListUserGroups(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doListUserGroups()",
+		"args", args)
+	defer lg.Debug("finished doListUserGroups()")
+
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryGroupReadonlyScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	userGroups, err := grps.EffectiveGroups(args[0], lugLister(ds), cmn.LOOKUPWORKERS)
+	if err != nil {
+		return err
+	}
+
+	err = filterList(cmd, userGroups, grps.LISTKEY)
+	if err != nil {
+		return err
+	}
+
+	flgCountVal, err := cmd.Flags().GetBool(flgnm.FLG_COUNT)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgCountVal {
+		fmt.Println(len(userGroups.Groups))
+		return nil
+	}
+
+	err = fmtrs.Output(os.Stdout, outputFmt, userGroups, grps.LISTKEY, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// lugLister returns a function that lists all of the groups that a user or group is a direct member of
+func lugLister(ds *admin.Service) grps.GroupLister {
+	return func(memberKey string) ([]*admin.Group, error) {
+		lg.Debugw("starting lugLister()",
+			"memberKey", memberKey)
+		defer lg.Debug("finished lugLister()")
+
+		var groups []*admin.Group
+
+		glc := grps.AddUserKey(ds.Groups.List(), memberKey)
+		glc = grps.AddFields(glc, "nextPageToken,groups(email,id,name)").(*admin.GroupsListCall)
+		for {
+			resp, err := grps.DoList(glc)
+			if err != nil {
+				return nil, err
+			}
+			groups = append(groups, resp.Groups...)
+			if resp.NextPageToken == "" {
+				break
+			}
+			glc = glc.PageToken(resp.NextPageToken)
+		}
+		return groups, nil
+	}
+}
+
+func init() {
+	listCmd.AddCommand(listUserGroupsCmd)
+
+	listUserGroupsCmd.Flags().BoolVarP(&count, flgnm.FLG_COUNT, "", false, "count number of entities returned")
+}
@@ -23,10 +23,8 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 
 	cmn "github.com/plusworx/gmin/utils/common"
@@ -45,22 +43,23 @@ var listUsersCmd = &cobra.Command{
 	Aliases: []string{"user", "usrs", "usr"},
 	Args:    cobra.NoArgs,
 	Example: `gmin list users -a primaryemail~addresses
-gmin ls user -q name:Fred`,
+gmin ls user -q name:Fred
+gmin ls users -p all -a primaryemail --output jsonl --parallel 4`,
 	Short: "Outputs a list of users",
-	Long:  `Outputs a list of users.`,
-	RunE:  doListUsers,
+	Long: `Outputs a list of users.
+
+JSONL, CSV and TSV output and counts are written as each page of results arrives. CSV and TSV columns
+come from --attributes and the first page of results and listing stops with an error if a later page
+has attributes that are not in the columns. If listing stops with an error, --page-token resumes it
+from the page given in the error message. --parallel lists all pages of every domain with up to the
+given number of domains at a time.`,
+	RunE: doListUsers,
 }
 
 func doListUsers(cmd *cobra.Command, args []string) error {
 	lg.Debugw("starting doListUsers()",
 		"args", args)
 
-	var (
-		jsonData     []byte
-		users        *admin.Users
-		validOrderBy string
-	)
-
 	flgCustFldMaskVal, err := cmd.Flags().GetString(flgnm.FLG_CUSTFLDMASK)
 	if err != nil {
 		lg.Error(err)
@@ -101,26 +100,125 @@ func doListUsers(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
 	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryUserReadonlyScope)
 	if err != nil {
 		return err
 	}
 	ds := srv.(*admin.Service)
 
+	ulc, listAttrs, err := lusListCall(cmd, ds, "")
+	if err != nil {
+		return err
+	}
+
+	shards, err := parallelDomains(cmd)
+	if err != nil {
+		return err
+	}
+
+	users := new(admin.Users)
+	pl := pagedList{
+		fields:  listAttrs,
+		listKey: usrs.LISTKEY,
+		merge: func(page interface{}) {
+			pgUsers := page.(*admin.Users)
+			users.Users = append(users.Users, pgUsers.Users...)
+			users.Etag = pgUsers.Etag
+			users.Kind = pgUsers.Kind
+			users.NextPageToken = pgUsers.NextPageToken
+		},
+		result: users,
+		size: func(page interface{}) int {
+			return len(page.(*admin.Users).Users)
+		},
+	}
+
+	err = listPages(cmd, outputFmt, pl, lusFetch(ulc), shards, func(shard string) cmn.PageFunc {
+		shardULC, _, err := lusListCall(cmd, ds, shard)
+		if err != nil {
+			return func(pageToken string) (interface{}, string, error) {
+				return nil, "", err
+			}
+		}
+		return lusFetch(shardULC)
+	})
+	if err != nil {
+		return listDomainError(cmd, err)
+	}
+
+	lg.Debug("finished doListUsers()")
+	return nil
+}
+
+// lusFetch returns a function that gets a page of users
+func lusFetch(ulc *admin.UsersListCall) cmn.PageFunc {
+	return func(pageToken string) (interface{}, string, error) {
+		if pageToken != "" {
+			ulc = usrs.AddPageToken(ulc, pageToken)
+		}
+		users, err := usrs.DoList(ulc)
+		if err != nil {
+			return nil, "", err
+		}
+		return users, users.NextPageToken, nil
+	}
+}
+
+// lusListCall sets up a users list call from command flags. Users are listed from shardDomain when it
+// is given and otherwise from the domain flag, the domain of the active profile or the whole customer.
+func lusListCall(cmd *cobra.Command, ds *admin.Service, shardDomain string) (*admin.UsersListCall, string, error) {
+	lg.Debugw("starting lusListCall()",
+		"shardDomain", shardDomain)
+	defer lg.Debug("finished lusListCall()")
+
+	var (
+		listAttrs    string
+		validOrderBy string
+	)
+
+	flgCustFldMaskVal, err := cmd.Flags().GetString(flgnm.FLG_CUSTFLDMASK)
+	if err != nil {
+		lg.Error(err)
+		return nil, "", err
+	}
+
+	flgProjectionVal, err := cmd.Flags().GetString(flgnm.FLG_PROJECTION)
+	if err != nil {
+		lg.Error(err)
+		return nil, "", err
+	}
+
+	flgDeletedVal, err := cmd.Flags().GetBool(flgnm.FLG_DELETED)
+	if err != nil {
+		lg.Error(err)
+		return nil, "", err
+	}
+
+	flgQueryVal, err := cmd.Flags().GetString(flgnm.FLG_QUERY)
+	if err != nil {
+		lg.Error(err)
+		return nil, "", err
+	}
+
 	ulc := ds.Users.List()
 
 	flgAttrsVal, err := cmd.Flags().GetString(flgnm.FLG_ATTRIBUTES)
 	if err != nil {
 		lg.Error(err)
-		return err
+		return nil, "", err
 	}
 	if flgAttrsVal != "" {
-		listAttrs, err := gpars.ParseOutputAttrs(flgAttrsVal, usrs.UserAttrMap)
+		listAttrs, err = gpars.ParseOutputAttrs(flgAttrsVal, usrs.UserAttrMap)
 		if err != nil {
 			lg.Error(err)
-			return err
+			return nil, "", err
 		}
-		formattedAttrs := usrs.STARTUSERSFIELD + listAttrs + usrs.ENDFIELD
+		formattedAttrs := "nextPageToken," + usrs.STARTUSERSFIELD + listAttrs + usrs.ENDFIELD
 
 		listCall := usrs.AddFields(ulc, formattedAttrs)
 		ulc = listCall.(*admin.UsersListCall)
@@ -130,18 +228,19 @@ func doListUsers(cmd *cobra.Command, args []string) error {
 		ulc = usrs.AddShowDeleted(ulc)
 	}
 
-	flgDomainVal, err := cmd.Flags().GetString(flgnm.FLG_DOMAIN)
+	flgDomainVal, err := listDomain(cmd)
 	if err != nil {
-		lg.Error(err)
-		return err
+		return nil, "", err
 	}
-	if flgDomainVal != "" {
+	if shardDomain != "" {
+		ulc = usrs.AddDomain(ulc, shardDomain)
+	} else if flgDomainVal != "" {
 		ulc = usrs.AddDomain(ulc, flgDomainVal)
 	} else {
 		customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
 		if err != nil {
 			lg.Error(err)
-			return err
+			return nil, "", err
 		}
 		ulc = usrs.AddCustomer(ulc, customerID)
 	}
@@ -152,7 +251,7 @@ func doListUsers(cmd *cobra.Command, args []string) error {
 		if !ok {
 			err = fmt.Errorf(gmess.ERR_INVALIDPROJECTIONTYPE, flgProjectionVal)
 			lg.Error(err)
-			return err
+			return nil, "", err
 		}
 
 		listCall := usrs.AddProjection(ulc, proj)
@@ -167,7 +266,7 @@ func doListUsers(cmd *cobra.Command, args []string) error {
 			} else {
 				err = errors.New(gmess.ERR_NOCUSTOMFIELDMASK)
 				lg.Error(err)
-				return err
+				return nil, "", err
 			}
 		}
 	}
@@ -176,7 +275,7 @@ func doListUsers(cmd *cobra.Command, args []string) error {
 		formattedQuery, err := gpars.ParseQuery(flgQueryVal, usrs.QueryAttrMap)
 		if err != nil {
 			lg.Error(err)
-			return err
+			return nil, "", err
 		}
 
 		ulc = usrs.AddQuery(ulc, formattedQuery)
@@ -185,7 +284,7 @@ func doListUsers(cmd *cobra.Command, args []string) error {
 	flgOrderByVal, err := cmd.Flags().GetString(flgnm.FLG_ORDERBY)
 	if err != nil {
 		lg.Error(err)
-		return err
+		return nil, "", err
 	}
 	if flgOrderByVal != "" {
 		ob := strings.ToLower(flgOrderByVal)
@@ -193,7 +292,7 @@ func doListUsers(cmd *cobra.Command, args []string) error {
 		if !ok {
 			err = fmt.Errorf(gmess.ERR_INVALIDORDERBY, flgOrderByVal)
 			lg.Error(err)
-			return err
+			return nil, "", err
 		}
 
 		validOrderBy = ob
@@ -202,7 +301,7 @@ func doListUsers(cmd *cobra.Command, args []string) error {
 			validOrderBy, err = cmn.IsValidAttr(ob, usrs.UserAttrMap)
 			if err != nil {
 				lg.Error(err)
-				return err
+				return nil, "", err
 			}
 		}
 
@@ -211,14 +310,14 @@ func doListUsers(cmd *cobra.Command, args []string) error {
 		flgSrtOrdByVal, err := cmd.Flags().GetString(flgnm.FLG_SORTORDER)
 		if err != nil {
 			lg.Error(err)
-			return err
+			return nil, "", err
 		}
 		if flgSrtOrdByVal != "" {
 			so := strings.ToLower(flgSrtOrdByVal)
 			validSortOrder, err := cmn.IsValidAttr(so, cmn.ValidSortOrders)
 			if err != nil {
 				lg.Error(err)
-				return err
+				return nil, "", err
 			}
 
 			ulc = usrs.AddSortOrder(ulc, validSortOrder)
@@ -228,7 +327,7 @@ func doListUsers(cmd *cobra.Command, args []string) error {
 	flgViewTypeVal, err := cmd.Flags().GetString(flgnm.FLG_VIEWTYPE)
 	if err != nil {
 		lg.Error(err)
-		return err
+		return nil, "", err
 	}
 	if flgViewTypeVal != "" {
 		vt := strings.ToLower(flgViewTypeVal)
@@ -236,7 +335,7 @@ func doListUsers(cmd *cobra.Command, args []string) error {
 		if !ok {
 			err = fmt.Errorf(gmess.ERR_INVALIDVIEWTYPE, flgViewTypeVal)
 			lg.Error(err)
-			return err
+			return nil, "", err
 		}
 
 		listCall := usrs.AddViewType(ulc, vt)
@@ -246,126 +345,11 @@ func doListUsers(cmd *cobra.Command, args []string) error {
 	flgMaxResultsVal, err := cmd.Flags().GetInt64(flgnm.FLG_MAXRESULTS)
 	if err != nil {
 		lg.Error(err)
-		return err
+		return nil, "", err
 	}
 	ulc = usrs.AddMaxResults(ulc, flgMaxResultsVal)
 
-	users, err = usrs.DoList(ulc)
-	if err != nil {
-		lg.Error(err)
-		return err
-	}
-
-	flgPagesVal, err := cmd.Flags().GetString(flgnm.FLG_PAGES)
-	if err != nil {
-		lg.Error(err)
-		return err
-	}
-	if flgPagesVal != "" {
-		err = doUserPages(ulc, users, flgPagesVal)
-		if err != nil {
-			lg.Error(err)
-			return err
-		}
-	}
-
-	jsonData, err = json.MarshalIndent(users, "", "    ")
-	if err != nil {
-		lg.Error(err)
-		return err
-	}
-
-	flgCountVal, err := cmd.Flags().GetBool(flgnm.FLG_COUNT)
-	if err != nil {
-		lg.Error(err)
-		return err
-	}
-	if flgCountVal {
-		fmt.Println(len(users.Users))
-	} else {
-		fmt.Println(string(jsonData))
-	}
-
-	lg.Debug("finished doListUsers()")
-	return nil
-}
-
-func doUserAllPages(ulc *admin.UsersListCall, users *admin.Users) error {
-	lg.Debug("starting doUserAllPages()")
-
-	if users.NextPageToken != "" {
-		ulc = usrs.AddPageToken(ulc, users.NextPageToken)
-		nxtUsers, err := usrs.DoList(ulc)
-		if err != nil {
-			lg.Error(err)
-			return err
-		}
-		users.Users = append(users.Users, nxtUsers.Users...)
-		users.Etag = nxtUsers.Etag
-		users.NextPageToken = nxtUsers.NextPageToken
-
-		if nxtUsers.NextPageToken != "" {
-			doUserAllPages(ulc, users)
-		}
-	}
-
-	lg.Debug("finished doUserAllPages()")
-	return nil
-}
-
-func doUserNumPages(ulc *admin.UsersListCall, users *admin.Users, numPages int) error {
-	lg.Debugw("starting doUserNumPages()",
-		"numPages", numPages)
-
-	if users.NextPageToken != "" && numPages > 0 {
-		ulc = usrs.AddPageToken(ulc, users.NextPageToken)
-		nxtUsers, err := usrs.DoList(ulc)
-		if err != nil {
-			lg.Error(err)
-			return err
-		}
-		users.Users = append(users.Users, nxtUsers.Users...)
-		users.Etag = nxtUsers.Etag
-		users.NextPageToken = nxtUsers.NextPageToken
-
-		if nxtUsers.NextPageToken != "" {
-			doUserNumPages(ulc, users, numPages-1)
-		}
-	}
-
-	lg.Debug("finished doUserNumPages()")
-	return nil
-}
-
-func doUserPages(ulc *admin.UsersListCall, users *admin.Users, pages string) error {
-	lg.Debugw("starting doUserPages()",
-		"pages", pages)
-
-	if pages == "all" {
-		err := doUserAllPages(ulc, users)
-		if err != nil {
-			lg.Error(err)
-			return err
-		}
-	} else {
-		numPages, err := strconv.Atoi(pages)
-		if err != nil {
-			err = errors.New(gmess.ERR_INVALIDPAGESARGUMENT)
-			lg.Error(err)
-			return err
-		}
-
-		if numPages > 1 {
-			err = doUserNumPages(ulc, users, numPages-1)
-			if err != nil {
-				lg.Error(err)
-				return err
-			}
-		}
-	}
-
-	lg.Debug("finished doUserPages()")
-	return nil
+	return ulc, listAttrs, nil
 }
 
 func init() {
@@ -374,10 +358,12 @@ func init() {
 	listUsersCmd.Flags().StringVarP(&attrs, flgnm.FLG_ATTRIBUTES, "a", "", "required user attributes (separated by ~)")
 	listUsersCmd.Flags().BoolVarP(&count, flgnm.FLG_COUNT, "", false, "count number of entities returned")
 	listUsersCmd.Flags().StringVarP(&customField, flgnm.FLG_CUSTFLDMASK, "c", "", "custom field mask schemas (separated by ~)")
-	listUsersCmd.Flags().StringVarP(&domain, flgnm.FLG_DOMAIN, "d", "", "domain from which to get users")
+	listUsersCmd.Flags().StringVarP(&domain, flgnm.FLG_DOMAIN, "d", "", "domain from which to get users (default is the domain of the active profile)")
 	listUsersCmd.Flags().Int64VarP(&maxResults, flgnm.FLG_MAXRESULTS, "m", 500, "maximum number of results to return per page")
 	listUsersCmd.Flags().StringVarP(&orderBy, flgnm.FLG_ORDERBY, "o", "", "field by which results will be ordered")
+	listUsersCmd.Flags().StringVar(&pageToken, flgnm.FLG_PAGETOKEN, "", "page token to resume listing from")
 	listUsersCmd.Flags().StringVarP(&pages, flgnm.FLG_PAGES, "p", "", "number of pages of results to be returned ('all' or a number)")
+	listUsersCmd.Flags().IntVar(&parallel, flgnm.FLG_PARALLEL, 0, "number of domains to list in parallel")
 	listUsersCmd.Flags().StringVarP(&projection, flgnm.FLG_PROJECTION, "j", "", "type of projection")
 	listUsersCmd.Flags().StringVarP(&query, flgnm.FLG_QUERY, "q", "", "selection criteria to get users (separated by ~)")
 	listUsersCmd.Flags().StringVarP(&sortOrder, flgnm.FLG_SORTORDER, "s", "", "sort order of returned results")
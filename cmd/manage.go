@@ -31,8 +31,8 @@ var manageCmd = &cobra.Command{
 	Use:     "manage",
 	Aliases: []string{"mng"},
 	Args:    cobra.NoArgs,
-	Short:   "Manages Google Workspace devices",
-	Long:    "Manages Google Workspace devices.",
+	Short:   "Manages Google Workspace objects",
+	Long:    "Manages Google Workspace objects.",
 	Run:     doManage,
 }
 
@@ -0,0 +1,111 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	usec "github.com/plusworx/gmin/utils/usersecurity"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var manageBackupCodesCmd = &cobra.Command{
+	Use:     "backup-codes <user email address, alias or id> <action>",
+	Aliases: []string{"backup-code", "bcodes", "bcode"},
+	Args:    cobra.ExactArgs(2),
+	Example: `gmin manage backup-codes mickey.mouse@disney.com generate
+gmin mng bcodes mickey.mouse@disney.com invalidate`,
+	Short: "Generates or invalidates user 2-step verification backup codes",
+	Long: `Generates or invalidates user 2-step verification backup codes.
+
+Valid actions are:
+generate - replaces any existing backup codes with a new set of backup codes
+invalidate - invalidates all of the user's backup codes
+
+New backup codes can be shown with the list backup-codes command.`,
+	RunE: doManageBackupCodes,
+}
+
+func doManageBackupCodes(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doManageBackupCodes()",
+		"args", args)
+	defer lg.Debug("finished doManageBackupCodes()")
+
+	action := strings.ToLower(args[1])
+	ok := cmn.SliceContainsStr(usec.ValidBackupCodeActions, action)
+	if !ok {
+		err := fmt.Errorf(gmess.ERR_INVALIDACTIONTYPE, args[1])
+		lg.Error(err)
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryUserSecurityScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	err = mbcPerformAction(ds, args[0], action)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// mbcPerformAction performs a backup code action and outputs the result
+func mbcPerformAction(ds *admin.Service, userKey string, action string) error {
+	lg.Debugw("starting mbcPerformAction()",
+		"userKey", userKey,
+		"action", action)
+	defer lg.Debug("finished mbcPerformAction()")
+
+	switch action {
+	case "generate":
+		err := ds.VerificationCodes.Generate(userKey).Do()
+		if err != nil {
+			return err
+		}
+		fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_BCODESGENERATED, userKey)))
+		lg.Infof(gmess.INFO_BCODESGENERATED, userKey)
+	case "invalidate":
+		err := ds.VerificationCodes.Invalidate(userKey).Do()
+		if err != nil {
+			return err
+		}
+		fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_BCODESINVALIDATED, userKey)))
+		lg.Infof(gmess.INFO_BCODESINVALIDATED, userKey)
+	}
+
+	return nil
+}
+
+func init() {
+	manageCmd.AddCommand(manageBackupCodesCmd)
+}
@@ -55,9 +55,11 @@ func doManageGroupSettings(cmd *cobra.Command, args []string) error {
 
 	grpSettings := new(gset.Groups)
 
-	// Collect names of command flags passed in
+	// Collect names of command flags passed in, ignoring inherited flags such as dry-run
 	cmd.Flags().Visit(func(f *pflag.Flag) {
-		flagsPassed = append(flagsPassed, f.Name)
+		if cmd.InheritedFlags().Lookup(f.Name) == nil {
+			flagsPassed = append(flagsPassed, f.Name)
+		}
 	})
 
 	// Process command flags
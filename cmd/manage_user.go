@@ -0,0 +1,124 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	usrs "github.com/plusworx/gmin/utils/users"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var manageUserCmd = &cobra.Command{
+	Use:     "user <user email address, alias or id> <action>",
+	Aliases: []string{"usr"},
+	Args:    cobra.ExactArgs(2),
+	Example: `gmin manage user mickey.mouse@disney.com signout
+gmin mng usr 108777422227428736547 signout
+gmin mng usr donald.duck@disney.com make-admin`,
+	Short: "Performs an action on a user",
+	Long: `Performs an action on a user.
+
+Valid actions are:
+make-admin - makes the user a super administrator
+revoke-admin - revokes the user's super administrator status
+signout - signs the user out of all web and device sessions and resets their sign-in cookies`,
+	RunE: doManageUser,
+}
+
+func doManageUser(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doManageUser()",
+		"args", args)
+	defer lg.Debug("finished doManageUser()")
+
+	action := strings.ToLower(args[1])
+	ok := cmn.SliceContainsStr(usrs.ValidActions, action)
+	if !ok {
+		err := fmt.Errorf(gmess.ERR_INVALIDACTIONTYPE, args[1])
+		lg.Error(err)
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, muActionScope(action))
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	err = muPerformAction(ds, args[0], action)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// muActionScope returns the scope needed to perform a user action
+func muActionScope(action string) string {
+	if action == "signout" {
+		return admin.AdminDirectoryUserSecurityScope
+	}
+	return admin.AdminDirectoryUserScope
+}
+
+// muPerformAction performs a user action and outputs the result
+func muPerformAction(ds *admin.Service, userKey string, action string) error {
+	lg.Debugw("starting muPerformAction()",
+		"userKey", userKey,
+		"action", action)
+	defer lg.Debug("finished muPerformAction()")
+
+	switch action {
+	case "make-admin", "revoke-admin":
+		makeAdmin := &admin.UserMakeAdmin{Status: action == "make-admin", ForceSendFields: []string{"Status"}}
+		err := ds.Users.MakeAdmin(userKey, makeAdmin).Do()
+		if err != nil {
+			return err
+		}
+		msg := gmess.INFO_USERMADEADMIN
+		if !makeAdmin.Status {
+			msg = gmess.INFO_ADMINREVOKED
+		}
+		fmt.Println(cmn.GminMessage(fmt.Sprintf(msg, userKey)))
+		lg.Infof(msg, userKey)
+	case "signout":
+		err := ds.Users.SignOut(userKey).Do()
+		if err != nil {
+			return err
+		}
+		fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_USERSIGNEDOUT, userKey)))
+		lg.Infof(gmess.INFO_USERSIGNEDOUT, userKey)
+	}
+
+	return nil
+}
+
+func init() {
+	manageCmd.AddCommand(manageUserCmd)
+}
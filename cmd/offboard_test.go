@@ -0,0 +1,170 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	cfg "github.com/plusworx/gmin/utils/config"
+	"github.com/spf13/viper"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+func TestOffboardUserFakeServer(t *testing.T) {
+	fs := newFakeServer(t)
+	fs.AddUser(&admin.User{PrimaryEmail: "frank.castle@mycompany.com", Aliases: []string{"punisher@mycompany.com"}, Name: &admin.UserName{GivenName: "Frank", FamilyName: "Castle"}})
+	fs.AddUser(&admin.User{PrimaryEmail: "bruce.wayne@mycompany.com", Name: &admin.UserName{GivenName: "Bruce", FamilyName: "Wayne"}})
+	fs.AddUser(&admin.User{PrimaryEmail: "matt.murdock@mycompany.com", Aliases: []string{"daredevil@mycompany.com"}, Name: &admin.UserName{GivenName: "Matt", FamilyName: "Murdock"}})
+	fs.AddGroup(&admin.Group{Email: "sales@mycompany.com", Name: "Sales"})
+	fs.AddGroup(&admin.Group{Email: "finance@mycompany.com", Name: "Finance"})
+	fs.AddMember("sales@mycompany.com", &admin.Member{Email: "frank.castle@mycompany.com", Role: "MEMBER"})
+	fs.AddMember("finance@mycompany.com", &admin.Member{Email: "frank.castle@mycompany.com", Role: "MANAGER"})
+	fs.AddMember("finance@mycompany.com", &admin.Member{Email: "bruce.wayne@mycompany.com", Role: "MEMBER"})
+	fs.AddToken("frank.castle@mycompany.com", &admin.Token{ClientId: "111.apps.googleusercontent.com"})
+	fs.AddMobDevice(&admin.MobileDevice{ResourceId: "mdev1", Email: []string{"frank.castle@mycompany.com"}})
+	fs.AddMobDevice(&admin.MobileDevice{ResourceId: "mdev2", Email: []string{"bruce.wayne@mycompany.com"}})
+
+	dir := t.TempDir()
+	workflow := filepath.Join(dir, "leavers.yaml")
+	ioutil.WriteFile(workflow, []byte(`steps:
+  - action: suspend
+  - action: remove-groups
+  - action: move-orgunit
+    orgunit: /Leavers
+  - action: remove-aliases
+  - action: revoke-tokens
+  - action: delete-mobile-devices
+`), 0644)
+	badWorkflow := filepath.Join(dir, "bad.yaml")
+	ioutil.WriteFile(badWorkflow, []byte("steps:\n  - action: archive\n"), 0644)
+
+	_, err := runGmin(t, "offboard", "user", "frank.castle@mycompany.com", "-w", badWorkflow)
+	if err == nil || err.Error() != "invalid action type: archive" {
+		t.Errorf("Got error: %v - expected error: invalid action type: archive", err)
+	}
+
+	// Leavers orgunit doesn't exist so the run stops at the move-orgunit step
+	_, err = runGmin(t, "offboard", "user", "frank.castle@mycompany.com", "-w", workflow)
+	if err == nil || !strings.HasPrefix(err.Error(), "offboarding stopped at step 3 (move-orgunit)") {
+		t.Fatalf("Got error: %v - expected error: offboarding stopped at step 3 (move-orgunit)", err)
+	}
+
+	frank := fs.User("frank.castle@mycompany.com")
+	if !frank.Suspended {
+		t.Error("Got suspended: false - expected suspended: true")
+	}
+	if fs.Member("sales@mycompany.com", "frank.castle@mycompany.com") != nil || fs.Member("finance@mycompany.com", "frank.castle@mycompany.com") != nil {
+		t.Error("Got group memberships still present - expected frank to be removed from all groups")
+	}
+	if fs.Member("finance@mycompany.com", "bruce.wayne@mycompany.com") == nil {
+		t.Error("Got bruce membership removed - expected bruce membership to be kept")
+	}
+	if len(frank.Aliases) != 1 {
+		t.Errorf("Got aliases: %v - expected aliases: 1", len(frank.Aliases))
+	}
+
+	progress, err := ioutil.ReadFile(filepath.Join(viper.GetString(cfg.CONFIGLOGPATH), "offboard", "frank.castle@mycompany.com.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(progress), `"status": "failed"`) {
+		t.Errorf("Got progress: %v - expected failed step", string(progress))
+	}
+
+	fs.AddOrgUnit(&admin.OrgUnit{Name: "Leavers", OrgUnitPath: "/Leavers", ParentOrgUnitPath: "/"})
+	fs.AddMember("sales@mycompany.com", &admin.Member{Email: "frank.castle@mycompany.com", Role: "MEMBER"})
+
+	out, err := runGmin(t, "offboard", "user", "frank.castle@mycompany.com", "-w", workflow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "step 2 of 6: remove-groups - already done") || !strings.Contains(out, "user offboarded: frank.castle@mycompany.com") {
+		t.Errorf("Got output: %v - expected steps 1 and 2 to be skipped", out)
+	}
+	// Completed steps are skipped so the re-added membership is left alone
+	if fs.Member("sales@mycompany.com", "frank.castle@mycompany.com") == nil {
+		t.Error("Got sales membership removed - expected remove-groups step to be skipped")
+	}
+	if frank.OrgUnitPath != "/Leavers" {
+		t.Errorf("Got orgunit: %v - expected orgunit: /Leavers", frank.OrgUnitPath)
+	}
+	if len(frank.Aliases) != 0 || len(fs.Tokens["frank.castle@mycompany.com"]) != 0 {
+		t.Errorf("Got aliases and tokens: %v %v - expected aliases and tokens: 0 0", len(frank.Aliases), len(fs.Tokens["frank.castle@mycompany.com"]))
+	}
+	if _, ok := fs.MobDevices["mdev1"]; ok {
+		t.Error("Got mdev1 present - expected mdev1 to be deleted")
+	}
+	if _, ok := fs.MobDevices["mdev2"]; !ok {
+		t.Error("Got mdev2 deleted - expected mdev2 to be kept")
+	}
+
+	_, err = runGmin(t, "offboard", "user", "frank.castle@mycompany.com", "-w", workflow, "--restart")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fs.Member("sales@mycompany.com", "frank.castle@mycompany.com") != nil {
+		t.Error("Got sales membership present - expected --restart to run remove-groups again")
+	}
+
+	out, err = runGmin(t, "offboard", "user", "bruce.wayne@mycompany.com", "--dry-run")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "[dry run]") {
+		t.Errorf("Got output: %v - expected dry run output", out)
+	}
+	if fs.User("bruce.wayne@mycompany.com").Suspended || fs.Member("finance@mycompany.com", "bruce.wayne@mycompany.com") == nil {
+		t.Error("Got bruce changed - expected dry run to make no changes")
+	}
+	_, err = os.Stat(filepath.Join(viper.GetString(cfg.CONFIGLOGPATH), "offboard", "bruce.wayne@mycompany.com.json"))
+	if !os.IsNotExist(err) {
+		t.Errorf("Got progress file stat error: %v - expected dry run not to save progress", err)
+	}
+
+	// A dry run goes past the remove-aliases step to show all of the planned changes
+	out, err = runGmin(t, "offboard", "user", "matt.murdock@mycompany.com", "-w", workflow, "--dry-run")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedOut := []string{
+		"/aliases/daredevil@mycompany.com\n    ? current state unknown",
+		"step 4 of 6: remove-aliases - done",
+		"step 6 of 6: delete-mobile-devices - done",
+		"user offboarded: matt.murdock@mycompany.com",
+	}
+	for _, expected := range expectedOut {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Got output: %v - expected output to contain: %v", out, expected)
+		}
+	}
+	if strings.Contains(out, "run the command again to resume") {
+		t.Errorf("Got output: %v - expected no resume hint in dry run", out)
+	}
+	if len(fs.User("matt.murdock@mycompany.com").Aliases) != 1 {
+		t.Error("Got matt alias removed - expected dry run to make no changes")
+	}
+}
@@ -0,0 +1,320 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	cfg "github.com/plusworx/gmin/utils/config"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	grps "github.com/plusworx/gmin/utils/groups"
+	lg "github.com/plusworx/gmin/utils/logging"
+	mdevs "github.com/plusworx/gmin/utils/mobiledevices"
+	offb "github.com/plusworx/gmin/utils/offboard"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var offboardUserCmd = &cobra.Command{
+	Use:     "user <user email address, alias or id>",
+	Aliases: []string{"usr"},
+	Args:    cobra.ExactArgs(1),
+	Example: `gmin offboard user frank.castle@mycompany.com
+gmin offboard user frank.castle@mycompany.com -w leavers.yaml
+gmin offb usr frank.castle@mycompany.com -w leavers.yaml --dry-run`,
+	Short: "Offboards a user by running an ordered workflow of steps",
+	Long: `Offboards a user by running an ordered workflow of steps defined in a YAML workflow file like this:
+
+steps:
+  - action: suspend
+  - action: signout
+  - action: revoke-tokens
+  - action: remove-groups
+  - action: remove-aliases
+  - action: move-orgunit
+    orgunit: /Leavers
+  - action: wipe-mobile-devices
+
+Valid actions are:
+delete-mobile-devices - deletes the user's mobile devices
+move-orgunit - moves the user to the orgunit given by orgunit
+remove-aliases - deletes the user's aliases
+remove-groups - removes the user from all of their groups
+revoke-tokens - deletes all OAuth tokens issued to third-party applications by the user
+signout - signs the user out of all web and device sessions
+suspend - suspends the user
+wipe-mobile-devices - wipes the user's account from their mobile devices
+
+If no workflow file is given then every action apart from delete-mobile-devices and move-orgunit is
+carried out in the order above.
+
+The status of each step is saved in the offboard directory of the log path. If a step fails then
+running the command again skips the steps that have already been done. Use --restart to run every
+step again. Progress is not saved by a dry run.`,
+	RunE: doOffboardUser,
+}
+
+func doOffboardUser(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doOffboardUser()",
+		"args", args)
+	defer lg.Debug("finished doOffboardUser()")
+
+	wf := offb.DefaultWorkflow()
+
+	flgWorkflowVal, err := cmd.Flags().GetString(flgnm.FLG_WORKFLOW)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgWorkflowVal != "" {
+		wf, err = offb.Load(flgWorkflowVal)
+		if err != nil {
+			return err
+		}
+	}
+
+	flgRestartVal, err := cmd.Flags().GetBool(flgnm.FLG_RESTART)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, wf.Scopes()...)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
+	if err != nil {
+		return err
+	}
+
+	user, err := ds.Users.Get(args[0]).Fields("aliases,id,primaryEmail").Do()
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	progressPath, err := offb.ProgressPath(user.PrimaryEmail)
+	if err != nil {
+		return err
+	}
+
+	progress := &offb.Progress{User: user.PrimaryEmail}
+	if !flgRestartVal {
+		progress, err = offb.LoadProgress(progressPath, user.PrimaryEmail)
+		if err != nil {
+			return err
+		}
+	}
+
+	// A dry run goes through every step so that all of the planned changes are shown
+	var failedSteps int
+	numSteps := len(wf.Steps)
+	for idx, step := range wf.Steps {
+		if progress.Done(idx, step) {
+			fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_STEPSKIPPED, idx+1, numSteps, step.Action)))
+			lg.Infof(gmess.INFO_STEPSKIPPED, idx+1, numSteps, step.Action)
+			continue
+		}
+
+		stepErr := obuRunStep(ds, customerID, user, step)
+		progress.Record(idx, step, stepErr)
+
+		if !cmn.DryRun {
+			err = progress.Save(progressPath)
+			if err != nil {
+				return err
+			}
+		}
+
+		if stepErr != nil && cmn.DryRun {
+			failedSteps++
+			err = fmt.Errorf(gmess.ERR_STEPFAILED, idx+1, numSteps, step.Action, stepErr)
+			fmt.Println(cmn.GminMessage(err.Error()))
+			lg.Error(err)
+			continue
+		}
+
+		if stepErr != nil {
+			err = fmt.Errorf(gmess.ERR_OFFBOARDSTOPPED, idx+1, step.Action, stepErr)
+			lg.Error(err)
+			return err
+		}
+
+		fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_STEPDONE, idx+1, numSteps, step.Action)))
+		lg.Infof(gmess.INFO_STEPDONE, idx+1, numSteps, step.Action)
+	}
+
+	if failedSteps > 0 {
+		err = fmt.Errorf(gmess.ERR_OFFBOARDDRYRUNFAILED, failedSteps)
+		lg.Error(err)
+		return err
+	}
+
+	fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_OFFBOARDED, user.PrimaryEmail)))
+	lg.Infof(gmess.INFO_OFFBOARDED, user.PrimaryEmail)
+
+	return nil
+}
+
+// obuMobDevs performs an action on, or deletes if action is empty, all of a user's mobile devices
+func obuMobDevs(ds *admin.Service, customerID string, email string, action string) error {
+	lg.Debugw("starting obuMobDevs()",
+		"email", email,
+		"action", action)
+	defer lg.Debug("finished obuMobDevs()")
+
+	var resourceIDs []string
+
+	mdlc := mdevs.AddQuery(ds.Mobiledevices.List(customerID), "email:"+email)
+	mdlc = mdevs.AddFields(mdlc, "nextPageToken,mobiledevices(resourceId)").(*admin.MobiledevicesListCall)
+	for {
+		mobdevs, err := mdevs.DoList(mdlc)
+		if err != nil {
+			return err
+		}
+		for _, mobdev := range mobdevs.Mobiledevices {
+			resourceIDs = append(resourceIDs, mobdev.ResourceId)
+		}
+		if mobdevs.NextPageToken == "" {
+			break
+		}
+		mdlc = mdevs.AddPageToken(mdlc, mobdevs.NextPageToken)
+	}
+
+	for _, resID := range resourceIDs {
+		var err error
+
+		if action == "" {
+			err = ds.Mobiledevices.Delete(customerID, resID).Do()
+			if err != nil {
+				return err
+			}
+			fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_MDEVDELETED, resID)))
+			lg.Infof(gmess.INFO_MDEVDELETED, resID)
+			continue
+		}
+
+		err = ds.Mobiledevices.Action(customerID, resID, &admin.MobileDeviceAction{Action: action}).Do()
+		if err != nil {
+			return err
+		}
+		fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_MDEVACTIONPERFORMED, action, resID)))
+		lg.Infof(gmess.INFO_MDEVACTIONPERFORMED, action, resID)
+	}
+
+	return nil
+}
+
+// obuRemoveGroups removes a user from all of their groups
+func obuRemoveGroups(ds *admin.Service, email string) error {
+	lg.Debugw("starting obuRemoveGroups()",
+		"email", email)
+	defer lg.Debug("finished obuRemoveGroups()")
+
+	var groupEmails []string
+
+	glc := grps.AddUserKey(ds.Groups.List(), email)
+	glc = grps.AddFields(glc, "nextPageToken,groups(email)").(*admin.GroupsListCall)
+	for {
+		groups, err := grps.DoList(glc)
+		if err != nil {
+			return err
+		}
+		for _, group := range groups.Groups {
+			groupEmails = append(groupEmails, group.Email)
+		}
+		if groups.NextPageToken == "" {
+			break
+		}
+		glc = grps.AddPageToken(glc, groups.NextPageToken)
+	}
+
+	for _, groupEmail := range groupEmails {
+		err := ds.Members.Delete(groupEmail, email).Do()
+		if err != nil {
+			return err
+		}
+		fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_MEMBERDELETED, email, groupEmail)))
+		lg.Infof(gmess.INFO_MEMBERDELETED, email, groupEmail)
+	}
+
+	return nil
+}
+
+// obuRunStep carries out an offboarding workflow step
+func obuRunStep(ds *admin.Service, customerID string, user *admin.User, step offb.Step) error {
+	lg.Debugw("starting obuRunStep()",
+		"user", user.PrimaryEmail,
+		"action", step.Action)
+	defer lg.Debug("finished obuRunStep()")
+
+	switch step.Action {
+	case offb.ACTDELETEMOBDEVS:
+		return obuMobDevs(ds, customerID, user.PrimaryEmail, "")
+	case offb.ACTMOVEORGUNIT:
+		_, err := ds.Users.Update(user.Id, &admin.User{OrgUnitPath: step.OrgUnit}).Do()
+		if err != nil {
+			return err
+		}
+		fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_USERUPDATED, user.PrimaryEmail)))
+		lg.Infof(gmess.INFO_USERUPDATED, user.PrimaryEmail)
+	case offb.ACTREMOVEALIASES:
+		for _, alias := range user.Aliases {
+			err := ds.Users.Aliases.Delete(user.Id, alias).Do()
+			if err != nil {
+				return err
+			}
+			fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_USERALIASDELETED, alias, user.PrimaryEmail)))
+			lg.Infof(gmess.INFO_USERALIASDELETED, alias, user.PrimaryEmail)
+		}
+	case offb.ACTREMOVEGROUPS:
+		return obuRemoveGroups(ds, user.PrimaryEmail)
+	case offb.ACTREVOKETOKENS:
+		return bdtokDelete(ds, user.PrimaryEmail, "")
+	case offb.ACTSIGNOUT:
+		return muPerformAction(ds, user.PrimaryEmail, "signout")
+	case offb.ACTSUSPEND:
+		_, err := ds.Users.Update(user.Id, &admin.User{Suspended: true}).Do()
+		if err != nil {
+			return err
+		}
+		fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_USERUPDATED, user.PrimaryEmail)))
+		lg.Infof(gmess.INFO_USERUPDATED, user.PrimaryEmail)
+	case offb.ACTWIPEMOBDEVS:
+		return obuMobDevs(ds, customerID, user.PrimaryEmail, "admin_account_wipe")
+	}
+
+	return nil
+}
+
+func init() {
+	offboardCmd.AddCommand(offboardUserCmd)
+
+	offboardUserCmd.Flags().BoolVarP(&restart, flgnm.FLG_RESTART, "", false, "run every step again ignoring saved progress")
+	offboardUserCmd.Flags().StringVarP(&workflowFile, flgnm.FLG_WORKFLOW, "w", "", "workflow file path")
+}
@@ -0,0 +1,168 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+func TestOnboardUserFakeServer(t *testing.T) {
+	fs := newFakeServer(t)
+	fs.AddGroup(&admin.Group{Email: "sales@mycompany.com", Name: "Sales"})
+	fs.AddGroup(&admin.Group{Email: "sales-leads@mycompany.com", Name: "Sales Leads"})
+
+	dir := t.TempDir()
+	templates := filepath.Join(dir, "starters.yaml")
+	ioutil.WriteFile(templates, []byte(`templates:
+  sales:
+    primaryEmail: "{firstname}.{lastname}@mycompany.com"
+    aliases:
+      - "{initial}{lastname}@mycompany.com"
+    orgunit: /
+    department: Sales
+    groups:
+      - email: sales@mycompany.com
+      - email: sales-leads@mycompany.com
+        role: MANAGER
+  marketing:
+    primaryEmail: "{firstname}.{lastname}@mycompany.com"
+    groups:
+      - email: marketing@mycompany.com
+`), 0644)
+	handoff := filepath.Join(dir, "handoff.csv")
+
+	out, err := runGmin(t, "onboard", "user", "-f", "Peter", "-l", "Parker", "-t", "sales", "--template-file", templates, "--handoff-file", handoff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "user onboarded: peter.parker@mycompany.com") {
+		t.Errorf("Got output: %v - expected user onboarded message", out)
+	}
+
+	peter := fs.User("peter.parker@mycompany.com")
+	if peter == nil {
+		t.Fatal("Got user: nil - expected user: peter.parker@mycompany.com")
+	}
+	if !peter.ChangePasswordAtNextLogin || peter.Password == "" {
+		t.Errorf("Got change password and password: %v %v - expected generated password to be changed at next login", peter.ChangePasswordAtNextLogin, peter.Password)
+	}
+	if len(peter.Aliases) != 1 || peter.Aliases[0] != "pparker@mycompany.com" {
+		t.Errorf("Got aliases: %v - expected aliases: [pparker@mycompany.com]", peter.Aliases)
+	}
+	member := fs.Member("sales-leads@mycompany.com", "peter.parker@mycompany.com")
+	if fs.Member("sales@mycompany.com", "peter.parker@mycompany.com") == nil || member == nil || member.Role != "MANAGER" {
+		t.Error("Got group memberships missing - expected peter to be added to sales and sales-leads")
+	}
+
+	content, err := ioutil.ReadFile(handoff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[1], "peter.parker@mycompany.com,") {
+		t.Errorf("Got handoff file: %v - expected credentials for peter.parker@mycompany.com", string(content))
+	}
+
+	// Marketing group doesn't exist so membership fails after the user has been created
+	_, err = runGmin(t, "onboard", "user", "mj@mycompany.com", "-f", "Mary", "-l", "Jane", "-t", "marketing", "--template-file", templates, "--handoff-file", handoff)
+	if err == nil {
+		t.Fatal("Got error: nil - expected marketing group not found error")
+	}
+	if fs.User("mj@mycompany.com") == nil {
+		t.Error("Got user: nil - expected user: mj@mycompany.com")
+	}
+	content, _ = ioutil.ReadFile(handoff)
+	if !strings.Contains(string(content), "mj@mycompany.com,") {
+		t.Errorf("Got handoff file: %v - expected credentials for mj@mycompany.com", string(content))
+	}
+
+	_, err = runGmin(t, "onboard", "user", "-f", "Peter", "-l", "Parker", "-t", "hr", "--template-file", templates, "--handoff-file", handoff)
+	if err == nil || err.Error() != "template not found: hr" {
+		t.Errorf("Got error: %v - expected error: template not found: hr", err)
+	}
+
+	dryHandoff := filepath.Join(dir, "dryrun.csv")
+	out, err = runGmin(t, "onboard", "user", "-f", "Bruce", "-l", "Banner", "-t", "sales", "--template-file", templates, "--handoff-file", dryHandoff, "--dry-run")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "[dry run]") || fs.User("bruce.banner@mycompany.com") != nil {
+		t.Errorf("Got output: %v - expected dry run to make no changes", out)
+	}
+	_, err = os.Stat(dryHandoff)
+	if !os.IsNotExist(err) {
+		t.Errorf("Got handoff file stat error: %v - expected dry run not to write handoff file", err)
+	}
+}
+
+func TestBatchOnboardUsersFakeServer(t *testing.T) {
+	fs := newFakeServer(t)
+	fs.AddGroup(&admin.Group{Email: "sales@mycompany.com", Name: "Sales"})
+
+	dir := t.TempDir()
+	templates := filepath.Join(dir, "starters.yaml")
+	ioutil.WriteFile(templates, []byte(`templates:
+  sales:
+    primaryEmail: "{firstname}.{lastname}@mycompany.com"
+    groups:
+      - email: sales@mycompany.com
+  contractors:
+    changePasswordAtNextLogin: false
+`), 0644)
+	input := filepath.Join(dir, "starters.csv")
+	ioutil.WriteFile(input, []byte(`firstName,lastName,primaryEmail,template
+Peter,Parker,,
+Mary,Jane,mj@mycompany.com,contractors
+Bruce,Banner,,contractors
+`), 0644)
+	handoff := filepath.Join(dir, "handoff.csv")
+
+	_, err := runGmin(t, "batch-onboard", "users", "-i", input, "-f", "csv", "-t", "sales", "--template-file", templates,
+		"--handoff-file", handoff, "--results-dir", dir)
+	if err == nil || !strings.Contains(err.Error(), "1 of 3") {
+		t.Errorf("Got error: %v - expected 1 of 3 batch rows to fail", err)
+	}
+
+	if fs.User("peter.parker@mycompany.com") == nil || fs.Member("sales@mycompany.com", "peter.parker@mycompany.com") == nil {
+		t.Error("Got peter missing - expected peter.parker@mycompany.com to be onboarded")
+	}
+	mj := fs.User("mj@mycompany.com")
+	if mj == nil || mj.ChangePasswordAtNextLogin {
+		t.Error("Got mj missing or changing password - expected mj@mycompany.com to be onboarded by contractors template")
+	}
+
+	content, err := ioutil.ReadFile(handoff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[1], "mj@mycompany.com,") || !strings.HasPrefix(lines[2], "peter.parker@mycompany.com,") {
+		t.Errorf("Got handoff file: %v - expected credentials for mj and peter", string(content))
+	}
+}
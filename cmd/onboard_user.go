@@ -0,0 +1,300 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	btch "github.com/plusworx/gmin/utils/batch"
+	cmn "github.com/plusworx/gmin/utils/common"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	onb "github.com/plusworx/gmin/utils/onboard"
+	pwds "github.com/plusworx/gmin/utils/passwords"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var onboardUserCmd = &cobra.Command{
+	Use:     "user [user email address]",
+	Aliases: []string{"usr"},
+	Args:    cobra.MaximumNArgs(1),
+	Example: `gmin onboard user -f Peter -l Parker -t sales --template-file starters.yaml
+gmin onboard user peter.parker@mycompany.com -f Peter -l Parker -t sales --template-file starters.yaml
+gmin onb usr -f Peter -l Parker -t sales --template-file starters.yaml --handoff-file parker.csv --dry-run`,
+	Short: "Onboards a user using a template",
+	Long: `Onboards a user using a template from a YAML template file like this:
+
+templates:
+  sales:
+    primaryEmail: "{firstname}.{lastname}@mycompany.com"
+    aliases:
+      - "{initial}{lastname}@mycompany.com"
+    orgunit: /Sales
+    department: Sales
+    groups:
+      - email: sales@mycompany.com
+      - email: sales-leads@mycompany.com
+        role: MANAGER
+    customSchemas:
+      EmployeeData:
+        costCentre: S100
+    changePasswordAtNextLogin: true
+    includeInGlobalAddressList: true
+
+The primaryEmail and aliases patterns can contain the {firstname}, {lastname}, {initial} and
+{lastinitial} placeholders which are replaced by the lowercased user names with any characters
+that are not letters or digits removed. A user email address argument overrides the primaryEmail
+pattern.
+
+The user is created with a randomly generated password, added to the template groups and given the
+template aliases. Users must change their password at next login unless the template sets
+changePasswordAtNextLogin to false.
+
+The new user's email address and password are written to a CSV handoff file that only the file
+owner can read. The default handoff file is gmin_credentials_<timestamp>.csv in the current
+directory. No handoff file is written by a dry run.`,
+	RunE: doOnboardUser,
+}
+
+func doOnboardUser(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doOnboardUser()",
+		"args", args)
+	defer lg.Debug("finished doOnboardUser()")
+
+	tmpl, err := onbuTemplate(cmd)
+	if err != nil {
+		return err
+	}
+
+	starter := new(onb.Starter)
+	if len(args) == 1 {
+		starter.PrimaryEmail = args[0]
+	}
+
+	starter.FirstName, err = cmd.Flags().GetString(flgnm.FLG_FIRSTNAME)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	starter.LastName, err = cmd.Flags().GetString(flgnm.FLG_LASTNAME)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	flgPwdLenVal, err := cmd.Flags().GetInt(flgnm.FLG_PWDLENGTH)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	handoffPath, err := onbuHandoffPath(cmd)
+	if err != nil {
+		return err
+	}
+
+	hasher, err := passwordHasher(cmd)
+	if err != nil {
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, onbuScopes...)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	user, err := tmpl.NewUser(starter)
+	if err != nil {
+		return err
+	}
+
+	cred, err := onbuOnboard(ds, tmpl, starter, user, flgPwdLenVal, hasher, func(fn func() error) error {
+		return fn()
+	})
+	// The handoff file is written if the user was created even if a later step failed
+	if cred != nil {
+		hoErr := onbuWriteHandoff(handoffPath, []onb.Credential{*cred})
+		if hoErr != nil {
+			return hoErr
+		}
+	}
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	return nil
+}
+
+// onbuScopes are the scopes needed to onboard users
+var onbuScopes = []string{
+	admin.AdminDirectoryGroupMemberScope,
+	admin.AdminDirectoryUserAliasScope,
+	admin.AdminDirectoryUserScope,
+}
+
+func init() {
+	onboardCmd.AddCommand(onboardUserCmd)
+
+	onboardUserCmd.Flags().StringVarP(&firstName, flgnm.FLG_FIRSTNAME, "f", "", "user's first name")
+	onboardUserCmd.Flags().StringVar(&handoffFile, flgnm.FLG_HANDOFFFILE, "", "filepath to credentials handoff file")
+	onboardUserCmd.Flags().StringVar(&hasher, flgnm.FLG_HASHER, "", "password hasher (crypt, md5 or sha-1)")
+	onboardUserCmd.Flags().StringVarP(&lastName, flgnm.FLG_LASTNAME, "l", "", "user's last name")
+	onboardUserCmd.Flags().IntVar(&pwdLength, flgnm.FLG_PWDLENGTH, onb.DEFAULTPASSWORDLENGTH, "length of generated password")
+	onboardUserCmd.Flags().StringVarP(&templateName, flgnm.FLG_TEMPLATE, "t", "", "name of onboarding template")
+	onboardUserCmd.Flags().StringVar(&templateFile, flgnm.FLG_TEMPLATEFILE, "", "filepath to onboarding template file")
+	onboardUserCmd.MarkFlagRequired(flgnm.FLG_TEMPLATE)
+	onboardUserCmd.MarkFlagRequired(flgnm.FLG_TEMPLATEFILE)
+}
+
+func onbuHandoffPath(cmd *cobra.Command) (string, error) {
+	lg.Debug("starting onbuHandoffPath()")
+	defer lg.Debug("finished onbuHandoffPath()")
+
+	flgHandoffVal, err := cmd.Flags().GetString(flgnm.FLG_HANDOFFFILE)
+	if err != nil {
+		lg.Error(err)
+		return "", err
+	}
+	if flgHandoffVal == "" {
+		flgHandoffVal = "gmin_credentials_" + time.Now().Format(btch.FILETIMEFORMAT) + ".csv"
+	}
+	return flgHandoffVal, nil
+}
+
+// onbuOnboard creates a user made from a template, adds them to the template groups and gives them the
+// template aliases. API calls are made by do so that batch onboarding can retry them. The new user's
+// credential is returned whenever the user has been created so that it can be handed off even if a
+// later step fails.
+func onbuOnboard(ds *admin.Service, tmpl *onb.Template, starter *onb.Starter, user *admin.User, pwdLength int, hasher pwds.Hasher, do func(func() error) error) (*onb.Credential, error) {
+	lg.Debugw("starting onbuOnboard()",
+		"user", user.PrimaryEmail)
+	defer lg.Debug("finished onbuOnboard()")
+
+	pwd, err := onb.GeneratePassword(pwdLength)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Password = pwd
+	err = pwds.Prepare(user, hasher)
+	if err != nil {
+		return nil, err
+	}
+
+	var newUser *admin.User
+	err = do(func() error {
+		var err error
+		newUser, err = ds.Users.Insert(user).Do()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_USERCREATED, newUser.PrimaryEmail)))
+	lg.Infof(gmess.INFO_USERCREATED, newUser.PrimaryEmail)
+
+	cred := &onb.Credential{Password: pwd, PrimaryEmail: newUser.PrimaryEmail}
+
+	for _, grp := range tmpl.Groups {
+		member := &admin.Member{Email: newUser.PrimaryEmail, Role: grp.Role}
+		err = do(func() error {
+			_, err := ds.Members.Insert(grp.Email, member).Do()
+			return err
+		})
+		if err != nil {
+			return cred, err
+		}
+		fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_MEMBERCREATED, newUser.PrimaryEmail, grp.Email)))
+		lg.Infof(gmess.INFO_MEMBERCREATED, newUser.PrimaryEmail, grp.Email)
+	}
+
+	for _, alias := range tmpl.AliasesFor(starter, newUser.PrimaryEmail) {
+		alias := alias
+		err = do(func() error {
+			_, err := ds.Users.Aliases.Insert(newUser.PrimaryEmail, &admin.Alias{Alias: alias}).Do()
+			return err
+		})
+		if err != nil {
+			return cred, err
+		}
+		fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_USERALIASCREATED, alias, newUser.PrimaryEmail)))
+		lg.Infof(gmess.INFO_USERALIASCREATED, alias, newUser.PrimaryEmail)
+	}
+
+	fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_USERONBOARDED, newUser.PrimaryEmail)))
+	lg.Infof(gmess.INFO_USERONBOARDED, newUser.PrimaryEmail)
+
+	return cred, nil
+}
+
+func onbuTemplate(cmd *cobra.Command) (*onb.Template, error) {
+	lg.Debug("starting onbuTemplate()")
+	defer lg.Debug("finished onbuTemplate()")
+
+	tmpls, err := onbuTemplates(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	flgTemplateVal, err := cmd.Flags().GetString(flgnm.FLG_TEMPLATE)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+	return tmpls.Get(flgTemplateVal)
+}
+
+func onbuTemplates(cmd *cobra.Command) (*onb.Templates, error) {
+	lg.Debug("starting onbuTemplates()")
+	defer lg.Debug("finished onbuTemplates()")
+
+	flgTemplateFileVal, err := cmd.Flags().GetString(flgnm.FLG_TEMPLATEFILE)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+	return onb.Load(flgTemplateFileVal)
+}
+
+func onbuWriteHandoff(filePath string, creds []onb.Credential) error {
+	lg.Debugw("starting onbuWriteHandoff()",
+		"filePath", filePath)
+	defer lg.Debug("finished onbuWriteHandoff()")
+
+	if cmn.DryRun || len(creds) == 0 {
+		return nil
+	}
+
+	err := onb.WriteHandoff(filePath, creds)
+	if err != nil {
+		return err
+	}
+	fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_HANDOFFWRITTEN, filePath)))
+	lg.Infof(gmess.INFO_HANDOFFWRITTEN, filePath)
+	return nil
+}
@@ -0,0 +1,136 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	cfg "github.com/plusworx/gmin/utils/config"
+	"github.com/spf13/viper"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+func TestPasswordHashingFakeServer(t *testing.T) {
+	fs := newFakeServer(t)
+	fs.AddUser(&admin.User{PrimaryEmail: "donald.duck@disney.com", Name: &admin.UserName{GivenName: "Donald", FamilyName: "Duck"}})
+
+	cases := []struct {
+		args         []string
+		expectedErr  string
+		expectedHF   string
+		expectedHash string
+		user         string
+	}{
+		{
+			args:         []string{"create", "user", "mickey.mouse@disney.com", "-f", "Mickey", "-l", "Mouse", "-p", "MySuperStrongPassword", "--hasher", "SHA-1"},
+			expectedHF:   "SHA-1",
+			expectedHash: "e1f7c050db42a86e4d358e8c1dcef57e3b4f2fc0",
+			user:         "mickey.mouse@disney.com",
+		},
+		{
+			args:         []string{"create", "user", "minnie.mouse@disney.com", "-f", "Minnie", "-l", "Mouse", "-a", `{"password":"$6$saltstring$hash","hashFunction":"crypt"}`},
+			expectedHF:   "crypt",
+			expectedHash: "$6$saltstring$hash",
+			user:         "minnie.mouse@disney.com",
+		},
+		{
+			args:        []string{"create", "user", "goofy@disney.com", "-f", "Goofy", "-l", "Goof", "-a", `{"password":"NotAHash","hashFunction":"MD5"}`},
+			expectedErr: "password is not a valid MD5 hash",
+		},
+		{
+			args:        []string{"create", "user", "goofy@disney.com", "-f", "Goofy", "-l", "Goof", "-p", "MySuperStrongPassword", "--hasher", "bcrypt"},
+			expectedErr: "invalid password hasher: bcrypt - valid hashers are: crypt, md5, sha-1",
+		},
+		{
+			args:         []string{"update", "user", "donald.duck@disney.com", "-p", "MySuperStrongPassword"},
+			expectedHF:   "crypt",
+			expectedHash: "$6$",
+			user:         "donald.duck@disney.com",
+		},
+	}
+
+	for _, c := range cases {
+		_, err := runGmin(t, c.args...)
+
+		gotErr := ""
+		if err != nil {
+			gotErr = err.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("Got error: %v - expected error: %v", gotErr, c.expectedErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		user := fs.User(c.user)
+		if user.HashFunction != c.expectedHF || !strings.HasPrefix(user.Password, c.expectedHash) {
+			t.Errorf("Got password: %v %v - expected password: %v %v", user.HashFunction, user.Password, c.expectedHF, c.expectedHash)
+		}
+	}
+
+	viper.Set(cfg.CONFIGHASHER, "md5")
+	defer viper.Set(cfg.CONFIGHASHER, "")
+
+	_, err := runGmin(t, "update", "user", "donald.duck@disney.com", "-p", "MySuperStrongPassword")
+	if err != nil {
+		t.Fatal(err)
+	}
+	donald := fs.User("donald.duck@disney.com")
+	if donald.HashFunction != "MD5" || donald.Password != "a74c74cabf0ddbd80ca0031aa55c2c83" {
+		t.Errorf("Got password: %v %v - expected config hasher to make MD5 hash", donald.HashFunction, donald.Password)
+	}
+}
+
+func TestBatchCreateUsersPreHashedFakeServer(t *testing.T) {
+	fs := newFakeServer(t)
+
+	dir := t.TempDir()
+	input := filepath.Join(dir, "users.csv")
+	ioutil.WriteFile(input, []byte(`primaryEmail,firstName,lastName,password,hashFunction
+mickey.mouse@disney.com,Mickey,Mouse,MySuperStrongPassword,
+minnie.mouse@disney.com,Minnie,Mouse,e1f7c050db42a86e4d358e8c1dcef57e3b4f2fc0,sha-1
+goofy@disney.com,Goofy,Goof,NotAHash,SHA-1
+`), 0644)
+
+	_, err := runGmin(t, "batch-create", "users", "-i", input, "-f", "csv", "--results-dir", dir)
+	if err == nil || !strings.Contains(err.Error(), "1 of 3") {
+		t.Errorf("Got error: %v - expected 1 of 3 batch rows to fail", err)
+	}
+
+	mickey := fs.User("mickey.mouse@disney.com")
+	if mickey == nil || mickey.HashFunction != "crypt" || !strings.HasPrefix(mickey.Password, "$6$") {
+		t.Errorf("Got mickey: %v - expected password to be hashed once with crypt", mickey)
+	}
+	minnie := fs.User("minnie.mouse@disney.com")
+	if minnie == nil || minnie.HashFunction != "SHA-1" || minnie.Password != "e1f7c050db42a86e4d358e8c1dcef57e3b4f2fc0" {
+		t.Errorf("Got minnie: %v - expected already hashed password to be kept", minnie)
+	}
+	if fs.User("goofy@disney.com") != nil {
+		t.Error("Got goofy created - expected invalid SHA-1 hash to be rejected")
+	}
+}
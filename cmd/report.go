@@ -0,0 +1,77 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	"github.com/spf13/cobra"
+)
+
+var reportCmd = &cobra.Command{
+	Use:     "report",
+	Aliases: []string{"rpt"},
+	Args:    cobra.NoArgs,
+	Short:   "Outputs reports built from Google Workspace data",
+	Long:    "Outputs reports built from Google Workspace data.",
+	Run:     doReport,
+}
+
+func doReport(cmd *cobra.Command, args []string) {
+	cmd.Help()
+}
+
+// writeReportBatchFile writes a batch input file with total entries that carries out action on the
+// objects in a report
+func writeReportBatchFile(path string, action string, content []byte, total int) error {
+	lg.Debugw("starting writeReportBatchFile()",
+		"path", path,
+		"action", action)
+	defer lg.Debug("finished writeReportBatchFile()")
+
+	err := ioutil.WriteFile(path, content, 0600)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	// The report itself may be piped from standard output so the file message goes to standard error
+	fmt.Fprintln(os.Stderr, cmn.GminMessage(fmt.Sprintf(gmess.INFO_BATCHFILEWRITTEN, action, total, path)))
+	lg.Infof(gmess.INFO_BATCHFILEWRITTEN, action, total, path)
+
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.PersistentFlags().StringVar(&logLevel, flgnm.FLG_LOGLEVEL, "info", "log level (debug, info, error, warn)")
+	reportCmd.PersistentFlags().StringVar(&output, flgnm.FLG_OUTPUT, "json", "output format (csv, json, jsonl, table, tsv, yaml)")
+
+	reportCmd.PersistentPreRunE = preRunForDisplayCmds
+}
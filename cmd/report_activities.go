@@ -0,0 +1,242 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"time"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	gpars "github.com/plusworx/gmin/utils/gminparsers"
+	lg "github.com/plusworx/gmin/utils/logging"
+	rpts "github.com/plusworx/gmin/utils/reports"
+	"github.com/spf13/cobra"
+	reports "google.golang.org/api/admin/reports/v1"
+)
+
+var reportActivitiesCmd = &cobra.Command{
+	Use:     "activities <application>",
+	Aliases: []string{"activity", "acts", "act"},
+	Args:    cobra.ExactArgs(1),
+	Example: `gmin report activities login --start-time 7d --output csv
+gmin rpt acts admin -u mickey.mouse@disney.com -a id(time)~actor(email)~events(name) --pages all
+gmin rpt acts drive --event-name download --filters "doc_type==document" --start-time 2020-10-01T00:00:00Z`,
+	Short: "Outputs audit activity records for an application",
+	Long: `Outputs audit activity records for an application from the Admin Reports API.
+
+Valid applications are:
+access_transparency
+admin
+calendar
+chat
+chrome
+context_aware_access
+data_studio
+drive
+gcp
+gplus
+groups
+groups_enterprise
+jamboard
+login
+meet
+mobile
+rules
+saml
+token
+user_accounts
+
+--start-time and --end-time take an RFC 3339 time like 2020-10-01T00:00:00Z or a duration before now like
+12h, 7d or 2w. --event-name and --filters are passed to the API as they are, so their values are the
+event and parameter names of the application's activity reports.
+
+JSONL, CSV and TSV output and counts are written as each page of results arrives. If listing stops with
+an error, --page-token resumes it from the page given in the error message.`,
+	RunE: doReportActivities,
+}
+
+func doReportActivities(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doReportActivities()",
+		"args", args)
+	defer lg.Debug("finished doReportActivities()")
+
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	application, err := rpts.ValidateApplication(args[0])
+	if err != nil {
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEREPORT, reports.AdminReportsAuditReadonlyScope)
+	if err != nil {
+		return err
+	}
+	rs := srv.(*reports.Service)
+
+	alc, listAttrs, err := ractListCall(cmd, rs, application)
+	if err != nil {
+		return err
+	}
+
+	activities := new(reports.Activities)
+	pl := pagedList{
+		fields:  listAttrs,
+		listKey: rpts.ACTIVITYLISTKEY,
+		merge: func(page interface{}) {
+			pgActivities := page.(*reports.Activities)
+			activities.Items = append(activities.Items, pgActivities.Items...)
+			activities.Etag = pgActivities.Etag
+			activities.Kind = pgActivities.Kind
+			activities.NextPageToken = pgActivities.NextPageToken
+		},
+		result: activities,
+		size: func(page interface{}) int {
+			return len(page.(*reports.Activities).Items)
+		},
+	}
+
+	return listPages(cmd, outputFmt, pl, ractFetch(alc), nil, nil)
+}
+
+// ractFetch returns a function that gets a page of activities
+func ractFetch(alc *reports.ActivitiesListCall) cmn.PageFunc {
+	return func(pageToken string) (interface{}, string, error) {
+		if pageToken != "" {
+			alc = rpts.AddPageToken(alc, pageToken).(*reports.ActivitiesListCall)
+		}
+		activities, err := rpts.DoActivitiesList(alc)
+		if err != nil {
+			return nil, "", err
+		}
+		return activities, activities.NextPageToken, nil
+	}
+}
+
+// ractListCall sets up an activities list call for application from command flags
+func ractListCall(cmd *cobra.Command, rs *reports.Service, application string) (*reports.ActivitiesListCall, string, error) {
+	lg.Debugw("starting ractListCall()",
+		"application", application)
+	defer lg.Debug("finished ractListCall()")
+
+	var listAttrs string
+
+	flgUserKeyVal, err := cmd.Flags().GetString(flgnm.FLG_USERKEY)
+	if err != nil {
+		lg.Error(err)
+		return nil, "", err
+	}
+	if flgUserKeyVal == "" {
+		flgUserKeyVal = rpts.ALLUSERS
+	}
+
+	alc := rs.Activities.List(flgUserKeyVal, application)
+
+	flgAttrsVal, err := cmd.Flags().GetString(flgnm.FLG_ATTRIBUTES)
+	if err != nil {
+		lg.Error(err)
+		return nil, "", err
+	}
+	if flgAttrsVal != "" {
+		listAttrs, err = gpars.ParseOutputAttrs(flgAttrsVal, rpts.ActivityAttrMap)
+		if err != nil {
+			return nil, "", err
+		}
+		formattedAttrs := "nextPageToken," + rpts.STARTACTIVITIESFIELD + listAttrs + rpts.ENDFIELD
+		alc = rpts.AddFields(alc, formattedAttrs).(*reports.ActivitiesListCall)
+	}
+
+	now := time.Now()
+
+	flgStartTimeVal, err := cmd.Flags().GetString(flgnm.FLG_STARTTIME)
+	if err != nil {
+		lg.Error(err)
+		return nil, "", err
+	}
+	if flgStartTimeVal != "" {
+		start, err := rpts.ParseReportTime(flgStartTimeVal, now)
+		if err != nil {
+			return nil, "", err
+		}
+		alc = rpts.AddStartTime(alc, start)
+	}
+
+	flgEndTimeVal, err := cmd.Flags().GetString(flgnm.FLG_ENDTIME)
+	if err != nil {
+		lg.Error(err)
+		return nil, "", err
+	}
+	if flgEndTimeVal != "" {
+		end, err := rpts.ParseReportTime(flgEndTimeVal, now)
+		if err != nil {
+			return nil, "", err
+		}
+		alc = rpts.AddEndTime(alc, end)
+	}
+
+	flgEventNameVal, err := cmd.Flags().GetString(flgnm.FLG_EVENTNAME)
+	if err != nil {
+		lg.Error(err)
+		return nil, "", err
+	}
+	if flgEventNameVal != "" {
+		alc = rpts.AddEventName(alc, flgEventNameVal)
+	}
+
+	flgFiltersVal, err := cmd.Flags().GetString(flgnm.FLG_FILTERS)
+	if err != nil {
+		lg.Error(err)
+		return nil, "", err
+	}
+	if flgFiltersVal != "" {
+		alc = rpts.AddFilters(alc, flgFiltersVal).(*reports.ActivitiesListCall)
+	}
+
+	flgMaxResultsVal, err := cmd.Flags().GetInt64(flgnm.FLG_MAXRESULTS)
+	if err != nil {
+		lg.Error(err)
+		return nil, "", err
+	}
+	alc = rpts.AddMaxResults(alc, flgMaxResultsVal).(*reports.ActivitiesListCall)
+
+	return alc, listAttrs, nil
+}
+
+func init() {
+	reportCmd.AddCommand(reportActivitiesCmd)
+
+	reportActivitiesCmd.Flags().StringVarP(&attrs, flgnm.FLG_ATTRIBUTES, "a", "", "required activity attributes (separated by ~)")
+	reportActivitiesCmd.Flags().BoolVarP(&count, flgnm.FLG_COUNT, "", false, "count number of entities returned")
+	reportActivitiesCmd.Flags().StringVar(&endTime, flgnm.FLG_ENDTIME, "", "end of the time range of returned activities")
+	reportActivitiesCmd.Flags().StringVarP(&eventName, flgnm.FLG_EVENTNAME, "e", "", "name of event that returned activities contain")
+	reportActivitiesCmd.Flags().StringVar(&filters, flgnm.FLG_FILTERS, "", "event parameter filters (separated by ,)")
+	reportActivitiesCmd.Flags().Int64VarP(&maxResults, flgnm.FLG_MAXRESULTS, "m", 1000, "maximum number of results to return per page")
+	reportActivitiesCmd.Flags().StringVar(&pageToken, flgnm.FLG_PAGETOKEN, "", "page token to resume listing from")
+	reportActivitiesCmd.Flags().StringVarP(&pages, flgnm.FLG_PAGES, "p", "", "number of pages of results to be returned ('all' or a number)")
+	reportActivitiesCmd.Flags().StringVar(&sortBy, flgnm.FLG_SORTBY, "", "attributes to sort results by (separated by ~)")
+	reportActivitiesCmd.Flags().StringVar(&startTime, flgnm.FLG_STARTTIME, "", "start of the time range of returned activities")
+	reportActivitiesCmd.Flags().StringVarP(&userKey, flgnm.FLG_USERKEY, "u", "", "email address or id of user whose activities are returned (default all)")
+	reportActivitiesCmd.Flags().StringVar(&where, flgnm.FLG_WHERE, "", "expression that results must match")
+}
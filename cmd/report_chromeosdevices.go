@@ -0,0 +1,349 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	cdevs "github.com/plusworx/gmin/utils/chromeosdevices"
+	cmn "github.com/plusworx/gmin/utils/common"
+	cfg "github.com/plusworx/gmin/utils/config"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	fmtrs "github.com/plusworx/gmin/utils/formatters"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var reportCrOSDevsCmd = &cobra.Command{
+	Use:     "chromeos-devices",
+	Aliases: []string{"chromeos-device", "cros-devices", "cros-device", "cros-devs", "cros-dev", "cdevs", "cdev"},
+	Args:    cobra.NoArgs,
+	Example: `gmin report chromeos-devices --output table
+gmin rpt cdevs -t /Classrooms --aue-months 12 --output csv
+gmin rpt cdevs --checks no-sync --sync-days 60 --batch-file disable.csv
+gmin rpt cdevs --checks aue --batch-file move.csv --batch-action move -o /Retiring`,
+	Short: "Outputs a summary report of the ChromeOS device fleet",
+	Long: `Outputs a summary report of the ChromeOS device fleet. Devices are counted by each of these dimensions:
+
+aue - how long it is until the device's auto update expiration
+check - the fleet checks that the device fails
+last-sync - how long ago the device last synced
+model - device model
+orgunit - device orgunit path
+os-version - ChromeOS version
+recent-user - the user who used the device most recently
+status - device status
+
+The fleet checks are:
+
+aue - devices whose auto update expiration has passed or is within --aue-months months
+no-sync - devices that have not synced for --sync-days days or have never synced
+
+All checks are made unless --checks is given. --orgunit-path limits the report to devices in an orgunit and
+the orgunits below it.
+
+--batch-file writes the IDs of devices that fail any check to a CSV input file that is ready to use with
+'gmin batch-manage chromeos-devices -i <file> -f csv' to disable (--batch-action disable, the default) or
+deprovision (--batch-action deprovision, which needs --reason) them, or with
+'gmin batch-move chromeos-devices -i <file> -f csv' to move them to the orgunit given by --orgunit
+(--batch-action move). Deprovisioned devices are left out of input files and disabled devices are left out
+of disable input files.`,
+	RunE: doReportCrOSDevs,
+}
+
+func doReportCrOSDevs(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doReportCrOSDevs()",
+		"args", args)
+	defer lg.Debug("finished doReportCrOSDevs()")
+
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	opts, err := rcdOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	flgBatchFileVal, err := cmd.Flags().GetString(flgnm.FLG_BATCHFILE)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	batchAction, actionVal, err := rcdBatchAction(cmd)
+	if err != nil {
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryDeviceChromeosReadonlyScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	cdlc, err := rcdListCall(cmd, ds)
+	if err != nil {
+		return err
+	}
+
+	// Devices are counted page by page so that large fleets can be reported on without holding every device
+	fleet := cdevs.NewFleet(opts)
+	pgntr := new(cmn.Paginator)
+	err = pgntr.Run(lcdFetch(cdlc), func(page interface{}) error {
+		for _, dev := range page.(*admin.ChromeOsDevices).Chromeosdevices {
+			fleet.Add(dev)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	report := struct {
+		Counts []*cdevs.FleetCount `json:"counts"`
+	}{Counts: fleet.Counts()}
+
+	err = fmtrs.Output(os.Stdout, outputFmt, report, cdevs.FLEETLISTKEY, "")
+	if err != nil {
+		return err
+	}
+
+	if flgBatchFileVal != "" {
+		content, total, err := rcdBatchInput(batchAction, actionVal, fleet.Matched)
+		if err != nil {
+			return err
+		}
+		err = writeReportBatchFile(flgBatchFileVal, batchAction, content, total)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rcdBatchAction gets the batch input file action and the deprovision reason or orgunit that goes with it
+func rcdBatchAction(cmd *cobra.Command) (string, string, error) {
+	lg.Debug("starting rcdBatchAction()")
+	defer lg.Debug("finished rcdBatchAction()")
+
+	flgBatchActionVal, err := cmd.Flags().GetString(flgnm.FLG_BATCHACTION)
+	if err != nil {
+		lg.Error(err)
+		return "", "", err
+	}
+	action := strings.ToLower(flgBatchActionVal)
+	if !cmn.SliceContainsStr(cdevs.ValidFleetActions, action) {
+		err = fmt.Errorf(gmess.ERR_INVALIDBATCHACTION, flgBatchActionVal, strings.Join(cdevs.ValidFleetActions, ", "))
+		lg.Error(err)
+		return "", "", err
+	}
+
+	switch action {
+	case cdevs.FLEETACTIONDEPROVISION:
+		flgReasonVal, err := cmd.Flags().GetString(flgnm.FLG_REASON)
+		if err != nil {
+			lg.Error(err)
+			return "", "", err
+		}
+		if flgReasonVal == "" {
+			err = errors.New(gmess.ERR_NODEPROVISIONREASON)
+			lg.Error(err)
+			return "", "", err
+		}
+		lowerReason := strings.ToLower(flgReasonVal)
+		if !cmn.SliceContainsStr(cdevs.ValidDeprovisionReasons, lowerReason) {
+			err = fmt.Errorf(gmess.ERR_INVALIDDEPROVISIONREASON, flgReasonVal)
+			lg.Error(err)
+			return "", "", err
+		}
+		return action, lowerReason, nil
+	case cdevs.FLEETACTIONMOVE:
+		flgOrgUnitVal, err := cmd.Flags().GetString(flgnm.FLG_ORGUNIT)
+		if err != nil {
+			lg.Error(err)
+			return "", "", err
+		}
+		if flgOrgUnitVal == "" {
+			err = errors.New(gmess.ERR_NOMOVEORGUNIT)
+			lg.Error(err)
+			return "", "", err
+		}
+		return action, flgOrgUnitVal, nil
+	}
+	return action, "", nil
+}
+
+// rcdBatchInput returns the contents of a CSV batch input file that carries out action on devices and
+// the number of devices in it. Deprovisioned devices are left out and so are disabled devices when the
+// action is disable.
+func rcdBatchInput(action string, actionVal string, devices []*admin.ChromeOsDevice) ([]byte, int, error) {
+	lg.Debugw("starting rcdBatchInput()",
+		"action", action,
+		"actionVal", actionVal)
+	defer lg.Debug("finished rcdBatchInput()")
+
+	var (
+		buf   bytes.Buffer
+		total int
+	)
+
+	cw := csv.NewWriter(&buf)
+
+	var header []string
+	switch action {
+	case cdevs.FLEETACTIONDEPROVISION:
+		header = []string{"deviceId", "action", "deprovisionReason"}
+	case cdevs.FLEETACTIONMOVE:
+		header = []string{"deviceId", "orgUnitPath"}
+	default:
+		header = []string{"deviceId", "action"}
+	}
+	err := cw.Write(header)
+	if err != nil {
+		lg.Error(err)
+		return nil, 0, err
+	}
+
+	for _, dev := range devices {
+		if dev.Status == cdevs.STATUSDEPROVISIONED || (action == cdevs.FLEETACTIONDISABLE && dev.Status == cdevs.STATUSDISABLED) {
+			continue
+		}
+
+		var row []string
+		switch action {
+		case cdevs.FLEETACTIONDEPROVISION:
+			row = []string{dev.DeviceId, action, actionVal}
+		case cdevs.FLEETACTIONMOVE:
+			row = []string{dev.DeviceId, actionVal}
+		default:
+			row = []string{dev.DeviceId, action}
+		}
+		err = cw.Write(row)
+		if err != nil {
+			lg.Error(err)
+			return nil, 0, err
+		}
+		total++
+	}
+	cw.Flush()
+	err = cw.Error()
+	if err != nil {
+		lg.Error(err)
+		return nil, 0, err
+	}
+	return buf.Bytes(), total, nil
+}
+
+// rcdListCall sets up a list call that gets the attributes needed by the fleet report for every device
+// in the customer or orgunit
+func rcdListCall(cmd *cobra.Command, ds *admin.Service) (*admin.ChromeosdevicesListCall, error) {
+	lg.Debug("starting rcdListCall()")
+	defer lg.Debug("finished rcdListCall()")
+
+	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	cdlc := ds.Chromeosdevices.List(customerID)
+	cdlc = cdevs.AddFields(cdlc, "nextPageToken,"+cdevs.STARTCHROMEDEVICESFIELD+cdevs.FLEETFIELDS+cdevs.ENDFIELD).(*admin.ChromeosdevicesListCall)
+	cdlc = cdevs.AddMaxResults(cdlc, 200)
+
+	flgOUVal, err := cmd.Flags().GetString(flgnm.FLG_ORGUNITPATH)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+	if flgOUVal != "" {
+		cdlc = cdevs.AddOrgUnitPath(cdlc, flgOUVal)
+	}
+	return cdlc, nil
+}
+
+// rcdOptions gets the fleet checks and thresholds from command flags
+func rcdOptions(cmd *cobra.Command) (cdevs.FleetOptions, error) {
+	lg.Debug("starting rcdOptions()")
+	defer lg.Debug("finished rcdOptions()")
+
+	opts := cdevs.FleetOptions{Checks: cdevs.ValidFleetChecks, Now: time.Now()}
+
+	flgChecksVal, err := cmd.Flags().GetString(flgnm.FLG_CHECKS)
+	if err != nil {
+		lg.Error(err)
+		return opts, err
+	}
+	if flgChecksVal != "" {
+		opts.Checks = strings.Split(strings.ToLower(flgChecksVal), "~")
+		err = cdevs.ValidateFleetChecks(opts.Checks)
+		if err != nil {
+			return opts, err
+		}
+	}
+
+	thresholds := []struct {
+		flgName string
+		val     *int
+	}{
+		{flgName: flgnm.FLG_AUEMONTHS, val: &opts.AUEMonths},
+		{flgName: flgnm.FLG_SYNCDAYS, val: &opts.SyncDays},
+	}
+	for _, threshold := range thresholds {
+		flgVal, err := cmd.Flags().GetInt(threshold.flgName)
+		if err != nil {
+			lg.Error(err)
+			return opts, err
+		}
+		if flgVal < 0 {
+			err = fmt.Errorf(gmess.ERR_INVALIDDAYS, threshold.flgName, flgVal)
+			lg.Error(err)
+			return opts, err
+		}
+		*threshold.val = flgVal
+	}
+	return opts, nil
+}
+
+func init() {
+	reportCmd.AddCommand(reportCrOSDevsCmd)
+
+	reportCrOSDevsCmd.Flags().IntVar(&aueMonths, flgnm.FLG_AUEMONTHS, 6, "months before auto update expiration that devices fail the aue check")
+	reportCrOSDevsCmd.Flags().StringVar(&deviceAction, flgnm.FLG_BATCHACTION, cdevs.FLEETACTIONDISABLE, "action that the batch input file is for (deprovision, disable, move)")
+	reportCrOSDevsCmd.Flags().StringVar(&batchFile, flgnm.FLG_BATCHFILE, "", "path of batch input file to write for devices that fail fleet checks")
+	reportCrOSDevsCmd.Flags().StringVar(&checks, flgnm.FLG_CHECKS, "", "checks to make (separated by ~)")
+	reportCrOSDevsCmd.Flags().StringVarP(&targetOrgUnit, flgnm.FLG_ORGUNIT, "o", "", "orgunit path to move devices to when --batch-action is move")
+	reportCrOSDevsCmd.Flags().StringVarP(&orgUnit, flgnm.FLG_ORGUNITPATH, "t", "", "orgunit path of devices to report on")
+	reportCrOSDevsCmd.Flags().StringVarP(&reason, flgnm.FLG_REASON, "r", "", "device deprovision reason when --batch-action is deprovision")
+	reportCrOSDevsCmd.Flags().IntVar(&syncDays, flgnm.FLG_SYNCDAYS, 30, "days without sync before devices fail the no-sync check")
+}
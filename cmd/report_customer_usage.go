@@ -0,0 +1,149 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	cmn "github.com/plusworx/gmin/utils/common"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	gpars "github.com/plusworx/gmin/utils/gminparsers"
+	lg "github.com/plusworx/gmin/utils/logging"
+	rpts "github.com/plusworx/gmin/utils/reports"
+	"github.com/spf13/cobra"
+	reports "google.golang.org/api/admin/reports/v1"
+)
+
+var reportCustomerUsageCmd = &cobra.Command{
+	Use:     "customer-usage",
+	Aliases: []string{"customer-usage-reports", "cust-usage", "cusage"},
+	Args:    cobra.NoArgs,
+	Example: `gmin report customer-usage -d 2020-10-12
+gmin rpt cusage -d 2020-10-12 --parameters accounts:num_users~gmail:num_emails_sent --output yaml`,
+	Short: "Outputs usage reports for the customer account",
+	Long: `Outputs usage reports for the customer account from the Admin Reports API.
+
+--date is required and is the day that the usage reports are for in the format YYYY-MM-DD. Usage data
+usually takes a few days to become available. --parameters selects the usage parameters that are returned
+and is passed to the API as it is, except that ~ separates parameters.`,
+	RunE: doReportCustomerUsage,
+}
+
+func doReportCustomerUsage(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doReportCustomerUsage()",
+		"args", args)
+	defer lg.Debug("finished doReportCustomerUsage()")
+
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	date, err := reportUsageDate(cmd)
+	if err != nil {
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEREPORT, reports.AdminReportsUsageReadonlyScope)
+	if err != nil {
+		return err
+	}
+	rs := srv.(*reports.Service)
+
+	cugc, listAttrs, err := rcuGetCall(cmd, rs, date)
+	if err != nil {
+		return err
+	}
+
+	usageReports := new(reports.UsageReports)
+	pl := pagedList{
+		fields:  listAttrs,
+		listKey: rpts.USAGELISTKEY,
+		merge:   reportUsageMerge(usageReports),
+		result:  usageReports,
+		size:    reportUsageSize,
+	}
+
+	return listPages(cmd, outputFmt, pl, rcuFetch(cugc), nil, nil)
+}
+
+// rcuFetch returns a function that gets a page of customer usage reports
+func rcuFetch(cugc *reports.CustomerUsageReportsGetCall) cmn.PageFunc {
+	return func(pageToken string) (interface{}, string, error) {
+		if pageToken != "" {
+			cugc = rpts.AddPageToken(cugc, pageToken).(*reports.CustomerUsageReportsGetCall)
+		}
+		usageReports, err := rpts.DoCustomerUsageGet(cugc)
+		if err != nil {
+			return nil, "", err
+		}
+		return usageReports, usageReports.NextPageToken, nil
+	}
+}
+
+// rcuGetCall sets up a customer usage report get call for date from command flags
+func rcuGetCall(cmd *cobra.Command, rs *reports.Service, date string) (*reports.CustomerUsageReportsGetCall, string, error) {
+	lg.Debugw("starting rcuGetCall()",
+		"date", date)
+	defer lg.Debug("finished rcuGetCall()")
+
+	var listAttrs string
+
+	cugc := rs.CustomerUsageReports.Get(date)
+
+	flgAttrsVal, err := cmd.Flags().GetString(flgnm.FLG_ATTRIBUTES)
+	if err != nil {
+		lg.Error(err)
+		return nil, "", err
+	}
+	if flgAttrsVal != "" {
+		listAttrs, err = gpars.ParseOutputAttrs(flgAttrsVal, rpts.UsageAttrMap)
+		if err != nil {
+			return nil, "", err
+		}
+		formattedAttrs := "nextPageToken," + rpts.STARTUSAGEREPORTSFIELD + listAttrs + rpts.ENDFIELD
+		cugc = rpts.AddFields(cugc, formattedAttrs).(*reports.CustomerUsageReportsGetCall)
+	}
+
+	flgParametersVal, err := cmd.Flags().GetString(flgnm.FLG_PARAMETERS)
+	if err != nil {
+		lg.Error(err)
+		return nil, "", err
+	}
+	if flgParametersVal != "" {
+		cugc = rpts.AddParameters(cugc, rpts.FormatParameters(flgParametersVal)).(*reports.CustomerUsageReportsGetCall)
+	}
+
+	return cugc, listAttrs, nil
+}
+
+func init() {
+	reportCmd.AddCommand(reportCustomerUsageCmd)
+
+	reportCustomerUsageCmd.Flags().StringVarP(&attrs, flgnm.FLG_ATTRIBUTES, "a", "", "required usage report attributes (separated by ~)")
+	reportCustomerUsageCmd.Flags().BoolVarP(&count, flgnm.FLG_COUNT, "", false, "count number of entities returned")
+	reportCustomerUsageCmd.Flags().StringVarP(&reportDate, flgnm.FLG_DATE, "d", "", "date of usage reports (YYYY-MM-DD)")
+	reportCustomerUsageCmd.Flags().StringVar(&pageToken, flgnm.FLG_PAGETOKEN, "", "page token to resume listing from")
+	reportCustomerUsageCmd.Flags().StringVarP(&pages, flgnm.FLG_PAGES, "p", "", "number of pages of results to be returned ('all' or a number)")
+	reportCustomerUsageCmd.Flags().StringVar(&parameters, flgnm.FLG_PARAMETERS, "", "usage parameters to return (separated by ~)")
+	reportCustomerUsageCmd.Flags().StringVar(&sortBy, flgnm.FLG_SORTBY, "", "attributes to sort results by (separated by ~)")
+	reportCustomerUsageCmd.Flags().StringVar(&where, flgnm.FLG_WHERE, "", "expression that results must match")
+}
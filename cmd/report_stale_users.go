@@ -0,0 +1,299 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	cfg "github.com/plusworx/gmin/utils/config"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	fmtrs "github.com/plusworx/gmin/utils/formatters"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	usrs "github.com/plusworx/gmin/utils/users"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var reportStaleUsersCmd = &cobra.Command{
+	Use:     "stale-users",
+	Aliases: []string{"stale-user", "stale"},
+	Args:    cobra.NoArgs,
+	Example: `gmin report stale-users --output csv
+gmin rpt stale -t /Sales --checks inactive~never-logged-in --inactive-days 60
+gmin rpt stale --checks inactive --batch-file suspend.csv --batch-action suspend`,
+	Short: "Outputs a report of inactive and stale user accounts",
+	Long: `Outputs a report of inactive and stale user accounts. Each user is listed once with the checks that they
+fail, which can be any of:
+
+inactive - active users who have not logged in for --inactive-days days
+never-logged-in - active users who have never logged in and were created more than --never-logged-in-days days ago
+no-2sv - active users who are not enrolled in 2-Step Verification
+suspended - suspended users who have not logged in, or were created if they never did, more than --suspended-days days ago
+
+All checks are made unless --checks is given. --orgunit-path limits the report to users in an orgunit and
+the orgunits below it.
+
+--batch-file writes the users in the report to an input file that is ready to use with either
+'gmin batch-update users -i <file> -f csv' to suspend them (--batch-action suspend, the default) or
+'gmin batch-delete users -i <file>' to delete them (--batch-action delete). Users who only fail the
+no-2sv check are left out of input files and users who are already suspended are left out of suspend
+input files.`,
+	RunE: doReportStaleUsers,
+}
+
+func doReportStaleUsers(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doReportStaleUsers()",
+		"args", args)
+	defer lg.Debug("finished doReportStaleUsers()")
+
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	opts, err := rsuOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	flgBatchActionVal, err := cmd.Flags().GetString(flgnm.FLG_BATCHACTION)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	batchAction := strings.ToLower(flgBatchActionVal)
+	if !cmn.SliceContainsStr(usrs.ValidStaleActions, batchAction) {
+		err = fmt.Errorf(gmess.ERR_INVALIDBATCHACTION, flgBatchActionVal, strings.Join(usrs.ValidStaleActions, ", "))
+		lg.Error(err)
+		return err
+	}
+
+	flgBatchFileVal, err := cmd.Flags().GetString(flgnm.FLG_BATCHFILE)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryUserReadonlyScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	ulc, err := rsuListCall(cmd, ds)
+	if err != nil {
+		return err
+	}
+
+	report := struct {
+		Users []*usrs.StaleUser `json:"users"`
+	}{Users: []*usrs.StaleUser{}}
+
+	// Only stale users are kept so that large directories can be reported on without holding every user
+	pgntr := new(cmn.Paginator)
+	err = pgntr.Run(lusFetch(ulc), func(page interface{}) error {
+		for _, user := range page.(*admin.Users).Users {
+			reasons := usrs.StaleReasons(user, opts)
+			if len(reasons) == 0 {
+				continue
+			}
+			report.Users = append(report.Users, &usrs.StaleUser{
+				CreationTime:    user.CreationTime,
+				IsEnrolledIn2Sv: user.IsEnrolledIn2Sv,
+				LastLoginTime:   user.LastLoginTime,
+				OrgUnitPath:     user.OrgUnitPath,
+				PrimaryEmail:    user.PrimaryEmail,
+				Reasons:         reasons,
+				Suspended:       user.Suspended,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = fmtrs.Output(os.Stdout, outputFmt, report, usrs.LISTKEY, "")
+	if err != nil {
+		return err
+	}
+
+	if flgBatchFileVal != "" {
+		content, total, err := rsuBatchInput(batchAction, report.Users)
+		if err != nil {
+			return err
+		}
+		err = writeReportBatchFile(flgBatchFileVal, batchAction, content, total)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rsuBatchInput returns the contents of a batch input file that carries out action on users. Users
+// who are only reported for not being enrolled in 2-Step Verification are left out because they are
+// still in use.
+func rsuBatchInput(action string, users []*usrs.StaleUser) ([]byte, int, error) {
+	lg.Debugw("starting rsuBatchInput()",
+		"action", action)
+	defer lg.Debug("finished rsuBatchInput()")
+
+	var (
+		buf   bytes.Buffer
+		total int
+	)
+
+	if action == usrs.STALEACTIONDELETE {
+		for _, user := range users {
+			if rsuNo2SVOnly(user) {
+				continue
+			}
+			fmt.Fprintln(&buf, user.PrimaryEmail)
+			total++
+		}
+		return buf.Bytes(), total, nil
+	}
+
+	cw := csv.NewWriter(&buf)
+	err := cw.Write([]string{usrs.KEYNAME, "suspended"})
+	if err != nil {
+		lg.Error(err)
+		return nil, 0, err
+	}
+	for _, user := range users {
+		if user.Suspended || rsuNo2SVOnly(user) {
+			continue
+		}
+		err = cw.Write([]string{user.PrimaryEmail, "true"})
+		if err != nil {
+			lg.Error(err)
+			return nil, 0, err
+		}
+		total++
+	}
+	cw.Flush()
+	err = cw.Error()
+	if err != nil {
+		lg.Error(err)
+		return nil, 0, err
+	}
+	return buf.Bytes(), total, nil
+}
+
+// rsuNo2SVOnly reports whether the only check that a user fails is no-2sv
+func rsuNo2SVOnly(user *usrs.StaleUser) bool {
+	return len(user.Reasons) == 1 && user.Reasons[0] == usrs.STALENO2SV
+}
+
+// rsuListCall sets up a list call that gets the attributes needed by stale user checks for every user
+// in the customer or orgunit
+func rsuListCall(cmd *cobra.Command, ds *admin.Service) (*admin.UsersListCall, error) {
+	lg.Debug("starting rsuListCall()")
+	defer lg.Debug("finished rsuListCall()")
+
+	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	ulc := ds.Users.List()
+	ulc = usrs.AddCustomer(ulc, customerID)
+	ulc = usrs.AddFields(ulc, "nextPageToken,"+usrs.STARTUSERSFIELD+usrs.STALEFIELDS+usrs.ENDFIELD).(*admin.UsersListCall)
+	ulc = usrs.AddMaxResults(ulc, 500)
+
+	flgOUVal, err := cmd.Flags().GetString(flgnm.FLG_ORGUNITPATH)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+	if flgOUVal != "" {
+		ulc = usrs.AddQuery(ulc, "orgUnitPath='"+flgOUVal+"'")
+	}
+	return ulc, nil
+}
+
+// rsuOptions gets the stale user checks and thresholds from command flags
+func rsuOptions(cmd *cobra.Command) (usrs.StaleOptions, error) {
+	lg.Debug("starting rsuOptions()")
+	defer lg.Debug("finished rsuOptions()")
+
+	opts := usrs.StaleOptions{Checks: usrs.ValidStaleChecks, Now: time.Now()}
+
+	flgChecksVal, err := cmd.Flags().GetString(flgnm.FLG_CHECKS)
+	if err != nil {
+		lg.Error(err)
+		return opts, err
+	}
+	if flgChecksVal != "" {
+		opts.Checks = strings.Split(strings.ToLower(flgChecksVal), "~")
+		err = usrs.ValidateStaleChecks(opts.Checks)
+		if err != nil {
+			return opts, err
+		}
+	}
+
+	days := []struct {
+		flgName string
+		val     *int
+	}{
+		{flgName: flgnm.FLG_INACTIVEDAYS, val: &opts.InactiveDays},
+		{flgName: flgnm.FLG_NEVERLOGGEDINDAYS, val: &opts.NeverLoggedInDays},
+		{flgName: flgnm.FLG_SUSPENDEDDAYS, val: &opts.SuspendedDays},
+	}
+	for _, day := range days {
+		flgVal, err := cmd.Flags().GetInt(day.flgName)
+		if err != nil {
+			lg.Error(err)
+			return opts, err
+		}
+		if flgVal < 0 {
+			err = fmt.Errorf(gmess.ERR_INVALIDDAYS, day.flgName, flgVal)
+			lg.Error(err)
+			return opts, err
+		}
+		*day.val = flgVal
+	}
+	return opts, nil
+}
+
+func init() {
+	reportCmd.AddCommand(reportStaleUsersCmd)
+
+	reportStaleUsersCmd.Flags().StringVar(&batchAction, flgnm.FLG_BATCHACTION, usrs.STALEACTIONSUSPEND, "action that the batch input file is for (delete, suspend)")
+	reportStaleUsersCmd.Flags().StringVar(&batchFile, flgnm.FLG_BATCHFILE, "", "path of batch input file to write for the users in the report")
+	reportStaleUsersCmd.Flags().StringVar(&checks, flgnm.FLG_CHECKS, "", "checks to make (separated by ~)")
+	reportStaleUsersCmd.Flags().IntVar(&inactiveDays, flgnm.FLG_INACTIVEDAYS, 90, "days without login before an active user is inactive")
+	reportStaleUsersCmd.Flags().IntVar(&neverLoggedInDays, flgnm.FLG_NEVERLOGGEDINDAYS, 30, "days since creation before a user who has never logged in is reported")
+	reportStaleUsersCmd.Flags().StringVarP(&orgUnit, flgnm.FLG_ORGUNITPATH, "t", "", "orgunit path of users to report on")
+	reportStaleUsersCmd.Flags().IntVar(&suspendedDays, flgnm.FLG_SUSPENDEDDAYS, 90, "days without login before a suspended user is reported")
+}
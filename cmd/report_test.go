@@ -0,0 +1,465 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	lg "github.com/plusworx/gmin/utils/logging"
+	admin "google.golang.org/api/admin/directory/v1"
+	reports "google.golang.org/api/admin/reports/v1"
+)
+
+func TestReportStaleUsersFakeServer(t *testing.T) {
+	const neverLoggedIn = "1970-01-01T00:00:00.000Z"
+
+	now := time.Now().UTC()
+	daysAgo := func(days int) string {
+		return now.AddDate(0, 0, -days).Format(time.RFC3339)
+	}
+
+	fs := newFakeServer(t)
+	fs.AddUser(&admin.User{PrimaryEmail: "active@disney.com", CreationTime: daysAgo(400), LastLoginTime: daysAgo(1), IsEnrolledIn2Sv: true, OrgUnitPath: "/"})
+	fs.AddUser(&admin.User{PrimaryEmail: "idle@disney.com", CreationTime: daysAgo(400), LastLoginTime: daysAgo(100), IsEnrolledIn2Sv: true, OrgUnitPath: "/Sales"})
+	fs.AddUser(&admin.User{PrimaryEmail: "new@disney.com", CreationTime: daysAgo(40), LastLoginTime: neverLoggedIn, IsEnrolledIn2Sv: true, OrgUnitPath: "/Sales/UK"})
+	fs.AddUser(&admin.User{PrimaryEmail: "no2sv@disney.com", CreationTime: daysAgo(400), LastLoginTime: daysAgo(1), OrgUnitPath: "/"})
+	fs.AddUser(&admin.User{PrimaryEmail: "gone@disney.com", CreationTime: daysAgo(400), LastLoginTime: daysAgo(200), Suspended: true, OrgUnitPath: "/Sales"})
+
+	dir := t.TempDir()
+	allFile := filepath.Join(dir, "all.csv")
+	suspendFile := filepath.Join(dir, "suspend.csv")
+	deleteFile := filepath.Join(dir, "delete.txt")
+
+	allStale := "creationTime,isEnrolledIn2Sv,lastLoginTime,orgUnitPath,primaryEmail,reasons,suspended\n" +
+		daysAgo(400) + ",false," + daysAgo(200) + ",/Sales,gone@disney.com,suspended,true\n" +
+		daysAgo(400) + ",true," + daysAgo(100) + ",/Sales,idle@disney.com,inactive,false\n" +
+		daysAgo(40) + ",true," + neverLoggedIn + ",/Sales/UK,new@disney.com,never-logged-in,false\n" +
+		daysAgo(400) + ",false," + daysAgo(1) + ",/,no2sv@disney.com,no-2sv,false\n"
+
+	cases := []struct {
+		args        []string
+		expectedErr string
+		expectedOut string
+	}{
+		{
+			args:        []string{"report", "stale-users", "--output", "csv"},
+			expectedOut: allStale,
+		},
+		{
+			args:        []string{"report", "stale-users", "--batch-file", allFile, "--output", "csv"},
+			expectedOut: allStale,
+		},
+		{
+			args: []string{"report", "stale-users", "-t", "/Sales", "--inactive-days", "30", "--never-logged-in-days", "60", "--output", "csv"},
+			expectedOut: "creationTime,isEnrolledIn2Sv,lastLoginTime,orgUnitPath,primaryEmail,reasons,suspended\n" +
+				daysAgo(400) + ",false," + daysAgo(200) + ",/Sales,gone@disney.com,suspended,true\n" +
+				daysAgo(400) + ",true," + daysAgo(100) + ",/Sales,idle@disney.com,inactive,false\n",
+		},
+		{
+			args:        []string{"report", "stale-users", "--checks", "inactive~suspended", "--batch-file", suspendFile, "--output", "jsonl"},
+			expectedOut: `{"creationTime":"` + daysAgo(400) + `","isEnrolledIn2Sv":false,"lastLoginTime":"` + daysAgo(200) + `","orgUnitPath":"/Sales","primaryEmail":"gone@disney.com","reasons":["suspended"],"suspended":true}` + "\n" + `{"creationTime":"` + daysAgo(400) + `","isEnrolledIn2Sv":true,"lastLoginTime":"` + daysAgo(100) + `","orgUnitPath":"/Sales","primaryEmail":"idle@disney.com","reasons":["inactive"],"suspended":false}` + "\n",
+		},
+		{
+			args:        []string{"report", "stale-users", "--checks", "suspended", "--batch-file", deleteFile, "--batch-action", "delete", "--output", "jsonl"},
+			expectedOut: `{"creationTime":"` + daysAgo(400) + `","isEnrolledIn2Sv":false,"lastLoginTime":"` + daysAgo(200) + `","orgUnitPath":"/Sales","primaryEmail":"gone@disney.com","reasons":["suspended"],"suspended":true}` + "\n",
+		},
+		{
+			args:        []string{"report", "stale-users", "--checks", "dormant"},
+			expectedErr: "invalid stale user check: dormant - valid checks are: inactive, never-logged-in, no-2sv, suspended",
+		},
+		{
+			args:        []string{"report", "stale-users", "--batch-action", "archive"},
+			expectedErr: "invalid batch action: archive - valid actions are: delete, suspend",
+		},
+		{
+			args:        []string{"report", "stale-users", "--inactive-days", "-1"},
+			expectedErr: "inactive-days must not be negative: -1",
+		},
+	}
+
+	for _, c := range cases {
+		out, got := runGmin(t, c.args...)
+
+		gotErr := ""
+		if got != nil {
+			gotErr = got.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("%v - got error: %v - expected error: %v", c.args, gotErr, c.expectedErr)
+		}
+		if out != c.expectedOut {
+			t.Errorf("%v - got output: %v - expected output: %v", c.args, out, c.expectedOut)
+		}
+	}
+
+	batchFiles := []struct {
+		expectedContent string
+		path            string
+	}{
+		{
+			expectedContent: "userKey,suspended\nidle@disney.com,true\nnew@disney.com,true\n",
+			path:            allFile,
+		},
+		{
+			expectedContent: "userKey,suspended\nidle@disney.com,true\n",
+			path:            suspendFile,
+		},
+		{
+			expectedContent: "gone@disney.com\n",
+			path:            deleteFile,
+		},
+	}
+
+	for _, bf := range batchFiles {
+		content, err := ioutil.ReadFile(bf.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(content) != bf.expectedContent {
+			t.Errorf("Got batch file: %v - expected batch file: %v", string(content), bf.expectedContent)
+		}
+	}
+}
+
+func TestReportCrOSDevsFakeServer(t *testing.T) {
+	now := time.Now().UTC()
+	daysAgo := func(days int) string {
+		return now.AddDate(0, 0, -days).Format(time.RFC3339)
+	}
+	monthsAhead := func(months int) int64 {
+		return now.AddDate(0, months, 0).UnixNano() / int64(time.Millisecond)
+	}
+
+	fs := newFakeServer(t)
+	fs.AddCrOSDevice(&admin.ChromeOsDevice{DeviceId: "cros1", AutoUpdateExpiration: monthsAhead(3), LastSync: daysAgo(1), Model: "Chromebook A", OrgUnitPath: "/", OsVersion: "86.0", Status: "ACTIVE"})
+	fs.AddCrOSDevice(&admin.ChromeOsDevice{DeviceId: "cros2", AutoUpdateExpiration: monthsAhead(36), LastSync: daysAgo(60), Model: "Chromebook A", OrgUnitPath: "/Sales", OsVersion: "86.0", Status: "ACTIVE"})
+	fs.AddCrOSDevice(&admin.ChromeOsDevice{DeviceId: "cros3", AutoUpdateExpiration: monthsAhead(36), LastSync: daysAgo(2), Model: "Chromebook B", OrgUnitPath: "/Sales", OsVersion: "85.0", Status: "ACTIVE"})
+
+	dir := t.TempDir()
+	deprovisionFile := filepath.Join(dir, "deprovision.csv")
+	moveFile := filepath.Join(dir, "move.csv")
+
+	const fleetCounts = "1,aue,within 6 months\n" +
+		"2,aue,over 24 months\n" +
+		"2,last-sync,within 7 days\n" +
+		"1,last-sync,within 90 days\n" +
+		"2,model,Chromebook A\n" +
+		"1,model,Chromebook B\n" +
+		"2,orgunit,/Sales\n" +
+		"1,orgunit,/\n" +
+		"2,os-version,86.0\n" +
+		"1,os-version,85.0\n" +
+		"3,recent-user,none\n" +
+		"3,status,ACTIVE\n"
+
+	cases := []struct {
+		args        []string
+		expectedErr string
+		expectedOut string
+	}{
+		{
+			args:        []string{"report", "chromeos-devices", "--output", "csv"},
+			expectedOut: "devices,dimension,value\n1,check,aue\n1,check,no-sync\n" + fleetCounts,
+		},
+		{
+			args:        []string{"report", "chromeos-devices", "--checks", "aue", "--batch-file", deprovisionFile, "--batch-action", "deprovision", "-r", "retiring_device", "--output", "csv"},
+			expectedOut: "devices,dimension,value\n1,check,aue\n" + fleetCounts,
+		},
+		{
+			args: []string{"report", "chromeos-devices", "-t", "/Sales", "--checks", "no-sync", "--batch-file", moveFile, "--batch-action", "move", "-o", "/Retiring", "--output", "csv"},
+			expectedOut: "devices,dimension,value\n" +
+				"1,check,no-sync\n" +
+				"2,aue,over 24 months\n" +
+				"1,last-sync,within 7 days\n" +
+				"1,last-sync,within 90 days\n" +
+				"1,model,Chromebook A\n" +
+				"1,model,Chromebook B\n" +
+				"2,orgunit,/Sales\n" +
+				"1,os-version,85.0\n" +
+				"1,os-version,86.0\n" +
+				"2,recent-user,none\n" +
+				"2,status,ACTIVE\n",
+		},
+		{
+			args:        []string{"report", "chromeos-devices", "--checks", "battery"},
+			expectedErr: "invalid fleet check: battery - valid checks are: aue, no-sync",
+		},
+		{
+			args:        []string{"report", "chromeos-devices", "--batch-action", "wipe"},
+			expectedErr: "invalid batch action: wipe - valid actions are: deprovision, disable, move",
+		},
+		{
+			args:        []string{"report", "chromeos-devices", "--batch-action", "deprovision"},
+			expectedErr: "must provide a deprovision reason",
+		},
+		{
+			args:        []string{"report", "chromeos-devices", "--batch-action", "deprovision", "-r", "lost"},
+			expectedErr: "invalid deprovision reason: lost",
+		},
+		{
+			args:        []string{"report", "chromeos-devices", "--batch-action", "move"},
+			expectedErr: "must provide an orgunit to move devices to",
+		},
+		{
+			args:        []string{"report", "chromeos-devices", "--sync-days", "-1"},
+			expectedErr: "sync-days must not be negative: -1",
+		},
+	}
+
+	for _, c := range cases {
+		out, got := runGmin(t, c.args...)
+
+		gotErr := ""
+		if got != nil {
+			gotErr = got.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("%v - got error: %v - expected error: %v", c.args, gotErr, c.expectedErr)
+		}
+		if out != c.expectedOut {
+			t.Errorf("%v - got output: %v - expected output: %v", c.args, out, c.expectedOut)
+		}
+	}
+
+	batchFiles := []struct {
+		expectedContent string
+		path            string
+	}{
+		{
+			expectedContent: "deviceId,action,deprovisionReason\ncros1,deprovision,retiring_device\n",
+			path:            deprovisionFile,
+		},
+		{
+			expectedContent: "deviceId,orgUnitPath\ncros2,/Retiring\n",
+			path:            moveFile,
+		},
+	}
+
+	for _, bf := range batchFiles {
+		content, err := ioutil.ReadFile(bf.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(content) != bf.expectedContent {
+			t.Errorf("Got batch file: %v - expected batch file: %v", string(content), bf.expectedContent)
+		}
+	}
+}
+
+func TestRcdBatchInput(t *testing.T) {
+	devices := []*admin.ChromeOsDevice{
+		{DeviceId: "cros1", Status: "ACTIVE"},
+		{DeviceId: "cros2", Status: "DISABLED"},
+		{DeviceId: "cros3", Status: "DEPROVISIONED"},
+	}
+
+	cases := []struct {
+		action          string
+		actionVal       string
+		expectedContent string
+		expectedTotal   int
+	}{
+		{
+			action:          "disable",
+			expectedContent: "deviceId,action\ncros1,disable\n",
+			expectedTotal:   1,
+		},
+		{
+			action:          "deprovision",
+			actionVal:       "retiring_device",
+			expectedContent: "deviceId,action,deprovisionReason\ncros1,deprovision,retiring_device\ncros2,deprovision,retiring_device\n",
+			expectedTotal:   2,
+		},
+		{
+			action:          "move",
+			actionVal:       "/Retiring",
+			expectedContent: "deviceId,orgUnitPath\ncros1,/Retiring\ncros2,/Retiring\n",
+			expectedTotal:   2,
+		},
+	}
+
+	lg.InitLogging("info")
+
+	for _, c := range cases {
+		content, total, err := rcdBatchInput(c.action, c.actionVal, devices)
+		if err != nil {
+			t.Fatalf("Got error: %v - expected error: nil", err)
+		}
+		if string(content) != c.expectedContent {
+			t.Errorf("%v - got batch file: %v - expected batch file: %v", c.action, string(content), c.expectedContent)
+		}
+		if total != c.expectedTotal {
+			t.Errorf("%v - got total: %v - expected total: %v", c.action, total, c.expectedTotal)
+		}
+	}
+}
+
+func TestReportActivitiesFakeServer(t *testing.T) {
+	now := time.Now().UTC()
+	hoursAgo := func(hours int) string {
+		return now.Add(time.Duration(-hours) * time.Hour).Format(time.RFC3339)
+	}
+
+	fs := newFakeServer(t)
+	fs.AddActivity(&reports.Activity{Actor: &reports.ActivityActor{Email: "mickey.mouse@disney.com"}, Events: []*reports.ActivityEvents{{Name: "login_success"}}, Id: &reports.ActivityId{ApplicationName: "login", Time: hoursAgo(48)}})
+	fs.AddActivity(&reports.Activity{Actor: &reports.ActivityActor{Email: "minnie.mouse@disney.com"}, Events: []*reports.ActivityEvents{{Name: "login_failure"}}, Id: &reports.ActivityId{ApplicationName: "login", Time: hoursAgo(240)}})
+	fs.AddActivity(&reports.Activity{Actor: &reports.ActivityActor{Email: "admin@disney.com"}, Events: []*reports.ActivityEvents{{Name: "CREATE_USER"}}, Id: &reports.ActivityId{ApplicationName: "admin", Time: hoursAgo(24)}})
+	fs.AddActivity(&reports.Activity{Actor: &reports.ActivityActor{Email: "mickey.mouse@disney.com"}, Events: []*reports.ActivityEvents{{Name: "logout"}}, Id: &reports.ActivityId{ApplicationName: "login", Time: hoursAgo(1)}})
+
+	cases := []struct {
+		args        []string
+		expectedErr string
+		expectedOut string
+	}{
+		{
+			args: []string{"report", "activities", "login", "-a", "id(time)~actor(email)~events(name)", "--output", "csv"},
+			expectedOut: "id.time,actor.email,events.0.name\n" +
+				hoursAgo(1) + ",mickey.mouse@disney.com,logout\n" +
+				hoursAgo(48) + ",mickey.mouse@disney.com,login_success\n" +
+				hoursAgo(240) + ",minnie.mouse@disney.com,login_failure\n",
+		},
+		{
+			args: []string{"report", "activities", "login", "-u", "mickey.mouse@disney.com", "--start-time", "72h", "-a", "id(time)~events(name)", "--output", "csv"},
+			expectedOut: "id.time,events.0.name\n" +
+				hoursAgo(1) + ",logout\n" +
+				hoursAgo(48) + ",login_success\n",
+		},
+		{
+			args:        []string{"report", "activities", "login", "--start-time", "3d", "--end-time", now.Add(-12 * time.Hour).Format(time.RFC3339), "-a", "id(time)~events(name)", "--output", "csv"},
+			expectedOut: "id.time,events.0.name\n" + hoursAgo(48) + ",login_success\n",
+		},
+		{
+			args:        []string{"report", "activities", "login", "-e", "login_failure", "-a", "actor(email)", "--output", "jsonl"},
+			expectedOut: `{"actor":{"email":"minnie.mouse@disney.com"}}` + "\n",
+		},
+		{
+			args:        []string{"report", "activities", "login", "-a", "actor(email)", "--where", "actor.email ~ 'minnie'", "--output", "jsonl"},
+			expectedOut: `{"actor":{"email":"minnie.mouse@disney.com"}}` + "\n",
+		},
+		{
+			args:        []string{"report", "activities", "login", "-m", "1", "-p", "all", "--count"},
+			expectedOut: "3\n",
+		},
+		{
+			args:        []string{"report", "activities", "gmail"},
+			expectedErr: "invalid application: gmail - valid applications are: access_transparency, admin, calendar, chat, chrome, context_aware_access, data_studio, drive, gcp, gplus, groups, groups_enterprise, jamboard, login, meet, mobile, rules, saml, token, user_accounts",
+		},
+		{
+			args:        []string{"report", "activities", "login", "--start-time", "yesterday"},
+			expectedErr: "invalid report time: yesterday - must be an RFC 3339 time or a duration before now like 7d",
+		},
+	}
+
+	for _, c := range cases {
+		out, got := runGmin(t, c.args...)
+
+		gotErr := ""
+		if got != nil {
+			gotErr = got.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("%v - got error: %v - expected error: %v", c.args, gotErr, c.expectedErr)
+		}
+		if out != c.expectedOut {
+			t.Errorf("%v - got output: %v - expected output: %v", c.args, out, c.expectedOut)
+		}
+	}
+}
+
+func TestReportUsageFakeServer(t *testing.T) {
+	fs := newFakeServer(t)
+	fs.AddUsageReport(&reports.UsageReport{
+		Date:   "2020-10-12",
+		Entity: &reports.UsageReportEntity{Type: "USER", UserEmail: "mickey.mouse@disney.com"},
+		Parameters: []*reports.UsageReportParameters{
+			{Name: "accounts:last_login_time", DatetimeValue: "2020-10-12T09:00:00.000Z"},
+			{Name: "gmail:num_emails_sent", IntValue: 5},
+		},
+	})
+	fs.AddUsageReport(&reports.UsageReport{
+		Date:       "2020-10-12",
+		Entity:     &reports.UsageReportEntity{Type: "USER", UserEmail: "minnie.mouse@disney.com"},
+		Parameters: []*reports.UsageReportParameters{{Name: "gmail:num_emails_sent", IntValue: 150}},
+	})
+	fs.AddUsageReport(&reports.UsageReport{
+		Date:       "2020-10-11",
+		Entity:     &reports.UsageReportEntity{Type: "USER", UserEmail: "mickey.mouse@disney.com"},
+		Parameters: []*reports.UsageReportParameters{{Name: "gmail:num_emails_sent", IntValue: 2}},
+	})
+	fs.AddUsageReport(&reports.UsageReport{
+		Date:       "2020-10-12",
+		Entity:     &reports.UsageReportEntity{Type: "CUSTOMER"},
+		Parameters: []*reports.UsageReportParameters{{Name: "accounts:num_users", IntValue: 2}},
+	})
+
+	cases := []struct {
+		args        []string
+		expectedErr string
+		expectedOut string
+	}{
+		{
+			args: []string{"report", "user-usage", "-d", "2020-10-12", "--parameters", "gmail:num_emails_sent", "-a", "entity(userEmail)~parameters(name,intValue)", "--output", "csv"},
+			expectedOut: "entity.userEmail,parameters.0.name,parameters.0.intValue\n" +
+				"mickey.mouse@disney.com,gmail:num_emails_sent,5\n" +
+				"minnie.mouse@disney.com,gmail:num_emails_sent,150\n",
+		},
+		{
+			args:        []string{"report", "user-usage", "-d", "2020-10-12", "-u", "mickey.mouse@disney.com", "--parameters", "accounts:last_login_time~gmail:num_emails_sent", "-a", "parameters(name)", "--output", "jsonl"},
+			expectedOut: `{"parameters":[{"name":"accounts:last_login_time"},{"name":"gmail:num_emails_sent"}]}` + "\n",
+		},
+		{
+			args:        []string{"report", "user-usage", "-d", "2020-10-12", "-m", "1", "-p", "all", "--count"},
+			expectedOut: "2\n",
+		},
+		{
+			args:        []string{"report", "customer-usage", "-d", "2020-10-12", "-a", "date~parameters(name,intValue)", "--output", "jsonl"},
+			expectedOut: `{"date":"2020-10-12","parameters":[{"intValue":"2","name":"accounts:num_users"}]}` + "\n",
+		},
+		{
+			args:        []string{"report", "user-usage"},
+			expectedErr: "must provide a report date",
+		},
+		{
+			args:        []string{"report", "customer-usage", "-d", "12/10/2020"},
+			expectedErr: "invalid report date: 12/10/2020 - must be in the format YYYY-MM-DD",
+		},
+	}
+
+	for _, c := range cases {
+		out, got := runGmin(t, c.args...)
+
+		gotErr := ""
+		if got != nil {
+			gotErr = got.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("%v - got error: %v - expected error: %v", c.args, gotErr, c.expectedErr)
+		}
+		if out != c.expectedOut {
+			t.Errorf("%v - got output: %v - expected output: %v", c.args, out, c.expectedOut)
+		}
+	}
+}
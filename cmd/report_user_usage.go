@@ -0,0 +1,224 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"errors"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	gpars "github.com/plusworx/gmin/utils/gminparsers"
+	lg "github.com/plusworx/gmin/utils/logging"
+	rpts "github.com/plusworx/gmin/utils/reports"
+	"github.com/spf13/cobra"
+	reports "google.golang.org/api/admin/reports/v1"
+)
+
+var reportUserUsageCmd = &cobra.Command{
+	Use:     "user-usage",
+	Aliases: []string{"user-usage-reports", "usr-usage", "uusage"},
+	Args:    cobra.NoArgs,
+	Example: `gmin report user-usage -d 2020-10-12 --pages all --output csv
+gmin rpt uusage -d 2020-10-12 -u mickey.mouse@disney.com --parameters accounts:last_login_time~gmail:num_emails_sent
+gmin rpt uusage -d 2020-10-12 --filters "gmail:num_emails_sent>100" -a entity(userEmail)~parameters`,
+	Short: "Outputs usage reports for users",
+	Long: `Outputs usage reports for users from the Admin Reports API.
+
+--date is required and is the day that the usage reports are for in the format YYYY-MM-DD. Usage data
+usually takes a few days to become available. --parameters selects the usage parameters that are returned
+and --filters selects users by usage parameter value. Both are passed to the API as they are, except that
+~ separates parameters.
+
+JSONL, CSV and TSV output and counts are written as each page of results arrives. If listing stops with
+an error, --page-token resumes it from the page given in the error message.`,
+	RunE: doReportUserUsage,
+}
+
+func doReportUserUsage(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doReportUserUsage()",
+		"args", args)
+	defer lg.Debug("finished doReportUserUsage()")
+
+	outputFmt, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	date, err := reportUsageDate(cmd)
+	if err != nil {
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEREPORT, reports.AdminReportsUsageReadonlyScope)
+	if err != nil {
+		return err
+	}
+	rs := srv.(*reports.Service)
+
+	uugc, listAttrs, err := ruuGetCall(cmd, rs, date)
+	if err != nil {
+		return err
+	}
+
+	usageReports := new(reports.UsageReports)
+	pl := pagedList{
+		fields:  listAttrs,
+		listKey: rpts.USAGELISTKEY,
+		merge:   reportUsageMerge(usageReports),
+		result:  usageReports,
+		size:    reportUsageSize,
+	}
+
+	return listPages(cmd, outputFmt, pl, ruuFetch(uugc), nil, nil)
+}
+
+// reportUsageDate gets the usage report date from command flags
+func reportUsageDate(cmd *cobra.Command) (string, error) {
+	lg.Debug("starting reportUsageDate()")
+	defer lg.Debug("finished reportUsageDate()")
+
+	flgDateVal, err := cmd.Flags().GetString(flgnm.FLG_DATE)
+	if err != nil {
+		lg.Error(err)
+		return "", err
+	}
+	if flgDateVal == "" {
+		err = errors.New(gmess.ERR_NOREPORTDATE)
+		lg.Error(err)
+		return "", err
+	}
+	err = rpts.ValidateReportDate(flgDateVal)
+	if err != nil {
+		return "", err
+	}
+	return flgDateVal, nil
+}
+
+// reportUsageMerge returns a function that adds the usage reports in a page to usageReports
+func reportUsageMerge(usageReports *reports.UsageReports) func(page interface{}) {
+	return func(page interface{}) {
+		pgUsageReports := page.(*reports.UsageReports)
+		usageReports.UsageReports = append(usageReports.UsageReports, pgUsageReports.UsageReports...)
+		usageReports.Etag = pgUsageReports.Etag
+		usageReports.Kind = pgUsageReports.Kind
+		usageReports.NextPageToken = pgUsageReports.NextPageToken
+		usageReports.Warnings = append(usageReports.Warnings, pgUsageReports.Warnings...)
+	}
+}
+
+// reportUsageSize returns the number of usage reports in a page
+func reportUsageSize(page interface{}) int {
+	return len(page.(*reports.UsageReports).UsageReports)
+}
+
+// ruuFetch returns a function that gets a page of user usage reports
+func ruuFetch(uugc *reports.UserUsageReportGetCall) cmn.PageFunc {
+	return func(pageToken string) (interface{}, string, error) {
+		if pageToken != "" {
+			uugc = rpts.AddPageToken(uugc, pageToken).(*reports.UserUsageReportGetCall)
+		}
+		usageReports, err := rpts.DoUserUsageGet(uugc)
+		if err != nil {
+			return nil, "", err
+		}
+		return usageReports, usageReports.NextPageToken, nil
+	}
+}
+
+// ruuGetCall sets up a user usage report get call for date from command flags
+func ruuGetCall(cmd *cobra.Command, rs *reports.Service, date string) (*reports.UserUsageReportGetCall, string, error) {
+	lg.Debugw("starting ruuGetCall()",
+		"date", date)
+	defer lg.Debug("finished ruuGetCall()")
+
+	var listAttrs string
+
+	flgUserKeyVal, err := cmd.Flags().GetString(flgnm.FLG_USERKEY)
+	if err != nil {
+		lg.Error(err)
+		return nil, "", err
+	}
+	if flgUserKeyVal == "" {
+		flgUserKeyVal = rpts.ALLUSERS
+	}
+
+	uugc := rs.UserUsageReport.Get(flgUserKeyVal, date)
+
+	flgAttrsVal, err := cmd.Flags().GetString(flgnm.FLG_ATTRIBUTES)
+	if err != nil {
+		lg.Error(err)
+		return nil, "", err
+	}
+	if flgAttrsVal != "" {
+		listAttrs, err = gpars.ParseOutputAttrs(flgAttrsVal, rpts.UsageAttrMap)
+		if err != nil {
+			return nil, "", err
+		}
+		formattedAttrs := "nextPageToken," + rpts.STARTUSAGEREPORTSFIELD + listAttrs + rpts.ENDFIELD
+		uugc = rpts.AddFields(uugc, formattedAttrs).(*reports.UserUsageReportGetCall)
+	}
+
+	flgFiltersVal, err := cmd.Flags().GetString(flgnm.FLG_FILTERS)
+	if err != nil {
+		lg.Error(err)
+		return nil, "", err
+	}
+	if flgFiltersVal != "" {
+		uugc = rpts.AddFilters(uugc, flgFiltersVal).(*reports.UserUsageReportGetCall)
+	}
+
+	flgParametersVal, err := cmd.Flags().GetString(flgnm.FLG_PARAMETERS)
+	if err != nil {
+		lg.Error(err)
+		return nil, "", err
+	}
+	if flgParametersVal != "" {
+		uugc = rpts.AddParameters(uugc, rpts.FormatParameters(flgParametersVal)).(*reports.UserUsageReportGetCall)
+	}
+
+	flgMaxResultsVal, err := cmd.Flags().GetInt64(flgnm.FLG_MAXRESULTS)
+	if err != nil {
+		lg.Error(err)
+		return nil, "", err
+	}
+	uugc = rpts.AddMaxResults(uugc, flgMaxResultsVal).(*reports.UserUsageReportGetCall)
+
+	return uugc, listAttrs, nil
+}
+
+func init() {
+	reportCmd.AddCommand(reportUserUsageCmd)
+
+	reportUserUsageCmd.Flags().StringVarP(&attrs, flgnm.FLG_ATTRIBUTES, "a", "", "required usage report attributes (separated by ~)")
+	reportUserUsageCmd.Flags().BoolVarP(&count, flgnm.FLG_COUNT, "", false, "count number of entities returned")
+	reportUserUsageCmd.Flags().StringVarP(&reportDate, flgnm.FLG_DATE, "d", "", "date of usage reports (YYYY-MM-DD)")
+	reportUserUsageCmd.Flags().StringVar(&filters, flgnm.FLG_FILTERS, "", "usage parameter filters (separated by ,)")
+	reportUserUsageCmd.Flags().Int64VarP(&maxResults, flgnm.FLG_MAXRESULTS, "m", 1000, "maximum number of results to return per page")
+	reportUserUsageCmd.Flags().StringVar(&pageToken, flgnm.FLG_PAGETOKEN, "", "page token to resume listing from")
+	reportUserUsageCmd.Flags().StringVarP(&pages, flgnm.FLG_PAGES, "p", "", "number of pages of results to be returned ('all' or a number)")
+	reportUserUsageCmd.Flags().StringVar(&parameters, flgnm.FLG_PARAMETERS, "", "usage parameters to return (separated by ~)")
+	reportUserUsageCmd.Flags().StringVar(&sortBy, flgnm.FLG_SORTBY, "", "attributes to sort results by (separated by ~)")
+	reportUserUsageCmd.Flags().StringVarP(&userKey, flgnm.FLG_USERKEY, "u", "", "email address or id of user whose usage is returned (default all)")
+	reportUserUsageCmd.Flags().StringVar(&where, flgnm.FLG_WHERE, "", "expression that results must match")
+}
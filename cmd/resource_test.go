@@ -0,0 +1,188 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+func TestResourceCmdsFakeServer(t *testing.T) {
+	fs := newFakeServer(t)
+	fs.AddFeature(&admin.Feature{Name: "Whiteboard"})
+
+	dir := t.TempDir()
+	bldgsCSV := filepath.Join(dir, "buildings.csv")
+	ioutil.WriteFile(bldgsCSV, []byte("buildingId,buildingName,floorNames,locality,latitude\nannex,Annex,1~2,London,51.5\n"), 0644)
+	calResCSV := filepath.Join(dir, "calres.csv")
+	ioutil.WriteFile(calResCSV, []byte("resourceId,resourceName,buildingId,capacity,featureInstances\nroom2,Room 2,annex,4,Whiteboard\nroom3,Room 3,hq,20,\n"), 0644)
+	calResUpdCSV := filepath.Join(dir, "calresupd.csv")
+	ioutil.WriteFile(calResUpdCSV, []byte("resourceId,floorName\nroom1,\n"), 0644)
+	featsJSON := filepath.Join(dir, "features.json")
+	ioutil.WriteFile(featsJSON, []byte("{\"name\":\"Projector\"}\n"), 0644)
+
+	cases := []struct {
+		args        []string
+		expectedErr string
+	}{
+		{
+			args: []string{"create", "building", "hq", "-n", "Head Office", "--floor-names", "G~1~2"},
+		},
+		{
+			args:        []string{"create", "building", "empty", "-n", "Empty", "--floor-names", "~"},
+			expectedErr: "--floor-names cannot be empty string",
+		},
+		{
+			args: []string{"update", "building", "hq", "-d", "Main office"},
+		},
+		{
+			args: []string{"create", "feature", "VC"},
+		},
+		{
+			args: []string{"update", "feature", "VC", "-n", "Video Conferencing"},
+		},
+		{
+			args: []string{"batch-create", "features", "-i", featsJSON, "--results-dir", t.TempDir()},
+		},
+		{
+			args: []string{"create", "calendar-resource", "room1", "-n", "Room 1", "-b", "hq", "-c", "10", "--floor-name", "1", "--features", "Whiteboard~Video Conferencing"},
+		},
+		{
+			args:        []string{"create", "calendar-resource", "room9", "-n", "Room 9", "-b", "missing"},
+			expectedErr: "googleapi: Error 400: Invalid building: missing, invalid",
+		},
+		{
+			args: []string{"batch-create", "buildings", "-i", bldgsCSV, "-f", "csv", "--results-dir", t.TempDir()},
+		},
+		{
+			args: []string{"batch-create", "calendar-resources", "-i", calResCSV, "-f", "csv", "--results-dir", t.TempDir()},
+		},
+		{
+			args: []string{"update", "calendar-resource", "room1", "-c", "12", "-u", "Big room"},
+		},
+		{
+			args: []string{"batch-update", "calendar-resources", "-i", calResUpdCSV, "-f", "csv", "--results-dir", t.TempDir()},
+		},
+	}
+
+	for _, c := range cases {
+		_, got := runGmin(t, c.args...)
+
+		gotErr := ""
+		if got != nil {
+			gotErr = got.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("Got error: %v - expected error: %v", gotErr, c.expectedErr)
+		}
+	}
+
+	hq := fs.Buildings["hq"]
+	if hq == nil || hq.Description != "Main office" || len(hq.FloorNames) != 3 {
+		t.Errorf("Got building: %v - expected building: hq with description Main office and 3 floors", hq)
+	}
+	annex := fs.Buildings["annex"]
+	if annex == nil || annex.Address == nil || annex.Address.Locality != "London" || annex.Coordinates == nil || annex.Coordinates.Latitude != 51.5 {
+		t.Errorf("Got building: %v - expected building: annex in London at latitude 51.5", annex)
+	}
+	if fs.Features["Video Conferencing"] == nil || fs.Features["VC"] != nil || fs.Features["Projector"] == nil {
+		t.Errorf("Got features: %v - expected features: Projector, Video Conferencing and Whiteboard", fs.Features)
+	}
+
+	room1 := fs.CalResources["room1"]
+	if room1 == nil || room1.Capacity != 12 || room1.UserVisibleDescription != "Big room" || room1.FloorName != "" {
+		t.Errorf("Got calendar resource: %v - expected calendar resource: room1 with capacity 12 and no floor name", room1)
+	}
+	if len(fs.CalResources) != 3 {
+		t.Errorf("Got calendar resources: %v - expected calendar resources: 3", len(fs.CalResources))
+	}
+
+	out, err := runGmin(t, "list", "calendar-resources", "-q", "buildingid=hq~capacity>=15", "-a", "resourceid", "--output", "csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "resourceId\nroom3\n" {
+		t.Errorf("Got output: %q - expected output: %q", out, "resourceId\nroom3\n")
+	}
+
+	_, err = runGmin(t, "list", "calendar-resources", "-o", "email")
+	if err == nil || err.Error() != "invalid order by field: email" {
+		t.Errorf("Got error: %v - expected error: invalid order by field: email", err)
+	}
+
+	out, err = runGmin(t, "list", "buildings", "--count")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(out) != "2" {
+		t.Errorf("Got count: %v - expected count: 2", strings.TrimSpace(out))
+	}
+
+	out, err = runGmin(t, "list", "features", "-a", "name", "--output", "csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "name\nProjector\nVideo Conferencing\nWhiteboard\n" {
+		t.Errorf("Got output: %q - expected output: %q", out, "name\nProjector\nVideo Conferencing\nWhiteboard\n")
+	}
+
+	out, err = runGmin(t, "get", "calendar-resource", "room2", "-a", "resourcename~featureinstances(feature(name))")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "Room 2") || !strings.Contains(out, "Whiteboard") {
+		t.Errorf("Got output: %v - expected output containing: Room 2 and Whiteboard", out)
+	}
+
+	out, err = runGmin(t, "get", "building", "annex", "-a", "address(locality)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "London") || strings.Contains(out, "Annex") {
+		t.Errorf("Got output: %v - expected output containing only: London", out)
+	}
+
+	_, err = runGmin(t, "delete", "building", "annex")
+	if err == nil {
+		t.Error("Got error: nil - expected error deleting building containing resources")
+	}
+
+	delCases := [][]string{
+		{"delete", "calendar-resource", "room2"},
+		{"delete", "building", "annex"},
+		{"delete", "feature", "Projector"},
+	}
+	for _, args := range delCases {
+		if _, err := runGmin(t, args...); err != nil {
+			t.Errorf("Got error: %v - expected error: nil", err)
+		}
+	}
+
+	if fs.CalResources["room2"] != nil || fs.Buildings["annex"] != nil || fs.Features["Projector"] != nil {
+		t.Error("Got deleted objects still present - expected room2, annex and Projector to be deleted")
+	}
+}
@@ -0,0 +1,311 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	aply "github.com/plusworx/gmin/utils/apply"
+	cmn "github.com/plusworx/gmin/utils/common"
+	cfg "github.com/plusworx/gmin/utils/config"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	grps "github.com/plusworx/gmin/utils/groups"
+	grpset "github.com/plusworx/gmin/utils/groupsettings"
+	lg "github.com/plusworx/gmin/utils/logging"
+	mems "github.com/plusworx/gmin/utils/members"
+	ous "github.com/plusworx/gmin/utils/orgunits"
+	snap "github.com/plusworx/gmin/utils/snapshot"
+	usrs "github.com/plusworx/gmin/utils/users"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+	gset "google.golang.org/api/groupssettings/v1"
+)
+
+var restoreCmd = &cobra.Command{
+	Use:  "restore -d <snapshot directory> | -i <list output file>",
+	Args: cobra.NoArgs,
+	Example: `gmin restore -d gmin_export_20201201100000 --dry-run
+gmin restore -d gmin_export_20201201100000 --types members~group-settings --group sales@mycompany.org
+gmin restore -d gmin_export_20201201100000 --orgunit /Sales
+gmin restore -i users.json --types users --orgunit /Sales`,
+	Short: "Restores orgunits, groups, members, aliases, group settings and users from a snapshot",
+	Long: `Restores orgunits, groups, members, aliases, group settings and users from a snapshot directory written
+by the export command, or orgunits, groups, users and their aliases from a file of JSON output from list
+orgunits, list groups or list users. List output should be taken without --attributes because user
+attributes that it leaves out, such as suspended, are restored to their default values.
+
+The snapshot is compared with the tenant to produce a plan which is shown and then carried out. Orgunits,
+groups, group members and aliases that are missing from the tenant are recreated, and changed orgunit,
+group, group settings, member and user attributes are reverted to their snapshot values. Objects created
+since the snapshot are left alone and deleted users are not recreated (use undelete user). Use the global
+--dry-run flag to show the plan without carrying it out.
+
+Object types that can be restored are aliases, group-settings, groups, members, orgunits and users. The
+--types flag restricts the restore to some of these types, --group restricts it to a group and its
+settings, members and aliases, and --orgunit restricts it to orgunits and users at or below an orgunit path
+and the aliases of those users.`,
+	RunE: doRestore,
+}
+
+func doRestore(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doRestore()",
+		"args", args)
+	defer lg.Debug("finished doRestore()")
+
+	var filter snap.Filter
+
+	flgDirVal, err := cmd.Flags().GetString(flgnm.FLG_DIR)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	flgInFileVal, err := cmd.Flags().GetString(flgnm.FLG_INPUTFILE)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	if flgDirVal == "" && flgInFileVal == "" {
+		err = errors.New(gmess.ERR_NORESTORESOURCE)
+		lg.Error(err)
+		return err
+	}
+	if flgDirVal != "" && flgInFileVal != "" {
+		err = errors.New(gmess.ERR_DIRANDINPUTFILE)
+		lg.Error(err)
+		return err
+	}
+
+	filter.Group, err = cmd.Flags().GetString(flgnm.FLG_GROUP)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	filter.OrgUnit, err = cmd.Flags().GetString(flgnm.FLG_ORGUNIT)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	if filter.Group != "" && filter.OrgUnit != "" {
+		err = errors.New(gmess.ERR_GROUPANDORGUNITFLAGS)
+		lg.Error(err)
+		return err
+	}
+
+	flgTypesVal, err := cmd.Flags().GetString(flgnm.FLG_TYPES)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgTypesVal != "" {
+		for _, objType := range strings.Split(flgTypesVal, "~") {
+			objType = strings.ToLower(strings.TrimSpace(objType))
+			if !cmn.SliceContainsStr(snap.RestoreTypes, objType) {
+				err = fmt.Errorf(gmess.ERR_INVALIDOBJECTTYPE, objType)
+				lg.Error(err)
+				return err
+			}
+			filter.Types = append(filter.Types, objType)
+		}
+	}
+
+	flgQPSVal, err := cmd.Flags().GetFloat64(flgnm.FLG_QPS)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgQPSVal < 0 {
+		err = fmt.Errorf(gmess.ERR_INVALIDQPS, flgQPSVal)
+		lg.Error(err)
+		return err
+	}
+
+	var snapshot *snap.Snapshot
+	if flgInFileVal != "" {
+		snapshot, err = snap.ReadList(flgInFileVal, filter)
+	} else {
+		snapshot, err = snap.Read(flgDirVal, filter)
+	}
+	if err != nil {
+		return err
+	}
+
+	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
+	if err != nil {
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryGroupScope, admin.AdminDirectoryOrgunitScope,
+		admin.AdminDirectoryUserScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	srv, err = cmn.CreateService(cmn.SRVTYPEGRPSETTING, gset.AppsGroupsSettingsScope)
+	if err != nil {
+		return err
+	}
+	gss := srv.(*gset.Service)
+
+	current, err := restoreCurrent(ds, gss, customerID, snapshot)
+	if err != nil {
+		return err
+	}
+
+	plan, err := aply.RestorePlan(snapshot, current)
+	if err != nil {
+		return err
+	}
+
+	return applyPlan(ds, gss, customerID, plan, flgQPSVal)
+}
+
+// restoreCurrent gets the tenant objects that are compared with snapshot objects
+func restoreCurrent(ds *admin.Service, gss *gset.Service, customerID string, snapshot *snap.Snapshot) (*aply.Current, error) {
+	lg.Debug("starting restoreCurrent()")
+	defer lg.Debug("finished restoreCurrent()")
+
+	var (
+		current    = aply.NewCurrent()
+		getGrps    bool
+		getUsers   = len(snapshot.Users) > 0
+		memGrps    = map[string]bool{}
+		settingsOf = map[string]bool{}
+	)
+
+	for _, alias := range snapshot.Aliases {
+		getGrps = getGrps || alias.Type == snap.ALIASTYPEGROUP
+		getUsers = getUsers || alias.Type == snap.ALIASTYPEUSER
+	}
+	for _, settings := range snapshot.GroupSettings {
+		settingsOf[strings.ToLower(settings.Email)] = true
+	}
+	for _, mem := range snapshot.Members {
+		memGrps[strings.ToLower(mem.Group)] = true
+	}
+	getGrps = getGrps || len(snapshot.Groups) > 0 || len(settingsOf) > 0 || len(memGrps) > 0
+
+	if len(snapshot.OrgUnits) > 0 {
+		oulc := ds.Orgunits.List(customerID)
+		oulc = ous.AddType(oulc, "all")
+		orgunits, err := ous.DoList(oulc)
+		if err != nil {
+			return nil, err
+		}
+		for _, ou := range orgunits.OrganizationUnits {
+			current.OrgUnits[ou.OrgUnitPath] = ou
+		}
+	}
+
+	if getGrps {
+		glc := ds.Groups.List()
+		glc = grps.AddCustomer(glc, customerID)
+		for {
+			groups, err := grps.DoList(glc)
+			if err != nil {
+				return nil, err
+			}
+			for _, group := range groups.Groups {
+				current.Groups[strings.ToLower(group.Email)] = group
+			}
+			if groups.NextPageToken == "" {
+				break
+			}
+			glc = grps.AddPageToken(glc, groups.NextPageToken)
+		}
+	}
+
+	for email, group := range current.Groups {
+		if settingsOf[email] {
+			gsgc := gss.Groups.Get(group.Email)
+			settings, err := grpset.DoGet(gsgc)
+			if err != nil {
+				return nil, err
+			}
+			current.GroupSettings[email] = settings
+		}
+
+		if memGrps[email] {
+			current.Members[email] = map[string]*admin.Member{}
+
+			mlc := ds.Members.List(group.Email)
+			for {
+				members, err := mems.DoList(mlc)
+				if err != nil {
+					return nil, err
+				}
+				for _, member := range members.Members {
+					current.Members[email][strings.ToLower(member.Email)] = member
+				}
+				if members.NextPageToken == "" {
+					break
+				}
+				mlc = mems.AddPageToken(mlc, members.NextPageToken)
+			}
+		}
+	}
+
+	if getUsers {
+		ulc := ds.Users.List()
+		ulc = usrs.AddCustomer(ulc, customerID)
+		listCall := usrs.AddProjection(ulc, "full")
+		ulc = listCall.(*admin.UsersListCall)
+		for {
+			users, err := usrs.DoList(ulc)
+			if err != nil {
+				return nil, err
+			}
+			for _, user := range users.Users {
+				current.Users[strings.ToLower(user.PrimaryEmail)] = user
+			}
+			if users.NextPageToken == "" {
+				break
+			}
+			ulc = usrs.AddPageToken(ulc, users.NextPageToken)
+		}
+	}
+
+	return current, nil
+}
+
+func init() {
+	rootCmd.AddCommand(restoreCmd)
+
+	restoreCmd.Flags().StringVarP(&snapshotDir, flgnm.FLG_DIR, "d", "", "snapshot directory path")
+	restoreCmd.Flags().StringVarP(&groupEmail, flgnm.FLG_GROUP, "g", "", "restore group with this email address")
+	restoreCmd.Flags().StringVarP(&inputFile, flgnm.FLG_INPUTFILE, "i", "", "filepath to json output of list users, groups or orgunits")
+	restoreCmd.Flags().StringVar(&logLevel, flgnm.FLG_LOGLEVEL, "info", "log level (debug, info, error, warn)")
+	restoreCmd.Flags().StringVarP(&orgUnit, flgnm.FLG_ORGUNIT, "o", "", "restore orgunits and users at or below this orgunit path")
+	restoreCmd.Flags().Float64Var(&qps, flgnm.FLG_QPS, 0, "maximum API calls started per second (default is based on API quota)")
+	restoreCmd.Flags().BoolVar(&silent, flgnm.FLG_SILENT, false, "suppress console output")
+	restoreCmd.Flags().StringVarP(&objTypes, flgnm.FLG_TYPES, "t", "", "object types to restore (separated by ~)")
+
+	restoreCmd.PreRunE = preRun
+}
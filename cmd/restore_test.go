@@ -0,0 +1,179 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+func TestRestoreFakeServer(t *testing.T) {
+	fs := newFakeServer(t)
+	fs.AddOrgUnit(&admin.OrgUnit{Name: "Characters", Description: "Cartoon characters"})
+	fs.AddOrgUnit(&admin.OrgUnit{Name: "Villains", Description: "Cartoon villains"})
+	fs.AddUser(&admin.User{PrimaryEmail: "mickey.mouse@disney.com", Aliases: []string{"mickey@disney.com"}, Name: &admin.UserName{GivenName: "Mickey", FamilyName: "Mouse"}, OrgUnitPath: "/Characters"})
+	fs.AddUser(&admin.User{PrimaryEmail: "donald.duck@disney.com", Name: &admin.UserName{GivenName: "Donald", FamilyName: "Duck"}})
+	fs.AddGroup(&admin.Group{Email: "cartoons@disney.com", Name: "Cartoons", Aliases: []string{"toons@disney.com"}})
+	fs.AddMember("cartoons@disney.com", &admin.Member{Email: "mickey.mouse@disney.com", Role: "OWNER"})
+	fs.AddMember("cartoons@disney.com", &admin.Member{Email: "donald.duck@disney.com", Role: "MEMBER"})
+
+	dir := filepath.Join(t.TempDir(), "export")
+	_, err := runGmin(t, "export", "-d", dir)
+	if err != nil {
+		t.Fatalf("Got error: %v - expected error: nil", err)
+	}
+
+	usersFile := filepath.Join(t.TempDir(), "users.json")
+	out, err := runGmin(t, "list", "users", "-p", "all")
+	if err != nil {
+		t.Fatalf("Got error: %v - expected error: nil", err)
+	}
+	ioutil.WriteFile(usersFile, []byte(out), 0644)
+
+	// Changes made after the snapshot
+	delete(fs.OrgUnits, "/Villains")
+	delete(fs.Members["cartoons@disney.com"], "donald.duck@disney.com")
+	fs.Member("cartoons@disney.com", "mickey.mouse@disney.com").Role = "MEMBER"
+	fs.GroupSettings["cartoons@disney.com"].WhoCanJoin = "ALL_IN_DOMAIN_CAN_JOIN"
+	fs.User("mickey.mouse@disney.com").Suspended = true
+	fs.User("mickey.mouse@disney.com").Aliases = nil
+	fs.User("donald.duck@disney.com").Name.GivenName = "Donny"
+
+	cases := []struct {
+		args        []string
+		expectedErr string
+		expectedOut []string
+		dryRun      bool
+	}{
+		{
+			args:        []string{"restore", "-d", dir, "--group", "cartoons@disney.com", "--orgunit", "/Characters"},
+			expectedErr: "cannot provide both --group and --orgunit flags",
+		},
+		{
+			args:        []string{"restore", "-d", dir, "--types", "members~widgets"},
+			expectedErr: "invalid object type: widgets",
+		},
+		{
+			args: []string{"restore", "-d", dir, "--dry-run"},
+			expectedOut: []string{
+				"plan: 3 to create, 4 to update, 0 to delete",
+				"+ create orgunit /Villains\n    + description: \"Cartoon villains\"\n    + name: \"Villains\"\n    + parentOrgUnitPath: \"/\"",
+				"~ update group-settings cartoons@disney.com\n    ~ whoCanJoin: \"ALL_IN_DOMAIN_CAN_JOIN\" -> \"CAN_REQUEST_TO_JOIN\"",
+				"~ update group-member mickey.mouse@disney.com in group cartoons@disney.com\n    ~ role: \"MEMBER\" -> \"OWNER\"",
+				"+ create group-member donald.duck@disney.com in group cartoons@disney.com",
+				"+ create user-alias mickey@disney.com for mickey.mouse@disney.com",
+				"~ update user mickey.mouse@disney.com\n    ~ suspended: true -> false",
+				"~ update user donald.duck@disney.com\n    ~ name.givenName: \"Donny\" -> \"Donald\"",
+			},
+			dryRun: true,
+		},
+		{
+			args:        []string{"restore", "--group", "cartoons@disney.com"},
+			expectedErr: "must provide a snapshot directory (--dir) or list output file (--input-file)",
+		},
+		{
+			args:        []string{"restore", "-d", dir, "-i", usersFile},
+			expectedErr: "cannot provide both --dir and --input-file flags",
+		},
+		{
+			args: []string{"restore", "-i", usersFile, "--dry-run"},
+			expectedOut: []string{
+				"plan: 1 to create, 2 to update, 0 to delete",
+				"+ create user-alias mickey@disney.com for mickey.mouse@disney.com",
+				"~ update user mickey.mouse@disney.com\n    ~ suspended: true -> false",
+				"~ update user donald.duck@disney.com\n    ~ name.givenName: \"Donny\" -> \"Donald\"",
+			},
+			dryRun: true,
+		},
+		{
+			args:        []string{"restore", "-i", usersFile, "--orgunit", "/Characters", "--types", "users", "--dry-run"},
+			expectedOut: []string{"plan: 0 to create, 1 to update, 0 to delete"},
+			dryRun:      true,
+		},
+		{
+			args:        []string{"restore", "-d", dir, "--orgunit", "/Characters", "--dry-run"},
+			expectedOut: []string{"plan: 1 to create, 1 to update, 0 to delete"},
+			dryRun:      true,
+		},
+		{
+			args:        []string{"restore", "-d", dir, "--types", "members", "--qps", "100"},
+			expectedOut: []string{"plan: 1 to create, 1 to update, 0 to delete"},
+		},
+		{
+			args:        []string{"restore", "-d", dir, "--qps", "100"},
+			expectedOut: []string{"plan: 2 to create, 3 to update, 0 to delete"},
+		},
+		{
+			args:        []string{"restore", "-d", dir},
+			expectedOut: []string{"no changes needed"},
+		},
+	}
+
+	for _, c := range cases {
+		fs.Requests = nil
+
+		out, got := runGmin(t, c.args...)
+
+		gotErr := ""
+		if got != nil {
+			gotErr = got.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("Got error: %v - expected error: %v", gotErr, c.expectedErr)
+		}
+
+		for _, exp := range c.expectedOut {
+			if !strings.Contains(out, exp) {
+				t.Errorf("Got output: %v - expected output to contain: %v", out, exp)
+			}
+		}
+
+		if c.dryRun {
+			for _, req := range fs.Requests {
+				if !strings.HasPrefix(req, "GET ") {
+					t.Errorf("Got request: %v - expected only GET requests", req)
+				}
+			}
+		}
+	}
+
+	if ou := fs.OrgUnits["/Villains"]; ou == nil || ou.Description != "Cartoon villains" {
+		t.Errorf("Got orgunit: %v - expected orgunit: /Villains", ou)
+	}
+	if mem := fs.Member("cartoons@disney.com", "donald.duck@disney.com"); mem == nil || mem.Role != "MEMBER" {
+		t.Errorf("Got member: %v - expected member: donald.duck@disney.com with role MEMBER", mem)
+	}
+	if fs.GroupSettings["cartoons@disney.com"].WhoCanJoin != "CAN_REQUEST_TO_JOIN" {
+		t.Errorf("Got whoCanJoin: %v - expected whoCanJoin: CAN_REQUEST_TO_JOIN", fs.GroupSettings["cartoons@disney.com"].WhoCanJoin)
+	}
+	if user := fs.User("mickey.mouse@disney.com"); user.Suspended || len(user.Aliases) != 1 {
+		t.Errorf("Got user: %+v - expected user: not suspended with alias mickey@disney.com", user)
+	}
+	if fs.User("donald.duck@disney.com").Name.GivenName != "Donald" {
+		t.Errorf("Got given name: %v - expected given name: Donald", fs.User("donald.duck@disney.com").Name.GivenName)
+	}
+}
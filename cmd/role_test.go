@@ -0,0 +1,174 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+func TestRoleCmdsFakeServer(t *testing.T) {
+	fs := newFakeServer(t)
+	fs.AddUser(&admin.User{PrimaryEmail: "mickey.mouse@disney.com", Name: &admin.UserName{GivenName: "Mickey", FamilyName: "Mouse"}})
+	fs.AddUser(&admin.User{PrimaryEmail: "donald.duck@disney.com", Name: &admin.UserName{GivenName: "Donald", FamilyName: "Duck"}})
+	fs.AddOrgUnit(&admin.OrgUnit{Name: "Sales", OrgUnitPath: "/Sales", ParentOrgUnitPath: "/"})
+	fs.AddPrivilege(&admin.Privilege{PrivilegeName: "USERS_RETRIEVE", ServiceId: "00haapch16h1ysv", ServiceName: "admin_directory"})
+	fs.AddPrivilege(&admin.Privilege{PrivilegeName: "USERS_UPDATE", ServiceId: "00haapch16h1ysv", ServiceName: "admin_directory"})
+
+	dir := t.TempDir()
+	rolesCSV := filepath.Join(dir, "roles.csv")
+	ioutil.WriteFile(rolesCSV, []byte("roleName,roleDescription,rolePrivileges\nUser Viewer,Looks up users,users_retrieve\n"), 0644)
+	rasJSON := filepath.Join(dir, "ras.json")
+	ioutil.WriteFile(rasJSON, []byte("{\"assignedTo\":\"donald.duck@disney.com\",\"roleKey\":\"User Viewer\",\"orgUnit\":\"/Sales\"}\n"), 0644)
+	delRoles := filepath.Join(dir, "roles.txt")
+	ioutil.WriteFile(delRoles, []byte("User Viewer\n"), 0644)
+
+	cases := []struct {
+		args        []string
+		expectedErr string
+	}{
+		{
+			args: []string{"create", "role", "Helpdesk Admin", "-d", "Password resets", "-p", "users_retrieve~USERS_UPDATE"},
+		},
+		{
+			args:        []string{"create", "role", "Bad Role", "-p", "GROUPS_ALL"},
+			expectedErr: "privilege not found: GROUPS_ALL",
+		},
+		{
+			args: []string{"update", "role", "helpdesk admin", "-d", "Password resets and user lookups"},
+		},
+		{
+			args: []string{"create", "role-assignment", "mickey.mouse@disney.com", "-r", "Helpdesk Admin"},
+		},
+		{
+			args:        []string{"create", "role-assignment", "mickey.mouse@disney.com", "-r", "Missing Role"},
+			expectedErr: "role not found: Missing Role",
+		},
+		{
+			args: []string{"batch-create", "roles", "-i", rolesCSV, "-f", "csv", "--results-dir", t.TempDir()},
+		},
+		{
+			args: []string{"batch-create", "role-assignments", "-i", rasJSON, "--results-dir", t.TempDir()},
+		},
+	}
+
+	for _, c := range cases {
+		_, got := runGmin(t, c.args...)
+
+		gotErr := ""
+		if got != nil {
+			gotErr = got.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("Got error: %v - expected error: %v", gotErr, c.expectedErr)
+		}
+	}
+
+	role := fs.Role("Helpdesk Admin")
+	if role == nil {
+		t.Fatal("Got role: nil - expected role: Helpdesk Admin")
+	}
+	if role.RoleDescription != "Password resets and user lookups" || len(role.RolePrivileges) != 2 {
+		t.Errorf("Got role: %v %v - expected role: Password resets and user lookups 2", role.RoleDescription, len(role.RolePrivileges))
+	}
+	if role.RolePrivileges[0].ServiceId != "00haapch16h1ysv" {
+		t.Errorf("Got service id: %v - expected service id: 00haapch16h1ysv", role.RolePrivileges[0].ServiceId)
+	}
+	if fs.Role("User Viewer") == nil {
+		t.Error("Got role: nil - expected role: User Viewer")
+	}
+
+	out, err := runGmin(t, "list", "privileges", "-a", "privilegename")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "USERS_UPDATE") {
+		t.Errorf("Got output: %v - expected output containing: USERS_UPDATE", out)
+	}
+
+	out, err = runGmin(t, "list", "roles", "--count")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(out) != "2" {
+		t.Errorf("Got count: %v - expected count: 2", strings.TrimSpace(out))
+	}
+
+	out, err = runGmin(t, "get", "role", strconv.FormatInt(role.RoleId, 10), "-a", "rolename")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "Helpdesk Admin") {
+		t.Errorf("Got output: %v - expected output containing: Helpdesk Admin", out)
+	}
+
+	var mickeyRA, donaldRA *admin.RoleAssignment
+	for _, ra := range fs.RoleAssignments {
+		switch ra.AssignedTo {
+		case fs.User("mickey.mouse@disney.com").Id:
+			mickeyRA = ra
+		case fs.User("donald.duck@disney.com").Id:
+			donaldRA = ra
+		}
+	}
+	if mickeyRA == nil || mickeyRA.ScopeType != "CUSTOMER" || mickeyRA.RoleId != role.RoleId {
+		t.Fatalf("Got role assignment: %v - expected CUSTOMER role assignment for: mickey.mouse@disney.com", mickeyRA)
+	}
+	if donaldRA == nil || donaldRA.ScopeType != "ORG_UNIT" || "id:"+donaldRA.OrgUnitId != fs.OrgUnits["/Sales"].OrgUnitId {
+		t.Fatalf("Got role assignment: %v - expected ORG_UNIT role assignment for: donald.duck@disney.com", donaldRA)
+	}
+
+	out, err = runGmin(t, "list", "role-assignments", "-u", "mickey.mouse@disney.com", "-a", "roleassignmentid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mickeyRAID := strconv.FormatInt(mickeyRA.RoleAssignmentId, 10)
+	donaldRAID := strconv.FormatInt(donaldRA.RoleAssignmentId, 10)
+	if !strings.Contains(out, mickeyRAID) || strings.Contains(out, donaldRAID) {
+		t.Errorf("Got output: %v - expected output containing only: %v", out, mickeyRAID)
+	}
+
+	delRAs := filepath.Join(dir, "ras.txt")
+	ioutil.WriteFile(delRAs, []byte(donaldRAID+"\n"), 0644)
+
+	delCases := [][]string{
+		{"delete", "role-assignment", mickeyRAID},
+		{"delete", "role", "Helpdesk Admin"},
+		{"batch-delete", "role-assignments", "-i", delRAs, "--results-dir", t.TempDir()},
+		{"batch-delete", "roles", "-i", delRoles, "--results-dir", t.TempDir()},
+	}
+	for _, args := range delCases {
+		if _, err := runGmin(t, args...); err != nil {
+			t.Errorf("Got error: %v - expected error: nil", err)
+		}
+	}
+
+	if len(fs.Roles) != 0 || len(fs.RoleAssignments) != 0 {
+		t.Errorf("Got roles and role assignments: %v %v - expected roles and role assignments: 0 0", len(fs.Roles), len(fs.RoleAssignments))
+	}
+}
@@ -23,105 +23,176 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/mitchellh/go-homedir"
+	btch "github.com/plusworx/gmin/utils/batch"
 	cmn "github.com/plusworx/gmin/utils/common"
 	cfg "github.com/plusworx/gmin/utils/config"
+	doms "github.com/plusworx/gmin/utils/domains"
 	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	fmtrs "github.com/plusworx/gmin/utils/formatters"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
 	lg "github.com/plusworx/gmin/utils/logging"
+	ous "github.com/plusworx/gmin/utils/orgunits"
+	pwds "github.com/plusworx/gmin/utils/passwords"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	admin "google.golang.org/api/admin/directory/v1"
+	"google.golang.org/api/googleapi"
 )
 
 var (
-	adminEmail       string
-	approveMems      string
-	archiveOnly      bool
-	assetID          string
-	assistContent    string
-	attrs            string
-	archived         bool
-	banUser          string
-	blockInherit     bool
-	cfgFile          string
-	collabInbox      bool
-	contactOwner     string
-	credentialPath   string
-	changePassword   bool
-	composite        bool
-	count            bool
-	customerID       string
-	customField      string
-	deleted          bool
-	delFormat        string
-	deliverySetting  string
-	denyNotification bool
-	denyText         string
-	discoverGroup    string
-	domain           string
-	extMems          bool
-	filter           string
-	firstName        string
-	footerText       string
-	forceSend        string
-	format           string
-	gal              bool
-	group            string
-	groupDesc        string
-	groupEmail       string
-	groupName        string
-	incFooter        bool
-	inputFile        string
-	isArchived       bool
-	join             string
-	language         string
-	lastName         string
-	leave            string
-	location         string
-	logLevel         string
-	logPath          string
-	logRotationCount uint
-	logRotationTime  int
-	maxResults       int64
-	messageMod       string
-	modContent       string
-	modMems          string
-	notes            string
-	orderBy          string
-	orgUnit          string
-	orgUnitDesc      string
-	orgUnitName      string
-	pages            string
-	parentOUPath     string
-	password         string
-	postAsGroup      bool
-	postMessage      string
-	projection       string
-	query            string
-	queryable        bool
-	sheetRange       string
-	reason           string
-	recoveryEmail    string
-	recoveryPhone    string
-	repliesOnTop     bool
-	replyEmail       string
-	replyTo          string
-	role             string
-	searchType       string
-	silent           bool
-	sortOrder        string
-	spamMod          string
-	suspended        bool
-	userEmail        string
-	userKey          string
-	viewGroup        string
-	viewMems         string
-	viewType         string
-	webPosting       bool
+	adminEmail        string
+	allowEmpty        bool
+	approveMems       string
+	archiveOnly       bool
+	assetID           string
+	assistContent     string
+	attrs             string
+	archived          bool
+	aueMonths         int
+	banUser           string
+	batchAction       string
+	batchFile         string
+	blockInherit      bool
+	buildingID        string
+	capacity          int64
+	cfgFile           string
+	checks            string
+	clientID          string
+	collabInbox       bool
+	contactOwner      string
+	credentialFile    string
+	credentialPath    string
+	changePassword    bool
+	composite         bool
+	count             bool
+	customerID        string
+	customField       string
+	deleted           bool
+	delFormat         string
+	deliverySetting   string
+	denyNotification  bool
+	denyText          string
+	deviceAction      string
+	discoverGroup     string
+	domain            string
+	dryRun            bool
+	endpoint          string
+	endTime           string
+	eventName         string
+	extMems           bool
+	features          string
+	filter            string
+	filters           string
+	firstName         string
+	floorName         string
+	floorNames        string
+	floorSection      string
+	footerText        string
+	forceSend         string
+	format            string
+	gal               bool
+	group             string
+	groupDesc         string
+	groupEmail        string
+	groupName         string
+	handoffFile       string
+	hasher            string
+	inactiveDays      int
+	incFooter         bool
+	inputFile         string
+	isArchived        bool
+	join              string
+	language          string
+	lastName          string
+	leave             string
+	location          string
+	logLevel          string
+	logPath           string
+	logRotationCount  uint
+	logRotationTime   int
+	maxResults        int64
+	messageMod        string
+	modContent        string
+	modMems           string
+	neverLoggedInDays int
+	notes             string
+	objTypes          string
+	orderBy           string
+	orgUnit           string
+	orgUnitDesc       string
+	orgUnitName       string
+	output            string
+	pages             string
+	pageToken         string
+	parallel          int
+	parameters        string
+	parentOUPath      string
+	password          string
+	photoDir          string
+	photoFile         string
+	postAsGroup       bool
+	postMessage       string
+	privileges        string
+	profile           string
+	projection        string
+	protectOwners     bool
+	prune             bool
+	pwdLength         int
+	qps               float64
+	query             string
+	queryable         bool
+	recursive         bool
+	reportDate        string
+	resourceCategory  string
+	resourceDesc      string
+	resourceName      string
+	resourceType      string
+	sheetRange        string
+	reason            string
+	restart           bool
+	recoveryEmail     string
+	recoveryPhone     string
+	repliesOnTop      bool
+	replyEmail        string
+	replyTo           string
+	resultsDir        string
+	resultsFormat     string
+	role              string
+	roleDesc          string
+	roleName          string
+	searchType        string
+	silent            bool
+	snapshotDir       string
+	sortBy            string
+	sortOrder         string
+	spamMod           string
+	startTime         string
+	stateFile         string
+	suspended         bool
+	suspendedDays     int
+	syncDays          int
+	targetOrgUnit     string
+	templateFile      string
+	templateName      string
+	userDesc          string
+	userEmail         string
+	userKey           string
+	viewGroup         string
+	viewMems          string
+	viewType          string
+	webPosting        bool
+	where             string
+	workers           int
+	workflowFile      string
 )
 
 var rootCmd = &cobra.Command{
@@ -163,10 +234,415 @@ func getLogLevel(cmd *cobra.Command) (string, error) {
 	return logFlgVal, nil
 }
 
+func getOutputFormat(cmd *cobra.Command) (string, error) {
+	outputFlgVal, err := cmd.Flags().GetString(flgnm.FLG_OUTPUT)
+	if err != nil {
+		lg.Error(err)
+		return "", err
+	}
+
+	lwrFmt := strings.ToLower(outputFlgVal)
+	ok := cmn.SliceContainsStr(cmn.ValidOutputFormats, lwrFmt)
+	if !ok {
+		err = fmt.Errorf(gmess.ERR_INVALIDOUTPUTFORMAT, outputFlgVal)
+		lg.Error(err)
+		return "", err
+	}
+	return lwrFmt, nil
+}
+
+// pagedList describes the pages of results returned by a list command
+type pagedList struct {
+	// fields is the formatted attribute string that selects output columns
+	fields string
+	// listKey is the JSON name of the slice that holds the results in each page
+	listKey string
+	// merge adds the results in a page to result
+	merge func(page interface{})
+	// result holds the merged results of output formats that can't be streamed
+	result interface{}
+	// size returns the number of results in a page
+	size func(page interface{}) int
+}
+
+// listPages fetches the pages of a list command and writes them to standard output. The pages flag sets
+// how many pages are fetched and the page-token flag resumes from a given page. When shards are given
+// all pages of every shard are fetched, with up to --parallel shards at a time. Counts and JSONL, CSV and
+// TSV output are written as each page arrives and other formats once all of the pages have been fetched.
+func listPages(cmd *cobra.Command, outputFmt string, pl pagedList, fetch cmn.PageFunc, shards []string, shardFetch func(shard string) cmn.PageFunc) error {
+	lg.Debugw("starting listPages()",
+		"outputFmt", outputFmt,
+		"shards", shards)
+	defer lg.Debug("finished listPages()")
+
+	var (
+		handle  cmn.PageHandler
+		results int
+		strmr   *fmtrs.Streamer
+	)
+
+	flgCountVal, err := cmd.Flags().GetBool(flgnm.FLG_COUNT)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	flgPagesVal, err := cmd.Flags().GetString(flgnm.FLG_PAGES)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	flgPageTokenVal, err := cmd.Flags().GetString(flgnm.FLG_PAGETOKEN)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	pgntr := &cmn.Paginator{MaxPages: 1, PageToken: flgPageTokenVal}
+	pgntr.Progress = func(nextPageToken string) {
+		lg.Infof(gmess.INFO_NEXTPAGETOKEN, nextPageToken)
+	}
+	switch flgPagesVal {
+	case "":
+	case "all":
+		pgntr.MaxPages = 0
+	default:
+		numPages, err := strconv.Atoi(flgPagesVal)
+		if err != nil {
+			err = errors.New(gmess.ERR_INVALIDPAGESARGUMENT)
+			lg.Error(err)
+			return err
+		}
+		if numPages > 1 {
+			pgntr.MaxPages = numPages
+		}
+	}
+
+	if shards != nil {
+		if flgPageTokenVal != "" {
+			err = fmt.Errorf(gmess.ERR_PARALLELFLAG, flgnm.FLG_PAGETOKEN)
+			lg.Error(err)
+			return err
+		}
+		if flgPagesVal != "" && flgPagesVal != "all" {
+			err = fmt.Errorf(gmess.ERR_PARALLELFLAG, flgnm.FLG_PAGES)
+			lg.Error(err)
+			return err
+		}
+	}
+
+	whereExpr, sortKeys, err := listSelection(cmd)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case flgCountVal:
+		handle = func(page interface{}) error {
+			results += pl.size(page)
+			return nil
+		}
+	// Sorted results can only be written once every page has arrived
+	case fmtrs.CanStream(outputFmt) && len(sortKeys) == 0:
+		strmr, err = fmtrs.NewStreamer(os.Stdout, outputFmt, pl.listKey, pl.fields)
+		if err != nil {
+			return err
+		}
+		handle = strmr.WritePage
+	default:
+		handle = func(page interface{}) error {
+			pl.merge(page)
+			return nil
+		}
+	}
+
+	if whereExpr != nil {
+		pageHandle := handle
+		handle = func(page interface{}) error {
+			err := fmtrs.FilterList(page, pl.listKey, whereExpr, nil)
+			if err != nil {
+				return err
+			}
+			return pageHandle(page)
+		}
+	}
+
+	if shards != nil {
+		flgParallelVal, err := cmd.Flags().GetInt(flgnm.FLG_PARALLEL)
+		if err != nil {
+			lg.Error(err)
+			return err
+		}
+		err = cmn.FanOut(shards, flgParallelVal, shardFetch, handle)
+		if err != nil {
+			return err
+		}
+	} else {
+		err = pgntr.Run(fetch, handle)
+		if err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case flgCountVal:
+		fmt.Println(results)
+	case strmr == nil:
+		err = fmtrs.FilterList(pl.result, pl.listKey, nil, sortKeys)
+		if err != nil {
+			return err
+		}
+		return fmtrs.Output(os.Stdout, outputFmt, pl.result, pl.listKey, pl.fields)
+	default:
+		return strmr.Close()
+	}
+	return nil
+}
+
+func newBatchPool(cmd *cobra.Command, apiQPS float64) (*btch.Pool, error) {
+	qpsFlgVal, err := cmd.Flags().GetFloat64(flgnm.FLG_QPS)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+	if qpsFlgVal < 0 {
+		err = fmt.Errorf(gmess.ERR_INVALIDQPS, qpsFlgVal)
+		lg.Error(err)
+		return nil, err
+	}
+	if qpsFlgVal == 0 {
+		qpsFlgVal = apiQPS
+	}
+
+	workersFlgVal, err := cmd.Flags().GetInt(flgnm.FLG_WORKERS)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+	if workersFlgVal < 1 {
+		err = fmt.Errorf(gmess.ERR_INVALIDWORKERS, workersFlgVal)
+		lg.Error(err)
+		return nil, err
+	}
+
+	return btch.NewPool(workersFlgVal, qpsFlgVal), nil
+}
+
+func newBatchReport(cmd *cobra.Command, input *btch.Input) (*btch.Report, error) {
+	dirFlgVal, err := cmd.Flags().GetString(flgnm.FLG_RESULTSDIR)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	fmtFlgVal, err := cmd.Flags().GetString(flgnm.FLG_RESULTSFORMAT)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+	lwrFmt := strings.ToLower(fmtFlgVal)
+	ok := cmn.SliceContainsStr(cmn.ValidResultsFormats, lwrFmt)
+	if !ok {
+		err = fmt.Errorf(gmess.ERR_INVALIDFILEFORMAT, fmtFlgVal)
+		lg.Error(err)
+		return nil, err
+	}
+
+	return btch.NewReport(input, dirFlgVal, lwrFmt), nil
+}
+
+// listDomain returns the domain flag value or, if the flag isn't given, the domain of the active profile
+func listDomain(cmd *cobra.Command) (string, error) {
+	lg.Debug("starting listDomain()")
+	defer lg.Debug("finished listDomain()")
+
+	flgDomainVal, err := cmd.Flags().GetString(flgnm.FLG_DOMAIN)
+	if err != nil {
+		lg.Error(err)
+		return "", err
+	}
+	if flgDomainVal == "" && cfg.ActiveProfile() != "" {
+		flgDomainVal = cfg.GetString(cfg.CONFIGDOMAIN)
+	}
+	return flgDomainVal, nil
+}
+
+// parallelDomains returns the domains to list in parallel when the parallel flag is set and no domain
+// has been given
+func parallelDomains(cmd *cobra.Command) ([]string, error) {
+	lg.Debug("starting parallelDomains()")
+	defer lg.Debug("finished parallelDomains()")
+
+	flgParallelVal, err := cmd.Flags().GetInt(flgnm.FLG_PARALLEL)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+	flgDomainVal, err := listDomain(cmd)
+	if err != nil {
+		return nil, err
+	}
+	if flgParallelVal < 1 || flgDomainVal != "" {
+		return nil, nil
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryDomainReadonlyScope)
+	if err != nil {
+		return nil, err
+	}
+	ds := srv.(*admin.Service)
+
+	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
+	if err != nil {
+		return nil, err
+	}
+
+	domains, err := doms.DoList(ds.Domains.List(customerID))
+	if err != nil {
+		return nil, err
+	}
+
+	shards := []string{}
+	for _, domain := range domains.Domains {
+		shards = append(shards, domain.DomainName)
+	}
+	return shards, nil
+}
+
+// parallelOrgUnits returns the orgunit paths to list in parallel when the parallel flag is set and no
+// orgunit has been given
+func parallelOrgUnits(cmd *cobra.Command) ([]string, error) {
+	lg.Debug("starting parallelOrgUnits()")
+	defer lg.Debug("finished parallelOrgUnits()")
+
+	flgParallelVal, err := cmd.Flags().GetInt(flgnm.FLG_PARALLEL)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+	flgOUVal, err := cmd.Flags().GetString(flgnm.FLG_ORGUNITPATH)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+	if flgParallelVal < 1 || flgOUVal != "" {
+		return nil, nil
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryOrgunitReadonlyScope)
+	if err != nil {
+		return nil, err
+	}
+	ds := srv.(*admin.Service)
+
+	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
+	if err != nil {
+		return nil, err
+	}
+
+	oulc := ous.AddType(ds.Orgunits.List(customerID), "all")
+	oulc = ous.AddFields(oulc, "organizationUnits(orgUnitPath)").(*admin.OrgunitsListCall)
+	orgunits, err := ous.DoList(oulc)
+	if err != nil {
+		return nil, err
+	}
+
+	shards := []string{"/"}
+	for _, ou := range orgunits.OrganizationUnits {
+		shards = append(shards, ou.OrgUnitPath)
+	}
+	return shards, nil
+}
+
+// passwordHasher returns the hasher named by the hasher flag, the hasher config value or the default hasher
+func passwordHasher(cmd *cobra.Command) (pwds.Hasher, error) {
+	flgHasherVal, err := cmd.Flags().GetString(flgnm.FLG_HASHER)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+	if flgHasherVal == "" {
+		flgHasherVal = cfg.GetString(cfg.CONFIGHASHER)
+	}
+	return pwds.NewHasher(flgHasherVal)
+}
+
+// listDomainError returns a domain not found error in place of a failed list error when the domain flag
+// isn't one of the customer's domains. The domain is only checked after a bad request or not found error
+// so that lists don't need the domain read scope and the domain of the active profile isn't checked.
+func listDomainError(cmd *cobra.Command, listErr error) error {
+	lg.Debug("starting listDomainError()")
+	defer lg.Debug("finished listDomainError()")
+
+	gErr, ok := listErr.(*googleapi.Error)
+	if !ok || (gErr.Code != http.StatusBadRequest && gErr.Code != http.StatusNotFound) {
+		return listErr
+	}
+
+	flgDomainVal, err := cmd.Flags().GetString(flgnm.FLG_DOMAIN)
+	if err != nil || flgDomainVal == "" {
+		return listErr
+	}
+
+	err = validateDomain(flgDomainVal)
+	if err != nil && err.Error() == fmt.Sprintf(gmess.ERR_DOMAINNOTFOUND, flgDomainVal) {
+		return err
+	}
+	return listErr
+}
+
+// validateDomain checks that a domain flag value is one of the customer's domains or domain aliases
+func validateDomain(domain string) error {
+	lg.Debugw("starting validateDomain()",
+		"domain", domain)
+	defer lg.Debug("finished validateDomain()")
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryDomainReadonlyScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
+	if err != nil {
+		return err
+	}
+
+	return doms.ValidateDomain(ds, customerID, domain)
+}
+
+func writeBatchReport(report *btch.Report) error {
+	resultsPath, err := report.WriteResults()
+	if err != nil {
+		return err
+	}
+	fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_BATCHRESULTS, resultsPath)))
+	lg.Infof(gmess.INFO_BATCHRESULTS, resultsPath)
+
+	failed := report.Failed()
+	if failed == 0 {
+		return nil
+	}
+
+	failedPath, err := report.WriteFailedRows()
+	if err != nil {
+		return err
+	}
+	fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_BATCHFAILEDROWS, failedPath)))
+	lg.Infof(gmess.INFO_BATCHFAILEDROWS, failedPath)
+
+	err = fmt.Errorf(gmess.ERR_BATCHROWSFAILED, failed, len(report.Results()))
+	lg.Error(err)
+	return err
+}
+
 func init() {
 	cobra.OnInitialize(initConfig)
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, flgnm.FLG_CONFIG, "", "config file (default is $HOME/.gmin.yaml)")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, flgnm.FLG_DRYRUN, false, "show changes that would be made without making them")
+	rootCmd.PersistentFlags().StringVar(&profile, flgnm.FLG_PROFILE, "", "config file profile to use (default is GMIN_PROFILE or profile set in config file)")
 }
 
 func initConfig() {
@@ -189,8 +665,14 @@ func initConfig() {
 }
 
 func preRun(cmd *cobra.Command, args []string) error {
+	// Set up profile before logging because it may change log path
+	err := setupProfile(cmd)
+	if err != nil {
+		return err
+	}
+
 	// Set up logging
-	err := setupLogging(cmd)
+	err = setupLogging(cmd)
 	if err != nil {
 		return err
 	}
@@ -204,6 +686,17 @@ func preRun(cmd *cobra.Command, args []string) error {
 		os.Stdout = nil
 		os.Stderr = nil
 	}
+	// Show changes rather than making them according to dry run flag
+	dryRunFlgVal, err := cmd.Flags().GetBool(flgnm.FLG_DRYRUN)
+	if err != nil {
+		return err
+	}
+	cmn.DryRun = dryRunFlgVal
+	// Journal changes so that they can be undone
+	err = setupJournal(cmd, args)
+	if err != nil {
+		return err
+	}
 	// Get gmin admin email address
 	admAddr, err := cfg.ReadConfigString(cfg.CONFIGADMIN)
 	if err != nil {
@@ -220,11 +713,23 @@ func preRun(cmd *cobra.Command, args []string) error {
 }
 
 func preRunForDisplayCmds(cmd *cobra.Command, args []string) error {
+	// Set up profile before logging because it may change log path
+	err := setupProfile(cmd)
+	if err != nil {
+		return err
+	}
+
 	// Set up logging
-	err := setupLogging(cmd)
+	err = setupLogging(cmd)
 	if err != nil {
 		return err
 	}
+	// Show changes rather than making them according to dry run flag
+	dryRunFlgVal, err := cmd.Flags().GetBool(flgnm.FLG_DRYRUN)
+	if err != nil {
+		return err
+	}
+	cmn.DryRun = dryRunFlgVal
 	// Get gmin admin email address
 	admAddr, err := cfg.ReadConfigString(cfg.CONFIGADMIN)
 	if err != nil {
@@ -240,6 +745,44 @@ func preRunForDisplayCmds(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func setupProfile(cmd *cobra.Command) error {
+	profileFlgVal, err := cmd.Flags().GetString(flgnm.FLG_PROFILE)
+	if err != nil {
+		return err
+	}
+	if profileFlgVal == "" {
+		// Uses GMIN_PROFILE environment variable if set, otherwise config file value
+		profileFlgVal = viper.GetString(cfg.CONFIGPROFILE)
+	}
+
+	err = cfg.UseProfile(profileFlgVal)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func setupJournal(cmd *cobra.Command, args []string) error {
+	cmn.RunJournal = nil
+	if cmn.DryRun {
+		return nil
+	}
+
+	journalDir, err := cmn.JournalDir()
+	if err != nil {
+		return err
+	}
+
+	// Flags aren't recorded because they may include passwords
+	cmn.RunJournal, err = cmn.NewJournal(journalDir, strings.TrimSpace(cmd.CommandPath()+" "+strings.Join(args, " ")))
+	if err != nil {
+		return err
+	}
+	lg.Infow("journal run ID",
+		"runID", cmn.RunJournal.RunID())
+	return nil
+}
+
 func setupLogging(cmd *cobra.Command) error {
 	// Set up logging
 	logFlgVal, err := getLogLevel(cmd)
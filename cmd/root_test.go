@@ -0,0 +1,131 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+func TestDryRunFakeServer(t *testing.T) {
+	fs := newFakeServer(t)
+	fs.AddUser(&admin.User{PrimaryEmail: "mickey.mouse@disney.com", Name: &admin.UserName{GivenName: "Mickey", FamilyName: "Mouse"}, Aliases: []string{"mickey@disney.com"}})
+	fs.AddUser(&admin.User{PrimaryEmail: "donald.duck@disney.com", Name: &admin.UserName{GivenName: "Donald", FamilyName: "Duck"}})
+	fs.AddGroup(&admin.Group{Email: "cartoons@disney.com", Name: "Cartoons"})
+	fs.AddOrgUnit(&admin.OrgUnit{Name: "Characters"})
+	fs.AddCrOSDevice(&admin.ChromeOsDevice{DeviceId: "cros1", OrgUnitPath: "/"})
+
+	dir := t.TempDir()
+	delUsers := filepath.Join(dir, "users.txt")
+	ioutil.WriteFile(delUsers, []byte("mickey.mouse@disney.com\ndonald.duck@disney.com\n"), 0644)
+
+	cases := []struct {
+		args        []string
+		expectedErr string
+		expectedOut []string
+	}{
+		{
+			args: []string{"update", "user", "mickey.mouse@disney.com", "-f", "Michael", "-s", "--dry-run"},
+			expectedOut: []string{
+				"gmin: [dry run] would update users/mickey.mouse@disney.com\n" +
+					"    + suspended: true\n" +
+					"    ~ name.givenName: \"Mickey\" -> \"Michael\"\n",
+				"gmin: [dry run] user updated: mickey.mouse@disney.com",
+			},
+		},
+		{
+			args:        []string{"update", "user", "minnie.mouse@disney.com", "-f", "Minerva", "--dry-run"},
+			expectedErr: "googleapi: Error 404: Resource Not Found: minnie.mouse@disney.com, notFound",
+		},
+		{
+			args:        []string{"batch-delete", "users", "-i", delUsers, "--results-dir", t.TempDir(), "--dry-run"},
+			expectedOut: []string{"would delete users/mickey.mouse@disney.com\n", "would delete users/donald.duck@disney.com\n"},
+		},
+		{
+			args: []string{"manage", "group-settings", "cartoons@disney.com", "--join", "all_in_domain_can_join", "--dry-run"},
+			expectedOut: []string{
+				"would update groups/cartoons@disney.com\n" +
+					"    ~ whoCanJoin: \"CAN_REQUEST_TO_JOIN\" -> \"ALL_IN_DOMAIN_CAN_JOIN\"\n",
+			},
+		},
+		{
+			args: []string{"delete", "user-alias", "mickey@disney.com", "mickey.mouse@disney.com", "--dry-run"},
+			expectedOut: []string{
+				"would delete users/mickey.mouse@disney.com/aliases/mickey@disney.com\n" +
+					"    ? current state unknown\n",
+			},
+		},
+		{
+			args: []string{"move", "chromeos-device", "cros1", "/Characters", "--dry-run"},
+			expectedOut: []string{
+				"would moveDevicesToOu customer/my_customer/devices/chromeos\n" +
+					"    + deviceIds: [\"cros1\"]\n" +
+					"    + orgUnitPath: \"/Characters\"\n",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		fs.Requests = nil
+
+		out, got := runGmin(t, c.args...)
+
+		gotErr := ""
+		if got != nil {
+			gotErr = got.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("Got error: %v - expected error: %v", gotErr, c.expectedErr)
+		}
+
+		for _, exp := range c.expectedOut {
+			if !strings.Contains(out, exp) {
+				t.Errorf("Got output: %v - expected output to contain: %v", out, exp)
+			}
+		}
+
+		for _, req := range fs.Requests {
+			if !strings.HasPrefix(req, "GET ") {
+				t.Errorf("Got request: %v - expected only GET requests", req)
+			}
+		}
+	}
+
+	user := fs.User("mickey.mouse@disney.com")
+	if user == nil || user.Name.GivenName != "Mickey" || user.Suspended {
+		t.Errorf("Got user: %v - expected user: unchanged", user)
+	}
+	if user != nil && len(user.Aliases) != 1 {
+		t.Errorf("Got aliases: %v - expected aliases: [mickey@disney.com]", user.Aliases)
+	}
+	if fs.User("donald.duck@disney.com") == nil {
+		t.Error("Got user: nil - expected user: donald.duck@disney.com")
+	}
+	if fs.GroupSettings["cartoons@disney.com"].WhoCanJoin != "CAN_REQUEST_TO_JOIN" {
+		t.Errorf("Got whoCanJoin: %v - expected whoCanJoin: CAN_REQUEST_TO_JOIN", fs.GroupSettings["cartoons@disney.com"].WhoCanJoin)
+	}
+}
@@ -30,8 +30,8 @@ import (
 var setCmd = &cobra.Command{
 	Use:   "set",
 	Args:  cobra.NoArgs,
-	Short: "Sets configuration values",
-	Long:  "Sets configuration values.",
+	Short: "Sets configuration values and user photos",
+	Long:  "Sets configuration values and user photos.",
 	Run:   doSet,
 }
 
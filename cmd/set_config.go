@@ -24,6 +24,7 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	valid "github.com/asaskevich/govalidator"
 	cmn "github.com/plusworx/gmin/utils/common"
@@ -31,6 +32,7 @@ import (
 	flgnm "github.com/plusworx/gmin/utils/flagnames"
 	gmess "github.com/plusworx/gmin/utils/gminmessages"
 	lg "github.com/plusworx/gmin/utils/logging"
+	pwds "github.com/plusworx/gmin/utils/passwords"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -106,6 +108,42 @@ func doSetConfig(cmd *cobra.Command, args []string) error {
 		lg.Infof(gmess.INFO_CUSTOMERIDSET, flgCustIDVal)
 	}
 
+	flgEndpointVal, err := cmd.Flags().GetString(flgnm.FLG_ENDPOINT)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgEndpointVal != "" {
+		viper.Set(cfg.CONFIGENDPOINT, flgEndpointVal)
+		err := viper.WriteConfig()
+		if err != nil {
+			lg.Error(err)
+			return err
+		}
+		fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_ENDPOINTSET, flgEndpointVal)))
+		lg.Infof(gmess.INFO_ENDPOINTSET, flgEndpointVal)
+	}
+
+	flgHasherVal, err := cmd.Flags().GetString(flgnm.FLG_HASHER)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgHasherVal != "" {
+		_, err = pwds.NewHasher(flgHasherVal)
+		if err != nil {
+			return err
+		}
+		viper.Set(cfg.CONFIGHASHER, strings.ToLower(flgHasherVal))
+		err := viper.WriteConfig()
+		if err != nil {
+			lg.Error(err)
+			return err
+		}
+		fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_HASHERSET, strings.ToLower(flgHasherVal))))
+		lg.Infof(gmess.INFO_HASHERSET, strings.ToLower(flgHasherVal))
+	}
+
 	flgLogPathVal, err := cmd.Flags().GetString(flgnm.FLG_LOGPATH)
 	if err != nil {
 		lg.Error(err)
@@ -154,7 +192,7 @@ func doSetConfig(cmd *cobra.Command, args []string) error {
 		lg.Infof(gmess.INFO_LOGROTATIONTIMESET, flgLogRotTimeVal)
 	}
 
-	if flgAdminVal == "" && flgCustIDVal == "" && flgCredPathVal == "" && flgLogPathVal == "" &&
+	if flgAdminVal == "" && flgCustIDVal == "" && flgCredPathVal == "" && flgEndpointVal == "" && flgHasherVal == "" && flgLogPathVal == "" &&
 		flgLogRotCountVal == 0 && flgLogRotTimeVal == 0 {
 		cmd.Help()
 	}
@@ -167,6 +205,8 @@ func init() {
 
 	setConfigCmd.Flags().StringVarP(&adminEmail, flgnm.FLG_ADMIN, "a", "", "administrator email address")
 	setConfigCmd.Flags().StringVarP(&customerID, flgnm.FLG_CUSTOMERID, "c", "", "customer id for domain")
+	setConfigCmd.Flags().StringVar(&endpoint, flgnm.FLG_ENDPOINT, "", "API endpoint override (used for testing)")
+	setConfigCmd.Flags().StringVar(&hasher, flgnm.FLG_HASHER, "", "password hasher (crypt, md5 or sha-1)")
 	setConfigCmd.Flags().StringVarP(&logPath, flgnm.FLG_LOGPATH, "l", "", "log file path")
 	setConfigCmd.Flags().UintVarP(&logRotationCount, flgnm.FLG_LOGROTATIONCOUNT, "r", 0, "max number of retained log files")
 	setConfigCmd.Flags().IntVarP(&logRotationTime, flgnm.FLG_LOGROTATIONTIME, "t", 0, "time after which new log file created")
@@ -0,0 +1,185 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	valid "github.com/asaskevich/govalidator"
+	cmn "github.com/plusworx/gmin/utils/common"
+	cfg "github.com/plusworx/gmin/utils/config"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var setProfileCmd = &cobra.Command{
+	Use:     "profile <profile name>",
+	Aliases: []string{"prof"},
+	Args:    cobra.ExactArgs(1),
+	Example: `gmin set profile acme
+gmin set profile acme --admin my.admin@acme.com --credential-file /home/me/creds/acme.json --customer-id C01abc2de --domain acme.com
+gmin set prof acme -a my.admin@acme.com -c C01abc2de`,
+	Short: "Sets default profile and profile information in config file",
+	Long: `Sets default profile and profile information in config file.
+
+Profiles hold administrator, credential file, customer ID, default domain and log path for a Google Workspace
+tenant. If any of these flags are provided then the profile is created or updated with the values given.
+
+The profile is then made the default profile that is used when the --profile flag and GMIN_PROFILE environment
+variable are not set. Profile values take precedence over other config file values and environment variables.
+
+N.B. Profile names are not case sensitive.`,
+	RunE: doSetProfile,
+}
+
+func doSetProfile(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doSetProfile()",
+		"args", args)
+	defer lg.Debug("finished doSetProfile()")
+
+	name := strings.ToLower(args[0])
+
+	profiles, err := cfg.Profiles()
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	profile, exists := profiles[name]
+
+	flgAdminVal, err := cmd.Flags().GetString(flgnm.FLG_ADMIN)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgAdminVal != "" {
+		ok := valid.IsEmail(flgAdminVal)
+		if !ok {
+			err = fmt.Errorf(gmess.ERR_INVALIDEMAILADDRESS, flgAdminVal)
+			lg.Error(err)
+			return err
+		}
+		profile.Administrator = flgAdminVal
+	}
+
+	flgCredFileVal, err := cmd.Flags().GetString(flgnm.FLG_CREDFILE)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgCredFileVal != "" {
+		profile.CredentialFile = flgCredFileVal
+	}
+
+	flgCustIDVal, err := cmd.Flags().GetString(flgnm.FLG_CUSTOMERID)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgCustIDVal != "" {
+		profile.CustomerID = flgCustIDVal
+	}
+
+	flgDomainVal, err := cmd.Flags().GetString(flgnm.FLG_DOMAIN)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgDomainVal != "" {
+		profile.Domain = flgDomainVal
+	}
+
+	flgLogPathVal, err := cmd.Flags().GetString(flgnm.FLG_LOGPATH)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgLogPathVal != "" {
+		profile.LogPath = flgLogPathVal
+	}
+
+	valsPassed := flgAdminVal != "" || flgCredFileVal != "" || flgCustIDVal != "" || flgDomainVal != "" || flgLogPathVal != ""
+	if !exists && !valsPassed {
+		err = fmt.Errorf(gmess.ERR_PROFILENOTFOUND, args[0])
+		lg.Error(err)
+		return err
+	}
+
+	if valsPassed {
+		profiles[name] = profile
+
+		// All profiles are set because viper only returns set values for a key that has been set
+		profilesMap := map[string]interface{}{}
+		for pName, p := range profiles {
+			profilesMap[pName] = profileMap(p)
+		}
+		viper.Set(cfg.CONFIGPROFILES, profilesMap)
+	}
+	viper.Set(cfg.CONFIGPROFILE, name)
+
+	err = viper.WriteConfig()
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_PROFILESET, name)))
+	lg.Infof(gmess.INFO_PROFILESET, name)
+
+	return nil
+}
+
+func profileMap(profile cfg.Profile) map[string]interface{} {
+	lg.Debugw("starting profileMap()",
+		"profile", profile)
+	defer lg.Debug("finished profileMap()")
+
+	pMap := map[string]interface{}{}
+	vals := map[string]string{
+		cfg.CONFIGADMIN:    profile.Administrator,
+		cfg.CONFIGCREDFILE: profile.CredentialFile,
+		cfg.CONFIGCUSTID:   profile.CustomerID,
+		cfg.CONFIGDOMAIN:   profile.Domain,
+		cfg.CONFIGLOGPATH:  profile.LogPath,
+	}
+	for key, val := range vals {
+		if val != "" {
+			pMap[key] = val
+		}
+	}
+	return pMap
+}
+
+func init() {
+	setCmd.AddCommand(setProfileCmd)
+
+	setProfileCmd.Flags().StringVarP(&adminEmail, flgnm.FLG_ADMIN, "a", "", "administrator email address")
+	setProfileCmd.Flags().StringVarP(&customerID, flgnm.FLG_CUSTOMERID, "c", "", "customer id for domain")
+	setProfileCmd.Flags().StringVarP(&domain, flgnm.FLG_DOMAIN, "d", "", "default domain")
+	setProfileCmd.Flags().StringVarP(&credentialFile, flgnm.FLG_CREDFILE, "f", "", "service account credential file")
+	setProfileCmd.Flags().StringVarP(&logPath, flgnm.FLG_LOGPATH, "l", "", "log file path")
+	setProfileCmd.PersistentFlags().BoolVar(&silent, flgnm.FLG_SILENT, false, "suppress console output")
+}
@@ -0,0 +1,142 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	cfg "github.com/plusworx/gmin/utils/config"
+	"github.com/spf13/viper"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+func TestProfiles(t *testing.T) {
+	fs := newFakeServer(t)
+	fs.AddDomain(&admin.Domains{DomainName: "acme.com"})
+	fs.AddDomain(&admin.Domains{DomainName: "globex.com"})
+	fs.AddUser(&admin.User{PrimaryEmail: "wile.coyote@acme.com"})
+	fs.AddUser(&admin.User{PrimaryEmail: "hank.scorpio@globex.com"})
+
+	dir := t.TempDir()
+	cfgFile := filepath.Join(dir, ".gmin.yaml")
+	ioutil.WriteFile(cfgFile, []byte(`administrator: admin@example.com
+profiles:
+  acme:
+    administrator: admin@acme.com
+    customerid: C01acme
+    domain: acme.com
+    logpath: `+dir+`
+  globex:
+    administrator: admin@globex.com
+    logpath: `+dir+`
+`), 0644)
+
+	t.Cleanup(func() {
+		viper.Set(cfg.CONFIGPROFILE, "")
+		viper.Set(cfg.CONFIGPROFILES, map[string]interface{}{})
+		cfg.UseProfile("")
+	})
+
+	cases := []struct {
+		args        []string
+		envProfile  string
+		expectedErr string
+		expectedOut []string
+	}{
+		{
+			args:        []string{"whoami", "--config", cfgFile, "--profile", "acme"},
+			expectedOut: []string{"admin is admin@acme.com", "profile is acme", "domain is acme.com"},
+		},
+		{
+			args:        []string{"list", "users", "--config", cfgFile, "--profile", "acme", "-a", "primaryemail", "--output", "csv"},
+			expectedOut: []string{"primaryEmail\nwile.coyote@acme.com\n"},
+		},
+		{
+			args:        []string{"list", "users", "--config", cfgFile, "--profile", "acme", "-d", "globex.com", "-a", "primaryemail", "--output", "csv"},
+			expectedOut: []string{"primaryEmail\nhank.scorpio@globex.com\n"},
+		},
+		{
+			args:        []string{"whoami", "--config", cfgFile},
+			envProfile:  "globex",
+			expectedOut: []string{"admin is admin@globex.com", "profile is globex"},
+		},
+		{
+			args:        []string{"whoami", "--config", cfgFile, "--profile", "initech"},
+			expectedErr: "profile not found: initech",
+		},
+		{
+			args:        []string{"show", "profiles", "--config", cfgFile, "--profile", "ACME"},
+			expectedOut: []string{"* acme\n    administrator: admin@acme.com\n    customerid: C01acme\n", "  globex\n    administrator: admin@globex.com\n"},
+		},
+		{
+			args:        []string{"set", "profile", "initech", "--config", cfgFile},
+			expectedErr: "profile not found: initech",
+		},
+		{
+			args:        []string{"set", "profile", "initech", "--config", cfgFile, "-a", "admin@initech.com", "-d", "initech.com"},
+			expectedOut: []string{"profile set to: initech"},
+		},
+		{
+			args:        []string{"whoami", "--config", cfgFile},
+			expectedOut: []string{"admin is admin@initech.com", "profile is initech", "domain is initech.com"},
+		},
+		{
+			args:        []string{"list", "users", "--config", cfgFile, "--count"},
+			expectedErr: "googleapi: Error 400: Domain not found., badRequest",
+		},
+		{
+			args:        []string{"set", "profile", "globex", "--config", cfgFile},
+			expectedOut: []string{"profile set to: globex"},
+		},
+		{
+			args:        []string{"whoami", "--config", cfgFile},
+			expectedOut: []string{"admin is admin@globex.com", "profile is globex"},
+		},
+	}
+
+	for _, c := range cases {
+		os.Setenv(cfg.ENVPREFIX+cfg.ENVVARPROFILE, c.envProfile)
+
+		out, got := runGmin(t, c.args...)
+
+		os.Unsetenv(cfg.ENVPREFIX + cfg.ENVVARPROFILE)
+
+		gotErr := ""
+		if got != nil {
+			gotErr = got.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("Got error: %v - expected error: %v", gotErr, c.expectedErr)
+		}
+
+		for _, exp := range c.expectedOut {
+			if !strings.Contains(out, exp) {
+				t.Errorf("Got output: %v - expected output to contain: %v", out, exp)
+			}
+		}
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	usrs "github.com/plusworx/gmin/utils/users"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var setUserPhotoCmd = &cobra.Command{
+	Use:     "user-photo <user email address, alias or id> <image file path>",
+	Aliases: []string{"usr-photo", "uphoto"},
+	Args:    cobra.ExactArgs(2),
+	Example: `gmin set user-photo mickey.mouse@disney.com mickey.jpg
+gmin set uphoto donald.duck@disney.com /photos/donald.png`,
+	Short: "Sets a user photo",
+	Long: `Sets a user photo from a JPEG or PNG image file.
+
+Images larger than 96x96 pixels are scaled down, keeping their aspect ratio, and all images are uploaded as JPEG.`,
+	RunE: doSetUserPhoto,
+}
+
+func doSetUserPhoto(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doSetUserPhoto()",
+		"args", args)
+	defer lg.Debug("finished doSetUserPhoto()")
+
+	photo, err := usrs.PhotoFromFile(args[1])
+	if err != nil {
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryUserScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	_, err = ds.Users.Photos.Update(args[0], photo).Do()
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_PHOTOUPDATED, args[0])))
+	lg.Infof(gmess.INFO_PHOTOUPDATED, args[0])
+
+	return nil
+}
+
+func init() {
+	setCmd.AddCommand(setUserPhotoCmd)
+}
@@ -30,6 +30,7 @@ import (
 	cdevs "github.com/plusworx/gmin/utils/chromeosdevices"
 	ca "github.com/plusworx/gmin/utils/commandaliases"
 	cmn "github.com/plusworx/gmin/utils/common"
+	doms "github.com/plusworx/gmin/utils/domains"
 	flgnm "github.com/plusworx/gmin/utils/flagnames"
 	gmess "github.com/plusworx/gmin/utils/gminmessages"
 	gas "github.com/plusworx/gmin/utils/groupaliases"
@@ -39,9 +40,13 @@ import (
 	mems "github.com/plusworx/gmin/utils/members"
 	mdevs "github.com/plusworx/gmin/utils/mobiledevices"
 	ous "github.com/plusworx/gmin/utils/orgunits"
+	rpts "github.com/plusworx/gmin/utils/reports"
+	rsrcs "github.com/plusworx/gmin/utils/resources"
+	rls "github.com/plusworx/gmin/utils/roles"
 	scs "github.com/plusworx/gmin/utils/schemas"
 	uas "github.com/plusworx/gmin/utils/useraliases"
 	usrs "github.com/plusworx/gmin/utils/users"
+	usec "github.com/plusworx/gmin/utils/usersecurity"
 	"github.com/spf13/cobra"
 )
 
@@ -55,16 +60,30 @@ gmin show attrs user name`,
 	Long: `Shows object attribute information.
 	
 Valid objects are:
+activity, act
+app-password, asp
+backup-code, bcode
+building, bldg
+calendar-resource, cal-resource, calres, cres
 chromeos-device, cros-device, cros-dev, cdev
+domain, dom
+domain-alias, dom-alias, dalias, da
+feature, feat
 group, grp
 group-alias, grp-alias, galias, ga
 group-member, grp-member, grp-mem, gmember, gmem
 group-settings,	grp-settings, grp-set, gsettings, gset
 mobile-device, mob-device, mob-dev, mdev
 orgunit, ou
+privilege, priv
+role
+role-assignment, role-asgmt, rasgmt, ra
 schema, sc
+token, tok
+usage-report, usage
 user, usr
-user-alias, ualias, ua`,
+user-alias, ualias, ua
+user-photo, usr-photo, uphoto`,
 	RunE: doShowAttrs,
 }
 
@@ -110,6 +129,41 @@ func doShowAttrs(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if cmn.SliceContainsStr(ca.ActivityAliases, object) {
+		err := saActivity(args, lArgs, args[0], lowerFilter, flgQueryableVal, flgCompositeVal)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cmn.SliceContainsStr(ca.ASPAliases, object) {
+		err := saAppPassword(args, lArgs, args[0], lowerFilter, flgQueryableVal, flgCompositeVal)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cmn.SliceContainsStr(ca.BCodeAliases, object) {
+		err := saBackupCode(args, lArgs, args[0], lowerFilter, flgQueryableVal, flgCompositeVal)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cmn.SliceContainsStr(ca.BldgAliases, object) {
+		err := saBuilding(args, lArgs, args[0], lowerFilter, flgQueryableVal, flgCompositeVal)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cmn.SliceContainsStr(ca.CalResAliases, object) {
+		err := saCalendarResource(args, lArgs, args[0], lowerFilter, flgQueryableVal, flgCompositeVal)
+		if err != nil {
+			return err
+		}
+	}
+
 	if cmn.SliceContainsStr(ca.CDevAliases, object) {
 		err := saChromeOSDev(args, lArgs, args[0], lowerFilter, flgQueryableVal, flgCompositeVal)
 		if err != nil {
@@ -117,6 +171,20 @@ func doShowAttrs(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if cmn.SliceContainsStr(ca.DomainAliases, object) {
+		err := saDomain(args, lArgs, args[0], lowerFilter, flgQueryableVal, flgCompositeVal)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cmn.SliceContainsStr(ca.DAAliases, object) {
+		err := saDomainAlias(args, lArgs, args[0], lowerFilter, flgQueryableVal, flgCompositeVal)
+		if err != nil {
+			return err
+		}
+	}
+
 	if cmn.SliceContainsStr(ca.GroupAliases, object) {
 		err := saGroup(args, lArgs, args[0], lowerFilter, flgQueryableVal, flgCompositeVal)
 		if err != nil {
@@ -124,6 +192,13 @@ func doShowAttrs(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if cmn.SliceContainsStr(ca.FeatAliases, object) {
+		err := saFeature(args, lArgs, args[0], lowerFilter, flgQueryableVal, flgCompositeVal)
+		if err != nil {
+			return err
+		}
+	}
+
 	if cmn.SliceContainsStr(ca.GAAliases, object) {
 		err := saGroupAlias(args, lArgs, args[0], lowerFilter, flgQueryableVal, flgCompositeVal)
 		if err != nil {
@@ -159,6 +234,27 @@ func doShowAttrs(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if cmn.SliceContainsStr(ca.PrivAliases, object) {
+		err := saPrivilege(args, lArgs, args[0], lowerFilter, flgQueryableVal, flgCompositeVal)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cmn.SliceContainsStr(ca.RoleAliases, object) {
+		err := saRole(args, lArgs, args[0], lowerFilter, flgQueryableVal, flgCompositeVal)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cmn.SliceContainsStr(ca.RAAliases, object) {
+		err := saRoleAssignment(args, lArgs, args[0], lowerFilter, flgQueryableVal, flgCompositeVal)
+		if err != nil {
+			return err
+		}
+	}
+
 	if cmn.SliceContainsStr(ca.SCAliases, object) {
 		err := saSchema(args, lArgs, args[0], lowerFilter, flgQueryableVal, flgCompositeVal)
 		if err != nil {
@@ -166,6 +262,20 @@ func doShowAttrs(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if cmn.SliceContainsStr(ca.TokenAliases, object) {
+		err := saToken(args, lArgs, args[0], lowerFilter, flgQueryableVal, flgCompositeVal)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cmn.SliceContainsStr(ca.UsageAliases, object) {
+		err := saUsageReport(args, lArgs, args[0], lowerFilter, flgQueryableVal, flgCompositeVal)
+		if err != nil {
+			return err
+		}
+	}
+
 	if cmn.SliceContainsStr(ca.UserAliases, object) {
 		err := saUser(args, lArgs, args[0], lowerFilter, flgQueryableVal, flgCompositeVal)
 		if err != nil {
@@ -180,6 +290,13 @@ func doShowAttrs(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if cmn.SliceContainsStr(ca.UPhotoAliases, object) {
+		err := saUserPhoto(args, lArgs, args[0], lowerFilter, flgQueryableVal, flgCompositeVal)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -191,6 +308,154 @@ func init() {
 	showAttrsCmd.Flags().BoolVarP(&queryable, flgnm.FLG_QUERYABLE, "q", false, "show attributes that can be used in a query")
 }
 
+func saActivity(args []string, lArgs int, objectName string, filter string, queryable bool, composite bool) error {
+	lg.Debugw("starting saActivity()",
+		"args", args,
+		"lArgs", lArgs,
+		"objectName", objectName)
+	defer lg.Debug("finished saActivity()")
+
+	if queryable {
+		return fmt.Errorf(gmess.ERR_NOQUERYABLEATTRS, objectName)
+	}
+
+	if lArgs == 1 {
+		if composite {
+			rpts.ShowActivityCompAttrs(filter)
+			return nil
+		}
+		rpts.ShowActivityAttrs(filter)
+	}
+
+	if lArgs == 2 {
+		if composite {
+			return fmt.Errorf(gmess.ERR_NOCOMPOSITEATTRS, args[lArgs-1])
+		}
+		err := rpts.ShowActivitySubAttrs(args[lArgs-1], filter)
+		if err != nil {
+			return err
+		}
+	}
+
+	if lArgs > 2 {
+		return fmt.Errorf(gmess.ERR_NOCOMPOSITEATTRS, args[2])
+	}
+
+	return nil
+}
+
+func saAppPassword(args []string, lArgs int, objectName string, filter string, queryable bool, composite bool) error {
+	lg.Debugw("starting saAppPassword()",
+		"args", args,
+		"lArgs", lArgs,
+		"objectName", objectName)
+	defer lg.Debug("finished saAppPassword()")
+
+	if queryable {
+		return fmt.Errorf(gmess.ERR_NOQUERYABLEATTRS, objectName)
+	}
+
+	if lArgs == 1 {
+		if composite {
+			return fmt.Errorf(gmess.ERR_NOCOMPOSITEATTRS, objectName)
+		}
+		usec.ShowAspAttrs(filter)
+	}
+
+	if lArgs > 1 {
+		return fmt.Errorf(gmess.ERR_NOCOMPOSITEATTRS, objectName)
+	}
+
+	return nil
+}
+
+func saBackupCode(args []string, lArgs int, objectName string, filter string, queryable bool, composite bool) error {
+	lg.Debugw("starting saBackupCode()",
+		"args", args,
+		"lArgs", lArgs,
+		"objectName", objectName)
+	defer lg.Debug("finished saBackupCode()")
+
+	if queryable {
+		return fmt.Errorf(gmess.ERR_NOQUERYABLEATTRS, objectName)
+	}
+
+	if lArgs == 1 {
+		if composite {
+			return fmt.Errorf(gmess.ERR_NOCOMPOSITEATTRS, objectName)
+		}
+		usec.ShowVerCodeAttrs(filter)
+	}
+
+	if lArgs > 1 {
+		return fmt.Errorf(gmess.ERR_NOCOMPOSITEATTRS, objectName)
+	}
+
+	return nil
+}
+
+func saBuilding(args []string, lArgs int, objectName string, filter string, queryable bool, composite bool) error {
+	lg.Debugw("starting saBuilding()",
+		"args", args,
+		"lArgs", lArgs,
+		"objectName", objectName)
+	defer lg.Debug("finished saBuilding()")
+
+	if queryable {
+		return fmt.Errorf(gmess.ERR_NOQUERYABLEATTRS, objectName)
+	}
+
+	if lArgs == 1 {
+		if composite {
+			rsrcs.ShowBuildingCompAttrs(filter)
+			return nil
+		}
+		rsrcs.ShowBuildingAttrs(filter)
+	}
+
+	if lArgs == 2 {
+		if composite {
+			return fmt.Errorf(gmess.ERR_NOCOMPOSITEATTRS, args[lArgs-1])
+		}
+		err := rsrcs.ShowBuildingSubAttrs(args[lArgs-1], filter)
+		if err != nil {
+			return err
+		}
+	}
+
+	if lArgs > 2 {
+		return fmt.Errorf(gmess.ERR_NOCOMPOSITEATTRS, args[2])
+	}
+
+	return nil
+}
+
+func saCalendarResource(args []string, lArgs int, objectName string, filter string, queryable bool, composite bool) error {
+	lg.Debugw("starting saCalendarResource()",
+		"args", args,
+		"lArgs", lArgs,
+		"objectName", objectName)
+	defer lg.Debug("finished saCalendarResource()")
+
+	if queryable {
+		cmn.ShowQueryableAttrs(filter, rsrcs.QueryAttrMap)
+		return nil
+	}
+
+	if lArgs == 1 {
+		if composite {
+			return fmt.Errorf(gmess.ERR_NOCOMPOSITEATTRS, objectName)
+		}
+		rsrcs.ShowCalendarAttrs(filter)
+	}
+
+	if lArgs > 1 {
+		return fmt.Errorf(gmess.ERR_NOCOMPOSITEATTRS, objectName)
+	}
+
+	return nil
+}
+
 func saChromeOSDev(args []string, lArgs int, objectName string, filter string, queryable bool, composite bool) error {
 	lg.Debugw("starting saChromeOSDev()",
 		"args", args,
@@ -229,6 +494,81 @@ func saChromeOSDev(args []string, lArgs int, objectName string, filter string, q
 	return nil
 }
 
+func saDomain(args []string, lArgs int, objectName string, filter string, queryable bool, composite bool) error {
+	lg.Debugw("starting saDomain()",
+		"args", args,
+		"lArgs", lArgs,
+		"objectName", objectName)
+	defer lg.Debug("finished saDomain()")
+
+	if queryable {
+		return fmt.Errorf(gmess.ERR_NOQUERYABLEATTRS, objectName)
+	}
+
+	if lArgs == 1 {
+		if composite {
+			return fmt.Errorf(gmess.ERR_NOCOMPOSITEATTRS, objectName)
+		}
+		doms.ShowAttrs(filter)
+	}
+
+	if lArgs > 1 {
+		return fmt.Errorf(gmess.ERR_NOCOMPOSITEATTRS, objectName)
+	}
+
+	return nil
+}
+
+func saDomainAlias(args []string, lArgs int, objectName string, filter string, queryable bool, composite bool) error {
+	lg.Debugw("starting saDomainAlias()",
+		"args", args,
+		"lArgs", lArgs,
+		"objectName", objectName)
+	defer lg.Debug("finished saDomainAlias()")
+
+	if queryable {
+		return fmt.Errorf(gmess.ERR_NOQUERYABLEATTRS, objectName)
+	}
+
+	if lArgs == 1 {
+		if composite {
+			return fmt.Errorf(gmess.ERR_NOCOMPOSITEATTRS, objectName)
+		}
+		doms.ShowAliasAttrs(filter)
+	}
+
+	if lArgs > 1 {
+		return fmt.Errorf(gmess.ERR_NOCOMPOSITEATTRS, objectName)
+	}
+
+	return nil
+}
+
+func saFeature(args []string, lArgs int, objectName string, filter string, queryable bool, composite bool) error {
+	lg.Debugw("starting saFeature()",
+		"args", args,
+		"lArgs", lArgs,
+		"objectName", objectName)
+	defer lg.Debug("finished saFeature()")
+
+	if queryable {
+		return fmt.Errorf(gmess.ERR_NOQUERYABLEATTRS, objectName)
+	}
+
+	if lArgs == 1 {
+		if composite {
+			return fmt.Errorf(gmess.ERR_NOCOMPOSITEATTRS, objectName)
+		}
+		rsrcs.ShowFeatureAttrs(filter)
+	}
+
+	if lArgs > 1 {
+		return fmt.Errorf(gmess.ERR_NOCOMPOSITEATTRS, objectName)
+	}
+
+	return nil
+}
+
 func saGroup(args []string, lArgs int, objectName string, filter string, queryable bool, composite bool) error {
 	lg.Debugw("starting saGroup()",
 		"args", args,
@@ -393,6 +733,93 @@ func saOrgUnit(args []string, lArgs int, objectName string, filter string, query
 	return nil
 }
 
+func saPrivilege(args []string, lArgs int, objectName string, filter string, queryable bool, composite bool) error {
+	lg.Debugw("starting saPrivilege()",
+		"args", args,
+		"lArgs", lArgs,
+		"objectName", objectName)
+	defer lg.Debug("finished saPrivilege()")
+
+	if queryable {
+		return fmt.Errorf(gmess.ERR_NOQUERYABLEATTRS, objectName)
+	}
+
+	if lArgs == 1 {
+		if composite {
+			return fmt.Errorf(gmess.ERR_NOCOMPOSITEATTRS, objectName)
+		}
+		rls.ShowPrivilegeAttrs(filter)
+	}
+
+	if lArgs > 1 {
+		return fmt.Errorf(gmess.ERR_NOCOMPOSITEATTRS, objectName)
+	}
+
+	return nil
+}
+
+func saRole(args []string, lArgs int, objectName string, filter string, queryable bool, composite bool) error {
+	lg.Debugw("starting saRole()",
+		"args", args,
+		"lArgs", lArgs,
+		"objectName", objectName)
+	defer lg.Debug("finished saRole()")
+
+	if queryable {
+		return fmt.Errorf(gmess.ERR_NOQUERYABLEATTRS, objectName)
+	}
+
+	if lArgs == 1 {
+		if composite {
+			rls.ShowCompAttrs(filter)
+			return nil
+		}
+		rls.ShowAttrs(filter)
+		return nil
+	}
+
+	if lArgs == 2 {
+		if composite {
+			return fmt.Errorf(gmess.ERR_NOCOMPOSITEATTRS, args[lArgs-1])
+		}
+		err := rls.ShowSubAttrs(args[lArgs-1], filter)
+		if err != nil {
+			return err
+		}
+	}
+
+	if lArgs > 2 {
+		return fmt.Errorf(gmess.ERR_NOCOMPOSITEATTRS, args[2])
+	}
+
+	return nil
+}
+
+func saRoleAssignment(args []string, lArgs int, objectName string, filter string, queryable bool, composite bool) error {
+	lg.Debugw("starting saRoleAssignment()",
+		"args", args,
+		"lArgs", lArgs,
+		"objectName", objectName)
+	defer lg.Debug("finished saRoleAssignment()")
+
+	if queryable {
+		return fmt.Errorf(gmess.ERR_NOQUERYABLEATTRS, objectName)
+	}
+
+	if lArgs == 1 {
+		if composite {
+			return fmt.Errorf(gmess.ERR_NOCOMPOSITEATTRS, objectName)
+		}
+		rls.ShowAssignmentAttrs(filter)
+	}
+
+	if lArgs > 1 {
+		return fmt.Errorf(gmess.ERR_NOCOMPOSITEATTRS, objectName)
+	}
+
+	return nil
+}
+
 func saSchema(args []string, lArgs int, objectName string, filter string, queryable bool, composite bool) error {
 	lg.Debugw("starting saSchema()",
 		"args", args,
@@ -440,6 +867,67 @@ func saSchema(args []string, lArgs int, objectName string, filter string, querya
 	return nil
 }
 
+func saToken(args []string, lArgs int, objectName string, filter string, queryable bool, composite bool) error {
+	lg.Debugw("starting saToken()",
+		"args", args,
+		"lArgs", lArgs,
+		"objectName", objectName)
+	defer lg.Debug("finished saToken()")
+
+	if queryable {
+		return fmt.Errorf(gmess.ERR_NOQUERYABLEATTRS, objectName)
+	}
+
+	if lArgs == 1 {
+		if composite {
+			return fmt.Errorf(gmess.ERR_NOCOMPOSITEATTRS, objectName)
+		}
+		usec.ShowTokenAttrs(filter)
+	}
+
+	if lArgs > 1 {
+		return fmt.Errorf(gmess.ERR_NOCOMPOSITEATTRS, objectName)
+	}
+
+	return nil
+}
+
+func saUsageReport(args []string, lArgs int, objectName string, filter string, queryable bool, composite bool) error {
+	lg.Debugw("starting saUsageReport()",
+		"args", args,
+		"lArgs", lArgs,
+		"objectName", objectName)
+	defer lg.Debug("finished saUsageReport()")
+
+	if queryable {
+		return fmt.Errorf(gmess.ERR_NOQUERYABLEATTRS, objectName)
+	}
+
+	if lArgs == 1 {
+		if composite {
+			rpts.ShowUsageCompAttrs(filter)
+			return nil
+		}
+		rpts.ShowUsageAttrs(filter)
+	}
+
+	if lArgs == 2 {
+		if composite {
+			return fmt.Errorf(gmess.ERR_NOCOMPOSITEATTRS, args[lArgs-1])
+		}
+		err := rpts.ShowUsageSubAttrs(args[lArgs-1], filter)
+		if err != nil {
+			return err
+		}
+	}
+
+	if lArgs > 2 {
+		return fmt.Errorf(gmess.ERR_NOCOMPOSITEATTRS, args[2])
+	}
+
+	return nil
+}
+
 func saUser(args []string, lArgs int, objectName string, filter string, queryable bool, composite bool) error {
 	lg.Debugw("starting saUser()",
 		"args", args,
@@ -501,3 +989,28 @@ func saUserAlias(args []string, lArgs int, objectName string, filter string, que
 
 	return nil
 }
+
+func saUserPhoto(args []string, lArgs int, objectName string, filter string, queryable bool, composite bool) error {
+	lg.Debugw("starting saUserPhoto()",
+		"args", args,
+		"lArgs", lArgs,
+		"objectName", objectName)
+	defer lg.Debug("finished saUserPhoto()")
+
+	if queryable {
+		return fmt.Errorf(gmess.ERR_NOQUERYABLEATTRS, objectName)
+	}
+
+	if lArgs == 1 {
+		if composite {
+			return fmt.Errorf(gmess.ERR_NOCOMPOSITEATTRS, objectName)
+		}
+		usrs.ShowPhotoAttrs(filter)
+	}
+
+	if lArgs > 1 {
+		return fmt.Errorf(gmess.ERR_NOCOMPOSITEATTRS, objectName)
+	}
+
+	return nil
+}
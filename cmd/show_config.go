@@ -57,11 +57,15 @@ func doShowConfig(cmd *cobra.Command, args []string) error {
 	admin := os.Getenv(cfg.ENVPREFIX + cfg.ENVVARADMIN)
 	credPath := os.Getenv(cfg.ENVPREFIX + cfg.ENVVARCREDPATH)
 	custID := os.Getenv(cfg.ENVPREFIX + cfg.ENVVARCUSTID)
+	endpoint := os.Getenv(cfg.ENVPREFIX + cfg.ENVVARENDPOINT)
+	hasher := os.Getenv(cfg.ENVPREFIX + cfg.ENVVARHASHER)
 	logPath := os.Getenv(cfg.ENVPREFIX + cfg.ENVVARLOGPATH)
 	logRotationCount := os.Getenv(cfg.ENVPREFIX + cfg.ENVVARLOGROTATIONCOUNT)
 	logRotationTime := os.Getenv(cfg.ENVPREFIX + cfg.ENVVARLOGROTATIONTIME)
+	profile := os.Getenv(cfg.ENVPREFIX + cfg.ENVVARPROFILE)
 
-	if admin == "" && credPath == "" && custID == "" && logPath == "" && logRotationCount == "" && logRotationTime == "" {
+	if admin == "" && credPath == "" && custID == "" && endpoint == "" && hasher == "" && logPath == "" && logRotationCount == "" && logRotationTime == "" &&
+		profile == "" {
 		fmt.Println(gmess.INFO_ENVVARSNOTFOUND)
 	}
 	if admin != "" {
@@ -73,6 +77,12 @@ func doShowConfig(cmd *cobra.Command, args []string) error {
 	if custID != "" {
 		fmt.Println(cfg.ENVPREFIX+cfg.ENVVARCUSTID+":", custID)
 	}
+	if endpoint != "" {
+		fmt.Println(cfg.ENVPREFIX+cfg.ENVVARENDPOINT+":", endpoint)
+	}
+	if hasher != "" {
+		fmt.Println(cfg.ENVPREFIX+cfg.ENVVARHASHER+":", hasher)
+	}
 	if logPath != "" {
 		fmt.Println(cfg.ENVPREFIX+cfg.ENVVARLOGPATH+":", logPath)
 	}
@@ -82,6 +92,9 @@ func doShowConfig(cmd *cobra.Command, args []string) error {
 	if logRotationTime != "" {
 		fmt.Println(cfg.ENVPREFIX+cfg.ENVVARLOGROTATIONTIME+":", logRotationTime)
 	}
+	if profile != "" {
+		fmt.Println(cfg.ENVPREFIX+cfg.ENVVARPROFILE+":", profile)
+	}
 
 	fmt.Println("")
 	fmt.Println("Config File")
@@ -0,0 +1,100 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	cfg "github.com/plusworx/gmin/utils/config"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	"github.com/spf13/cobra"
+)
+
+var showProfilesCmd = &cobra.Command{
+	Use:     "profiles",
+	Aliases: []string{"profile", "profs", "prof"},
+	Args:    cobra.NoArgs,
+	Example: `gmin show profiles
+gmin show profs`,
+	Short: "Shows config file profiles",
+	Long: `Shows config file profiles.
+
+The active profile is marked with an asterisk (*).`,
+	RunE: doShowProfiles,
+}
+
+func doShowProfiles(cmd *cobra.Command, args []string) error {
+	lg.Debug("starting doShowProfiles()")
+	defer lg.Debug("finished doShowProfiles()")
+
+	profiles, err := cfg.Profiles()
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	if len(profiles) == 0 {
+		fmt.Println(gmess.INFO_PROFILESNOTFOUND)
+		return nil
+	}
+
+	names := []string{}
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		profile := profiles[name]
+
+		marker := "  "
+		if name == cfg.ActiveProfile() {
+			marker = "* "
+		}
+		fmt.Println(marker + name)
+
+		vals := []struct {
+			key string
+			val string
+		}{
+			{cfg.CONFIGADMIN, profile.Administrator},
+			{cfg.CONFIGCREDFILE, profile.CredentialFile},
+			{cfg.CONFIGCUSTID, profile.CustomerID},
+			{cfg.CONFIGDOMAIN, profile.Domain},
+			{cfg.CONFIGLOGPATH, profile.LogPath},
+		}
+		for _, v := range vals {
+			if v.val != "" {
+				fmt.Println("    " + v.key + ": " + v.val)
+			}
+		}
+	}
+
+	return nil
+}
+
+func init() {
+	showCmd.AddCommand(showProfilesCmd)
+}
@@ -75,11 +75,44 @@ func TestDoShowAttrs(t *testing.T) {
 			queryable:   true,
 			expectedErr: fmt.Sprintf(gmess.ERR_NOQUERYABLEATTRS, "ga"),
 		},
+		{
+			args:        []string{"dom"},
+			composite:   true,
+			expectedErr: fmt.Sprintf(gmess.ERR_NOCOMPOSITEATTRS, "dom"),
+		},
+		{
+			args:        []string{"bldg", "floornames"},
+			expectedErr: fmt.Sprintf(gmess.ERR_NOTCOMPOSITEATTR, "floornames"),
+		},
+		{
+			args:        []string{"feat"},
+			queryable:   true,
+			expectedErr: fmt.Sprintf(gmess.ERR_NOQUERYABLEATTRS, "feat"),
+		},
+		{
+			args:        []string{"tok"},
+			queryable:   true,
+			expectedErr: fmt.Sprintf(gmess.ERR_NOQUERYABLEATTRS, "tok"),
+		},
+		{
+			args:        []string{"asp", "name"},
+			expectedErr: fmt.Sprintf(gmess.ERR_NOCOMPOSITEATTRS, "asp"),
+		},
 		{
 			args:        []string{"gmem"},
 			composite:   true,
 			expectedErr: fmt.Sprintf(gmess.ERR_NOCOMPOSITEATTRS, "gmem"),
 		},
+		{
+			args:        []string{"role", "roleprivileges"},
+			composite:   true,
+			expectedErr: fmt.Sprintf(gmess.ERR_NOCOMPOSITEATTRS, "roleprivileges"),
+		},
+		{
+			args:        []string{"ra"},
+			queryable:   true,
+			expectedErr: fmt.Sprintf(gmess.ERR_NOQUERYABLEATTRS, "ra"),
+		},
 	}
 
 	initConfig()
@@ -0,0 +1,274 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	aply "github.com/plusworx/gmin/utils/apply"
+	btch "github.com/plusworx/gmin/utils/batch"
+	cmn "github.com/plusworx/gmin/utils/common"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	mems "github.com/plusworx/gmin/utils/members"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var syncMemberCmd = &cobra.Command{
+	Use:     "group-members <group email address or id> -i <input file path or google sheet id>",
+	Aliases: []string{"group-member", "grp-members", "grp-member", "gmembers", "gmember", "gmems", "gmem"},
+	Args:    cobra.ExactArgs(1),
+	Example: `gmin sync group-members sales@mycompany.com -i sales.txt
+gmin sync gmems sales@mycompany.com -i sales.csv -f csv --protect-owners
+gmin sync gmem sales@mycompany.com -i 1odyAIp3jGspd3M4xeepxWD6aeQIUuHBgrZB2OHSu8MI -s 'Sheet1!A1:B25' -f gsheet --dry-run`,
+	Short: "Makes the members of a group match a member list",
+	Long: `Makes the members of a group match a member list by creating members that are missing, changing member roles
+and deleting members that are not in the list. The planned changes are shown before they are made and --dry-run
+shows them without making them.
+
+A text input file or piped in data should provide member email addresses on separate lines like this:
+
+frank.castle@mycompany.com
+bruce.wayne@mycompany.com
+peter.parker@mycompany.com
+
+The contents of a JSON file should look something like this:
+
+{"email":"kayden.yundt@mycompany.com","role":"MEMBER"}
+{"email":"kenyatta.tillman@mycompany.com","role":"MANAGER"}
+
+CSV and Google sheets must have a header row with the following column names being the only ones that are valid:
+
+delivery_settings
+email [required]
+role
+
+Members without a role keep their current role or are created as members. Delivery settings are ignored.
+--protect-owners leaves current owners of the group in place with their role unchanged even if the list
+says otherwise. An empty list is refused unless --allow-empty is given, in which case every member is deleted.`,
+	RunE: doSyncMember,
+}
+
+func doSyncMember(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doSyncMember()",
+		"args", args)
+	defer lg.Debug("finished doSyncMember()")
+
+	var (
+		input   []*admin.Member
+		members []aply.Member
+	)
+
+	group := args[0]
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryGroupMemberScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	input, err = smInputMembers(cmd)
+	if err != nil {
+		return err
+	}
+	for _, member := range input {
+		members = append(members, aply.Member{Email: member.Email, Role: member.Role})
+	}
+
+	flgProtectVal, err := cmd.Flags().GetBool(flgnm.FLG_PROTECTOWNERS)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	flgAllowEmptyVal, err := cmd.Flags().GetBool(flgnm.FLG_ALLOWEMPTY)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	flgQPSVal, err := cmd.Flags().GetFloat64(flgnm.FLG_QPS)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgQPSVal < 0 {
+		err = fmt.Errorf(gmess.ERR_INVALIDQPS, flgQPSVal)
+		lg.Error(err)
+		return err
+	}
+
+	curMembers, err := smCurrent(ds, group)
+	if err != nil {
+		return err
+	}
+
+	plan, protected, err := aply.SyncMembersPlan(group, members, curMembers, flgProtectVal, flgAllowEmptyVal)
+	if err != nil {
+		return err
+	}
+
+	for _, owner := range protected {
+		fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_MEMBERPROTECTED, owner.Email, group)))
+		lg.Infof(gmess.INFO_MEMBERPROTECTED, owner.Email, group)
+	}
+
+	return applyPlan(ds, nil, "", plan, flgQPSVal)
+}
+
+// smCurrent gets the current members of a group keyed by lowercase email address. Members without an email
+// address are left out so that they are never deleted.
+func smCurrent(ds *admin.Service, group string) (map[string]*admin.Member, error) {
+	lg.Debugw("starting smCurrent()",
+		"group", group)
+	defer lg.Debug("finished smCurrent()")
+
+	curMembers := map[string]*admin.Member{}
+
+	mlc := ds.Members.List(group)
+	mlc = mems.AddFields(mlc, "nextPageToken,members(email,id,role,type)").(*admin.MembersListCall)
+	for {
+		members, err := mems.DoList(mlc)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range members.Members {
+			if member.Email == "" {
+				continue
+			}
+			curMembers[strings.ToLower(member.Email)] = member
+		}
+		if members.NextPageToken == "" {
+			break
+		}
+		mlc = mems.AddPageToken(mlc, members.NextPageToken)
+	}
+
+	return curMembers, nil
+}
+
+// smInputMembers reads the desired group members from the input file, piped input or Google sheet
+func smInputMembers(cmd *cobra.Command) ([]*admin.Member, error) {
+	lg.Debug("starting smInputMembers()")
+	defer lg.Debug("finished smInputMembers()")
+
+	var (
+		members []*admin.Member
+		objs    []interface{}
+	)
+
+	inputFlgVal, err := cmd.Flags().GetString(flgnm.FLG_INPUTFILE)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	scanner, err := cmn.InputFromStdIn(inputFlgVal)
+	if err != nil {
+		return nil, err
+	}
+
+	if inputFlgVal == "" && scanner == nil {
+		err := errors.New(gmess.ERR_NOINPUTFILE)
+		lg.Error(err)
+		return nil, err
+	}
+
+	formatFlgVal, err := cmd.Flags().GetString(flgnm.FLG_FORMAT)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+	lwrFmt := strings.ToLower(formatFlgVal)
+
+	ok := cmn.SliceContainsStr(cmn.ValidFileFormats, lwrFmt)
+	if !ok {
+		err = fmt.Errorf(gmess.ERR_INVALIDFILEFORMAT, formatFlgVal)
+		lg.Error(err)
+		return nil, err
+	}
+
+	callParams := btch.CallParams{CallType: cmn.CALLTYPECREATE, ObjectType: cmn.OBJTYPEMEMBER}
+
+	switch {
+	case lwrFmt == "text":
+		emails, _, err := btch.DeleteProcessTextFile(inputFlgVal, scanner)
+		if err != nil {
+			return nil, err
+		}
+		for _, email := range emails {
+			email = strings.TrimSpace(email)
+			if email == "" {
+				continue
+			}
+			members = append(members, &admin.Member{Email: email})
+		}
+		return members, nil
+	case lwrFmt == "csv":
+		objs, _, err = btch.ProcessCSVFile(callParams, inputFlgVal, mems.MemberAttrMap)
+		if err != nil {
+			return nil, err
+		}
+	case lwrFmt == "json":
+		objs, _, err = btch.ProcessJSON(callParams, inputFlgVal, scanner, mems.MemberAttrMap)
+		if err != nil {
+			return nil, err
+		}
+	case lwrFmt == "gsheet":
+		rangeFlgVal, err := cmd.Flags().GetString(flgnm.FLG_SHEETRANGE)
+		if err != nil {
+			lg.Error(err)
+			return nil, err
+		}
+
+		objs, _, err = btch.ProcessGSheet(callParams, inputFlgVal, rangeFlgVal, mems.MemberAttrMap)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		err = fmt.Errorf(gmess.ERR_INVALIDFILEFORMAT, formatFlgVal)
+		lg.Error(err)
+		return nil, err
+	}
+
+	for _, memObj := range objs {
+		members = append(members, memObj.(*admin.Member))
+	}
+
+	return members, nil
+}
+
+func init() {
+	syncCmd.AddCommand(syncMemberCmd)
+
+	syncMemberCmd.Flags().BoolVar(&allowEmpty, flgnm.FLG_ALLOWEMPTY, false, "allow an empty member list that deletes every member")
+
+	syncMemberCmd.Flags().StringVarP(&inputFile, flgnm.FLG_INPUTFILE, "i", "", "filepath to group member data file or sheet id")
+	syncMemberCmd.Flags().StringVarP(&delFormat, flgnm.FLG_FORMAT, "f", "text", "group member data file format (text, csv, json or gsheet)")
+	syncMemberCmd.Flags().BoolVar(&protectOwners, flgnm.FLG_PROTECTOWNERS, false, "never delete or change the role of current owners")
+	syncMemberCmd.Flags().StringVarP(&sheetRange, flgnm.FLG_SHEETRANGE, "s", "", "group member data gsheet range")
+}
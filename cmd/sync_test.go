@@ -0,0 +1,122 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+func TestSyncMembersFakeServer(t *testing.T) {
+	fs := newFakeServer(t)
+	fs.AddGroup(&admin.Group{Email: "sales@mycompany.org", Name: "Sales"})
+	fs.AddMember("sales@mycompany.org", &admin.Member{Email: "boss@mycompany.org", Role: "OWNER", Type: "USER"})
+	fs.AddMember("sales@mycompany.org", &admin.Member{Email: "a.person@mycompany.org", Role: "MANAGER", Type: "USER"})
+	fs.AddMember("sales@mycompany.org", &admin.Member{Email: "b.person@mycompany.org", Role: "MEMBER", Type: "USER"})
+
+	dir := t.TempDir()
+	textFile := filepath.Join(dir, "sales.txt")
+	ioutil.WriteFile(textFile, []byte("a.person@mycompany.org\n\nc.person@mycompany.org\n"), 0644)
+	csvFile := filepath.Join(dir, "sales.csv")
+	ioutil.WriteFile(csvFile, []byte("email,role\nA.Person@mycompany.org,member\nboss@mycompany.org,manager\n"), 0644)
+	dupFile := filepath.Join(dir, "dup.txt")
+	ioutil.WriteFile(dupFile, []byte("a.person@mycompany.org\nA.Person@mycompany.org\n"), 0644)
+	emptyFile := filepath.Join(dir, "empty.txt")
+	ioutil.WriteFile(emptyFile, []byte("\n"), 0644)
+
+	_, err := runGmin(t, "sync", "group-members", "sales@mycompany.org", "-i", dupFile)
+	if err == nil || err.Error() != "member: A.Person@mycompany.org is listed more than once" {
+		t.Errorf("Got error: %v - expected error: member: A.Person@mycompany.org is listed more than once", err)
+	}
+
+	_, err = runGmin(t, "sync", "group-members", "sales@mycompany.org", "-i", emptyFile)
+	if err == nil || err.Error() != "member list is empty - use --allow-empty to delete every member of group: sales@mycompany.org" {
+		t.Errorf("Got error: %v - expected error: member list is empty - use --allow-empty to delete every member of group: sales@mycompany.org", err)
+	}
+
+	out, err := runGmin(t, "sync", "group-members", "sales@mycompany.org", "-i", emptyFile, "--allow-empty", "--dry-run")
+	if err != nil {
+		t.Fatalf("Got error: %v - expected error: nil", err)
+	}
+	if !strings.Contains(out, "plan: 0 to create, 0 to update, 3 to delete") {
+		t.Errorf("Got output: %v - expected output to contain: plan: 0 to create, 0 to update, 3 to delete", out)
+	}
+
+	out, err = runGmin(t, "sync", "group-members", "sales@mycompany.org", "-i", textFile, "--protect-owners", "--dry-run")
+	if err != nil {
+		t.Fatalf("Got error: %v - expected error: nil", err)
+	}
+	for _, want := range []string{"owner: boss@mycompany.org of group: sales@mycompany.org is protected", "plan: 1 to create, 0 to update, 1 to delete"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Got output: %v - expected output to contain: %v", out, want)
+		}
+	}
+	for _, req := range fs.Requests {
+		if !strings.HasPrefix(req, "GET ") {
+			t.Errorf("Got request: %v - expected only GET requests", req)
+		}
+	}
+
+	_, err = runGmin(t, "sync", "group-members", "sales@mycompany.org", "-i", textFile, "--protect-owners")
+	if err != nil {
+		t.Fatalf("Got error: %v - expected error: nil", err)
+	}
+	expected := map[string]string{
+		"a.person@mycompany.org": "MANAGER",
+		"boss@mycompany.org":     "OWNER",
+		"c.person@mycompany.org": "MEMBER",
+	}
+	smCheckMembers(t, fs.Members["sales@mycompany.org"], expected)
+
+	_, err = runGmin(t, "sync", "group-members", "sales@mycompany.org", "-i", csvFile, "-f", "csv")
+	if err != nil {
+		t.Fatalf("Got error: %v - expected error: nil", err)
+	}
+	expected = map[string]string{
+		"a.person@mycompany.org": "MEMBER",
+		"boss@mycompany.org":     "MANAGER",
+	}
+	smCheckMembers(t, fs.Members["sales@mycompany.org"], expected)
+}
+
+func smCheckMembers(t *testing.T, members map[string]*admin.Member, expected map[string]string) {
+	t.Helper()
+
+	if len(members) != len(expected) {
+		t.Errorf("Got members: %v - expected members: %v", len(members), len(expected))
+	}
+	for email, role := range expected {
+		member, ok := members[email]
+		if !ok {
+			t.Errorf("Got member: nil - expected member: %v", email)
+			continue
+		}
+		if member.Role != role {
+			t.Errorf("Got role: %v for member: %v - expected role: %v", member.Role, email, role)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+	"google.golang.org/api/googleapi"
+	gset "google.golang.org/api/groupssettings/v1"
+)
+
+// undoAdminScopes are the scopes needed to revert every kind of journaled Directory API change
+var undoAdminScopes = []string{
+	admin.AdminDirectoryDeviceChromeosScope,
+	admin.AdminDirectoryGroupScope,
+	admin.AdminDirectoryOrgunitScope,
+	admin.AdminDirectoryResourceCalendarScope,
+	admin.AdminDirectoryRolemanagementScope,
+	admin.AdminDirectoryUserScope,
+	admin.AdminDirectoryUserschemaScope,
+}
+
+var undoCmd = &cobra.Command{
+	Use:  "undo <run id>",
+	Args: cobra.ExactArgs(1),
+	Example: `gmin undo 20201201100000-a1b2c3 --dry-run
+gmin undo 20201201100000-a1b2c3`,
+	Short: "Reverts the changes made by a run",
+	Long: `Reverts the changes made by a run using the journal of objects recorded before each change was made.
+Run IDs are shown by the history command.
+
+Changes are reverted in reverse order. Deleted users are undeleted (if they are still within the
+recovery period), deleted groups, members, aliases, orgunits, roles, role assignments, schemas and
+calendar resources, buildings and features are recreated, feature renames are reversed, updated objects
+have the changed attributes set back to their previous values, super admin changes made with make-admin
+and revoke-admin are reversed and created objects are deleted. Changes that can't be reverted, such as
+passwords and device actions, are reported and skipped. Use the global --dry-run flag to show the changes
+that would be made.`,
+	RunE: doUndo,
+}
+
+func doUndo(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doUndo()",
+		"args", args)
+	defer lg.Debug("finished doUndo()")
+
+	var skipped int
+
+	journalDir, err := cmn.JournalDir()
+	if err != nil {
+		return err
+	}
+
+	entries, err := cmn.ReadJournal(journalDir, args[0])
+	if err != nil {
+		return err
+	}
+
+	adminClient, err := cmn.HTTPClient(cmn.SRVTYPEADMIN, undoAdminScopes...)
+	if err != nil {
+		return err
+	}
+
+	gsClient, err := cmn.HTTPClient(cmn.SRVTYPEGRPSETTING, gset.AppsGroupsSettingsScope)
+	if err != nil {
+		return err
+	}
+
+	for idx := len(entries) - 1; idx >= 0; idx-- {
+		undo, err := cmn.JournalUndo(entries[idx])
+		if err != nil {
+			fmt.Println(cmn.GminMessage(err.Error()))
+			skipped++
+			continue
+		}
+
+		client := adminClient
+		if strings.Contains(undo.URL, "/groups/v1/") {
+			client = gsClient
+		}
+
+		err = sendUndo(client, undo)
+		if err != nil {
+			return err
+		}
+	}
+
+	if skipped > 0 {
+		err = fmt.Errorf(gmess.ERR_UNDOINCOMPLETE, skipped, len(entries))
+		lg.Error(err)
+		return err
+	}
+
+	fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_RUNUNDONE, args[0])))
+	lg.Infof(gmess.INFO_RUNUNDONE, args[0])
+
+	return nil
+}
+
+func sendUndo(client *http.Client, undo *cmn.UndoRequest) error {
+	lg.Debugw("starting sendUndo()",
+		"method", undo.Method,
+		"url", undo.URL)
+	defer lg.Debug("finished sendUndo()")
+
+	req, err := http.NewRequest(undo.Method, undo.URL, bytes.NewReader(undo.Body))
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if undo.Body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	err = googleapi.CheckResponse(resp)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_CHANGEUNDONE, undo.Description)))
+	lg.Infof(gmess.INFO_CHANGEUNDONE, undo.Description)
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(undoCmd)
+
+	undoCmd.Flags().StringVar(&logLevel, flgnm.FLG_LOGLEVEL, "info", "log level (debug, info, error, warn)")
+	undoCmd.Flags().BoolVar(&silent, flgnm.FLG_SILENT, false, "suppress console output")
+
+	undoCmd.PreRunE = preRun
+}
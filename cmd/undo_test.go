@@ -0,0 +1,180 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+func TestUndoScopes(t *testing.T) {
+	// Each undoable object type needs its scope to be requested by undo
+	expected := map[string]string{
+		"buildings":          admin.AdminDirectoryResourceCalendarScope,
+		"calendar resources": admin.AdminDirectoryResourceCalendarScope,
+		"features":           admin.AdminDirectoryResourceCalendarScope,
+		"roles":              admin.AdminDirectoryRolemanagementScope,
+		"role assignments":   admin.AdminDirectoryRolemanagementScope,
+		"users":              admin.AdminDirectoryUserScope,
+	}
+	requested := map[string]bool{}
+	for _, scope := range undoAdminScopes {
+		requested[scope] = true
+	}
+	for objType, scope := range expected {
+		if !requested[scope] {
+			t.Errorf("Got scopes: %v - expected scope for %v: %v", undoAdminScopes, objType, scope)
+		}
+	}
+}
+
+func TestUndoFakeServer(t *testing.T) {
+	fs := newFakeServer(t)
+	fs.AddUser(&admin.User{PrimaryEmail: "mickey.mouse@disney.com", Aliases: []string{"mickey@disney.com"}, Name: &admin.UserName{GivenName: "Mickey", FamilyName: "Mouse"}})
+	fs.AddUser(&admin.User{PrimaryEmail: "donald.duck@disney.com", Name: &admin.UserName{GivenName: "Donald", FamilyName: "Duck"}})
+	fs.AddGroup(&admin.Group{Email: "cartoons@disney.com", Name: "Cartoons"})
+	fs.AddMember("cartoons@disney.com", &admin.Member{Email: "mickey.mouse@disney.com", Role: "OWNER"})
+	fs.AddCrOSDevice(&admin.ChromeOsDevice{DeviceId: "cros1", OrgUnitPath: "/"})
+
+	changes := [][]string{
+		{"delete", "user-alias", "mickey@disney.com", "mickey.mouse@disney.com"},
+		{"update", "user", "mickey.mouse@disney.com", "-f", "Michael", "-s"},
+		{"delete", "group-member", "mickey.mouse@disney.com", "cartoons@disney.com"},
+		{"manage", "group-settings", "cartoons@disney.com", "--join", "all_in_domain_can_join"},
+		{"delete", "user", "donald.duck@disney.com"},
+		{"create", "group", "villains@disney.com", "-n", "Villains"},
+		{"manage", "chromeos-device", "cros1", "disable"},
+	}
+	for _, args := range changes {
+		_, err := runGmin(t, args...)
+		if err != nil {
+			t.Fatalf("Got error: %v - expected error: nil", err)
+		}
+	}
+
+	out, err := runGmin(t, "history", "--output", "json")
+	if err != nil {
+		t.Fatalf("Got error: %v - expected error: nil", err)
+	}
+	var history struct {
+		Runs []cmn.JournalRun `json:"runs"`
+	}
+	err = json.Unmarshal([]byte(out), &history)
+	if err != nil {
+		t.Fatalf("Got error: %v - expected error: nil", err)
+	}
+	if len(history.Runs) != len(changes) {
+		t.Fatalf("Got runs: %v - expected runs: %v", len(history.Runs), len(changes))
+	}
+	if history.Runs[0].Command != "gmin manage chromeos-device cros1 disable" {
+		t.Errorf("Got command: %v - expected command: gmin manage chromeos-device cros1 disable", history.Runs[0].Command)
+	}
+	if history.Runs[len(changes)-1].Command != "gmin delete user-alias mickey@disney.com mickey.mouse@disney.com" {
+		t.Errorf("Got command: %v - expected command: gmin delete user-alias mickey@disney.com mickey.mouse@disney.com", history.Runs[len(changes)-1].Command)
+	}
+
+	cases := []struct {
+		args        []string
+		expectedErr string
+		expectedOut []string
+	}{
+		{
+			args:        []string{"undo", "20200101000000-000000"},
+			expectedErr: "run not found in journal: 20200101000000-000000",
+		},
+		{
+			args:        []string{"undo", history.Runs[0].RunID},
+			expectedErr: "1 of 1 changes could not be undone",
+			expectedOut: []string{"cannot undo POST customer/my_customer/devices/chromeos/cros1/action"},
+		},
+		{
+			args:        []string{"undo", history.Runs[1].RunID, "--dry-run"},
+			expectedOut: []string{"[dry run] would delete groups/"},
+		},
+		{
+			args:        []string{"undo", history.Runs[1].RunID},
+			expectedOut: []string{"change undone: delete groups/", "run undone: " + history.Runs[1].RunID},
+		},
+		{
+			args:        []string{"undo", history.Runs[2].RunID},
+			expectedOut: []string{"change undone: post users/"},
+		},
+		{
+			args:        []string{"undo", history.Runs[3].RunID},
+			expectedOut: []string{"change undone: patch groups/cartoons@disney.com"},
+		},
+		{
+			args:        []string{"undo", history.Runs[4].RunID},
+			expectedOut: []string{"change undone: post groups/cartoons@disney.com/members"},
+		},
+		{
+			args:        []string{"undo", history.Runs[5].RunID},
+			expectedOut: []string{"change undone: patch users/"},
+		},
+		{
+			args:        []string{"undo", history.Runs[6].RunID},
+			expectedOut: []string{"change undone: post users/mickey.mouse@disney.com/aliases"},
+		},
+	}
+
+	for _, c := range cases {
+		out, got := runGmin(t, c.args...)
+
+		gotErr := ""
+		if got != nil {
+			gotErr = got.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("Got error: %v - expected error: %v", gotErr, c.expectedErr)
+		}
+
+		for _, exp := range c.expectedOut {
+			if !strings.Contains(out, exp) {
+				t.Errorf("Got output: %v - expected output to contain: %v", out, exp)
+			}
+		}
+	}
+
+	if user := fs.User("mickey.mouse@disney.com"); user.Name.GivenName != "Mickey" || user.Suspended {
+		t.Errorf("Got user: %+v - expected user: Mickey and not suspended", user)
+	}
+	if user := fs.User("mickey.mouse@disney.com"); len(user.Aliases) != 1 || user.Aliases[0] != "mickey@disney.com" {
+		t.Errorf("Got aliases: %v - expected aliases: [mickey@disney.com]", user.Aliases)
+	}
+	if mem := fs.Member("cartoons@disney.com", "mickey.mouse@disney.com"); mem == nil || mem.Role != "OWNER" {
+		t.Errorf("Got member: %v - expected member: mickey.mouse@disney.com with role OWNER", mem)
+	}
+	if fs.GroupSettings["cartoons@disney.com"].WhoCanJoin != "CAN_REQUEST_TO_JOIN" {
+		t.Errorf("Got whoCanJoin: %v - expected whoCanJoin: CAN_REQUEST_TO_JOIN", fs.GroupSettings["cartoons@disney.com"].WhoCanJoin)
+	}
+	if fs.User("donald.duck@disney.com") == nil {
+		t.Error("Got user: nil - expected user: donald.duck@disney.com")
+	}
+	if fs.Group("villains@disney.com") != nil {
+		t.Error("Got group: villains@disney.com - expected group: nil")
+	}
+}
@@ -0,0 +1,104 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	cfg "github.com/plusworx/gmin/utils/config"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var updateBuildingCmd = &cobra.Command{
+	Use:     "building <building id>",
+	Aliases: []string{"bldg"},
+	Args:    cobra.ExactArgs(1),
+	Example: `gmin update building hq -n "Head Office" -d "Main office building"
+gmin upd bldg annex --floor-names 1~2`,
+	Short: "Updates a building",
+	Long: `Updates a building.
+
+Floor names given by the floor-names flag replace the existing floor names of the building.`,
+	RunE: doUpdateBuilding,
+}
+
+func doUpdateBuilding(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doUpdateBuilding()",
+		"args", args)
+	defer lg.Debug("finished doUpdateBuilding()")
+
+	var (
+		building    *admin.Building
+		flagsPassed []string
+	)
+
+	building = new(admin.Building)
+
+	// Collect names of command flags passed in
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		flagsPassed = append(flagsPassed, f.Name)
+	})
+
+	// Process command flags
+	err := processBuildingFlags(cmd, building, flagsPassed)
+	if err != nil {
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryResourceCalendarScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
+	if err != nil {
+		return err
+	}
+
+	rbpc := ds.Resources.Buildings.Patch(customerID, args[0], building)
+	_, err = rbpc.Do()
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_BUILDINGUPDATED, args[0])))
+	lg.Infof(gmess.INFO_BUILDINGUPDATED, args[0])
+
+	return nil
+}
+
+func init() {
+	updateCmd.AddCommand(updateBuildingCmd)
+
+	updateBuildingCmd.Flags().StringVarP(&resourceDesc, flgnm.FLG_DESCRIPTION, "d", "", "building description")
+	updateBuildingCmd.Flags().StringVar(&floorNames, flgnm.FLG_FLOORNAMES, "", "building floor names separated by (~)")
+	updateBuildingCmd.Flags().StringVarP(&resourceName, flgnm.FLG_NAME, "n", "", "building name")
+}
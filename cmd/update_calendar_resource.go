@@ -0,0 +1,112 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	cfg "github.com/plusworx/gmin/utils/config"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var updateCalResCmd = &cobra.Command{
+	Use:     "calendar-resource <resource id>",
+	Aliases: []string{"cal-resource", "calres", "cres"},
+	Args:    cobra.ExactArgs(1),
+	Example: `gmin update calendar-resource room1 -c 12 --features Whiteboard~VC
+gmin upd cres room1 --floor-section ""`,
+	Short: "Updates a calendar resource",
+	Long: `Updates a calendar resource.
+
+Features given by the features flag replace the existing features of the resource. Attributes can be cleared by
+providing an empty string.`,
+	RunE: doUpdateCalRes,
+}
+
+func doUpdateCalRes(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doUpdateCalRes()",
+		"args", args)
+	defer lg.Debug("finished doUpdateCalRes()")
+
+	var (
+		calendar    *admin.CalendarResource
+		flagsPassed []string
+	)
+
+	calendar = new(admin.CalendarResource)
+
+	// Collect names of command flags passed in
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		flagsPassed = append(flagsPassed, f.Name)
+	})
+
+	// Process command flags
+	err := processCalResFlags(cmd, calendar, flagsPassed)
+	if err != nil {
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryResourceCalendarScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
+	if err != nil {
+		return err
+	}
+
+	rcpc := ds.Resources.Calendars.Patch(customerID, args[0], calendar)
+	_, err = rcpc.Do()
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_CALRESUPDATED, args[0])))
+	lg.Infof(gmess.INFO_CALRESUPDATED, args[0])
+
+	return nil
+}
+
+func init() {
+	updateCmd.AddCommand(updateCalResCmd)
+
+	updateCalResCmd.Flags().StringVarP(&buildingID, flgnm.FLG_BUILDINGID, "b", "", "id of building containing the resource")
+	updateCalResCmd.Flags().Int64VarP(&capacity, flgnm.FLG_CAPACITY, "c", 0, "capacity of the resource")
+	updateCalResCmd.Flags().StringVar(&resourceCategory, flgnm.FLG_CATEGORY, "", "resource category")
+	updateCalResCmd.Flags().StringVarP(&resourceDesc, flgnm.FLG_DESCRIPTION, "d", "", "resource description for admins")
+	updateCalResCmd.Flags().StringVar(&features, flgnm.FLG_FEATURES, "", "resource feature names separated by (~)")
+	updateCalResCmd.Flags().StringVar(&floorName, flgnm.FLG_FLOORNAME, "", "name of floor containing the resource")
+	updateCalResCmd.Flags().StringVar(&floorSection, flgnm.FLG_FLOORSECTION, "", "name of floor section containing the resource")
+	updateCalResCmd.Flags().StringVarP(&resourceName, flgnm.FLG_NAME, "n", "", "resource name")
+	updateCalResCmd.Flags().StringVarP(&resourceType, flgnm.FLG_RESOURCETYPE, "t", "", "resource type")
+	updateCalResCmd.Flags().StringVarP(&userDesc, flgnm.FLG_USERDESC, "u", "", "resource description for users")
+}
@@ -0,0 +1,95 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	cfg "github.com/plusworx/gmin/utils/config"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var updateFeatureCmd = &cobra.Command{
+	Use:     "feature <feature name>",
+	Aliases: []string{"feat"},
+	Args:    cobra.ExactArgs(1),
+	Example: `gmin update feature Whiteboard -n "Interactive Whiteboard"
+gmin upd feat VC -n "Video Conferencing"`,
+	Short: "Renames a calendar resource feature",
+	Long: `Renames a calendar resource feature.
+
+The name is the only feature attribute that can be changed.`,
+	RunE: doUpdateFeature,
+}
+
+func doUpdateFeature(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doUpdateFeature()",
+		"args", args)
+	defer lg.Debug("finished doUpdateFeature()")
+
+	flgNameVal, err := cmd.Flags().GetString(flgnm.FLG_NAME)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	if flgNameVal == "" {
+		err = fmt.Errorf(gmess.ERR_EMPTYSTRING, "--"+flgnm.FLG_NAME)
+		lg.Error(err)
+		return err
+	}
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryResourceCalendarScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
+	if err != nil {
+		return err
+	}
+
+	rfrc := ds.Resources.Features.Rename(customerID, args[0], &admin.FeatureRename{NewName: flgNameVal})
+	err = rfrc.Do()
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_FEATURERENAMED, args[0], flgNameVal)))
+	lg.Infof(gmess.INFO_FEATURERENAMED, args[0], flgNameVal)
+
+	return nil
+}
+
+func init() {
+	updateCmd.AddCommand(updateFeatureCmd)
+
+	updateFeatureCmd.Flags().StringVarP(&resourceName, flgnm.FLG_NAME, "n", "", "new feature name")
+	updateFeatureCmd.MarkFlagRequired(flgnm.FLG_NAME)
+}
@@ -0,0 +1,158 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	cfg "github.com/plusworx/gmin/utils/config"
+	flgnm "github.com/plusworx/gmin/utils/flagnames"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	rls "github.com/plusworx/gmin/utils/roles"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var updateRoleCmd = &cobra.Command{
+	Use:  "role <role name or id>",
+	Args: cobra.ExactArgs(1),
+	Example: `gmin update role "Helpdesk Admin" -d "Password resets and user lookups"
+gmin upd role 123456789 -n "Group Admin" -p GROUPS_ALL`,
+	Short: "Updates a custom admin role",
+	Long: `Updates a custom admin role.
+
+Privileges given by the privileges flag replace the existing privileges of the role.`,
+	RunE: doUpdateRole,
+}
+
+func doUpdateRole(cmd *cobra.Command, args []string) error {
+	lg.Debugw("starting doUpdateRole()",
+		"args", args)
+	defer lg.Debug("finished doUpdateRole()")
+
+	var (
+		flagsPassed []string
+		role        *admin.Role
+	)
+
+	role = new(admin.Role)
+
+	// Collect names of command flags passed in
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		flagsPassed = append(flagsPassed, f.Name)
+	})
+
+	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryRolemanagementScope)
+	if err != nil {
+		return err
+	}
+	ds := srv.(*admin.Service)
+
+	customerID, err := cfg.ReadConfigString(cfg.CONFIGCUSTID)
+	if err != nil {
+		return err
+	}
+
+	// Process command flags
+	err = processUpdRoleFlags(cmd, ds, customerID, role, flagsPassed)
+	if err != nil {
+		return err
+	}
+
+	roleID, err := rls.RoleID(ds, customerID, args[0])
+	if err != nil {
+		return err
+	}
+
+	rpc := ds.Roles.Patch(customerID, roleID, role)
+	_, err = rpc.Do()
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_ROLEUPDATED, args[0])))
+	lg.Infof(gmess.INFO_ROLEUPDATED, args[0])
+
+	return nil
+}
+
+func init() {
+	updateCmd.AddCommand(updateRoleCmd)
+
+	updateRoleCmd.Flags().StringVarP(&roleDesc, flgnm.FLG_DESCRIPTION, "d", "", "role description")
+	updateRoleCmd.Flags().StringVarP(&roleName, flgnm.FLG_NAME, "n", "", "role name")
+	updateRoleCmd.Flags().StringVarP(&privileges, flgnm.FLG_PRIVILEGES, "p", "", "role privilege names separated by (~)")
+}
+
+func processUpdRoleFlags(cmd *cobra.Command, ds *admin.Service, customerID string, role *admin.Role, flagNames []string) error {
+	lg.Debugw("starting processUpdRoleFlags()",
+		"flagNames", flagNames)
+	defer lg.Debug("finished processUpdRoleFlags()")
+
+	for _, flName := range flagNames {
+		if flName == flgnm.FLG_DESCRIPTION {
+			flgDescriptionVal, err := cmd.Flags().GetString(flgnm.FLG_DESCRIPTION)
+			if err != nil {
+				lg.Error(err)
+				return err
+			}
+			if flgDescriptionVal == "" {
+				role.ForceSendFields = append(role.ForceSendFields, "RoleDescription")
+			}
+			role.RoleDescription = flgDescriptionVal
+		}
+		if flName == flgnm.FLG_NAME {
+			flgNameVal, err := cmd.Flags().GetString(flgnm.FLG_NAME)
+			if err != nil {
+				lg.Error(err)
+				return err
+			}
+			if flgNameVal == "" {
+				err = fmt.Errorf(gmess.ERR_EMPTYSTRING, "--"+flName)
+				lg.Error(err)
+				return err
+			}
+			role.RoleName = flgNameVal
+		}
+		if flName == flgnm.FLG_PRIVILEGES {
+			flgPrivsVal, err := cmd.Flags().GetString(flgnm.FLG_PRIVILEGES)
+			if err != nil {
+				lg.Error(err)
+				return err
+			}
+			serviceIDs, err := roleServiceIDs(ds, customerID)
+			if err != nil {
+				return err
+			}
+			role.RolePrivileges, err = rls.ParsePrivileges(flgPrivsVal, serviceIDs)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+func TestUpdateCmdsFakeServer(t *testing.T) {
+	fs := newFakeServer(t)
+	fs.AddUser(&admin.User{PrimaryEmail: "mickey.mouse@disney.com", Name: &admin.UserName{GivenName: "Mickey", FamilyName: "Mouse"}})
+	fs.AddGroup(&admin.Group{Email: "cartoons@disney.com", Name: "Cartoons"})
+	fs.AddMember("cartoons@disney.com", &admin.Member{Email: "mickey.mouse@disney.com"})
+	fs.AddOrgUnit(&admin.OrgUnit{Name: "Characters"})
+
+	cases := []struct {
+		args        []string
+		expectedErr string
+	}{
+		{
+			args: []string{"update", "user", "mickey.mouse@disney.com", "-f", "Michael", "-o", "/Characters", "-s"},
+		},
+		{
+			args: []string{"update", "group", "cartoons@disney.com", "-d", "Cartoon characters"},
+		},
+		{
+			args: []string{"update", "group-member", "mickey.mouse@disney.com", "cartoons@disney.com", "-r", "MANAGER"},
+		},
+		{
+			args: []string{"manage", "group-settings", "cartoons@disney.com", "--join", "all_in_domain_can_join"},
+		},
+		{
+			args:        []string{"update", "user", "minnie.mouse@disney.com", "-f", "Minerva"},
+			expectedErr: "googleapi: Error 404: Resource Not Found: minnie.mouse@disney.com, notFound",
+		},
+	}
+
+	for _, c := range cases {
+		_, got := runGmin(t, c.args...)
+
+		gotErr := ""
+		if got != nil {
+			gotErr = got.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("Got error: %v - expected error: %v", gotErr, c.expectedErr)
+		}
+	}
+
+	user := fs.User("mickey.mouse@disney.com")
+	if user.Name.GivenName != "Michael" || user.Name.FamilyName != "Mouse" || user.OrgUnitPath != "/Characters" || !user.Suspended {
+		t.Errorf("Got user: %v %v %v %v - expected user: Michael Mouse /Characters true", user.Name.GivenName, user.Name.FamilyName, user.OrgUnitPath, user.Suspended)
+	}
+
+	group := fs.Group("cartoons@disney.com")
+	if group.Description != "Cartoon characters" {
+		t.Errorf("Got group description: %v - expected group description: Cartoon characters", group.Description)
+	}
+
+	member := fs.Member("cartoons@disney.com", "mickey.mouse@disney.com")
+	if member.Role != "MANAGER" {
+		t.Errorf("Got member role: %v - expected member role: MANAGER", member.Role)
+	}
+
+	settings := fs.GroupSettings["cartoons@disney.com"]
+	if settings.WhoCanJoin != "ALL_IN_DOMAIN_CAN_JOIN" {
+		t.Errorf("Got whoCanJoin: %v - expected whoCanJoin: ALL_IN_DOMAIN_CAN_JOIN", settings.WhoCanJoin)
+	}
+}
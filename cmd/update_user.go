@@ -32,6 +32,7 @@ import (
 	flgnm "github.com/plusworx/gmin/utils/flagnames"
 	gmess "github.com/plusworx/gmin/utils/gminmessages"
 	lg "github.com/plusworx/gmin/utils/logging"
+	pwds "github.com/plusworx/gmin/utils/passwords"
 	usrs "github.com/plusworx/gmin/utils/users"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -45,8 +46,11 @@ var updateUserCmd = &cobra.Command{
 	Example: `gmin update user another.user@mycompany.com -p strongpassword -s
 gmin upd user finance.person@mycompany.com -l Newlastname`,
 	Short: "Updates a user",
-	Long:  `Updates a user.`,
-	RunE:  doUpdateUser,
+	Long: `Updates a user.
+
+The password is hashed by the hasher given by --hasher, the hasher config value or crypt (SHA-512) by default. An
+already hashed password can be given in the attributes along with its hashFunction (crypt, MD5 or SHA-1).`,
+	RunE: doUpdateUser,
 }
 
 func doUpdateUser(cmd *cobra.Command, args []string) error {
@@ -121,6 +125,11 @@ func doUpdateUser(cmd *cobra.Command, args []string) error {
 			attrUser.ForceSendFields = emptyVals.ForceSendFields
 		}
 
+		// A hash function only applies to a password given in the attributes
+		if user.Password != "" {
+			attrUser.HashFunction = ""
+		}
+
 		err = mergo.Merge(user, attrUser)
 		if err != nil {
 			lg.Error(err)
@@ -128,6 +137,16 @@ func doUpdateUser(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	hasher, err := passwordHasher(cmd)
+	if err != nil {
+		return err
+	}
+
+	err = pwds.Prepare(user, hasher)
+	if err != nil {
+		return err
+	}
+
 	srv, err := cmn.CreateService(cmn.SRVTYPEADMIN, admin.AdminDirectoryUserScope)
 	if err != nil {
 		return err
@@ -156,6 +175,7 @@ func init() {
 	updateUserCmd.Flags().StringVarP(&firstName, flgnm.FLG_FIRSTNAME, "f", "", "user's first name")
 	updateUserCmd.Flags().StringVar(&forceSend, flgnm.FLG_FORCE, "", "field list for ForceSendFields separated by (~)")
 	updateUserCmd.Flags().BoolVarP(&gal, flgnm.FLG_GAL, "g", false, "display user in Global Address List")
+	updateUserCmd.Flags().StringVar(&hasher, flgnm.FLG_HASHER, "", "password hasher (crypt, md5 or sha-1)")
 	updateUserCmd.Flags().StringVarP(&lastName, flgnm.FLG_LASTNAME, "l", "", "user's last name")
 	updateUserCmd.Flags().StringVarP(&orgUnit, flgnm.FLG_ORGUNIT, "o", "", "user's orgunit")
 	updateUserCmd.Flags().StringVarP(&password, flgnm.FLG_PASSWORD, "p", "", "user's password")
@@ -380,18 +400,12 @@ func uuOrgunitFlag(user *admin.User, flagName string, flgVal string) error {
 
 func uuPasswordFlag(user *admin.User, flagName string, flgVal string) error {
 	lg.Debugw("starting uuPasswordFlag()",
-		"flagName", flagName,
-		"flgVal", flgVal)
+		"flagName", flagName)
 	if flgVal == "" {
 		err := fmt.Errorf(gmess.ERR_EMPTYSTRING, flagName)
 		return err
 	}
-	pwd, err := usrs.HashPassword(flgVal)
-	if err != nil {
-		return err
-	}
-	user.Password = pwd
-	user.HashFunction = usrs.HASHFUNCTION
+	user.Password = flgVal
 	lg.Debug("finished uuPasswordFlag()")
 	return nil
 }
@@ -0,0 +1,138 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+func TestUserPhotoCmdsFakeServer(t *testing.T) {
+	fs := newFakeServer(t)
+	fs.AddUser(&admin.User{PrimaryEmail: "mickey.mouse@disney.com", Name: &admin.UserName{GivenName: "Mickey", FamilyName: "Mouse"}})
+	fs.AddUser(&admin.User{PrimaryEmail: "donald.duck@disney.com", Name: &admin.UserName{GivenName: "Donald", FamilyName: "Duck"}})
+
+	var buf bytes.Buffer
+	png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 300, 150)))
+
+	dir := t.TempDir()
+	photoDir := filepath.Join(dir, "photos")
+	os.Mkdir(photoDir, 0755)
+	ioutil.WriteFile(filepath.Join(photoDir, "mickey.mouse@disney.com.png"), buf.Bytes(), 0644)
+	ioutil.WriteFile(filepath.Join(photoDir, "donald.duck@disney.com.jpg"), buf.Bytes(), 0644)
+	ioutil.WriteFile(filepath.Join(photoDir, "goofy@disney.com.png"), buf.Bytes(), 0644)
+	ioutil.WriteFile(filepath.Join(photoDir, "notes.txt"), []byte("not a photo"), 0644)
+	single := filepath.Join(dir, "single.png")
+	ioutil.WriteFile(single, buf.Bytes(), 0644)
+	saved := filepath.Join(dir, "saved.jpg")
+	emptyDir := t.TempDir()
+
+	cases := []struct {
+		args        []string
+		expectedErr string
+	}{
+		{
+			args: []string{"manage", "user", "mickey.mouse@disney.com", "make-admin"},
+		},
+		{
+			args: []string{"batch-manage", "users", "make-admin", "-i", filepath.Join(dir, "admins.txt"), "--results-dir", t.TempDir()},
+		},
+		{
+			args: []string{"manage", "user", "donald.duck@disney.com", "revoke-admin"},
+		},
+		{
+			args: []string{"set", "user-photo", "donald.duck@disney.com", single},
+		},
+		{
+			args:        []string{"batch-update", "user-photos", "-d", photoDir, "--results-dir", t.TempDir()},
+			expectedErr: "1 of 3 batch rows failed",
+		},
+		{
+			args: []string{"get", "user-photo", "mickey.mouse@disney.com", "--file", saved},
+		},
+		{
+			args: []string{"delete", "user-photo", "donald.duck@disney.com"},
+		},
+		{
+			args:        []string{"batch-update", "user-photos", "-d", emptyDir, "--results-dir", t.TempDir()},
+			expectedErr: "no photo files found in directory: " + emptyDir,
+		},
+	}
+
+	ioutil.WriteFile(filepath.Join(dir, "admins.txt"), []byte("donald.duck@disney.com\n"), 0644)
+
+	for _, c := range cases {
+		_, got := runGmin(t, c.args...)
+
+		gotErr := ""
+		if got != nil {
+			gotErr = got.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("Got error: %v - expected error: %v", gotErr, c.expectedErr)
+		}
+	}
+
+	if !fs.User("mickey.mouse@disney.com").IsAdmin {
+		t.Error("Got mickey admin: false - expected mickey admin: true")
+	}
+	if fs.User("donald.duck@disney.com").IsAdmin {
+		t.Error("Got donald admin: true - expected donald admin: false")
+	}
+
+	photo, ok := fs.Photos["mickey.mouse@disney.com"]
+	if !ok {
+		t.Fatal("Got mickey photo: nil - expected mickey photo")
+	}
+	if photo.Width != 96 || photo.Height != 48 {
+		t.Errorf("Got photo size: %vx%v - expected photo size: 96x48", photo.Width, photo.Height)
+	}
+	if _, ok := fs.Photos["donald.duck@disney.com"]; ok {
+		t.Error("Got donald photo present - expected donald photo to be deleted")
+	}
+
+	imgData, err := ioutil.ReadFile(saved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(imgData)); err != nil {
+		t.Errorf("Got saved photo error: %v - expected JPEG image", err)
+	}
+
+	out, err := runGmin(t, "get", "user-photo", "mickey.mouse@disney.com", "-a", "height~width")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, `"width": 96`) || strings.Contains(out, "photoData") {
+		t.Errorf("Got photo output: %v - expected only height and width", out)
+	}
+}
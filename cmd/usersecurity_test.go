@@ -0,0 +1,156 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+func TestUserSecurityCmdsFakeServer(t *testing.T) {
+	fs := newFakeServer(t)
+	fs.AddUser(&admin.User{PrimaryEmail: "mickey.mouse@disney.com", Name: &admin.UserName{GivenName: "Mickey", FamilyName: "Mouse"}})
+	fs.AddUser(&admin.User{PrimaryEmail: "donald.duck@disney.com", Name: &admin.UserName{GivenName: "Donald", FamilyName: "Duck"}})
+	fs.AddToken("mickey.mouse@disney.com", &admin.Token{ClientId: "111.apps.googleusercontent.com", DisplayText: "Calendar Sync", Scopes: []string{"https://www.googleapis.com/auth/calendar"}})
+	fs.AddToken("mickey.mouse@disney.com", &admin.Token{ClientId: "222.apps.googleusercontent.com", DisplayText: "Mail Merge"})
+	fs.AddToken("donald.duck@disney.com", &admin.Token{ClientId: "111.apps.googleusercontent.com", DisplayText: "Calendar Sync"})
+	fs.AddToken("donald.duck@disney.com", &admin.Token{ClientId: "333.apps.googleusercontent.com", DisplayText: "Drive Backup"})
+	fs.AddAsp("mickey.mouse@disney.com", &admin.Asp{CodeId: 1, Name: "Thunderbird"})
+	fs.AddAsp("donald.duck@disney.com", &admin.Asp{CodeId: 2, Name: "Outlook"})
+	fs.AddAsp("donald.duck@disney.com", &admin.Asp{CodeId: 3, Name: "iPhone Mail"})
+
+	dir := t.TempDir()
+	users := filepath.Join(dir, "users.txt")
+	ioutil.WriteFile(users, []byte("mickey.mouse@disney.com\ndonald.duck@disney.com\n"), 0644)
+
+	cases := []struct {
+		args        []string
+		expectedErr string
+	}{
+		{
+			args: []string{"manage", "user", "mickey.mouse@disney.com", "signout"},
+		},
+		{
+			args:        []string{"manage", "user", "mickey.mouse@disney.com", "suspend"},
+			expectedErr: "invalid action type: suspend",
+		},
+		{
+			args: []string{"delete", "token", "mickey.mouse@disney.com", "222.apps.googleusercontent.com"},
+		},
+		{
+			args: []string{"batch-delete", "tokens", "-i", users, "--client-id", "111.apps.googleusercontent.com", "--results-dir", t.TempDir()},
+		},
+		{
+			args: []string{"delete", "app-password", "mickey.mouse@disney.com", "1"},
+		},
+		{
+			args:        []string{"delete", "asp", "mickey.mouse@disney.com", "one"},
+			expectedErr: "code id must be a number: one",
+		},
+		{
+			args: []string{"batch-delete", "app-passwords", "-i", users, "--results-dir", t.TempDir()},
+		},
+		{
+			args: []string{"batch-manage", "users", "signout", "-i", users, "--results-dir", t.TempDir()},
+		},
+		{
+			args: []string{"batch-manage", "backup-codes", "generate", "-i", users, "--results-dir", t.TempDir()},
+		},
+		{
+			args: []string{"manage", "backup-codes", "donald.duck@disney.com", "invalidate"},
+		},
+		{
+			args:        []string{"manage", "bcodes", "donald.duck@disney.com", "print"},
+			expectedErr: "invalid action type: print",
+		},
+	}
+
+	for _, c := range cases {
+		_, got := runGmin(t, c.args...)
+
+		gotErr := ""
+		if got != nil {
+			gotErr = got.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("Got error: %v - expected error: %v", gotErr, c.expectedErr)
+		}
+	}
+
+	if len(fs.SignOuts) != 3 {
+		t.Errorf("Got sign outs: %v - expected sign outs: 3", len(fs.SignOuts))
+	}
+	if len(fs.Tokens["mickey.mouse@disney.com"]) != 0 {
+		t.Errorf("Got mickey tokens: %v - expected mickey tokens: 0", len(fs.Tokens["mickey.mouse@disney.com"]))
+	}
+	donaldToks := fs.Tokens["donald.duck@disney.com"]
+	if len(donaldToks) != 1 || donaldToks[0].ClientId != "333.apps.googleusercontent.com" {
+		t.Errorf("Got donald tokens: %v - expected donald tokens: 333.apps.googleusercontent.com", donaldToks)
+	}
+	if len(fs.Asps["mickey.mouse@disney.com"])+len(fs.Asps["donald.duck@disney.com"]) != 0 {
+		t.Error("Got app passwords still present - expected all app passwords to be deleted")
+	}
+	if len(fs.VerCodes["mickey.mouse@disney.com"]) != 10 {
+		t.Errorf("Got mickey backup codes: %v - expected mickey backup codes: 10", len(fs.VerCodes["mickey.mouse@disney.com"]))
+	}
+	if len(fs.VerCodes["donald.duck@disney.com"]) != 0 {
+		t.Errorf("Got donald backup codes: %v - expected donald backup codes: 0", len(fs.VerCodes["donald.duck@disney.com"]))
+	}
+
+	out, err := runGmin(t, "list", "backup-codes", "mickey.mouse@disney.com", "--count")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(out) != "10" {
+		t.Errorf("Got backup code count: %v - expected backup code count: 10", strings.TrimSpace(out))
+	}
+
+	fs.AddToken("mickey.mouse@disney.com", &admin.Token{ClientId: "444.apps.googleusercontent.com", DisplayText: "Slides Helper"})
+	out, err = runGmin(t, "get", "token", "mickey.mouse@disney.com", "444.apps.googleusercontent.com", "-a", "displaytext")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "Slides Helper") || strings.Contains(out, "clientId") {
+		t.Errorf("Got token output: %v - expected only displayText: Slides Helper", out)
+	}
+
+	out, err = runGmin(t, "list", "tokens", "mickey.mouse@disney.com", "-a", "clientid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "444.apps.googleusercontent.com") || strings.Contains(out, "Slides Helper") {
+		t.Errorf("Got token list: %v - expected only clientId: 444.apps.googleusercontent.com", out)
+	}
+
+	_, err = runGmin(t, "batch-delete", "tokens", "-i", users, "--results-dir", t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs.Tokens["mickey.mouse@disney.com"])+len(fs.Tokens["donald.duck@disney.com"]) != 0 {
+		t.Error("Got tokens still present - expected all tokens to be deleted")
+	}
+}
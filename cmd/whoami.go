@@ -38,8 +38,9 @@ import (
 var whoamiCmd = &cobra.Command{
 	Use:   "whoami",
 	Short: "Displays the email address of the user impersonated by the service account",
-	Long:  `Displays the email address of the user impersonated by the service account.`,
-	RunE:  doWhoami,
+	Long: `Displays the email address of the user impersonated by the service account. If a profile is
+being used then the profile name and default domain are also displayed.`,
+	RunE: doWhoami,
 }
 
 func doWhoami(cmd *cobra.Command, args []string) error {
@@ -50,8 +51,10 @@ func doWhoami(cmd *cobra.Command, args []string) error {
 	var err error
 
 	email := os.Getenv(cfg.ENVPREFIX + cfg.ENVVARADMIN)
+	profile := cfg.ActiveProfile()
 
-	if email == "" {
+	// Profile values take precedence over environment variables
+	if email == "" || profile != "" {
 		email, err = cfg.ReadConfigString(cfg.CONFIGADMIN)
 		if err != nil {
 			return err
@@ -60,6 +63,15 @@ func doWhoami(cmd *cobra.Command, args []string) error {
 
 	fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_ADMINIS, email)))
 
+	if profile != "" {
+		fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_PROFILEIS, profile)))
+
+		domain := cfg.GetString(cfg.CONFIGDOMAIN)
+		if domain != "" {
+			fmt.Println(cmn.GminMessage(fmt.Sprintf(gmess.INFO_DOMAINIS, domain)))
+		}
+	}
+
 	return nil
 }
 
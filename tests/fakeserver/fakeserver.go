@@ -0,0 +1,2200 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+// Package fakeserver provides an in-memory fake of the Admin SDK Directory and Reports, Groups
+// Settings and Sheets APIs used by gmin so that commands can be tested without a live Google Workspace
+// tenant. Point gmin at it by setting the endpoint config value (or GMIN_ENDPOINT environment
+// variable) to the server URL.
+package fakeserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	admin "google.golang.org/api/admin/directory/v1"
+	reports "google.golang.org/api/admin/reports/v1"
+	gset "google.golang.org/api/groupssettings/v1"
+)
+
+const (
+	// DIRECTORYPATH is Directory API path prefix
+	DIRECTORYPATH string = "/admin/directory/v1/"
+	// GRPSETTINGSPATH is Groups Settings API path prefix
+	GRPSETTINGSPATH string = "/groups/v1/groups/"
+	// REPORTSPATH is Reports API path prefix
+	REPORTSPATH string = "/admin/reports/v1/"
+	// SHEETSPATH is Sheets API path prefix
+	SHEETSPATH string = "/v4/spreadsheets/"
+)
+
+// Server is a fake Google API server with an in-memory store
+type Server struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	nextID int
+
+	// Activities holds audit activities in the order that they were added
+	Activities []*reports.Activity
+	// Asps holds app passwords keyed by lowercase user primary email address
+	Asps map[string][]*admin.Asp
+	// Buildings holds buildings keyed by building id
+	Buildings map[string]*admin.Building
+	// CalResources holds calendar resources keyed by resource id
+	CalResources map[string]*admin.CalendarResource
+	// CrOSDevices holds ChromeOS devices keyed by device id
+	CrOSDevices map[string]*admin.ChromeOsDevice
+	// DeletedUsers holds deleted users keyed by user id
+	DeletedUsers map[string]*admin.User
+	// DomainAliases holds domain aliases keyed by lowercase domain alias name
+	DomainAliases map[string]*admin.DomainAlias
+	// Domains holds domains keyed by lowercase domain name
+	Domains map[string]*admin.Domains
+	// Features holds calendar resource features keyed by feature name
+	Features map[string]*admin.Feature
+	// Groups holds groups keyed by lowercase email address
+	Groups map[string]*admin.Group
+	// GroupSettings holds group settings keyed by lowercase group email address
+	GroupSettings map[string]*gset.Groups
+	// Members holds group members keyed by lowercase group email address then member email address
+	Members map[string]map[string]*admin.Member
+	// MobDevices holds mobile devices keyed by resource id
+	MobDevices map[string]*admin.MobileDevice
+	// OrgUnits holds orgunits keyed by orgunit path
+	OrgUnits map[string]*admin.OrgUnit
+	// Photos holds user photos keyed by lowercase user primary email address
+	Photos map[string]*admin.UserPhoto
+	// Privileges holds the privileges that can be given to roles
+	Privileges []*admin.Privilege
+	// Requests records method and path of every request received
+	Requests []string
+	// RoleAssignments holds role assignments keyed by role assignment id
+	RoleAssignments map[string]*admin.RoleAssignment
+	// Roles holds roles keyed by role id
+	Roles map[string]*admin.Role
+	// Schemas holds schemas keyed by schema name
+	Schemas map[string]*admin.Schema
+	// Sheets holds sheet values keyed by spreadsheet id and then range
+	Sheets map[string]map[string][][]interface{}
+	// SignOuts records the lowercase primary email address of every user signed out
+	SignOuts []string
+	// Tokens holds OAuth tokens keyed by lowercase user primary email address
+	Tokens map[string][]*admin.Token
+	// UsageReports holds customer and user usage reports in the order that they were added
+	UsageReports []*reports.UsageReport
+	// Users holds users keyed by lowercase primary email address
+	Users map[string]*admin.User
+	// VerCodes holds backup verification codes keyed by lowercase user primary email address
+	VerCodes map[string][]*admin.VerificationCode
+}
+
+// New creates and starts a fake server containing the root orgunit
+func New() *Server {
+	fs := &Server{
+		Asps:            map[string][]*admin.Asp{},
+		Buildings:       map[string]*admin.Building{},
+		CalResources:    map[string]*admin.CalendarResource{},
+		CrOSDevices:     map[string]*admin.ChromeOsDevice{},
+		DeletedUsers:    map[string]*admin.User{},
+		DomainAliases:   map[string]*admin.DomainAlias{},
+		Domains:         map[string]*admin.Domains{},
+		Features:        map[string]*admin.Feature{},
+		Groups:          map[string]*admin.Group{},
+		GroupSettings:   map[string]*gset.Groups{},
+		Members:         map[string]map[string]*admin.Member{},
+		MobDevices:      map[string]*admin.MobileDevice{},
+		OrgUnits:        map[string]*admin.OrgUnit{},
+		Photos:          map[string]*admin.UserPhoto{},
+		RoleAssignments: map[string]*admin.RoleAssignment{},
+		Roles:           map[string]*admin.Role{},
+		Schemas:         map[string]*admin.Schema{},
+		Sheets:          map[string]map[string][][]interface{}{},
+		Tokens:          map[string][]*admin.Token{},
+		Users:           map[string]*admin.User{},
+		VerCodes:        map[string][]*admin.VerificationCode{},
+	}
+	fs.OrgUnits["/"] = &admin.OrgUnit{Name: "/", OrgUnitId: "id:root", OrgUnitPath: "/"}
+	fs.Server = httptest.NewServer(http.HandlerFunc(fs.serveHTTP))
+	return fs
+}
+
+// AddActivity adds an audit activity to the store
+func (fs *Server) AddActivity(activity *reports.Activity) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.Activities = append(fs.Activities, activity)
+}
+
+// AddAsp adds an app password for a user to the store
+func (fs *Server) AddAsp(userEmail string, asp *admin.Asp) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	email := strings.ToLower(userEmail)
+	asp.UserKey = email
+	fs.Asps[email] = append(fs.Asps[email], asp)
+}
+
+// AddBuilding adds a building to the store
+func (fs *Server) AddBuilding(building *admin.Building) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.Buildings[building.BuildingId] = building
+}
+
+// AddCalResource adds a calendar resource to the store
+func (fs *Server) AddCalResource(calendar *admin.CalendarResource) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.insertCalResource(calendar)
+}
+
+// AddCrOSDevice adds a ChromeOS device to the store
+func (fs *Server) AddCrOSDevice(dev *admin.ChromeOsDevice) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if dev.DeviceId == "" {
+		dev.DeviceId = fs.newID()
+	}
+	fs.CrOSDevices[dev.DeviceId] = dev
+}
+
+// AddDomain adds a verified domain to the store
+func (fs *Server) AddDomain(domain *admin.Domains) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	domain.Kind = "admin#directory#domain"
+	domain.Verified = true
+	fs.Domains[strings.ToLower(domain.DomainName)] = domain
+}
+
+// AddDomainAlias adds a verified domain alias to the store
+func (fs *Server) AddDomainAlias(alias *admin.DomainAlias) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	alias.Kind = "admin#directory#domainAlias"
+	alias.Verified = true
+	fs.DomainAliases[strings.ToLower(alias.DomainAliasName)] = alias
+}
+
+// AddFeature adds a calendar resource feature to the store
+func (fs *Server) AddFeature(feature *admin.Feature) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.Features[feature.Name] = feature
+}
+
+// AddGroup adds a group and default group settings to the store
+func (fs *Server) AddGroup(group *admin.Group) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.insertGroup(group)
+}
+
+// AddMember adds a member to a group in the store
+func (fs *Server) AddMember(groupEmail string, member *admin.Member) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.insertMember(strings.ToLower(groupEmail), member)
+}
+
+// AddMobDevice adds a mobile device to the store
+func (fs *Server) AddMobDevice(dev *admin.MobileDevice) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if dev.ResourceId == "" {
+		dev.ResourceId = fs.newID()
+	}
+	fs.MobDevices[dev.ResourceId] = dev
+}
+
+// AddOrgUnit adds an orgunit to the store
+func (fs *Server) AddOrgUnit(ou *admin.OrgUnit) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.insertOrgUnit(ou)
+}
+
+// AddPrivilege adds a privilege that can be given to roles
+func (fs *Server) AddPrivilege(priv *admin.Privilege) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	priv.Kind = "admin#directory#privilege"
+	fs.Privileges = append(fs.Privileges, priv)
+}
+
+// AddRole adds a role to the store
+func (fs *Server) AddRole(role *admin.Role) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.insertRole(role)
+}
+
+// AddSheet adds sheet values for a spreadsheet id and range
+func (fs *Server) AddSheet(sheetID string, sheetRange string, values [][]interface{}) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if fs.Sheets[sheetID] == nil {
+		fs.Sheets[sheetID] = map[string][][]interface{}{}
+	}
+	fs.Sheets[sheetID][sheetRange] = values
+}
+
+// AddToken adds an OAuth token for a user to the store
+func (fs *Server) AddToken(userEmail string, token *admin.Token) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	email := strings.ToLower(userEmail)
+	token.UserKey = email
+	fs.Tokens[email] = append(fs.Tokens[email], token)
+}
+
+// AddUsageReport adds a customer or user usage report to the store
+func (fs *Server) AddUsageReport(usageReport *reports.UsageReport) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.UsageReports = append(fs.UsageReports, usageReport)
+}
+
+// AddUser adds a user to the store
+func (fs *Server) AddUser(user *admin.User) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.insertUser(user)
+}
+
+// Group returns a copy of stored group or nil if it does not exist
+func (fs *Server) Group(key string) *admin.Group {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	return fs.findGroup(key)
+}
+
+// Member returns stored group member or nil if it does not exist
+func (fs *Server) Member(groupKey string, memberKey string) *admin.Member {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	group := fs.findGroup(groupKey)
+	if group == nil {
+		return nil
+	}
+	return fs.findMember(group.Email, memberKey)
+}
+
+// Role returns stored role with the given name or id or nil if it does not exist
+func (fs *Server) Role(key string) *admin.Role {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	return fs.findRole(key)
+}
+
+// User returns stored user or nil if it does not exist
+func (fs *Server) User(key string) *admin.User {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	return fs.findUser(key)
+}
+
+func (fs *Server) findGroup(key string) *admin.Group {
+	lwrKey := strings.ToLower(key)
+	if group, ok := fs.Groups[lwrKey]; ok {
+		return group
+	}
+	for _, group := range fs.Groups {
+		if group.Id == key || containsFold(group.Aliases, key) {
+			return group
+		}
+	}
+	return nil
+}
+
+func (fs *Server) findMember(groupEmail string, key string) *admin.Member {
+	mems := fs.Members[strings.ToLower(groupEmail)]
+	if member, ok := mems[strings.ToLower(key)]; ok {
+		return member
+	}
+	for _, member := range mems {
+		if member.Id == key {
+			return member
+		}
+	}
+	return nil
+}
+
+func (fs *Server) findOrgUnit(key string) *admin.OrgUnit {
+	if strings.HasPrefix(key, "id:") {
+		for _, ou := range fs.OrgUnits {
+			if ou.OrgUnitId == key {
+				return ou
+			}
+		}
+		return nil
+	}
+	if !strings.HasPrefix(key, "/") {
+		key = "/" + key
+	}
+	return fs.OrgUnits[key]
+}
+
+func (fs *Server) findRole(key string) *admin.Role {
+	if role, ok := fs.Roles[key]; ok {
+		return role
+	}
+	for _, role := range fs.Roles {
+		if strings.EqualFold(role.RoleName, key) {
+			return role
+		}
+	}
+	return nil
+}
+
+func (fs *Server) findSchema(key string) *admin.Schema {
+	if schema, ok := fs.Schemas[key]; ok {
+		return schema
+	}
+	for _, schema := range fs.Schemas {
+		if schema.SchemaId == key {
+			return schema
+		}
+	}
+	return nil
+}
+
+func (fs *Server) findUser(key string) *admin.User {
+	lwrKey := strings.ToLower(key)
+	if user, ok := fs.Users[lwrKey]; ok {
+		return user
+	}
+	for _, user := range fs.Users {
+		if user.Id == key || containsFold(user.Aliases, key) {
+			return user
+		}
+	}
+	return nil
+}
+
+func (fs *Server) insertCalResource(calendar *admin.CalendarResource) {
+	calendar.Kind = "admin#directory#resources#calendars#CalendarResource"
+	calendar.ResourceEmail = "c_" + calendar.ResourceId + "@resource.calendar.google.com"
+	calendar.GeneratedResourceName = calendar.ResourceName
+	fs.CalResources[calendar.ResourceId] = calendar
+}
+
+func (fs *Server) insertGroup(group *admin.Group) {
+	if group.Id == "" {
+		group.Id = fs.newID()
+	}
+	group.Kind = "admin#directory#group"
+	group.AdminCreated = true
+	email := strings.ToLower(group.Email)
+	fs.Groups[email] = group
+	if fs.GroupSettings[email] == nil {
+		fs.GroupSettings[email] = &gset.Groups{
+			Email:             group.Email,
+			Name:              group.Name,
+			Description:       group.Description,
+			WhoCanJoin:        "CAN_REQUEST_TO_JOIN",
+			WhoCanPostMessage: "ANYONE_CAN_POST",
+			Kind:              "groupsSettings#groups",
+		}
+	}
+	if fs.Members[email] == nil {
+		fs.Members[email] = map[string]*admin.Member{}
+	}
+}
+
+func (fs *Server) insertMember(groupEmail string, member *admin.Member) {
+	if fs.Members[groupEmail] == nil {
+		fs.Members[groupEmail] = map[string]*admin.Member{}
+	}
+	if member.Id == "" {
+		if user := fs.findUser(member.Email); user != nil {
+			member.Id = user.Id
+		} else {
+			member.Id = fs.newID()
+		}
+	}
+	if member.Role == "" {
+		member.Role = "MEMBER"
+	}
+	if member.Type == "" {
+		member.Type = "USER"
+		if fs.findGroup(member.Email) != nil {
+			member.Type = "GROUP"
+		}
+	}
+	if member.Status == "" {
+		member.Status = "ACTIVE"
+	}
+	member.Kind = "admin#directory#member"
+	fs.Members[groupEmail][strings.ToLower(member.Email)] = member
+	if group := fs.Groups[groupEmail]; group != nil {
+		group.DirectMembersCount = int64(len(fs.Members[groupEmail]))
+	}
+}
+
+func (fs *Server) insertOrgUnit(ou *admin.OrgUnit) {
+	if ou.OrgUnitId == "" {
+		ou.OrgUnitId = "id:" + fs.newID()
+	}
+	if ou.ParentOrgUnitPath == "" {
+		ou.ParentOrgUnitPath = "/"
+	}
+	if ou.OrgUnitPath == "" {
+		ou.OrgUnitPath = strings.TrimSuffix(ou.ParentOrgUnitPath, "/") + "/" + ou.Name
+	}
+	if parent := fs.OrgUnits[ou.ParentOrgUnitPath]; parent != nil {
+		ou.ParentOrgUnitId = parent.OrgUnitId
+	}
+	ou.Kind = "admin#directory#orgUnit"
+	fs.OrgUnits[ou.OrgUnitPath] = ou
+}
+
+func (fs *Server) insertRole(role *admin.Role) {
+	if role.RoleId == 0 {
+		role.RoleId, _ = strconv.ParseInt(fs.newID(), 10, 64)
+	}
+	role.Kind = "admin#directory#role"
+	fs.Roles[strconv.FormatInt(role.RoleId, 10)] = role
+}
+
+func (fs *Server) insertUser(user *admin.User) {
+	if user.Id == "" {
+		user.Id = fs.newID()
+	}
+	if user.OrgUnitPath == "" {
+		user.OrgUnitPath = "/"
+	}
+	if user.Name != nil && user.Name.FullName == "" {
+		user.Name.FullName = strings.TrimSpace(user.Name.GivenName + " " + user.Name.FamilyName)
+	}
+	user.Kind = "admin#directory#user"
+	fs.Users[strings.ToLower(user.PrimaryEmail)] = user
+}
+
+func (fs *Server) newID() string {
+	fs.nextID++
+	return strconv.Itoa(100000000000000000 + fs.nextID)
+}
+
+func (fs *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	path := r.URL.EscapedPath()
+	fs.Requests = append(fs.Requests, r.Method+" "+path)
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "badRequest", err.Error())
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(path, DIRECTORYPATH):
+		fs.serveDirectory(w, r, splitPath(strings.TrimPrefix(path, DIRECTORYPATH)), body)
+	case strings.HasPrefix(path, REPORTSPATH):
+		fs.serveReports(w, r, splitPath(strings.TrimPrefix(path, REPORTSPATH)))
+	case strings.HasPrefix(path, GRPSETTINGSPATH):
+		fs.serveGroupSettings(w, r, strings.TrimPrefix(path, GRPSETTINGSPATH), body)
+	case strings.HasPrefix(path, SHEETSPATH):
+		fs.serveSheets(w, r, splitPath(strings.TrimPrefix(path, SHEETSPATH)))
+	default:
+		writeError(w, http.StatusNotFound, "notFound", "unknown path: "+path)
+	}
+}
+
+func (fs *Server) serveDirectory(w http.ResponseWriter, r *http.Request, segs []string, body []byte) {
+	if len(segs) == 0 {
+		writeError(w, http.StatusNotFound, "notFound", "missing resource")
+		return
+	}
+
+	switch segs[0] {
+	case "customer":
+		if len(segs) < 3 {
+			writeError(w, http.StatusNotFound, "notFound", "missing customer resource")
+			return
+		}
+		switch segs[2] {
+		case "devices":
+			if len(segs) > 3 && segs[3] == "chromeos" {
+				fs.serveCrOSDevices(w, r, segs[4:], body)
+				return
+			}
+			if len(segs) > 3 && segs[3] == "mobile" {
+				fs.serveMobDevices(w, r, segs[4:], body)
+				return
+			}
+		case "domainaliases":
+			fs.serveDomainAliases(w, r, segs[3:], body)
+			return
+		case "domains":
+			fs.serveDomains(w, r, segs[3:], body)
+			return
+		case "orgunits":
+			fs.serveOrgUnits(w, r, segs[3:], body)
+			return
+		case "resources":
+			if len(segs) > 3 && segs[3] == "buildings" {
+				fs.serveBuildings(w, r, segs[4:], body)
+				return
+			}
+			if len(segs) > 3 && segs[3] == "calendars" {
+				fs.serveCalResources(w, r, segs[4:], body)
+				return
+			}
+			if len(segs) > 3 && segs[3] == "features" {
+				fs.serveFeatures(w, r, segs[4:], body)
+				return
+			}
+		case "roleassignments":
+			fs.serveRoleAssignments(w, r, segs[3:], body)
+			return
+		case "roles":
+			fs.serveRoles(w, r, segs[3:], body)
+			return
+		case "schemas":
+			fs.serveSchemas(w, r, segs[3:], body)
+			return
+		}
+	case "groups":
+		fs.serveGroups(w, r, segs[1:], body)
+		return
+	case "users":
+		fs.serveUsers(w, r, segs[1:], body)
+		return
+	}
+	writeError(w, http.StatusNotFound, "notFound", "unknown resource: "+strings.Join(segs, "/"))
+}
+
+func (fs *Server) serveAsps(w http.ResponseWriter, r *http.Request, email string, segs []string) {
+	if len(segs) == 0 && r.Method == http.MethodGet {
+		asps := []interface{}{}
+		for _, asp := range fs.Asps[email] {
+			asps = append(asps, asp)
+		}
+		writeList(w, r, "admin#directory#aspList", "items", asps)
+		return
+	}
+
+	if len(segs) == 1 && r.Method == http.MethodDelete {
+		codeID, _ := strconv.ParseInt(segs[0], 10, 64)
+		for idx, asp := range fs.Asps[email] {
+			if asp.CodeId == codeID {
+				fs.Asps[email] = append(fs.Asps[email][:idx], fs.Asps[email][idx+1:]...)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		writeNotFound(w, segs[0])
+		return
+	}
+
+	writeError(w, http.StatusNotFound, "notFound", strings.Join(segs, "/"))
+}
+
+func (fs *Server) serveBuildings(w http.ResponseWriter, r *http.Request, segs []string, body []byte) {
+	if len(segs) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			buildings := []interface{}{}
+			for _, key := range sortedKeys(fs.Buildings) {
+				buildings = append(buildings, fs.Buildings[key])
+			}
+			writeList(w, r, "admin#directory#resources#buildings#buildingsList", "buildings", buildings)
+		case http.MethodPost:
+			building := new(admin.Building)
+			if !decodeBody(w, body, building) {
+				return
+			}
+			if _, ok := fs.Buildings[building.BuildingId]; ok {
+				writeError(w, http.StatusConflict, "duplicate", "Entity already exists.")
+				return
+			}
+			building.Kind = "admin#directory#resources#buildings#Building"
+			fs.Buildings[building.BuildingId] = building
+			writeFields(w, r, building)
+		}
+		return
+	}
+
+	building, ok := fs.Buildings[segs[0]]
+	if !ok {
+		writeNotFound(w, segs[0])
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeFields(w, r, building)
+	case http.MethodPut, http.MethodPatch:
+		if !mergeBody(w, body, building) {
+			return
+		}
+		writeFields(w, r, building)
+	case http.MethodDelete:
+		for _, calendar := range fs.CalResources {
+			if calendar.BuildingId == segs[0] {
+				writeError(w, http.StatusBadRequest, "badRequest", "Cannot delete a building that contains resources")
+				return
+			}
+		}
+		delete(fs.Buildings, segs[0])
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (fs *Server) serveCalResources(w http.ResponseWriter, r *http.Request, segs []string, body []byte) {
+	if len(segs) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			query := r.URL.Query().Get("query")
+			calendars := []interface{}{}
+			for _, key := range sortedKeys(fs.CalResources) {
+				if query != "" && !calResMatchesQuery(fs.CalResources[key], query) {
+					continue
+				}
+				calendars = append(calendars, fs.CalResources[key])
+			}
+			writeList(w, r, "admin#directory#resources#calendars#calendarResourcesList", "items", calendars)
+		case http.MethodPost:
+			calendar := new(admin.CalendarResource)
+			if !decodeBody(w, body, calendar) {
+				return
+			}
+			if _, ok := fs.CalResources[calendar.ResourceId]; ok {
+				writeError(w, http.StatusConflict, "duplicate", "Entity already exists.")
+				return
+			}
+			if calendar.BuildingId != "" && fs.Buildings[calendar.BuildingId] == nil {
+				writeError(w, http.StatusBadRequest, "invalid", "Invalid building: "+calendar.BuildingId)
+				return
+			}
+			fs.insertCalResource(calendar)
+			writeFields(w, r, calendar)
+		}
+		return
+	}
+
+	calendar, ok := fs.CalResources[segs[0]]
+	if !ok {
+		writeNotFound(w, segs[0])
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeFields(w, r, calendar)
+	case http.MethodPut, http.MethodPatch:
+		if !mergeBody(w, body, calendar) {
+			return
+		}
+		writeFields(w, r, calendar)
+	case http.MethodDelete:
+		delete(fs.CalResources, segs[0])
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (fs *Server) serveFeatures(w http.ResponseWriter, r *http.Request, segs []string, body []byte) {
+	if len(segs) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			features := []interface{}{}
+			for _, key := range sortedKeys(fs.Features) {
+				features = append(features, fs.Features[key])
+			}
+			writeList(w, r, "admin#directory#resources#features#featuresList", "features", features)
+		case http.MethodPost:
+			feature := new(admin.Feature)
+			if !decodeBody(w, body, feature) {
+				return
+			}
+			if _, ok := fs.Features[feature.Name]; ok {
+				writeError(w, http.StatusConflict, "duplicate", "Entity already exists.")
+				return
+			}
+			feature.Kind = "admin#directory#resources#features#Feature"
+			fs.Features[feature.Name] = feature
+			writeFields(w, r, feature)
+		}
+		return
+	}
+
+	feature, ok := fs.Features[segs[0]]
+	if !ok {
+		writeNotFound(w, segs[0])
+		return
+	}
+
+	if len(segs) == 2 && segs[1] == "rename" && r.Method == http.MethodPost {
+		rename := new(admin.FeatureRename)
+		if !decodeBody(w, body, rename) {
+			return
+		}
+		delete(fs.Features, segs[0])
+		feature.Name = rename.NewName
+		fs.Features[feature.Name] = feature
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeFields(w, r, feature)
+	case http.MethodDelete:
+		delete(fs.Features, segs[0])
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (fs *Server) serveCrOSDevices(w http.ResponseWriter, r *http.Request, segs []string, body []byte) {
+	switch {
+	case len(segs) == 0 && r.Method == http.MethodGet:
+		devs := []interface{}{}
+		ouPath := r.URL.Query().Get("orgUnitPath")
+		for _, key := range sortedKeys(fs.CrOSDevices) {
+			dev := fs.CrOSDevices[key]
+			if ouPath != "" && dev.OrgUnitPath != ouPath {
+				continue
+			}
+			devs = append(devs, dev)
+		}
+		writeList(w, r, "admin#directory#chromeosdevices", "chromeosdevices", devs)
+	case len(segs) == 1 && segs[0] == "moveDevicesToOu" && r.Method == http.MethodPost:
+		move := admin.ChromeOsMoveDevicesToOu{}
+		if !decodeBody(w, body, &move) {
+			return
+		}
+		for _, id := range move.DeviceIds {
+			dev, ok := fs.CrOSDevices[id]
+			if !ok {
+				writeNotFound(w, id)
+				return
+			}
+			dev.OrgUnitPath = r.URL.Query().Get("orgUnitPath")
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case len(segs) == 1:
+		dev, ok := fs.CrOSDevices[segs[0]]
+		if !ok {
+			writeNotFound(w, segs[0])
+			return
+		}
+		if r.Method == http.MethodPut || r.Method == http.MethodPatch {
+			if !mergeBody(w, body, dev) {
+				return
+			}
+		}
+		writeFields(w, r, dev)
+	case len(segs) == 2 && segs[1] == "action" && r.Method == http.MethodPost:
+		dev, ok := fs.CrOSDevices[segs[0]]
+		if !ok {
+			writeNotFound(w, segs[0])
+			return
+		}
+		action := admin.ChromeOsDeviceAction{}
+		if !decodeBody(w, body, &action) {
+			return
+		}
+		switch action.Action {
+		case "deprovision":
+			dev.Status = "DEPROVISIONED"
+		case "disable":
+			dev.Status = "DISABLED"
+		case "reenable":
+			dev.Status = "ACTIVE"
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusNotFound, "notFound", "unsupported ChromeOS device call")
+	}
+}
+
+func (fs *Server) serveGroupSettings(w http.ResponseWriter, r *http.Request, key string, body []byte) {
+	key, _ = url.PathUnescape(key)
+	group := fs.findGroup(key)
+	if group == nil {
+		writeNotFound(w, key)
+		return
+	}
+	settings := fs.GroupSettings[strings.ToLower(group.Email)]
+
+	if r.Method == http.MethodPut || r.Method == http.MethodPatch {
+		if !mergeBody(w, body, settings) {
+			return
+		}
+	}
+	writeFields(w, r, settings)
+}
+
+func (fs *Server) serveGroups(w http.ResponseWriter, r *http.Request, segs []string, body []byte) {
+	if len(segs) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			fs.listGroups(w, r)
+		case http.MethodPost:
+			group := new(admin.Group)
+			if !decodeBody(w, body, group) {
+				return
+			}
+			if fs.findGroup(group.Email) != nil {
+				writeError(w, http.StatusConflict, "duplicate", "Entity already exists.")
+				return
+			}
+			fs.insertGroup(group)
+			writeFields(w, r, group)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "badRequest", r.Method)
+		}
+		return
+	}
+
+	group := fs.findGroup(segs[0])
+	if group == nil {
+		writeNotFound(w, segs[0])
+		return
+	}
+	email := strings.ToLower(group.Email)
+
+	if len(segs) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			writeFields(w, r, group)
+		case http.MethodPut, http.MethodPatch:
+			if !mergeBody(w, body, group) {
+				return
+			}
+			if newEmail := strings.ToLower(group.Email); newEmail != email {
+				delete(fs.Groups, email)
+				fs.Groups[newEmail] = group
+			}
+			writeFields(w, r, group)
+		case http.MethodDelete:
+			delete(fs.Groups, email)
+			delete(fs.GroupSettings, email)
+			delete(fs.Members, email)
+			for _, mems := range fs.Members {
+				delete(mems, email)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}
+		return
+	}
+
+	switch segs[1] {
+	case "aliases":
+		fs.serveAliases(w, r, segs[2:], body, &group.Aliases, group.Email, group.Id)
+	case "members":
+		fs.serveMembers(w, r, group, segs[2:], body)
+	default:
+		writeError(w, http.StatusNotFound, "notFound", segs[1])
+	}
+}
+
+func (fs *Server) serveAliases(w http.ResponseWriter, r *http.Request, segs []string, body []byte, aliases *[]string, primary string, id string) {
+	switch {
+	case len(segs) == 0 && r.Method == http.MethodGet:
+		list := []interface{}{}
+		for _, alias := range *aliases {
+			list = append(list, admin.Alias{Alias: alias, Id: id, Kind: "admin#directory#alias", PrimaryEmail: primary})
+		}
+		writeFields(w, r, map[string]interface{}{"kind": "admin#directory#aliases", "aliases": list})
+	case len(segs) == 0 && r.Method == http.MethodPost:
+		alias := admin.Alias{}
+		if !decodeBody(w, body, &alias) {
+			return
+		}
+		*aliases = append(*aliases, alias.Alias)
+		alias.Id = id
+		alias.PrimaryEmail = primary
+		alias.Kind = "admin#directory#alias"
+		writeFields(w, r, alias)
+	case len(segs) == 1 && r.Method == http.MethodDelete:
+		kept := []string{}
+		found := false
+		for _, alias := range *aliases {
+			if strings.EqualFold(alias, segs[0]) {
+				found = true
+				continue
+			}
+			kept = append(kept, alias)
+		}
+		if !found {
+			writeNotFound(w, segs[0])
+			return
+		}
+		*aliases = kept
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusNotFound, "notFound", "unsupported alias call")
+	}
+}
+
+func (fs *Server) serveMembers(w http.ResponseWriter, r *http.Request, group *admin.Group, segs []string, body []byte) {
+	email := strings.ToLower(group.Email)
+
+	if len(segs) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			roles := r.URL.Query().Get("roles")
+			mems := []interface{}{}
+			for _, key := range sortedKeys(fs.Members[email]) {
+				member := fs.Members[email][key]
+				if roles != "" && !containsFold(strings.Split(roles, ","), member.Role) {
+					continue
+				}
+				mems = append(mems, member)
+			}
+			writeList(w, r, "admin#directory#members", "members", mems)
+		case http.MethodPost:
+			member := new(admin.Member)
+			if !decodeBody(w, body, member) {
+				return
+			}
+			if fs.findMember(email, member.Email) != nil {
+				writeError(w, http.StatusConflict, "duplicate", "Member already exists.")
+				return
+			}
+			fs.insertMember(email, member)
+			writeFields(w, r, member)
+		}
+		return
+	}
+
+	member := fs.findMember(email, segs[0])
+	if member == nil {
+		writeNotFound(w, segs[0])
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeFields(w, r, member)
+	case http.MethodPut, http.MethodPatch:
+		if !mergeBody(w, body, member) {
+			return
+		}
+		writeFields(w, r, member)
+	case http.MethodDelete:
+		delete(fs.Members[email], strings.ToLower(member.Email))
+		group.DirectMembersCount = int64(len(fs.Members[email]))
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (fs *Server) serveMobDevices(w http.ResponseWriter, r *http.Request, segs []string, body []byte) {
+	switch {
+	case len(segs) == 0 && r.Method == http.MethodGet:
+		devs := []interface{}{}
+		query := r.URL.Query().Get("query")
+		for _, key := range sortedKeys(fs.MobDevices) {
+			dev := fs.MobDevices[key]
+			if strings.HasPrefix(query, "email:") && !mobDevHasEmail(dev, strings.TrimPrefix(query, "email:")) {
+				continue
+			}
+			devs = append(devs, dev)
+		}
+		writeList(w, r, "admin#directory#mobiledevices", "mobiledevices", devs)
+	case len(segs) == 1:
+		dev, ok := fs.MobDevices[segs[0]]
+		if !ok {
+			writeNotFound(w, segs[0])
+			return
+		}
+		if r.Method == http.MethodDelete {
+			delete(fs.MobDevices, segs[0])
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		writeFields(w, r, dev)
+	case len(segs) == 2 && segs[1] == "action" && r.Method == http.MethodPost:
+		dev, ok := fs.MobDevices[segs[0]]
+		if !ok {
+			writeNotFound(w, segs[0])
+			return
+		}
+		action := admin.MobileDeviceAction{}
+		if !decodeBody(w, body, &action) {
+			return
+		}
+		switch action.Action {
+		case "approve":
+			dev.Status = "APPROVED"
+		case "block":
+			dev.Status = "BLOCKED"
+		case "admin_account_wipe", "admin_remote_wipe":
+			dev.Status = "WIPING"
+		case "cancel_remote_wipe_then_activate", "cancel_remote_wipe_then_block":
+			dev.Status = "APPROVED"
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusNotFound, "notFound", "unsupported mobile device call")
+	}
+}
+
+func (fs *Server) serveOrgUnits(w http.ResponseWriter, r *http.Request, segs []string, body []byte) {
+	if len(segs) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			fs.listOrgUnits(w, r)
+		case http.MethodPost:
+			ou := new(admin.OrgUnit)
+			if !decodeBody(w, body, ou) {
+				return
+			}
+			if ou.ParentOrgUnitPath == "" && ou.ParentOrgUnitId != "" {
+				if parent := fs.findOrgUnit(ou.ParentOrgUnitId); parent != nil {
+					ou.ParentOrgUnitPath = parent.OrgUnitPath
+				}
+			}
+			if fs.OrgUnits[ou.ParentOrgUnitPath] == nil && ou.ParentOrgUnitPath != "" {
+				writeNotFound(w, ou.ParentOrgUnitPath)
+				return
+			}
+			fs.insertOrgUnit(ou)
+			writeFields(w, r, ou)
+		}
+		return
+	}
+
+	ouPath, _ := url.PathUnescape(strings.Join(segs, "/"))
+	ou := fs.findOrgUnit(ouPath)
+	if ou == nil {
+		writeNotFound(w, ouPath)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeFields(w, r, ou)
+	case http.MethodPut, http.MethodPatch:
+		oldPath := ou.OrgUnitPath
+		if !mergeBody(w, body, ou) {
+			return
+		}
+		ou.OrgUnitPath = strings.TrimSuffix(ou.ParentOrgUnitPath, "/") + "/" + ou.Name
+		if ou.OrgUnitPath != oldPath {
+			delete(fs.OrgUnits, oldPath)
+			fs.OrgUnits[ou.OrgUnitPath] = ou
+		}
+		writeFields(w, r, ou)
+	case http.MethodDelete:
+		for path := range fs.OrgUnits {
+			if strings.HasPrefix(path, ou.OrgUnitPath+"/") {
+				writeError(w, http.StatusBadRequest, "badRequest", "OrgUnit has child OrgUnits")
+				return
+			}
+		}
+		delete(fs.OrgUnits, ou.OrgUnitPath)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (fs *Server) serveDomainAliases(w http.ResponseWriter, r *http.Request, segs []string, body []byte) {
+	if len(segs) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			parent := strings.ToLower(r.URL.Query().Get("parentDomainName"))
+
+			aliases := []interface{}{}
+			for _, key := range sortedKeys(fs.DomainAliases) {
+				alias := fs.DomainAliases[key]
+				if parent != "" && strings.ToLower(alias.ParentDomainName) != parent {
+					continue
+				}
+				aliases = append(aliases, alias)
+			}
+			writeFields(w, r, map[string]interface{}{"kind": "admin#directory#domainAliases", "domainAliases": aliases})
+		case http.MethodPost:
+			alias := new(admin.DomainAlias)
+			if !decodeBody(w, body, alias) {
+				return
+			}
+			key := strings.ToLower(alias.DomainAliasName)
+			if fs.Domains[key] != nil || fs.DomainAliases[key] != nil {
+				writeError(w, http.StatusConflict, "duplicate", "Entity already exists.")
+				return
+			}
+			if fs.Domains[strings.ToLower(alias.ParentDomainName)] == nil {
+				writeNotFound(w, alias.ParentDomainName)
+				return
+			}
+			alias.Kind = "admin#directory#domainAlias"
+			fs.DomainAliases[key] = alias
+			writeFields(w, r, alias)
+		}
+		return
+	}
+
+	key := strings.ToLower(segs[0])
+	alias, ok := fs.DomainAliases[key]
+	if !ok {
+		writeNotFound(w, segs[0])
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeFields(w, r, alias)
+	case http.MethodDelete:
+		delete(fs.DomainAliases, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// domainWithAliases returns a copy of a domain that includes its domain aliases
+func (fs *Server) domainWithAliases(domain *admin.Domains) *admin.Domains {
+	d := *domain
+	d.DomainAliases = nil
+	for _, key := range sortedKeys(fs.DomainAliases) {
+		alias := fs.DomainAliases[key]
+		if strings.EqualFold(alias.ParentDomainName, domain.DomainName) {
+			d.DomainAliases = append(d.DomainAliases, alias)
+		}
+	}
+	return &d
+}
+
+func (fs *Server) serveDomains(w http.ResponseWriter, r *http.Request, segs []string, body []byte) {
+	if len(segs) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			domains := []interface{}{}
+			for _, key := range sortedKeys(fs.Domains) {
+				domains = append(domains, fs.domainWithAliases(fs.Domains[key]))
+			}
+			writeFields(w, r, map[string]interface{}{"kind": "admin#directory#domains", "domains": domains})
+		case http.MethodPost:
+			domain := new(admin.Domains)
+			if !decodeBody(w, body, domain) {
+				return
+			}
+			key := strings.ToLower(domain.DomainName)
+			if fs.Domains[key] != nil || fs.DomainAliases[key] != nil {
+				writeError(w, http.StatusConflict, "duplicate", "Entity already exists.")
+				return
+			}
+			domain.Kind = "admin#directory#domain"
+			fs.Domains[key] = domain
+			writeFields(w, r, domain)
+		}
+		return
+	}
+
+	key := strings.ToLower(segs[0])
+	domain, ok := fs.Domains[key]
+	if !ok {
+		writeNotFound(w, segs[0])
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeFields(w, r, fs.domainWithAliases(domain))
+	case http.MethodDelete:
+		for aliasKey, alias := range fs.DomainAliases {
+			if strings.EqualFold(alias.ParentDomainName, domain.DomainName) {
+				delete(fs.DomainAliases, aliasKey)
+			}
+		}
+		delete(fs.Domains, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (fs *Server) servePhotos(w http.ResponseWriter, r *http.Request, user *admin.User, segs []string, body []byte) {
+	email := strings.ToLower(user.PrimaryEmail)
+
+	if len(segs) != 1 || segs[0] != "thumbnail" {
+		writeError(w, http.StatusNotFound, "notFound", strings.Join(segs, "/"))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		photo, ok := fs.Photos[email]
+		if !ok {
+			writeNotFound(w, email)
+			return
+		}
+		writeFields(w, r, photo)
+	case http.MethodPut, http.MethodPatch:
+		photo := new(admin.UserPhoto)
+		if !decodeBody(w, body, photo) {
+			return
+		}
+		photo.Id = user.Id
+		photo.Kind = "admin#directory#user#photo"
+		photo.PrimaryEmail = user.PrimaryEmail
+		fs.Photos[email] = photo
+		writeFields(w, r, photo)
+	case http.MethodDelete:
+		delete(fs.Photos, email)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (fs *Server) serveRoleAssignments(w http.ResponseWriter, r *http.Request, segs []string, body []byte) {
+	if len(segs) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			var userID string
+			if userKey := r.URL.Query().Get("userKey"); userKey != "" {
+				user := fs.findUser(userKey)
+				if user == nil {
+					writeNotFound(w, userKey)
+					return
+				}
+				userID = user.Id
+			}
+			roleID := r.URL.Query().Get("roleId")
+
+			assignments := []interface{}{}
+			for _, key := range sortedKeys(fs.RoleAssignments) {
+				ra := fs.RoleAssignments[key]
+				if userID != "" && ra.AssignedTo != userID {
+					continue
+				}
+				if roleID != "" && strconv.FormatInt(ra.RoleId, 10) != roleID {
+					continue
+				}
+				assignments = append(assignments, ra)
+			}
+			writeList(w, r, "admin#directory#roleAssignments", "items", assignments)
+		case http.MethodPost:
+			ra := new(admin.RoleAssignment)
+			if !decodeBody(w, body, ra) {
+				return
+			}
+			if fs.Roles[strconv.FormatInt(ra.RoleId, 10)] == nil {
+				writeNotFound(w, strconv.FormatInt(ra.RoleId, 10))
+				return
+			}
+			ra.RoleAssignmentId, _ = strconv.ParseInt(fs.newID(), 10, 64)
+			ra.Kind = "admin#directory#roleAssignment"
+			fs.RoleAssignments[strconv.FormatInt(ra.RoleAssignmentId, 10)] = ra
+			writeFields(w, r, ra)
+		}
+		return
+	}
+
+	ra, ok := fs.RoleAssignments[segs[0]]
+	if !ok {
+		writeNotFound(w, segs[0])
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeFields(w, r, ra)
+	case http.MethodDelete:
+		delete(fs.RoleAssignments, segs[0])
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (fs *Server) serveRoles(w http.ResponseWriter, r *http.Request, segs []string, body []byte) {
+	if len(segs) == 2 && segs[0] == "ALL" && segs[1] == "privileges" && r.Method == http.MethodGet {
+		privs := []interface{}{}
+		for _, priv := range fs.Privileges {
+			privs = append(privs, priv)
+		}
+		writeFields(w, r, map[string]interface{}{"kind": "admin#directory#privileges", "items": privs})
+		return
+	}
+
+	if len(segs) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			roles := []interface{}{}
+			for _, key := range sortedKeys(fs.Roles) {
+				roles = append(roles, fs.Roles[key])
+			}
+			writeList(w, r, "admin#directory#roles", "items", roles)
+		case http.MethodPost:
+			role := new(admin.Role)
+			if !decodeBody(w, body, role) {
+				return
+			}
+			if fs.findRole(role.RoleName) != nil {
+				writeError(w, http.StatusConflict, "duplicate", "Entity already exists.")
+				return
+			}
+			role.RoleId = 0
+			fs.insertRole(role)
+			writeFields(w, r, role)
+		}
+		return
+	}
+
+	role, ok := fs.Roles[segs[0]]
+	if !ok {
+		writeNotFound(w, segs[0])
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeFields(w, r, role)
+	case http.MethodPut, http.MethodPatch:
+		if !mergeBody(w, body, role) {
+			return
+		}
+		writeFields(w, r, role)
+	case http.MethodDelete:
+		for _, ra := range fs.RoleAssignments {
+			if strconv.FormatInt(ra.RoleId, 10) == segs[0] {
+				writeError(w, http.StatusBadRequest, "badRequest", "Cannot delete a role that is assigned")
+				return
+			}
+		}
+		delete(fs.Roles, segs[0])
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (fs *Server) serveSchemas(w http.ResponseWriter, r *http.Request, segs []string, body []byte) {
+	if len(segs) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			schemas := []interface{}{}
+			for _, key := range sortedKeys(fs.Schemas) {
+				schemas = append(schemas, fs.Schemas[key])
+			}
+			writeFields(w, r, map[string]interface{}{"kind": "admin#directory#schemas", "schemas": schemas})
+		case http.MethodPost:
+			schema := new(admin.Schema)
+			if !decodeBody(w, body, schema) {
+				return
+			}
+			schema.SchemaId = fs.newID()
+			schema.Kind = "admin#directory#schema"
+			fs.Schemas[schema.SchemaName] = schema
+			writeFields(w, r, schema)
+		}
+		return
+	}
+
+	schema := fs.findSchema(segs[0])
+	if schema == nil {
+		writeNotFound(w, segs[0])
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeFields(w, r, schema)
+	case http.MethodPut, http.MethodPatch:
+		if !mergeBody(w, body, schema) {
+			return
+		}
+		writeFields(w, r, schema)
+	case http.MethodDelete:
+		delete(fs.Schemas, schema.SchemaName)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (fs *Server) serveReports(w http.ResponseWriter, r *http.Request, segs []string) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusNotFound, "notFound", "unsupported reports call")
+		return
+	}
+
+	switch {
+	case len(segs) == 5 && segs[0] == "activity" && segs[1] == "users" && segs[3] == "applications":
+		userKey, _ := url.PathUnescape(segs[2])
+		fs.serveActivities(w, r, userKey, segs[4])
+	case len(segs) == 5 && segs[0] == "usage" && segs[1] == "users" && segs[3] == "dates":
+		userKey, _ := url.PathUnescape(segs[2])
+		fs.serveUsageReports(w, r, "USER", userKey, segs[4])
+	case len(segs) == 3 && segs[0] == "usage" && segs[1] == "dates":
+		fs.serveUsageReports(w, r, "CUSTOMER", "all", segs[2])
+	default:
+		writeError(w, http.StatusNotFound, "notFound", "unsupported reports call")
+	}
+}
+
+// serveActivities lists the activities of an application, newest first, filtered by user, event name
+// and time range
+func (fs *Server) serveActivities(w http.ResponseWriter, r *http.Request, userKey string, application string) {
+	query := r.URL.Query()
+	eventName := query.Get("eventName")
+	startTime, _ := time.Parse(time.RFC3339, query.Get("startTime"))
+	endTime, _ := time.Parse(time.RFC3339, query.Get("endTime"))
+
+	matched := []*reports.Activity{}
+	for _, activity := range fs.Activities {
+		if activity.Id == nil || activity.Id.ApplicationName != application {
+			continue
+		}
+		if userKey != "all" && (activity.Actor == nil || !strings.EqualFold(activity.Actor.Email, userKey)) {
+			continue
+		}
+		if eventName != "" && !activityHasEvent(activity, eventName) {
+			continue
+		}
+		actTime, _ := time.Parse(time.RFC3339, activity.Id.Time)
+		if !startTime.IsZero() && actTime.Before(startTime) {
+			continue
+		}
+		if !endTime.IsZero() && actTime.After(endTime) {
+			continue
+		}
+		matched = append(matched, activity)
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].Id.Time > matched[j].Id.Time
+	})
+
+	items := []interface{}{}
+	for _, activity := range matched {
+		items = append(items, activity)
+	}
+	writeList(w, r, "admin#reports#activities", "items", items)
+}
+
+// serveUsageReports lists the usage reports of an entity type for a date with only the parameters
+// given by the parameters parameter
+func (fs *Server) serveUsageReports(w http.ResponseWriter, r *http.Request, entityType string, userKey string, date string) {
+	var params []string
+	if query := r.URL.Query().Get("parameters"); query != "" {
+		params = strings.Split(query, ",")
+	}
+
+	items := []interface{}{}
+	for _, usageReport := range fs.UsageReports {
+		if usageReport.Date != date || usageReport.Entity == nil || usageReport.Entity.Type != entityType {
+			continue
+		}
+		if userKey != "all" && !strings.EqualFold(usageReport.Entity.UserEmail, userKey) {
+			continue
+		}
+		if params == nil {
+			items = append(items, usageReport)
+			continue
+		}
+
+		selected := *usageReport
+		selected.Parameters = []*reports.UsageReportParameters{}
+		for _, param := range usageReport.Parameters {
+			for _, name := range params {
+				if param.Name == name {
+					selected.Parameters = append(selected.Parameters, param)
+				}
+			}
+		}
+		items = append(items, &selected)
+	}
+	writeList(w, r, "admin#reports#usageReports", "usageReports", items)
+}
+
+func (fs *Server) serveSheets(w http.ResponseWriter, r *http.Request, segs []string) {
+	if len(segs) != 3 || segs[1] != "values" || r.Method != http.MethodGet {
+		writeError(w, http.StatusNotFound, "notFound", "unsupported sheets call")
+		return
+	}
+
+	sheetRange, _ := url.PathUnescape(segs[2])
+	values, ok := fs.Sheets[segs[0]][sheetRange]
+	if !ok {
+		writeNotFound(w, segs[0]+" "+sheetRange)
+		return
+	}
+	writeFields(w, r, map[string]interface{}{"range": sheetRange, "majorDimension": "ROWS", "values": values})
+}
+
+func (fs *Server) serveTokens(w http.ResponseWriter, r *http.Request, email string, segs []string) {
+	if len(segs) == 0 && r.Method == http.MethodGet {
+		tokens := []interface{}{}
+		for _, token := range fs.Tokens[email] {
+			tokens = append(tokens, token)
+		}
+		writeList(w, r, "admin#directory#tokenList", "items", tokens)
+		return
+	}
+
+	if len(segs) == 1 {
+		clientID, _ := url.PathUnescape(segs[0])
+		for idx, token := range fs.Tokens[email] {
+			if token.ClientId != clientID {
+				continue
+			}
+			switch r.Method {
+			case http.MethodGet:
+				writeFields(w, r, token)
+			case http.MethodDelete:
+				fs.Tokens[email] = append(fs.Tokens[email][:idx], fs.Tokens[email][idx+1:]...)
+				w.WriteHeader(http.StatusNoContent)
+			}
+			return
+		}
+		writeNotFound(w, clientID)
+		return
+	}
+
+	writeError(w, http.StatusNotFound, "notFound", strings.Join(segs, "/"))
+}
+
+func (fs *Server) serveUsers(w http.ResponseWriter, r *http.Request, segs []string, body []byte) {
+	if len(segs) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			fs.listUsers(w, r)
+		case http.MethodPost:
+			user := new(admin.User)
+			if !decodeBody(w, body, user) {
+				return
+			}
+			if fs.findUser(user.PrimaryEmail) != nil {
+				writeError(w, http.StatusConflict, "duplicate", "Entity already exists.")
+				return
+			}
+			fs.insertUser(user)
+			writeFields(w, r, user)
+		}
+		return
+	}
+
+	if len(segs) == 2 && segs[1] == "undelete" && r.Method == http.MethodPost {
+		user, ok := fs.DeletedUsers[segs[0]]
+		if !ok {
+			writeNotFound(w, segs[0])
+			return
+		}
+		undel := admin.UserUndelete{}
+		if !decodeBody(w, body, &undel) {
+			return
+		}
+		if undel.OrgUnitPath != "" {
+			user.OrgUnitPath = undel.OrgUnitPath
+		}
+		user.DeletionTime = ""
+		delete(fs.DeletedUsers, segs[0])
+		fs.Users[strings.ToLower(user.PrimaryEmail)] = user
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	key, _ := url.PathUnescape(segs[0])
+	user := fs.findUser(key)
+	if user == nil {
+		writeNotFound(w, key)
+		return
+	}
+	email := strings.ToLower(user.PrimaryEmail)
+
+	if len(segs) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			writeFields(w, r, user)
+		case http.MethodPut, http.MethodPatch:
+			update := new(admin.User)
+			if !decodeBody(w, body, update) {
+				return
+			}
+			if update.OrgUnitPath != "" && fs.findOrgUnit(update.OrgUnitPath) == nil {
+				writeError(w, http.StatusBadRequest, "invalid", "Invalid Input: INVALID_OU_ID")
+				return
+			}
+			if !mergeBody(w, body, user) {
+				return
+			}
+			if newEmail := strings.ToLower(user.PrimaryEmail); newEmail != email {
+				delete(fs.Users, email)
+				fs.Users[newEmail] = user
+			}
+			writeFields(w, r, user)
+		case http.MethodDelete:
+			delete(fs.Users, email)
+			user.DeletionTime = "2020-01-01T00:00:00.000Z"
+			fs.DeletedUsers[user.Id] = user
+			for _, mems := range fs.Members {
+				delete(mems, email)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}
+		return
+	}
+
+	switch segs[1] {
+	case "aliases":
+		fs.serveAliases(w, r, segs[2:], body, &user.Aliases, user.PrimaryEmail, user.Id)
+	case "asps":
+		fs.serveAsps(w, r, email, segs[2:])
+	case "makeAdmin":
+		makeAdmin := new(admin.UserMakeAdmin)
+		if !decodeBody(w, body, makeAdmin) {
+			return
+		}
+		user.IsAdmin = makeAdmin.Status
+		w.WriteHeader(http.StatusNoContent)
+	case "photos":
+		fs.servePhotos(w, r, user, segs[2:], body)
+	case "signOut":
+		fs.SignOuts = append(fs.SignOuts, email)
+		w.WriteHeader(http.StatusNoContent)
+	case "tokens":
+		fs.serveTokens(w, r, email, segs[2:])
+	case "verificationCodes":
+		fs.serveVerCodes(w, r, email, segs[2:])
+	default:
+		writeError(w, http.StatusNotFound, "notFound", segs[1])
+	}
+}
+
+func (fs *Server) listGroups(w http.ResponseWriter, r *http.Request) {
+	var (
+		domain  = strings.ToLower(r.URL.Query().Get("domain"))
+		groups  = []interface{}{}
+		userKey = r.URL.Query().Get("userKey")
+	)
+
+	if domain != "" && fs.Domains[domain] == nil && fs.DomainAliases[domain] == nil {
+		writeError(w, http.StatusBadRequest, "badRequest", "Domain not found.")
+		return
+	}
+
+	if user := fs.findUser(userKey); user != nil {
+		userKey = user.PrimaryEmail
+	}
+
+	for _, key := range sortedKeys(fs.Groups) {
+		group := fs.Groups[key]
+		if domain != "" && !strings.HasSuffix(key, "@"+domain) {
+			continue
+		}
+		if userKey != "" && fs.findMember(group.Email, userKey) == nil {
+			continue
+		}
+		groups = append(groups, group)
+	}
+	writeList(w, r, "admin#directory#groups", "groups", groups)
+}
+
+func (fs *Server) listOrgUnits(w http.ResponseWriter, r *http.Request) {
+	var (
+		ous    = []interface{}{}
+		parent = r.URL.Query().Get("orgUnitPath")
+		all    = strings.ToLower(r.URL.Query().Get("type")) == "all"
+	)
+
+	if parent == "" {
+		parent = "/"
+	}
+	if !strings.HasPrefix(parent, "/") && !strings.HasPrefix(parent, "id:") {
+		parent = "/" + parent
+	}
+	if pou := fs.findOrgUnit(parent); pou != nil {
+		parent = pou.OrgUnitPath
+	}
+
+	for _, key := range sortedKeys(fs.OrgUnits) {
+		ou := fs.OrgUnits[key]
+		if key == "/" {
+			continue
+		}
+		if all && (parent == "/" || strings.HasPrefix(key, parent+"/")) {
+			ous = append(ous, ou)
+			continue
+		}
+		if !all && ou.ParentOrgUnitPath == parent {
+			ous = append(ous, ou)
+		}
+	}
+	writeFields(w, r, map[string]interface{}{"kind": "admin#directory#orgUnits", "organizationUnits": ous})
+}
+
+func (fs *Server) listUsers(w http.ResponseWriter, r *http.Request) {
+	var (
+		domain  = strings.ToLower(r.URL.Query().Get("domain"))
+		query   = r.URL.Query().Get("query")
+		source  = fs.Users
+		users   = []interface{}{}
+		deleted = r.URL.Query().Get("showDeleted") == "true"
+	)
+
+	if domain != "" && fs.Domains[domain] == nil && fs.DomainAliases[domain] == nil {
+		writeError(w, http.StatusBadRequest, "badRequest", "Domain not found.")
+		return
+	}
+
+	if deleted {
+		source = fs.DeletedUsers
+	}
+
+	for _, key := range sortedKeys(source) {
+		user := source[key]
+		if domain != "" && !strings.HasSuffix(strings.ToLower(user.PrimaryEmail), "@"+domain) {
+			continue
+		}
+		if !userMatchesQuery(user, query) {
+			continue
+		}
+		users = append(users, user)
+	}
+	writeList(w, r, "admin#directory#users", "users", users)
+}
+
+// fieldMask is a parsed partial response fields parameter where a nil value selects the whole attribute
+type fieldMask map[string]fieldMask
+
+func activityHasEvent(activity *reports.Activity, eventName string) bool {
+	for _, event := range activity.Events {
+		if event.Name == eventName {
+			return true
+		}
+	}
+	return false
+}
+
+func filterFields(val interface{}, mask fieldMask) interface{} {
+	if mask == nil {
+		return val
+	}
+
+	switch v := val.(type) {
+	case []interface{}:
+		filtered := []interface{}{}
+		for _, item := range v {
+			filtered = append(filtered, filterFields(item, mask))
+		}
+		return filtered
+	case map[string]interface{}:
+		filtered := map[string]interface{}{}
+		for key, subMask := range mask {
+			for attr, attrVal := range v {
+				if strings.EqualFold(attr, key) {
+					filtered[attr] = filterFields(attrVal, subMask)
+				}
+			}
+		}
+		return filtered
+	}
+	return val
+}
+
+func parseFields(fields string) fieldMask {
+	mask := fieldMask{}
+
+	depth := 0
+	start := 0
+	for i := 0; i <= len(fields); i++ {
+		if i < len(fields) {
+			switch fields[i] {
+			case '(':
+				depth++
+				continue
+			case ')':
+				depth--
+				continue
+			case ',':
+				if depth > 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+		addField(mask, strings.TrimSpace(fields[start:i]))
+		start = i + 1
+	}
+	return mask
+}
+
+func addField(mask fieldMask, field string) {
+	if field == "" {
+		return
+	}
+
+	var subMask fieldMask
+
+	if open := strings.Index(field, "("); open != -1 && strings.HasSuffix(field, ")") {
+		subMask = parseFields(field[open+1 : len(field)-1])
+		field = field[:open]
+	}
+	if slash := strings.Index(field, "/"); slash != -1 {
+		subMask = fieldMask{}
+		addField(subMask, field[slash+1:])
+		field = field[:slash]
+	}
+
+	existing, ok := mask[field]
+	if ok && (existing == nil || subMask == nil) {
+		mask[field] = nil
+		return
+	}
+	if ok {
+		for key, val := range subMask {
+			existing[key] = val
+		}
+		return
+	}
+	mask[field] = subMask
+}
+
+func containsFold(strs []string, s string) bool {
+	for _, str := range strs {
+		if strings.EqualFold(str, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func decodeBody(w http.ResponseWriter, body []byte, obj interface{}) bool {
+	err := json.Unmarshal(body, obj)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "badRequest", err.Error())
+		return false
+	}
+	return true
+}
+
+// mergeBody overlays the JSON attributes in the request body on to an existing object
+func mergeBody(w http.ResponseWriter, body []byte, obj interface{}) bool {
+	var (
+		current = map[string]interface{}{}
+		changes = map[string]interface{}{}
+	)
+
+	existing, err := json.Marshal(obj)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internalError", err.Error())
+		return false
+	}
+	if !decodeBody(w, existing, &current) || !decodeBody(w, body, &changes) {
+		return false
+	}
+
+	for key, val := range changes {
+		current[key] = val
+	}
+
+	merged, err := json.Marshal(current)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internalError", err.Error())
+		return false
+	}
+	return decodeBody(w, merged, obj)
+}
+
+func sortedKeys(m interface{}) []string {
+	keys := []string{}
+
+	switch v := m.(type) {
+	case map[string]*admin.Building:
+		for key := range v {
+			keys = append(keys, key)
+		}
+	case map[string]*admin.CalendarResource:
+		for key := range v {
+			keys = append(keys, key)
+		}
+	case map[string]*admin.ChromeOsDevice:
+		for key := range v {
+			keys = append(keys, key)
+		}
+	case map[string]*admin.Feature:
+		for key := range v {
+			keys = append(keys, key)
+		}
+	case map[string]*admin.Group:
+		for key := range v {
+			keys = append(keys, key)
+		}
+	case map[string]*admin.Member:
+		for key := range v {
+			keys = append(keys, key)
+		}
+	case map[string]*admin.MobileDevice:
+		for key := range v {
+			keys = append(keys, key)
+		}
+	case map[string]*admin.OrgUnit:
+		for key := range v {
+			keys = append(keys, key)
+		}
+	case map[string]*admin.DomainAlias:
+		for key := range v {
+			keys = append(keys, key)
+		}
+	case map[string]*admin.Domains:
+		for key := range v {
+			keys = append(keys, key)
+		}
+	case map[string]*admin.Role:
+		for key := range v {
+			keys = append(keys, key)
+		}
+	case map[string]*admin.RoleAssignment:
+		for key := range v {
+			keys = append(keys, key)
+		}
+	case map[string]*admin.Schema:
+		for key := range v {
+			keys = append(keys, key)
+		}
+	case map[string]*admin.User:
+		for key := range v {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func splitPath(path string) []string {
+	segs := []string{}
+	for _, seg := range strings.Split(path, "/") {
+		if seg == "" {
+			continue
+		}
+		unesc, err := url.PathUnescape(seg)
+		if err != nil {
+			unesc = seg
+		}
+		segs = append(segs, unesc)
+	}
+	return segs
+}
+
+// userMatchesQuery supports simple space separated field=value or field:value query clauses
+func calResMatchesQuery(calendar *admin.CalendarResource, query string) bool {
+	for _, clause := range strings.Split(query, " AND ") {
+		clause = strings.TrimSpace(clause)
+		sep := strings.IndexAny(clause, "=<>:")
+		if sep == -1 {
+			continue
+		}
+		field := clause[:sep]
+		rest := strings.TrimLeft(clause[sep:], "=<>:")
+		op := clause[sep : len(clause)-len(rest)]
+		val := strings.Trim(rest, "'\"")
+
+		switch field {
+		case "buildingId":
+			if calendar.BuildingId != val {
+				return false
+			}
+		case "capacity":
+			capacity, _ := strconv.ParseInt(val, 10, 64)
+			switch op {
+			case ">=":
+				if calendar.Capacity < capacity {
+					return false
+				}
+			case "<=":
+				if calendar.Capacity > capacity {
+					return false
+				}
+			default:
+				if calendar.Capacity != capacity {
+					return false
+				}
+			}
+		case "floor_name":
+			if calendar.FloorName != val {
+				return false
+			}
+		case "name", "generatedResourceName":
+			if calendar.ResourceName != val {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func userMatchesQuery(user *admin.User, query string) bool {
+	for _, clause := range strings.Fields(query) {
+		sep := strings.IndexAny(clause, "=:")
+		if sep == -1 {
+			continue
+		}
+		field := strings.ToLower(clause[:sep])
+		val := strings.Trim(clause[sep+1:], "'\"")
+
+		switch field {
+		case "email":
+			if !strings.EqualFold(user.PrimaryEmail, val) {
+				return false
+			}
+		case "isadmin":
+			if strconv.FormatBool(user.IsAdmin) != strings.ToLower(val) {
+				return false
+			}
+		case "issuspended":
+			if strconv.FormatBool(user.Suspended) != strings.ToLower(val) {
+				return false
+			}
+		case "orgunitpath":
+			if user.OrgUnitPath != val && !strings.HasPrefix(user.OrgUnitPath, strings.TrimSuffix(val, "/")+"/") {
+				return false
+			}
+		case "familyname":
+			if user.Name == nil || !strings.EqualFold(user.Name.FamilyName, val) {
+				return false
+			}
+		case "givenname":
+			if user.Name == nil || !strings.EqualFold(user.Name.GivenName, val) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (fs *Server) serveVerCodes(w http.ResponseWriter, r *http.Request, email string, segs []string) {
+	switch {
+	case len(segs) == 0 && r.Method == http.MethodGet:
+		codes := []interface{}{}
+		for _, code := range fs.VerCodes[email] {
+			codes = append(codes, code)
+		}
+		writeList(w, r, "admin#directory#verificationCodesList", "items", codes)
+	case len(segs) == 1 && segs[0] == "generate" && r.Method == http.MethodPost:
+		codes := []*admin.VerificationCode{}
+		for i := 0; i < 10; i++ {
+			codes = append(codes, &admin.VerificationCode{
+				Kind:             "admin#directory#verificationCode",
+				UserId:           email,
+				VerificationCode: fmt.Sprintf("%08d", fs.nextID*10+i),
+			})
+		}
+		fs.nextID++
+		fs.VerCodes[email] = codes
+		w.WriteHeader(http.StatusNoContent)
+	case len(segs) == 1 && segs[0] == "invalidate" && r.Method == http.MethodPost:
+		delete(fs.VerCodes, email)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusNotFound, "notFound", strings.Join(segs, "/"))
+	}
+}
+
+func mobDevHasEmail(dev *admin.MobileDevice, email string) bool {
+	for _, devEmail := range dev.Email {
+		if strings.EqualFold(devEmail, email) {
+			return true
+		}
+	}
+	return false
+}
+
+func writeError(w http.ResponseWriter, code int, reason string, message string) {
+	writeJSON(w, code, map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    code,
+			"message": message,
+			"errors": []interface{}{
+				map[string]interface{}{"domain": "global", "reason": reason, "message": message},
+			},
+		},
+	})
+}
+
+// writeFields writes a successful response containing only the attributes given by the fields parameter
+func writeFields(w http.ResponseWriter, r *http.Request, obj interface{}) {
+	fields := r.URL.Query().Get("fields")
+	if fields == "" {
+		writeJSON(w, http.StatusOK, obj)
+		return
+	}
+
+	var generic interface{}
+	jsonBytes, err := json.Marshal(obj)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internalError", err.Error())
+		return
+	}
+	if !decodeBody(w, jsonBytes, &generic) {
+		return
+	}
+	writeJSON(w, http.StatusOK, filterFields(generic, parseFields(fields)))
+}
+
+func writeJSON(w http.ResponseWriter, code int, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(obj)
+}
+
+// writeList writes a page of list results using maxResults and a numeric pageToken
+func writeList(w http.ResponseWriter, r *http.Request, kind string, listKey string, items []interface{}) {
+	start, _ := strconv.Atoi(r.URL.Query().Get("pageToken"))
+	maxResults, err := strconv.Atoi(r.URL.Query().Get("maxResults"))
+	if err != nil || maxResults < 1 {
+		maxResults = len(items)
+	}
+
+	if start > len(items) {
+		start = len(items)
+	}
+	end := start + maxResults
+	if end > len(items) {
+		end = len(items)
+	}
+
+	resp := map[string]interface{}{"kind": kind, listKey: items[start:end]}
+	if end < len(items) {
+		resp["nextPageToken"] = strconv.Itoa(end)
+	}
+	writeFields(w, r, resp)
+}
+
+func writeNotFound(w http.ResponseWriter, key string) {
+	writeError(w, http.StatusNotFound, "notFound", fmt.Sprintf("Resource Not Found: %v", key))
+}
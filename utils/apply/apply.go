@@ -0,0 +1,608 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package apply
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+
+	valid "github.com/asaskevich/govalidator"
+	cmn "github.com/plusworx/gmin/utils/common"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	grpset "github.com/plusworx/gmin/utils/groupsettings"
+	lg "github.com/plusworx/gmin/utils/logging"
+	mems "github.com/plusworx/gmin/utils/members"
+	admin "google.golang.org/api/admin/directory/v1"
+	gset "google.golang.org/api/groupssettings/v1"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// DEFAULTROLE is the role given to state file members that don't have one
+	DEFAULTROLE string = "MEMBER"
+	// ROOTORGUNIT is the path of the top level orgunit
+	ROOTORGUNIT string = "/"
+)
+
+// Action is a change that is needed to make the tenant match desired state
+type Action struct {
+	// CallType is one of cmn.CALLTYPECREATE, cmn.CALLTYPEDELETE or cmn.CALLTYPEUPDATE
+	CallType int
+	Changes  []string
+	// Group is the group email address of member and group settings actions or the primary email
+	// address of alias actions
+	Group string
+	Key   string
+	// Object is the *admin.OrgUnit, *admin.Group, *gset.Groups, *admin.Member, *admin.Alias or
+	// *admin.User to send
+	Object interface{}
+	// ObjType is one of cmn.OBJTYPEGROUP, cmn.OBJTYPEGRPALIAS, cmn.OBJTYPEGRPSET, cmn.OBJTYPEMEMBER,
+	// cmn.OBJTYPEORGUNIT, cmn.OBJTYPEUSER or cmn.OBJTYPEUSRALIAS
+	ObjType int
+}
+
+// Current holds tenant objects that are compared with desired state
+type Current struct {
+	// GroupSettings are keyed by lowercase group email address
+	GroupSettings map[string]*gset.Groups
+	// Groups are keyed by lowercase group email address
+	Groups map[string]*admin.Group
+	// Members are keyed by lowercase group email address and then lowercase member email address
+	Members map[string]map[string]*admin.Member
+	// OrgUnits are keyed by orgunit path
+	OrgUnits map[string]*admin.OrgUnit
+	// Users is keyed by lower case primary email address
+	Users map[string]*admin.User
+}
+
+// Group is desired state of a group. Settings are only compared if present and members only if
+// the members list is present, in which case members that aren't listed are deleted.
+type Group struct {
+	Description string                 `yaml:"description,omitempty"`
+	Email       string                 `yaml:"email"`
+	Members     []Member               `yaml:"members,omitempty"`
+	Name        string                 `yaml:"name,omitempty"`
+	Settings    map[string]interface{} `yaml:"settings,omitempty"`
+}
+
+// Member is desired state of a group member
+type Member struct {
+	Email string `yaml:"email"`
+	Role  string `yaml:"role,omitempty"`
+}
+
+// OrgUnit is desired state of an orgunit
+type OrgUnit struct {
+	BlockInheritance *bool  `yaml:"blockInheritance,omitempty"`
+	Description      string `yaml:"description,omitempty"`
+	Path             string `yaml:"path"`
+}
+
+// State is desired state read from a state file
+type State struct {
+	Groups   []Group   `yaml:"groups,omitempty"`
+	OrgUnits []OrgUnit `yaml:"orgunits,omitempty"`
+}
+
+// NewCurrent returns an empty Current
+func NewCurrent() *Current {
+	return &Current{
+		GroupSettings: map[string]*gset.Groups{},
+		Groups:        map[string]*admin.Group{},
+		Members:       map[string]map[string]*admin.Member{},
+		OrgUnits:      map[string]*admin.OrgUnit{},
+		Users:         map[string]*admin.User{},
+	}
+}
+
+// Load reads and validates a state file
+func Load(filePath string) (*State, error) {
+	lg.Debugw("starting Load()",
+		"filePath", filePath)
+	defer lg.Debug("finished Load()")
+
+	yamlBytes, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	state := new(State)
+	err = yaml.UnmarshalStrict(yamlBytes, state)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	err = state.validate()
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// Plan returns the actions needed to make the tenant match desired state in the order that they
+// should be carried out. Parent orgunits come before children, groups come before their settings
+// and members, and deletions come last. If prune is true then orgunits and groups that aren't
+// in desired state are deleted.
+func Plan(state *State, current *Current, prune bool) ([]Action, error) {
+	lg.Debugw("starting Plan()",
+		"prune", prune)
+	defer lg.Debug("finished Plan()")
+
+	var (
+		grpActions    []Action
+		grpDelActions []Action
+		gsActions     []Action
+		memActions    []Action
+		memDelActions []Action
+		ouActions     []Action
+		ouDelActions  []Action
+	)
+
+	wantedOUs := map[string]bool{}
+	for _, ou := range state.OrgUnits {
+		wantedOUs[ou.Path] = true
+	}
+
+	for _, ou := range sortedOrgUnits(state.OrgUnits) {
+		parent := path.Dir(ou.Path)
+		if parent != ROOTORGUNIT && !wantedOUs[parent] && current.OrgUnits[parent] == nil {
+			err := fmt.Errorf(gmess.ERR_NOPARENTORGUNIT, ou.Path)
+			lg.Error(err)
+			return nil, err
+		}
+
+		action, err := orgUnitAction(ou, current.OrgUnits[ou.Path])
+		if err != nil {
+			return nil, err
+		}
+		if action != nil {
+			ouActions = append(ouActions, *action)
+		}
+	}
+
+	wantedGrps := map[string]bool{}
+	for _, grp := range state.Groups {
+		email := strings.ToLower(grp.Email)
+		wantedGrps[email] = true
+
+		action, err := groupAction(grp, current.Groups[email])
+		if err != nil {
+			return nil, err
+		}
+		if action != nil {
+			grpActions = append(grpActions, *action)
+		}
+
+		if grp.Settings != nil {
+			action, err := groupSettingsAction(grp, current.GroupSettings[email])
+			if err != nil {
+				return nil, err
+			}
+			if action != nil {
+				gsActions = append(gsActions, *action)
+			}
+		}
+
+		if grp.Members != nil {
+			actions, delActions, err := memberActions(grp, current.Members[email])
+			if err != nil {
+				return nil, err
+			}
+			memActions = append(memActions, actions...)
+			memDelActions = append(memDelActions, delActions...)
+		}
+	}
+
+	if prune {
+		for _, email := range sortedKeys(current.Groups) {
+			if !wantedGrps[email] {
+				grpDelActions = append(grpDelActions, Action{CallType: cmn.CALLTYPEDELETE, Key: current.Groups[email].Email, ObjType: cmn.OBJTYPEGROUP})
+			}
+		}
+
+		ouPaths := sortedKeys(current.OrgUnits)
+		// Children are deleted before parents
+		sort.SliceStable(ouPaths, func(i, j int) bool { return ouDepth(ouPaths[i]) > ouDepth(ouPaths[j]) })
+		for _, ouPath := range ouPaths {
+			if ouPath != ROOTORGUNIT && !wantedOUs[ouPath] {
+				ouDelActions = append(ouDelActions, Action{CallType: cmn.CALLTYPEDELETE, Key: ouPath, ObjType: cmn.OBJTYPEORGUNIT})
+			}
+		}
+	}
+
+	plan := []Action{}
+	for _, actions := range [][]Action{ouActions, grpActions, gsActions, memActions, memDelActions, grpDelActions, ouDelActions} {
+		plan = append(plan, actions...)
+	}
+	return plan, nil
+}
+
+// Summary returns the number of create, update and delete actions in a plan
+func Summary(plan []Action) (int, int, int) {
+	var creates, deletes, updates int
+
+	for _, action := range plan {
+		switch action.CallType {
+		case cmn.CALLTYPECREATE:
+			creates++
+		case cmn.CALLTYPEDELETE:
+			deletes++
+		case cmn.CALLTYPEUPDATE:
+			updates++
+		}
+	}
+	return creates, updates, deletes
+}
+
+// String describes an action and its changes
+func (a Action) String() string {
+	var (
+		objName string
+		verb    string
+	)
+
+	switch a.ObjType {
+	case cmn.OBJTYPEGROUP:
+		objName = "group"
+	case cmn.OBJTYPEGRPALIAS:
+		objName = "group-alias"
+	case cmn.OBJTYPEGRPSET:
+		objName = "group-settings"
+	case cmn.OBJTYPEMEMBER:
+		objName = "group-member"
+	case cmn.OBJTYPEORGUNIT:
+		objName = "orgunit"
+	case cmn.OBJTYPEUSER:
+		objName = "user"
+	case cmn.OBJTYPEUSRALIAS:
+		objName = "user-alias"
+	}
+
+	switch a.CallType {
+	case cmn.CALLTYPECREATE:
+		verb = "+ create"
+	case cmn.CALLTYPEDELETE:
+		verb = "- delete"
+	case cmn.CALLTYPEUPDATE:
+		verb = "~ update"
+	}
+
+	desc := verb + " " + objName + " " + a.Key
+	switch a.ObjType {
+	case cmn.OBJTYPEGRPALIAS, cmn.OBJTYPEUSRALIAS:
+		desc = desc + " for " + a.Group
+	case cmn.OBJTYPEMEMBER:
+		desc = desc + " in group " + a.Group
+	}
+	for _, change := range a.Changes {
+		desc = desc + "\n    " + change
+	}
+	return desc
+}
+
+func groupAction(grp Group, curGrp *admin.Group) (*Action, error) {
+	lg.Debugw("starting groupAction()",
+		"email", grp.Email)
+	defer lg.Debug("finished groupAction()")
+
+	group := &admin.Group{Description: grp.Description, Email: grp.Email, Name: grp.Name}
+
+	if curGrp == nil {
+		return newAction(cmn.CALLTYPECREATE, cmn.OBJTYPEGROUP, grp.Email, "", nil, group)
+	}
+	// Email address is the key so there is no need to compare it
+	group.Email = ""
+	return newAction(cmn.CALLTYPEUPDATE, cmn.OBJTYPEGROUP, grp.Email, "", curGrp, group)
+}
+
+func groupSettingsAction(grp Group, curSettings *gset.Groups) (*Action, error) {
+	lg.Debugw("starting groupSettingsAction()",
+		"email", grp.Email)
+	defer lg.Debug("finished groupSettingsAction()")
+
+	var (
+		hdrMap  = map[int]string{}
+		objData []interface{}
+	)
+
+	for idx, name := range sortedKeys(grp.Settings) {
+		attrName, err := cmn.IsValidAttr(name, grpset.GroupSettingsAttrMap)
+		if err != nil {
+			return nil, err
+		}
+		hdrMap[idx] = attrName
+		objData = append(objData, grp.Settings[name])
+	}
+
+	grpParams := grpset.GroupParams{GroupKey: grp.Email, Settings: new(gset.Groups)}
+	err := grpset.PopulateGroupSettings(&grpParams, hdrMap, objData)
+	if err != nil {
+		return nil, err
+	}
+
+	return newAction(cmn.CALLTYPEUPDATE, cmn.OBJTYPEGRPSET, grp.Email, grp.Email, curSettings, grpParams.Settings)
+}
+
+func memberActions(grp Group, curMembers map[string]*admin.Member) ([]Action, []Action, error) {
+	lg.Debugw("starting memberActions()",
+		"email", grp.Email)
+	defer lg.Debug("finished memberActions()")
+
+	var (
+		actions    []Action
+		delActions []Action
+		wanted     = map[string]bool{}
+	)
+
+	for _, mem := range grp.Members {
+		email := strings.ToLower(mem.Email)
+		wanted[email] = true
+
+		role := mem.Role
+		if role == "" {
+			role = DEFAULTROLE
+		}
+		validRole, err := mems.ValidateRole(role)
+		if err != nil {
+			lg.Error(err)
+			return nil, nil, err
+		}
+
+		curMem := curMembers[email]
+		callType := cmn.CALLTYPEUPDATE
+		member := &admin.Member{Role: validRole}
+		if curMem == nil {
+			callType = cmn.CALLTYPECREATE
+			member.Email = mem.Email
+		}
+
+		action, err := newAction(callType, cmn.OBJTYPEMEMBER, mem.Email, grp.Email, curMem, member)
+		if err != nil {
+			return nil, nil, err
+		}
+		if action != nil {
+			actions = append(actions, *action)
+		}
+	}
+
+	// Members without an email address, such as the customer, are never deleted
+	for _, email := range sortedKeys(curMembers) {
+		if curMembers[email].Email == "" {
+			continue
+		}
+		if !wanted[email] {
+			delActions = append(delActions, Action{CallType: cmn.CALLTYPEDELETE, Group: grp.Email, Key: curMembers[email].Email, ObjType: cmn.OBJTYPEMEMBER})
+		}
+	}
+	return actions, delActions, nil
+}
+
+// newAction returns an action for sending obj if it changes anything in current. A nil action
+// is returned if there is nothing to change.
+func newAction(callType int, objType int, key string, group string, current interface{}, obj interface{}) (*Action, error) {
+	lg.Debugw("starting newAction()",
+		"key", key)
+	defer lg.Debug("finished newAction()")
+
+	var curMap map[string]interface{}
+
+	if !isNil(current) {
+		var err error
+		curMap, err = objectMap(current)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	objMap, err := objectMap(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	// The API leaves out false and empty values so they are the same as missing current values
+	if curMap != nil {
+		for key, val := range objMap {
+			if _, exists := curMap[key]; !exists && isZero(val) {
+				curMap[key] = val
+			}
+		}
+	}
+
+	changes := cmn.DryRunChanges(curMap, objMap)
+	if callType == cmn.CALLTYPEUPDATE && len(changes) == 0 {
+		return nil, nil
+	}
+	return &Action{CallType: callType, Changes: changes, Group: group, Key: key, Object: obj, ObjType: objType}, nil
+}
+
+// isNil checks for nil values of the current object pointer types
+func isNil(obj interface{}) bool {
+	switch typedObj := obj.(type) {
+	case *admin.Group:
+		return typedObj == nil
+	case *admin.Member:
+		return typedObj == nil
+	case *admin.OrgUnit:
+		return typedObj == nil
+	case *admin.User:
+		return typedObj == nil
+	case *gset.Groups:
+		return typedObj == nil
+	}
+	return obj == nil
+}
+
+// isZero checks for the false and empty values that the API leaves out of objects
+func isZero(val interface{}) bool {
+	switch typedVal := val.(type) {
+	case bool:
+		return !typedVal
+	case float64:
+		return typedVal == 0
+	case string:
+		return typedVal == ""
+	}
+	return false
+}
+
+// objectMap converts an API object to a map of the attributes that would be sent
+func objectMap(obj interface{}) (map[string]interface{}, error) {
+	jsonBytes, err := json.Marshal(obj)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	objMap := map[string]interface{}{}
+	err = json.Unmarshal(jsonBytes, &objMap)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+	return objMap, nil
+}
+
+func orgUnitAction(ou OrgUnit, curOU *admin.OrgUnit) (*Action, error) {
+	lg.Debugw("starting orgUnitAction()",
+		"path", ou.Path)
+	defer lg.Debug("finished orgUnitAction()")
+
+	orgunit := &admin.OrgUnit{Description: ou.Description}
+	if ou.BlockInheritance != nil {
+		orgunit.BlockInheritance = *ou.BlockInheritance
+		orgunit.ForceSendFields = []string{"BlockInheritance"}
+	}
+
+	if curOU == nil {
+		orgunit.Name = path.Base(ou.Path)
+		orgunit.ParentOrgUnitPath = path.Dir(ou.Path)
+		return newAction(cmn.CALLTYPECREATE, cmn.OBJTYPEORGUNIT, ou.Path, "", nil, orgunit)
+	}
+	return newAction(cmn.CALLTYPEUPDATE, cmn.OBJTYPEORGUNIT, ou.Path, "", curOU, orgunit)
+}
+
+func ouDepth(ouPath string) int {
+	return strings.Count(strings.TrimSuffix(ouPath, "/"), "/")
+}
+
+func sortedKeys(m interface{}) []string {
+	keys := []string{}
+
+	switch typedMap := m.(type) {
+	case map[string]*admin.Group:
+		for key := range typedMap {
+			keys = append(keys, key)
+		}
+	case map[string]*admin.Member:
+		for key := range typedMap {
+			keys = append(keys, key)
+		}
+	case map[string]*admin.OrgUnit:
+		for key := range typedMap {
+			keys = append(keys, key)
+		}
+	case map[string]interface{}:
+		for key := range typedMap {
+			keys = append(keys, key)
+		}
+	case map[string]string:
+		for key := range typedMap {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// sortedOrgUnits returns orgunits with parents before children
+func sortedOrgUnits(orgunits []OrgUnit) []OrgUnit {
+	sorted := make([]OrgUnit, len(orgunits))
+	copy(sorted, orgunits)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if ouDepth(sorted[i].Path) != ouDepth(sorted[j].Path) {
+			return ouDepth(sorted[i].Path) < ouDepth(sorted[j].Path)
+		}
+		return sorted[i].Path < sorted[j].Path
+	})
+	return sorted
+}
+
+func (s *State) validate() error {
+	lg.Debug("starting validate()")
+	defer lg.Debug("finished validate()")
+
+	ouPaths := map[string]bool{}
+	for _, ou := range s.OrgUnits {
+		if !strings.HasPrefix(ou.Path, "/") || ou.Path == ROOTORGUNIT || strings.HasSuffix(ou.Path, "/") {
+			err := fmt.Errorf(gmess.ERR_INVALIDORGUNITPATH, ou.Path)
+			lg.Error(err)
+			return err
+		}
+		if ouPaths[ou.Path] {
+			err := fmt.Errorf(gmess.ERR_DUPLICATEINSTATE, ou.Path)
+			lg.Error(err)
+			return err
+		}
+		ouPaths[ou.Path] = true
+	}
+
+	grpEmails := map[string]bool{}
+	for _, grp := range s.Groups {
+		email := strings.ToLower(grp.Email)
+		if !valid.IsEmail(email) {
+			err := fmt.Errorf(gmess.ERR_INVALIDEMAILADDRESS, grp.Email)
+			lg.Error(err)
+			return err
+		}
+		if grpEmails[email] {
+			err := fmt.Errorf(gmess.ERR_DUPLICATEINSTATE, grp.Email)
+			lg.Error(err)
+			return err
+		}
+		grpEmails[email] = true
+
+		memEmails := map[string]bool{}
+		for _, mem := range grp.Members {
+			memEmail := strings.ToLower(mem.Email)
+			if !valid.IsEmail(memEmail) {
+				err := fmt.Errorf(gmess.ERR_INVALIDEMAILADDRESS, mem.Email)
+				lg.Error(err)
+				return err
+			}
+			if memEmails[memEmail] {
+				err := fmt.Errorf(gmess.ERR_DUPLICATEINSTATE, grp.Email+" member "+mem.Email)
+				lg.Error(err)
+				return err
+			}
+			memEmails[memEmail] = true
+		}
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package apply
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	lg "github.com/plusworx/gmin/utils/logging"
+	admin "google.golang.org/api/admin/directory/v1"
+	gset "google.golang.org/api/groupssettings/v1"
+)
+
+func TestLoad(t *testing.T) {
+	cases := []struct {
+		content     string
+		expectedErr string
+	}{
+		{
+			content: "orgunits:\n  - path: /Sales\n  - path: /Sales/EMEA\ngroups:\n  - email: sales@mycompany.org\n    members:\n      - email: a.person@mycompany.org\n",
+		},
+		{
+			content:     "orgunits:\n  - path: Sales\n",
+			expectedErr: "invalid orgunit path: Sales",
+		},
+		{
+			content:     "orgunits:\n  - path: /Sales/\n",
+			expectedErr: "invalid orgunit path: /Sales/",
+		},
+		{
+			content:     "groups:\n  - email: sales@mycompany.org\n  - email: Sales@mycompany.org\n",
+			expectedErr: "Sales@mycompany.org is in state file more than once",
+		},
+		{
+			content:     "groups:\n  - email: sales\n",
+			expectedErr: "invalid email address: sales",
+		},
+		{
+			content:     "groups:\n  - email: sales@mycompany.org\n    owner: a.person@mycompany.org\n",
+			expectedErr: "yaml: unmarshal errors:\n  line 3: field owner not found in type apply.Group",
+		},
+	}
+
+	dir := t.TempDir()
+
+	lg.InitLogging("info")
+
+	for _, c := range cases {
+		filePath := filepath.Join(dir, "state.yaml")
+		ioutil.WriteFile(filePath, []byte(c.content), 0644)
+
+		_, got := Load(filePath)
+
+		gotErr := ""
+		if got != nil {
+			gotErr = got.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("Got error: %v - expected error: %v", gotErr, c.expectedErr)
+		}
+	}
+}
+
+func TestPlan(t *testing.T) {
+	lg.InitLogging("info")
+
+	noBlock := false
+
+	current := NewCurrent()
+	current.OrgUnits["/Old"] = &admin.OrgUnit{Name: "Old", OrgUnitPath: "/Old"}
+	current.OrgUnits["/Old/Older"] = &admin.OrgUnit{Name: "Older", OrgUnitPath: "/Old/Older"}
+	current.OrgUnits["/Sales"] = &admin.OrgUnit{Description: "Sales", Name: "Sales", OrgUnitPath: "/Sales"}
+	current.Groups["old@mycompany.org"] = &admin.Group{Email: "old@mycompany.org"}
+	current.Groups["sales@mycompany.org"] = &admin.Group{Email: "sales@mycompany.org", Name: "Sales"}
+	current.GroupSettings["sales@mycompany.org"] = &gset.Groups{WhoCanJoin: "CAN_REQUEST_TO_JOIN"}
+	current.Members["sales@mycompany.org"] = map[string]*admin.Member{
+		"":                       {Id: "C01234567", Role: "MEMBER", Type: "CUSTOMER"},
+		"a.person@mycompany.org": {Email: "a.person@mycompany.org", Role: "MEMBER"},
+		"b.person@mycompany.org": {Email: "b.person@mycompany.org", Role: "MEMBER"},
+	}
+
+	state := &State{
+		Groups: []Group{
+			{
+				Email: "sales@mycompany.org",
+				Members: []Member{
+					{Email: "a.person@mycompany.org", Role: "owner"},
+					{Email: "c.person@mycompany.org"},
+				},
+				Name:     "Sales",
+				Settings: map[string]interface{}{"whoCanJoin": "invited_can_join"},
+			},
+			{Email: "support@mycompany.org", Name: "Support"},
+		},
+		OrgUnits: []OrgUnit{
+			{Path: "/Sales/EMEA/UK"},
+			{Path: "/Sales/EMEA"},
+			{BlockInheritance: &noBlock, Description: "Sales", Path: "/Sales"},
+		},
+	}
+
+	cases := []struct {
+		prune    bool
+		expected []string
+	}{
+		{
+			expected: []string{
+				"+ create orgunit /Sales/EMEA\n    + name: \"EMEA\"\n    + parentOrgUnitPath: \"/Sales\"",
+				"+ create orgunit /Sales/EMEA/UK\n    + name: \"UK\"\n    + parentOrgUnitPath: \"/Sales/EMEA\"",
+				"+ create group support@mycompany.org\n    + email: \"support@mycompany.org\"\n    + name: \"Support\"",
+				"~ update group-settings sales@mycompany.org\n    ~ whoCanJoin: \"CAN_REQUEST_TO_JOIN\" -> \"INVITED_CAN_JOIN\"",
+				"~ update group-member a.person@mycompany.org in group sales@mycompany.org\n    ~ role: \"MEMBER\" -> \"OWNER\"",
+				"+ create group-member c.person@mycompany.org in group sales@mycompany.org\n    + email: \"c.person@mycompany.org\"\n    + role: \"MEMBER\"",
+				"- delete group-member b.person@mycompany.org in group sales@mycompany.org",
+			},
+		},
+		{
+			prune: true,
+			expected: []string{
+				"+ create orgunit /Sales/EMEA\n    + name: \"EMEA\"\n    + parentOrgUnitPath: \"/Sales\"",
+				"+ create orgunit /Sales/EMEA/UK\n    + name: \"UK\"\n    + parentOrgUnitPath: \"/Sales/EMEA\"",
+				"+ create group support@mycompany.org\n    + email: \"support@mycompany.org\"\n    + name: \"Support\"",
+				"~ update group-settings sales@mycompany.org\n    ~ whoCanJoin: \"CAN_REQUEST_TO_JOIN\" -> \"INVITED_CAN_JOIN\"",
+				"~ update group-member a.person@mycompany.org in group sales@mycompany.org\n    ~ role: \"MEMBER\" -> \"OWNER\"",
+				"+ create group-member c.person@mycompany.org in group sales@mycompany.org\n    + email: \"c.person@mycompany.org\"\n    + role: \"MEMBER\"",
+				"- delete group-member b.person@mycompany.org in group sales@mycompany.org",
+				"- delete group old@mycompany.org",
+				"- delete orgunit /Old/Older",
+				"- delete orgunit /Old",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		plan, err := Plan(state, current, c.prune)
+		if err != nil {
+			t.Fatalf("Got error: %v - expected error: nil", err)
+		}
+
+		if len(plan) != len(c.expected) {
+			t.Errorf("Got plan length: %v - expected plan length: %v", len(plan), len(c.expected))
+			continue
+		}
+		for idx, action := range plan {
+			if action.String() != c.expected[idx] {
+				t.Errorf("Got action: %v - expected action: %v", action, c.expected[idx])
+			}
+		}
+	}
+
+	_, err := Plan(&State{OrgUnits: []OrgUnit{{Path: "/Marketing/EMEA"}}}, current, false)
+	if err == nil || err.Error() != "parent orgunit is not in state file or tenant: /Marketing/EMEA" {
+		t.Errorf("Got error: %v - expected error: parent orgunit is not in state file or tenant: /Marketing/EMEA", err)
+	}
+}
+
+func TestSyncMembersPlan(t *testing.T) {
+	lg.InitLogging("info")
+
+	current := map[string]*admin.Member{
+		"a.person@mycompany.org": {Email: "a.person@mycompany.org", Role: "MANAGER"},
+		"b.person@mycompany.org": {Email: "b.person@mycompany.org", Role: "MEMBER"},
+		"boss@mycompany.org":     {Email: "boss@mycompany.org", Role: "OWNER"},
+	}
+	members := []Member{
+		{Email: "A.Person@mycompany.org"},
+		{Email: "boss@mycompany.org", Role: "member"},
+		{Email: "c.person@mycompany.org", Role: "manager"},
+	}
+
+	cases := []struct {
+		expected      []string
+		expProtected  int
+		protectOwners bool
+	}{
+		{
+			expected: []string{
+				"~ update group-member boss@mycompany.org in group sales@mycompany.org\n    ~ role: \"OWNER\" -> \"MEMBER\"",
+				"+ create group-member c.person@mycompany.org in group sales@mycompany.org\n    + email: \"c.person@mycompany.org\"\n    + role: \"MANAGER\"",
+				"- delete group-member b.person@mycompany.org in group sales@mycompany.org",
+			},
+		},
+		{
+			expected: []string{
+				"+ create group-member c.person@mycompany.org in group sales@mycompany.org\n    + email: \"c.person@mycompany.org\"\n    + role: \"MANAGER\"",
+				"- delete group-member b.person@mycompany.org in group sales@mycompany.org",
+			},
+			expProtected:  1,
+			protectOwners: true,
+		},
+	}
+
+	for _, c := range cases {
+		plan, protected, err := SyncMembersPlan("sales@mycompany.org", members, current, c.protectOwners, false)
+		if err != nil {
+			t.Fatalf("Got error: %v - expected error: nil", err)
+		}
+
+		if len(protected) != c.expProtected {
+			t.Errorf("Got protected: %v - expected protected: %v", len(protected), c.expProtected)
+		}
+		if len(plan) != len(c.expected) {
+			t.Errorf("Got plan length: %v - expected plan length: %v", len(plan), len(c.expected))
+			continue
+		}
+		for idx, action := range plan {
+			if action.String() != c.expected[idx] {
+				t.Errorf("Got action: %v - expected action: %v", action, c.expected[idx])
+			}
+		}
+	}
+
+	_, _, err := SyncMembersPlan("sales@mycompany.org", []Member{{Email: "c.person@mycompany.org"}, {Email: "C.Person@mycompany.org"}}, current, false, false)
+	if err == nil || err.Error() != "member: C.Person@mycompany.org is listed more than once" {
+		t.Errorf("Got error: %v - expected error: member: C.Person@mycompany.org is listed more than once", err)
+	}
+
+	_, _, err = SyncMembersPlan("sales@mycompany.org", []Member{}, current, false, false)
+	if err == nil || err.Error() != "member list is empty - use --allow-empty to delete every member of group: sales@mycompany.org" {
+		t.Errorf("Got error: %v - expected error: member list is empty - use --allow-empty to delete every member of group: sales@mycompany.org", err)
+	}
+
+	plan, _, err := SyncMembersPlan("sales@mycompany.org", []Member{}, current, false, true)
+	if err != nil {
+		t.Fatalf("Got error: %v - expected error: nil", err)
+	}
+	if len(plan) != len(current) {
+		t.Errorf("Got plan length: %v - expected plan length: %v", len(plan), len(current))
+	}
+}
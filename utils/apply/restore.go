@@ -0,0 +1,300 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package apply
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	lg "github.com/plusworx/gmin/utils/logging"
+	snap "github.com/plusworx/gmin/utils/snapshot"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+// restoreUserAttrs are the user attributes that are reverted to their snapshot values
+var restoreUserAttrs = []string{
+	"addresses",
+	"archived",
+	"customSchemas",
+	"externalIds",
+	"gender",
+	"includeInGlobalAddressList",
+	"ipWhitelisted",
+	"keywords",
+	"languages",
+	"locations",
+	"name",
+	"notes",
+	"orgUnitPath",
+	"organizations",
+	"phones",
+	"posixAccounts",
+	"recoveryEmail",
+	"recoveryPhone",
+	"relations",
+	"sshPublicKeys",
+	"suspended",
+	"websites",
+}
+
+// restoreUserBools maps boolean user attributes to their admin.User field names. False values are
+// not written to snapshots so missing values are restored as false.
+var restoreUserBools = map[string]string{
+	"archived":                   "Archived",
+	"includeInGlobalAddressList": "IncludeInGlobalAddressList",
+	"ipWhitelisted":              "IpWhitelisted",
+	"suspended":                  "Suspended",
+}
+
+// RestorePlan returns the actions needed to recreate snapshot orgunits, groups, members and aliases
+// that are missing from the tenant and to revert changed orgunit, group, group settings, member and
+// user attributes to their snapshot values. Objects that have been created since the snapshot are left
+// alone and deleted users are not recreated.
+func RestorePlan(snapshot *snap.Snapshot, current *Current) ([]Action, error) {
+	lg.Debug("starting RestorePlan()")
+	defer lg.Debug("finished RestorePlan()")
+
+	var (
+		aliasActions []Action
+		grpActions   []Action
+		gsActions    []Action
+		memActions   []Action
+		ouActions    []Action
+		userActions  []Action
+	)
+
+	orgunits := make([]*admin.OrgUnit, len(snapshot.OrgUnits))
+	copy(orgunits, snapshot.OrgUnits)
+	// Parents are created before children
+	sort.SliceStable(orgunits, func(i, j int) bool {
+		return ouDepth(orgunits[i].OrgUnitPath) < ouDepth(orgunits[j].OrgUnitPath)
+	})
+	for _, ou := range orgunits {
+		restored := OrgUnit{Description: ou.Description, Path: ou.OrgUnitPath}
+		curOU := current.OrgUnits[ou.OrgUnitPath]
+		// False block inheritance is only sent if it needs to be reverted
+		if ou.BlockInheritance || (curOU != nil && curOU.BlockInheritance) {
+			blockInheritance := ou.BlockInheritance
+			restored.BlockInheritance = &blockInheritance
+		}
+
+		action, err := orgUnitAction(restored, curOU)
+		if err != nil {
+			return nil, err
+		}
+		if action != nil {
+			ouActions = append(ouActions, *action)
+		}
+	}
+
+	restoredGrps := map[string]bool{}
+	for _, grp := range snapshot.Groups {
+		email := strings.ToLower(grp.Email)
+		restoredGrps[email] = true
+
+		action, err := groupAction(Group{Description: grp.Description, Email: grp.Email, Name: grp.Name}, current.Groups[email])
+		if err != nil {
+			return nil, err
+		}
+		if action != nil {
+			grpActions = append(grpActions, *action)
+		}
+	}
+
+	for _, settings := range snapshot.GroupSettings {
+		email := strings.ToLower(settings.Email)
+		if current.Groups[email] == nil && !restoredGrps[email] {
+			continue
+		}
+
+		// Email, name and description belong to the group and kind is read only
+		restored := *settings
+		restored.Description = ""
+		restored.Email = ""
+		restored.Kind = ""
+		restored.Name = ""
+
+		action, err := newAction(cmn.CALLTYPEUPDATE, cmn.OBJTYPEGRPSET, settings.Email, settings.Email, current.GroupSettings[email], &restored)
+		if err != nil {
+			return nil, err
+		}
+		if action != nil {
+			gsActions = append(gsActions, *action)
+		}
+	}
+
+	for _, mem := range snapshot.Members {
+		grpEmail := strings.ToLower(mem.Group)
+		if current.Groups[grpEmail] == nil && !restoredGrps[grpEmail] {
+			continue
+		}
+
+		key := mem.Member.Email
+		if key == "" {
+			key = mem.Member.Id
+		}
+		curMem := current.Members[grpEmail][strings.ToLower(key)]
+
+		callType := cmn.CALLTYPEUPDATE
+		member := &admin.Member{DeliverySettings: mem.Member.DeliverySettings, Role: mem.Member.Role}
+		if curMem == nil {
+			callType = cmn.CALLTYPECREATE
+			member.Email = mem.Member.Email
+			member.Id = mem.Member.Id
+			member.Type = mem.Member.Type
+		}
+
+		action, err := newAction(callType, cmn.OBJTYPEMEMBER, key, mem.Group, curMem, member)
+		if err != nil {
+			return nil, err
+		}
+		if action != nil {
+			memActions = append(memActions, *action)
+		}
+	}
+
+	for _, alias := range snapshot.Aliases {
+		action := aliasAction(alias, current, restoredGrps)
+		if action != nil {
+			aliasActions = append(aliasActions, *action)
+		}
+	}
+
+	for _, user := range snapshot.Users {
+		curUser := current.Users[strings.ToLower(user.PrimaryEmail)]
+		if curUser == nil {
+			lg.Warnw("user in snapshot not found - skipping",
+				"user", user.PrimaryEmail)
+			continue
+		}
+
+		action, err := userAction(user, curUser)
+		if err != nil {
+			return nil, err
+		}
+		if action != nil {
+			userActions = append(userActions, *action)
+		}
+	}
+
+	plan := []Action{}
+	for _, actions := range [][]Action{ouActions, grpActions, gsActions, memActions, aliasActions, userActions} {
+		plan = append(plan, actions...)
+	}
+	return plan, nil
+}
+
+// aliasAction returns an action to create an alias that is missing from its group or user. A nil
+// action is returned if the alias exists or its group or user can't be found.
+func aliasAction(alias snap.Alias, current *Current, restoredGrps map[string]bool) *Action {
+	lg.Debugw("starting aliasAction()",
+		"alias", alias.Alias)
+	defer lg.Debug("finished aliasAction()")
+
+	var (
+		aliases []string
+		objType int
+		owner   = strings.ToLower(alias.PrimaryEmail)
+	)
+
+	switch alias.Type {
+	case snap.ALIASTYPEGROUP:
+		objType = cmn.OBJTYPEGRPALIAS
+		curGrp := current.Groups[owner]
+		if curGrp == nil && !restoredGrps[owner] {
+			lg.Warnw("group of alias in snapshot not found - skipping",
+				"alias", alias.Alias)
+			return nil
+		}
+		if curGrp != nil {
+			aliases = curGrp.Aliases
+		}
+	case snap.ALIASTYPEUSER:
+		objType = cmn.OBJTYPEUSRALIAS
+		curUser := current.Users[owner]
+		if curUser == nil {
+			lg.Warnw("user of alias in snapshot not found - skipping",
+				"alias", alias.Alias)
+			return nil
+		}
+		aliases = curUser.Aliases
+	default:
+		return nil
+	}
+
+	for _, curAlias := range aliases {
+		if strings.EqualFold(curAlias, alias.Alias) {
+			return nil
+		}
+	}
+
+	return &Action{
+		CallType: cmn.CALLTYPECREATE,
+		Changes:  []string{"+ alias: \"" + alias.Alias + "\""},
+		Group:    alias.PrimaryEmail,
+		Key:      alias.Alias,
+		Object:   &admin.Alias{Alias: alias.Alias},
+		ObjType:  objType,
+	}
+}
+
+func userAction(user *admin.User, curUser *admin.User) (*Action, error) {
+	lg.Debugw("starting userAction()",
+		"email", user.PrimaryEmail)
+	defer lg.Debug("finished userAction()")
+
+	userMap, err := objectMap(user)
+	if err != nil {
+		return nil, err
+	}
+
+	restoreMap := map[string]interface{}{}
+	for _, attr := range restoreUserAttrs {
+		val, ok := userMap[attr]
+		if ok {
+			restoreMap[attr] = val
+		}
+	}
+
+	jsonBytes, err := json.Marshal(restoreMap)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	restored := new(admin.User)
+	err = json.Unmarshal(jsonBytes, restored)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+	for _, attr := range sortedKeys(restoreUserBools) {
+		if _, ok := restoreMap[attr]; !ok {
+			restored.ForceSendFields = append(restored.ForceSendFields, restoreUserBools[attr])
+		}
+	}
+
+	return newAction(cmn.CALLTYPEUPDATE, cmn.OBJTYPEUSER, user.PrimaryEmail, "", curUser, restored)
+}
@@ -0,0 +1,99 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package apply
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+// OWNERROLE is the role of group owners
+const OWNERROLE string = "OWNER"
+
+// SyncMembersPlan returns the actions needed to make the members of a group match a desired member list.
+// Members that are not in the list are deleted. A listed member without a role keeps their current
+// role or is created as a MEMBER. When protectOwners is true, current owners are never deleted or given a
+// different role and are returned separately so that they can be reported. An empty member list, which
+// deletes every member, is only accepted when allowEmpty is true.
+func SyncMembersPlan(group string, members []Member, curMembers map[string]*admin.Member, protectOwners bool, allowEmpty bool) ([]Action, []*admin.Member, error) {
+	lg.Debugw("starting SyncMembersPlan()",
+		"group", group,
+		"protectOwners", protectOwners,
+		"allowEmpty", allowEmpty)
+	defer lg.Debug("finished SyncMembersPlan()")
+
+	if len(members) == 0 && !allowEmpty {
+		err := fmt.Errorf(gmess.ERR_EMPTYSYNCMEMBERS, group)
+		lg.Error(err)
+		return nil, nil, err
+	}
+
+	var (
+		plan      []Action
+		protected []*admin.Member
+		wanted    = map[string]bool{}
+	)
+
+	grp := Group{Email: group}
+	for _, mem := range members {
+		if mem.Email == "" {
+			err := errors.New(gmess.ERR_NOMEMBEREMAILADDRESS)
+			lg.Error(err)
+			return nil, nil, err
+		}
+
+		email := strings.ToLower(mem.Email)
+		if wanted[email] {
+			err := fmt.Errorf(gmess.ERR_DUPLICATESYNCMEMBER, mem.Email)
+			lg.Error(err)
+			return nil, nil, err
+		}
+		wanted[email] = true
+
+		if curMem := curMembers[email]; curMem != nil && mem.Role == "" {
+			mem.Role = curMem.Role
+		}
+		grp.Members = append(grp.Members, mem)
+	}
+
+	actions, delActions, err := memberActions(grp, curMembers)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, action := range append(actions, delActions...) {
+		curMem := curMembers[strings.ToLower(action.Key)]
+		if protectOwners && action.CallType != cmn.CALLTYPECREATE && curMem.Role == OWNERROLE {
+			protected = append(protected, curMem)
+			continue
+		}
+		plan = append(plan, action)
+	}
+	return plan, protected, nil
+}
@@ -39,7 +39,10 @@ import (
 	lg "github.com/plusworx/gmin/utils/logging"
 	mems "github.com/plusworx/gmin/utils/members"
 	mdevs "github.com/plusworx/gmin/utils/mobiledevices"
+	onb "github.com/plusworx/gmin/utils/onboard"
 	ous "github.com/plusworx/gmin/utils/orgunits"
+	rsrcs "github.com/plusworx/gmin/utils/resources"
+	rls "github.com/plusworx/gmin/utils/roles"
 	usrs "github.com/plusworx/gmin/utils/users"
 	admin "google.golang.org/api/admin/directory/v1"
 	sheet "google.golang.org/api/sheets/v4"
@@ -71,24 +74,27 @@ func DeleteFromFileFactory(hdrMap map[int]string, objData []interface{}, keyName
 }
 
 // DeleteProcessGSheet does batch processing of Google Sheet input
-func DeleteProcessGSheet(sheetID string, sheetrange string, attrMap map[string]string, keyName string) ([]string, error) {
+func DeleteProcessGSheet(sheetID string, sheetrange string, attrMap map[string]string, keyName string) ([]string, *Input, error) {
 	lg.Debugw("starting DeleteProcessGSheet()",
 		"sheetID", sheetID,
 		"sheetrange", sheetrange)
 	defer lg.Debug("finished DeleteProcessGSheet()")
 
-	var outputObjs []string
+	var (
+		input      = &Input{Format: "gsheet"}
+		outputObjs []string
+	)
 
 	if sheetrange == "" {
 		err := errors.New(gmess.ERR_NOSHEETRANGE)
 		lg.Error(err)
-		return nil, err
+		return nil, nil, err
 	}
 
 	srv, err := cmn.CreateService(cmn.SRVTYPESHEET, sheet.DriveReadonlyScope)
 	if err != nil {
 		lg.Error(err)
-		return nil, err
+		return nil, nil, err
 	}
 	ss := srv.(*sheet.Service)
 
@@ -96,19 +102,20 @@ func DeleteProcessGSheet(sheetID string, sheetrange string, attrMap map[string]s
 	sValRange, err := ssvgc.Do()
 	if err != nil {
 		lg.Error(err)
-		return nil, err
+		return nil, nil, err
 	}
 
 	if len(sValRange.Values) == 0 {
 		err = fmt.Errorf(gmess.ERR_NOSHEETDATAFOUND, sheetID, sheetrange)
 		lg.Error(err)
-		return nil, err
+		return nil, nil, err
 	}
 
+	input.setHeader(sValRange.Values[0])
 	hdrMap := cmn.ProcessHeader(sValRange.Values[0])
 	err = cmn.ValidateHeader(hdrMap, attrMap)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	for idx, row := range sValRange.Values {
@@ -118,28 +125,32 @@ func DeleteProcessGSheet(sheetID string, sheetrange string, attrMap map[string]s
 
 		objVar, err := DeleteFromFileFactory(hdrMap, row, keyName)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		outputObjs = append(outputObjs, objVar)
+		input.addRow(row)
 	}
 
-	return outputObjs, nil
+	return outputObjs, input, nil
 }
 
 // DeleteProcessTextFile does batch processing of text input
-func DeleteProcessTextFile(filePath string, scanner *bufio.Scanner) ([]string, error) {
+func DeleteProcessTextFile(filePath string, scanner *bufio.Scanner) ([]string, *Input, error) {
 	lg.Debugw("starting DeleteProcessTextFile()",
 		"filePath", filePath)
 	defer lg.Debug("finished DeleteProcessTextFile()")
 
-	var outputObjs []string
+	var (
+		input      = &Input{Format: "text"}
+		outputObjs []string
+	)
 
 	if filePath != "" {
 		file, err := os.Open(filePath)
 		if err != nil {
 			lg.Error(err)
-			return nil, err
+			return nil, nil, err
 		}
 		defer file.Close()
 		scanner = bufio.NewScanner(file)
@@ -148,9 +159,10 @@ func DeleteProcessTextFile(filePath string, scanner *bufio.Scanner) ([]string, e
 	for scanner.Scan() {
 		obj := scanner.Text()
 		outputObjs = append(outputObjs, obj)
+		input.Rows = append(input.Rows, []string{obj})
 	}
 
-	return outputObjs, nil
+	return outputObjs, input, nil
 }
 
 // FromFileFactory produces objects from input file data
@@ -160,6 +172,38 @@ func FromFileFactory(callParams CallParams, hdrMap map[int]string, objData []int
 	defer lg.Debug("finished FromFileFactory()")
 
 	switch callParams.ObjectType {
+	case cmn.OBJTYPEBUILDING:
+		building := new(admin.Building)
+		if callParams.CallType == cmn.CALLTYPECREATE {
+			err := rsrcs.PopulateBuilding(building, hdrMap, objData)
+			if err != nil {
+				return nil, err
+			}
+			return building, nil
+		}
+		if callParams.CallType == cmn.CALLTYPEUPDATE {
+			err := rsrcs.PopulateBuildingForUpdate(building, hdrMap, objData)
+			if err != nil {
+				return nil, err
+			}
+			return building, nil
+		}
+	case cmn.OBJTYPECALRES:
+		calendar := new(admin.CalendarResource)
+		if callParams.CallType == cmn.CALLTYPECREATE {
+			err := rsrcs.PopulateCalendar(calendar, hdrMap, objData)
+			if err != nil {
+				return nil, err
+			}
+			return calendar, nil
+		}
+		if callParams.CallType == cmn.CALLTYPEUPDATE {
+			err := rsrcs.PopulateCalendarForUpdate(calendar, hdrMap, objData)
+			if err != nil {
+				return nil, err
+			}
+			return calendar, nil
+		}
 	case cmn.OBJTYPECROSDEV:
 		if callParams.CallType == cmn.CALLTYPEMANAGE {
 			mngdev := cdevs.ManagedDevice{}
@@ -185,6 +229,15 @@ func FromFileFactory(callParams CallParams, hdrMap map[int]string, objData []int
 			}
 			return crosdev, nil
 		}
+	case cmn.OBJTYPEFEATURE:
+		if callParams.CallType == cmn.CALLTYPECREATE {
+			feature := new(admin.Feature)
+			err := rsrcs.PopulateFeature(feature, hdrMap, objData)
+			if err != nil {
+				return nil, err
+			}
+			return feature, nil
+		}
 	case cmn.OBJTYPEGROUP:
 		if callParams.CallType == cmn.CALLTYPECREATE {
 			group := new(admin.Group)
@@ -219,7 +272,7 @@ func FromFileFactory(callParams CallParams, hdrMap map[int]string, objData []int
 			return member, nil
 		}
 		if callParams.CallType == cmn.CALLTYPEUPDATE {
-			memParams := mems.MemberParams{}
+			memParams := mems.MemberParams{Member: new(admin.Member)}
 			err := mems.PopulateMemberForUpdate(&memParams, hdrMap, objData)
 			if err != nil {
 				return nil, err
@@ -252,6 +305,33 @@ func FromFileFactory(callParams CallParams, hdrMap map[int]string, objData []int
 			}
 			return ouParams, nil
 		}
+	case cmn.OBJTYPEROLE:
+		if callParams.CallType == cmn.CALLTYPECREATE {
+			role := new(admin.Role)
+			err := rls.PopulateRole(role, hdrMap, objData)
+			if err != nil {
+				return nil, err
+			}
+			return role, nil
+		}
+	case cmn.OBJTYPEROLEASSIGN:
+		if callParams.CallType == cmn.CALLTYPECREATE {
+			raParams := rls.RoleAssignmentParams{}
+			err := rls.PopulateRoleAssignment(&raParams, hdrMap, objData)
+			if err != nil {
+				return nil, err
+			}
+			return raParams, nil
+		}
+	case cmn.OBJTYPESTARTER:
+		if callParams.CallType == cmn.CALLTYPECREATE {
+			starter := new(onb.Starter)
+			err := onb.PopulateStarter(starter, hdrMap, objData)
+			if err != nil {
+				return nil, err
+			}
+			return starter, nil
+		}
 	case cmn.OBJTYPEUSER:
 		if callParams.CallType == cmn.CALLTYPECREATE {
 			user := new(admin.User)
@@ -314,6 +394,58 @@ func FromJSONFactory(callParam CallParams, jsonData string, attrMap map[string]s
 	}
 
 	switch callParam.ObjectType {
+	case cmn.OBJTYPEBUILDING:
+		building := new(admin.Building)
+		err = json.Unmarshal(jsonBytes, &building)
+		if err != nil {
+			lg.Error(err)
+			return nil, err
+		}
+		if building.BuildingId == "" {
+			err = fmt.Errorf(gmess.ERR_EMPTYSTRING, rsrcs.BUILDINGKEYNAME)
+			lg.Error(err)
+			return nil, err
+		}
+		if callParam.CallType == cmn.CALLTYPECREATE {
+			return building, nil
+		}
+		if callParam.CallType == cmn.CALLTYPEUPDATE {
+			err = json.Unmarshal(jsonBytes, &emptyVals)
+			if err != nil {
+				lg.Error(err)
+				return nil, err
+			}
+			if len(emptyVals.ForceSendFields) > 0 {
+				building.ForceSendFields = emptyVals.ForceSendFields
+			}
+			return building, nil
+		}
+	case cmn.OBJTYPECALRES:
+		calendar := new(admin.CalendarResource)
+		err = json.Unmarshal(jsonBytes, &calendar)
+		if err != nil {
+			lg.Error(err)
+			return nil, err
+		}
+		if calendar.ResourceId == "" {
+			err = fmt.Errorf(gmess.ERR_EMPTYSTRING, rsrcs.CALENDARKEYNAME)
+			lg.Error(err)
+			return nil, err
+		}
+		if callParam.CallType == cmn.CALLTYPECREATE {
+			return calendar, nil
+		}
+		if callParam.CallType == cmn.CALLTYPEUPDATE {
+			err = json.Unmarshal(jsonBytes, &emptyVals)
+			if err != nil {
+				lg.Error(err)
+				return nil, err
+			}
+			if len(emptyVals.ForceSendFields) > 0 {
+				calendar.ForceSendFields = emptyVals.ForceSendFields
+			}
+			return calendar, nil
+		}
 	case cmn.OBJTYPECROSDEV:
 		if callParam.CallType == cmn.CALLTYPEMANAGE {
 			mngDev := cdevs.ManagedDevice{}
@@ -357,6 +489,16 @@ func FromJSONFactory(callParam CallParams, jsonData string, attrMap map[string]s
 			}
 			return crosdev, nil
 		}
+	case cmn.OBJTYPEFEATURE:
+		if callParam.CallType == cmn.CALLTYPECREATE {
+			feature := new(admin.Feature)
+			err = json.Unmarshal(jsonBytes, &feature)
+			if err != nil {
+				lg.Error(err)
+				return nil, err
+			}
+			return feature, nil
+		}
 	case cmn.OBJTYPEGROUP:
 		if callParam.CallType == cmn.CALLTYPECREATE {
 			group := new(admin.Group)
@@ -541,6 +683,36 @@ func FromJSONFactory(callParam CallParams, jsonData string, attrMap map[string]s
 			}
 			return ouParams, nil
 		}
+	case cmn.OBJTYPEROLE:
+		if callParam.CallType == cmn.CALLTYPECREATE {
+			role := new(admin.Role)
+			err = json.Unmarshal(jsonBytes, &role)
+			if err != nil {
+				lg.Error(err)
+				return nil, err
+			}
+			return role, nil
+		}
+	case cmn.OBJTYPEROLEASSIGN:
+		if callParam.CallType == cmn.CALLTYPECREATE {
+			raParams := rls.RoleAssignmentParams{}
+			err = json.Unmarshal(jsonBytes, &raParams)
+			if err != nil {
+				lg.Error(err)
+				return nil, err
+			}
+			return raParams, nil
+		}
+	case cmn.OBJTYPESTARTER:
+		starter := new(onb.Starter)
+		err = json.Unmarshal(jsonBytes, starter)
+		if err != nil {
+			lg.Error(err)
+			return nil, err
+		}
+		if callParam.CallType == cmn.CALLTYPECREATE {
+			return starter, nil
+		}
 	case cmn.OBJTYPEUSER:
 		if callParam.CallType == cmn.CALLTYPECREATE {
 			user := new(admin.User)
@@ -615,13 +787,14 @@ func FromJSONFactory(callParam CallParams, jsonData string, attrMap map[string]s
 }
 
 // ProcessCSVFile does batch processing of CSV input files
-func ProcessCSVFile(callParams CallParams, filePath string, attrMap map[string]string) ([]interface{}, error) {
+func ProcessCSVFile(callParams CallParams, filePath string, attrMap map[string]string) ([]interface{}, *Input, error) {
 	lg.Debugw("starting ProcessCSVFile()",
 		"filePath", filePath,
 		"attrMap", attrMap)
 	defer lg.Debug("finished ProcessCSVFile()")
 
 	var (
+		input      = &Input{Format: "csv"}
 		iSlice     []interface{}
 		hdrMap     = map[int]string{}
 		outputObjs []interface{}
@@ -630,7 +803,7 @@ func ProcessCSVFile(callParams CallParams, filePath string, attrMap map[string]s
 	csvfile, err := os.Open(filePath)
 	if err != nil {
 		lg.Error(err)
-		return nil, err
+		return nil, nil, err
 	}
 	defer csvfile.Close()
 
@@ -644,7 +817,7 @@ func ProcessCSVFile(callParams CallParams, filePath string, attrMap map[string]s
 		}
 		if err != nil {
 			lg.Error(err)
-			return nil, err
+			return nil, nil, err
 		}
 
 		if count == 0 {
@@ -652,10 +825,11 @@ func ProcessCSVFile(callParams CallParams, filePath string, attrMap map[string]s
 			for idx, value := range record {
 				iSlice[idx] = value
 			}
+			input.setHeader(iSlice)
 			hdrMap = cmn.ProcessHeader(iSlice)
 			err = validateHeader(hdrMap, attrMap)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			count = count + 1
 			continue
@@ -667,37 +841,41 @@ func ProcessCSVFile(callParams CallParams, filePath string, attrMap map[string]s
 
 		objVar, err := FromFileFactory(callParams, hdrMap, iSlice)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		outputObjs = append(outputObjs, objVar)
+		input.addRow(iSlice)
 
 		count = count + 1
 	}
 
-	return outputObjs, nil
+	return outputObjs, input, nil
 }
 
 // ProcessGSheet does batch processing of Google Sheet input
-func ProcessGSheet(callParams CallParams, sheetID string, sheetrange string, attrMap map[string]string) ([]interface{}, error) {
+func ProcessGSheet(callParams CallParams, sheetID string, sheetrange string, attrMap map[string]string) ([]interface{}, *Input, error) {
 	lg.Debugw("starting ProcessGSheet()",
 		"sheetID", sheetID,
 		"sheetrange", sheetrange,
 		"attrMap", attrMap)
 	defer lg.Debug("finished ProcessGSheet()")
 
-	var outputObjs []interface{}
+	var (
+		input      = &Input{Format: "gsheet"}
+		outputObjs []interface{}
+	)
 
 	if sheetrange == "" {
 		err := errors.New(gmess.ERR_NOSHEETRANGE)
 		lg.Error(err)
-		return nil, err
+		return nil, nil, err
 	}
 
 	srv, err := cmn.CreateService(cmn.SRVTYPESHEET, sheet.DriveReadonlyScope)
 	if err != nil {
 		lg.Error(err)
-		return nil, err
+		return nil, nil, err
 	}
 	ss := srv.(*sheet.Service)
 
@@ -705,19 +883,20 @@ func ProcessGSheet(callParams CallParams, sheetID string, sheetrange string, att
 	sValRange, err := ssvgc.Do()
 	if err != nil {
 		lg.Error(err)
-		return nil, err
+		return nil, nil, err
 	}
 
 	if len(sValRange.Values) == 0 {
 		err = fmt.Errorf(gmess.ERR_NOSHEETDATAFOUND, sheetID, sheetrange)
 		lg.Error(err)
-		return nil, err
+		return nil, nil, err
 	}
 
+	input.setHeader(sValRange.Values[0])
 	hdrMap := cmn.ProcessHeader(sValRange.Values[0])
 	err = cmn.ValidateHeader(hdrMap, attrMap)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	for idx, row := range sValRange.Values {
@@ -727,29 +906,33 @@ func ProcessGSheet(callParams CallParams, sheetID string, sheetrange string, att
 
 		objVar, err := FromFileFactory(callParams, hdrMap, row)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		outputObjs = append(outputObjs, objVar)
+		input.addRow(row)
 	}
 
-	return outputObjs, nil
+	return outputObjs, input, nil
 }
 
 // ProcessJSON does batch processing of JSON file input
-func ProcessJSON(callParam CallParams, filePath string, scanner *bufio.Scanner, attrMap map[string]string) ([]interface{}, error) {
+func ProcessJSON(callParam CallParams, filePath string, scanner *bufio.Scanner, attrMap map[string]string) ([]interface{}, *Input, error) {
 	lg.Debugw("starting ProcessJSON()",
 		"filePath", filePath,
 		"attrMap", attrMap)
 	defer lg.Debug("finished ProcessJSON()")
 
-	var outputObjs []interface{}
+	var (
+		input      = &Input{Format: "json"}
+		outputObjs []interface{}
+	)
 
 	if filePath != "" {
 		file, err := os.Open(filePath)
 		if err != nil {
 			lg.Error(err)
-			return nil, err
+			return nil, nil, err
 		}
 		defer file.Close()
 
@@ -761,18 +944,19 @@ func ProcessJSON(callParam CallParams, filePath string, scanner *bufio.Scanner,
 
 		objVar, err := FromJSONFactory(callParam, jsonData, attrMap)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		outputObjs = append(outputObjs, objVar)
+		input.Rows = append(input.Rows, []string{jsonData})
 	}
 	err := scanner.Err()
 	if err != nil {
 		lg.Error(err)
-		return nil, err
+		return nil, nil, err
 	}
 
-	return outputObjs, nil
+	return outputObjs, input, nil
 }
 
 // validateHeader validates header column names
@@ -0,0 +1,138 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package batch
+
+import (
+	"sync"
+	"time"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	lg "github.com/plusworx/gmin/utils/logging"
+)
+
+const (
+	// DEFAULTWORKERS is default number of concurrent batch workers
+	DEFAULTWORKERS int = 10
+	// DIRECTORYQPS is default Directory API query rate (2,400 queries per minute quota)
+	DIRECTORYQPS float64 = 40
+	// GRPSETTINGSQPS is default Groups Settings API query rate
+	GRPSETTINGSQPS float64 = 10
+	// ORGUNITQPS is default orgunit create/update/delete rate (only 1 orgunit can be changed per
+	// second but 1 second interval can still result in rate limit errors)
+	ORGUNITQPS float64 = 0.5
+)
+
+// Limiter is a token bucket rate limiter
+type Limiter struct {
+	burst  float64
+	last   time.Time
+	mu     sync.Mutex
+	qps    float64
+	tokens float64
+}
+
+// NewLimiter returns a Limiter that allows qps calls per second with bursts of up to one second's
+// worth of calls. A qps of zero or less means no limit.
+func NewLimiter(qps float64) *Limiter {
+	lg.Debugw("starting NewLimiter()",
+		"qps", qps)
+	defer lg.Debug("finished NewLimiter()")
+
+	burst := qps
+	if burst < 1 {
+		burst = 1
+	}
+	return &Limiter{burst: burst, last: time.Now(), qps: qps, tokens: burst}
+}
+
+// Wait blocks until the rate limit allows another call
+func (l *Limiter) Wait() {
+	if l == nil || l.qps <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.qps
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	// Take a token and sleep off any deficit, which reserves a slot for this caller
+	l.tokens--
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.qps * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	time.Sleep(delay)
+}
+
+// Pool runs batch jobs with a bounded number of workers
+type Pool struct {
+	jobs    chan func()
+	limiter *Limiter
+	wg      sync.WaitGroup
+}
+
+// NewPool starts a pool of workers. Until the pool is waited for, API calls, including those retried
+// by jobs, are made at no more than qps calls per second.
+func NewPool(workers int, qps float64) *Pool {
+	lg.Debugw("starting NewPool()",
+		"workers", workers,
+		"qps", qps)
+	defer lg.Debug("finished NewPool()")
+
+	if workers < 1 {
+		workers = 1
+	}
+
+	p := &Pool{jobs: make(chan func()), limiter: NewLimiter(qps)}
+	cmn.SetCallLimiter(p.limiter)
+
+	for i := 0; i < workers; i++ {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			for job := range p.jobs {
+				job()
+			}
+		}()
+	}
+	return p
+}
+
+// Submit queues a job, blocking until a worker is free. Jobs are started in the order that they are
+// submitted.
+func (p *Pool) Submit(job func()) {
+	p.jobs <- job
+}
+
+// Wait waits for all submitted jobs to finish and removes the pool's API call rate limit. No more jobs
+// may be submitted afterwards.
+func (p *Pool) Wait() {
+	close(p.jobs)
+	p.wg.Wait()
+	cmn.SetCallLimiter(nil)
+}
@@ -0,0 +1,146 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package batch
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	tsts "github.com/plusworx/gmin/tests"
+	cmn "github.com/plusworx/gmin/utils/common"
+	cfg "github.com/plusworx/gmin/utils/config"
+	lg "github.com/plusworx/gmin/utils/logging"
+	"github.com/spf13/viper"
+)
+
+func TestPool(t *testing.T) {
+	var (
+		calls   int
+		callsMu sync.Mutex
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		callsMu.Lock()
+		calls++
+		callsMu.Unlock()
+	}))
+	defer srv.Close()
+
+	// Jobs that make several calls, such as a list followed by deletes, are limited on every call
+	cases := []struct {
+		callsPerJob int
+		jobs        int
+		minElapsed  time.Duration
+		qps         float64
+		workers     int
+	}{
+		{
+			jobs:    100,
+			qps:     0,
+			workers: 3,
+		},
+		{
+			callsPerJob: 1,
+			jobs:        60,
+			minElapsed:  150 * time.Millisecond,
+			qps:         50,
+			workers:     5,
+		},
+		{
+			callsPerJob: 3,
+			jobs:        10,
+			minElapsed:  400 * time.Millisecond,
+			qps:         20,
+			workers:     10,
+		},
+	}
+
+	tsts.InitConfig()
+	lg.InitLogging("info")
+
+	viper.Set(cfg.CONFIGENDPOINT, srv.URL)
+	defer viper.Set(cfg.CONFIGENDPOINT, "")
+
+	client, err := cmn.HTTPClient(cmn.SRVTYPEADMIN)
+	if err != nil {
+		t.Fatalf("Got error: %v - expected error: nil", err)
+	}
+
+	for _, c := range cases {
+		var (
+			active    int
+			completed int
+			maxActive int
+			mu        sync.Mutex
+		)
+		calls = 0
+
+		start := time.Now()
+		pool := NewPool(c.workers, c.qps)
+
+		for i := 0; i < c.jobs; i++ {
+			pool.Submit(func() {
+				mu.Lock()
+				active++
+				if active > maxActive {
+					maxActive = active
+				}
+				mu.Unlock()
+
+				time.Sleep(time.Millisecond)
+
+				for call := 0; call < c.callsPerJob; call++ {
+					resp, err := client.Get(srv.URL)
+					if err != nil {
+						t.Errorf("Got error: %v - expected error: nil", err)
+						continue
+					}
+					resp.Body.Close()
+				}
+
+				mu.Lock()
+				active--
+				completed++
+				mu.Unlock()
+			})
+		}
+		pool.Wait()
+		elapsed := time.Since(start)
+
+		if completed != c.jobs {
+			t.Errorf("Got completed jobs: %v - expected completed jobs: %v", completed, c.jobs)
+		}
+		if maxActive > c.workers {
+			t.Errorf("Got max concurrent jobs: %v - expected max concurrent jobs: %v", maxActive, c.workers)
+		}
+		if calls != c.jobs*c.callsPerJob {
+			t.Errorf("Got calls: %v - expected calls: %v", calls, c.jobs*c.callsPerJob)
+		}
+		if elapsed < c.minElapsed {
+			t.Errorf("Got elapsed time: %v - expected elapsed time at least: %v", elapsed, c.minElapsed)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package batch
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	lg "github.com/plusworx/gmin/utils/logging"
+	"google.golang.org/api/googleapi"
+)
+
+const (
+	// FILETIMEFORMAT is used to timestamp batch results file names
+	FILETIMEFORMAT string = "20060102150405"
+	// STATUSFAILED is batch result status of a failed row
+	STATUSFAILED string = "failed"
+	// STATUSSUCCESS is batch result status of a successful row
+	STATUSSUCCESS string = "success"
+)
+
+// Input holds batch input rows in their original form so that failed rows can be written out again
+type Input struct {
+	Format string
+	Header []string
+	Rows   [][]string
+}
+
+// Result holds the outcome of processing a batch input row
+type Result struct {
+	Row          int    `json:"row"`
+	Key          string `json:"key"`
+	Status       string `json:"status"`
+	ErrorCode    int    `json:"errorCode,omitempty"`
+	ErrorMessage string `json:"errorMessage,omitempty"`
+
+	idx int
+}
+
+// Report collects the results of a batch run
+type Report struct {
+	dir     string
+	format  string
+	input   *Input
+	mu      sync.Mutex
+	results []Result
+	started time.Time
+}
+
+// NewReport returns a Report for batch input that writes results files of the given format (csv or json)
+// to dir
+func NewReport(input *Input, dir string, format string) *Report {
+	if input == nil {
+		input = &Input{}
+	}
+	return &Report{dir: dir, format: format, input: input, started: time.Now()}
+}
+
+// Add records the result of processing the input row at index idx. A nil err means success.
+func (r *Report) Add(idx int, key string, err error) {
+	lg.Debugw("starting Add()",
+		"idx", idx,
+		"key", key)
+	defer lg.Debug("finished Add()")
+
+	res := Result{Row: r.input.RowNum(idx), Key: key, Status: STATUSSUCCESS, idx: idx}
+
+	if err != nil {
+		res.Status = STATUSFAILED
+		res.ErrorMessage = err.Error()
+
+		var gErr *googleapi.Error
+		if errors.As(err, &gErr) {
+			res.ErrorCode = gErr.Code
+			res.ErrorMessage = gErr.Message
+		}
+	}
+
+	r.mu.Lock()
+	r.results = append(r.results, res)
+	r.mu.Unlock()
+}
+
+// Failed returns the number of failed rows
+func (r *Report) Failed() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	failed := 0
+	for _, res := range r.results {
+		if res.Status == STATUSFAILED {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Results returns results in input row order
+func (r *Report) Results() []Result {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	results := make([]Result, len(r.results))
+	copy(results, r.results)
+	sort.Slice(results, func(i, j int) bool { return results[i].idx < results[j].idx })
+	return results
+}
+
+// createFile creates a new file in the report directory named from prefix, the start time and ext. A number
+// is added to the name if a file with it already exists so that runs started in the same second never
+// write to the same file.
+func (r *Report) createFile(prefix string, ext string) (*os.File, string, error) {
+	stem := prefix + r.started.Format(FILETIMEFORMAT)
+
+	for num := 1; ; num++ {
+		name := stem
+		if num > 1 {
+			name = stem + "_" + strconv.Itoa(num)
+		}
+		path := filepath.Join(r.dir, name+"."+ext)
+
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0666)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			lg.Error(err)
+			return nil, "", err
+		}
+		return file, path, nil
+	}
+}
+
+// WriteFailedRows writes failed input rows to a file that can be used as input to rerun them.
+// Google Sheet rows are written as CSV. The file path is returned.
+func (r *Report) WriteFailedRows() (string, error) {
+	lg.Debug("starting WriteFailedRows()")
+	defer lg.Debug("finished WriteFailedRows()")
+
+	ext := r.input.Format
+	switch ext {
+	case "gsheet":
+		ext = "csv"
+	case "text":
+		ext = "txt"
+	}
+	file, path, err := r.createFile("gmin_failed_", ext)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	var rows [][]string
+	for _, res := range r.Results() {
+		if res.Status == STATUSFAILED && res.idx < len(r.input.Rows) {
+			rows = append(rows, r.input.Rows[res.idx])
+		}
+	}
+
+	if ext == "csv" {
+		w := csv.NewWriter(file)
+		if r.input.Header != nil {
+			rows = append([][]string{r.input.Header}, rows...)
+		}
+		err = w.WriteAll(rows)
+	} else {
+		for _, row := range rows {
+			if len(row) == 0 {
+				continue
+			}
+			_, err = fmt.Fprintln(file, row[0])
+			if err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		lg.Error(err)
+		return "", err
+	}
+	return path, nil
+}
+
+// WriteResults writes results to a file and returns the file path
+func (r *Report) WriteResults() (string, error) {
+	lg.Debug("starting WriteResults()")
+	defer lg.Debug("finished WriteResults()")
+
+	file, path, err := r.createFile("gmin_results_", r.format)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	results := r.Results()
+
+	if r.format == "csv" {
+		w := csv.NewWriter(file)
+		rows := [][]string{{"row", "key", "status", "errorCode", "errorMessage"}}
+		for _, res := range results {
+			code := ""
+			if res.ErrorCode != 0 {
+				code = strconv.Itoa(res.ErrorCode)
+			}
+			rows = append(rows, []string{strconv.Itoa(res.Row), res.Key, res.Status, code, res.ErrorMessage})
+		}
+		err = w.WriteAll(rows)
+	} else {
+		enc := json.NewEncoder(file)
+		enc.SetIndent("", "    ")
+		err = enc.Encode(results)
+	}
+	if err != nil {
+		lg.Error(err)
+		return "", err
+	}
+	return path, nil
+}
+
+// RowNum returns the input row number of the object at index idx, counting any header as row 1
+func (in *Input) RowNum(idx int) int {
+	if in.Header != nil {
+		return idx + 2
+	}
+	return idx + 1
+}
+
+func (in *Input) addRow(row []interface{}) {
+	strRow := make([]string, len(row))
+	for idx, val := range row {
+		strRow[idx] = fmt.Sprintf("%v", val)
+	}
+	in.Rows = append(in.Rows, strRow)
+}
+
+func (in *Input) setHeader(hdr []interface{}) {
+	in.Header = make([]string, len(hdr))
+	for idx, val := range hdr {
+		in.Header[idx] = fmt.Sprintf("%v", val)
+	}
+}
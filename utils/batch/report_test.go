@@ -0,0 +1,157 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package batch
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tsts "github.com/plusworx/gmin/tests"
+	lg "github.com/plusworx/gmin/utils/logging"
+	"google.golang.org/api/googleapi"
+)
+
+func TestReport(t *testing.T) {
+	cases := []struct {
+		expFailed  string
+		expResults string
+		format     string
+		input      *Input
+	}{
+		{
+			expFailed:  "email,role\nb@disney.com,OWNER\n",
+			expResults: "row,key,status,errorCode,errorMessage\n2,a@disney.com,success,,\n3,b@disney.com,failed,404,Resource Not Found: b@disney.com\n4,c@disney.com,success,,\n",
+			format:     "csv",
+			input: &Input{
+				Format: "csv",
+				Header: []string{"email", "role"},
+				Rows:   [][]string{{"a@disney.com", "MEMBER"}, {"b@disney.com", "OWNER"}, {"c@disney.com", "MEMBER"}},
+			},
+		},
+		{
+			expFailed: "b@disney.com\n",
+			expResults: `[
+    {
+        "row": 1,
+        "key": "a@disney.com",
+        "status": "success"
+    },
+    {
+        "row": 2,
+        "key": "b@disney.com",
+        "status": "failed",
+        "errorCode": 404,
+        "errorMessage": "Resource Not Found: b@disney.com"
+    },
+    {
+        "row": 3,
+        "key": "c@disney.com",
+        "status": "success"
+    }
+]
+`,
+			format: "json",
+			input: &Input{
+				Format: "text",
+				Rows:   [][]string{{"a@disney.com"}, {"b@disney.com"}, {"c@disney.com"}},
+			},
+		},
+	}
+
+	tsts.InitConfig()
+	lg.InitLogging("info")
+
+	for _, c := range cases {
+		report := NewReport(c.input, t.TempDir(), c.format)
+
+		notFound := &googleapi.Error{Code: 404, Message: "Resource Not Found: b@disney.com"}
+		report.Add(2, "c@disney.com", nil)
+		report.Add(1, "b@disney.com", fmt.Errorf("%w - b@disney.com", notFound))
+		report.Add(0, "a@disney.com", nil)
+
+		if report.Failed() != 1 {
+			t.Errorf("Got failed: %v - expected failed: 1", report.Failed())
+		}
+
+		resultsPath, err := report.WriteResults()
+		if err != nil {
+			t.Fatalf("Got error: %v - expected error: nil", err)
+		}
+		got, _ := ioutil.ReadFile(resultsPath)
+		if string(got) != c.expResults {
+			t.Errorf("Got results: %v - expected results: %v", string(got), c.expResults)
+		}
+
+		failedPath, err := report.WriteFailedRows()
+		if err != nil {
+			t.Fatalf("Got error: %v - expected error: nil", err)
+		}
+		got, _ = ioutil.ReadFile(failedPath)
+		if string(got) != c.expFailed {
+			t.Errorf("Got failed rows: %v - expected failed rows: %v", string(got), c.expFailed)
+		}
+	}
+}
+
+func TestReportFileNames(t *testing.T) {
+	tsts.InitConfig()
+	lg.InitLogging("info")
+
+	dir := t.TempDir()
+	started := time.Now()
+	paths := map[string]bool{}
+
+	for i := 0; i < 3; i++ {
+		report := NewReport(&Input{Format: "text", Rows: [][]string{{"a@disney.com"}}}, dir, "json")
+		// Runs started in the same second
+		report.started = started
+		report.Add(0, "a@disney.com", errors.New("failed"))
+
+		resultsPath, err := report.WriteResults()
+		if err != nil {
+			t.Fatalf("Got error: %v - expected error: nil", err)
+		}
+		failedPath, err := report.WriteFailedRows()
+		if err != nil {
+			t.Fatalf("Got error: %v - expected error: nil", err)
+		}
+
+		for _, path := range []string{resultsPath, failedPath} {
+			if paths[path] {
+				t.Errorf("Got path: %v - expected path: not used by an earlier run", path)
+			}
+			paths[path] = true
+		}
+	}
+
+	stamp := started.Format(FILETIMEFORMAT)
+	for _, name := range []string{"gmin_results_" + stamp + "_3.json", "gmin_failed_" + stamp + "_3.txt"} {
+		if !paths[filepath.Join(dir, name)] {
+			t.Errorf("Got paths: %v - expected paths to include: %v", paths, name)
+		}
+	}
+}
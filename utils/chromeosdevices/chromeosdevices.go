@@ -38,8 +38,14 @@ import (
 const (
 	// ENDFIELD is List call attribute string terminator
 	ENDFIELD string = ")"
+	// LISTKEY is name of List call results attribute
+	LISTKEY string = "chromeosdevices"
 	// STARTCHROMEDEVICESFIELD is List call attribute string prefix
 	STARTCHROMEDEVICESFIELD string = "chromeosdevices("
+	// STATUSDEPROVISIONED is the status of deprovisioned devices
+	STATUSDEPROVISIONED string = "DEPROVISIONED"
+	// STATUSDISABLED is the status of disabled devices
+	STATUSDISABLED string = "DISABLED"
 )
 
 // ManagedDevice is struct to extract device data
@@ -0,0 +1,317 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package chromeosdevices
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+const (
+	// FLEETACTIONDEPROVISION writes a batch-manage chromeos-devices input file that deprovisions devices
+	FLEETACTIONDEPROVISION string = "deprovision"
+	// FLEETACTIONDISABLE writes a batch-manage chromeos-devices input file that disables devices
+	FLEETACTIONDISABLE string = "disable"
+	// FLEETACTIONMOVE writes a batch-move chromeos-devices input file that moves devices to an orgunit
+	FLEETACTIONMOVE string = "move"
+	// FLEETAUE counts devices by how long it is until their auto update expiration
+	FLEETAUE string = "aue"
+	// FLEETCHECK counts devices that fail each fleet check
+	FLEETCHECK string = "check"
+	// FLEETCHECKAUE is the check for devices whose auto update expiration is close or has passed
+	FLEETCHECKAUE string = "aue"
+	// FLEETCHECKNOSYNC is the check for devices that have not synced recently
+	FLEETCHECKNOSYNC string = "no-sync"
+	// FLEETFIELDS are the device attributes that fleet reports need
+	FLEETFIELDS string = "autoUpdateExpiration,deviceId,lastSync,model,orgUnitPath,osVersion,recentUsers,serialNumber,status"
+	// FLEETLASTSYNC counts devices by how long ago they last synced
+	FLEETLASTSYNC string = "last-sync"
+	// FLEETLISTKEY is the name of the fleet report device counts attribute
+	FLEETLISTKEY string = "counts"
+	// FLEETMODEL counts devices by model
+	FLEETMODEL string = "model"
+	// FLEETORGUNIT counts devices by orgunit
+	FLEETORGUNIT string = "orgunit"
+	// FLEETOSVERSION counts devices by ChromeOS version
+	FLEETOSVERSION string = "os-version"
+	// FLEETRECENTUSER counts devices by the user who used them most recently
+	FLEETRECENTUSER string = "recent-user"
+	// FLEETSTATUS counts devices by status
+	FLEETSTATUS string = "status"
+	// FLEETUNKNOWN is the value counted when a device doesn't have an attribute
+	FLEETUNKNOWN string = "unknown"
+)
+
+// aueBands are the auto update expiration bands in the order that they are reported
+var aueBands = []string{
+	"expired",
+	"within 6 months",
+	"within 12 months",
+	"within 24 months",
+	"over 24 months",
+	FLEETUNKNOWN,
+}
+
+// fleetDimensions are the device counts in the order that they are reported
+var fleetDimensions = []string{
+	FLEETCHECK,
+	FLEETAUE,
+	FLEETLASTSYNC,
+	FLEETMODEL,
+	FLEETORGUNIT,
+	FLEETOSVERSION,
+	FLEETRECENTUSER,
+	FLEETSTATUS,
+}
+
+// syncBands are the last sync bands in the order that they are reported
+var syncBands = []string{
+	"within 7 days",
+	"within 30 days",
+	"within 90 days",
+	"over 90 days",
+	"never",
+}
+
+// ValidFleetActions are the actions that fleet report batch input files can be written for
+var ValidFleetActions = []string{
+	FLEETACTIONDEPROVISION,
+	FLEETACTIONDISABLE,
+	FLEETACTIONMOVE,
+}
+
+// ValidFleetChecks are the checks that can be made on devices for the fleet report
+var ValidFleetChecks = []string{
+	FLEETCHECKAUE,
+	FLEETCHECKNOSYNC,
+}
+
+// Fleet collects device counts and the devices that fail fleet checks
+type Fleet struct {
+	counts  map[string]map[string]int
+	Matched []*admin.ChromeOsDevice
+	opts    FleetOptions
+}
+
+// FleetCount is the number of devices with a value in a fleet report dimension
+type FleetCount struct {
+	Devices   int    `json:"devices"`
+	Dimension string `json:"dimension"`
+	Value     string `json:"value"`
+}
+
+// FleetOptions holds the checks and thresholds used to find devices that need attention
+type FleetOptions struct {
+	AUEMonths int
+	Checks    []string
+	Now       time.Time
+	SyncDays  int
+}
+
+// Add counts dev and keeps it if it fails any of the fleet checks
+func (f *Fleet) Add(dev *admin.ChromeOsDevice) {
+	f.count(FLEETAUE, aueBand(dev, f.opts.Now))
+	f.count(FLEETLASTSYNC, syncBand(dev, f.opts.Now))
+	f.count(FLEETMODEL, dev.Model)
+	f.count(FLEETORGUNIT, dev.OrgUnitPath)
+	f.count(FLEETOSVERSION, dev.OsVersion)
+	f.count(FLEETRECENTUSER, recentUser(dev))
+	f.count(FLEETSTATUS, dev.Status)
+
+	checks := f.Checks(dev)
+	for _, check := range checks {
+		f.count(FLEETCHECK, check)
+	}
+	if len(checks) > 0 {
+		f.Matched = append(f.Matched, dev)
+	}
+}
+
+// Checks returns the fleet checks that dev fails
+func (f *Fleet) Checks(dev *admin.ChromeOsDevice) []string {
+	checks := []string{}
+
+	for _, check := range ValidFleetChecks {
+		if !cmn.SliceContainsStr(f.opts.Checks, check) {
+			continue
+		}
+
+		var failed bool
+
+		switch check {
+		case FLEETCHECKAUE:
+			failed = dev.AutoUpdateExpiration != 0 &&
+				aueTime(dev).Before(f.opts.Now.AddDate(0, f.opts.AUEMonths, 0))
+		case FLEETCHECKNOSYNC:
+			lastSync, err := time.Parse(time.RFC3339, dev.LastSync)
+			failed = err != nil || lastSync.Before(f.opts.Now.AddDate(0, 0, -f.opts.SyncDays))
+		}
+
+		if failed {
+			checks = append(checks, check)
+		}
+	}
+	return checks
+}
+
+// Counts returns the device counts for each dimension
+//
+// Auto update expiration and last sync bands are in band order and other values are in descending
+// order of device count.
+func (f *Fleet) Counts() []*FleetCount {
+	lg.Debug("starting Counts()")
+	defer lg.Debug("finished Counts()")
+
+	counts := []*FleetCount{}
+
+	for _, dim := range fleetDimensions {
+		dimCounts := []*FleetCount{}
+		for val, devices := range f.counts[dim] {
+			dimCounts = append(dimCounts, &FleetCount{Devices: devices, Dimension: dim, Value: val})
+		}
+
+		var bands []string
+		switch dim {
+		case FLEETAUE:
+			bands = aueBands
+		case FLEETLASTSYNC:
+			bands = syncBands
+		}
+
+		sort.Slice(dimCounts, func(i, j int) bool {
+			if bands != nil {
+				return bandIndex(bands, dimCounts[i].Value) < bandIndex(bands, dimCounts[j].Value)
+			}
+			if dimCounts[i].Devices != dimCounts[j].Devices {
+				return dimCounts[i].Devices > dimCounts[j].Devices
+			}
+			return dimCounts[i].Value < dimCounts[j].Value
+		})
+		counts = append(counts, dimCounts...)
+	}
+	return counts
+}
+
+func (f *Fleet) count(dim string, val string) {
+	if val == "" {
+		val = FLEETUNKNOWN
+	}
+	if f.counts[dim] == nil {
+		f.counts[dim] = map[string]int{}
+	}
+	f.counts[dim][val]++
+}
+
+// NewFleet returns an empty Fleet that checks devices using opts
+func NewFleet(opts FleetOptions) *Fleet {
+	lg.Debugw("starting NewFleet()",
+		"opts", opts)
+	defer lg.Debug("finished NewFleet()")
+
+	return &Fleet{counts: map[string]map[string]int{}, Matched: []*admin.ChromeOsDevice{}, opts: opts}
+}
+
+// ValidateFleetChecks checks that the checks given for the fleet report are valid
+func ValidateFleetChecks(checks []string) error {
+	lg.Debugw("starting ValidateFleetChecks()",
+		"checks", checks)
+	defer lg.Debug("finished ValidateFleetChecks()")
+
+	for _, check := range checks {
+		if !cmn.SliceContainsStr(ValidFleetChecks, check) {
+			err := fmt.Errorf(gmess.ERR_INVALIDFLEETCHECK, check, strings.Join(ValidFleetChecks, ", "))
+			lg.Error(err)
+			return err
+		}
+	}
+	return nil
+}
+
+func aueBand(dev *admin.ChromeOsDevice, now time.Time) string {
+	if dev.AutoUpdateExpiration == 0 {
+		return FLEETUNKNOWN
+	}
+
+	aue := aueTime(dev)
+	switch {
+	case aue.Before(now):
+		return aueBands[0]
+	case aue.Before(now.AddDate(0, 6, 0)):
+		return aueBands[1]
+	case aue.Before(now.AddDate(0, 12, 0)):
+		return aueBands[2]
+	case aue.Before(now.AddDate(0, 24, 0)):
+		return aueBands[3]
+	}
+	return aueBands[4]
+}
+
+// aueTime converts the auto update expiration of dev, which is in milliseconds since the Unix epoch
+func aueTime(dev *admin.ChromeOsDevice) time.Time {
+	return time.Unix(0, dev.AutoUpdateExpiration*int64(time.Millisecond))
+}
+
+func bandIndex(bands []string, band string) int {
+	for idx, b := range bands {
+		if b == band {
+			return idx
+		}
+	}
+	return len(bands)
+}
+
+// recentUser returns the email address of the user who used dev most recently or unmanaged if it
+// was used by a user from outside the domain
+func recentUser(dev *admin.ChromeOsDevice) string {
+	if len(dev.RecentUsers) == 0 {
+		return "none"
+	}
+	if dev.RecentUsers[0].Email == "" {
+		return "unmanaged"
+	}
+	return dev.RecentUsers[0].Email
+}
+
+func syncBand(dev *admin.ChromeOsDevice, now time.Time) string {
+	lastSync, err := time.Parse(time.RFC3339, dev.LastSync)
+	if err != nil {
+		return syncBands[4]
+	}
+
+	switch {
+	case lastSync.After(now.AddDate(0, 0, -7)):
+		return syncBands[0]
+	case lastSync.After(now.AddDate(0, 0, -30)):
+		return syncBands[1]
+	case lastSync.After(now.AddDate(0, 0, -90)):
+		return syncBands[2]
+	}
+	return syncBands[3]
+}
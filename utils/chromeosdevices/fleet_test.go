@@ -0,0 +1,217 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package chromeosdevices
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	tsts "github.com/plusworx/gmin/tests"
+	lg "github.com/plusworx/gmin/utils/logging"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+func fleetTestDevices() []*admin.ChromeOsDevice {
+	aue := func(year int, month time.Month, day int) int64 {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC).UnixNano() / int64(time.Millisecond)
+	}
+
+	return []*admin.ChromeOsDevice{
+		{
+			AutoUpdateExpiration: aue(2020, 12, 1),
+			DeviceId:             "dev1",
+			LastSync:             "2020-10-15T00:00:00.000Z",
+			Model:                "Chromebook A",
+			OrgUnitPath:          "/",
+			OsVersion:            "86.0",
+			RecentUsers:          []*admin.ChromeOsDeviceRecentUsers{{Email: "mickey@disney.com", Type: "USER_TYPE_MANAGED"}},
+			Status:               "ACTIVE",
+		},
+		{
+			AutoUpdateExpiration: aue(2019, 6, 1),
+			DeviceId:             "dev2",
+			LastSync:             "2020-08-01T00:00:00.000Z",
+			Model:                "Chromebook A",
+			OrgUnitPath:          "/Sales",
+			OsVersion:            "85.0",
+			Status:               "DISABLED",
+		},
+		{
+			DeviceId:    "dev3",
+			Model:       "Chromebook B",
+			OrgUnitPath: "/Sales",
+			OsVersion:   "86.0",
+			RecentUsers: []*admin.ChromeOsDeviceRecentUsers{{Type: "USER_TYPE_UNMANAGED"}},
+			Status:      "ACTIVE",
+		},
+		{
+			AutoUpdateExpiration: aue(2023, 1, 1),
+			DeviceId:             "dev4",
+			LastSync:             "2020-10-01T00:00:00.000Z",
+			Model:                "Chromebook B",
+			OrgUnitPath:          "/",
+			OsVersion:            "86.0",
+			RecentUsers:          []*admin.ChromeOsDeviceRecentUsers{{Email: "mickey@disney.com", Type: "USER_TYPE_MANAGED"}},
+			Status:               "ACTIVE",
+		},
+	}
+}
+
+func TestFleetChecks(t *testing.T) {
+	opts := FleetOptions{
+		AUEMonths: 6,
+		Checks:    ValidFleetChecks,
+		Now:       time.Date(2020, 10, 16, 0, 0, 0, 0, time.UTC),
+		SyncDays:  30,
+	}
+
+	cases := []struct {
+		checks         []string
+		expectedChecks [][]string
+	}{
+		{
+			expectedChecks: [][]string{
+				{FLEETCHECKAUE},
+				{FLEETCHECKAUE, FLEETCHECKNOSYNC},
+				{FLEETCHECKNOSYNC},
+				{},
+			},
+		},
+		{
+			checks: []string{FLEETCHECKNOSYNC},
+			expectedChecks: [][]string{
+				{},
+				{FLEETCHECKNOSYNC},
+				{FLEETCHECKNOSYNC},
+				{},
+			},
+		},
+	}
+
+	tsts.InitConfig()
+	lg.InitLogging("info")
+
+	for _, c := range cases {
+		o := opts
+		if c.checks != nil {
+			o.Checks = c.checks
+		}
+		fleet := NewFleet(o)
+
+		for idx, dev := range fleetTestDevices() {
+			checks := fleet.Checks(dev)
+			if !reflect.DeepEqual(checks, c.expectedChecks[idx]) {
+				t.Errorf("%v - got checks: %v - expected checks: %v", dev.DeviceId, checks, c.expectedChecks[idx])
+			}
+		}
+	}
+}
+
+func TestFleetCounts(t *testing.T) {
+	opts := FleetOptions{
+		AUEMonths: 6,
+		Checks:    ValidFleetChecks,
+		Now:       time.Date(2020, 10, 16, 0, 0, 0, 0, time.UTC),
+		SyncDays:  30,
+	}
+
+	expectedCounts := []FleetCount{
+		{Devices: 2, Dimension: FLEETCHECK, Value: FLEETCHECKAUE},
+		{Devices: 2, Dimension: FLEETCHECK, Value: FLEETCHECKNOSYNC},
+		{Devices: 1, Dimension: FLEETAUE, Value: "expired"},
+		{Devices: 1, Dimension: FLEETAUE, Value: "within 6 months"},
+		{Devices: 1, Dimension: FLEETAUE, Value: "over 24 months"},
+		{Devices: 1, Dimension: FLEETAUE, Value: FLEETUNKNOWN},
+		{Devices: 1, Dimension: FLEETLASTSYNC, Value: "within 7 days"},
+		{Devices: 1, Dimension: FLEETLASTSYNC, Value: "within 30 days"},
+		{Devices: 1, Dimension: FLEETLASTSYNC, Value: "within 90 days"},
+		{Devices: 1, Dimension: FLEETLASTSYNC, Value: "never"},
+		{Devices: 2, Dimension: FLEETMODEL, Value: "Chromebook A"},
+		{Devices: 2, Dimension: FLEETMODEL, Value: "Chromebook B"},
+		{Devices: 2, Dimension: FLEETORGUNIT, Value: "/"},
+		{Devices: 2, Dimension: FLEETORGUNIT, Value: "/Sales"},
+		{Devices: 3, Dimension: FLEETOSVERSION, Value: "86.0"},
+		{Devices: 1, Dimension: FLEETOSVERSION, Value: "85.0"},
+		{Devices: 2, Dimension: FLEETRECENTUSER, Value: "mickey@disney.com"},
+		{Devices: 1, Dimension: FLEETRECENTUSER, Value: "none"},
+		{Devices: 1, Dimension: FLEETRECENTUSER, Value: "unmanaged"},
+		{Devices: 3, Dimension: FLEETSTATUS, Value: "ACTIVE"},
+		{Devices: 1, Dimension: FLEETSTATUS, Value: "DISABLED"},
+	}
+
+	tsts.InitConfig()
+	lg.InitLogging("info")
+
+	fleet := NewFleet(opts)
+	for _, dev := range fleetTestDevices() {
+		fleet.Add(dev)
+	}
+
+	counts := []FleetCount{}
+	for _, count := range fleet.Counts() {
+		counts = append(counts, *count)
+	}
+	if !reflect.DeepEqual(counts, expectedCounts) {
+		t.Errorf("Got counts: %v - expected counts: %v", counts, expectedCounts)
+	}
+
+	matched := []string{}
+	for _, dev := range fleet.Matched {
+		matched = append(matched, dev.DeviceId)
+	}
+	expectedMatched := []string{"dev1", "dev2", "dev3"}
+	if !reflect.DeepEqual(matched, expectedMatched) {
+		t.Errorf("Got matched devices: %v - expected matched devices: %v", matched, expectedMatched)
+	}
+}
+
+func TestValidateFleetChecks(t *testing.T) {
+	cases := []struct {
+		checks      []string
+		expectedErr string
+	}{
+		{
+			checks: []string{FLEETCHECKAUE, FLEETCHECKNOSYNC},
+		},
+		{
+			checks:      []string{"battery"},
+			expectedErr: "invalid fleet check: battery - valid checks are: aue, no-sync",
+		},
+	}
+
+	tsts.InitConfig()
+	lg.InitLogging("info")
+
+	for _, c := range cases {
+		err := ValidateFleetChecks(c.checks)
+
+		gotErr := ""
+		if err != nil {
+			gotErr = err.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("Got error: %v - expected error: %v", gotErr, c.expectedErr)
+		}
+	}
+}
@@ -22,6 +22,38 @@ THE SOFTWARE.
 
 package commandaliases
 
+// ActivityAliases are activity command aliases
+var ActivityAliases = []string{
+	"activity",
+	"act",
+}
+
+// ASPAliases are app password command aliases
+var ASPAliases = []string{
+	"app-password",
+	"asp",
+}
+
+// BCodeAliases are backup code command aliases
+var BCodeAliases = []string{
+	"backup-code",
+	"bcode",
+}
+
+// BldgAliases are building command aliases
+var BldgAliases = []string{
+	"building",
+	"bldg",
+}
+
+// CalResAliases are calendar resource command aliases
+var CalResAliases = []string{
+	"calendar-resource",
+	"cal-resource",
+	"calres",
+	"cres",
+}
+
 // CDevAliases are ChromeOS device command aliases
 var CDevAliases = []string{
 	"chromeos-device",
@@ -30,6 +62,26 @@ var CDevAliases = []string{
 	"cdev",
 }
 
+// DAAliases are domain alias command aliases
+var DAAliases = []string{
+	"domain-alias",
+	"dom-alias",
+	"dalias",
+	"da",
+}
+
+// DomainAliases are domain command aliases
+var DomainAliases = []string{
+	"domain",
+	"dom",
+}
+
+// FeatAliases are feature command aliases
+var FeatAliases = []string{
+	"feature",
+	"feat",
+}
+
 // GAAliases are group alias command aliases
 var GAAliases = []string{
 	"group-alias",
@@ -76,12 +128,37 @@ var OUAliases = []string{
 	"ou",
 }
 
+// PrivAliases are privilege command aliases
+var PrivAliases = []string{
+	"privilege",
+	"priv",
+}
+
+// RAAliases are role assignment command aliases
+var RAAliases = []string{
+	"role-assignment",
+	"role-asgmt",
+	"rasgmt",
+	"ra",
+}
+
+// RoleAliases are role command aliases
+var RoleAliases = []string{
+	"role",
+}
+
 // SCAliases are schema command aliases
 var SCAliases = []string{
 	"schema",
 	"sc",
 }
 
+// TokenAliases are token command aliases
+var TokenAliases = []string{
+	"token",
+	"tok",
+}
+
 // UAAliases are user alias command aliases
 var UAAliases = []string{
 	"user-alias",
@@ -89,6 +166,19 @@ var UAAliases = []string{
 	"ua",
 }
 
+// UPhotoAliases are user photo command aliases
+var UPhotoAliases = []string{
+	"user-photo",
+	"usr-photo",
+	"uphoto",
+}
+
+// UsageAliases are usage report command aliases
+var UsageAliases = []string{
+	"usage-report",
+	"usage",
+}
+
 // UserAliases are user command aliases
 var UserAliases = []string{
 	"user",
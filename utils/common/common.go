@@ -30,21 +30,25 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net"
+	"net/http"
 	"os"
 	"os/user"
 	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	cfg "github.com/plusworx/gmin/utils/config"
 	flgnm "github.com/plusworx/gmin/utils/flagnames"
 	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	"github.com/spf13/viper"
 	"go.uber.org/zap"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 	admin "google.golang.org/api/admin/directory/v1"
+	reports "google.golang.org/api/admin/reports/v1"
 	"google.golang.org/api/googleapi"
 	gset "google.golang.org/api/groupssettings/v1"
 	"google.golang.org/api/option"
@@ -64,13 +68,21 @@ const (
 const (
 	// Object Types
 
-	OBJTYPECROSDEV = iota
+	OBJTYPEBUILDING = iota
+	OBJTYPECALRES
+	OBJTYPECROSDEV
+	OBJTYPEFEATURE
 	OBJTYPEGROUP
+	OBJTYPEGRPALIAS
 	OBJTYPEGRPSET
 	OBJTYPEMEMBER
 	OBJTYPEMOBDEV
 	OBJTYPEORGUNIT
+	OBJTYPEROLE
+	OBJTYPEROLEASSIGN
+	OBJTYPESTARTER
 	OBJTYPEUSER
+	OBJTYPEUSRALIAS
 )
 const (
 	// Service Types
@@ -79,11 +91,17 @@ const (
 	SRVTYPEADMIN = iota
 	// SRVTYPEGRPSETTING is used to request sheet service
 	SRVTYPEGRPSETTING
+	// SRVTYPEREPORT is used to request reports service
+	SRVTYPEREPORT
 	// SRVTYPESHEET is used to request sheet service
 	SRVTYPESHEET
 )
 
 const (
+	// GRPSETTINGSPATH is Groups Settings API path relative to an endpoint override
+	GRPSETTINGSPATH string = "groups/v1/groups/"
+	// LOOKUPWORKERS is the number of concurrent API lookups made when expanding nested groups
+	LOOKUPWORKERS int = 5
 	// QUIT is used for terminating commands
 	QUIT int = 99
 	// TIMEFORMAT is used to format timestamp
@@ -97,6 +115,7 @@ type EmptyValues struct {
 
 var globalFlagValues = []string{
 	"loglevel",
+	"output",
 }
 
 // Logger passed from logging package
@@ -111,6 +130,22 @@ var ValidFileFormats = []string{
 	"txt",
 }
 
+// ValidOutputFormats provides valid get and list output format strings
+var ValidOutputFormats = []string{
+	"csv",
+	"json",
+	"jsonl",
+	"table",
+	"tsv",
+	"yaml",
+}
+
+// ValidResultsFormats provides valid batch results file format strings
+var ValidResultsFormats = []string{
+	"csv",
+	"json",
+}
+
 // validLogLevels provides valid log level strings
 var validLogLevels = []string{
 	"debug",
@@ -129,10 +164,30 @@ var ValidSortOrders = map[string]string{
 
 // ValidPrimaryShowArgs holds valid primary arguments for the show command
 var ValidPrimaryShowArgs = []string{
+	"act",
+	"activity",
+	"app-password",
+	"asp",
+	"backup-code",
+	"bcode",
+	"bldg",
+	"building",
+	"cal-resource",
+	"calendar-resource",
+	"calres",
 	"cdev",
 	"chromeos-device",
+	"cres",
 	"cros-dev",
 	"cros-device",
+	"da",
+	"dalias",
+	"dom",
+	"dom-alias",
+	"domain",
+	"domain-alias",
+	"feat",
+	"feature",
 	"group",
 	"grp",
 	"group-alias",
@@ -155,27 +210,41 @@ var ValidPrimaryShowArgs = []string{
 	"mobile-device",
 	"orgunit",
 	"ou",
+	"priv",
+	"privilege",
+	"ra",
+	"rasgmt",
+	"role",
+	"role-asgmt",
+	"role-assignment",
 	"schema",
 	"sc",
+	"tok",
+	"token",
 	"ua",
 	"ualias",
+	"uphoto",
+	"usage",
+	"usage-report",
 	"user",
 	"user-alias",
+	"user-photo",
 	"usr",
+	"usr-photo",
 }
 
 // CreateService function creates and returns a service object
 func CreateService(serviceType int, scope ...string) (interface{}, error) {
 	var srv interface{}
 
-	ctx, ts, err := oauthSetup(scope)
+	ctx, opts, err := clientOptions(serviceType, scope)
 	if err != nil {
 		return nil, err
 	}
 
 	// Admin service
 	if serviceType == SRVTYPEADMIN {
-		srv, err = admin.NewService(ctx, option.WithTokenSource(ts))
+		srv, err = admin.NewService(ctx, opts...)
 		if err != nil {
 			err = fmt.Errorf(gmess.ERR_CREATEDIRECTORYSERVICE, err)
 			Logger.Error(err)
@@ -185,7 +254,7 @@ func CreateService(serviceType int, scope ...string) (interface{}, error) {
 
 	// Group Setting service
 	if serviceType == SRVTYPEGRPSETTING {
-		srv, err = gset.NewService(ctx, option.WithTokenSource(ts))
+		srv, err = gset.NewService(ctx, opts...)
 		if err != nil {
 			err = fmt.Errorf(gmess.ERR_CREATEGRPSETTINGSERVICE, err)
 			Logger.Error(err)
@@ -193,9 +262,19 @@ func CreateService(serviceType int, scope ...string) (interface{}, error) {
 		}
 	}
 
+	// Reports service
+	if serviceType == SRVTYPEREPORT {
+		srv, err = reports.NewService(ctx, opts...)
+		if err != nil {
+			err = fmt.Errorf(gmess.ERR_CREATEREPORTSSERVICE, err)
+			Logger.Error(err)
+			return nil, err
+		}
+	}
+
 	// Sheet service
 	if serviceType == SRVTYPESHEET {
-		srv, err = sheet.NewService(ctx, option.WithTokenSource(ts))
+		srv, err = sheet.NewService(ctx, opts...)
 		if err != nil {
 			err = fmt.Errorf(gmess.ERR_CREATESHEETSERVICE, err)
 			Logger.Error(err)
@@ -206,6 +285,93 @@ func CreateService(serviceType int, scope ...string) (interface{}, error) {
 	return srv, nil
 }
 
+// clientOptions returns the API client options for a service. If an endpoint override is
+// configured then requests are sent there unauthenticated, otherwise service account
+// credentials are used. In dry run mode write requests are replaced by a description
+// of the changes that they would make, otherwise they are journaled if a run journal is set.
+func clientOptions(serviceType int, scope []string) (context.Context, []option.ClientOption, error) {
+	Logger.Debugw("starting clientOptions()",
+		"serviceType", serviceType,
+		"scope", scope)
+	defer Logger.Debug("finished clientOptions()")
+
+	var (
+		client *http.Client
+		ctx    context.Context
+		opts   []option.ClientOption
+	)
+
+	endpoint := viper.GetString(cfg.CONFIGENDPOINT)
+	if endpoint != "" {
+		if !strings.HasSuffix(endpoint, "/") {
+			endpoint = endpoint + "/"
+		}
+		if serviceType == SRVTYPEGRPSETTING {
+			endpoint = endpoint + GRPSETTINGSPATH
+		}
+		Logger.Debugw("using endpoint override",
+			"endpoint", endpoint)
+		ctx = context.Background()
+		client = &http.Client{Transport: http.DefaultTransport}
+		opts = []option.ClientOption{option.WithEndpoint(endpoint), option.WithoutAuthentication()}
+	} else {
+		var (
+			err error
+			ts  oauth2.TokenSource
+		)
+		ctx, ts, err = oauthSetup(scope)
+		if err != nil {
+			return nil, nil, err
+		}
+		client = oauth2.NewClient(ctx, ts)
+		opts = []option.ClientOption{option.WithTokenSource(ts)}
+	}
+
+	opts = append(opts, option.WithHTTPClient(wrapClient(client)))
+	return ctx, opts, nil
+}
+
+// HTTPClient returns an HTTP client for sending API requests directly with the same credentials,
+// dry run and journaling behaviour as API services
+func HTTPClient(serviceType int, scope ...string) (*http.Client, error) {
+	Logger.Debugw("starting HTTPClient()",
+		"serviceType", serviceType,
+		"scope", scope)
+	defer Logger.Debug("finished HTTPClient()")
+
+	var client *http.Client
+
+	if viper.GetString(cfg.CONFIGENDPOINT) != "" {
+		client = &http.Client{Transport: http.DefaultTransport}
+	} else {
+		ctx, ts, err := oauthSetup(scope)
+		if err != nil {
+			return nil, err
+		}
+		client = oauth2.NewClient(ctx, ts)
+	}
+
+	return wrapClient(client), nil
+}
+
+// wrapClient returns a client that waits for the call limiter before each request and shows changes
+// in dry run mode or journals them otherwise
+func wrapClient(client *http.Client) *http.Client {
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	var transport http.RoundTripper = &limitTransport{base: base}
+
+	if DryRun {
+		return &http.Client{Transport: &dryRunTransport{base: transport}}
+	}
+	if RunJournal != nil {
+		return &http.Client{Transport: &journalTransport{base: transport, journal: RunJournal}}
+	}
+	return &http.Client{Transport: transport}
+}
+
 // deDupeStrSlice gets rid of duplicate values in a slice
 func deDupeStrSlice(strSlice []string) []string {
 	Logger.Debugw("starting deDupeStrSlice()",
@@ -224,11 +390,37 @@ func deDupeStrSlice(strSlice []string) []string {
 	return res
 }
 
+// DoConcurrently calls fn with each index from 0 to n-1 using no more than workers goroutines at a time
+// and returns when all of the calls have finished
+func DoConcurrently(n int, workers int, fn func(idx int)) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, workers)
+
+	for idx := 0; idx < n; idx++ {
+		idx := idx
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(idx)
+		}()
+	}
+	wg.Wait()
+}
+
 // GminMessage constructs a message for output
 func GminMessage(msgTxt string) string {
 	Logger.Debugw("starting GminMessage()",
 		"msgTxt", msgTxt)
 	defer Logger.Debug("finished GminMessage()")
+	if DryRun {
+		msgTxt = DRYRUNPREFIX + msgTxt
+	}
 	return Timestamp() + " gmin: " + msgTxt
 }
 
@@ -280,7 +472,7 @@ func IPAddress() string {
 
 	conn, err := net.Dial("udp", "8.8.8.8:80")
 	if err != nil {
-		ip = "unavailable"
+		return "unavailable"
 	}
 	defer conn.Close()
 
@@ -345,14 +537,17 @@ func oauthSetup(scope []string) (context.Context, oauth2.TokenSource, error) {
 		return nil, nil, err
 	}
 
-	credentialPath, err := cfg.ReadConfigString(cfg.CONFIGCREDPATH)
-	if err != nil {
-		return nil, nil, err
-	}
-
 	ctx := context.Background()
 
-	ServiceAccountFilePath := filepath.Join(filepath.ToSlash(credentialPath), cfg.CREDENTIALFILE)
+	// Profiles name a credential file, otherwise gmin_credentials in credential path is used
+	ServiceAccountFilePath := cfg.GetString(cfg.CONFIGCREDFILE)
+	if ServiceAccountFilePath == "" {
+		credentialPath, err := cfg.ReadConfigString(cfg.CONFIGCREDPATH)
+		if err != nil {
+			return nil, nil, err
+		}
+		ServiceAccountFilePath = filepath.Join(filepath.ToSlash(credentialPath), cfg.CREDENTIALFILE)
+	}
 
 	jsonCredentials, err := ioutil.ReadFile(ServiceAccountFilePath)
 	if err != nil {
@@ -566,6 +761,8 @@ func ShowGlobalFlagValues(lenArgs int, args []string, filter string) error {
 		switch {
 		case flag == flgnm.FLG_LOGLEVEL:
 			ShowFlagValues(validLogLevels, filter)
+		case flag == flgnm.FLG_OUTPUT:
+			ShowFlagValues(ValidOutputFormats, filter)
 		default:
 			err := fmt.Errorf(gmess.ERR_FLAGNOTRECOGNIZED, args[1])
 			Logger.Error(err)
@@ -621,6 +818,21 @@ func SliceContainsStr(strs []string, s string) bool {
 	return false
 }
 
+// SliceContainsStrFold tells whether a slice contains a particular string, ignoring case
+func SliceContainsStrFold(strs []string, s string) bool {
+	Logger.Debugw("starting SliceContainsStrFold()",
+		"strs", strs,
+		"s", s)
+	defer Logger.Debug("finished SliceContainsStrFold()")
+
+	for _, sComp := range strs {
+		if strings.EqualFold(s, sComp) {
+			return true
+		}
+	}
+	return false
+}
+
 // Timestamp gets current formatted time
 func Timestamp() string {
 	t := time.Now()
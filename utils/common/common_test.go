@@ -95,3 +95,31 @@ func TestSliceContainsStr(t *testing.T) {
 		}
 	}
 }
+
+func TestSliceContainsStrFold(t *testing.T) {
+	cases := []struct {
+		expectedResult bool
+		input          string
+		sl             []string
+	}{
+		{
+			expectedResult: true,
+			input:          "Sales@MyCompany.com",
+			sl:             []string{"finance@mycompany.com", "sales@mycompany.com"},
+		},
+		{
+			expectedResult: false,
+			input:          "marketing@mycompany.com",
+			sl:             []string{"finance@mycompany.com", "sales@mycompany.com"},
+		},
+	}
+
+	Logger = tsts.GetLogger()
+
+	for _, c := range cases {
+		res := SliceContainsStrFold(c.sl, c.input)
+		if res != c.expectedResult {
+			t.Errorf("Got result: %v - expected result: %v", res, c.expectedResult)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+)
+
+// DRYRUNPREFIX is prepended to gmin messages in dry run mode
+const DRYRUNPREFIX string = "[dry run] "
+
+// DryRun is set when mutating API calls should be shown rather than made
+var DryRun bool
+
+// dryRunActions are the API custom methods that are sent as POST requests
+var dryRunActions = map[string]bool{
+	"action":          true,
+	"generate":        true,
+	"invalidate":      true,
+	"makeAdmin":       true,
+	"moveDevicesToOu": true,
+	"signOut":         true,
+	"undelete":        true,
+}
+
+// dryRunNoGetPaths are parts of request paths whose objects can't be got on their own, so
+// changes to them are shown without the current state
+var dryRunNoGetPaths = []string{
+	"/aliases/",
+}
+
+// dryRunPathPrefixes are stripped from request paths to describe the object being changed
+var dryRunPathPrefixes = []string{
+	"admin/directory/v1/",
+	"groups/v1/",
+}
+
+// dryRunQueryParams are request query parameters that do not describe a change
+var dryRunQueryParams = map[string]bool{
+	"alt":         true,
+	"fields":      true,
+	"prettyPrint": true,
+}
+
+// dryRunTransport passes read requests on to the API and replaces write requests
+// with a description of the changes that they would make
+type dryRunTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	Logger.Debugw("starting RoundTrip()",
+		"method", req.Method,
+		"url", req.URL.String())
+	defer Logger.Debug("finished RoundTrip()")
+
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return t.base.RoundTrip(req)
+	}
+
+	update, err := dryRunRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	for param, vals := range req.URL.Query() {
+		if !dryRunQueryParams[param] && len(vals) > 0 {
+			update[param] = vals[0]
+		}
+	}
+
+	object := strings.TrimPrefix(req.URL.Path, "/")
+	for _, prefix := range dryRunPathPrefixes {
+		object = strings.TrimPrefix(object, prefix)
+	}
+
+	switch req.Method {
+	case http.MethodDelete:
+		if dryRunNoGet(object) {
+			dryRunPrint("delete", object, []string{"? " + gmess.INFO_DRYRUNSTATEUNKNOWN})
+			return dryRunResponse(req, http.StatusNoContent, nil)
+		}
+		current, errResp, err := t.current(req)
+		if errResp != nil || err != nil {
+			return errResp, err
+		}
+		dryRunPrint("delete", object, nil)
+		return dryRunResponse(req, http.StatusNoContent, current)
+	case http.MethodPatch, http.MethodPut:
+		current, errResp, err := t.current(req)
+		if errResp != nil || err != nil {
+			return errResp, err
+		}
+		dryRunPrint("update", object, DryRunChanges(current, update))
+		for key, val := range update {
+			current[key] = val
+		}
+		return dryRunResponse(req, http.StatusOK, current)
+	default:
+		action := "create"
+		if dryRunActions[path.Base(object)] {
+			action = path.Base(object)
+			object = path.Dir(object)
+		}
+		dryRunPrint(action, object, DryRunChanges(nil, update))
+		return dryRunResponse(req, http.StatusOK, update)
+	}
+}
+
+// current gets the object that a request would change. If the object can't be got then
+// the error response is returned instead so that callers see the same error that the
+// change would have caused.
+func (t *dryRunTransport) current(req *http.Request) (map[string]interface{}, *http.Response, error) {
+	getReq, err := http.NewRequest(http.MethodGet, req.URL.String(), nil)
+	if err != nil {
+		Logger.Error(err)
+		return nil, nil, err
+	}
+	getReq = getReq.WithContext(req.Context())
+	getReq.Header = req.Header.Clone()
+	getReq.Header.Del("Content-Type")
+
+	resp, err := t.base.RoundTrip(getReq)
+	if err != nil {
+		Logger.Error(err)
+		return nil, nil, err
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, resp, nil
+	}
+	defer resp.Body.Close()
+
+	current := map[string]interface{}{}
+	err = json.NewDecoder(resp.Body).Decode(&current)
+	if err != nil {
+		Logger.Error(err)
+		return nil, nil, err
+	}
+	return current, nil, nil
+}
+
+// DryRunChanges returns a sorted description of the fields in update that differ from
+// current. Nested objects are compared field by field and arrays are compared as a whole.
+func DryRunChanges(current map[string]interface{}, update map[string]interface{}) []string {
+	changes := []string{}
+	dryRunChanges("", current, update, &changes)
+	sort.Strings(changes)
+	return changes
+}
+
+func dryRunChanges(prefix string, current map[string]interface{}, update map[string]interface{}, changes *[]string) {
+	for key, newVal := range update {
+		attr := prefix + key
+		oldVal, exists := current[key]
+
+		newMap, newIsMap := newVal.(map[string]interface{})
+		oldMap, oldIsMap := oldVal.(map[string]interface{})
+		if newIsMap && (oldIsMap || oldVal == nil) {
+			dryRunChanges(attr+".", oldMap, newMap, changes)
+			continue
+		}
+
+		if exists && reflect.DeepEqual(oldVal, newVal) {
+			continue
+		}
+		if !exists {
+			*changes = append(*changes, fmt.Sprintf("+ %v: %v", attr, dryRunValue(key, newVal)))
+			continue
+		}
+		*changes = append(*changes, fmt.Sprintf("~ %v: %v -> %v", attr, dryRunValue(key, oldVal), dryRunValue(key, newVal)))
+	}
+}
+
+func dryRunNoGet(object string) bool {
+	for _, noGetPath := range dryRunNoGetPaths {
+		if strings.Contains(object, noGetPath) {
+			return true
+		}
+	}
+	return false
+}
+
+func dryRunPrint(action string, object string, changes []string) {
+	msg := GminMessage(fmt.Sprintf(gmess.INFO_DRYRUNCHANGE, action, object))
+	for _, change := range changes {
+		msg = msg + "\n    " + change
+	}
+	fmt.Println(msg)
+	Logger.Infow(fmt.Sprintf(gmess.INFO_DRYRUNCHANGE, action, object),
+		"changes", changes)
+}
+
+func dryRunRequestBody(req *http.Request) (map[string]interface{}, error) {
+	update := map[string]interface{}{}
+	if req.Body == nil {
+		return update, nil
+	}
+	defer req.Body.Close()
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		Logger.Error(err)
+		return nil, err
+	}
+	if len(body) > 0 {
+		// Non-JSON bodies are ignored
+		json.Unmarshal(body, &update)
+	}
+	return update, nil
+}
+
+func dryRunResponse(req *http.Request, code int, obj map[string]interface{}) (*http.Response, error) {
+	body := []byte{}
+	if code != http.StatusNoContent {
+		var err error
+		body, err = json.Marshal(obj)
+		if err != nil {
+			Logger.Error(err)
+			return nil, err
+		}
+	}
+
+	return &http.Response{
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Header:        http.Header{"Content-Type": []string{"application/json; charset=UTF-8"}},
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Request:       req,
+		Status:        fmt.Sprintf("%d %v", code, http.StatusText(code)),
+		StatusCode:    code,
+	}, nil
+}
+
+func dryRunValue(key string, val interface{}) string {
+	if val == nil {
+		return "null"
+	}
+	if strings.EqualFold(key, "password") {
+		return "********"
+	}
+	jsonVal, err := json.Marshal(val)
+	if err != nil {
+		return fmt.Sprintf("%v", val)
+	}
+	return string(jsonVal)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package common
+
+import (
+	"reflect"
+	"testing"
+
+	tsts "github.com/plusworx/gmin/tests"
+)
+
+func TestDryRunChanges(t *testing.T) {
+	cases := []struct {
+		current         map[string]interface{}
+		expectedChanges []string
+		update          map[string]interface{}
+	}{
+		{
+			current: map[string]interface{}{
+				"name":        map[string]interface{}{"familyName": "Mouse", "givenName": "Mickey"},
+				"orgUnitPath": "/",
+				"suspended":   false,
+			},
+			expectedChanges: []string{
+				"+ password: ********",
+				`~ name.givenName: "Mickey" -> "Michael"`,
+				`~ orgUnitPath: "/" -> "/Characters"`,
+			},
+			update: map[string]interface{}{
+				"name":        map[string]interface{}{"familyName": "Mouse", "givenName": "Michael"},
+				"orgUnitPath": "/Characters",
+				"password":    "VeryStrongPassword",
+				"suspended":   false,
+			},
+		},
+		{
+			current: nil,
+			expectedChanges: []string{
+				`+ deviceIds: ["cros1","cros2"]`,
+				`+ orgUnitPath: "/Characters"`,
+			},
+			update: map[string]interface{}{
+				"deviceIds":   []interface{}{"cros1", "cros2"},
+				"orgUnitPath": "/Characters",
+			},
+		},
+		{
+			current: map[string]interface{}{
+				"aliases": []interface{}{"mickey@disney.com"},
+			},
+			expectedChanges: []string{},
+			update: map[string]interface{}{
+				"aliases": []interface{}{"mickey@disney.com"},
+			},
+		},
+	}
+
+	Logger = tsts.GetLogger()
+
+	for _, c := range cases {
+		output := DryRunChanges(c.current, c.update)
+
+		if !reflect.DeepEqual(output, c.expectedChanges) {
+			t.Errorf("Got output: %v - expected: %v", output, c.expectedChanges)
+		}
+	}
+}
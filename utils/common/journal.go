@@ -0,0 +1,628 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package common
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	cfg "github.com/plusworx/gmin/utils/config"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+)
+
+const (
+	// JOURNALDIR is the name of the journal directory in the log path
+	JOURNALDIR string = "journal"
+	// JOURNALEXT is the file extension of journal run files
+	JOURNALEXT string = ".jsonl"
+	// JOURNALREDACTED replaces the values of secret attributes in journaled request bodies
+	JOURNALREDACTED string = "REDACTED"
+	// JOURNALTIMEFORMAT is used to start run IDs
+	JOURNALTIMEFORMAT string = "20060102150405"
+)
+
+// RunJournal records the changes made by the current command. Changes are not journaled if it is nil.
+var RunJournal *Journal
+
+// journalAPIPrefixes are the API path prefixes that journaled object paths follow
+var journalAPIPrefixes = []string{
+	"admin/directory/v1/",
+	"groups/v1/",
+}
+
+// journalGetQueryParams are the request query parameters that are kept when getting the object
+// that a request will change
+var journalGetQueryParams = map[string]bool{
+	"alt":         true,
+	"prettyPrint": true,
+}
+
+// journalNoRevert are attributes whose previous values can't be restored by undo. Their values are
+// redacted in journaled request bodies.
+var journalNoRevert = map[string]bool{
+	"hashFunction": true,
+	"password":     true,
+}
+
+// Journal writes the before-image of objects changed by a gmin run to a journal file
+type Journal struct {
+	command string
+	dir     string
+	mu      sync.Mutex
+	runID   string
+}
+
+// JournalEntry records a successful change made by an API request
+type JournalEntry struct {
+	// After is the response to a POST request, which is the created object for inserts
+	After json.RawMessage `json:"after,omitempty"`
+	// Before is the object before a PUT, PATCH or DELETE request
+	Before  json.RawMessage `json:"before,omitempty"`
+	Body    json.RawMessage `json:"body,omitempty"`
+	Command string          `json:"command"`
+	Method  string          `json:"method"`
+	RunID   string          `json:"runId"`
+	Status  int             `json:"status"`
+	Time    time.Time       `json:"time"`
+	URL     string          `json:"url"`
+}
+
+// JournalRun summarises a journaled gmin run
+type JournalRun struct {
+	Changes int       `json:"changes"`
+	Command string    `json:"command"`
+	RunID   string    `json:"runId"`
+	Time    time.Time `json:"time"`
+}
+
+// UndoRequest is an API request that reverts a journaled change
+type UndoRequest struct {
+	Body        []byte
+	Description string
+	Method      string
+	URL         string
+}
+
+// journalTransport records the before-image of objects changed by write requests
+type journalTransport struct {
+	base    http.RoundTripper
+	journal *Journal
+}
+
+// NewJournal returns a Journal that writes to a new run file in dir when the first change is recorded
+func NewJournal(dir string, command string) (*Journal, error) {
+	Logger.Debugw("starting NewJournal()",
+		"dir", dir,
+		"command", command)
+	defer Logger.Debug("finished NewJournal()")
+
+	randBytes := make([]byte, 3)
+	_, err := rand.Read(randBytes)
+	if err != nil {
+		Logger.Error(err)
+		return nil, err
+	}
+
+	runID := time.Now().UTC().Format(JOURNALTIMEFORMAT) + "-" + hex.EncodeToString(randBytes)
+	return &Journal{command: command, dir: dir, runID: runID}, nil
+}
+
+// JournalDir returns the journal directory in the log path
+func JournalDir() (string, error) {
+	logpath := cfg.GetString(cfg.CONFIGLOGPATH)
+	if logpath == "" {
+		logpath = os.Getenv(cfg.ENVPREFIX + cfg.ENVVARLOGPATH)
+		if logpath == "" {
+			err := fmt.Errorf(gmess.ERR_NOTFOUNDINCONFIG, cfg.CONFIGLOGPATH)
+			Logger.Error(err)
+			return "", err
+		}
+	}
+	return filepath.Join(logpath, JOURNALDIR), nil
+}
+
+// JournalRuns returns summaries of the journaled runs in dir with the most recent first
+func JournalRuns(dir string) ([]JournalRun, error) {
+	Logger.Debugw("starting JournalRuns()",
+		"dir", dir)
+	defer Logger.Debug("finished JournalRuns()")
+
+	runs := []JournalRun{}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return runs, nil
+		}
+		Logger.Error(err)
+		return nil, err
+	}
+
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != JOURNALEXT {
+			continue
+		}
+
+		entries, err := ReadJournal(dir, strings.TrimSuffix(file.Name(), JOURNALEXT))
+		if err != nil {
+			return nil, err
+		}
+		if len(entries) == 0 {
+			continue
+		}
+		runs = append(runs, JournalRun{Changes: len(entries), Command: entries[0].Command, RunID: entries[0].RunID, Time: entries[0].Time})
+	}
+
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].Time.After(runs[j].Time) })
+	return runs, nil
+}
+
+// JournalUndo returns the request that reverts a journaled change. Deleted users are undeleted,
+// deleted groups, members, aliases, orgunits, roles, role assignments, schemas and calendar resources,
+// buildings and features are recreated,
+// updated objects have the changed attributes set back to their previous values and created objects
+// are deleted.
+func JournalUndo(entry JournalEntry) (*UndoRequest, error) {
+	Logger.Debugw("starting JournalUndo()",
+		"method", entry.Method,
+		"url", entry.URL)
+	defer Logger.Debug("finished JournalUndo()")
+
+	var (
+		after  map[string]interface{}
+		before map[string]interface{}
+		undo   *UndoRequest
+	)
+
+	apiURL, segs, err := journalObjectPath(entry.URL)
+	if err != nil {
+		return nil, err
+	}
+	if len(entry.After) > 0 {
+		json.Unmarshal(entry.After, &after)
+	}
+	if len(entry.Before) > 0 {
+		json.Unmarshal(entry.Before, &before)
+	}
+
+	object := strings.Join(segs, "/")
+	lastSeg := segs[len(segs)-1]
+
+	switch entry.Method {
+	case http.MethodDelete:
+		// Aliases can't be got so deleted aliases are recreated from the request path
+		if (segs[0] == "users" || segs[0] == "groups") && len(segs) == 4 && segs[2] == "aliases" {
+			undo = journalUndoRequest(http.MethodPost, apiURL, segs[:3], map[string]interface{}{"alias": segs[3]})
+			break
+		}
+		if before == nil {
+			break
+		}
+		switch {
+		case segs[0] == "users" && len(segs) == 2:
+			undo = journalUndoRequest(http.MethodPost, apiURL, []string{"users", journalString(before, "id"), "undelete"},
+				journalPick(before, "orgUnitPath"))
+		case segs[0] == "groups" && len(segs) == 2:
+			undo = journalUndoRequest(http.MethodPost, apiURL, segs[:1], journalPick(before, "description", "email", "name"))
+		case segs[0] == "groups" && len(segs) == 4 && segs[2] == "members":
+			member := journalPick(before, "delivery_settings", "email", "role", "type")
+			if member["email"] == nil {
+				member["id"] = before["id"]
+			}
+			undo = journalUndoRequest(http.MethodPost, apiURL, segs[:3], member)
+		case segs[0] == "customer" && len(segs) > 3 && segs[2] == "orgunits":
+			undo = journalUndoRequest(http.MethodPost, apiURL, segs[:3],
+				journalPick(before, "blockInheritance", "description", "name", "parentOrgUnitPath"))
+		case segs[0] == "customer" && len(segs) == 4 && segs[2] == "roleassignments":
+			undo = journalUndoRequest(http.MethodPost, apiURL, segs[:3], journalPick(before, "assignedTo", "orgUnitId", "roleId", "scopeType"))
+		case segs[0] == "customer" && len(segs) == 4 && segs[2] == "roles":
+			undo = journalUndoRequest(http.MethodPost, apiURL, segs[:3], journalPick(before, "roleDescription", "roleName", "rolePrivileges"))
+		case segs[0] == "customer" && len(segs) == 4 && segs[2] == "schemas":
+			undo = journalUndoRequest(http.MethodPost, apiURL, segs[:3], journalPick(before, "displayName", "fields", "schemaName"))
+		case segs[0] == "customer" && len(segs) == 5 && segs[3] == "buildings":
+			undo = journalUndoRequest(http.MethodPost, apiURL, segs[:4],
+				journalPick(before, "address", "buildingId", "buildingName", "coordinates", "description", "floorNames"))
+		case segs[0] == "customer" && len(segs) == 5 && segs[3] == "calendars":
+			undo = journalUndoRequest(http.MethodPost, apiURL, segs[:4],
+				journalPick(before, "buildingId", "capacity", "featureInstances", "floorName", "floorSection", "resourceCategory",
+					"resourceDescription", "resourceId", "resourceName", "resourceType", "userVisibleDescription"))
+		case segs[0] == "customer" && len(segs) == 5 && segs[3] == "features":
+			undo = journalUndoRequest(http.MethodPost, apiURL, segs[:4], journalPick(before, "name"))
+		}
+	case http.MethodPatch, http.MethodPut:
+		if before == nil {
+			break
+		}
+		var body map[string]interface{}
+		json.Unmarshal(entry.Body, &body)
+
+		revert := map[string]interface{}{}
+		for key, val := range body {
+			if journalNoRevert[key] {
+				continue
+			}
+			prev, ok := before[key]
+			if !ok {
+				prev = journalZero(val)
+			}
+			revert[key] = prev
+		}
+		if len(revert) == 0 {
+			break
+		}
+
+		// IDs are used where possible because updates can change email addresses and orgunit paths,
+		// but group settings only accept email addresses
+		grpSettings := strings.HasSuffix(apiURL, "/"+journalAPIPrefixes[1])
+		objSegs := segs
+		switch {
+		case (segs[0] == "users" || segs[0] == "groups") && len(segs) == 2 && !grpSettings && journalString(before, "id") != "":
+			objSegs = []string{segs[0], journalString(before, "id")}
+		case segs[0] == "customer" && len(segs) > 3 && segs[2] == "orgunits" && journalString(before, "orgUnitId") != "":
+			objSegs = []string{segs[0], segs[1], segs[2], journalString(before, "orgUnitId")}
+		}
+		undo = journalUndoRequest(http.MethodPatch, apiURL, objSegs, revert)
+	case http.MethodPost:
+		switch {
+		case segs[0] == "users" && len(segs) == 3 && lastSeg == "undelete":
+			undo = journalUndoRequest(http.MethodDelete, apiURL, segs[:2], nil)
+		case segs[0] == "users" && len(segs) == 3 && lastSeg == "makeAdmin":
+			var body map[string]interface{}
+			json.Unmarshal(entry.Body, &body)
+			status, _ := body["status"].(bool)
+			undo = journalUndoRequest(http.MethodPost, apiURL, segs, map[string]interface{}{"status": !status})
+		case segs[0] == "customer" && len(segs) == 6 && segs[3] == "features" && lastSeg == "rename":
+			var body map[string]interface{}
+			json.Unmarshal(entry.Body, &body)
+			undo = journalUndoRequest(http.MethodPost, apiURL, []string{segs[0], segs[1], segs[2], segs[3], journalString(body, "newName"), lastSeg},
+				map[string]interface{}{"newName": segs[4]})
+		case after == nil:
+		case (segs[0] == "users" || segs[0] == "groups") && len(segs) == 1 && journalString(after, "id") != "":
+			undo = journalUndoRequest(http.MethodDelete, apiURL, []string{segs[0], journalString(after, "id")}, nil)
+		case segs[0] == "groups" && len(segs) == 3 && segs[2] == "members":
+			key := journalString(after, "id")
+			if key == "" {
+				key = journalString(after, "email")
+			}
+			undo = journalUndoRequest(http.MethodDelete, apiURL, append(segs[:3:3], key), nil)
+		case (segs[0] == "users" || segs[0] == "groups") && len(segs) == 3 && segs[2] == "aliases":
+			undo = journalUndoRequest(http.MethodDelete, apiURL, append(segs[:3:3], journalString(after, "alias")), nil)
+		case segs[0] == "customer" && len(segs) == 3 && segs[2] == "orgunits":
+			undo = journalUndoRequest(http.MethodDelete, apiURL, append(segs[:3:3], journalString(after, "orgUnitId")), nil)
+		case segs[0] == "customer" && len(segs) == 3 && segs[2] == "roleassignments":
+			undo = journalUndoRequest(http.MethodDelete, apiURL, append(segs[:3:3], journalString(after, "roleAssignmentId")), nil)
+		case segs[0] == "customer" && len(segs) == 3 && segs[2] == "roles":
+			undo = journalUndoRequest(http.MethodDelete, apiURL, append(segs[:3:3], journalString(after, "roleId")), nil)
+		case segs[0] == "customer" && len(segs) == 3 && segs[2] == "schemas":
+			undo = journalUndoRequest(http.MethodDelete, apiURL, append(segs[:3:3], journalString(after, "schemaId")), nil)
+		case segs[0] == "customer" && len(segs) == 4 && segs[3] == "buildings":
+			undo = journalUndoRequest(http.MethodDelete, apiURL, append(segs[:4:4], journalString(after, "buildingId")), nil)
+		case segs[0] == "customer" && len(segs) == 4 && segs[3] == "calendars":
+			undo = journalUndoRequest(http.MethodDelete, apiURL, append(segs[:4:4], journalString(after, "resourceId")), nil)
+		case segs[0] == "customer" && len(segs) == 4 && segs[3] == "features":
+			undo = journalUndoRequest(http.MethodDelete, apiURL, append(segs[:4:4], journalString(after, "name")), nil)
+		}
+	}
+
+	if undo == nil {
+		err = fmt.Errorf(gmess.ERR_CANNOTUNDO, entry.Method, object)
+		Logger.Error(err)
+		return nil, err
+	}
+	undo.Description = strings.ToLower(undo.Method) + " " + strings.TrimPrefix(undo.URL, apiURL)
+	return undo, nil
+}
+
+// ReadJournal reads the entries of a journaled run
+func ReadJournal(dir string, runID string) ([]JournalEntry, error) {
+	Logger.Debugw("starting ReadJournal()",
+		"dir", dir,
+		"runID", runID)
+	defer Logger.Debug("finished ReadJournal()")
+
+	entries := []JournalEntry{}
+
+	f, err := os.Open(filepath.Join(dir, filepath.Base(runID)+JOURNALEXT))
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = fmt.Errorf(gmess.ERR_RUNNOTFOUND, runID)
+		}
+		Logger.Error(err)
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry JournalEntry
+		err = json.Unmarshal(scanner.Bytes(), &entry)
+		if err != nil {
+			Logger.Error(err)
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	err = scanner.Err()
+	if err != nil {
+		Logger.Error(err)
+		return nil, err
+	}
+	return entries, nil
+}
+
+// RunID returns the ID of the journaled run
+func (j *Journal) RunID() string {
+	return j.runID
+}
+
+func (j *Journal) add(entry JournalEntry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entry.Command = j.command
+	entry.RunID = j.runID
+	entry.Time = time.Now().UTC()
+
+	jsonData, err := json.Marshal(entry)
+	if err != nil {
+		Logger.Error(err)
+		return err
+	}
+
+	err = os.MkdirAll(j.dir, 0700)
+	if err != nil {
+		Logger.Error(err)
+		return err
+	}
+
+	f, err := os.OpenFile(filepath.Join(j.dir, j.runID+JOURNALEXT), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		Logger.Error(err)
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(jsonData, '\n'))
+	if err != nil {
+		Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *journalTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	Logger.Debugw("starting RoundTrip()",
+		"method", req.Method,
+		"url", req.URL.String())
+	defer Logger.Debug("finished RoundTrip()")
+
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return t.base.RoundTrip(req)
+	}
+
+	entry := JournalEntry{Method: req.Method, URL: req.URL.String()}
+
+	if req.Body != nil {
+		body, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			Logger.Error(err)
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		if json.Valid(body) {
+			entry.Body = journalRedact(body)
+		}
+	}
+
+	if req.Method != http.MethodPost {
+		before, err := t.before(req)
+		if err != nil {
+			return nil, err
+		}
+		entry.Before = before
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode >= http.StatusMultipleChoices {
+		return resp, err
+	}
+	entry.Status = resp.StatusCode
+
+	if req.Method == http.MethodPost && resp.Body != nil {
+		after, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			Logger.Error(err)
+			return nil, err
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(after))
+		if json.Valid(after) {
+			entry.After = after
+		}
+	}
+
+	// A change that has been made is not failed because it couldn't be journaled
+	err = t.journal.add(entry)
+	if err != nil {
+		Logger.Errorw(gmess.ERR_JOURNALWRITE,
+			"error", err,
+			"url", entry.URL)
+	}
+	return resp, nil
+}
+
+// before gets the object that a request will change. Nothing is returned if it can't be got.
+func (t *journalTransport) before(req *http.Request) (json.RawMessage, error) {
+	query := url.Values{}
+	for param, vals := range req.URL.Query() {
+		if journalGetQueryParams[param] {
+			query[param] = vals
+		}
+	}
+	getURL := *req.URL
+	getURL.RawQuery = query.Encode()
+
+	getReq, err := http.NewRequest(http.MethodGet, getURL.String(), nil)
+	if err != nil {
+		Logger.Error(err)
+		return nil, err
+	}
+	getReq = getReq.WithContext(req.Context())
+	getReq.Header = req.Header.Clone()
+	getReq.Header.Del("Content-Type")
+
+	resp, err := t.base.RoundTrip(getReq)
+	if err != nil {
+		Logger.Error(err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, nil
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		Logger.Error(err)
+		return nil, err
+	}
+	if !json.Valid(body) {
+		return nil, nil
+	}
+	return body, nil
+}
+
+// journalObjectPath splits a request URL into the API base URL and the object path segments
+func journalObjectPath(reqURL string) (string, []string, error) {
+	u, err := url.Parse(reqURL)
+	if err != nil {
+		Logger.Error(err)
+		return "", nil, err
+	}
+
+	for _, prefix := range journalAPIPrefixes {
+		idx := strings.Index(u.Path, "/"+prefix)
+		if idx < 0 {
+			continue
+		}
+		object := strings.Trim(u.Path[idx+len(prefix)+1:], "/")
+		if object == "" {
+			break
+		}
+		return u.Scheme + "://" + u.Host + u.Path[:idx+len(prefix)+1], strings.Split(object, "/"), nil
+	}
+
+	err = fmt.Errorf(gmess.ERR_CANNOTUNDO, "", u.Path)
+	Logger.Error(err)
+	return "", nil, err
+}
+
+func journalPick(obj map[string]interface{}, keys ...string) map[string]interface{} {
+	picked := map[string]interface{}{}
+	for _, key := range keys {
+		if val, ok := obj[key]; ok {
+			picked[key] = val
+		}
+	}
+	return picked
+}
+
+// journalRedact replaces the values of attributes that can't be reverted, such as passwords, so that
+// they aren't written to the journal
+func journalRedact(body []byte) []byte {
+	var obj map[string]interface{}
+	err := json.Unmarshal(body, &obj)
+	if err != nil {
+		return body
+	}
+
+	redacted := false
+	for key := range journalNoRevert {
+		if _, ok := obj[key]; ok {
+			obj[key] = JOURNALREDACTED
+			redacted = true
+		}
+	}
+	if !redacted {
+		return body
+	}
+
+	redactedBody, err := json.Marshal(obj)
+	if err != nil {
+		return body
+	}
+	return redactedBody
+}
+
+func journalString(obj map[string]interface{}, key string) string {
+	str, _ := obj[key].(string)
+	return str
+}
+
+func journalUndoRequest(method string, apiURL string, segs []string, body map[string]interface{}) *UndoRequest {
+	escaped := make([]string, len(segs))
+	for idx, seg := range segs {
+		escaped[idx] = url.PathEscape(seg)
+	}
+
+	undo := &UndoRequest{Method: method, URL: apiURL + strings.Join(escaped, "/")}
+	if body != nil {
+		undo.Body, _ = json.Marshal(body)
+	}
+	return undo
+}
+
+// journalZero returns the value that clears an attribute that was missing before a change
+func journalZero(val interface{}) interface{} {
+	switch val.(type) {
+	case bool:
+		return false
+	case float64:
+		return 0
+	case string:
+		return ""
+	case []interface{}:
+		return []interface{}{}
+	}
+	return nil
+}
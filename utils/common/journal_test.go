@@ -0,0 +1,293 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package common
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	tsts "github.com/plusworx/gmin/tests"
+)
+
+func TestJournalUndo(t *testing.T) {
+	const apiURL = "https://admin.googleapis.com/admin/directory/v1/"
+
+	cases := []struct {
+		entry          JournalEntry
+		expectedBody   string
+		expectedErr    string
+		expectedMethod string
+		expectedURL    string
+	}{
+		{
+			entry: JournalEntry{
+				Before: json.RawMessage(`{"id":"123","orgUnitPath":"/Characters","primaryEmail":"mickey.mouse@disney.com"}`),
+				Method: "DELETE",
+				URL:    apiURL + "users/mickey.mouse@disney.com",
+			},
+			expectedBody:   `{"orgUnitPath":"/Characters"}`,
+			expectedMethod: "POST",
+			expectedURL:    apiURL + "users/123/undelete",
+		},
+		{
+			entry: JournalEntry{
+				Method: "DELETE",
+				URL:    apiURL + "users/mickey.mouse@disney.com/aliases/mickey@disney.com",
+			},
+			expectedBody:   `{"alias":"mickey@disney.com"}`,
+			expectedMethod: "POST",
+			expectedURL:    apiURL + "users/mickey.mouse@disney.com/aliases",
+		},
+		{
+			entry: JournalEntry{
+				Before: json.RawMessage(`{"email":"mickey.mouse@disney.com","etag":"abc","id":"123","role":"OWNER"}`),
+				Method: "DELETE",
+				URL:    apiURL + "groups/cartoons@disney.com/members/mickey.mouse@disney.com",
+			},
+			expectedBody:   `{"email":"mickey.mouse@disney.com","role":"OWNER"}`,
+			expectedMethod: "POST",
+			expectedURL:    apiURL + "groups/cartoons@disney.com/members",
+		},
+		{
+			entry: JournalEntry{
+				Before: json.RawMessage(`{"id":"123","name":{"familyName":"Mouse","givenName":"Mickey"},"primaryEmail":"mickey.mouse@disney.com"}`),
+				Body:   json.RawMessage(`{"name":{"givenName":"Michael"},"password":"VeryStrongPassword","suspended":true}`),
+				Method: "PUT",
+				URL:    apiURL + "users/mickey.mouse@disney.com",
+			},
+			expectedBody:   `{"name":{"familyName":"Mouse","givenName":"Mickey"},"suspended":false}`,
+			expectedMethod: "PATCH",
+			expectedURL:    apiURL + "users/123",
+		},
+		{
+			entry: JournalEntry{
+				Before: json.RawMessage(`{"email":"cartoons@disney.com","whoCanJoin":"CAN_REQUEST_TO_JOIN"}`),
+				Body:   json.RawMessage(`{"whoCanJoin":"ALL_IN_DOMAIN_CAN_JOIN"}`),
+				Method: "PATCH",
+				URL:    "https://www.googleapis.com/groups/v1/groups/cartoons@disney.com",
+			},
+			expectedBody:   `{"whoCanJoin":"CAN_REQUEST_TO_JOIN"}`,
+			expectedMethod: "PATCH",
+			expectedURL:    "https://www.googleapis.com/groups/v1/groups/cartoons@disney.com",
+		},
+		{
+			entry: JournalEntry{
+				After:  json.RawMessage(`{"email":"villains@disney.com","id":"456"}`),
+				Method: "POST",
+				URL:    apiURL + "groups",
+			},
+			expectedMethod: "DELETE",
+			expectedURL:    apiURL + "groups/456",
+		},
+		{
+			entry: JournalEntry{
+				After:  json.RawMessage(`{"orgUnitId":"id:789","orgUnitPath":"/Villains"}`),
+				Method: "POST",
+				URL:    apiURL + "customer/my_customer/orgunits",
+			},
+			expectedMethod: "DELETE",
+			expectedURL:    apiURL + "customer/my_customer/orgunits/id:789",
+		},
+		{
+			entry: JournalEntry{
+				After:  json.RawMessage(`{"roleId":"321","roleName":"Helpdesk Admin"}`),
+				Method: "POST",
+				URL:    apiURL + "customer/my_customer/roles",
+			},
+			expectedMethod: "DELETE",
+			expectedURL:    apiURL + "customer/my_customer/roles/321",
+		},
+		{
+			entry: JournalEntry{
+				Before: json.RawMessage(`{"assignedTo":"123","etag":"abc","roleAssignmentId":"654","roleId":"321","scopeType":"CUSTOMER"}`),
+				Method: "DELETE",
+				URL:    apiURL + "customer/my_customer/roleassignments/654",
+			},
+			expectedBody:   `{"assignedTo":"123","roleId":"321","scopeType":"CUSTOMER"}`,
+			expectedMethod: "POST",
+			expectedURL:    apiURL + "customer/my_customer/roleassignments",
+		},
+		{
+			entry: JournalEntry{
+				Before: json.RawMessage(`{"capacity":"10","etags":"abc","kind":"admin#directory#resources#calendars#CalendarResource","resourceEmail":"c_123@resource.calendar.google.com","resourceId":"room1","resourceName":"Room 1"}`),
+				Method: "DELETE",
+				URL:    apiURL + "customer/my_customer/resources/calendars/room1",
+			},
+			expectedBody:   `{"capacity":"10","resourceId":"room1","resourceName":"Room 1"}`,
+			expectedMethod: "POST",
+			expectedURL:    apiURL + "customer/my_customer/resources/calendars",
+		},
+		{
+			entry: JournalEntry{
+				Body:   json.RawMessage(`{"newName":"Whiteboard"}`),
+				Method: "POST",
+				URL:    apiURL + "customer/my_customer/resources/features/Blackboard/rename",
+			},
+			expectedBody:   `{"newName":"Blackboard"}`,
+			expectedMethod: "POST",
+			expectedURL:    apiURL + "customer/my_customer/resources/features/Whiteboard/rename",
+		},
+		{
+			entry: JournalEntry{
+				Body:   json.RawMessage(`{"status":true}`),
+				Method: "POST",
+				URL:    apiURL + "users/123/makeAdmin",
+			},
+			expectedBody:   `{"status":false}`,
+			expectedMethod: "POST",
+			expectedURL:    apiURL + "users/123/makeAdmin",
+		},
+		{
+			entry: JournalEntry{
+				Method: "POST",
+				URL:    apiURL + "users/123/undelete",
+			},
+			expectedMethod: "DELETE",
+			expectedURL:    apiURL + "users/123",
+		},
+		{
+			entry: JournalEntry{
+				Method: "POST",
+				URL:    apiURL + "customer/my_customer/devices/chromeos/cros1/action",
+			},
+			expectedErr: "cannot undo POST customer/my_customer/devices/chromeos/cros1/action",
+		},
+	}
+
+	Logger = tsts.GetLogger()
+
+	for _, c := range cases {
+		undo, err := JournalUndo(c.entry)
+
+		gotErr := ""
+		if err != nil {
+			gotErr = err.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("Got error: %v - expected error: %v", gotErr, c.expectedErr)
+		}
+		if err != nil {
+			continue
+		}
+
+		if undo.Method != c.expectedMethod {
+			t.Errorf("Got method: %v - expected method: %v", undo.Method, c.expectedMethod)
+		}
+		if undo.URL != c.expectedURL {
+			t.Errorf("Got URL: %v - expected URL: %v", undo.URL, c.expectedURL)
+		}
+		if string(undo.Body) != c.expectedBody {
+			t.Errorf("Got body: %v - expected body: %v", string(undo.Body), c.expectedBody)
+		}
+	}
+}
+
+func TestJournalTransport(t *testing.T) {
+	Logger = tsts.GetLogger()
+
+	var getQuery url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			getQuery = r.URL.Query()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"123","primaryEmail":"mickey.mouse@disney.com"}`))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	j, err := NewJournal(dir, "gmin update user mickey.mouse@disney.com")
+	if err != nil {
+		t.Fatalf("Got error: %v - expected error: nil", err)
+	}
+	client := &http.Client{Transport: &journalTransport{base: http.DefaultTransport, journal: j}}
+
+	req, err := http.NewRequest(http.MethodPut, srv.URL+"/admin/directory/v1/users/mickey.mouse@disney.com?alt=json&resolveConflictAccount=true",
+		strings.NewReader(`{"hashFunction":"SHA-1","password":"VeryStrongPassword","suspended":true}`))
+	if err != nil {
+		t.Fatalf("Got error: %v - expected error: nil", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Got error: %v - expected error: nil", err)
+	}
+	resp.Body.Close()
+
+	if getQuery.Get("alt") != "json" || getQuery.Get("resolveConflictAccount") != "" {
+		t.Errorf("Got before query: %v - expected before query: alt=json", getQuery.Encode())
+	}
+
+	entries, err := ReadJournal(dir, j.RunID())
+	if err != nil {
+		t.Fatalf("Got error: %v - expected error: nil", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Got entries: %v - expected entries: 1", len(entries))
+	}
+	expectedBody := `{"hashFunction":"REDACTED","password":"REDACTED","suspended":true}`
+	if string(entries[0].Body) != expectedBody {
+		t.Errorf("Got body: %v - expected body: %v", string(entries[0].Body), expectedBody)
+	}
+	if len(entries[0].Before) == 0 {
+		t.Error("Got before: empty - expected before: mickey.mouse@disney.com")
+	}
+}
+
+func TestJournalRuns(t *testing.T) {
+	Logger = tsts.GetLogger()
+	dir := t.TempDir()
+
+	for _, command := range []string{"gmin delete user mickey.mouse@disney.com", "gmin create group villains@disney.com"} {
+		j, err := NewJournal(dir, command)
+		if err != nil {
+			t.Fatalf("Got error: %v - expected error: nil", err)
+		}
+		err = j.add(JournalEntry{Method: "DELETE", URL: "https://admin.googleapis.com/admin/directory/v1/users/x"})
+		if err != nil {
+			t.Fatalf("Got error: %v - expected error: nil", err)
+		}
+	}
+
+	runs, err := JournalRuns(dir)
+	if err != nil {
+		t.Fatalf("Got error: %v - expected error: nil", err)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("Got runs: %v - expected runs: 2", len(runs))
+	}
+	if runs[0].Command != "gmin create group villains@disney.com" {
+		t.Errorf("Got command: %v - expected command: gmin create group villains@disney.com", runs[0].Command)
+	}
+
+	entries, err := ReadJournal(dir, runs[1].RunID)
+	if err != nil {
+		t.Fatalf("Got error: %v - expected error: nil", err)
+	}
+	if len(entries) != 1 || entries[0].Command != "gmin delete user mickey.mouse@disney.com" {
+		t.Errorf("Got entries: %v - expected entries: 1 for gmin delete user mickey.mouse@disney.com", entries)
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package common
+
+import (
+	"net/http"
+	"sync"
+)
+
+// CallWaiter blocks until a rate limit allows another API call
+type CallWaiter interface {
+	Wait()
+}
+
+var (
+	callLimiter   CallWaiter
+	callLimiterMu sync.RWMutex
+)
+
+// limitTransport waits for the call limiter, if one is set, before sending each request
+type limitTransport struct {
+	base http.RoundTripper
+}
+
+// SetCallLimiter sets the rate limiter that every API call waits for, including retries. Calls are
+// not limited if limiter is nil.
+func SetCallLimiter(limiter CallWaiter) {
+	callLimiterMu.Lock()
+	defer callLimiterMu.Unlock()
+	callLimiter = limiter
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *limitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	callLimiterMu.RLock()
+	limiter := callLimiter
+	callLimiterMu.RUnlock()
+
+	if limiter != nil {
+		limiter.Wait()
+	}
+	return t.base.RoundTrip(req)
+}
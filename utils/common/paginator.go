@@ -0,0 +1,170 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+)
+
+// PAGEMAXRETRYTIME is the longest time spent retrying a page that has failed with a retryable error
+const PAGEMAXRETRYTIME time.Duration = 32 * time.Second
+
+var errFanOutStopped = errors.New("fan out stopped")
+
+// PageFunc gets the page of list results that starts at pageToken, where an empty pageToken means the
+// first page, and returns the page with the token of the page after it
+type PageFunc func(pageToken string) (interface{}, string, error)
+
+// PageHandler is called with each page of list results in the order that they are fetched
+type PageHandler func(page interface{}) error
+
+// Paginator fetches pages of list results one at a time and hands each page on as soon as it arrives
+// so that results never have to be held in memory all at once
+type Paginator struct {
+	// MaxPages is the number of pages to fetch, where zero means all of them
+	MaxPages int
+	// PageToken is the token of the first page to fetch and is used to resume an interrupted listing
+	PageToken string
+	// Progress is called with the token of the next page after each page has been handled
+	Progress func(nextPageToken string)
+}
+
+// Run fetches pages with fetch and passes them to handle until there are no more pages or MaxPages
+// have been fetched. Retryable API errors are retried with exponential backoff. Other errors stop the
+// run and, after the first page, say which page token to resume from.
+func (p *Paginator) Run(fetch PageFunc, handle PageHandler) error {
+	Logger.Debugw("starting Run()",
+		"maxPages", p.MaxPages,
+		"pageToken", p.PageToken)
+	defer Logger.Debug("finished Run()")
+
+	pageToken := p.PageToken
+
+	for pageNum := 1; ; pageNum++ {
+		page, nextToken, err := fetchPage(fetch, pageToken)
+		if err != nil {
+			if pageToken != "" && err != errFanOutStopped {
+				err = fmt.Errorf(gmess.ERR_PAGEFAILED, err, pageToken)
+			}
+			Logger.Error(err)
+			return err
+		}
+
+		err = handle(page)
+		if err != nil {
+			return err
+		}
+
+		if p.Progress != nil && nextToken != "" {
+			p.Progress(nextToken)
+		}
+		if nextToken == "" || pageNum == p.MaxPages {
+			return nil
+		}
+		pageToken = nextToken
+	}
+}
+
+// FanOut runs a paginator for each shard, such as a domain or orgunit, with up to workers shards being
+// listed at the same time. newFetch returns the PageFunc for a shard and pages are passed to handle
+// one at a time. The first error stops any shards that are still running and is returned.
+func FanOut(shards []string, workers int, newFetch func(shard string) PageFunc, handle PageHandler) error {
+	Logger.Debugw("starting FanOut()",
+		"shards", shards,
+		"workers", workers)
+	defer Logger.Debug("finished FanOut()")
+
+	var (
+		firstErr error
+		mu       sync.Mutex
+	)
+
+	stopped := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return firstErr != nil
+	}
+
+	DoConcurrently(len(shards), workers, func(idx int) {
+		fetch := newFetch(shards[idx])
+
+		pgntr := new(Paginator)
+		err := pgntr.Run(func(pageToken string) (interface{}, string, error) {
+			if stopped() {
+				return nil, "", errFanOutStopped
+			}
+			return fetch(pageToken)
+		}, func(page interface{}) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if firstErr != nil {
+				return errFanOutStopped
+			}
+			return handle(page)
+		})
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil && err != errFanOutStopped && firstErr == nil {
+			firstErr = err
+		}
+	})
+
+	return firstErr
+}
+
+func fetchPage(fetch PageFunc, pageToken string) (interface{}, string, error) {
+	var (
+		nextToken string
+		page      interface{}
+	)
+
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = PAGEMAXRETRYTIME
+
+	err := backoff.Retry(func() error {
+		var err error
+		page, nextToken, err = fetch(pageToken)
+		if err == nil {
+			return nil
+		}
+		if err == errFanOutStopped || !IsErrRetryable(err) {
+			return backoff.Permanent(err)
+		}
+		// Log the retries
+		Logger.Warnw(err.Error(),
+			"retrying", b.GetElapsedTime().String(),
+			"pageToken", pageToken)
+		return err
+	}, b)
+	if perr, ok := err.(*backoff.PermanentError); ok {
+		err = perr.Err
+	}
+	return page, nextToken, err
+}
@@ -0,0 +1,181 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+
+	tsts "github.com/plusworx/gmin/tests"
+	"google.golang.org/api/googleapi"
+)
+
+// testPages returns a PageFunc over numPages pages where each page is its own index and page tokens
+// are the index of the page as a string
+func testPages(numPages int, failAt int, failErr error) PageFunc {
+	return func(pageToken string) (interface{}, string, error) {
+		idx := 0
+		if pageToken != "" {
+			idx, _ = strconv.Atoi(pageToken)
+		}
+		if idx == failAt && failErr != nil {
+			return nil, "", failErr
+		}
+		nextToken := ""
+		if idx < numPages-1 {
+			nextToken = strconv.Itoa(idx + 1)
+		}
+		return idx, nextToken, nil
+	}
+}
+
+func TestPaginatorRun(t *testing.T) {
+	Logger = tsts.GetLogger()
+
+	cases := []struct {
+		expectedErr    string
+		expectedPages  []int
+		expectedTokens []string
+		failAt         int
+		failErr        error
+		maxPages       int
+		pageToken      string
+	}{
+		{
+			expectedPages:  []int{0, 1, 2},
+			expectedTokens: []string{"1", "2"},
+			failAt:         -1,
+		},
+		{
+			expectedPages:  []int{0, 1},
+			expectedTokens: []string{"1", "2"},
+			failAt:         -1,
+			maxPages:       2,
+		},
+		{
+			expectedPages:  []int{2},
+			expectedTokens: []string{},
+			failAt:         -1,
+			pageToken:      "2",
+		},
+		{
+			expectedErr:   "boom",
+			expectedPages: []int{},
+			failAt:        0,
+			failErr:       errors.New("boom"),
+		},
+		{
+			expectedErr:    "boom - resume with --page-token 1",
+			expectedPages:  []int{0},
+			expectedTokens: []string{"1"},
+			failAt:         1,
+			failErr:        errors.New("boom"),
+		},
+	}
+
+	for _, c := range cases {
+		pages := []int{}
+		tokens := []string{}
+
+		pgntr := Paginator{
+			MaxPages:  c.maxPages,
+			PageToken: c.pageToken,
+			Progress:  func(nextPageToken string) { tokens = append(tokens, nextPageToken) },
+		}
+		err := pgntr.Run(testPages(3, c.failAt, c.failErr), func(page interface{}) error {
+			pages = append(pages, page.(int))
+			return nil
+		})
+		if err != nil && err.Error() != c.expectedErr {
+			t.Errorf("Got error: %v - expected error: %v", err.Error(), c.expectedErr)
+		}
+		if err == nil && c.expectedErr != "" {
+			t.Errorf("Got no error - expected error: %v", c.expectedErr)
+		}
+		if !reflect.DeepEqual(pages, c.expectedPages) {
+			t.Errorf("Got pages: %v - expected pages: %v", pages, c.expectedPages)
+		}
+		if c.expectedTokens != nil && !reflect.DeepEqual(tokens, c.expectedTokens) {
+			t.Errorf("Got tokens: %v - expected tokens: %v", tokens, c.expectedTokens)
+		}
+	}
+}
+
+func TestPaginatorRetry(t *testing.T) {
+	Logger = tsts.GetLogger()
+
+	calls := 0
+	fetch := func(pageToken string) (interface{}, string, error) {
+		calls++
+		if calls == 1 {
+			return nil, "", &googleapi.Error{Code: 429, Body: "rateLimitExceeded"}
+		}
+		return calls, "", nil
+	}
+
+	var got interface{}
+	pgntr := new(Paginator)
+	err := pgntr.Run(fetch, func(page interface{}) error {
+		got = page
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Got error: %v - expected no error", err)
+	}
+	if calls != 2 || got != 2 {
+		t.Errorf("Got calls: %v page: %v - expected calls: 2 page: 2", calls, got)
+	}
+}
+
+func TestFanOut(t *testing.T) {
+	Logger = tsts.GetLogger()
+
+	shards := []string{"a", "b", "c"}
+
+	total := 0
+	err := FanOut(shards, 2, func(shard string) PageFunc {
+		return testPages(4, -1, nil)
+	}, func(page interface{}) error {
+		total++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Got error: %v - expected no error", err)
+	}
+	if total != 12 {
+		t.Errorf("Got pages: %v - expected pages: 12", total)
+	}
+
+	err = FanOut(shards, 2, func(shard string) PageFunc {
+		if shard == "b" {
+			return testPages(4, 0, fmt.Errorf("shard %v failed", shard))
+		}
+		return testPages(4, -1, nil)
+	}, func(page interface{}) error { return nil })
+	if err == nil || err.Error() != "shard b failed" {
+		t.Errorf("Got error: %v - expected error: shard b failed", err)
+	}
+}
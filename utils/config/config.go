@@ -24,6 +24,7 @@ package config
 
 import (
 	"fmt"
+	"strings"
 
 	gmess "github.com/plusworx/gmin/utils/gminmessages"
 	"github.com/spf13/viper"
@@ -35,18 +36,30 @@ const (
 	CONFIGADMIN string = "administrator"
 	// CONFIGCUSTID is config file customer id variable name
 	CONFIGCUSTID string = "customerid"
+	// CONFIGCREDFILE is config file credential file variable name
+	CONFIGCREDFILE string = "credentialfile"
 	// CONFIGCREDPATH is config file credential path variable name
 	CONFIGCREDPATH string = "credentialpath"
+	// CONFIGDOMAIN is config file default domain variable name
+	CONFIGDOMAIN string = "domain"
+	// CONFIGENDPOINT is config file API endpoint override variable name
+	CONFIGENDPOINT string = "endpoint"
 	// CONFIGFILENAME is configuration file name
 	CONFIGFILENAME string = ".gmin.yaml"
 	// CONFIGFILEPREFIX is name of gmin config file without the .yaml suffix
 	CONFIGFILEPREFIX string = ".gmin"
+	// CONFIGHASHER is config file password hasher variable name
+	CONFIGHASHER string = "hasher"
 	// CONFIGLOGPATH is config file log path variable name
 	CONFIGLOGPATH string = "logpath"
 	// CONFIGLOGROTATIONCOUNT is config file log rotation count variable name
 	CONFIGLOGROTATIONCOUNT string = "logrotationcount"
 	// CONFIGLOGROTATIONTIME is config file log rotation time variable name
 	CONFIGLOGROTATIONTIME string = "logrotationtime"
+	// CONFIGPROFILE is config file default profile variable name
+	CONFIGPROFILE string = "profile"
+	// CONFIGPROFILES is config file profiles variable name
+	CONFIGPROFILES string = "profiles"
 	// CREDENTIALFILE service account credentials file name
 	CREDENTIALFILE string = "gmin_credentials"
 	// DEFAULTCUSTID is default customer id value
@@ -63,29 +76,104 @@ const (
 	ENVVARCREDPATH string = "_CREDENTIALPATH"
 	// ENVVARCUSTID is gmin custormer id environment variable suffix
 	ENVVARCUSTID string = "_CUSTOMERID"
+	// ENVVARENDPOINT is gmin API endpoint override environment variable suffix
+	ENVVARENDPOINT string = "_ENDPOINT"
+	// ENVVARHASHER is gmin password hasher environment variable suffix
+	ENVVARHASHER string = "_HASHER"
 	// ENVVARLOGPATH is gmin log path environment variable suffix
 	ENVVARLOGPATH string = "_LOGPATH"
 	// ENVVARLOGROTATIONCOUNT is number of log files that are kept
 	ENVVARLOGROTATIONCOUNT string = "_LOGROTATIONCOUNT"
 	// ENVVARLOGROTATIONTIME is amount of time (seconds) before a new log file is created
 	ENVVARLOGROTATIONTIME string = "_LOGROTATIONTIME"
+	// ENVVARPROFILE is gmin profile environment variable suffix
+	ENVVARPROFILE string = "_PROFILE"
 	// LOGFILE is default gmin log file name
 	LOGFILE string = "gmin_log.%Y%m%d%H%M%S"
 )
 
 // File holds configuration data
 type File struct {
-	Administrator    string `yaml:"administrator"`
-	CredentialPath   string `yaml:"credentialpath"`
-	CustomerID       string `yaml:"customerid"`
-	LogPath          string `yaml:"logpath"`
-	LogRotationCount uint   `yaml:"logrotationcount"`
-	LogRotationTime  int    `yaml:"logrotationtime"`
+	Administrator    string             `yaml:"administrator"`
+	CredentialPath   string             `yaml:"credentialpath"`
+	CustomerID       string             `yaml:"customerid"`
+	Endpoint         string             `yaml:"endpoint,omitempty"`
+	Hasher           string             `yaml:"hasher,omitempty"`
+	LogPath          string             `yaml:"logpath"`
+	LogRotationCount uint               `yaml:"logrotationcount"`
+	LogRotationTime  int                `yaml:"logrotationtime"`
+	Profile          string             `yaml:"profile,omitempty"`
+	Profiles         map[string]Profile `yaml:"profiles,omitempty"`
 }
 
+// Profile holds configuration data for a named Google Workspace tenant
+type Profile struct {
+	Administrator  string `yaml:"administrator,omitempty"`
+	CredentialFile string `yaml:"credentialfile,omitempty"`
+	CustomerID     string `yaml:"customerid,omitempty"`
+	Domain         string `yaml:"domain,omitempty"`
+	LogPath        string `yaml:"logpath,omitempty"`
+}
+
+var (
+	activeProfile     Profile
+	activeProfileName string
+)
+
 // Logger passed from logging package
 var Logger *zap.SugaredLogger
 
+// ActiveProfile returns the name of the profile in use or an empty string if there isn't one
+func ActiveProfile() string {
+	return activeProfileName
+}
+
+// GetString gets a string item from the active profile if it is set there, otherwise
+// from environment variables or config file
+func GetString(key string) string {
+	// Logging may not be set up yet so there is no logging here
+	val := activeProfile.value(key)
+	if val != "" {
+		return val
+	}
+	return viper.GetString(key)
+}
+
+// Profiles returns the profiles in config file
+func Profiles() (map[string]Profile, error) {
+	profiles := map[string]Profile{}
+
+	err := viper.UnmarshalKey(CONFIGPROFILES, &profiles)
+	if err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+// UseProfile makes the named profile active. An empty name means that no profile is used.
+func UseProfile(name string) error {
+	// Logging may not be set up yet so there is no logging here
+	activeProfile = Profile{}
+	activeProfileName = ""
+
+	if name == "" {
+		return nil
+	}
+
+	profiles, err := Profiles()
+	if err != nil {
+		return err
+	}
+	profile, ok := profiles[strings.ToLower(name)]
+	if !ok {
+		return fmt.Errorf(gmess.ERR_PROFILENOTFOUND, name)
+	}
+
+	activeProfile = profile
+	activeProfileName = strings.ToLower(name)
+	return nil
+}
+
 // ReadConfigString gets a string item from config file
 func ReadConfigString(key string) (string, error) {
 	Logger.Debugw("starting ReadConfigString()",
@@ -94,11 +182,26 @@ func ReadConfigString(key string) (string, error) {
 
 	var err error
 
-	// Use viper directly here because logging may not be set up yet
-	str := viper.GetString(key)
+	str := GetString(key)
 	if str == "" {
 		err = fmt.Errorf(gmess.ERR_NOTFOUNDINCONFIG, key)
 		Logger.Error(err)
 	}
 	return str, err
 }
+
+func (p Profile) value(key string) string {
+	switch key {
+	case CONFIGADMIN:
+		return p.Administrator
+	case CONFIGCREDFILE:
+		return p.CredentialFile
+	case CONFIGCUSTID:
+		return p.CustomerID
+	case CONFIGDOMAIN:
+		return p.Domain
+	case CONFIGLOGPATH:
+		return p.LogPath
+	}
+	return ""
+}
@@ -0,0 +1,241 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package domains
+
+import (
+	"fmt"
+	"strings"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	admin "google.golang.org/api/admin/directory/v1"
+	"google.golang.org/api/googleapi"
+)
+
+const (
+	// ALIASLISTKEY is name of domain alias List call results attribute
+	ALIASLISTKEY string = "domainAliases"
+	// ENDFIELD is List call attribute string terminator
+	ENDFIELD string = ")"
+	// LISTKEY is name of domain List call results attribute
+	LISTKEY string = "domains"
+	// STARTALIASESFIELD is domain alias List call attribute string prefix
+	STARTALIASESFIELD string = "domainAliases("
+	// STARTDOMAINSFIELD is domain List call attribute string prefix
+	STARTDOMAINSFIELD string = "domains("
+)
+
+// DomainAliasAttrMap provides lowercase mappings to valid admin.DomainAlias attributes
+var DomainAliasAttrMap = map[string]string{
+	"creationtime":     "creationTime",
+	"domainaliasname":  "domainAliasName",
+	"etag":             "etag",
+	"kind":             "kind",
+	"parentdomainname": "parentDomainName",
+	"verified":         "verified",
+}
+
+// DomainAttrMap provides lowercase mappings to valid admin.Domains attributes
+var DomainAttrMap = map[string]string{
+	"creationtime":  "creationTime",
+	"domainaliases": "domainAliases",
+	"domainname":    "domainName",
+	"etag":          "etag",
+	"isprimary":     "isPrimary",
+	"kind":          "kind",
+	"verified":      "verified",
+}
+
+var domainAliasAttrs = []string{
+	"creationTime",
+	"domainAliasName",
+	"etag",
+	"kind",
+	"parentDomainName",
+	"verified",
+}
+
+var domainAttrs = []string{
+	"creationTime",
+	"domainAliases",
+	"domainName",
+	"etag",
+	"isPrimary",
+	"kind",
+	"verified",
+}
+
+// AddFields adds fields to be returned from admin calls
+func AddFields(callObj interface{}, attrs string) interface{} {
+	lg.Debugw("starting AddFields()",
+		"attrs", attrs)
+	defer lg.Debug("finished AddFields()")
+
+	var fields googleapi.Field = googleapi.Field(attrs)
+
+	switch callObj.(type) {
+	case *admin.DomainAliasesGetCall:
+		var newDAGC *admin.DomainAliasesGetCall
+		dagc := callObj.(*admin.DomainAliasesGetCall)
+		newDAGC = dagc.Fields(fields)
+
+		return newDAGC
+	case *admin.DomainAliasesListCall:
+		var newDALC *admin.DomainAliasesListCall
+		dalc := callObj.(*admin.DomainAliasesListCall)
+		newDALC = dalc.Fields(fields)
+
+		return newDALC
+	case *admin.DomainsGetCall:
+		var newDGC *admin.DomainsGetCall
+		dgc := callObj.(*admin.DomainsGetCall)
+		newDGC = dgc.Fields(fields)
+
+		return newDGC
+	case *admin.DomainsListCall:
+		var newDLC *admin.DomainsListCall
+		dlc := callObj.(*admin.DomainsListCall)
+		newDLC = dlc.Fields(fields)
+
+		return newDLC
+	}
+
+	return nil
+}
+
+// AddParentDomainName adds ParentDomainName to admin.DomainAliasesListCall
+func AddParentDomainName(dalc *admin.DomainAliasesListCall, parent string) *admin.DomainAliasesListCall {
+	lg.Debugw("starting AddParentDomainName()",
+		"parent", parent)
+	defer lg.Debug("finished AddParentDomainName()")
+
+	var newDALC *admin.DomainAliasesListCall
+
+	newDALC = dalc.ParentDomainName(parent)
+
+	return newDALC
+}
+
+// DoGet calls the .Do() function on the admin.DomainsGetCall
+func DoGet(dgc *admin.DomainsGetCall) (*admin.Domains, error) {
+	lg.Debug("starting DoGet()")
+	defer lg.Debug("finished DoGet()")
+
+	domain, err := dgc.Do()
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	return domain, nil
+}
+
+// DoGetAlias calls the .Do() function on the admin.DomainAliasesGetCall
+func DoGetAlias(dagc *admin.DomainAliasesGetCall) (*admin.DomainAlias, error) {
+	lg.Debug("starting DoGetAlias()")
+	defer lg.Debug("finished DoGetAlias()")
+
+	alias, err := dagc.Do()
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	return alias, nil
+}
+
+// DoList calls the .Do() function on the admin.DomainsListCall
+func DoList(dlc *admin.DomainsListCall) (*admin.Domains2, error) {
+	lg.Debug("starting DoList()")
+	defer lg.Debug("finished DoList()")
+
+	domains, err := dlc.Do()
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	return domains, nil
+}
+
+// DoListAliases calls the .Do() function on the admin.DomainAliasesListCall
+func DoListAliases(dalc *admin.DomainAliasesListCall) (*admin.DomainAliases, error) {
+	lg.Debug("starting DoListAliases()")
+	defer lg.Debug("finished DoListAliases()")
+
+	aliases, err := dalc.Do()
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	return aliases, nil
+}
+
+// ShowAliasAttrs displays requested domain alias attributes
+func ShowAliasAttrs(filter string) {
+	lg.Debugw("starting ShowAliasAttrs()",
+		"filter", filter)
+	defer lg.Debug("finished ShowAliasAttrs()")
+
+	cmn.ShowAttrs(domainAliasAttrs, DomainAliasAttrMap, filter)
+}
+
+// ShowAttrs displays requested domain attributes
+func ShowAttrs(filter string) {
+	lg.Debugw("starting ShowAttrs()",
+		"filter", filter)
+	defer lg.Debug("finished ShowAttrs()")
+
+	cmn.ShowAttrs(domainAttrs, DomainAttrMap, filter)
+}
+
+// ValidateDomain checks that a domain is one of the customer's domains or domain aliases
+func ValidateDomain(ds *admin.Service, customerID string, domain string) error {
+	lg.Debugw("starting ValidateDomain()",
+		"domain", domain)
+	defer lg.Debug("finished ValidateDomain()")
+
+	dlc := ds.Domains.List(customerID).Fields("domains(domainName,domainAliases(domainAliasName))")
+
+	domains, err := DoList(dlc)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range domains.Domains {
+		if strings.EqualFold(d.DomainName, domain) {
+			return nil
+		}
+		for _, alias := range d.DomainAliases {
+			if strings.EqualFold(alias.DomainAliasName, domain) {
+				return nil
+			}
+		}
+	}
+
+	err = fmt.Errorf(gmess.ERR_DOMAINNOTFOUND, domain)
+	lg.Error(err)
+	return err
+}
@@ -23,84 +23,137 @@ THE SOFTWARE.
 package flagnames
 
 const (
-	FLG_ADMIN            string = "admin"
-	FLG_APPROVEMEM       string = "approve-member"
-	FLG_ARCHIVED         string = "archived"
-	FLG_ARCHIVEONLY      string = "archive-only"
-	FLG_ASSETID          string = "asset-id"
-	FLG_ASSISTCONTENT    string = "assist-content"
-	FLG_ATTRIBUTES       string = "attributes"
-	FLG_BANUSER          string = "ban-user"
-	FLG_BLOCKINHERIT     string = "block-inherit"
-	FLG_CHANGEPWD        string = "change-password"
-	FLG_COLLABINBOX      string = "collab-inbox"
-	FLG_COMPOSITE        string = "composite"
-	FLG_CONTACTOWNER     string = "contact-owner"
-	FLG_CREDPATH         string = "credential-path"
-	FLG_CUSTFLDMASK      string = "custom-field-mask"
-	FLG_CUSTOMERID       string = "customer-id"
-	FLG_CONFIG           string = "config"
-	FLG_COUNT            string = "count"
-	FLG_DELETED          string = "deleted"
-	FLG_DELIVERYSETTING  string = "delivery-setting"
-	FLG_DENYTEXT         string = "deny-text"
-	FLG_DESCRIPTION      string = "description"
-	FLG_DISCGROUP        string = "discover-group"
-	FLG_DOMAIN           string = "domain"
-	FLG_EMAIL            string = "email"
-	FLG_EXTMEMBER        string = "ext-member"
-	FLG_FILTER           string = "filter"
-	FLG_FIRSTNAME        string = "first-name"
-	FLG_FOOTERON         string = "footer-on"
-	FLG_FOOTERTEXT       string = "footer-text"
-	FLG_FORCE            string = "force"
-	FLG_FORMAT           string = "format"
-	FLG_GAL              string = "global-address-list"
-	FLG_INPUTFILE        string = "input-file"
-	FLG_JOIN             string = "join"
-	FLG_LANGUAGE         string = "language"
-	FLG_LASTNAME         string = "last-name"
-	FLG_LEAVE            string = "leave"
-	FLG_LOCATION         string = "location"
-	FLG_LOGLEVEL         string = "log-level"
-	FLG_LOGPATH          string = "log-path"
-	FLG_LOGROTATIONCOUNT string = "log-rotation-count"
-	FLG_LOGROTATIONTIME  string = "log-rotation-time"
-	FLG_MAXRESULTS       string = "max-results"
-	FLG_MESSAGEMOD       string = "message-mod"
-	FLG_MODCONTENT       string = "mod-content"
-	FLG_MODMEMBER        string = "mod-member"
-	FLG_NAME             string = "name"
-	FLG_NOTES            string = "notes"
-	FLG_NOTIFYDENY       string = "notify-deny"
-	FLG_ORDERBY          string = "order-by"
-	FLG_ORGUNIT          string = "orgunit"
-	FLG_ORGUNITPATH      string = "orgunit-path"
-	FLG_PAGES            string = "pages"
-	FLG_PARENTPATH       string = "parent-path"
-	FLG_PASSWORD         string = "password"
-	FLG_POSTASGROUP      string = "post-as-group"
-	FLG_POSTMESSAGE      string = "post-message"
-	FLG_PROJECTION       string = "projection"
-	FLG_QUERY            string = "query"
-	FLG_QUERYABLE        string = "queryable"
-	FLG_REASON           string = "reason"
-	FLG_RECEMAIL         string = "recovery-email"
-	FLG_RECPHONE         string = "recovery-phone"
-	FLG_REPLIESONTOP     string = "replies-on-top"
-	FLG_REPLYEMAIL       string = "reply-email"
-	FLG_REPLYTO          string = "reply-to"
-	FLG_ROLE             string = "role"
-	FLG_ROLES            string = "roles"
-	FLG_SEARCHTYPE       string = "type"
-	FLG_SHEETRANGE       string = "sheet-range"
-	FLG_SILENT           string = "silent"
-	FLG_SORTORDER        string = "sort-order"
-	FLG_SPAMMOD          string = "spam-mod"
-	FLG_SUSPENDED        string = "suspended"
-	FLG_USERKEY          string = "user-key"
-	FLG_VIEWGROUP        string = "view-group"
-	FLG_VIEWMEMSHIP      string = "view-membership"
-	FLG_VIEWTYPE         string = "view-type"
-	FLG_WEBPOSTING       string = "web-posting"
+	FLG_ADMIN             string = "admin"
+	FLG_ALLOWEMPTY        string = "allow-empty"
+	FLG_APPROVEMEM        string = "approve-member"
+	FLG_ARCHIVED          string = "archived"
+	FLG_ARCHIVEONLY       string = "archive-only"
+	FLG_ASSETID           string = "asset-id"
+	FLG_ASSISTCONTENT     string = "assist-content"
+	FLG_ATTRIBUTES        string = "attributes"
+	FLG_AUEMONTHS         string = "aue-months"
+	FLG_BANUSER           string = "ban-user"
+	FLG_BATCHACTION       string = "batch-action"
+	FLG_BATCHFILE         string = "batch-file"
+	FLG_BLOCKINHERIT      string = "block-inherit"
+	FLG_BUILDINGID        string = "building-id"
+	FLG_CAPACITY          string = "capacity"
+	FLG_CATEGORY          string = "category"
+	FLG_CHANGEPWD         string = "change-password"
+	FLG_CHECKS            string = "checks"
+	FLG_CLIENTID          string = "client-id"
+	FLG_COLLABINBOX       string = "collab-inbox"
+	FLG_COMPOSITE         string = "composite"
+	FLG_CONTACTOWNER      string = "contact-owner"
+	FLG_CREDFILE          string = "credential-file"
+	FLG_CREDPATH          string = "credential-path"
+	FLG_CUSTFLDMASK       string = "custom-field-mask"
+	FLG_CUSTOMERID        string = "customer-id"
+	FLG_CONFIG            string = "config"
+	FLG_COUNT             string = "count"
+	FLG_DATE              string = "date"
+	FLG_DELETED           string = "deleted"
+	FLG_DELIVERYSETTING   string = "delivery-setting"
+	FLG_DENYTEXT          string = "deny-text"
+	FLG_DESCRIPTION       string = "description"
+	FLG_DIR               string = "dir"
+	FLG_DISCGROUP         string = "discover-group"
+	FLG_DOMAIN            string = "domain"
+	FLG_DRYRUN            string = "dry-run"
+	FLG_EMAIL             string = "email"
+	FLG_ENDPOINT          string = "endpoint"
+	FLG_ENDTIME           string = "end-time"
+	FLG_EVENTNAME         string = "event-name"
+	FLG_EXTMEMBER         string = "ext-member"
+	FLG_FEATURES          string = "features"
+	FLG_FILE              string = "file"
+	FLG_FILTER            string = "filter"
+	FLG_FILTERS           string = "filters"
+	FLG_FIRSTNAME         string = "first-name"
+	FLG_FLOORNAME         string = "floor-name"
+	FLG_FLOORNAMES        string = "floor-names"
+	FLG_FLOORSECTION      string = "floor-section"
+	FLG_FOOTERON          string = "footer-on"
+	FLG_FOOTERTEXT        string = "footer-text"
+	FLG_FORCE             string = "force"
+	FLG_FORMAT            string = "format"
+	FLG_GAL               string = "global-address-list"
+	FLG_GROUP             string = "group"
+	FLG_HANDOFFFILE       string = "handoff-file"
+	FLG_HASHER            string = "hasher"
+	FLG_INACTIVEDAYS      string = "inactive-days"
+	FLG_INPUTFILE         string = "input-file"
+	FLG_JOIN              string = "join"
+	FLG_LANGUAGE          string = "language"
+	FLG_LASTNAME          string = "last-name"
+	FLG_LEAVE             string = "leave"
+	FLG_LOCATION          string = "location"
+	FLG_LOGLEVEL          string = "log-level"
+	FLG_LOGPATH           string = "log-path"
+	FLG_LOGROTATIONCOUNT  string = "log-rotation-count"
+	FLG_LOGROTATIONTIME   string = "log-rotation-time"
+	FLG_MAXRESULTS        string = "max-results"
+	FLG_MESSAGEMOD        string = "message-mod"
+	FLG_MODCONTENT        string = "mod-content"
+	FLG_MODMEMBER         string = "mod-member"
+	FLG_NAME              string = "name"
+	FLG_NEVERLOGGEDINDAYS string = "never-logged-in-days"
+	FLG_NOTES             string = "notes"
+	FLG_NOTIFYDENY        string = "notify-deny"
+	FLG_ORDERBY           string = "order-by"
+	FLG_ORGUNIT           string = "orgunit"
+	FLG_ORGUNITPATH       string = "orgunit-path"
+	FLG_OUTPUT            string = "output"
+	FLG_PAGES             string = "pages"
+	FLG_PAGETOKEN         string = "page-token"
+	FLG_PARALLEL          string = "parallel"
+	FLG_PARAMETERS        string = "parameters"
+	FLG_PARENTPATH        string = "parent-path"
+	FLG_PASSWORD          string = "password"
+	FLG_POSTASGROUP       string = "post-as-group"
+	FLG_POSTMESSAGE       string = "post-message"
+	FLG_PRIVILEGES        string = "privileges"
+	FLG_PROFILE           string = "profile"
+	FLG_PROTECTOWNERS     string = "protect-owners"
+	FLG_PRUNE             string = "prune"
+	FLG_PROJECTION        string = "projection"
+	FLG_PWDLENGTH         string = "password-length"
+	FLG_QPS               string = "qps"
+	FLG_QUERY             string = "query"
+	FLG_QUERYABLE         string = "queryable"
+	FLG_REASON            string = "reason"
+	FLG_RECEMAIL          string = "recovery-email"
+	FLG_RECPHONE          string = "recovery-phone"
+	FLG_RECURSIVE         string = "recursive"
+	FLG_REPLIESONTOP      string = "replies-on-top"
+	FLG_REPLYEMAIL        string = "reply-email"
+	FLG_REPLYTO           string = "reply-to"
+	FLG_RESOURCETYPE      string = "resource-type"
+	FLG_RESTART           string = "restart"
+	FLG_RESULTSDIR        string = "results-dir"
+	FLG_RESULTSFORMAT     string = "results-format"
+	FLG_ROLE              string = "role"
+	FLG_ROLES             string = "roles"
+	FLG_SEARCHTYPE        string = "type"
+	FLG_SHEETRANGE        string = "sheet-range"
+	FLG_SILENT            string = "silent"
+	FLG_SORTBY            string = "sort-by"
+	FLG_SORTORDER         string = "sort-order"
+	FLG_SPAMMOD           string = "spam-mod"
+	FLG_STARTTIME         string = "start-time"
+	FLG_SUSPENDED         string = "suspended"
+	FLG_SUSPENDEDDAYS     string = "suspended-days"
+	FLG_SYNCDAYS          string = "sync-days"
+	FLG_TEMPLATE          string = "template"
+	FLG_TEMPLATEFILE      string = "template-file"
+	FLG_TYPES             string = "types"
+	FLG_USERDESC          string = "user-description"
+	FLG_USERKEY           string = "user-key"
+	FLG_VIEWGROUP         string = "view-group"
+	FLG_VIEWMEMSHIP       string = "view-membership"
+	FLG_VIEWTYPE          string = "view-type"
+	FLG_WEBPOSTING        string = "web-posting"
+	FLG_WHERE             string = "where"
+	FLG_WORKERS           string = "workers"
+	FLG_WORKFLOW          string = "workflow"
 )
@@ -0,0 +1,479 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package formatters
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	gpars "github.com/plusworx/gmin/utils/gminparsers"
+	lg "github.com/plusworx/gmin/utils/logging"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// FMTCSV is comma separated values output format
+	FMTCSV string = "csv"
+	// FMTJSON is indented JSON output format
+	FMTJSON string = "json"
+	// FMTJSONL is JSON lines output format
+	FMTJSONL string = "jsonl"
+	// FMTTABLE is aligned text table output format
+	FMTTABLE string = "table"
+	// FMTTSV is tab separated values output format
+	FMTTSV string = "tsv"
+	// FMTYAML is YAML output format
+	FMTYAML string = "yaml"
+	// VALSEPARATOR separates values of flattened scalar arrays
+	VALSEPARATOR string = ";"
+)
+
+// Columns converts a formatted get or list fields string into column prefixes
+//
+// For example "primaryEmail,name(givenName,familyName),customSchemas/Emp/start" becomes
+// ["primaryEmail", "name.givenName", "name.familyName", "customSchemas.Emp.start"]
+func Columns(fields string) []string {
+	lg.Debugw("starting Columns()",
+		"fields", fields)
+	defer lg.Debug("finished Columns()")
+
+	cols := []string{}
+
+	for _, part := range splitTopLevel(fields) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		part = strings.ReplaceAll(part, "/", ".")
+
+		open := strings.Index(part, "(")
+		if open == -1 || !strings.HasSuffix(part, ")") {
+			cols = append(cols, part)
+			continue
+		}
+
+		prefix := part[:open]
+		for _, sub := range Columns(part[open+1 : len(part)-1]) {
+			cols = append(cols, prefix+"."+sub)
+		}
+	}
+	return cols
+}
+
+// FilterList removes the listKey results in obj that don't match where and sorts the rest by sortKeys
+//
+// obj must be a pointer to list results such as *admin.Users and either of where and sortKeys may be
+// nil. Results are filtered in their generic JSON form and then decoded back into obj.
+func FilterList(obj interface{}, listKey string, where gpars.WhereExpr, sortKeys []gpars.SortKey) error {
+	lg.Debugw("starting FilterList()",
+		"listKey", listKey)
+	defer lg.Debug("finished FilterList()")
+
+	if where == nil && len(sortKeys) == 0 {
+		return nil
+	}
+
+	generic, err := toGeneric(obj)
+	if err != nil {
+		return err
+	}
+
+	objMap, ok := generic.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	list, ok := objMap[listKey].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	kept := []interface{}{}
+	for _, rec := range list {
+		if where == nil || where.Match(rec) {
+			kept = append(kept, rec)
+		}
+	}
+	if len(sortKeys) > 0 {
+		gpars.SortRecords(kept, sortKeys)
+	}
+	objMap[listKey] = kept
+
+	jsonData, err := json.Marshal(objMap)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	// Clear obj first so that results that have been filtered out don't survive decoding
+	val := reflect.ValueOf(obj).Elem()
+	val.Set(reflect.Zero(val.Type()))
+
+	err = json.Unmarshal(jsonData, obj)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	return nil
+}
+
+func flatten(prefix string, val interface{}, out map[string]string) {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		for key, elem := range v {
+			flatten(joinKey(prefix, key), elem, out)
+		}
+	case []interface{}:
+		if isScalarSlice(v) {
+			strs := []string{}
+			for _, elem := range v {
+				strs = append(strs, scalarString(elem))
+			}
+			out[prefix] = strings.Join(strs, VALSEPARATOR)
+			return
+		}
+		for idx, elem := range v {
+			flatten(joinKey(prefix, strconv.Itoa(idx)), elem, out)
+		}
+	default:
+		out[prefix] = scalarString(v)
+	}
+}
+
+// Flatten converts a JSON compatible object into a map of dotted attribute paths and values
+func Flatten(obj interface{}) (map[string]string, error) {
+	lg.Debug("starting Flatten()")
+	defer lg.Debug("finished Flatten()")
+
+	generic, err := toGeneric(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	out := map[string]string{}
+	flatten("", generic, out)
+
+	return out, nil
+}
+
+func isScalarSlice(sl []interface{}) bool {
+	for _, elem := range sl {
+		switch elem.(type) {
+		case map[string]interface{}, []interface{}:
+			return false
+		}
+	}
+	return true
+}
+
+func joinKey(prefix string, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// matchesColumn reports whether a flattened key belongs to a requested column
+func matchesColumn(key string, col string) bool {
+	segs := []string{}
+	for _, seg := range strings.Split(key, ".") {
+		if _, err := strconv.Atoi(seg); err == nil {
+			continue
+		}
+		segs = append(segs, seg)
+	}
+	normKey := strings.ToLower(strings.Join(segs, "."))
+	lwrCol := strings.ToLower(col)
+
+	return normKey == lwrCol || strings.HasPrefix(normKey, lwrCol+".")
+}
+
+// Output writes get and list command results in the requested format
+//
+// listKey is the JSON name of the slice holding list results (for example "users") and should
+// be empty for single objects. fields is the formatted attribute string produced by
+// gminparsers.ParseOutputAttrs and may be empty.
+func Output(w io.Writer, format string, obj interface{}, listKey string, fields string) error {
+	lg.Debugw("starting Output()",
+		"format", format,
+		"listKey", listKey,
+		"fields", fields)
+	defer lg.Debug("finished Output()")
+
+	lwrFmt := strings.ToLower(format)
+
+	if lwrFmt == "" || lwrFmt == FMTJSON {
+		jsonData, err := json.MarshalIndent(obj, "", "    ")
+		if err != nil {
+			lg.Error(err)
+			return err
+		}
+		fmt.Fprintln(w, string(jsonData))
+		return nil
+	}
+
+	records, err := Records(obj, listKey)
+	if err != nil {
+		return err
+	}
+
+	switch lwrFmt {
+	case FMTCSV:
+		return writeDelimited(w, ',', records, fields)
+	case FMTJSONL:
+		return writeJSONL(w, records)
+	case FMTTABLE:
+		return writeTable(w, records, fields)
+	case FMTTSV:
+		return writeDelimited(w, '\t', records, fields)
+	case FMTYAML:
+		return writeYAML(w, records, listKey)
+	}
+
+	err = fmt.Errorf(gmess.ERR_INVALIDOUTPUTFORMAT, format)
+	lg.Error(err)
+	return err
+}
+
+// Records extracts individual objects from get or list results in generic form
+func Records(obj interface{}, listKey string) ([]interface{}, error) {
+	lg.Debugw("starting Records()",
+		"listKey", listKey)
+	defer lg.Debug("finished Records()")
+
+	generic, err := toGeneric(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	if listKey == "" {
+		return []interface{}{generic}, nil
+	}
+
+	objMap, ok := generic.(map[string]interface{})
+	if !ok {
+		return []interface{}{}, nil
+	}
+
+	list, ok := objMap[listKey].([]interface{})
+	if !ok {
+		return []interface{}{}, nil
+	}
+	return list, nil
+}
+
+// Rows flattens records and returns header and rows of string values
+func Rows(records []interface{}, fields string) ([]string, [][]string) {
+	lg.Debugw("starting Rows()",
+		"fields", fields)
+	defer lg.Debug("finished Rows()")
+
+	var (
+		flatRecs = []map[string]string{}
+		header   []string
+		keySet   = map[string]bool{}
+	)
+
+	for _, rec := range records {
+		flat := map[string]string{}
+		flatten("", rec, flat)
+		flatRecs = append(flatRecs, flat)
+		for key := range flat {
+			keySet[key] = true
+		}
+	}
+
+	allKeys := []string{}
+	for key := range keySet {
+		allKeys = append(allKeys, key)
+	}
+	sort.Strings(allKeys)
+
+	header = selectColumns(allKeys, fields)
+	// Without results the header is just the requested columns
+	if len(records) == 0 {
+		header = Columns(fields)
+	}
+
+	rows := [][]string{}
+	for _, flat := range flatRecs {
+		row := make([]string, len(header))
+		for idx, key := range header {
+			row[idx] = flat[key]
+		}
+		rows = append(rows, row)
+	}
+	return header, rows
+}
+
+// selectColumns returns the keys that match fields in field order or all keys if there are no fields
+func selectColumns(allKeys []string, fields string) []string {
+	cols := Columns(fields)
+	if len(cols) == 0 {
+		return allKeys
+	}
+
+	header := []string{}
+	used := map[string]bool{}
+	for _, col := range cols {
+		for _, key := range allKeys {
+			if !used[key] && matchesColumn(key, col) {
+				header = append(header, key)
+				used[key] = true
+			}
+		}
+	}
+	return header
+}
+
+func scalarString(val interface{}) string {
+	switch v := val.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// splitTopLevel splits fields string on commas that are not inside brackets
+func splitTopLevel(fields string) []string {
+	var (
+		depth int
+		parts []string
+		start int
+	)
+
+	for idx, ch := range fields {
+		switch ch {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, fields[start:idx])
+				start = idx + 1
+			}
+		}
+	}
+	parts = append(parts, fields[start:])
+	return parts
+}
+
+func toGeneric(obj interface{}) (interface{}, error) {
+	var generic interface{}
+
+	jsonData, err := json.Marshal(obj)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	err = json.Unmarshal(jsonData, &generic)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+	return generic, nil
+}
+
+func writeDelimited(w io.Writer, delim rune, records []interface{}, fields string) error {
+	header, rows := Rows(records, fields)
+
+	cw := csv.NewWriter(w)
+	cw.Comma = delim
+
+	err := cw.Write(header)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	err = cw.WriteAll(rows)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	return nil
+}
+
+func writeJSONL(w io.Writer, records []interface{}) error {
+	for _, rec := range records {
+		jsonData, err := json.Marshal(rec)
+		if err != nil {
+			lg.Error(err)
+			return err
+		}
+		fmt.Fprintln(w, string(jsonData))
+	}
+	return nil
+}
+
+func writeTable(w io.Writer, records []interface{}, fields string) error {
+	header, rows := Rows(records, fields)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+
+	err := tw.Flush()
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	return nil
+}
+
+func writeYAML(w io.Writer, records []interface{}, listKey string) error {
+	var out interface{} = records
+
+	if listKey == "" && len(records) == 1 {
+		out = records[0]
+	}
+
+	yamlData, err := yaml.Marshal(out)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	fmt.Fprint(w, string(yamlData))
+	return nil
+}
@@ -0,0 +1,226 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package formatters
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	tsts "github.com/plusworx/gmin/tests"
+	gpars "github.com/plusworx/gmin/utils/gminparsers"
+	lg "github.com/plusworx/gmin/utils/logging"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+func TestColumns(t *testing.T) {
+	cases := []struct {
+		expectedResult []string
+		fields         string
+	}{
+		{
+			expectedResult: []string{"primaryEmail"},
+			fields:         "primaryEmail",
+		},
+		{
+			expectedResult: []string{"primaryEmail", "name.givenName", "name.familyName"},
+			fields:         "primaryEmail,name(givenName,familyName)",
+		},
+		{
+			expectedResult: []string{"customSchemas.EmploymentData.startDate", "addresses.region"},
+			fields:         "customSchemas/EmploymentData/startDate,addresses(region)",
+		},
+		{
+			expectedResult: []string{},
+			fields:         "",
+		},
+	}
+
+	tsts.InitConfig()
+	lg.InitLogging("info")
+
+	for _, c := range cases {
+		output := Columns(c.fields)
+
+		if !reflect.DeepEqual(output, c.expectedResult) {
+			t.Errorf("Got result: %v - expected result: %v", output, c.expectedResult)
+		}
+	}
+}
+
+func TestOutput(t *testing.T) {
+	users := &admin.Users{
+		Users: []*admin.User{
+			{
+				Addresses:    []interface{}{map[string]interface{}{"type": "work", "region": "Kent"}},
+				Aliases:      []string{"fred@mycompany.org", "freddy@mycompany.org"},
+				Name:         &admin.UserName{FamilyName: "Bloggs", GivenName: "Fred"},
+				PrimaryEmail: "fred.bloggs@mycompany.org",
+			},
+			{
+				Name:         &admin.UserName{FamilyName: "Smith", GivenName: "Jane"},
+				PrimaryEmail: "jane.smith@mycompany.org",
+				Suspended:    true,
+			},
+		},
+	}
+
+	cases := []struct {
+		expectedErr    string
+		expectedResult string
+		fields         string
+		format         string
+		listKey        string
+		obj            interface{}
+	}{
+		{
+			expectedResult: "primaryEmail,name.givenName\nfred.bloggs@mycompany.org,Fred\njane.smith@mycompany.org,Jane\n",
+			fields:         "primaryEmail,name(givenName)",
+			format:         "csv",
+			listKey:        "users",
+			obj:            users,
+		},
+		{
+			expectedResult: "primaryEmail\taliases\nfred.bloggs@mycompany.org\tfred@mycompany.org;freddy@mycompany.org\njane.smith@mycompany.org\t\n",
+			fields:         "primaryEmail,aliases",
+			format:         "tsv",
+			listKey:        "users",
+			obj:            users,
+		},
+		{
+			expectedResult: "addresses.0.region,addresses.0.type\nKent,work\n,\n",
+			fields:         "addresses(region,type)",
+			format:         "CSV",
+			listKey:        "users",
+			obj:            users,
+		},
+		{
+			expectedResult: "{\"name\":{\"familyName\":\"Smith\",\"givenName\":\"Jane\"},\"primaryEmail\":\"jane.smith@mycompany.org\",\"suspended\":true}\n",
+			format:         "jsonl",
+			obj:            users.Users[1],
+		},
+		{
+			expectedResult: "name:\n  familyName: Smith\n  givenName: Jane\nprimaryEmail: jane.smith@mycompany.org\nsuspended: true\n",
+			format:         "yaml",
+			obj:            users.Users[1],
+		},
+		{
+			expectedErr: "invalid output format: xml",
+			format:      "xml",
+			obj:         users,
+		},
+	}
+
+	tsts.InitConfig()
+	lg.InitLogging("info")
+
+	for _, c := range cases {
+		var buf bytes.Buffer
+
+		err := Output(&buf, c.format, c.obj, c.listKey, c.fields)
+		if err != nil {
+			if err.Error() != c.expectedErr {
+				t.Errorf("Got error: %v - expected error: %v", err.Error(), c.expectedErr)
+			}
+			continue
+		}
+
+		if buf.String() != c.expectedResult {
+			t.Errorf("Got result: %q - expected result: %q", buf.String(), c.expectedResult)
+		}
+	}
+}
+
+func TestFilterList(t *testing.T) {
+	cases := []struct {
+		expectedResult []string
+		sortBy         string
+		where          string
+	}{
+		{
+			expectedResult: []string{"fred.bloggs@mycompany.org", "jane.smith@mycompany.org", "arthur.dent@mycompany.org"},
+		},
+		{
+			expectedResult: []string{"fred.bloggs@mycompany.org"},
+			where:          "suspended",
+		},
+		{
+			expectedResult: []string{"arthur.dent@mycompany.org", "jane.smith@mycompany.org"},
+			sortBy:         "primaryemail",
+			where:          "not suspended",
+		},
+		{
+			expectedResult: []string{},
+			where:          "orgUnitPath = '/Sales'",
+		},
+	}
+
+	tsts.InitConfig()
+	lg.InitLogging("info")
+
+	for _, c := range cases {
+		var (
+			sortKeys  []gpars.SortKey
+			whereExpr gpars.WhereExpr
+			err       error
+		)
+
+		users := &admin.Users{
+			Etag: "abc",
+			Users: []*admin.User{
+				{PrimaryEmail: "fred.bloggs@mycompany.org", Suspended: true},
+				{PrimaryEmail: "jane.smith@mycompany.org"},
+				{PrimaryEmail: "arthur.dent@mycompany.org"},
+			},
+		}
+
+		if c.where != "" {
+			whereExpr, err = gpars.ParseWhere(c.where)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		if c.sortBy != "" {
+			sortKeys, err = gpars.ParseSortBy(c.sortBy)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		err = FilterList(users, "users", whereExpr, sortKeys)
+		if err != nil {
+			t.Fatalf("Got error: %v - expected error: nil", err)
+		}
+
+		result := []string{}
+		for _, user := range users.Users {
+			result = append(result, user.PrimaryEmail)
+		}
+		if !reflect.DeepEqual(result, c.expectedResult) {
+			t.Errorf("Got result: %v - expected result: %v", result, c.expectedResult)
+		}
+		if users.Etag != "abc" {
+			t.Errorf("Got etag: %v - expected etag: abc", users.Etag)
+		}
+	}
+}
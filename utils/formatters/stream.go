@@ -0,0 +1,242 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package formatters
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+)
+
+// StreamFormats are the output formats that list results can be written in a page at a time
+var StreamFormats = []string{
+	FMTCSV,
+	FMTJSONL,
+	FMTTSV,
+}
+
+// Streamer writes pages of list results as they arrive instead of all at the end
+//
+// CSV and TSV headers are worked out from fields and the first page that has results. A later page
+// with an attribute that is not in the header is an error rather than being silently dropped.
+type Streamer struct {
+	cw      *csv.Writer
+	fields  string
+	format  string
+	header  []string
+	listKey string
+	w       io.Writer
+}
+
+// CanStream reports whether list results can be written a page at a time in format
+func CanStream(format string) bool {
+	return streamFormat(strings.ToLower(format))
+}
+
+// NewStreamer returns a Streamer that writes the listKey results of each page to w in format
+func NewStreamer(w io.Writer, format string, listKey string, fields string) (*Streamer, error) {
+	lg.Debugw("starting NewStreamer()",
+		"format", format,
+		"listKey", listKey,
+		"fields", fields)
+	defer lg.Debug("finished NewStreamer()")
+
+	lwrFmt := strings.ToLower(format)
+	if !streamFormat(lwrFmt) {
+		err := fmt.Errorf(gmess.ERR_INVALIDOUTPUTFORMAT, format)
+		lg.Error(err)
+		return nil, err
+	}
+	s := &Streamer{fields: fields, format: lwrFmt, listKey: listKey, w: w}
+	if lwrFmt != FMTJSONL {
+		s.cw = csv.NewWriter(w)
+		if lwrFmt == FMTTSV {
+			s.cw.Comma = '\t'
+		}
+	}
+	return s, nil
+}
+
+// Close writes the CSV or TSV header if no page had any results so that the output matches
+// output that is written all at once
+func (s *Streamer) Close() error {
+	lg.Debug("starting Close()")
+	defer lg.Debug("finished Close()")
+
+	if s.format == FMTJSONL || s.header != nil {
+		return nil
+	}
+
+	s.header, _ = Rows([]interface{}{}, s.fields)
+	err := s.writeRow(s.header)
+	if err != nil {
+		return err
+	}
+	return s.flush()
+}
+
+// WritePage writes the results in a page of list results
+func (s *Streamer) WritePage(page interface{}) error {
+	lg.Debug("starting WritePage()")
+	defer lg.Debug("finished WritePage()")
+
+	records, err := Records(page, s.listKey)
+	if err != nil {
+		return err
+	}
+
+	if s.format == FMTJSONL {
+		return writeJSONL(s.w, records)
+	}
+
+	if len(records) == 0 {
+		return nil
+	}
+
+	flatRecs := []map[string]string{}
+	for _, rec := range records {
+		flat := map[string]string{}
+		flatten("", rec, flat)
+		flatRecs = append(flatRecs, flat)
+	}
+
+	if s.header == nil {
+		s.header = streamHeader(flatRecs, s.fields)
+		err = s.writeRow(s.header)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = s.checkColumns(flatRecs)
+	if err != nil {
+		return err
+	}
+
+	for _, flat := range flatRecs {
+		row := make([]string, len(s.header))
+		for idx, key := range s.header {
+			row[idx] = flat[key]
+		}
+		err = s.writeRow(row)
+		if err != nil {
+			return err
+		}
+	}
+	return s.flush()
+}
+
+// checkColumns returns an error if a record has a wanted attribute that is not in the header
+func (s *Streamer) checkColumns(flatRecs []map[string]string) error {
+	inHeader := map[string]bool{}
+	for _, key := range s.header {
+		inHeader[key] = true
+	}
+
+	newKeys := []string{}
+	for _, flat := range flatRecs {
+		for key := range flat {
+			if !inHeader[key] {
+				newKeys = append(newKeys, key)
+				inHeader[key] = true
+			}
+		}
+	}
+	sort.Strings(newKeys)
+
+	newCols := selectColumns(newKeys, s.fields)
+	if len(newCols) == 0 {
+		return nil
+	}
+	err := fmt.Errorf(gmess.ERR_STREAMNEWCOLUMNS, s.format, strings.Join(newCols, ", "))
+	lg.Error(err)
+	return err
+}
+
+func (s *Streamer) flush() error {
+	s.cw.Flush()
+	err := s.cw.Error()
+	if err != nil {
+		lg.Error(err)
+	}
+	return err
+}
+
+func (s *Streamer) writeRow(row []string) error {
+	err := s.cw.Write(row)
+	if err != nil {
+		lg.Error(err)
+	}
+	return err
+}
+
+func streamFormat(format string) bool {
+	for _, strmFmt := range StreamFormats {
+		if format == strmFmt {
+			return true
+		}
+	}
+	return false
+}
+
+func streamHeader(flatRecs []map[string]string, fields string) []string {
+	var (
+		allKeys = []string{}
+		keySet  = map[string]bool{}
+	)
+
+	for _, flat := range flatRecs {
+		for key := range flat {
+			if !keySet[key] {
+				keySet[key] = true
+				allKeys = append(allKeys, key)
+			}
+		}
+	}
+	sort.Strings(allKeys)
+
+	// Requested columns that are missing from the first page are kept in case later pages have them
+	header := []string{}
+	used := map[string]bool{}
+	for _, col := range Columns(fields) {
+		colKeys := selectColumns(allKeys, col)
+		if len(colKeys) == 0 {
+			colKeys = []string{col}
+		}
+		for _, key := range colKeys {
+			if !used[key] {
+				header = append(header, key)
+				used[key] = true
+			}
+		}
+	}
+	if len(header) == 0 {
+		return allKeys
+	}
+	return header
+}
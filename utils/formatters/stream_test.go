@@ -0,0 +1,124 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package formatters
+
+import (
+	"bytes"
+	"testing"
+
+	tsts "github.com/plusworx/gmin/tests"
+	lg "github.com/plusworx/gmin/utils/logging"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+func TestStreamer(t *testing.T) {
+	pages := []interface{}{
+		&admin.Users{Users: []*admin.User{{PrimaryEmail: "fred.bloggs@mycompany.org", Suspended: true}}},
+		&admin.Users{},
+		&admin.Users{Users: []*admin.User{{PrimaryEmail: "jane.smith@mycompany.org", Aliases: []string{"jane@mycompany.org"}}}},
+	}
+
+	cases := []struct {
+		expectedErr    string
+		expectedResult string
+		fields         string
+		format         string
+		pages          []interface{}
+	}{
+		{
+			expectedErr:    "csv output stopped because later results have attributes that are not in the header: aliases - use jsonl output, --attributes or --sort-by",
+			expectedResult: "primaryEmail,suspended\nfred.bloggs@mycompany.org,true\n",
+			format:         "csv",
+		},
+		{
+			expectedResult: "primaryEmail,suspended\n",
+			fields:         "primaryEmail,suspended",
+			format:         "csv",
+			pages:          pages[1:2],
+		},
+		{
+			expectedResult: "\n",
+			format:         "tsv",
+			pages:          pages[1:2],
+		},
+		{
+			expectedResult: "primaryEmail,suspended\nfred.bloggs@mycompany.org,true\njane.smith@mycompany.org,\n",
+			fields:         "primaryEmail,suspended",
+			format:         "csv",
+		},
+		{
+			expectedResult: "aliases\tprimaryEmail\n\tfred.bloggs@mycompany.org\njane@mycompany.org\tjane.smith@mycompany.org\n",
+			fields:         "aliases,primaryEmail",
+			format:         "tsv",
+		},
+		{
+			expectedResult: "{\"primaryEmail\":\"fred.bloggs@mycompany.org\",\"suspended\":true}\n{\"aliases\":[\"jane@mycompany.org\"],\"primaryEmail\":\"jane.smith@mycompany.org\"}\n",
+			format:         "JSONL",
+		},
+		{
+			expectedErr: "invalid output format: table",
+			format:      "table",
+		},
+	}
+
+	tsts.InitConfig()
+	lg.InitLogging("info")
+
+	for _, c := range cases {
+		var buf bytes.Buffer
+
+		expCanStream := c.format != "table"
+		if got := CanStream(c.format); got != expCanStream {
+			t.Errorf("Got can stream: %v - expected can stream: %v", got, expCanStream)
+		}
+
+		if c.pages == nil {
+			c.pages = pages
+		}
+
+		strmr, err := NewStreamer(&buf, c.format, "users", c.fields)
+		if err == nil {
+			for _, page := range c.pages {
+				err = strmr.WritePage(page)
+				if err != nil {
+					break
+				}
+			}
+		}
+		if err == nil {
+			err = strmr.Close()
+		}
+
+		gotErr := ""
+		if err != nil {
+			gotErr = err.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("Got error: %v - expected error: %v", gotErr, c.expectedErr)
+		}
+
+		if buf.String() != c.expectedResult {
+			t.Errorf("Got result: %v - expected result: %v", buf.String(), c.expectedResult)
+		}
+	}
+}
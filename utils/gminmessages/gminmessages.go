@@ -28,49 +28,89 @@ const (
 	ERR_ADMINEMAILREQUIRED       string = "an email address is required - try again"
 	ERR_ATTRNOTRECOGNIZED        string = "%v attribute is not recognized"
 	ERR_ATTRSHOULDBE             string = "%v should be %v in attribute string"
-	ERR_BATCHCHROMEOSDEVICE      string = "error - %s - ChromeOS device: %s"
-	ERR_BATCHGROUP               string = "error - %s - group: %s"
-	ERR_BATCHGROUPSETTINGS       string = "error - %s - group settings for group: %s"
-	ERR_BATCHMEMBER              string = "error - %s - member: %s - group: %s"
-	ERR_BATCHMOBILEDEVICE        string = "error - %s - mobile device: %s"
+	ERR_BATCHBUILDING            string = "error - %w - building: %s"
+	ERR_BATCHCALRESOURCE         string = "error - %w - calendar resource: %s"
+	ERR_BATCHCHROMEOSDEVICE      string = "error - %w - ChromeOS device: %s"
+	ERR_BATCHFEATURE             string = "error - %w - feature: %s"
+	ERR_BATCHGROUP               string = "error - %w - group: %s"
+	ERR_BATCHGROUPSETTINGS       string = "error - %w - group settings for group: %s"
+	ERR_BATCHMEMBER              string = "error - %w - member: %s - group: %s"
+	ERR_BATCHMOBILEDEVICE        string = "error - %w - mobile device: %s"
 	ERR_BATCHMISSINGUSERDATA     string = "primaryEmail, givenName, familyName and password must all be provided"
-	ERR_BATCHOU                  string = "error - %s - orgunit: %s"
-	ERR_BATCHUSER                string = "error - %s - user: %s"
+	ERR_BATCHOU                  string = "error - %w - orgunit: %s"
+	ERR_BATCHPHOTO               string = "error - %w - photo: %s"
+	ERR_BATCHROLE                string = "error - %w - role: %s"
+	ERR_BATCHROLEASSIGNMENT      string = "error - %w - role assignment: %s"
+	ERR_BATCHROWSFAILED          string = "%d of %d batch rows failed"
+	ERR_BATCHUSER                string = "error - %w - user: %s"
 	ERR_CALLTYPENOTRECOGNIZED    string = "%v call type not recognized"
+	ERR_CANNOTUNDO               string = "cannot undo %v %v"
 	ERR_CREATEDIRECTORYSERVICE   string = "error - Creating Directory Service: %v"
 	ERR_CREATEGRPSETTINGSERVICE  string = "error - Creating Group Setting Service: %v"
+	ERR_CREATEREPORTSSERVICE     string = "error - Creating Reports Service: %v"
 	ERR_CREATESHEETSERVICE       string = "error - Creating Sheet Service: %v"
+	ERR_DIRANDINPUTFILE          string = "cannot provide both --dir and --input-file flags"
+	ERR_DIRNOTEMPTY              string = "directory is not empty: %v"
+	ERR_DOMAINNOTFOUND           string = "domain not found: %v"
+	ERR_DUPLICATEINSTATE         string = "%v is in state file more than once"
+	ERR_DUPLICATESYNCMEMBER      string = "member: %s is listed more than once"
 	ERR_EMPTYSTRING              string = "%v cannot be empty string"
+	ERR_EMPTYSYNCMEMBERS         string = "member list is empty - use --allow-empty to delete every member of group: %v"
 	ERR_FILENUMBERREQUIRED       string = "a file number is required - try again"
 	ERR_FLAGNOTRECOGNIZED        string = "%v flag is not recognized"
+	ERR_GROUPANDORGUNITFLAGS     string = "cannot provide both --group and --orgunit flags"
 	ERR_INVALIDACTIONTYPE        string = "invalid action type: %v"
 	ERR_INVALIDADMINEMAIL        string = "invalid admin email - try again"
+	ERR_INVALIDAPPLICATION       string = "invalid application: %v - valid applications are: %v"
+	ERR_INVALIDBATCHACTION       string = "invalid batch action: %v - valid actions are: %v"
 	ERR_INVALIDCONFIGPATH        string = "invalid config path - try again"
 	ERR_INVALIDCREDPATH          string = "invalid credentials path - try again"
 	ERR_INVALIDCUSTID            string = "invalid customer id - try again"
+	ERR_INVALIDDAYS              string = "%v must not be negative: %v"
 	ERR_INVALIDDELIVERYSETTING   string = "invalid delivery setting: %v"
 	ERR_INVALIDDEPROVISIONREASON string = "invalid deprovision reason: %v"
+	ERR_INVALIDDURATION          string = "invalid duration: %v"
 	ERR_INVALIDEMAILADDRESS      string = "invalid email address: %v"
 	ERR_INVALIDFILEFORMAT        string = "invalid file format: %v"
 	ERR_INVALIDFILENUMBER        string = "file number is invalid - try again"
+	ERR_INVALIDFLEETCHECK        string = "invalid fleet check: %v - valid checks are: %v"
+	ERR_INVALIDHASHER            string = "invalid password hasher: %v - valid hashers are: %v"
+	ERR_INVALIDHASHFUNCTION      string = "invalid hash function: %v - valid hash functions are: %v"
 	ERR_INVALIDJSONATTR          string = "attribute string is not valid JSON"
 	ERR_INVALIDJSONFILE          string = "input file is not valid JSON"
 	ERR_INVALIDLOGLEVEL          string = "invalid loglevel: %v"
 	ERR_INVALIDLOGPATH           string = "invalid log path - try again"
 	ERR_INVALIDLOGROTATIONCOUNT  string = "invalid log rotation count - try again"
 	ERR_INVALIDLOGROTATIONTIME   string = "invalid log rotation time - try again"
+	ERR_INVALIDNUMBER            string = "%v must be a number: %v"
 	ERR_INVALIDORDERBY           string = "invalid order by field: %v"
+	ERR_INVALIDORGUNITPATH       string = "invalid orgunit path: %v"
+	ERR_INVALIDOBJECTTYPE        string = "invalid object type: %v"
+	ERR_INVALIDOUTPUTFORMAT      string = "invalid output format: %v"
 	ERR_INVALIDPAGESARGUMENT     string = "pages argument must be 'all' or a number"
+	ERR_INVALIDPHOTO             string = "photo file must be a JPEG or PNG image: %v"
+	ERR_INVALIDPLACEHOLDER       string = "invalid placeholder %v in template: %v"
 	ERR_INVALIDPROJECTIONTYPE    string = "invalid projection type: %v"
+	ERR_INVALIDPWDHASH           string = "password is not a valid %v hash"
+	ERR_INVALIDPWDLENGTH         string = "password length %v must be between %v and %v"
+	ERR_INVALIDQPS               string = "qps must not be negative: %v"
 	ERR_INVALIDRECOVERYPHONE     string = "recovery phone number %v must start with '+'"
+	ERR_INVALIDREGEX             string = "invalid regular expression %v: %v"
+	ERR_INVALIDREPORTDATE        string = "invalid report date: %v - must be in the format YYYY-MM-DD"
+	ERR_INVALIDREPORTTIME        string = "invalid report time: %v - must be an RFC 3339 time or a duration before now like 7d"
 	ERR_INVALIDROLE              string = "invalid role: %v"
 	ERR_INVALIDSCHEMACOMPATTR    string = "invalid schema composite attribute: %v"
 	ERR_INVALIDSEARCHTYPE        string = "invalid search type: %v"
+	ERR_INVALIDSORTBY            string = "invalid sort by attribute: %v"
+	ERR_INVALIDSTALECHECK        string = "invalid stale user check: %v - valid checks are: %v"
 	ERR_INVALIDSTRING            string = "invalid string for %v supplied: %v"
 	ERR_INVALIDVIEWTYPE          string = "invalid view type: %v"
+	ERR_INVALIDWORKERS           string = "workers must be at least 1: %v"
+	ERR_JOURNALWRITE             string = "change could not be written to journal"
 	ERR_JWTCONFIGFROMJSON        string = "error - JWTConfigFromJSON: %v"
 	ERR_MAX2ARGSEXCEEDED         string = "exceeded maximum 2 arguments"
 	ERR_MAX3ARGSEXCEEDED         string = "exceeded maximum 3 arguments"
+	ERR_MISPLACEDDURATION        string = "duration %v must be added to or subtracted from a time in where expression"
 	ERR_MISSINGUSERDATA          string = "firstname, lastname and password must all be provided"
 	ERR_MUSTBENUMBER             string = "value entered must be a number - try again"
 	ERR_NOCOMPOSITEATTRS         string = "%v does not have any composite attributes"
@@ -85,41 +125,102 @@ const (
 	ERR_NOJSONOUKEY              string = "ouKey must be included in the JSON input string"
 	ERR_NOJSONUSERKEY            string = "userKey must be included in the JSON input string"
 	ERR_NOMEMBEREMAILADDRESS     string = "member email address must be provided"
+	ERR_NOMOVEORGUNIT            string = "must provide an orgunit to move devices to"
+	ERR_NOPARENTORGUNIT          string = "parent orgunit is not in state file or tenant: %v"
 	ERR_NONAMEOROUPATH           string = "name and parentOrgUnitPath must be provided"
+	ERR_NOPHOTOFILES             string = "no photo files found in directory: %v"
+	ERR_NOPRIMARYEMAIL           string = "primaryEmail must be given when template has no primaryEmail pattern"
+	ERR_NOREPORTDATE             string = "must provide a report date"
+	ERR_NORESTORESOURCE          string = "must provide a snapshot directory (--dir) or list output file (--input-file)"
+	ERR_NOROLEORASSIGNEE         string = "assignedTo and roleKey must be provided"
 	ERR_NOQUERYABLEATTRS         string = "%v does not have any queryable attributes"
 	ERR_NOSHEETDATAFOUND         string = "no data found in sheet %s - range: %s"
 	ERR_NOSHEETRANGE             string = "sheet-range must be provided"
 	ERR_NOTCOMPOSITEATTR         string = "%v is not a composite attribute"
+	ERR_NOTEMPLATE               string = "template must be given by flag or input row"
+	ERR_NOTEMPLATES              string = "template file has no templates"
 	ERR_NOTFOUNDINCONFIG         string = "%v not found in config"
+	ERR_NOTLISTOUTPUT            string = "%v is not json output of list users, list groups or list orgunits"
+	ERR_NOWORKFLOWSTEPS          string = "workflow must have at least one step"
 	ERR_OBJECTNOTFOUND           string = "%v not found"
 	ERR_OBJECTNOTRECOGNIZED      string = " %v is not recognized"
+	ERR_OFFBOARDDRYRUNFAILED     string = "dry run of offboarding found %d failing step(s)"
+	ERR_OFFBOARDSTOPPED          string = "offboarding stopped at step %d (%s): %w - run the command again to resume"
+	ERR_PAGEFAILED               string = "%w - resume with --page-token %v"
+	ERR_PARALLELFLAG             string = "--parallel cannot be used with --%v"
 	ERR_PIPEINPUTFILECONFLICT    string = "cannot provide input file when piping in input"
+	ERR_PRIVILEGENOTFOUND        string = "privilege not found: %v"
+	ERR_PROFILENOTFOUND          string = "profile not found: %v"
 	ERR_PROJECTIONFLAGNOTCUSTOM  string = "--projection must be set to 'custom' in order to use custom field mask"
 	ERR_QUERYABLEFLAG1ARG        string = "only one argument is allowed with --queryable flag"
 	ERR_QUERYANDCOMPOSITEFLAGS   string = "cannot provide both --composite and --queryable flags"
 	ERR_QUERYANDDELETEDFLAGS     string = "cannot provide both --query and --deleted flags"
+	ERR_RECURSIVEFLAG            string = "--recursive cannot be used with --%v"
+	ERR_ROLENOTFOUND             string = "role not found: %v"
+	ERR_RUNNOTFOUND              string = "run not found in journal: %v"
+	ERR_SNAPSHOTINCOMPLETE       string = "snapshot in %v is incomplete and cannot be used: %v"
+	ERR_SNAPSHOTVERSION          string = "snapshot version %v is not supported - expected version %v"
+	ERR_STEPFAILED               string = "step %d of %d: %s - failed: %w"
+	ERR_STEPORGUNIT              string = "orgunit can only be given to move-orgunit steps: %v"
+	ERR_STREAMNEWCOLUMNS         string = "%v output stopped because later results have attributes that are not in the header: %v - use jsonl output, --attributes or --sort-by"
+	ERR_TEMPLATENOTFOUND         string = "template not found: %v"
 	ERR_TOOMANYARGSMAX1          string = "too many arguments, %v has maximum of 1"
 	ERR_TOOMANYARGSMAX2          string = "too many arguments, %v has maximum of 2"
 	ERR_UNEXPECTEDATTRCHAR       string = "unexpected character %v found in attribute string"
+	ERR_UNDOINCOMPLETE           string = "%d of %d changes could not be undone"
 	ERR_UNEXPECTEDQUERYCHAR      string = "unexpected character %v found in query string"
+	ERR_UNEXPECTEDWHERETOKEN     string = "unexpected %v found in where expression"
+	ERR_UNTERMINATEDSTRING       string = "unterminated string %v found in where expression"
 
 	// Infos
 
 	INFO_ADMINIS              string = "admin is %v"
+	INFO_ADMINREVOKED         string = "super admin status revoked for user: %s"
 	INFO_ADMINSET             string = "administrator set to: %v"
+	INFO_APPLYNOCHANGES       string = "no changes needed"
+	INFO_APPLYPLAN            string = "plan: %d to create, %d to update, %d to delete"
+	INFO_ASPDELETED           string = "app password: %v deleted for user: %s"
+	INFO_ASPSDELETED          string = "%d app passwords deleted for user: %s"
+	INFO_BATCHFAILEDROWS      string = "failed rows written to: %s"
+	INFO_BATCHFILEWRITTEN     string = "%v batch input file with %d entries written to: %v"
+	INFO_BATCHRESULTS         string = "batch results written to: %s"
+	INFO_BCODESGENERATED      string = "backup codes generated for user: %s"
+	INFO_BCODESINVALIDATED    string = "backup codes invalidated for user: %s"
+	INFO_BUILDINGCREATED      string = "building created: %s"
+	INFO_BUILDINGDELETED      string = "building deleted: %s"
+	INFO_BUILDINGUPDATED      string = "building updated: %s"
+	INFO_CALRESCREATED        string = "calendar resource created: %s"
+	INFO_CALRESDELETED        string = "calendar resource deleted: %s"
+	INFO_CALRESUPDATED        string = "calendar resource updated: %s"
 	INFO_CDEVACTIONPERFORMED  string = "%s successfully performed on ChromeOS device: %s"
 	INFO_CDEVMOVEPERFORMED    string = "ChromeOS device: %s moved to: %s"
 	INFO_CDEVUPDATED          string = "ChromeOS device updated: %s"
+	INFO_CHANGEUNDONE         string = "change undone: %s"
 	INFO_CONFIGFILENOTFOUND   string = "Config file not found"
 	INFO_CREDENTIALPATHSET    string = "service account credential path set to: %v"
 	INFO_CREDENTIALSSET       string = "credentials set using: %v"
 	INFO_CUSTOMERIDSET        string = "customer ID set to: %v"
+	INFO_DOMAINALIASCREATED   string = "domain alias: %s created for domain: %s"
+	INFO_DOMAINALIASDELETED   string = "domain alias deleted: %s"
+	INFO_DOMAINCREATED        string = "domain created: %s"
+	INFO_DOMAINDELETED        string = "domain deleted: %s"
+	INFO_DOMAINIS             string = "domain is %v"
+	INFO_DRYRUNCHANGE         string = "would %v %v"
+	INFO_DRYRUNSTATEUNKNOWN   string = "current state unknown"
+	INFO_ENDPOINTSET          string = "API endpoint set to: %v"
 	INFO_ENVVARSNOTFOUND      string = "No environment variables found"
+	INFO_EXPORTCOMPLETED      string = "export written to: %s"
+	INFO_EXPORTED             string = "%d %s exported"
+	INFO_FEATURECREATED       string = "feature created: %s"
+	INFO_FEATUREDELETED       string = "feature deleted: %s"
+	INFO_FEATURERENAMED       string = "feature: %s renamed to: %s"
 	INFO_GROUPCREATED         string = "group created: %s"
 	INFO_GROUPALIASCREATED    string = "group alias: %s created for group: %s"
 	INFO_GROUPALIASDELETED    string = "group alias: %s deleted for group: %s"
 	INFO_GROUPDELETED         string = "group deleted: %s"
 	INFO_GROUPSETTINGSCHANGED string = "group settings changed for group: %s"
+	INFO_HANDOFFWRITTEN       string = "credentials written to: %s"
+	INFO_HASHERSET            string = "password hasher set to: %v"
 	INFO_INITCANCELLED        string = "init command cancelled"
 	INFO_INITCOMPLETED        string = "init completed successfully"
 	INFO_GROUPUPDATED         string = "group updated: %s"
@@ -130,18 +231,40 @@ const (
 	INFO_MDEVDELETED          string = "mobile device deleted: %s"
 	INFO_MEMBERCREATED        string = "member: %s created in group: %s"
 	INFO_MEMBERDELETED        string = "member: %s deleted from group: %s"
+	INFO_MEMBERPROTECTED      string = "owner: %s of group: %s is protected and has not been changed"
 	INFO_MEMBERUPDATED        string = "member: %s updated in group: %s"
+	INFO_NEXTPAGETOKEN        string = "next page token: %v"
+	INFO_OFFBOARDED           string = "user offboarded: %s"
 	INFO_OUCREATED            string = "orgunit created: %s"
 	INFO_OUDELETED            string = "orgunit deleted: %s"
 	INFO_OUUPDATED            string = "orgunit updated: %s"
+	INFO_PHOTODELETED         string = "photo deleted for user: %s"
+	INFO_PHOTOSAVED           string = "photo for user: %s saved to: %s"
+	INFO_PHOTOUPDATED         string = "photo updated for user: %s"
+	INFO_PROFILEIS            string = "profile is %v"
+	INFO_PROFILESET           string = "profile set to: %v"
+	INFO_PROFILESNOTFOUND     string = "No profiles found"
+	INFO_RACREATED            string = "role assignment created: %s"
+	INFO_RADELETED            string = "role assignment deleted: %s"
+	INFO_ROLECREATED          string = "role created: %s"
+	INFO_ROLEDELETED          string = "role deleted: %s"
+	INFO_ROLEUPDATED          string = "role updated: %s"
+	INFO_RUNUNDONE            string = "run undone: %s"
 	INFO_SCHEMACREATED        string = "schema created: %s"
 	INFO_SCHEMADELETED        string = "schema deleted: %s"
 	INFO_SCHEMAUPDATED        string = "schema updated: %s"
 	INFO_SETCOMMANDCANCELLED  string = "set command cancelled"
+	INFO_STEPDONE             string = "step %d of %d: %s - done"
+	INFO_STEPSKIPPED          string = "step %d of %d: %s - already done"
+	INFO_TOKENDELETED         string = "token: %s deleted for user: %s"
+	INFO_TOKENSDELETED        string = "%d tokens deleted for user: %s"
 	INFO_USERCREATED          string = "user created: %s"
 	INFO_USERALIASCREATED     string = "user alias: %s created for user: %s"
 	INFO_USERALIASDELETED     string = "user alias: %s deleted for user: %s"
 	INFO_USERDELETED          string = "user deleted: %s"
+	INFO_USERMADEADMIN        string = "user made super admin: %s"
+	INFO_USERONBOARDED        string = "user onboarded: %s"
+	INFO_USERSIGNEDOUT        string = "user signed out: %s"
 	INFO_USERUPDATED          string = "user updated: %s"
 	INFO_USERUNDELETED        string = "user undeleted: %s"
 )
@@ -44,8 +44,12 @@ const (
 
 	// Literals
 
+	// DURATION is a length of time such as 90d
+	DURATION
 	//IDENT is field name
 	IDENT
+	// NUMBER is a number
+	NUMBER
 
 	// Misc characters
 
@@ -69,12 +73,16 @@ const (
 	GT
 	// LT is <
 	LT
+	// MINUS is -
+	MINUS
 	// OP is an operator
 	OP
 	// OPENBRACK is (
 	OPENBRACK
 	// OPENSQBRACK is [
 	OPENSQBRACK
+	// PLUS is +
+	PLUS
 	// SINGLEQUOTE is '
 	SINGLEQUOTE
 	// TILDE is ~
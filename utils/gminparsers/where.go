@@ -0,0 +1,995 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package gminparsers
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+)
+
+// whereNow returns the time that now refers to in where expressions
+var whereNow = time.Now
+
+// durationUnits are the lengths of time that duration units stand for
+var durationUnits = map[string]time.Duration{
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+}
+
+// timeLayouts are the layouts that strings are tried against when they are compared with times
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// whereOps are the comparison operators allowed in where expressions
+var whereOps = []string{"=", "==", "!=", "<", "<=", ">", ">=", "~", "!~"}
+
+// SortKey is an attribute path that list results are sorted by
+type SortKey struct {
+	Descending bool
+	Path       []string
+}
+
+// WhereExpr is a parsed where expression that is evaluated against list results
+type WhereExpr interface {
+	// Match reports whether a list result in generic JSON form satisfies the expression
+	Match(obj interface{}) bool
+}
+
+// WhereParser represents a parser of where expressions
+type WhereParser struct {
+	pos  int
+	toks []whereTok
+	ws   *WhereScanner
+}
+
+// WhereScanner represents a lexical scanner for where expressions
+type WhereScanner struct {
+	scanr *Scanner
+}
+
+type andExpr struct {
+	left  WhereExpr
+	right WhereExpr
+}
+
+type cmpExpr struct {
+	left  whereOperand
+	op    string
+	re    *regexp.Regexp
+	right whereOperand
+}
+
+type existsExpr struct {
+	path pathOperand
+}
+
+type literalOperand struct {
+	val interface{}
+}
+
+type notExpr struct {
+	expr WhereExpr
+}
+
+type orExpr struct {
+	left  WhereExpr
+	right WhereExpr
+}
+
+type pathOperand []string
+
+type shiftOperand struct {
+	base   whereOperand
+	offset time.Duration
+}
+
+type truthExpr struct {
+	path pathOperand
+}
+
+type whereOperand interface {
+	values(obj interface{}) []interface{}
+}
+
+type whereTok struct {
+	lit string
+	tok Token
+}
+
+func (e andExpr) Match(obj interface{}) bool {
+	return e.left.Match(obj) && e.right.Match(obj)
+}
+
+func (e cmpExpr) Match(obj interface{}) bool {
+	lVals := e.left.values(obj)
+	if len(lVals) == 0 {
+		lVals = []interface{}{nil}
+	}
+
+	switch e.op {
+	case "~", "!~":
+		matched := false
+		for _, lVal := range lVals {
+			str, ok := scalarStr(lVal)
+			if ok && e.re.MatchString(str) {
+				matched = true
+				break
+			}
+		}
+		return matched == (e.op == "~")
+	}
+
+	rVals := e.right.values(obj)
+	if len(rVals) == 0 {
+		rVals = []interface{}{nil}
+	}
+
+	if e.op == "!=" {
+		return !anyCompare(lVals, rVals, "=")
+	}
+	return anyCompare(lVals, rVals, e.op)
+}
+
+func (e existsExpr) Match(obj interface{}) bool {
+	for _, val := range e.path.values(obj) {
+		if val != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (o literalOperand) values(obj interface{}) []interface{} {
+	return []interface{}{o.val}
+}
+
+func (e notExpr) Match(obj interface{}) bool {
+	return !e.expr.Match(obj)
+}
+
+func (e orExpr) Match(obj interface{}) bool {
+	return e.left.Match(obj) || e.right.Match(obj)
+}
+
+func (o pathOperand) values(obj interface{}) []interface{} {
+	return resolvePath(obj, o)
+}
+
+func (o shiftOperand) values(obj interface{}) []interface{} {
+	vals := []interface{}{}
+	for _, val := range o.base.values(obj) {
+		tm, ok := toTime(val)
+		if ok {
+			vals = append(vals, tm.Add(o.offset))
+		}
+	}
+	return vals
+}
+
+func (e truthExpr) Match(obj interface{}) bool {
+	for _, val := range e.path.values(obj) {
+		switch v := val.(type) {
+		case nil:
+		case bool:
+			if v {
+				return true
+			}
+		case float64:
+			if v != 0 {
+				return true
+			}
+		case string:
+			if v != "" {
+				return true
+			}
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// Parse is the entry point for the WhereParser
+func (wp *WhereParser) Parse() (WhereExpr, error) {
+	lg.Debug("starting WhereParser Parse()")
+	defer lg.Debug("finished Parse()")
+
+	for {
+		tok, lit := wp.ws.Scan()
+		if tok == WS {
+			continue
+		}
+		if tok == ILLEGAL {
+			err := fmt.Errorf(gmess.ERR_UNEXPECTEDWHERETOKEN, lit)
+			if strings.HasPrefix(lit, "'") || strings.HasPrefix(lit, "\"") {
+				err = fmt.Errorf(gmess.ERR_UNTERMINATEDSTRING, lit)
+			}
+			lg.Error(err)
+			return nil, err
+		}
+		wp.toks = append(wp.toks, whereTok{lit: lit, tok: tok})
+		if tok == EOS {
+			break
+		}
+	}
+
+	expr, err := wp.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if wp.peek().tok != EOS {
+		return nil, wp.unexpected()
+	}
+	return expr, nil
+}
+
+func (wp *WhereParser) isKeyword(keyword string) bool {
+	tk := wp.peek()
+	return tk.tok == IDENT && strings.ToLower(tk.lit) == keyword
+}
+
+func (wp *WhereParser) next() whereTok {
+	tk := wp.toks[wp.pos]
+	if tk.tok != EOS {
+		wp.pos++
+	}
+	return tk
+}
+
+func (wp *WhereParser) parseAnd() (WhereExpr, error) {
+	left, err := wp.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for wp.isKeyword("and") {
+		wp.next()
+		right, err := wp.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (wp *WhereParser) parseNot() (WhereExpr, error) {
+	if wp.isKeyword("not") || (wp.peek().tok == OP && wp.peek().lit == "!") {
+		wp.next()
+		expr, err := wp.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{expr: expr}, nil
+	}
+	return wp.parsePrimary()
+}
+
+func (wp *WhereParser) parseOperand() (whereOperand, error) {
+	var operand whereOperand
+
+	tk := wp.peek()
+	switch tk.tok {
+	case DURATION:
+		err := fmt.Errorf(gmess.ERR_MISPLACEDDURATION, tk.lit)
+		lg.Error(err)
+		return nil, err
+	case IDENT:
+		wp.next()
+		switch strings.ToLower(tk.lit) {
+		case "false":
+			operand = literalOperand{val: false}
+		case "now":
+			if wp.peek().tok == OPENBRACK && wp.toks[wp.pos+1].tok == CLOSEBRACK {
+				wp.next()
+				wp.next()
+			}
+			operand = literalOperand{val: whereNow()}
+		case "null":
+			operand = literalOperand{val: nil}
+		case "true":
+			operand = literalOperand{val: true}
+		default:
+			operand = pathOperand(strings.Split(tk.lit, "."))
+		}
+	case MINUS:
+		if wp.toks[wp.pos+1].tok != NUMBER {
+			return nil, wp.unexpected()
+		}
+		wp.next()
+		num, _ := strconv.ParseFloat(wp.next().lit, 64)
+		operand = literalOperand{val: -num}
+	case NUMBER:
+		wp.next()
+		num, _ := strconv.ParseFloat(tk.lit, 64)
+		operand = literalOperand{val: num}
+	case VALUE:
+		wp.next()
+		operand = literalOperand{val: tk.lit}
+	default:
+		return nil, wp.unexpected()
+	}
+
+	// Durations can be added to or subtracted from anything that is a time
+	for wp.peek().tok == PLUS || wp.peek().tok == MINUS {
+		sign := wp.next()
+		if wp.peek().tok != DURATION {
+			return nil, wp.unexpected()
+		}
+		offset, err := ParseDuration(wp.next().lit)
+		if err != nil {
+			return nil, err
+		}
+		if sign.tok == MINUS {
+			offset = -offset
+		}
+		operand = shiftOperand{base: operand, offset: offset}
+	}
+	return operand, nil
+}
+
+func (wp *WhereParser) parseOr() (WhereExpr, error) {
+	left, err := wp.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for wp.isKeyword("or") {
+		wp.next()
+		right, err := wp.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (wp *WhereParser) parsePrimary() (WhereExpr, error) {
+	if wp.peek().tok == OPENBRACK {
+		wp.next()
+		expr, err := wp.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if wp.peek().tok != CLOSEBRACK {
+			return nil, wp.unexpected()
+		}
+		wp.next()
+		return expr, nil
+	}
+
+	if wp.isKeyword("exists") && wp.toks[wp.pos+1].tok == OPENBRACK {
+		wp.next()
+		wp.next()
+		tk := wp.peek()
+		if tk.tok != IDENT {
+			return nil, wp.unexpected()
+		}
+		wp.next()
+		if wp.peek().tok != CLOSEBRACK {
+			return nil, wp.unexpected()
+		}
+		wp.next()
+		return existsExpr{path: pathOperand(strings.Split(tk.lit, "."))}, nil
+	}
+
+	left, err := wp.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	if wp.peek().tok != OP || wp.peek().lit == "!" {
+		path, ok := left.(pathOperand)
+		if !ok {
+			return nil, wp.unexpected()
+		}
+		return truthExpr{path: path}, nil
+	}
+
+	op := wp.next().lit
+	if op == "==" {
+		op = "="
+	}
+
+	if op == "~" || op == "!~" {
+		tk := wp.peek()
+		if tk.tok != VALUE {
+			return nil, wp.unexpected()
+		}
+		wp.next()
+		re, err := regexp.Compile(tk.lit)
+		if err != nil {
+			err = fmt.Errorf(gmess.ERR_INVALIDREGEX, tk.lit, err)
+			lg.Error(err)
+			return nil, err
+		}
+		return cmpExpr{left: left, op: op, re: re}, nil
+	}
+
+	right, err := wp.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return cmpExpr{left: left, op: op, right: right}, nil
+}
+
+func (wp *WhereParser) peek() whereTok {
+	return wp.toks[wp.pos]
+}
+
+func (wp *WhereParser) unexpected() error {
+	lit := wp.peek().lit
+	if wp.peek().tok == EOS {
+		lit = "end of expression"
+	}
+	err := fmt.Errorf(gmess.ERR_UNEXPECTEDWHERETOKEN, lit)
+	lg.Error(err)
+	return err
+}
+
+// Scan returns the next token and literal value from WhereScanner
+func (ws *WhereScanner) Scan() (Token, string) {
+	lg.Debug("starting whereScanner Scan()")
+	defer lg.Debug("finished Scan()")
+
+	// Read the next rune
+	ch := ws.scanr.read()
+
+	if unicode.IsSpace(ch) {
+		ws.scanr.unread()
+		return ws.scanr.scanWhitespace()
+	}
+	if unicode.IsLetter(ch) || ch == underscore {
+		ws.scanr.unread()
+		return ws.scanPath()
+	}
+	if unicode.IsDigit(ch) {
+		ws.scanr.unread()
+		return ws.scanNumber()
+	}
+	if ch == '\'' || ch == '"' {
+		return ws.scanString(ch)
+	}
+	if isWhereOperator(ch) {
+		ws.scanr.unread()
+		return ws.scanOperator()
+	}
+
+	// Otherwise read the individual character
+	if ch == eos {
+		return EOS, ""
+	}
+	if ch == ')' {
+		return CLOSEBRACK, string(ch)
+	}
+	if ch == '-' {
+		return MINUS, string(ch)
+	}
+	if ch == '(' {
+		return OPENBRACK, string(ch)
+	}
+	if ch == '+' {
+		return PLUS, string(ch)
+	}
+
+	return ILLEGAL, string(ch)
+}
+
+// scanNumber consumes a number and, if it is followed by a duration unit, returns a duration
+func (ws *WhereScanner) scanNumber() (Token, string) {
+	lg.Debug("starting whereScanner scanNumber()")
+	defer lg.Debug("finished scanNumber()")
+
+	var (
+		buf  bytes.Buffer
+		ch   rune
+		unit bool
+	)
+
+	for {
+		ch = ws.scanr.read()
+		if ch == eos {
+			break
+		}
+		if unicode.IsLetter(ch) {
+			unit = true
+		} else if unit || (!unicode.IsDigit(ch) && ch != '.') {
+			ws.scanr.unread()
+			break
+		}
+		buf.WriteRune(ch)
+	}
+
+	if !unit {
+		if _, err := strconv.ParseFloat(buf.String(), 64); err != nil {
+			return ILLEGAL, buf.String()
+		}
+		return NUMBER, buf.String()
+	}
+	if _, err := ParseDuration(buf.String()); err != nil {
+		return ILLEGAL, buf.String()
+	}
+	return DURATION, buf.String()
+}
+
+// scanOperator consumes contiguous comparison operator runes
+func (ws *WhereScanner) scanOperator() (Token, string) {
+	lg.Debug("starting whereScanner scanOperator()")
+	defer lg.Debug("finished scanOperator()")
+
+	var (
+		buf bytes.Buffer
+		ch  rune
+	)
+
+	for {
+		ch = ws.scanr.read()
+		if ch == eos {
+			break
+		}
+		if !isWhereOperator(ch) {
+			ws.scanr.unread()
+			break
+		}
+		buf.WriteRune(ch)
+	}
+
+	op := buf.String()
+	if op == "!" {
+		return OP, op
+	}
+	for _, whereOp := range whereOps {
+		if op == whereOp {
+			return OP, op
+		}
+	}
+	return ILLEGAL, op
+}
+
+// scanPath consumes an attribute path made up of idents separated by dots
+func (ws *WhereScanner) scanPath() (Token, string) {
+	lg.Debug("starting whereScanner scanPath()")
+	defer lg.Debug("finished scanPath()")
+
+	var (
+		buf bytes.Buffer
+		ch  rune
+	)
+
+	for {
+		ch = ws.scanr.read()
+		if ch == eos {
+			break
+		}
+		if !unicode.IsLetter(ch) && !unicode.IsDigit(ch) && ch != underscore && ch != '.' {
+			ws.scanr.unread()
+			break
+		}
+		buf.WriteRune(ch)
+	}
+
+	path := buf.String()
+	if strings.HasSuffix(path, ".") || strings.Contains(path, "..") {
+		return ILLEGAL, path
+	}
+	return IDENT, path
+}
+
+// scanString consumes a quoted string where backslash escapes the next character
+func (ws *WhereScanner) scanString(quote rune) (Token, string) {
+	lg.Debug("starting whereScanner scanString()")
+	defer lg.Debug("finished scanString()")
+
+	var (
+		buf bytes.Buffer
+		ch  rune
+	)
+
+	for {
+		ch = ws.scanr.read()
+		if ch == eos {
+			return ILLEGAL, string(quote) + buf.String()
+		}
+		if ch == quote {
+			break
+		}
+		if ch == '\\' {
+			next := ws.scanr.read()
+			if next == eos {
+				return ILLEGAL, string(quote) + buf.String()
+			}
+			// Keep backslashes that are not escaping a quote so that regex escapes still work
+			if next != quote && next != '\\' {
+				buf.WriteRune(ch)
+			}
+			ch = next
+		}
+		buf.WriteRune(ch)
+	}
+	return VALUE, buf.String()
+}
+
+func anyCompare(lVals []interface{}, rVals []interface{}, op string) bool {
+	for _, lVal := range lVals {
+		for _, rVal := range rVals {
+			res, ok := compareValues(lVal, rVal)
+			if !ok {
+				continue
+			}
+			switch {
+			case op == "=" && res == 0,
+				op == "<" && res < 0,
+				op == "<=" && res <= 0,
+				op == ">" && res > 0,
+				op == ">=" && res >= 0:
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// compareValues compares two values from generic JSON objects or where expression literals and
+// returns -1, 0 or 1 along with whether or not the values could be compared
+//
+// Missing values compare as the zero value of the other value's type, strings that are both numbers
+// or both times compare as numbers or times and other strings are compared without regard to case.
+func compareValues(a interface{}, b interface{}) (int, bool) {
+	_, aTime := a.(time.Time)
+	_, bTime := b.(time.Time)
+	if aTime || bTime {
+		aTm, aOK := toTime(a)
+		bTm, bOK := toTime(b)
+		if !aOK || !bOK {
+			return 0, false
+		}
+		switch {
+		case aTm.Before(bTm):
+			return -1, true
+		case aTm.After(bTm):
+			return 1, true
+		}
+		return 0, true
+	}
+
+	_, aBool := a.(bool)
+	_, bBool := b.(bool)
+	if aBool || bBool {
+		aB, aOK := toBool(a)
+		bB, bOK := toBool(b)
+		if !aOK || !bOK {
+			return 0, false
+		}
+		switch {
+		case aB == bB:
+			return 0, true
+		case bB:
+			return -1, true
+		}
+		return 1, true
+	}
+
+	_, aNum := a.(float64)
+	_, bNum := b.(float64)
+	if aNum || bNum {
+		aF, aOK := toFloat(a)
+		bF, bOK := toFloat(b)
+		if !aOK || !bOK {
+			return 0, false
+		}
+		switch {
+		case aF < bF:
+			return -1, true
+		case aF > bF:
+			return 1, true
+		}
+		return 0, true
+	}
+
+	aStr, aOK := scalarStr(a)
+	bStr, bOK := scalarStr(b)
+	if !aOK || !bOK {
+		return 0, false
+	}
+	if aStr != "" && bStr != "" {
+		aF, aErr := strconv.ParseFloat(aStr, 64)
+		bF, bErr := strconv.ParseFloat(bStr, 64)
+		if aErr == nil && bErr == nil {
+			return compareValues(aF, bF)
+		}
+		aTm, aOK := toTime(aStr)
+		bTm, bOK := toTime(bStr)
+		if aOK && bOK {
+			return compareValues(aTm, bTm)
+		}
+	}
+	return strings.Compare(strings.ToLower(aStr), strings.ToLower(bStr)), true
+}
+
+// isWhereOperator checks to see whether or not rune is part of a where comparison operator
+func isWhereOperator(ch rune) bool {
+	return ch == '=' || ch == '!' || ch == '<' || ch == '>' || ch == '~'
+}
+
+// resolvePath returns the values found at path in a generic JSON object
+//
+// Attribute names are matched without regard to case and arrays along the path are searched element
+// by element, so that addresses.type gives the type of every address.
+func resolvePath(obj interface{}, path []string) []interface{} {
+	if len(path) == 0 {
+		if arr, ok := obj.([]interface{}); ok {
+			return arr
+		}
+		return []interface{}{obj}
+	}
+
+	switch v := obj.(type) {
+	case []interface{}:
+		vals := []interface{}{}
+		for _, elem := range v {
+			vals = append(vals, resolvePath(elem, path)...)
+		}
+		return vals
+	case map[string]interface{}:
+		elem, ok := v[path[0]]
+		if !ok {
+			for key, val := range v {
+				if strings.EqualFold(key, path[0]) {
+					elem = val
+					ok = true
+					break
+				}
+			}
+		}
+		if !ok {
+			return []interface{}{}
+		}
+		return resolvePath(elem, path[1:])
+	}
+	return []interface{}{}
+}
+
+func scalarStr(val interface{}) (string, bool) {
+	switch v := val.(type) {
+	case nil:
+		return "", true
+	case bool:
+		return strconv.FormatBool(v), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case string:
+		return v, true
+	case time.Time:
+		return v.Format(time.RFC3339), true
+	}
+	return "", false
+}
+
+func toBool(val interface{}) (bool, bool) {
+	switch v := val.(type) {
+	case nil:
+		return false, true
+	case bool:
+		return v, true
+	case string:
+		b, err := strconv.ParseBool(v)
+		return b, err == nil
+	}
+	return false, false
+}
+
+func toFloat(val interface{}) (float64, bool) {
+	switch v := val.(type) {
+	case nil:
+		return 0, true
+	case float64:
+		return v, true
+	case string:
+		// Google APIs send 64 bit integers as strings
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func toTime(val interface{}) (time.Time, bool) {
+	switch v := val.(type) {
+	case time.Time:
+		return v, true
+	case string:
+		if len(v) < 10 || v[4] != '-' {
+			return time.Time{}, false
+		}
+		for _, layout := range timeLayouts {
+			tm, err := time.Parse(layout, v)
+			if err == nil {
+				return tm, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// NewWhereParser returns a new instance of WhereParser
+func NewWhereParser(buf *bytes.Buffer) *WhereParser {
+	lg.Debug("starting NewWhereParser()")
+	defer lg.Debug("finished NewWhereParser()")
+
+	return &WhereParser{ws: NewWhereScanner(buf)}
+}
+
+// NewWhereScanner returns a new instance of WhereScanner
+func NewWhereScanner(buf *bytes.Buffer) *WhereScanner {
+	lg.Debug("starting NewWhereScanner()")
+	defer lg.Debug("finished NewWhereScanner()")
+
+	scanr := &Scanner{strbuf: buf}
+	return &WhereScanner{scanr: scanr}
+}
+
+// ParseDuration converts a number followed by s, m, h, d or w into a duration
+func ParseDuration(dur string) (time.Duration, error) {
+	lg.Debugw("starting ParseDuration()",
+		"dur", dur)
+	defer lg.Debug("finished ParseDuration()")
+
+	err := fmt.Errorf(gmess.ERR_INVALIDDURATION, dur)
+
+	if len(dur) < 2 {
+		lg.Error(err)
+		return 0, err
+	}
+
+	unit, ok := durationUnits[strings.ToLower(dur[len(dur)-1:])]
+	if !ok {
+		lg.Error(err)
+		return 0, err
+	}
+
+	num, pErr := strconv.ParseFloat(dur[:len(dur)-1], 64)
+	if pErr != nil {
+		lg.Error(err)
+		return 0, err
+	}
+	return time.Duration(num * float64(unit)), nil
+}
+
+// ParseSortBy validates a sort by string of attribute paths separated by ~, each of which can end
+// with :asc or :desc
+func ParseSortBy(sortBy string) ([]SortKey, error) {
+	lg.Debugw("starting ParseSortBy()",
+		"sortBy", sortBy)
+	defer lg.Debug("finished ParseSortBy()")
+
+	keys := []SortKey{}
+
+	for _, part := range strings.Split(sortBy, "~") {
+		var key SortKey
+
+		attr := strings.TrimSpace(part)
+		if idx := strings.LastIndex(attr, ":"); idx != -1 {
+			switch strings.ToLower(attr[idx+1:]) {
+			case "asc":
+			case "desc":
+				key.Descending = true
+			default:
+				err := fmt.Errorf(gmess.ERR_INVALIDSORTBY, part)
+				lg.Error(err)
+				return nil, err
+			}
+			attr = attr[:idx]
+		}
+
+		bb := bytes.NewBufferString(attr)
+		ws := NewWhereScanner(bb)
+		tok, lit := ws.Scan()
+		if eosTok, _ := ws.Scan(); tok != IDENT || eosTok != EOS {
+			err := fmt.Errorf(gmess.ERR_INVALIDSORTBY, part)
+			lg.Error(err)
+			return nil, err
+		}
+		key.Path = strings.Split(lit, ".")
+
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// ParseWhere validates a where expression and returns it in a form that can be evaluated
+func ParseWhere(where string) (WhereExpr, error) {
+	lg.Debugw("starting ParseWhere()",
+		"where", where)
+	defer lg.Debug("finished ParseWhere()")
+
+	if strings.TrimSpace(where) == "" {
+		err := fmt.Errorf(gmess.ERR_UNEXPECTEDWHERETOKEN, "end of expression")
+		lg.Error(err)
+		return nil, err
+	}
+
+	bb := bytes.NewBufferString(where)
+
+	p := NewWhereParser(bb)
+
+	return p.Parse()
+}
+
+// SortRecords sorts list results in generic JSON form by keys
+//
+// Results that are missing a key come before those that have it and results that compare equal keep
+// their original order.
+func SortRecords(records []interface{}, keys []SortKey) {
+	lg.Debugw("starting SortRecords()",
+		"keys", keys)
+	defer lg.Debug("finished SortRecords()")
+
+	sort.SliceStable(records, func(i, j int) bool {
+		for _, key := range keys {
+			res := compareSortValues(resolvePath(records[i], key.Path), resolvePath(records[j], key.Path))
+			if res == 0 {
+				continue
+			}
+			if key.Descending {
+				return res > 0
+			}
+			return res < 0
+		}
+		return false
+	})
+}
+
+func compareSortValues(aVals []interface{}, bVals []interface{}) int {
+	switch {
+	case len(aVals) == 0 && len(bVals) == 0:
+		return 0
+	case len(aVals) == 0:
+		return -1
+	case len(bVals) == 0:
+		return 1
+	}
+
+	res, ok := compareValues(aVals[0], bVals[0])
+	if !ok {
+		aStr, _ := scalarStr(aVals[0])
+		bStr, _ := scalarStr(bVals[0])
+		return strings.Compare(aStr, bStr)
+	}
+	return res
+}
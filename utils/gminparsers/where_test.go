@@ -0,0 +1,237 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package gminparsers
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	tsts "github.com/plusworx/gmin/tests"
+	lg "github.com/plusworx/gmin/utils/logging"
+)
+
+const whereTestUsers = `[
+	{
+		"primaryEmail": "mickey.mouse@disney.com",
+		"isEnrolledIn2Sv": true,
+		"lastLoginTime": "2020-10-01T09:00:00.000Z",
+		"orgUnitPath": "/Sales",
+		"recoveryPhone": "+447700900123",
+		"addresses": [{"type": "work", "locality": "London"}, {"type": "home", "locality": "Paris"}],
+		"quotaBytes": "2048"
+	},
+	{
+		"primaryEmail": "minnie.mouse@disney.com",
+		"lastLoginTime": "2020-06-01T09:00:00.000Z",
+		"orgUnitPath": "/Sales",
+		"suspended": true,
+		"quotaBytes": "512"
+	},
+	{
+		"primaryEmail": "donald.duck@disney.com",
+		"lastLoginTime": "1970-01-01T00:00:00.000Z",
+		"orgUnitPath": "/Marketing",
+		"addresses": [{"type": "home", "locality": "London"}]
+	}
+]`
+
+func TestParseWhere(t *testing.T) {
+	var users []interface{}
+
+	err := json.Unmarshal([]byte(whereTestUsers), &users)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	whereNow = func() time.Time { return time.Date(2020, 10, 16, 0, 0, 0, 0, time.UTC) }
+	defer func() { whereNow = time.Now }()
+
+	cases := []struct {
+		expectedErr    string
+		expectedResult []int
+		where          string
+	}{
+		{
+			where:          "lastLoginTime < now - 90d",
+			expectedResult: []int{1, 2},
+		},
+		{
+			where:          "lastlogintime >= now() - 4w",
+			expectedResult: []int{0},
+		},
+		{
+			where:          "lastLoginTime > '2020-05-01' and lastLoginTime < '2020-07-01'",
+			expectedResult: []int{1},
+		},
+		{
+			where:          "not exists(recoveryPhone)",
+			expectedResult: []int{1, 2},
+		},
+		{
+			where:          "isEnrolledIn2Sv = false and orgUnitPath = '/sales'",
+			expectedResult: []int{1},
+		},
+		{
+			where:          "suspended or orgUnitPath != /Sales",
+			expectedErr:    "unexpected / found in where expression",
+			expectedResult: nil,
+		},
+		{
+			where:          "suspended or orgUnitPath != \"/Sales\"",
+			expectedResult: []int{1, 2},
+		},
+		{
+			where:          "addresses.type = 'work'",
+			expectedResult: []int{0},
+		},
+		{
+			where:          "addresses.locality = 'London' and not (addresses.type = 'work')",
+			expectedResult: []int{2},
+		},
+		{
+			where:          "primaryEmail ~ '^m.*\\.mouse@'",
+			expectedResult: []int{0, 1},
+		},
+		{
+			where:          "primaryEmail !~ 'mouse'",
+			expectedResult: []int{2},
+		},
+		{
+			where:          "quotaBytes > 1000",
+			expectedResult: []int{0},
+		},
+		{
+			where:          "exists(addresses) && suspended",
+			expectedErr:    "unexpected & found in where expression",
+			expectedResult: nil,
+		},
+		{
+			where:          "lastLoginTime < 90d",
+			expectedErr:    "duration 90d must be added to or subtracted from a time in where expression",
+			expectedResult: nil,
+		},
+		{
+			where:          "primaryEmail = 'mickey",
+			expectedErr:    "unterminated string 'mickey found in where expression",
+			expectedResult: nil,
+		},
+		{
+			where:          "(suspended",
+			expectedErr:    "unexpected end of expression found in where expression",
+			expectedResult: nil,
+		},
+		{
+			where:          "primaryEmail ~ '('",
+			expectedErr:    "invalid regular expression (: error parsing regexp: missing closing ): `(`",
+			expectedResult: nil,
+		},
+	}
+
+	tsts.InitConfig()
+	lg.InitLogging("info")
+
+	for _, c := range cases {
+		expr, err := ParseWhere(c.where)
+		if err != nil {
+			if err.Error() != c.expectedErr {
+				t.Errorf("%v - got error: %v - expected error: %v", c.where, err.Error(), c.expectedErr)
+			}
+			continue
+		}
+		if c.expectedErr != "" {
+			t.Errorf("%v - got no error - expected error: %v", c.where, c.expectedErr)
+			continue
+		}
+
+		result := []int{}
+		for idx, user := range users {
+			if expr.Match(user) {
+				result = append(result, idx)
+			}
+		}
+		if !reflect.DeepEqual(result, c.expectedResult) {
+			t.Errorf("%v - got result: %v - expected result: %v", c.where, result, c.expectedResult)
+		}
+	}
+}
+
+func TestSortRecords(t *testing.T) {
+	cases := []struct {
+		expectedErr    string
+		expectedResult []string
+		sortBy         string
+	}{
+		{
+			sortBy:         "primaryemail",
+			expectedResult: []string{"donald.duck@disney.com", "mickey.mouse@disney.com", "minnie.mouse@disney.com"},
+		},
+		{
+			sortBy:         "lastLoginTime:desc",
+			expectedResult: []string{"mickey.mouse@disney.com", "minnie.mouse@disney.com", "donald.duck@disney.com"},
+		},
+		{
+			sortBy:         "orgUnitPath:desc~quotaBytes",
+			expectedResult: []string{"minnie.mouse@disney.com", "mickey.mouse@disney.com", "donald.duck@disney.com"},
+		},
+		{
+			sortBy:      "orgUnitPath:down",
+			expectedErr: "invalid sort by attribute: orgUnitPath:down",
+		},
+		{
+			sortBy:      "name(givenName)",
+			expectedErr: "invalid sort by attribute: name(givenName)",
+		},
+	}
+
+	tsts.InitConfig()
+	lg.InitLogging("info")
+
+	for _, c := range cases {
+		var users []interface{}
+
+		err := json.Unmarshal([]byte(whereTestUsers), &users)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		keys, err := ParseSortBy(c.sortBy)
+		if err != nil {
+			if err.Error() != c.expectedErr {
+				t.Errorf("%v - got error: %v - expected error: %v", c.sortBy, err.Error(), c.expectedErr)
+			}
+			continue
+		}
+
+		SortRecords(users, keys)
+
+		result := []string{}
+		for _, user := range users {
+			result = append(result, user.(map[string]interface{})["primaryEmail"].(string))
+		}
+		if !reflect.DeepEqual(result, c.expectedResult) {
+			t.Errorf("%v - got result: %v - expected result: %v", c.sortBy, result, c.expectedResult)
+		}
+	}
+}
@@ -35,6 +35,8 @@ import (
 const (
 	// ENDFIELD is List call attribute string terminator
 	ENDFIELD string = ")"
+	// LISTKEY is name of List call results attribute
+	LISTKEY string = "aliases"
 	// STARTALIASESFIELD is List call attribute string prefix
 	STARTALIASESFIELD string = "aliases("
 )
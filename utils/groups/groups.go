@@ -39,6 +39,8 @@ const (
 	ENDFIELD string = ")"
 	// KEYNAME is name of key for processing
 	KEYNAME string = "groupKey"
+	// LISTKEY is name of List call results attribute
+	LISTKEY string = "groups"
 	// STARTGROUPSFIELD is List call attribute string prefix
 	STARTGROUPSFIELD string = "groups("
 )
@@ -23,6 +23,7 @@ THE SOFTWARE.
 package groups
 
 import (
+	"reflect"
 	"testing"
 
 	tsts "github.com/plusworx/gmin/tests"
@@ -290,3 +291,41 @@ func TestAddUserKey(t *testing.T) {
 		}
 	}
 }
+
+func TestEffectiveGroups(t *testing.T) {
+	parents := map[string][]*admin.Group{
+		"anne@example.com":     {{Email: "sales@example.com"}, {Email: "staff@example.com"}},
+		"sales@example.com":    {{Email: "staff@example.com"}},
+		"staff@example.com":    {{Email: "everyone@example.com"}},
+		"everyone@example.com": {{Email: "staff@example.com"}},
+	}
+	list := func(memberKey string) ([]*admin.Group, error) {
+		return parents[memberKey], nil
+	}
+
+	userGroups, err := EffectiveGroups("anne@example.com", list, 2)
+	if err != nil {
+		t.Fatalf("Got error: %v - expected error: <nil>", err)
+	}
+
+	got := map[string][]string{}
+	for _, grp := range userGroups.Groups {
+		got[grp.Email] = grp.Path
+		if grp.Direct != (len(grp.Path) == 1) {
+			t.Errorf("Got direct: %v for group: %v with path: %v", grp.Direct, grp.Email, grp.Path)
+		}
+	}
+	expected := map[string][]string{
+		"everyone@example.com": {"staff@example.com", "everyone@example.com"},
+		"sales@example.com":    {"sales@example.com"},
+		"staff@example.com":    {"staff@example.com"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Got paths: %v - expected paths: %v", got, expected)
+	}
+
+	expectedCycles := [][]string{{"staff@example.com", "everyone@example.com", "staff@example.com"}}
+	if !reflect.DeepEqual(userGroups.Cycles, expectedCycles) {
+		t.Errorf("Got cycles: %v - expected cycles: %v", userGroups.Cycles, expectedCycles)
+	}
+}
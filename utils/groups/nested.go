@@ -0,0 +1,117 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package groups
+
+import (
+	"strings"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	lg "github.com/plusworx/gmin/utils/logging"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+// GroupLister returns all of the groups that a user or group is a direct member of
+type GroupLister func(memberKey string) ([]*admin.Group, error)
+
+// UserGroup is a group that a user belongs to directly or through nested groups. Path holds the
+// email addresses of the groups from the group that the user belongs to directly up to this group.
+type UserGroup struct {
+	Direct bool     `json:"direct"`
+	Email  string   `json:"email"`
+	Id     string   `json:"id,omitempty"`
+	Name   string   `json:"name,omitempty"`
+	Path   []string `json:"path"`
+}
+
+// UserGroups holds the effective group memberships of a user. Each cycle is the path of group email
+// addresses that leads back to a group that is already in the path.
+type UserGroups struct {
+	Cycles [][]string   `json:"cycles,omitempty"`
+	Groups []*UserGroup `json:"groups"`
+}
+
+type memberOf struct {
+	key  string
+	path []string
+}
+
+// EffectiveGroups returns all of the groups that a user belongs to, either directly or because a group
+// that they belong to is a member of another group. Parent groups are looked up level by level with up
+// to workers lookups at a time. Each group is listed once with the shortest membership path.
+func EffectiveGroups(userKey string, list GroupLister, workers int) (*UserGroups, error) {
+	lg.Debugw("starting EffectiveGroups()",
+		"userKey", userKey,
+		"workers", workers)
+	defer lg.Debug("finished EffectiveGroups()")
+
+	var (
+		level   = []memberOf{{key: userKey}}
+		result  = &UserGroups{Groups: []*UserGroup{}}
+		visited = map[string]bool{}
+	)
+
+	for len(level) > 0 {
+		var (
+			errs    = make([]error, len(level))
+			next    []memberOf
+			results = make([][]*admin.Group, len(level))
+		)
+
+		cmn.DoConcurrently(len(level), workers, func(idx int) {
+			results[idx], errs[idx] = list(level[idx].key)
+		})
+
+		for idx, mbr := range level {
+			if errs[idx] != nil {
+				return nil, errs[idx]
+			}
+
+			for _, grp := range results[idx] {
+				key := strings.ToLower(grp.Email)
+				path := append(append([]string{}, mbr.path...), grp.Email)
+
+				if cmn.SliceContainsStrFold(mbr.path, key) {
+					lg.Warnw("group membership cycle found",
+						"cycle", path)
+					result.Cycles = append(result.Cycles, path)
+					continue
+				}
+				if visited[key] {
+					continue
+				}
+				visited[key] = true
+
+				result.Groups = append(result.Groups, &UserGroup{
+					Direct: len(mbr.path) == 0,
+					Email:  grp.Email,
+					Id:     grp.Id,
+					Name:   grp.Name,
+					Path:   path,
+				})
+				next = append(next, memberOf{key: grp.Email, path: path})
+			}
+		}
+		level = next
+	}
+	return result, nil
+}
@@ -48,7 +48,7 @@ func createLogger(loglevel string) (*zap.Logger, error) {
 	)
 
 	// Get logpath
-	logpath := viper.GetString(cfg.CONFIGLOGPATH)
+	logpath := cfg.GetString(cfg.CONFIGLOGPATH)
 	if logpath == "" {
 		// Look for environment variable
 		logpath = os.Getenv(cfg.ENVPREFIX + cfg.ENVVARLOGPATH)
@@ -39,6 +39,8 @@ const (
 	ENDFIELD string = ")"
 	// KEYNAME is name of key for processing
 	KEYNAME string = "memberKey"
+	// LISTKEY is name of List call results attribute
+	LISTKEY string = "members"
 	// STARTMEMBERSFIELD is List call attribute string prefix
 	STARTMEMBERSFIELD string = "members("
 )
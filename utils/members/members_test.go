@@ -23,6 +23,8 @@ THE SOFTWARE.
 package members
 
 import (
+	"errors"
+	"reflect"
 	"testing"
 
 	tsts "github.com/plusworx/gmin/tests"
@@ -190,3 +192,51 @@ func TestValidateRole(t *testing.T) {
 		}
 	}
 }
+
+func TestExpand(t *testing.T) {
+	groups := map[string][]*admin.Member{
+		"top@example.com": {
+			{Email: "mid@example.com", Role: "MEMBER", Type: MEMBERTYPEGROUP},
+			{Email: "anne@example.com", Role: "OWNER", Type: "USER"},
+		},
+		"mid@example.com": {
+			{Email: "anne@example.com", Role: "MEMBER", Type: "USER"},
+			{Email: "bob@example.com", Role: "MEMBER", Type: "USER"},
+			{Email: "top@example.com", Role: "MEMBER", Type: MEMBERTYPEGROUP},
+		},
+	}
+	list := func(groupKey string) ([]*admin.Member, error) {
+		if groupKey == "broken@example.com" {
+			return nil, errors.New("lookup failed")
+		}
+		return groups[groupKey], nil
+	}
+
+	nested, err := Expand("top@example.com", list, 2)
+	if err != nil {
+		t.Fatalf("Got error: %v - expected error: <nil>", err)
+	}
+
+	gotPaths := map[string][]string{}
+	for _, member := range nested.Members {
+		gotPaths[member.Email] = member.Path
+	}
+	expectedPaths := map[string][]string{
+		"anne@example.com": {"top@example.com"},
+		"bob@example.com":  {"top@example.com", "mid@example.com"},
+		"mid@example.com":  {"top@example.com"},
+	}
+	if !reflect.DeepEqual(gotPaths, expectedPaths) {
+		t.Errorf("Got paths: %v - expected paths: %v", gotPaths, expectedPaths)
+	}
+
+	expectedCycles := [][]string{{"top@example.com", "mid@example.com", "top@example.com"}}
+	if !reflect.DeepEqual(nested.Cycles, expectedCycles) {
+		t.Errorf("Got cycles: %v - expected cycles: %v", nested.Cycles, expectedCycles)
+	}
+
+	_, err = Expand("broken@example.com", list, 2)
+	if err == nil || err.Error() != "lookup failed" {
+		t.Errorf("Got error: %v - expected error: lookup failed", err)
+	}
+}
@@ -0,0 +1,130 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package members
+
+import (
+	"strings"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	lg "github.com/plusworx/gmin/utils/logging"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+// MEMBERTYPEGROUP is the type of members that are groups
+const MEMBERTYPEGROUP string = "GROUP"
+
+// MemberLister returns all of the members of a group
+type MemberLister func(groupKey string) ([]*admin.Member, error)
+
+// NestedMember is a member of a group or of one of its nested groups. Path holds the email addresses
+// of the groups from the expanded group down to the group that the member belongs to directly.
+type NestedMember struct {
+	Email  string   `json:"email,omitempty"`
+	Id     string   `json:"id,omitempty"`
+	Path   []string `json:"path"`
+	Role   string   `json:"role,omitempty"`
+	Status string   `json:"status,omitempty"`
+	Type   string   `json:"type,omitempty"`
+}
+
+// NestedMembers holds the members found by expanding nested groups. Each cycle is the path of group
+// email addresses that leads back to a group that is already in the path.
+type NestedMembers struct {
+	Cycles  [][]string      `json:"cycles,omitempty"`
+	Members []*NestedMember `json:"members"`
+}
+
+type nestedGroup struct {
+	key  string
+	path []string
+}
+
+// Expand returns the members of a group and of all of its nested groups. Groups are expanded level by
+// level with up to workers member lookups at a time. A member that belongs to more than one group is
+// listed once with the shortest membership path. Groups that are members of themselves, directly or
+// through other groups, are recorded as cycles and not expanded again.
+func Expand(groupKey string, list MemberLister, workers int) (*NestedMembers, error) {
+	lg.Debugw("starting Expand()",
+		"groupKey", groupKey,
+		"workers", workers)
+	defer lg.Debug("finished Expand()")
+
+	var (
+		level   = []nestedGroup{{key: groupKey, path: []string{groupKey}}}
+		nested  = &NestedMembers{Members: []*NestedMember{}}
+		seen    = map[string]bool{}
+		visited = map[string]bool{strings.ToLower(groupKey): true}
+	)
+
+	for len(level) > 0 {
+		var (
+			errs    = make([]error, len(level))
+			next    []nestedGroup
+			results = make([][]*admin.Member, len(level))
+		)
+
+		cmn.DoConcurrently(len(level), workers, func(idx int) {
+			results[idx], errs[idx] = list(level[idx].key)
+		})
+
+		for idx, grp := range level {
+			if errs[idx] != nil {
+				return nil, errs[idx]
+			}
+
+			for _, member := range results[idx] {
+				key := strings.ToLower(member.Email)
+				if key == "" {
+					key = member.Id
+				}
+
+				if member.Type == MEMBERTYPEGROUP && cmn.SliceContainsStrFold(grp.path, key) {
+					cycle := append(append([]string{}, grp.path...), member.Email)
+					lg.Warnw("group membership cycle found",
+						"cycle", cycle)
+					nested.Cycles = append(nested.Cycles, cycle)
+					continue
+				}
+
+				if !seen[key] {
+					seen[key] = true
+					nested.Members = append(nested.Members, &NestedMember{
+						Email:  member.Email,
+						Id:     member.Id,
+						Path:   grp.path,
+						Role:   member.Role,
+						Status: member.Status,
+						Type:   member.Type,
+					})
+				}
+
+				if member.Type == MEMBERTYPEGROUP && !visited[key] {
+					visited[key] = true
+					next = append(next, nestedGroup{key: member.Email, path: append(append([]string{}, grp.path...), member.Email)})
+				}
+			}
+		}
+		level = next
+	}
+	return nested, nil
+}
@@ -39,6 +39,8 @@ const (
 	ENDFIELD string = ")"
 	// KEYNAME is name of key for processing
 	KEYNAME string = "resourceId"
+	// LISTKEY is name of List call results attribute
+	LISTKEY string = "mobiledevices"
 	// STARTMOBDEVICESFIELD is List call attribute string prefix
 	STARTMOBDEVICESFIELD string = "mobiledevices("
 )
@@ -0,0 +1,313 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package offboard
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	admin "google.golang.org/api/admin/directory/v1"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// OFFBOARDDIR is the name of the offboarding progress directory in the log path
+	OFFBOARDDIR string = "offboard"
+	// STATUSDONE is the status of a step that completed successfully
+	STATUSDONE string = "done"
+	// STATUSFAILED is the status of a step that failed
+	STATUSFAILED string = "failed"
+)
+
+// Workflow step actions
+const (
+	ACTDELETEMOBDEVS string = "delete-mobile-devices"
+	ACTMOVEORGUNIT   string = "move-orgunit"
+	ACTREMOVEALIASES string = "remove-aliases"
+	ACTREMOVEGROUPS  string = "remove-groups"
+	ACTREVOKETOKENS  string = "revoke-tokens"
+	ACTSIGNOUT       string = "signout"
+	ACTSUSPEND       string = "suspend"
+	ACTWIPEMOBDEVS   string = "wipe-mobile-devices"
+)
+
+// ValidActions provide valid strings to be used for workflow step actions
+var ValidActions = []string{
+	ACTDELETEMOBDEVS,
+	ACTMOVEORGUNIT,
+	ACTREMOVEALIASES,
+	ACTREMOVEGROUPS,
+	ACTREVOKETOKENS,
+	ACTSIGNOUT,
+	ACTSUSPEND,
+	ACTWIPEMOBDEVS,
+}
+
+// actionScopes holds the scope needed by each workflow step action
+var actionScopes = map[string]string{
+	ACTDELETEMOBDEVS: admin.AdminDirectoryDeviceMobileScope,
+	ACTMOVEORGUNIT:   admin.AdminDirectoryUserScope,
+	ACTREMOVEALIASES: admin.AdminDirectoryUserAliasScope,
+	ACTREMOVEGROUPS:  admin.AdminDirectoryGroupScope,
+	ACTREVOKETOKENS:  admin.AdminDirectoryUserSecurityScope,
+	ACTSIGNOUT:       admin.AdminDirectoryUserSecurityScope,
+	ACTSUSPEND:       admin.AdminDirectoryUserScope,
+	ACTWIPEMOBDEVS:   admin.AdminDirectoryDeviceMobileActionScope,
+}
+
+// Progress records the outcome of the steps of an offboarding run so that it can be resumed
+type Progress struct {
+	Steps []StepResult `json:"steps"`
+	User  string       `json:"user"`
+}
+
+// Step is an offboarding workflow step. OrgUnit is only used by the move-orgunit action.
+type Step struct {
+	Action  string `yaml:"action"`
+	OrgUnit string `yaml:"orgunit,omitempty"`
+}
+
+// StepResult is the outcome of an offboarding workflow step
+type StepResult struct {
+	Action  string `json:"action"`
+	Error   string `json:"error,omitempty"`
+	OrgUnit string `json:"orgunit,omitempty"`
+	Status  string `json:"status"`
+	Time    string `json:"time"`
+}
+
+// Workflow is an ordered list of offboarding steps read from a workflow file
+type Workflow struct {
+	Steps []Step `yaml:"steps"`
+}
+
+// DefaultWorkflow is used when no workflow file is given
+func DefaultWorkflow() *Workflow {
+	return &Workflow{
+		Steps: []Step{
+			{Action: ACTSUSPEND},
+			{Action: ACTSIGNOUT},
+			{Action: ACTREVOKETOKENS},
+			{Action: ACTREMOVEGROUPS},
+			{Action: ACTREMOVEALIASES},
+			{Action: ACTWIPEMOBDEVS},
+		},
+	}
+}
+
+// Load reads and validates a workflow file
+func Load(filePath string) (*Workflow, error) {
+	lg.Debugw("starting Load()",
+		"filePath", filePath)
+	defer lg.Debug("finished Load()")
+
+	yamlBytes, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	wf := new(Workflow)
+	err = yaml.UnmarshalStrict(yamlBytes, wf)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	err = wf.validate()
+	if err != nil {
+		return nil, err
+	}
+	return wf, nil
+}
+
+// LoadProgress reads the saved progress of a user's offboarding run. An empty Progress is returned if
+// there is no saved progress.
+func LoadProgress(filePath string, user string) (*Progress, error) {
+	lg.Debugw("starting LoadProgress()",
+		"filePath", filePath,
+		"user", user)
+	defer lg.Debug("finished LoadProgress()")
+
+	progress := &Progress{User: user}
+
+	jsonBytes, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return progress, nil
+	}
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	err = json.Unmarshal(jsonBytes, progress)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+	return progress, nil
+}
+
+// ProgressPath returns the path of the progress file of a user's offboarding run
+func ProgressPath(user string) (string, error) {
+	lg.Debugw("starting ProgressPath()",
+		"user", user)
+	defer lg.Debug("finished ProgressPath()")
+
+	jrnlDir, err := cmn.JournalDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(jrnlDir), OFFBOARDDIR, strings.ToLower(user)+".json"), nil
+}
+
+// Done reports whether a step has already been completed. A step is only treated as done if the
+// step at the same position in the saved progress is the same step and completed successfully.
+func (p *Progress) Done(idx int, step Step) bool {
+	if idx >= len(p.Steps) {
+		return false
+	}
+	saved := p.Steps[idx]
+	return Step{Action: saved.Action, OrgUnit: saved.OrgUnit} == step && saved.Status == STATUSDONE
+}
+
+// Record sets the outcome of a step
+func (p *Progress) Record(idx int, step Step, stepErr error) {
+	result := StepResult{
+		Action:  step.Action,
+		OrgUnit: step.OrgUnit,
+		Status:  STATUSDONE,
+		Time:    time.Now().UTC().Format(time.RFC3339),
+	}
+	if stepErr != nil {
+		result.Error = stepErr.Error()
+		result.Status = STATUSFAILED
+	}
+
+	if idx < len(p.Steps) {
+		p.Steps[idx] = result
+		p.Steps = p.Steps[:idx+1]
+		return
+	}
+	p.Steps = append(p.Steps, result)
+}
+
+// Save writes progress to a file
+func (p *Progress) Save(filePath string) error {
+	lg.Debugw("starting Save()",
+		"filePath", filePath)
+	defer lg.Debug("finished Save()")
+
+	err := os.MkdirAll(filepath.Dir(filePath), 0755)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	jsonBytes, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	err = ioutil.WriteFile(filePath, jsonBytes, 0644)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	return nil
+}
+
+// Scopes returns the scopes needed to carry out the workflow steps
+func (wf *Workflow) Scopes() []string {
+	var (
+		scopes []string
+		seen   = map[string]bool{}
+	)
+
+	// User scope is always needed to look up the user
+	for _, scope := range append([]string{admin.AdminDirectoryUserScope}, wf.actionScopes()...) {
+		if seen[scope] {
+			continue
+		}
+		seen[scope] = true
+		scopes = append(scopes, scope)
+	}
+	return scopes
+}
+
+func (wf *Workflow) actionScopes() []string {
+	var scopes []string
+
+	for _, step := range wf.Steps {
+		scopes = append(scopes, actionScopes[step.Action])
+		// Mobile devices are found by listing them
+		if step.Action == ACTWIPEMOBDEVS {
+			scopes = append(scopes, admin.AdminDirectoryDeviceMobileReadonlyScope)
+		}
+	}
+	return scopes
+}
+
+func (wf *Workflow) validate() error {
+	lg.Debug("starting validate()")
+	defer lg.Debug("finished validate()")
+
+	if len(wf.Steps) == 0 {
+		err := errors.New(gmess.ERR_NOWORKFLOWSTEPS)
+		lg.Error(err)
+		return err
+	}
+
+	for idx := range wf.Steps {
+		step := &wf.Steps[idx]
+		step.Action = strings.ToLower(step.Action)
+
+		if !cmn.SliceContainsStr(ValidActions, step.Action) {
+			err := fmt.Errorf(gmess.ERR_INVALIDACTIONTYPE, step.Action)
+			lg.Error(err)
+			return err
+		}
+
+		if step.Action == ACTMOVEORGUNIT && !strings.HasPrefix(step.OrgUnit, "/") {
+			err := fmt.Errorf(gmess.ERR_INVALIDORGUNITPATH, step.OrgUnit)
+			lg.Error(err)
+			return err
+		}
+		if step.Action != ACTMOVEORGUNIT && step.OrgUnit != "" {
+			err := fmt.Errorf(gmess.ERR_STEPORGUNIT, step.Action)
+			lg.Error(err)
+			return err
+		}
+	}
+	return nil
+}
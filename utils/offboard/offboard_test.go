@@ -0,0 +1,204 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package offboard
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	tsts "github.com/plusworx/gmin/tests"
+	lg "github.com/plusworx/gmin/utils/logging"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+var errTest = errors.New("test error")
+
+func TestLoad(t *testing.T) {
+	cases := []struct {
+		expectedErr   string
+		expectedSteps int
+		yaml          string
+	}{
+		{
+			expectedSteps: 3,
+			yaml:          "steps:\n  - action: Suspend\n  - action: move-orgunit\n    orgunit: /Leavers\n  - action: signout\n",
+		},
+		{
+			expectedErr: "workflow must have at least one step",
+			yaml:        "steps: []\n",
+		},
+		{
+			expectedErr: "invalid action type: archive",
+			yaml:        "steps:\n  - action: archive\n",
+		},
+		{
+			expectedErr: "invalid orgunit path: ",
+			yaml:        "steps:\n  - action: move-orgunit\n",
+		},
+		{
+			expectedErr: "orgunit can only be given to move-orgunit steps: suspend",
+			yaml:        "steps:\n  - action: suspend\n    orgunit: /Leavers\n",
+		},
+		{
+			expectedErr: "yaml: unmarshal errors:\n  line 2: field ou not found in type offboard.Step",
+			yaml:        "steps:\n  - ou: /Leavers\n",
+		},
+	}
+
+	tsts.InitConfig()
+	lg.InitLogging("info")
+
+	dir := t.TempDir()
+
+	for _, c := range cases {
+		path := filepath.Join(dir, "workflow.yaml")
+		ioutil.WriteFile(path, []byte(c.yaml), 0644)
+
+		wf, err := Load(path)
+
+		gotErr := ""
+		if err != nil {
+			gotErr = err.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("Got error: %v - expected error: %v", gotErr, c.expectedErr)
+			continue
+		}
+		if err == nil && len(wf.Steps) != c.expectedSteps {
+			t.Errorf("Got steps: %v - expected steps: %v", len(wf.Steps), c.expectedSteps)
+		}
+		if err == nil && wf.Steps[0].Action != ACTSUSPEND {
+			t.Errorf("Got action: %v - expected action: %v", wf.Steps[0].Action, ACTSUSPEND)
+		}
+	}
+}
+
+func TestProgress(t *testing.T) {
+	tsts.InitConfig()
+	lg.InitLogging("info")
+
+	wf := DefaultWorkflow()
+	path := filepath.Join(t.TempDir(), "offboard", "frank.castle@mycompany.com.json")
+
+	progress, err := LoadProgress(path, "frank.castle@mycompany.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if progress.Done(0, wf.Steps[0]) {
+		t.Error("Got step 1 done: true - expected step 1 done: false")
+	}
+
+	progress.Record(0, wf.Steps[0], nil)
+	progress.Record(1, wf.Steps[1], nil)
+	progress.Record(2, wf.Steps[2], errTest)
+	progress.Record(3, Step{Action: ACTMOVEORGUNIT, OrgUnit: "/Leavers"}, nil)
+
+	err = progress.Save(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	progress, err = LoadProgress(path, "frank.castle@mycompany.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		expectedDone bool
+		idx          int
+		step         Step
+	}{
+		{
+			expectedDone: true,
+			idx:          0,
+			step:         wf.Steps[0],
+		},
+		{
+			expectedDone: true,
+			idx:          1,
+			step:         wf.Steps[1],
+		},
+		{
+			idx:  2,
+			step: wf.Steps[2],
+		},
+		{
+			idx:  3,
+			step: wf.Steps[3],
+		},
+		{
+			expectedDone: true,
+			idx:          3,
+			step:         Step{Action: ACTMOVEORGUNIT, OrgUnit: "/Leavers"},
+		},
+		{
+			idx:  3,
+			step: Step{Action: ACTMOVEORGUNIT, OrgUnit: "/Archive"},
+		},
+		{
+			idx:  4,
+			step: wf.Steps[4],
+		},
+		{
+			idx:  1,
+			step: Step{Action: ACTREMOVEGROUPS},
+		},
+	}
+
+	for _, c := range cases {
+		got := progress.Done(c.idx, c.step)
+		if got != c.expectedDone {
+			t.Errorf("Got step %v done: %v - expected step %v done: %v", c.idx+1, got, c.idx+1, c.expectedDone)
+		}
+	}
+
+	// Recording an earlier step again discards the outcome of the steps after it
+	progress.Record(1, wf.Steps[1], errTest)
+	if len(progress.Steps) != 2 || progress.Steps[1].Error != errTest.Error() {
+		t.Errorf("Got steps: %v - expected 2 steps with failed step 2", progress.Steps)
+	}
+}
+
+func TestScopes(t *testing.T) {
+	wf := &Workflow{
+		Steps: []Step{
+			{Action: ACTSUSPEND},
+			{Action: ACTMOVEORGUNIT, OrgUnit: "/Leavers"},
+			{Action: ACTREMOVEGROUPS},
+		},
+	}
+
+	got := wf.Scopes()
+	expected := []string{admin.AdminDirectoryUserScope, admin.AdminDirectoryGroupScope}
+
+	if len(got) != len(expected) {
+		t.Fatalf("Got scopes: %v - expected scopes: %v", got, expected)
+	}
+	for idx := range got {
+		if got[idx] != expected[idx] {
+			t.Errorf("Got scope: %v - expected scope: %v", got[idx], expected[idx])
+		}
+	}
+}
@@ -0,0 +1,496 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package onboard
+
+import (
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	valid "github.com/asaskevich/govalidator"
+	cmn "github.com/plusworx/gmin/utils/common"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	mems "github.com/plusworx/gmin/utils/members"
+	admin "google.golang.org/api/admin/directory/v1"
+	"google.golang.org/api/googleapi"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// DEFAULTPASSWORDLENGTH is the length of generated passwords when none is given
+	DEFAULTPASSWORDLENGTH int = 16
+	// DEFAULTROLE is the role given to template group members that don't have one
+	DEFAULTROLE string = "MEMBER"
+	// MAXPASSWORDLENGTH is the maximum length of a Google Workspace password
+	MAXPASSWORDLENGTH int = 100
+	// MINPASSWORDLENGTH is the minimum length of a Google Workspace password
+	MINPASSWORDLENGTH int = 8
+)
+
+// Pattern placeholders
+const (
+	PHFIRSTNAME   string = "{firstname}"
+	PHINITIAL     string = "{initial}"
+	PHLASTINITIAL string = "{lastinitial}"
+	PHLASTNAME    string = "{lastname}"
+)
+
+// Password character classes
+const (
+	pwdDigits  string = "23456789"
+	pwdLower   string = "abcdefghijkmnopqrstuvwxyz"
+	pwdSymbols string = "!#$%&*+-=?@^_~"
+	pwdUpper   string = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+)
+
+// StarterAttrMap provides lowercase mappings to valid Starter attributes
+var StarterAttrMap = map[string]string{
+	"firstname":    "firstName",
+	"lastname":     "lastName",
+	"primaryemail": "primaryEmail",
+	"template":     "template",
+}
+
+// ValidPlaceholders provide valid placeholders for primary email address and alias patterns
+var ValidPlaceholders = []string{
+	PHFIRSTNAME,
+	PHINITIAL,
+	PHLASTINITIAL,
+	PHLASTNAME,
+}
+
+var (
+	nameCharsRegex   = regexp.MustCompile(`[^a-z0-9]`)
+	placeholderRegex = regexp.MustCompile(`\{[^}]*\}`)
+)
+
+// Credential holds the password generated for a new user
+type Credential struct {
+	Password     string
+	PrimaryEmail string
+}
+
+// Group is a group that new users are added to
+type Group struct {
+	Email string `yaml:"email"`
+	Role  string `yaml:"role,omitempty"`
+}
+
+// Starter holds the details of a new user
+type Starter struct {
+	FirstName    string `json:"firstName"`
+	LastName     string `json:"lastName"`
+	PrimaryEmail string `json:"primaryEmail,omitempty"`
+	Template     string `json:"template,omitempty"`
+}
+
+// Template holds the settings given to new users. Aliases and PrimaryEmail are patterns that
+// can contain the {firstname}, {lastname}, {initial} and {lastinitial} placeholders.
+type Template struct {
+	Aliases                    []string                          `yaml:"aliases"`
+	ChangePasswordAtNextLogin  *bool                             `yaml:"changePasswordAtNextLogin"`
+	CustomSchemas              map[string]map[string]interface{} `yaml:"customSchemas"`
+	Department                 string                            `yaml:"department"`
+	Groups                     []Group                           `yaml:"groups"`
+	IncludeInGlobalAddressList *bool                             `yaml:"includeInGlobalAddressList"`
+	OrgUnit                    string                            `yaml:"orgunit"`
+	PrimaryEmail               string                            `yaml:"primaryEmail"`
+}
+
+// Templates holds onboarding templates keyed by name
+type Templates struct {
+	Templates map[string]*Template `yaml:"templates"`
+}
+
+// Expand replaces the placeholders in a pattern with a new user's names. Names are lowercased and
+// any characters that are not letters or digits are removed.
+func Expand(pattern string, firstName string, lastName string) string {
+	first := nameCharsRegex.ReplaceAllString(strings.ToLower(firstName), "")
+	last := nameCharsRegex.ReplaceAllString(strings.ToLower(lastName), "")
+
+	var initial, lastInitial string
+	if first != "" {
+		initial = first[:1]
+	}
+	if last != "" {
+		lastInitial = last[:1]
+	}
+
+	replacer := strings.NewReplacer(
+		PHFIRSTNAME, first,
+		PHINITIAL, initial,
+		PHLASTINITIAL, lastInitial,
+		PHLASTNAME, last,
+	)
+	return replacer.Replace(pattern)
+}
+
+// GeneratePassword returns a random password that contains at least one upper case letter,
+// lower case letter, digit and symbol. Characters that are easily confused are not used.
+func GeneratePassword(length int) (string, error) {
+	lg.Debugw("starting GeneratePassword()",
+		"length", length)
+	defer lg.Debug("finished GeneratePassword()")
+
+	if length < MINPASSWORDLENGTH || length > MAXPASSWORDLENGTH {
+		err := fmt.Errorf(gmess.ERR_INVALIDPWDLENGTH, length, MINPASSWORDLENGTH, MAXPASSWORDLENGTH)
+		lg.Error(err)
+		return "", err
+	}
+
+	classes := []string{pwdDigits, pwdLower, pwdSymbols, pwdUpper}
+	allChars := strings.Join(classes, "")
+	pwd := make([]byte, length)
+
+	for idx := range pwd {
+		chars := allChars
+		if idx < len(classes) {
+			chars = classes[idx]
+		}
+		char, err := randomChar(chars)
+		if err != nil {
+			return "", err
+		}
+		pwd[idx] = char
+	}
+
+	// Shuffle so that the guaranteed characters aren't always at the start
+	for idx := len(pwd) - 1; idx > 0; idx-- {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(idx+1)))
+		if err != nil {
+			lg.Error(err)
+			return "", err
+		}
+		swap := n.Int64()
+		pwd[idx], pwd[swap] = pwd[swap], pwd[idx]
+	}
+	return string(pwd), nil
+}
+
+// Load reads and validates an onboarding template file
+func Load(filePath string) (*Templates, error) {
+	lg.Debugw("starting Load()",
+		"filePath", filePath)
+	defer lg.Debug("finished Load()")
+
+	yamlBytes, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	tmpls := new(Templates)
+	err = yaml.UnmarshalStrict(yamlBytes, tmpls)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	err = tmpls.validate()
+	if err != nil {
+		return nil, err
+	}
+	return tmpls, nil
+}
+
+// PopulateStarter is used in batch processing
+func PopulateStarter(starter *Starter, hdrMap map[int]string, objData []interface{}) error {
+	lg.Debugw("starting PopulateStarter()",
+		"hdrMap", hdrMap)
+	defer lg.Debug("finished PopulateStarter()")
+
+	for idx, attr := range objData {
+		attrName := hdrMap[idx]
+		attrVal := fmt.Sprintf("%v", attr)
+
+		switch {
+		case attrName == "firstName":
+			starter.FirstName = attrVal
+		case attrName == "lastName":
+			starter.LastName = attrVal
+		case attrName == "primaryEmail":
+			starter.PrimaryEmail = attrVal
+		case attrName == "template":
+			starter.Template = attrVal
+		default:
+			err := fmt.Errorf(gmess.ERR_ATTRNOTRECOGNIZED, attrName)
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteHandoff writes the credentials of new users to a CSV file that only the owner can read
+func WriteHandoff(filePath string, creds []Credential) error {
+	lg.Debugw("starting WriteHandoff()",
+		"filePath", filePath)
+	defer lg.Debug("finished WriteHandoff()")
+
+	sort.Slice(creds, func(i, j int) bool {
+		return creds[i].PrimaryEmail < creds[j].PrimaryEmail
+	})
+
+	f, err := os.OpenFile(filePath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	err = w.Write([]string{"primaryEmail", "password"})
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	for _, cred := range creds {
+		err = w.Write([]string{cred.PrimaryEmail, cred.Password})
+		if err != nil {
+			lg.Error(err)
+			return err
+		}
+	}
+	w.Flush()
+
+	err = w.Error()
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	return nil
+}
+
+// AliasesFor returns a new user's email aliases. Aliases that are the same as the primary email
+// address are left out.
+func (t *Template) AliasesFor(starter *Starter, primaryEmail string) []string {
+	var aliases []string
+
+	for _, pattern := range t.Aliases {
+		alias := Expand(pattern, starter.FirstName, starter.LastName)
+		if strings.EqualFold(alias, primaryEmail) {
+			continue
+		}
+		aliases = append(aliases, alias)
+	}
+	return aliases
+}
+
+// NewUser returns the user to create for a new starter. The starter's primary email address is
+// used if it has one, otherwise the template's primary email address pattern is expanded.
+func (t *Template) NewUser(starter *Starter) (*admin.User, error) {
+	lg.Debugw("starting NewUser()",
+		"starter", starter.FirstName+" "+starter.LastName)
+	defer lg.Debug("finished NewUser()")
+
+	if starter.FirstName == "" || starter.LastName == "" {
+		err := fmt.Errorf(gmess.ERR_EMPTYSTRING, "firstName and lastName")
+		lg.Error(err)
+		return nil, err
+	}
+
+	user := new(admin.User)
+	user.Name = &admin.UserName{FamilyName: starter.LastName, GivenName: starter.FirstName}
+
+	user.PrimaryEmail = starter.PrimaryEmail
+	if user.PrimaryEmail == "" {
+		user.PrimaryEmail = Expand(t.PrimaryEmail, starter.FirstName, starter.LastName)
+	}
+	if user.PrimaryEmail == "" {
+		err := errors.New(gmess.ERR_NOPRIMARYEMAIL)
+		lg.Error(err)
+		return nil, err
+	}
+	if !valid.IsEmail(user.PrimaryEmail) {
+		err := fmt.Errorf(gmess.ERR_INVALIDEMAILADDRESS, user.PrimaryEmail)
+		lg.Error(err)
+		return nil, err
+	}
+
+	// New users are given a generated password so they change it by default
+	user.ChangePasswordAtNextLogin = true
+	if t.ChangePasswordAtNextLogin != nil && !*t.ChangePasswordAtNextLogin {
+		user.ChangePasswordAtNextLogin = false
+		user.ForceSendFields = append(user.ForceSendFields, "ChangePasswordAtNextLogin")
+	}
+
+	if t.IncludeInGlobalAddressList != nil {
+		user.IncludeInGlobalAddressList = *t.IncludeInGlobalAddressList
+		if !user.IncludeInGlobalAddressList {
+			user.ForceSendFields = append(user.ForceSendFields, "IncludeInGlobalAddressList")
+		}
+	}
+
+	user.OrgUnitPath = t.OrgUnit
+
+	if t.Department != "" {
+		user.Organizations = []*admin.UserOrganization{{Department: t.Department, Primary: true}}
+	}
+
+	if len(t.CustomSchemas) > 0 {
+		user.CustomSchemas = map[string]googleapi.RawMessage{}
+		for schema, fields := range t.CustomSchemas {
+			jsonBytes, err := json.Marshal(jsonValue(fields))
+			if err != nil {
+				lg.Error(err)
+				return nil, err
+			}
+			user.CustomSchemas[schema] = jsonBytes
+		}
+	}
+	return user, nil
+}
+
+// Get returns the named template
+func (ts *Templates) Get(name string) (*Template, error) {
+	lg.Debugw("starting Get()",
+		"name", name)
+	defer lg.Debug("finished Get()")
+
+	tmpl, ok := ts.Templates[strings.ToLower(name)]
+	if !ok {
+		err := fmt.Errorf(gmess.ERR_TEMPLATENOTFOUND, name)
+		lg.Error(err)
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+func (ts *Templates) validate() error {
+	lg.Debug("starting validate()")
+	defer lg.Debug("finished validate()")
+
+	if len(ts.Templates) == 0 {
+		err := errors.New(gmess.ERR_NOTEMPLATES)
+		lg.Error(err)
+		return err
+	}
+
+	// Template names are case insensitive
+	lowerTmpls := map[string]*Template{}
+	for name, tmpl := range ts.Templates {
+		if tmpl == nil {
+			tmpl = new(Template)
+		}
+		err := tmpl.validate(name)
+		if err != nil {
+			return err
+		}
+		lowerTmpls[strings.ToLower(name)] = tmpl
+	}
+	ts.Templates = lowerTmpls
+	return nil
+}
+
+func (t *Template) validate(name string) error {
+	lg.Debugw("starting validate()",
+		"name", name)
+	defer lg.Debug("finished validate()")
+
+	if t.OrgUnit != "" && !strings.HasPrefix(t.OrgUnit, "/") {
+		err := fmt.Errorf(gmess.ERR_INVALIDORGUNITPATH, t.OrgUnit)
+		lg.Error(err)
+		return err
+	}
+
+	patterns := append([]string{t.PrimaryEmail}, t.Aliases...)
+	for _, pattern := range patterns {
+		for _, ph := range placeholderRegex.FindAllString(pattern, -1) {
+			if !cmn.SliceContainsStr(ValidPlaceholders, strings.ToLower(ph)) {
+				err := fmt.Errorf(gmess.ERR_INVALIDPLACEHOLDER, ph, name)
+				lg.Error(err)
+				return err
+			}
+		}
+	}
+	for _, alias := range t.Aliases {
+		if !strings.Contains(alias, "@") {
+			err := fmt.Errorf(gmess.ERR_INVALIDEMAILADDRESS, alias)
+			lg.Error(err)
+			return err
+		}
+	}
+
+	for idx := range t.Groups {
+		grp := &t.Groups[idx]
+		if !valid.IsEmail(grp.Email) {
+			err := fmt.Errorf(gmess.ERR_INVALIDEMAILADDRESS, grp.Email)
+			lg.Error(err)
+			return err
+		}
+		if grp.Role == "" {
+			grp.Role = DEFAULTROLE
+		}
+		role, err := mems.ValidateRole(grp.Role)
+		if err != nil {
+			lg.Error(err)
+			return err
+		}
+		grp.Role = role
+	}
+	return nil
+}
+
+// jsonValue converts the map[interface{}]interface{} values produced by the YAML decoder
+// so that custom schema values can be marshalled to JSON
+func jsonValue(val interface{}) interface{} {
+	switch v := val.(type) {
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+		for key, mVal := range v {
+			m[fmt.Sprintf("%v", key)] = jsonValue(mVal)
+		}
+		return m
+	case map[string]interface{}:
+		m := map[string]interface{}{}
+		for key, mVal := range v {
+			m[key] = jsonValue(mVal)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for idx, sVal := range v {
+			s[idx] = jsonValue(sVal)
+		}
+		return s
+	default:
+		return v
+	}
+}
+
+func randomChar(chars string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(chars))))
+	if err != nil {
+		lg.Error(err)
+		return 0, err
+	}
+	return chars[n.Int64()], nil
+}
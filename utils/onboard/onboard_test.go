@@ -0,0 +1,308 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package onboard
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode"
+
+	tsts "github.com/plusworx/gmin/tests"
+	lg "github.com/plusworx/gmin/utils/logging"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+const testTemplates = `templates:
+  Sales:
+    primaryEmail: "{firstname}.{lastname}@mycompany.com"
+    aliases:
+      - "{initial}{lastname}@mycompany.com"
+      - "{firstname}.{lastname}@mycompany.com"
+    orgunit: /Sales
+    department: Sales
+    groups:
+      - email: sales@mycompany.com
+      - email: sales-leads@mycompany.com
+        role: manager
+    customSchemas:
+      EmployeeData:
+        costCentre: S100
+        projects:
+          - value: Alpha
+    changePasswordAtNextLogin: false
+    includeInGlobalAddressList: false
+  contractors:
+    orgunit: /Contractors
+`
+
+func TestExpand(t *testing.T) {
+	cases := []struct {
+		expectedStr string
+		firstName   string
+		lastName    string
+		pattern     string
+	}{
+		{
+			expectedStr: "peter.parker@mycompany.com",
+			firstName:   "Peter",
+			lastName:    "Parker",
+			pattern:     "{firstname}.{lastname}@mycompany.com",
+		},
+		{
+			expectedStr: "maryjane.w@mycompany.com",
+			firstName:   "Mary Jane",
+			lastName:    "Watson",
+			pattern:     "{firstname}.{lastinitial}@mycompany.com",
+		},
+		{
+			expectedStr: "soconnor@mycompany.com",
+			firstName:   "Sean",
+			lastName:    "O'Connor",
+			pattern:     "{initial}{lastname}@mycompany.com",
+		},
+		{
+			expectedStr: "sales@mycompany.com",
+			firstName:   "Peter",
+			lastName:    "Parker",
+			pattern:     "sales@mycompany.com",
+		},
+	}
+
+	for _, c := range cases {
+		output := Expand(c.pattern, c.firstName, c.lastName)
+		if output != c.expectedStr {
+			t.Errorf("Got output: %v - expected output: %v", output, c.expectedStr)
+		}
+	}
+}
+
+func TestGeneratePassword(t *testing.T) {
+	tsts.InitConfig()
+	lg.InitLogging("info")
+
+	cases := []struct {
+		expectedErr string
+		length      int
+	}{
+		{
+			length: MINPASSWORDLENGTH,
+		},
+		{
+			length: DEFAULTPASSWORDLENGTH,
+		},
+		{
+			expectedErr: "password length 7 must be between 8 and 100",
+			length:      7,
+		},
+		{
+			expectedErr: "password length 101 must be between 8 and 100",
+			length:      101,
+		},
+	}
+
+	for _, c := range cases {
+		pwd, err := GeneratePassword(c.length)
+
+		gotErr := ""
+		if err != nil {
+			gotErr = err.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("Got error: %v - expected error: %v", gotErr, c.expectedErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		if len(pwd) != c.length {
+			t.Errorf("Got length: %v - expected length: %v", len(pwd), c.length)
+		}
+		if strings.IndexFunc(pwd, unicode.IsUpper) == -1 || strings.IndexFunc(pwd, unicode.IsLower) == -1 ||
+			strings.IndexFunc(pwd, unicode.IsDigit) == -1 || !strings.ContainsAny(pwd, pwdSymbols) {
+			t.Errorf("Got password: %v - expected upper and lower case letters, digits and symbols", pwd)
+		}
+	}
+
+	pwd1, _ := GeneratePassword(DEFAULTPASSWORDLENGTH)
+	pwd2, _ := GeneratePassword(DEFAULTPASSWORDLENGTH)
+	if pwd1 == pwd2 {
+		t.Errorf("Got passwords: %v %v - expected different passwords", pwd1, pwd2)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	cases := []struct {
+		expectedErr string
+		yaml        string
+	}{
+		{
+			yaml: testTemplates,
+		},
+		{
+			expectedErr: "template file has no templates",
+			yaml:        "templates: {}\n",
+		},
+		{
+			expectedErr: "invalid orgunit path: Sales",
+			yaml:        "templates:\n  sales:\n    orgunit: Sales\n",
+		},
+		{
+			expectedErr: "invalid placeholder {surname} in template: sales",
+			yaml:        "templates:\n  sales:\n    primaryEmail: \"{surname}@mycompany.com\"\n",
+		},
+		{
+			expectedErr: "invalid email address: {lastname}",
+			yaml:        "templates:\n  sales:\n    aliases:\n      - \"{lastname}\"\n",
+		},
+		{
+			expectedErr: "invalid role: boss",
+			yaml:        "templates:\n  sales:\n    groups:\n      - email: sales@mycompany.com\n        role: boss\n",
+		},
+		{
+			expectedErr: "yaml: unmarshal errors:\n  line 3: field ou not found in type onboard.Template",
+			yaml:        "templates:\n  sales:\n    ou: /Sales\n",
+		},
+	}
+
+	tsts.InitConfig()
+	lg.InitLogging("info")
+
+	dir := t.TempDir()
+
+	for _, c := range cases {
+		path := filepath.Join(dir, "templates.yaml")
+		ioutil.WriteFile(path, []byte(c.yaml), 0644)
+
+		_, err := Load(path)
+
+		gotErr := ""
+		if err != nil {
+			gotErr = err.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("Got error: %v - expected error: %v", gotErr, c.expectedErr)
+		}
+	}
+}
+
+func TestNewUser(t *testing.T) {
+	tsts.InitConfig()
+	lg.InitLogging("info")
+
+	path := filepath.Join(t.TempDir(), "templates.yaml")
+	ioutil.WriteFile(path, []byte(testTemplates), 0644)
+
+	tmpls, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sales, err := tmpls.Get("SALES")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sales.Groups[0].Role != "MEMBER" || sales.Groups[1].Role != "MANAGER" {
+		t.Errorf("Got roles: %v %v - expected roles: MEMBER MANAGER", sales.Groups[0].Role, sales.Groups[1].Role)
+	}
+
+	starter := &Starter{FirstName: "Peter", LastName: "Parker"}
+	user, err := sales.NewUser(starter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.PrimaryEmail != "peter.parker@mycompany.com" || user.OrgUnitPath != "/Sales" {
+		t.Errorf("Got user: %v %v - expected user: peter.parker@mycompany.com /Sales", user.PrimaryEmail, user.OrgUnitPath)
+	}
+	if user.ChangePasswordAtNextLogin || user.IncludeInGlobalAddressList || len(user.ForceSendFields) != 2 {
+		t.Errorf("Got force send fields: %v - expected ChangePasswordAtNextLogin and IncludeInGlobalAddressList", user.ForceSendFields)
+	}
+	orgs := user.Organizations.([]*admin.UserOrganization)
+	if orgs[0].Department != "Sales" {
+		t.Errorf("Got department: %v - expected department: Sales", orgs[0].Department)
+	}
+	schema := string(user.CustomSchemas["EmployeeData"])
+	if schema != `{"costCentre":"S100","projects":[{"value":"Alpha"}]}` {
+		t.Errorf("Got custom schema: %v - expected custom schema with costCentre and projects", schema)
+	}
+
+	aliases := sales.AliasesFor(starter, user.PrimaryEmail)
+	if len(aliases) != 1 || aliases[0] != "pparker@mycompany.com" {
+		t.Errorf("Got aliases: %v - expected aliases: [pparker@mycompany.com]", aliases)
+	}
+
+	contractors, err := tmpls.Get("contractors")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = contractors.NewUser(starter)
+	if err == nil || err.Error() != "primaryEmail must be given when template has no primaryEmail pattern" {
+		t.Errorf("Got error: %v - expected error: primaryEmail must be given when template has no primaryEmail pattern", err)
+	}
+
+	user, err = contractors.NewUser(&Starter{FirstName: "Peter", LastName: "Parker", PrimaryEmail: "spidey@mycompany.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.PrimaryEmail != "spidey@mycompany.com" || !user.ChangePasswordAtNextLogin {
+		t.Errorf("Got user: %v %v - expected user: spidey@mycompany.com true", user.PrimaryEmail, user.ChangePasswordAtNextLogin)
+	}
+
+	_, err = tmpls.Get("marketing")
+	if err == nil || err.Error() != "template not found: marketing" {
+		t.Errorf("Got error: %v - expected error: template not found: marketing", err)
+	}
+}
+
+func TestWriteHandoff(t *testing.T) {
+	tsts.InitConfig()
+	lg.InitLogging("info")
+
+	path := filepath.Join(t.TempDir(), "handoff.csv")
+	creds := []Credential{
+		{PrimaryEmail: "peter.parker@mycompany.com", Password: "pwd2"},
+		{PrimaryEmail: "mary.jane@mycompany.com", Password: "pwd1"},
+	}
+
+	err := WriteHandoff(path, creds)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Got file mode: %v - expected file mode: -rw-------", info.Mode().Perm())
+	}
+
+	content, _ := ioutil.ReadFile(path)
+	expected := "primaryEmail,password\nmary.jane@mycompany.com,pwd1\npeter.parker@mycompany.com,pwd2\n"
+	if string(content) != expected {
+		t.Errorf("Got handoff file: %v - expected handoff file: %v", string(content), expected)
+	}
+}
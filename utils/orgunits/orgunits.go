@@ -39,6 +39,8 @@ const (
 	ENDFIELD string = ")"
 	// KEYNAME is name of key for processing
 	KEYNAME string = "ouKey"
+	// LISTKEY is name of List call results attribute
+	LISTKEY string = "organizationUnits"
 	// STARTORGUNITSFIELD is List call attribute string prefix
 	STARTORGUNITSFIELD string = "organizationUnits("
 )
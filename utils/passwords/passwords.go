@@ -0,0 +1,346 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package passwords
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+// Directory API password hash functions
+const (
+	HASHCRYPT string = "crypt"
+	HASHMD5   string = "MD5"
+	HASHSHA1  string = "SHA-1"
+)
+
+const (
+	// DEFAULTHASHER is the name of the hasher used when none is configured
+	DEFAULTHASHER string = "crypt"
+	// SHA512CRYPTROUNDS is the number of SHA-512 crypt rounds. 5000 is the crypt default so it
+	// isn't included in hashes.
+	SHA512CRYPTROUNDS int = 5000
+	// SHA512CRYPTSALTLEN is the length of SHA-512 crypt salts
+	SHA512CRYPTSALTLEN int = 16
+)
+
+// cryptAlphabet is the alphabet used by crypt for salts and hash encoding
+const cryptAlphabet string = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// Hasher hashes passwords in a format accepted by the Directory API
+type Hasher interface {
+	// Hash returns the hash of a password
+	Hash(password string) (string, error)
+	// HashFunction returns the Directory API hashFunction value of the hashes
+	HashFunction() string
+}
+
+// ValidHashFunctions provide valid Directory API hashFunction values for already hashed passwords
+var ValidHashFunctions = []string{
+	HASHCRYPT,
+	HASHMD5,
+	HASHSHA1,
+}
+
+var (
+	hashers = map[string]Hasher{
+		"crypt": sha512CryptHasher{},
+		"md5":   hexHasher{hashFunction: HASHMD5},
+		"sha-1": hexHasher{hashFunction: HASHSHA1},
+	}
+	hashersMu sync.RWMutex
+	hexRegex  = regexp.MustCompile(`^[0-9a-fA-F]+$`)
+)
+
+// hexHasher makes hex encoded MD5 or SHA-1 hashes
+type hexHasher struct {
+	hashFunction string
+}
+
+// sha512CryptHasher makes SHA-512 crypt ($6$) hashes
+type sha512CryptHasher struct{}
+
+// HasherNames returns the names of the registered hashers
+func HasherNames() []string {
+	hashersMu.RLock()
+	defer hashersMu.RUnlock()
+
+	var names []string
+	for name := range hashers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewHasher returns the named hasher. Names are case insensitive and an empty name returns the
+// default hasher.
+func NewHasher(name string) (Hasher, error) {
+	lg.Debugw("starting NewHasher()",
+		"name", name)
+	defer lg.Debug("finished NewHasher()")
+
+	if name == "" {
+		name = DEFAULTHASHER
+	}
+
+	hashersMu.RLock()
+	hasher, ok := hashers[strings.ToLower(name)]
+	hashersMu.RUnlock()
+
+	if !ok {
+		err := fmt.Errorf(gmess.ERR_INVALIDHASHER, name, strings.Join(HasherNames(), ", "))
+		lg.Error(err)
+		return nil, err
+	}
+	return hasher, nil
+}
+
+// Prepare gets a user's password ready to send to the API. A password that has a hash function is
+// treated as already hashed and is checked rather than hashed again. Any other password is hashed
+// by hasher.
+func Prepare(user *admin.User, hasher Hasher) error {
+	lg.Debug("starting Prepare()")
+	defer lg.Debug("finished Prepare()")
+
+	if user.Password == "" {
+		return nil
+	}
+
+	if user.HashFunction != "" {
+		hashFunction, err := validateHash(user.Password, user.HashFunction)
+		if err != nil {
+			return err
+		}
+		user.HashFunction = hashFunction
+		return nil
+	}
+
+	hash, err := hasher.Hash(user.Password)
+	if err != nil {
+		return err
+	}
+	user.Password = hash
+	user.HashFunction = hasher.HashFunction()
+	return nil
+}
+
+// RegisterHasher adds a hasher or replaces an existing one with the same name
+func RegisterHasher(name string, hasher Hasher) {
+	hashersMu.Lock()
+	defer hashersMu.Unlock()
+
+	hashers[strings.ToLower(name)] = hasher
+}
+
+// Hash implements Hasher
+func (h hexHasher) Hash(password string) (string, error) {
+	if h.hashFunction == HASHMD5 {
+		sum := md5.Sum([]byte(password))
+		return hex.EncodeToString(sum[:]), nil
+	}
+	sum := sha1.Sum([]byte(password))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// HashFunction implements Hasher
+func (h hexHasher) HashFunction() string {
+	return h.hashFunction
+}
+
+// Hash implements Hasher
+func (h sha512CryptHasher) Hash(password string) (string, error) {
+	salt := make([]byte, SHA512CRYPTSALTLEN)
+	for idx := range salt {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(cryptAlphabet))))
+		if err != nil {
+			lg.Error(err)
+			return "", err
+		}
+		salt[idx] = cryptAlphabet[n.Int64()]
+	}
+	return sha512Crypt([]byte(password), salt, SHA512CRYPTROUNDS), nil
+}
+
+// HashFunction implements Hasher
+func (h sha512CryptHasher) HashFunction() string {
+	return HASHCRYPT
+}
+
+// sha512Crypt implements the SHA-512 crypt algorithm described at
+// https://www.akkadia.org/drepper/SHA-crypt.txt
+func sha512Crypt(password []byte, salt []byte, rounds int) string {
+	pwdLen := len(password)
+
+	altDigest := sha512.New()
+	altDigest.Write(password)
+	altDigest.Write(salt)
+	altDigest.Write(password)
+	alt := altDigest.Sum(nil)
+
+	digest := sha512.New()
+	digest.Write(password)
+	digest.Write(salt)
+	for n := pwdLen; n > 0; n -= sha512.Size {
+		if n > sha512.Size {
+			digest.Write(alt)
+			continue
+		}
+		digest.Write(alt[:n])
+	}
+	for n := pwdLen; n > 0; n >>= 1 {
+		if n&1 != 0 {
+			digest.Write(alt)
+			continue
+		}
+		digest.Write(password)
+	}
+	sum := digest.Sum(nil)
+
+	pDigest := sha512.New()
+	for n := 0; n < pwdLen; n++ {
+		pDigest.Write(password)
+	}
+	pBytes := repeatBytes(pDigest.Sum(nil), pwdLen)
+
+	sDigest := sha512.New()
+	for n := 0; n < 16+int(sum[0]); n++ {
+		sDigest.Write(salt)
+	}
+	sBytes := repeatBytes(sDigest.Sum(nil), len(salt))
+
+	for r := 0; r < rounds; r++ {
+		rDigest := sha512.New()
+		if r&1 != 0 {
+			rDigest.Write(pBytes)
+		} else {
+			rDigest.Write(sum)
+		}
+		if r%3 != 0 {
+			rDigest.Write(sBytes)
+		}
+		if r%7 != 0 {
+			rDigest.Write(pBytes)
+		}
+		if r&1 != 0 {
+			rDigest.Write(sum)
+		} else {
+			rDigest.Write(pBytes)
+		}
+		sum = rDigest.Sum(nil)
+	}
+
+	prefix := "$6$"
+	if rounds != SHA512CRYPTROUNDS {
+		prefix = fmt.Sprintf("$6$rounds=%d$", rounds)
+	}
+	return prefix + string(salt) + "$" + sha512CryptEncode(sum)
+}
+
+// sha512CryptEncode encodes a SHA-512 crypt digest using the crypt byte order and alphabet
+func sha512CryptEncode(sum []byte) string {
+	var sb strings.Builder
+
+	encode := func(b2 byte, b1 byte, b0 byte, n int) {
+		w := uint(b2)<<16 | uint(b1)<<8 | uint(b0)
+		for ; n > 0; n-- {
+			sb.WriteByte(cryptAlphabet[w&0x3f])
+			w >>= 6
+		}
+	}
+
+	for idx := 0; idx < 21; idx++ {
+		// Each group takes bytes that are 21 apart, rotating which one comes first
+		b := [3]int{idx, idx + 21, idx + 42}
+		switch idx % 3 {
+		case 1:
+			b = [3]int{idx + 21, idx + 42, idx}
+		case 2:
+			b = [3]int{idx + 42, idx, idx + 21}
+		}
+		encode(sum[b[0]], sum[b[1]], sum[b[2]], 4)
+	}
+	encode(0, 0, sum[63], 2)
+	return sb.String()
+}
+
+func repeatBytes(src []byte, length int) []byte {
+	out := make([]byte, 0, length)
+	for len(out) < length {
+		n := length - len(out)
+		if n > len(src) {
+			n = len(src)
+		}
+		out = append(out, src[:n]...)
+	}
+	return out
+}
+
+// validateHash checks that an already hashed password looks like a hash made by the hash function
+// and returns the hash function in the form that the API expects
+func validateHash(hash string, hashFunction string) (string, error) {
+	lg.Debugw("starting validateHash()",
+		"hashFunction", hashFunction)
+	defer lg.Debug("finished validateHash()")
+
+	var validHF string
+	for _, hf := range ValidHashFunctions {
+		if strings.EqualFold(hf, hashFunction) {
+			validHF = hf
+		}
+	}
+	if validHF == "" {
+		err := fmt.Errorf(gmess.ERR_INVALIDHASHFUNCTION, hashFunction, strings.Join(ValidHashFunctions, ", "))
+		lg.Error(err)
+		return "", err
+	}
+
+	var ok bool
+	switch validHF {
+	case HASHCRYPT:
+		ok = strings.HasPrefix(hash, "$")
+	case HASHMD5:
+		ok = len(hash) == md5.Size*2 && hexRegex.MatchString(hash)
+	case HASHSHA1:
+		ok = len(hash) == sha1.Size*2 && hexRegex.MatchString(hash)
+	}
+	if !ok {
+		err := fmt.Errorf(gmess.ERR_INVALIDPWDHASH, validHF)
+		lg.Error(err)
+		return "", err
+	}
+	return validHF, nil
+}
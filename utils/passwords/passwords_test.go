@@ -0,0 +1,205 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package passwords
+
+import (
+	"strings"
+	"testing"
+
+	tsts "github.com/plusworx/gmin/tests"
+	lg "github.com/plusworx/gmin/utils/logging"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+type testHasher struct{}
+
+func (h testHasher) Hash(password string) (string, error) {
+	return "$test$" + password, nil
+}
+
+func (h testHasher) HashFunction() string {
+	return HASHCRYPT
+}
+
+func TestHashers(t *testing.T) {
+	cases := []struct {
+		expectedErr  string
+		expectedHF   string
+		expectedHash string
+		name         string
+	}{
+		{
+			expectedHF:   HASHSHA1,
+			expectedHash: "e1f7c050db42a86e4d358e8c1dcef57e3b4f2fc0",
+			name:         "SHA-1",
+		},
+		{
+			expectedHF:   HASHMD5,
+			expectedHash: "a74c74cabf0ddbd80ca0031aa55c2c83",
+			name:         "md5",
+		},
+		{
+			expectedHF:   HASHCRYPT,
+			expectedHash: "$6$",
+			name:         "",
+		},
+		{
+			expectedErr: "invalid password hasher: bcrypt - valid hashers are: crypt, md5, sha-1",
+			name:        "bcrypt",
+		},
+	}
+
+	tsts.InitConfig()
+	lg.InitLogging("info")
+
+	for _, c := range cases {
+		hasher, err := NewHasher(c.name)
+
+		gotErr := ""
+		if err != nil {
+			gotErr = err.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("Got error: %v - expected error: %v", gotErr, c.expectedErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		hash, _ := hasher.Hash("MySuperStrongPassword")
+		if !strings.HasPrefix(hash, c.expectedHash) || hasher.HashFunction() != c.expectedHF {
+			t.Errorf("Got hash: %v %v - expected hash: %v %v", hasher.HashFunction(), hash, c.expectedHF, c.expectedHash)
+		}
+	}
+}
+
+func TestPrepare(t *testing.T) {
+	cases := []struct {
+		expectedErr  string
+		expectedHF   string
+		expectedPwd  string
+		hashFunction string
+		password     string
+	}{
+		{
+			expectedHF:  HASHCRYPT,
+			expectedPwd: "$test$MySuperStrongPassword",
+			password:    "MySuperStrongPassword",
+		},
+		{
+			expectedHF:   HASHSHA1,
+			expectedPwd:  "e1f7c050db42a86e4d358e8c1dcef57e3b4f2fc0",
+			hashFunction: "sha-1",
+			password:     "e1f7c050db42a86e4d358e8c1dcef57e3b4f2fc0",
+		},
+		{
+			expectedHF:   HASHCRYPT,
+			expectedPwd:  "$6$saltstring$hash",
+			hashFunction: "CRYPT",
+			password:     "$6$saltstring$hash",
+		},
+		{
+			expectedErr:  "password is not a valid MD5 hash",
+			hashFunction: "MD5",
+			password:     "MySuperStrongPassword",
+		},
+		{
+			expectedErr:  "invalid hash function: SHA-256 - valid hash functions are: crypt, MD5, SHA-1",
+			hashFunction: "SHA-256",
+			password:     "MySuperStrongPassword",
+		},
+		{},
+	}
+
+	tsts.InitConfig()
+	lg.InitLogging("info")
+
+	for _, c := range cases {
+		user := &admin.User{HashFunction: c.hashFunction, Password: c.password}
+
+		err := Prepare(user, testHasher{})
+
+		gotErr := ""
+		if err != nil {
+			gotErr = err.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("Got error: %v - expected error: %v", gotErr, c.expectedErr)
+			continue
+		}
+		if err == nil && (user.Password != c.expectedPwd || user.HashFunction != c.expectedHF) {
+			t.Errorf("Got password: %v %v - expected password: %v %v", user.HashFunction, user.Password, c.expectedHF, c.expectedPwd)
+		}
+	}
+}
+
+func TestRegisterHasher(t *testing.T) {
+	tsts.InitConfig()
+	lg.InitLogging("info")
+
+	RegisterHasher("Test", testHasher{})
+	defer func() {
+		hashersMu.Lock()
+		delete(hashers, "test")
+		hashersMu.Unlock()
+	}()
+
+	hasher, err := NewHasher("TEST")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, _ := hasher.Hash("pwd")
+	if hash != "$test$pwd" {
+		t.Errorf("Got hash: %v - expected hash: $test$pwd", hash)
+	}
+}
+
+func TestSHA512Crypt(t *testing.T) {
+	cases := []struct {
+		expectedHash string
+		password     string
+		rounds       int
+		salt         string
+	}{
+		{
+			expectedHash: "$6$saltstring$svn8UoSVapNtMuq1ukKS4tPQd8iKwSMHWjl/O817G3uBnIFNjnQJuesI68u4OTLiBFdcbYEdFCoEOfaS35inz1",
+			password:     "Hello world!",
+			rounds:       5000,
+			salt:         "saltstring",
+		},
+		{
+			expectedHash: "$6$rounds=10000$saltstringsaltst$OW1/O6BYHV6BcXZu8QVeXbDWra3Oeqh0sbHbbMCVNSnCM/UrjmM0Dp8vOuZeHBy/YTBmSK6H9qs/y3RnOaw5v.",
+			password:     "Hello world!",
+			rounds:       10000,
+			salt:         "saltstringsaltst",
+		},
+	}
+
+	for _, c := range cases {
+		hash := sha512Crypt([]byte(c.password), []byte(c.salt), c.rounds)
+		if hash != c.expectedHash {
+			t.Errorf("Got hash: %v - expected hash: %v", hash, c.expectedHash)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package reports
+
+import (
+	"fmt"
+	"strings"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	reports "google.golang.org/api/admin/reports/v1"
+)
+
+// ActivityAttrMap provides lowercase mappings to valid reports.Activity attributes
+var ActivityAttrMap = map[string]string{
+	"actor":             "actor",
+	"applicationname":   "applicationName",
+	"boolvalue":         "boolValue",
+	"callertype":        "callerType",
+	"customerid":        "customerId",
+	"email":             "email",
+	"etag":              "etag",
+	"events":            "events",
+	"id":                "id",
+	"intvalue":          "intValue",
+	"ipaddress":         "ipAddress",
+	"key":               "key",
+	"kind":              "kind",
+	"messagevalue":      "messageValue",
+	"multiintvalue":     "multiIntValue",
+	"multimessagevalue": "multiMessageValue",
+	"multivalue":        "multiValue",
+	"name":              "name",
+	"ownerdomain":       "ownerDomain",
+	"parameters":        "parameters",
+	"profileid":         "profileId",
+	"time":              "time",
+	"type":              "type",
+	"uniquequalifier":   "uniqueQualifier",
+	"value":             "value",
+}
+
+var activityAttrs = []string{
+	"actor",
+	"etag",
+	"events",
+	"id",
+	"ipAddress",
+	"kind",
+	"ownerDomain",
+}
+
+var activityActorAttrs = []string{
+	"callerType",
+	"email",
+	"key",
+	"profileId",
+}
+
+var activityCompAttrs = []string{
+	"actor",
+	"events",
+	"id",
+}
+
+var activityEventsAttrs = []string{
+	"name",
+	"parameters",
+	"type",
+}
+
+var activityEventsParametersAttrs = []string{
+	"boolValue",
+	"intValue",
+	"messageValue",
+	"multiIntValue",
+	"multiMessageValue",
+	"multiValue",
+	"name",
+	"value",
+}
+
+var activityIDAttrs = []string{
+	"applicationName",
+	"customerId",
+	"time",
+	"uniqueQualifier",
+}
+
+// ValidApplications provide valid application names for reports.ActivitiesListCall
+var ValidApplications = []string{
+	"access_transparency",
+	"admin",
+	"calendar",
+	"chat",
+	"chrome",
+	"context_aware_access",
+	"data_studio",
+	"drive",
+	"gcp",
+	"gplus",
+	"groups",
+	"groups_enterprise",
+	"jamboard",
+	"login",
+	"meet",
+	"mobile",
+	"rules",
+	"saml",
+	"token",
+	"user_accounts",
+}
+
+// AddEndTime adds EndTime to reports.ActivitiesListCall
+func AddEndTime(alc *reports.ActivitiesListCall, endTime string) *reports.ActivitiesListCall {
+	lg.Debugw("starting AddEndTime()",
+		"endTime", endTime)
+	defer lg.Debug("finished AddEndTime()")
+
+	var newALC *reports.ActivitiesListCall
+
+	newALC = alc.EndTime(endTime)
+
+	return newALC
+}
+
+// AddEventName adds EventName to reports.ActivitiesListCall
+func AddEventName(alc *reports.ActivitiesListCall, eventName string) *reports.ActivitiesListCall {
+	lg.Debugw("starting AddEventName()",
+		"eventName", eventName)
+	defer lg.Debug("finished AddEventName()")
+
+	var newALC *reports.ActivitiesListCall
+
+	newALC = alc.EventName(eventName)
+
+	return newALC
+}
+
+// AddStartTime adds StartTime to reports.ActivitiesListCall
+func AddStartTime(alc *reports.ActivitiesListCall, startTime string) *reports.ActivitiesListCall {
+	lg.Debugw("starting AddStartTime()",
+		"startTime", startTime)
+	defer lg.Debug("finished AddStartTime()")
+
+	var newALC *reports.ActivitiesListCall
+
+	newALC = alc.StartTime(startTime)
+
+	return newALC
+}
+
+// DoActivitiesList calls the .Do() function on the reports.ActivitiesListCall
+func DoActivitiesList(alc *reports.ActivitiesListCall) (*reports.Activities, error) {
+	lg.Debug("starting DoActivitiesList()")
+	defer lg.Debug("finished DoActivitiesList()")
+
+	activities, err := alc.Do()
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	return activities, nil
+}
+
+// ShowActivityAttrs displays requested activity attributes
+func ShowActivityAttrs(filter string) {
+	lg.Debugw("starting ShowActivityAttrs()",
+		"filter", filter)
+	defer lg.Debug("finished ShowActivityAttrs()")
+
+	showAttrs(activityAttrs, activityCompAttrs, ActivityAttrMap, filter)
+}
+
+// ShowActivityCompAttrs displays activity composite attributes
+func ShowActivityCompAttrs(filter string) {
+	lg.Debugw("starting ShowActivityCompAttrs()",
+		"filter", filter)
+	defer lg.Debug("finished ShowActivityCompAttrs()")
+
+	cmn.ShowAttrs(activityCompAttrs, ActivityAttrMap, filter)
+}
+
+// ShowActivitySubAttrs displays attributes of activity composite attributes
+func ShowActivitySubAttrs(compAttr string, filter string) error {
+	lg.Debugw("starting ShowActivitySubAttrs()",
+		"compAttr", compAttr,
+		"filter", filter)
+	defer lg.Debug("finished ShowActivitySubAttrs()")
+
+	switch strings.ToLower(compAttr) {
+	case "actor":
+		cmn.ShowAttrs(activityActorAttrs, ActivityAttrMap, filter)
+	case "events":
+		showAttrs(activityEventsAttrs, []string{"parameters"}, ActivityAttrMap, filter)
+	case "id":
+		cmn.ShowAttrs(activityIDAttrs, ActivityAttrMap, filter)
+	case "parameters":
+		cmn.ShowAttrs(activityEventsParametersAttrs, ActivityAttrMap, filter)
+	default:
+		err := fmt.Errorf(gmess.ERR_NOTCOMPOSITEATTR, compAttr)
+		lg.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// ValidateApplication checks that an activity application name is valid and returns it in lowercase
+func ValidateApplication(application string) (string, error) {
+	lg.Debugw("starting ValidateApplication()",
+		"application", application)
+	defer lg.Debug("finished ValidateApplication()")
+
+	lwrApp := strings.ToLower(application)
+	if !cmn.SliceContainsStr(ValidApplications, lwrApp) {
+		err := fmt.Errorf(gmess.ERR_INVALIDAPPLICATION, application, strings.Join(ValidApplications, ", "))
+		lg.Error(err)
+		return "", err
+	}
+	return lwrApp, nil
+}
@@ -0,0 +1,234 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package reports
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	gpars "github.com/plusworx/gmin/utils/gminparsers"
+	lg "github.com/plusworx/gmin/utils/logging"
+	reports "google.golang.org/api/admin/reports/v1"
+	"google.golang.org/api/googleapi"
+)
+
+const (
+	// ACTIVITYLISTKEY is name of activity List call results attribute
+	ACTIVITYLISTKEY string = "items"
+	// ALLUSERS is the user key that gets activities or usage for every user
+	ALLUSERS string = "all"
+	// ENDFIELD is List call attribute string terminator
+	ENDFIELD string = ")"
+	// REPORTDATEFORMAT is the layout of usage report dates
+	REPORTDATEFORMAT string = "2006-01-02"
+	// STARTACTIVITIESFIELD is activity List call attribute string prefix
+	STARTACTIVITIESFIELD string = "items("
+	// STARTUSAGEREPORTSFIELD is usage report Get call attribute string prefix
+	STARTUSAGEREPORTSFIELD string = "usageReports("
+	// USAGELISTKEY is name of usage report Get call results attribute
+	USAGELISTKEY string = "usageReports"
+)
+
+// AddFields adds fields to be returned from reports calls
+func AddFields(callObj interface{}, attrs string) interface{} {
+	lg.Debugw("starting AddFields()",
+		"attrs", attrs)
+	defer lg.Debug("finished AddFields()")
+
+	var fields googleapi.Field = googleapi.Field(attrs)
+
+	switch callObj.(type) {
+	case *reports.ActivitiesListCall:
+		var newALC *reports.ActivitiesListCall
+		alc := callObj.(*reports.ActivitiesListCall)
+		newALC = alc.Fields(fields)
+
+		return newALC
+	case *reports.CustomerUsageReportsGetCall:
+		var newCUGC *reports.CustomerUsageReportsGetCall
+		cugc := callObj.(*reports.CustomerUsageReportsGetCall)
+		newCUGC = cugc.Fields(fields)
+
+		return newCUGC
+	case *reports.UserUsageReportGetCall:
+		var newUUGC *reports.UserUsageReportGetCall
+		uugc := callObj.(*reports.UserUsageReportGetCall)
+		newUUGC = uugc.Fields(fields)
+
+		return newUUGC
+	}
+	return nil
+}
+
+// AddFilters adds Filters to reports calls
+func AddFilters(callObj interface{}, filters string) interface{} {
+	lg.Debugw("starting AddFilters()",
+		"filters", filters)
+	defer lg.Debug("finished AddFilters()")
+
+	switch callObj.(type) {
+	case *reports.ActivitiesListCall:
+		var newALC *reports.ActivitiesListCall
+		alc := callObj.(*reports.ActivitiesListCall)
+		newALC = alc.Filters(filters)
+
+		return newALC
+	case *reports.UserUsageReportGetCall:
+		var newUUGC *reports.UserUsageReportGetCall
+		uugc := callObj.(*reports.UserUsageReportGetCall)
+		newUUGC = uugc.Filters(filters)
+
+		return newUUGC
+	}
+	return nil
+}
+
+// AddMaxResults adds MaxResults to reports calls
+func AddMaxResults(callObj interface{}, maxResults int64) interface{} {
+	lg.Debugw("starting AddMaxResults()",
+		"maxResults", maxResults)
+	defer lg.Debug("finished AddMaxResults()")
+
+	switch callObj.(type) {
+	case *reports.ActivitiesListCall:
+		var newALC *reports.ActivitiesListCall
+		alc := callObj.(*reports.ActivitiesListCall)
+		newALC = alc.MaxResults(maxResults)
+
+		return newALC
+	case *reports.UserUsageReportGetCall:
+		var newUUGC *reports.UserUsageReportGetCall
+		uugc := callObj.(*reports.UserUsageReportGetCall)
+		newUUGC = uugc.MaxResults(maxResults)
+
+		return newUUGC
+	}
+	return nil
+}
+
+// AddPageToken adds PageToken to reports calls
+func AddPageToken(callObj interface{}, token string) interface{} {
+	lg.Debugw("starting AddPageToken()",
+		"token", token)
+	defer lg.Debug("finished AddPageToken()")
+
+	switch callObj.(type) {
+	case *reports.ActivitiesListCall:
+		var newALC *reports.ActivitiesListCall
+		alc := callObj.(*reports.ActivitiesListCall)
+		newALC = alc.PageToken(token)
+
+		return newALC
+	case *reports.CustomerUsageReportsGetCall:
+		var newCUGC *reports.CustomerUsageReportsGetCall
+		cugc := callObj.(*reports.CustomerUsageReportsGetCall)
+		newCUGC = cugc.PageToken(token)
+
+		return newCUGC
+	case *reports.UserUsageReportGetCall:
+		var newUUGC *reports.UserUsageReportGetCall
+		uugc := callObj.(*reports.UserUsageReportGetCall)
+		newUUGC = uugc.PageToken(token)
+
+		return newUUGC
+	}
+	return nil
+}
+
+// AddParameters adds Parameters to usage report calls
+func AddParameters(callObj interface{}, parameters string) interface{} {
+	lg.Debugw("starting AddParameters()",
+		"parameters", parameters)
+	defer lg.Debug("finished AddParameters()")
+
+	switch callObj.(type) {
+	case *reports.CustomerUsageReportsGetCall:
+		var newCUGC *reports.CustomerUsageReportsGetCall
+		cugc := callObj.(*reports.CustomerUsageReportsGetCall)
+		newCUGC = cugc.Parameters(parameters)
+
+		return newCUGC
+	case *reports.UserUsageReportGetCall:
+		var newUUGC *reports.UserUsageReportGetCall
+		uugc := callObj.(*reports.UserUsageReportGetCall)
+		newUUGC = uugc.Parameters(parameters)
+
+		return newUUGC
+	}
+	return nil
+}
+
+// ParseReportTime converts an RFC 3339 time, or a duration before now like 7d, into the RFC 3339
+// time that reports calls expect
+func ParseReportTime(val string, now time.Time) (string, error) {
+	lg.Debugw("starting ParseReportTime()",
+		"val", val)
+	defer lg.Debug("finished ParseReportTime()")
+
+	t, err := time.Parse(time.RFC3339, val)
+	if err == nil {
+		return t.UTC().Format(time.RFC3339), nil
+	}
+
+	dur, err := gpars.ParseDuration(val)
+	if err != nil || dur < 0 {
+		err = fmt.Errorf(gmess.ERR_INVALIDREPORTTIME, val)
+		lg.Error(err)
+		return "", err
+	}
+	return now.Add(-dur).UTC().Format(time.RFC3339), nil
+}
+
+// ValidateReportDate checks that a usage report date is in the format YYYY-MM-DD
+func ValidateReportDate(date string) error {
+	lg.Debugw("starting ValidateReportDate()",
+		"date", date)
+	defer lg.Debug("finished ValidateReportDate()")
+
+	_, err := time.Parse(REPORTDATEFORMAT, date)
+	if err != nil {
+		err = fmt.Errorf(gmess.ERR_INVALIDREPORTDATE, date)
+		lg.Error(err)
+		return err
+	}
+	return nil
+}
+
+// showAttrs displays attributes with composite attributes marked by *
+func showAttrs(attrs []string, compAttrs []string, attrMap map[string]string, filter string) {
+	for _, a := range attrs {
+		lwrA := strings.ToLower(a)
+		if filter != "" && !strings.Contains(lwrA, strings.ToLower(filter)) {
+			continue
+		}
+		s, _ := cmn.IsValidAttr(lwrA, attrMap)
+		if cmn.SliceContainsStr(compAttrs, s) {
+			fmt.Println("* ", s)
+			continue
+		}
+		fmt.Println(s)
+	}
+}
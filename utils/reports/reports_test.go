@@ -0,0 +1,171 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package reports
+
+import (
+	"testing"
+	"time"
+
+	tsts "github.com/plusworx/gmin/tests"
+	lg "github.com/plusworx/gmin/utils/logging"
+)
+
+func TestFormatParameters(t *testing.T) {
+	cases := []struct {
+		expectedParams string
+		params         string
+	}{
+		{
+			expectedParams: "accounts:last_login_time",
+			params:         "accounts:last_login_time",
+		},
+		{
+			expectedParams: "accounts:last_login_time,gmail:num_emails_sent",
+			params:         "accounts:last_login_time~gmail:num_emails_sent",
+		},
+	}
+
+	tsts.InitConfig()
+	lg.InitLogging("info")
+
+	for _, c := range cases {
+		params := FormatParameters(c.params)
+		if params != c.expectedParams {
+			t.Errorf("Got parameters: %v - expected parameters: %v", params, c.expectedParams)
+		}
+	}
+}
+
+func TestParseReportTime(t *testing.T) {
+	now := time.Date(2020, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		expectedErr  string
+		expectedTime string
+		val          string
+	}{
+		{
+			expectedTime: "2020-10-01T09:30:00Z",
+			val:          "2020-10-01T10:30:00+01:00",
+		},
+		{
+			expectedTime: "2020-10-09T12:00:00Z",
+			val:          "7d",
+		},
+		{
+			expectedTime: "2020-10-16T00:00:00Z",
+			val:          "12h",
+		},
+		{
+			expectedErr: "invalid report time: yesterday - must be an RFC 3339 time or a duration before now like 7d",
+			val:         "yesterday",
+		},
+		{
+			expectedErr: "invalid report time: 2020-10-01 - must be an RFC 3339 time or a duration before now like 7d",
+			val:         "2020-10-01",
+		},
+	}
+
+	tsts.InitConfig()
+	lg.InitLogging("info")
+
+	for _, c := range cases {
+		got, err := ParseReportTime(c.val, now)
+
+		gotErr := ""
+		if err != nil {
+			gotErr = err.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("Got error: %v - expected error: %v", gotErr, c.expectedErr)
+		}
+		if got != c.expectedTime {
+			t.Errorf("Got time: %v - expected time: %v", got, c.expectedTime)
+		}
+	}
+}
+
+func TestValidateApplication(t *testing.T) {
+	cases := []struct {
+		application         string
+		expectedApplication string
+		expectedErr         string
+	}{
+		{
+			application:         "Login",
+			expectedApplication: "login",
+		},
+		{
+			application: "gmail",
+			expectedErr: "invalid application: gmail - valid applications are: access_transparency, admin, calendar, chat, chrome, context_aware_access, data_studio, drive, gcp, gplus, groups, groups_enterprise, jamboard, login, meet, mobile, rules, saml, token, user_accounts",
+		},
+	}
+
+	tsts.InitConfig()
+	lg.InitLogging("info")
+
+	for _, c := range cases {
+		application, err := ValidateApplication(c.application)
+
+		gotErr := ""
+		if err != nil {
+			gotErr = err.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("Got error: %v - expected error: %v", gotErr, c.expectedErr)
+		}
+		if application != c.expectedApplication {
+			t.Errorf("Got application: %v - expected application: %v", application, c.expectedApplication)
+		}
+	}
+}
+
+func TestValidateReportDate(t *testing.T) {
+	cases := []struct {
+		date        string
+		expectedErr string
+	}{
+		{
+			date: "2020-10-12",
+		},
+		{
+			date:        "12/10/2020",
+			expectedErr: "invalid report date: 12/10/2020 - must be in the format YYYY-MM-DD",
+		},
+	}
+
+	tsts.InitConfig()
+	lg.InitLogging("info")
+
+	for _, c := range cases {
+		err := ValidateReportDate(c.date)
+
+		gotErr := ""
+		if err != nil {
+			gotErr = err.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("Got error: %v - expected error: %v", gotErr, c.expectedErr)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package reports
+
+import (
+	"fmt"
+	"strings"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	reports "google.golang.org/api/admin/reports/v1"
+)
+
+// UsageAttrMap provides lowercase mappings to valid reports.UsageReport attributes
+var UsageAttrMap = map[string]string{
+	"boolvalue":     "boolValue",
+	"customerid":    "customerId",
+	"date":          "date",
+	"datetimevalue": "datetimeValue",
+	"entity":        "entity",
+	"entityid":      "entityId",
+	"etag":          "etag",
+	"intvalue":      "intValue",
+	"kind":          "kind",
+	"msgvalue":      "msgValue",
+	"name":          "name",
+	"parameters":    "parameters",
+	"profileid":     "profileId",
+	"stringvalue":   "stringValue",
+	"type":          "type",
+	"useremail":     "userEmail",
+}
+
+var usageAttrs = []string{
+	"date",
+	"entity",
+	"etag",
+	"kind",
+	"parameters",
+}
+
+var usageCompAttrs = []string{
+	"entity",
+	"parameters",
+}
+
+var usageEntityAttrs = []string{
+	"customerId",
+	"entityId",
+	"profileId",
+	"type",
+	"userEmail",
+}
+
+var usageParametersAttrs = []string{
+	"boolValue",
+	"datetimeValue",
+	"intValue",
+	"msgValue",
+	"name",
+	"stringValue",
+}
+
+// DoCustomerUsageGet calls the .Do() function on the reports.CustomerUsageReportsGetCall
+func DoCustomerUsageGet(cugc *reports.CustomerUsageReportsGetCall) (*reports.UsageReports, error) {
+	lg.Debug("starting DoCustomerUsageGet()")
+	defer lg.Debug("finished DoCustomerUsageGet()")
+
+	usageReports, err := cugc.Do()
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	return usageReports, nil
+}
+
+// DoUserUsageGet calls the .Do() function on the reports.UserUsageReportGetCall
+func DoUserUsageGet(uugc *reports.UserUsageReportGetCall) (*reports.UsageReports, error) {
+	lg.Debug("starting DoUserUsageGet()")
+	defer lg.Debug("finished DoUserUsageGet()")
+
+	usageReports, err := uugc.Do()
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	return usageReports, nil
+}
+
+// FormatParameters converts usage parameters separated by ~ into the comma separated list that usage
+// report calls expect
+func FormatParameters(params string) string {
+	lg.Debugw("starting FormatParameters()",
+		"params", params)
+	defer lg.Debug("finished FormatParameters()")
+
+	return strings.Join(strings.Split(params, "~"), ",")
+}
+
+// ShowUsageAttrs displays requested usage report attributes
+func ShowUsageAttrs(filter string) {
+	lg.Debugw("starting ShowUsageAttrs()",
+		"filter", filter)
+	defer lg.Debug("finished ShowUsageAttrs()")
+
+	showAttrs(usageAttrs, usageCompAttrs, UsageAttrMap, filter)
+}
+
+// ShowUsageCompAttrs displays usage report composite attributes
+func ShowUsageCompAttrs(filter string) {
+	lg.Debugw("starting ShowUsageCompAttrs()",
+		"filter", filter)
+	defer lg.Debug("finished ShowUsageCompAttrs()")
+
+	cmn.ShowAttrs(usageCompAttrs, UsageAttrMap, filter)
+}
+
+// ShowUsageSubAttrs displays attributes of usage report composite attributes
+func ShowUsageSubAttrs(compAttr string, filter string) error {
+	lg.Debugw("starting ShowUsageSubAttrs()",
+		"compAttr", compAttr,
+		"filter", filter)
+	defer lg.Debug("finished ShowUsageSubAttrs()")
+
+	switch strings.ToLower(compAttr) {
+	case "entity":
+		cmn.ShowAttrs(usageEntityAttrs, UsageAttrMap, filter)
+	case "parameters":
+		cmn.ShowAttrs(usageParametersAttrs, UsageAttrMap, filter)
+	default:
+		err := fmt.Errorf(gmess.ERR_NOTCOMPOSITEATTR, compAttr)
+		lg.Error(err)
+		return err
+	}
+
+	return nil
+}
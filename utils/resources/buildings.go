@@ -0,0 +1,255 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package resources
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+// BuildingAttrMap provides lowercase mappings to valid admin.Building attributes
+var BuildingAttrMap = map[string]string{
+	"address":            "address",
+	"addresslines":       "addressLines",
+	"administrativearea": "administrativeArea",
+	"buildingid":         "buildingId",
+	"buildingname":       "buildingName",
+	"coordinates":        "coordinates",
+	"description":        "description",
+	"etags":              "etags",
+	"floornames":         "floorNames",
+	"forcesendfields":    "forceSendFields",
+	"kind":               "kind",
+	"languagecode":       "languageCode",
+	"latitude":           "latitude",
+	"locality":           "locality",
+	"longitude":          "longitude",
+	"postalcode":         "postalCode",
+	"regioncode":         "regionCode",
+	"sublocality":        "sublocality",
+}
+
+var buildingAttrs = []string{
+	"address",
+	"buildingId",
+	"buildingName",
+	"coordinates",
+	"description",
+	"etags",
+	"floorNames",
+	"kind",
+}
+
+var addressAttrs = []string{
+	"addressLines",
+	"administrativeArea",
+	"languageCode",
+	"locality",
+	"postalCode",
+	"regionCode",
+	"sublocality",
+}
+
+var buildingCompAttrs = []string{
+	"address",
+	"coordinates",
+}
+
+var coordinatesAttrs = []string{
+	"latitude",
+	"longitude",
+}
+
+// FloorNames makes building floor names from names separated by (~)
+func FloorNames(names string) []string {
+	lg.Debugw("starting FloorNames()",
+		"names", names)
+	defer lg.Debug("finished FloorNames()")
+
+	return splitList(names)
+}
+
+// PopulateBuilding is used in batch processing
+func PopulateBuilding(building *admin.Building, hdrMap map[int]string, objData []interface{}) error {
+	lg.Debugw("starting PopulateBuilding()",
+		"hdrMap", hdrMap)
+	defer lg.Debug("finished PopulateBuilding()")
+
+	err := populateBuilding(building, hdrMap, objData, false)
+	if err != nil {
+		return err
+	}
+
+	if building.BuildingName == "" {
+		err = fmt.Errorf(gmess.ERR_EMPTYSTRING, "buildingName")
+		lg.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// PopulateBuildingForUpdate is used in batch processing
+func PopulateBuildingForUpdate(building *admin.Building, hdrMap map[int]string, objData []interface{}) error {
+	lg.Debugw("starting PopulateBuildingForUpdate()",
+		"hdrMap", hdrMap)
+	defer lg.Debug("finished PopulateBuildingForUpdate()")
+
+	return populateBuilding(building, hdrMap, objData, true)
+}
+
+func populateBuilding(building *admin.Building, hdrMap map[int]string, objData []interface{}, update bool) error {
+	for idx, attr := range objData {
+		attrName := hdrMap[idx]
+		attrVal := fmt.Sprintf("%v", attr)
+
+		switch {
+		case cmn.SliceContainsStr(addressAttrs, attrName):
+			if building.Address == nil {
+				building.Address = new(admin.BuildingAddress)
+			}
+			setAddressAttr(building.Address, attrName, attrVal)
+			if update && attrVal == "" {
+				building.Address.ForceSendFields = append(building.Address.ForceSendFields, strings.Title(attrName))
+			}
+			continue
+		case cmn.SliceContainsStr(coordinatesAttrs, attrName):
+			if attrVal == "" {
+				continue
+			}
+			coord, err := strconv.ParseFloat(attrVal, 64)
+			if err != nil {
+				err = fmt.Errorf(gmess.ERR_INVALIDNUMBER, attrName, attrVal)
+				lg.Error(err)
+				return err
+			}
+			if building.Coordinates == nil {
+				building.Coordinates = new(admin.BuildingCoordinates)
+			}
+			if attrName == "latitude" {
+				building.Coordinates.Latitude = coord
+			} else {
+				building.Coordinates.Longitude = coord
+			}
+			continue
+		case attrName == "buildingId":
+			if attrVal == "" {
+				err := fmt.Errorf(gmess.ERR_EMPTYSTRING, attrName)
+				lg.Error(err)
+				return err
+			}
+			building.BuildingId = attrVal
+			continue
+		case attrName == "buildingName":
+			if attrVal == "" && update {
+				err := fmt.Errorf(gmess.ERR_EMPTYSTRING, attrName)
+				lg.Error(err)
+				return err
+			}
+			building.BuildingName = attrVal
+		case attrName == "description":
+			building.Description = attrVal
+		case attrName == "floorNames":
+			building.FloorNames = FloorNames(attrVal)
+		default:
+			err := fmt.Errorf(gmess.ERR_ATTRNOTRECOGNIZED, attrName)
+			return err
+		}
+
+		if update && attrVal == "" {
+			building.ForceSendFields = append(building.ForceSendFields, strings.Title(attrName))
+		}
+	}
+
+	if building.BuildingId == "" {
+		err := fmt.Errorf(gmess.ERR_EMPTYSTRING, BUILDINGKEYNAME)
+		lg.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+func setAddressAttr(address *admin.BuildingAddress, attrName string, attrVal string) {
+	switch attrName {
+	case "addressLines":
+		address.AddressLines = splitList(attrVal)
+	case "administrativeArea":
+		address.AdministrativeArea = attrVal
+	case "languageCode":
+		address.LanguageCode = attrVal
+	case "locality":
+		address.Locality = attrVal
+	case "postalCode":
+		address.PostalCode = attrVal
+	case "regionCode":
+		address.RegionCode = attrVal
+	case "sublocality":
+		address.Sublocality = attrVal
+	}
+}
+
+// ShowBuildingAttrs displays requested building attributes
+func ShowBuildingAttrs(filter string) {
+	lg.Debugw("starting ShowBuildingAttrs()",
+		"filter", filter)
+	defer lg.Debug("finished ShowBuildingAttrs()")
+
+	cmn.ShowAttrs(buildingAttrs, BuildingAttrMap, filter)
+}
+
+// ShowBuildingCompAttrs displays building composite attributes
+func ShowBuildingCompAttrs(filter string) {
+	lg.Debugw("starting ShowBuildingCompAttrs()",
+		"filter", filter)
+	defer lg.Debug("finished ShowBuildingCompAttrs()")
+
+	cmn.ShowAttrs(buildingCompAttrs, BuildingAttrMap, filter)
+}
+
+// ShowBuildingSubAttrs displays attributes of building composite attributes
+func ShowBuildingSubAttrs(compAttr string, filter string) error {
+	lg.Debugw("starting ShowBuildingSubAttrs()",
+		"compAttr", compAttr,
+		"filter", filter)
+	defer lg.Debug("finished ShowBuildingSubAttrs()")
+
+	switch strings.ToLower(compAttr) {
+	case "address":
+		cmn.ShowAttrs(addressAttrs, BuildingAttrMap, filter)
+	case "coordinates":
+		cmn.ShowAttrs(coordinatesAttrs, BuildingAttrMap, filter)
+	default:
+		err := fmt.Errorf(gmess.ERR_NOTCOMPOSITEATTR, compAttr)
+		lg.Error(err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,213 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package resources
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+// CalendarAttrMap provides lowercase mappings to valid admin.CalendarResource attributes
+var CalendarAttrMap = map[string]string{
+	"buildingid":             "buildingId",
+	"capacity":               "capacity",
+	"etags":                  "etags",
+	"feature":                "feature", // Used in featureInstances
+	"featureinstances":       "featureInstances",
+	"floorname":              "floorName",
+	"floorsection":           "floorSection",
+	"forcesendfields":        "forceSendFields",
+	"generatedresourcename":  "generatedResourceName",
+	"kind":                   "kind",
+	"name":                   "name", // Used in featureInstances
+	"resourcecategory":       "resourceCategory",
+	"resourcedescription":    "resourceDescription",
+	"resourceemail":          "resourceEmail",
+	"resourceid":             "resourceId",
+	"resourcename":           "resourceName",
+	"resourcetype":           "resourceType",
+	"uservisibledescription": "userVisibleDescription",
+}
+
+// QueryAttrMap provides lowercase mappings to valid admin.CalendarResource query attributes
+var QueryAttrMap = map[string]string{
+	"buildingid":            "buildingId",
+	"capacity":              "capacity",
+	"featurename":           "featureInstances.feature.name",
+	"floorname":             "floor_name",
+	"generatedresourcename": "generatedResourceName",
+	"name":                  "name",
+	"resourcename":          "name",
+}
+
+// ValidOrderByStrs provide valid strings to be used to set admin.ResourcesCalendarsListCall OrderBy
+var ValidOrderByStrs = []string{
+	"buildingid",
+	"capacity",
+	"floorname",
+	"resourceid",
+	"resourcename",
+}
+
+var calendarAttrs = []string{
+	"buildingId",
+	"capacity",
+	"etags",
+	"featureInstances",
+	"floorName",
+	"floorSection",
+	"generatedResourceName",
+	"kind",
+	"resourceCategory",
+	"resourceDescription",
+	"resourceEmail",
+	"resourceId",
+	"resourceName",
+	"resourceType",
+	"userVisibleDescription",
+}
+
+// FeatureInstances makes calendar resource feature instances from feature names separated by (~)
+func FeatureInstances(names string) []interface{} {
+	lg.Debugw("starting FeatureInstances()",
+		"names", names)
+	defer lg.Debug("finished FeatureInstances()")
+
+	instances := []interface{}{}
+
+	for _, name := range splitList(names) {
+		instances = append(instances, admin.FeatureInstance{Feature: &admin.Feature{Name: name}})
+	}
+
+	return instances
+}
+
+// PopulateCalendar is used in batch processing
+func PopulateCalendar(calendar *admin.CalendarResource, hdrMap map[int]string, objData []interface{}) error {
+	lg.Debugw("starting PopulateCalendar()",
+		"hdrMap", hdrMap)
+	defer lg.Debug("finished PopulateCalendar()")
+
+	err := populateCalendar(calendar, hdrMap, objData, false)
+	if err != nil {
+		return err
+	}
+
+	if calendar.ResourceName == "" {
+		err = fmt.Errorf(gmess.ERR_EMPTYSTRING, "resourceName")
+		lg.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// PopulateCalendarForUpdate is used in batch processing
+func PopulateCalendarForUpdate(calendar *admin.CalendarResource, hdrMap map[int]string, objData []interface{}) error {
+	lg.Debugw("starting PopulateCalendarForUpdate()",
+		"hdrMap", hdrMap)
+	defer lg.Debug("finished PopulateCalendarForUpdate()")
+
+	return populateCalendar(calendar, hdrMap, objData, true)
+}
+
+func populateCalendar(calendar *admin.CalendarResource, hdrMap map[int]string, objData []interface{}, update bool) error {
+	for idx, attr := range objData {
+		attrName := hdrMap[idx]
+		attrVal := fmt.Sprintf("%v", attr)
+
+		switch {
+		case attrName == "buildingId":
+			calendar.BuildingId = attrVal
+		case attrName == "capacity":
+			if attrVal == "" {
+				break
+			}
+			capacity, err := strconv.ParseInt(attrVal, 10, 64)
+			if err != nil {
+				err = fmt.Errorf(gmess.ERR_INVALIDNUMBER, attrName, attrVal)
+				lg.Error(err)
+				return err
+			}
+			calendar.Capacity = capacity
+		case attrName == "featureInstances":
+			calendar.FeatureInstances = FeatureInstances(attrVal)
+		case attrName == "floorName":
+			calendar.FloorName = attrVal
+		case attrName == "floorSection":
+			calendar.FloorSection = attrVal
+		case attrName == "resourceCategory":
+			calendar.ResourceCategory = attrVal
+		case attrName == "resourceDescription":
+			calendar.ResourceDescription = attrVal
+		case attrName == "resourceId":
+			if attrVal == "" {
+				err := fmt.Errorf(gmess.ERR_EMPTYSTRING, attrName)
+				lg.Error(err)
+				return err
+			}
+			calendar.ResourceId = attrVal
+		case attrName == "resourceName":
+			if attrVal == "" && update {
+				err := fmt.Errorf(gmess.ERR_EMPTYSTRING, attrName)
+				lg.Error(err)
+				return err
+			}
+			calendar.ResourceName = attrVal
+		case attrName == "resourceType":
+			calendar.ResourceType = attrVal
+		case attrName == "userVisibleDescription":
+			calendar.UserVisibleDescription = attrVal
+		default:
+			err := fmt.Errorf(gmess.ERR_ATTRNOTRECOGNIZED, attrName)
+			return err
+		}
+
+		if update && attrVal == "" && attrName != "resourceId" {
+			calendar.ForceSendFields = append(calendar.ForceSendFields, strings.Title(attrName))
+		}
+	}
+
+	if calendar.ResourceId == "" {
+		err := fmt.Errorf(gmess.ERR_EMPTYSTRING, CALENDARKEYNAME)
+		lg.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// ShowCalendarAttrs displays requested calendar resource attributes
+func ShowCalendarAttrs(filter string) {
+	lg.Debugw("starting ShowCalendarAttrs()",
+		"filter", filter)
+	defer lg.Debug("finished ShowCalendarAttrs()")
+
+	cmn.ShowAttrs(calendarAttrs, CalendarAttrMap, filter)
+}
@@ -0,0 +1,80 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package resources
+
+import (
+	"fmt"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+// FeatureAttrMap provides lowercase mappings to valid admin.Feature attributes
+var FeatureAttrMap = map[string]string{
+	"etags": "etags",
+	"kind":  "kind",
+	"name":  "name",
+}
+
+var featureAttrs = []string{
+	"etags",
+	"kind",
+	"name",
+}
+
+// PopulateFeature is used in batch processing
+func PopulateFeature(feature *admin.Feature, hdrMap map[int]string, objData []interface{}) error {
+	lg.Debugw("starting PopulateFeature()",
+		"hdrMap", hdrMap)
+	defer lg.Debug("finished PopulateFeature()")
+
+	for idx, attr := range objData {
+		attrName := hdrMap[idx]
+		attrVal := fmt.Sprintf("%v", attr)
+
+		switch {
+		case attrName == "name":
+			if attrVal == "" {
+				err := fmt.Errorf(gmess.ERR_EMPTYSTRING, attrName)
+				lg.Error(err)
+				return err
+			}
+			feature.Name = attrVal
+		default:
+			err := fmt.Errorf(gmess.ERR_ATTRNOTRECOGNIZED, attrName)
+			return err
+		}
+	}
+	return nil
+}
+
+// ShowFeatureAttrs displays requested feature attributes
+func ShowFeatureAttrs(filter string) {
+	lg.Debugw("starting ShowFeatureAttrs()",
+		"filter", filter)
+	defer lg.Debug("finished ShowFeatureAttrs()")
+
+	cmn.ShowAttrs(featureAttrs, FeatureAttrMap, filter)
+}
@@ -0,0 +1,298 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package resources
+
+import (
+	"strings"
+
+	gpars "github.com/plusworx/gmin/utils/gminparsers"
+	lg "github.com/plusworx/gmin/utils/logging"
+	admin "google.golang.org/api/admin/directory/v1"
+	"google.golang.org/api/googleapi"
+)
+
+const (
+	// BUILDINGKEYNAME is name of building key for processing
+	BUILDINGKEYNAME string = "buildingId"
+	// BUILDINGLISTKEY is name of building List call results attribute
+	BUILDINGLISTKEY string = "buildings"
+	// CALENDARKEYNAME is name of calendar resource key for processing
+	CALENDARKEYNAME string = "resourceId"
+	// CALENDARLISTKEY is name of calendar resource List call results attribute
+	CALENDARLISTKEY string = "items"
+	// ENDFIELD is List call attribute string terminator
+	ENDFIELD string = ")"
+	// FEATUREKEYNAME is name of feature key for processing
+	FEATUREKEYNAME string = "name"
+	// FEATURELISTKEY is name of feature List call results attribute
+	FEATURELISTKEY string = "features"
+	// STARTBUILDINGSFIELD is building List call attribute string prefix
+	STARTBUILDINGSFIELD string = "buildings("
+	// STARTCALENDARSFIELD is calendar resource List call attribute string prefix
+	STARTCALENDARSFIELD string = "items("
+	// STARTFEATURESFIELD is feature List call attribute string prefix
+	STARTFEATURESFIELD string = "features("
+)
+
+// AddFields adds fields to be returned from admin calls
+func AddFields(callObj interface{}, attrs string) interface{} {
+	lg.Debugw("starting AddFields()",
+		"attrs", attrs)
+	defer lg.Debug("finished AddFields()")
+
+	var fields googleapi.Field = googleapi.Field(attrs)
+
+	switch callObj.(type) {
+	case *admin.ResourcesBuildingsGetCall:
+		var newRBGC *admin.ResourcesBuildingsGetCall
+		rbgc := callObj.(*admin.ResourcesBuildingsGetCall)
+		newRBGC = rbgc.Fields(fields)
+
+		return newRBGC
+	case *admin.ResourcesBuildingsListCall:
+		var newRBLC *admin.ResourcesBuildingsListCall
+		rblc := callObj.(*admin.ResourcesBuildingsListCall)
+		newRBLC = rblc.Fields(fields)
+
+		return newRBLC
+	case *admin.ResourcesCalendarsGetCall:
+		var newRCGC *admin.ResourcesCalendarsGetCall
+		rcgc := callObj.(*admin.ResourcesCalendarsGetCall)
+		newRCGC = rcgc.Fields(fields)
+
+		return newRCGC
+	case *admin.ResourcesCalendarsListCall:
+		var newRCLC *admin.ResourcesCalendarsListCall
+		rclc := callObj.(*admin.ResourcesCalendarsListCall)
+		newRCLC = rclc.Fields(fields)
+
+		return newRCLC
+	case *admin.ResourcesFeaturesGetCall:
+		var newRFGC *admin.ResourcesFeaturesGetCall
+		rfgc := callObj.(*admin.ResourcesFeaturesGetCall)
+		newRFGC = rfgc.Fields(fields)
+
+		return newRFGC
+	case *admin.ResourcesFeaturesListCall:
+		var newRFLC *admin.ResourcesFeaturesListCall
+		rflc := callObj.(*admin.ResourcesFeaturesListCall)
+		newRFLC = rflc.Fields(fields)
+
+		return newRFLC
+	}
+
+	return nil
+}
+
+// AddMaxResults adds MaxResults to admin calls
+func AddMaxResults(callObj interface{}, maxResults int64) interface{} {
+	lg.Debugw("starting AddMaxResults()",
+		"maxResults", maxResults)
+	defer lg.Debug("finished AddMaxResults()")
+
+	switch callObj.(type) {
+	case *admin.ResourcesBuildingsListCall:
+		rblc := callObj.(*admin.ResourcesBuildingsListCall)
+		return rblc.MaxResults(maxResults)
+	case *admin.ResourcesCalendarsListCall:
+		rclc := callObj.(*admin.ResourcesCalendarsListCall)
+		return rclc.MaxResults(maxResults)
+	case *admin.ResourcesFeaturesListCall:
+		rflc := callObj.(*admin.ResourcesFeaturesListCall)
+		return rflc.MaxResults(maxResults)
+	}
+
+	return nil
+}
+
+// AddOrderBy adds OrderBy to admin.ResourcesCalendarsListCall
+func AddOrderBy(rclc *admin.ResourcesCalendarsListCall, orderBy string, sortOrder string) *admin.ResourcesCalendarsListCall {
+	lg.Debugw("starting AddOrderBy()",
+		"orderBy", orderBy,
+		"sortOrder", sortOrder)
+	defer lg.Debug("finished AddOrderBy()")
+
+	var newRCLC *admin.ResourcesCalendarsListCall
+
+	if sortOrder == "descending" {
+		orderBy = orderBy + " desc"
+	}
+	newRCLC = rclc.OrderBy(orderBy)
+
+	return newRCLC
+}
+
+// AddPageToken adds PageToken to admin calls
+func AddPageToken(callObj interface{}, token string) interface{} {
+	lg.Debugw("starting AddPageToken()",
+		"token", token)
+	defer lg.Debug("finished AddPageToken()")
+
+	switch callObj.(type) {
+	case *admin.ResourcesBuildingsListCall:
+		rblc := callObj.(*admin.ResourcesBuildingsListCall)
+		return rblc.PageToken(token)
+	case *admin.ResourcesCalendarsListCall:
+		rclc := callObj.(*admin.ResourcesCalendarsListCall)
+		return rclc.PageToken(token)
+	case *admin.ResourcesFeaturesListCall:
+		rflc := callObj.(*admin.ResourcesFeaturesListCall)
+		return rflc.PageToken(token)
+	}
+
+	return nil
+}
+
+// AddQuery adds query to admin.ResourcesCalendarsListCall
+func AddQuery(rclc *admin.ResourcesCalendarsListCall, query string) *admin.ResourcesCalendarsListCall {
+	lg.Debugw("starting AddQuery()",
+		"query", query)
+	defer lg.Debug("finished AddQuery()")
+
+	var newRCLC *admin.ResourcesCalendarsListCall
+
+	newRCLC = rclc.Query(query)
+
+	return newRCLC
+}
+
+// DoGetBuilding calls the .Do() function on the admin.ResourcesBuildingsGetCall
+func DoGetBuilding(rbgc *admin.ResourcesBuildingsGetCall) (*admin.Building, error) {
+	lg.Debug("starting DoGetBuilding()")
+	defer lg.Debug("finished DoGetBuilding()")
+
+	building, err := rbgc.Do()
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	return building, nil
+}
+
+// DoGetCalendar calls the .Do() function on the admin.ResourcesCalendarsGetCall
+func DoGetCalendar(rcgc *admin.ResourcesCalendarsGetCall) (*admin.CalendarResource, error) {
+	lg.Debug("starting DoGetCalendar()")
+	defer lg.Debug("finished DoGetCalendar()")
+
+	calendar, err := rcgc.Do()
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	return calendar, nil
+}
+
+// DoGetFeature calls the .Do() function on the admin.ResourcesFeaturesGetCall
+func DoGetFeature(rfgc *admin.ResourcesFeaturesGetCall) (*admin.Feature, error) {
+	lg.Debug("starting DoGetFeature()")
+	defer lg.Debug("finished DoGetFeature()")
+
+	feature, err := rfgc.Do()
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	return feature, nil
+}
+
+// DoListBuildings calls the .Do() function on the admin.ResourcesBuildingsListCall
+func DoListBuildings(rblc *admin.ResourcesBuildingsListCall) (*admin.Buildings, error) {
+	lg.Debug("starting DoListBuildings()")
+	defer lg.Debug("finished DoListBuildings()")
+
+	buildings, err := rblc.Do()
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	return buildings, nil
+}
+
+// DoListCalendars calls the .Do() function on the admin.ResourcesCalendarsListCall
+func DoListCalendars(rclc *admin.ResourcesCalendarsListCall) (*admin.CalendarResources, error) {
+	lg.Debug("starting DoListCalendars()")
+	defer lg.Debug("finished DoListCalendars()")
+
+	calendars, err := rclc.Do()
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	return calendars, nil
+}
+
+// DoListFeatures calls the .Do() function on the admin.ResourcesFeaturesListCall
+func DoListFeatures(rflc *admin.ResourcesFeaturesListCall) (*admin.Features, error) {
+	lg.Debug("starting DoListFeatures()")
+	defer lg.Debug("finished DoListFeatures()")
+
+	features, err := rflc.Do()
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	return features, nil
+}
+
+// ParseQuery parses a calendar resource query. Query clauses are separated by (~) and are
+// joined with AND as required by the API.
+func ParseQuery(query string) (string, error) {
+	lg.Debugw("starting ParseQuery()",
+		"query", query)
+	defer lg.Debug("finished ParseQuery()")
+
+	clauses := []string{}
+
+	for _, clause := range strings.Split(query, "~") {
+		if strings.TrimSpace(clause) == "" {
+			continue
+		}
+		formattedClause, err := gpars.ParseQuery(clause, QueryAttrMap)
+		if err != nil {
+			return "", err
+		}
+		clauses = append(clauses, formattedClause)
+	}
+
+	return strings.Join(clauses, " AND "), nil
+}
+
+func splitList(list string) []string {
+	items := []string{}
+
+	for _, item := range strings.Split(list, "~") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		items = append(items, item)
+	}
+
+	return items
+}
@@ -0,0 +1,116 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	tsts "github.com/plusworx/gmin/tests"
+	lg "github.com/plusworx/gmin/utils/logging"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+func TestParseQuery(t *testing.T) {
+	cases := []struct {
+		expectedErr   string
+		expectedQuery string
+		query         string
+	}{
+		{
+			query:         "buildingid=hq",
+			expectedQuery: "buildingId=hq",
+		},
+		{
+			query:         "buildingid=hq~capacity>=10~floorname=1",
+			expectedQuery: "buildingId=hq AND capacity>=10 AND floor_name=1",
+		},
+		{
+			query:       "resourcemail=room1@mycompany.com",
+			expectedErr: "resourcemail attribute is not recognized",
+		},
+	}
+
+	tsts.InitConfig()
+	lg.InitLogging("info")
+
+	for _, c := range cases {
+		gotErr := ""
+
+		query, err := ParseQuery(c.query)
+		if err != nil {
+			gotErr = err.Error()
+		}
+
+		if gotErr != c.expectedErr {
+			t.Errorf("Got error: %v - expected error: %v", gotErr, c.expectedErr)
+		}
+		if query != c.expectedQuery {
+			t.Errorf("Got query: %v - expected query: %v", query, c.expectedQuery)
+		}
+	}
+}
+
+func TestPopulateCalendarForUpdate(t *testing.T) {
+	cases := []struct {
+		data           []interface{}
+		expectedErr    string
+		expectedFields []string
+		hdrMap         map[int]string
+	}{
+		{
+			data:           []interface{}{"room1", "", "20"},
+			expectedFields: []string{"FloorName"},
+			hdrMap:         map[int]string{0: "resourceId", 1: "floorName", 2: "capacity"},
+		},
+		{
+			data:        []interface{}{"room1", "lots"},
+			expectedErr: "capacity must be a number: lots",
+			hdrMap:      map[int]string{0: "resourceId", 1: "capacity"},
+		},
+		{
+			data:        []interface{}{"Room 1"},
+			expectedErr: "resourceId cannot be empty string",
+			hdrMap:      map[int]string{0: "resourceName"},
+		},
+	}
+
+	tsts.InitConfig()
+	lg.InitLogging("info")
+
+	for _, c := range cases {
+		gotErr := ""
+		calendar := new(admin.CalendarResource)
+
+		err := PopulateCalendarForUpdate(calendar, c.hdrMap, c.data)
+		if err != nil {
+			gotErr = err.Error()
+		}
+
+		if gotErr != c.expectedErr {
+			t.Errorf("Got error: %v - expected error: %v", gotErr, c.expectedErr)
+		}
+		if len(calendar.ForceSendFields) != len(c.expectedFields) {
+			t.Errorf("Got ForceSendFields: %v - expected ForceSendFields: %v", calendar.ForceSendFields, c.expectedFields)
+		}
+	}
+}
@@ -0,0 +1,539 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package roles
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	admin "google.golang.org/api/admin/directory/v1"
+	"google.golang.org/api/googleapi"
+)
+
+const (
+	// ENDFIELD is List call attribute string terminator
+	ENDFIELD string = ")"
+	// KEYNAME is name of role key for processing
+	KEYNAME string = "roleKey"
+	// LISTKEY is name of List call results attribute
+	LISTKEY string = "items"
+	// RAKEYNAME is name of role assignment key for processing
+	RAKEYNAME string = "roleAssignmentId"
+	// SCOPECUSTOMER is role assignment scope for the whole customer
+	SCOPECUSTOMER string = "CUSTOMER"
+	// SCOPEORGUNIT is role assignment scope for an orgunit
+	SCOPEORGUNIT string = "ORG_UNIT"
+	// STARTITEMSFIELD is List call attribute string prefix
+	STARTITEMSFIELD string = "items("
+)
+
+// Key is struct used to extract roleKey
+type Key struct {
+	RoleKey string
+}
+
+// RoleAssignmentParams holds role assignment data for batch processing
+type RoleAssignmentParams struct {
+	AssignedTo string `json:"assignedTo"`
+	OrgUnit    string `json:"orgUnit"`
+	RoleKey    string `json:"roleKey"`
+}
+
+// PrivilegeAttrMap provides lowercase mappings to valid admin.Privilege attributes
+var PrivilegeAttrMap = map[string]string{
+	"childprivileges": "childPrivileges",
+	"etag":            "etag",
+	"isouscopable":    "isOuScopable",
+	"kind":            "kind",
+	"privilegename":   "privilegeName",
+	"serviceid":       "serviceId",
+	"servicename":     "serviceName",
+}
+
+// RoleAssignmentAttrMap provides lowercase mappings to valid admin.RoleAssignment attributes
+var RoleAssignmentAttrMap = map[string]string{
+	"assignedto":       "assignedTo",
+	"etag":             "etag",
+	"kind":             "kind",
+	"orgunit":          "orgUnit", // Used in batch commands
+	"orgunitid":        "orgUnitId",
+	"roleassignmentid": "roleAssignmentId",
+	"roleid":           "roleId",
+	"rolekey":          "roleKey", // Used in batch commands
+	"scopetype":        "scopeType",
+}
+
+// RoleAttrMap provides lowercase mappings to valid admin.Role attributes
+var RoleAttrMap = map[string]string{
+	"etag":             "etag",
+	"forcesendfields":  "forceSendFields",
+	"issuperadminrole": "isSuperAdminRole",
+	"issystemrole":     "isSystemRole",
+	"kind":             "kind",
+	"privilegename":    "privilegeName",
+	"roledescription":  "roleDescription",
+	"roleid":           "roleId",
+	"rolekey":          "roleKey", // Used in batch commands
+	"rolename":         "roleName",
+	"roleprivileges":   "rolePrivileges",
+	"serviceid":        "serviceId",
+}
+
+var privilegeAttrs = []string{
+	"childPrivileges",
+	"etag",
+	"isOuScopable",
+	"kind",
+	"privilegeName",
+	"serviceId",
+	"serviceName",
+}
+
+var roleAssignmentAttrs = []string{
+	"assignedTo",
+	"etag",
+	"kind",
+	"orgUnitId",
+	"roleAssignmentId",
+	"roleId",
+	"scopeType",
+}
+
+var roleAttrs = []string{
+	"etag",
+	"isSuperAdminRole",
+	"isSystemRole",
+	"kind",
+	"roleDescription",
+	"roleId",
+	"roleName",
+	"rolePrivileges",
+}
+
+var roleCompAttrs = map[string]string{
+	"roleprivileges": "rolePrivileges",
+}
+
+var rolePrivilegeAttrs = []string{
+	"privilegeName",
+	"serviceId",
+}
+
+// AddFields adds fields to be returned from admin calls
+func AddFields(callObj interface{}, attrs string) interface{} {
+	lg.Debugw("starting AddFields()",
+		"attrs", attrs)
+	defer lg.Debug("finished AddFields()")
+
+	var fields googleapi.Field = googleapi.Field(attrs)
+
+	switch callObj.(type) {
+	case *admin.PrivilegesListCall:
+		var newPLC *admin.PrivilegesListCall
+		plc := callObj.(*admin.PrivilegesListCall)
+		newPLC = plc.Fields(fields)
+
+		return newPLC
+	case *admin.RoleAssignmentsListCall:
+		var newRALC *admin.RoleAssignmentsListCall
+		ralc := callObj.(*admin.RoleAssignmentsListCall)
+		newRALC = ralc.Fields(fields)
+
+		return newRALC
+	case *admin.RolesGetCall:
+		var newRGC *admin.RolesGetCall
+		rgc := callObj.(*admin.RolesGetCall)
+		newRGC = rgc.Fields(fields)
+
+		return newRGC
+	case *admin.RolesListCall:
+		var newRLC *admin.RolesListCall
+		rlc := callObj.(*admin.RolesListCall)
+		newRLC = rlc.Fields(fields)
+
+		return newRLC
+	}
+
+	return nil
+}
+
+// AddMaxResults adds MaxResults to admin calls
+func AddMaxResults(callObj interface{}, maxResults int64) interface{} {
+	lg.Debugw("starting AddMaxResults()",
+		"maxResults", maxResults)
+	defer lg.Debug("finished AddMaxResults()")
+
+	switch callObj.(type) {
+	case *admin.RoleAssignmentsListCall:
+		ralc := callObj.(*admin.RoleAssignmentsListCall)
+		return ralc.MaxResults(maxResults)
+	case *admin.RolesListCall:
+		rlc := callObj.(*admin.RolesListCall)
+		return rlc.MaxResults(maxResults)
+	}
+
+	return nil
+}
+
+// AddPageToken adds PageToken to admin calls
+func AddPageToken(callObj interface{}, token string) interface{} {
+	lg.Debugw("starting AddPageToken()",
+		"token", token)
+	defer lg.Debug("finished AddPageToken()")
+
+	switch callObj.(type) {
+	case *admin.RoleAssignmentsListCall:
+		ralc := callObj.(*admin.RoleAssignmentsListCall)
+		return ralc.PageToken(token)
+	case *admin.RolesListCall:
+		rlc := callObj.(*admin.RolesListCall)
+		return rlc.PageToken(token)
+	}
+
+	return nil
+}
+
+// DoGet calls the .Do() function on the admin.RolesGetCall
+func DoGet(rgc *admin.RolesGetCall) (*admin.Role, error) {
+	lg.Debug("starting DoGet()")
+	defer lg.Debug("finished DoGet()")
+
+	role, err := rgc.Do()
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	return role, nil
+}
+
+// DoList calls the .Do() function on the admin.RolesListCall
+func DoList(rlc *admin.RolesListCall) (*admin.Roles, error) {
+	lg.Debug("starting DoList()")
+	defer lg.Debug("finished DoList()")
+
+	roles, err := rlc.Do()
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+// DoListAssignments calls the .Do() function on the admin.RoleAssignmentsListCall
+func DoListAssignments(ralc *admin.RoleAssignmentsListCall) (*admin.RoleAssignments, error) {
+	lg.Debug("starting DoListAssignments()")
+	defer lg.Debug("finished DoListAssignments()")
+
+	assignments, err := ralc.Do()
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	return assignments, nil
+}
+
+// DoListPrivileges calls the .Do() function on the admin.PrivilegesListCall
+func DoListPrivileges(plc *admin.PrivilegesListCall) (*admin.Privileges, error) {
+	lg.Debug("starting DoListPrivileges()")
+	defer lg.Debug("finished DoListPrivileges()")
+
+	privileges, err := plc.Do()
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	return privileges, nil
+}
+
+// ParsePrivileges makes role privileges from privilege names separated by (~). Service IDs are
+// looked up in the privilege service ID map.
+func ParsePrivileges(privNames string, serviceIDs map[string]string) ([]*admin.RoleRolePrivileges, error) {
+	lg.Debugw("starting ParsePrivileges()",
+		"privNames", privNames)
+	defer lg.Debug("finished ParsePrivileges()")
+
+	rolePrivs := []*admin.RoleRolePrivileges{}
+
+	for _, name := range strings.Split(privNames, "~") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		rolePriv := &admin.RoleRolePrivileges{PrivilegeName: strings.ToUpper(name)}
+		err := SetServiceID(rolePriv, serviceIDs)
+		if err != nil {
+			return nil, err
+		}
+		rolePrivs = append(rolePrivs, rolePriv)
+	}
+
+	if len(rolePrivs) == 0 {
+		err := fmt.Errorf(gmess.ERR_EMPTYSTRING, "rolePrivileges")
+		lg.Error(err)
+		return nil, err
+	}
+
+	return rolePrivs, nil
+}
+
+// PopulateRole is used in batch processing
+func PopulateRole(role *admin.Role, hdrMap map[int]string, objData []interface{}) error {
+	lg.Debugw("starting PopulateRole()",
+		"hdrMap", hdrMap)
+	defer lg.Debug("finished PopulateRole()")
+
+	for idx, attr := range objData {
+		attrName := hdrMap[idx]
+		attrVal := fmt.Sprintf("%v", attr)
+
+		switch {
+		case attrName == "roleDescription":
+			role.RoleDescription = attrVal
+		case attrName == "roleName":
+			if attrVal == "" {
+				err := fmt.Errorf(gmess.ERR_EMPTYSTRING, attrName)
+				lg.Error(err)
+				return err
+			}
+			role.RoleName = attrVal
+		case attrName == "rolePrivileges":
+			for _, name := range strings.Split(attrVal, "~") {
+				name = strings.TrimSpace(name)
+				if name == "" {
+					continue
+				}
+				role.RolePrivileges = append(role.RolePrivileges, &admin.RoleRolePrivileges{PrivilegeName: strings.ToUpper(name)})
+			}
+		default:
+			err := fmt.Errorf(gmess.ERR_ATTRNOTRECOGNIZED, attrName)
+			return err
+		}
+	}
+	return nil
+}
+
+// PopulateRoleAssignment is used in batch processing
+func PopulateRoleAssignment(raParams *RoleAssignmentParams, hdrMap map[int]string, objData []interface{}) error {
+	lg.Debugw("starting PopulateRoleAssignment()",
+		"hdrMap", hdrMap)
+	defer lg.Debug("finished PopulateRoleAssignment()")
+
+	for idx, attr := range objData {
+		attrName := hdrMap[idx]
+		attrVal := fmt.Sprintf("%v", attr)
+
+		switch {
+		case attrName == "assignedTo":
+			if attrVal == "" {
+				err := fmt.Errorf(gmess.ERR_EMPTYSTRING, attrName)
+				lg.Error(err)
+				return err
+			}
+			raParams.AssignedTo = attrVal
+		case attrName == "orgUnit":
+			raParams.OrgUnit = attrVal
+		case attrName == "roleKey":
+			if attrVal == "" {
+				err := fmt.Errorf(gmess.ERR_EMPTYSTRING, attrName)
+				lg.Error(err)
+				return err
+			}
+			raParams.RoleKey = attrVal
+		default:
+			err := fmt.Errorf(gmess.ERR_ATTRNOTRECOGNIZED, attrName)
+			return err
+		}
+	}
+	return nil
+}
+
+// RoleID returns the ID of a role given its name or ID
+func RoleID(ds *admin.Service, customerID string, roleKey string) (string, error) {
+	lg.Debugw("starting RoleID()",
+		"roleKey", roleKey)
+	defer lg.Debug("finished RoleID()")
+
+	_, err := strconv.ParseInt(roleKey, 10, 64)
+	if err == nil {
+		return roleKey, nil
+	}
+
+	rlc := ds.Roles.List(customerID).Fields("items(roleId,roleName),nextPageToken")
+	for {
+		roles, err := DoList(rlc)
+		if err != nil {
+			return "", err
+		}
+		for _, role := range roles.Items {
+			if strings.EqualFold(role.RoleName, roleKey) {
+				return strconv.FormatInt(role.RoleId, 10), nil
+			}
+		}
+		if roles.NextPageToken == "" {
+			break
+		}
+		rlc = rlc.PageToken(roles.NextPageToken)
+	}
+
+	err = fmt.Errorf(gmess.ERR_ROLENOTFOUND, roleKey)
+	lg.Error(err)
+	return "", err
+}
+
+// ServiceIDs returns a map of privilege names to service IDs, including child privileges
+func ServiceIDs(privileges []*admin.Privilege) map[string]string {
+	lg.Debug("starting ServiceIDs()")
+	defer lg.Debug("finished ServiceIDs()")
+
+	serviceIDs := map[string]string{}
+	addServiceIDs(privileges, serviceIDs)
+
+	return serviceIDs
+}
+
+func addServiceIDs(privileges []*admin.Privilege, serviceIDs map[string]string) {
+	for _, priv := range privileges {
+		if _, ok := serviceIDs[priv.PrivilegeName]; !ok {
+			serviceIDs[priv.PrivilegeName] = priv.ServiceId
+		}
+		addServiceIDs(priv.ChildPrivileges, serviceIDs)
+	}
+}
+
+// SetServiceID sets the service ID of a role privilege if it has not been provided
+func SetServiceID(rolePriv *admin.RoleRolePrivileges, serviceIDs map[string]string) error {
+	lg.Debugw("starting SetServiceID()",
+		"privilegeName", rolePriv.PrivilegeName)
+	defer lg.Debug("finished SetServiceID()")
+
+	if rolePriv.ServiceId != "" {
+		return nil
+	}
+
+	serviceID, ok := serviceIDs[rolePriv.PrivilegeName]
+	if !ok {
+		err := fmt.Errorf(gmess.ERR_PRIVILEGENOTFOUND, rolePriv.PrivilegeName)
+		lg.Error(err)
+		return err
+	}
+	rolePriv.ServiceId = serviceID
+
+	return nil
+}
+
+// ShowAttrs displays requested role attributes
+func ShowAttrs(filter string) {
+	lg.Debugw("starting ShowAttrs()",
+		"filter", filter)
+	defer lg.Debug("finished ShowAttrs()")
+
+	for _, a := range roleAttrs {
+		lwrA := strings.ToLower(a)
+		comp, _ := cmn.IsValidAttr(lwrA, roleCompAttrs)
+		if filter == "" {
+			if comp != "" {
+				fmt.Println("* ", a)
+			} else {
+				fmt.Println(a)
+			}
+			continue
+		}
+
+		if strings.Contains(lwrA, strings.ToLower(filter)) {
+			if comp != "" {
+				fmt.Println("* ", a)
+			} else {
+				fmt.Println(a)
+			}
+		}
+	}
+}
+
+// ShowAssignmentAttrs displays requested role assignment attributes
+func ShowAssignmentAttrs(filter string) {
+	lg.Debugw("starting ShowAssignmentAttrs()",
+		"filter", filter)
+	defer lg.Debug("finished ShowAssignmentAttrs()")
+
+	cmn.ShowAttrs(roleAssignmentAttrs, RoleAssignmentAttrMap, filter)
+}
+
+// ShowCompAttrs displays role composite attributes
+func ShowCompAttrs(filter string) {
+	lg.Debugw("starting ShowCompAttrs()",
+		"filter", filter)
+	defer lg.Debug("finished ShowCompAttrs()")
+
+	keys := make([]string, 0, len(roleCompAttrs))
+	for k := range roleCompAttrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if filter == "" {
+			fmt.Println(roleCompAttrs[k])
+			continue
+		}
+
+		if strings.Contains(k, strings.ToLower(filter)) {
+			fmt.Println(roleCompAttrs[k])
+		}
+	}
+}
+
+// ShowPrivilegeAttrs displays requested privilege attributes
+func ShowPrivilegeAttrs(filter string) {
+	lg.Debugw("starting ShowPrivilegeAttrs()",
+		"filter", filter)
+	defer lg.Debug("finished ShowPrivilegeAttrs()")
+
+	cmn.ShowAttrs(privilegeAttrs, PrivilegeAttrMap, filter)
+}
+
+// ShowSubAttrs displays attributes of composite attributes
+func ShowSubAttrs(compAttr string, filter string) error {
+	lg.Debugw("starting ShowSubAttrs()",
+		"compAttr", compAttr,
+		"filter", filter)
+	defer lg.Debug("finished ShowSubAttrs()")
+
+	if strings.ToLower(compAttr) != "roleprivileges" {
+		err := fmt.Errorf(gmess.ERR_NOTCOMPOSITEATTR, compAttr)
+		lg.Error(err)
+		return err
+	}
+
+	cmn.ShowAttrs(rolePrivilegeAttrs, RoleAttrMap, filter)
+
+	return nil
+}
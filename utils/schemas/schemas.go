@@ -37,6 +37,8 @@ import (
 const (
 	// ENDFIELD is List call attribute string terminator
 	ENDFIELD = ")"
+	// LISTKEY is name of List call results attribute
+	LISTKEY = "schemas"
 	// STARTSCHEMASFIELD is List call attribute string prefix
 	STARTSCHEMASFIELD = "schemas("
 )
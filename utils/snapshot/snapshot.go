@@ -0,0 +1,596 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package snapshot
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	admin "google.golang.org/api/admin/directory/v1"
+	gset "google.golang.org/api/groupssettings/v1"
+)
+
+const (
+	// ALIASTYPEGROUP is alias type of group aliases
+	ALIASTYPEGROUP string = "group"
+	// ALIASTYPEUSER is alias type of user aliases
+	ALIASTYPEUSER string = "user"
+	// FORMATVERSION is the version of the snapshot layout written by this version of gmin
+	FORMATVERSION int = 1
+	// MANIFESTFILE is the name of the snapshot manifest file
+	MANIFESTFILE string = "manifest.json"
+	// MAXLINESIZE is the maximum size of an object line in a snapshot file
+	MAXLINESIZE int = 16 * 1024 * 1024
+	// TIMEFORMAT is used to timestamp default snapshot directory names
+	TIMEFORMAT string = "20060102150405"
+)
+
+// Object types held in a snapshot
+const (
+	ALIASES       string = "aliases"
+	CROSDEVICES   string = "chromeos-devices"
+	GROUPS        string = "groups"
+	GROUPSETTINGS string = "group-settings"
+	MEMBERS       string = "members"
+	MOBDEVICES    string = "mobile-devices"
+	ORGUNITS      string = "orgunits"
+	SCHEMAS       string = "schemas"
+	USERS         string = "users"
+)
+
+// ObjectTypes are the object types held in a snapshot in the order that they are exported
+var ObjectTypes = []string{
+	SCHEMAS,
+	ORGUNITS,
+	USERS,
+	GROUPS,
+	GROUPSETTINGS,
+	MEMBERS,
+	ALIASES,
+	CROSDEVICES,
+	MOBDEVICES,
+}
+
+// RestoreTypes are the object types that can be restored from a snapshot
+var RestoreTypes = []string{
+	ALIASES,
+	GROUPS,
+	GROUPSETTINGS,
+	MEMBERS,
+	ORGUNITS,
+	USERS,
+}
+
+// Alias is a user or group alias and the primary email address that it belongs to
+type Alias struct {
+	Alias        string `json:"alias"`
+	PrimaryEmail string `json:"primaryEmail"`
+	// Type is group or user
+	Type string `json:"type"`
+}
+
+// Filter selects the snapshot objects to be read. Group selects the group, its settings, members and
+// aliases. OrgUnit selects orgunits and users at or below an orgunit path, and the aliases of those users.
+// Types selects object types and all restorable types are selected if it is empty.
+type Filter struct {
+	Group   string
+	OrgUnit string
+	Types   []string
+}
+
+// FileInfo describes a snapshot object file
+type FileInfo struct {
+	Count int    `json:"count"`
+	File  string `json:"file"`
+}
+
+// Manifest describes a snapshot. Error is set if the export failed, in which case the snapshot is incomplete.
+type Manifest struct {
+	Completed   time.Time           `json:"completed"`
+	CustomerID  string              `json:"customerId"`
+	Error       string              `json:"error,omitempty"`
+	GminVersion string              `json:"gminVersion"`
+	Objects     map[string]FileInfo `json:"objects"`
+	Started     time.Time           `json:"started"`
+	Version     int                 `json:"version"`
+}
+
+// Member is a group member and the email address of its group
+type Member struct {
+	Group  string        `json:"group"`
+	Member *admin.Member `json:"member"`
+}
+
+// Snapshot holds the restorable objects read from a snapshot or list output. Manifest is nil for list output.
+type Snapshot struct {
+	Aliases       []Alias
+	GroupSettings []*gset.Groups
+	Groups        []*admin.Group
+	Manifest      *Manifest
+	Members       []Member
+	OrgUnits      []*admin.OrgUnit
+	Users         []*admin.User
+}
+
+// Writer writes objects to a snapshot directory
+type Writer struct {
+	dir      string
+	files    map[string]*os.File
+	manifest *Manifest
+	writers  map[string]*bufio.Writer
+}
+
+// NewWriter creates a snapshot directory, which must not already contain files, and returns a Writer for it
+func NewWriter(dir string, customerID string, gminVersion string) (*Writer, error) {
+	lg.Debugw("starting NewWriter()",
+		"dir", dir)
+	defer lg.Debug("finished NewWriter()")
+
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+	if len(entries) > 0 {
+		err = fmt.Errorf(gmess.ERR_DIRNOTEMPTY, dir)
+		lg.Error(err)
+		return nil, err
+	}
+
+	w := &Writer{
+		dir:     dir,
+		files:   map[string]*os.File{},
+		writers: map[string]*bufio.Writer{},
+		manifest: &Manifest{
+			CustomerID:  customerID,
+			GminVersion: gminVersion,
+			Objects:     map[string]FileInfo{},
+			Started:     time.Now().UTC(),
+			Version:     FORMATVERSION,
+		},
+	}
+
+	for _, objType := range ObjectTypes {
+		fileName := objType + ".jsonl"
+
+		f, err := os.Create(filepath.Join(dir, fileName))
+		if err != nil {
+			lg.Error(err)
+			w.closeFiles()
+			return nil, err
+		}
+		w.files[objType] = f
+		w.writers[objType] = bufio.NewWriter(f)
+		w.manifest.Objects[objType] = FileInfo{File: fileName}
+	}
+
+	return w, nil
+}
+
+// Abort flushes and closes snapshot object files and writes a manifest that marks the snapshot as incomplete
+// so that it is not used
+func (w *Writer) Abort(exportErr error) error {
+	lg.Debugw("starting Abort()",
+		"exportErr", exportErr)
+	defer lg.Debug("finished Abort()")
+
+	w.manifest.Error = exportErr.Error()
+	return w.finish()
+}
+
+// Close flushes and closes snapshot object files and writes the manifest
+func (w *Writer) Close() error {
+	lg.Debug("starting Close()")
+	defer lg.Debug("finished Close()")
+
+	w.manifest.Completed = time.Now().UTC()
+	return w.finish()
+}
+
+func (w *Writer) finish() error {
+	for _, objType := range ObjectTypes {
+		err := w.writers[objType].Flush()
+		if err != nil {
+			lg.Error(err)
+			w.closeFiles()
+			return err
+		}
+	}
+
+	err := w.closeFiles()
+	if err != nil {
+		return err
+	}
+
+	jsonData, err := json.MarshalIndent(w.manifest, "", "    ")
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	err = ioutil.WriteFile(filepath.Join(w.dir, MANIFESTFILE), append(jsonData, '\n'), 0644)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	return nil
+}
+
+// Count returns the number of objects of a type that have been written
+func (w *Writer) Count(objType string) int {
+	return w.manifest.Objects[objType].Count
+}
+
+// Write writes an object of the given type to the snapshot as a line of JSON
+func (w *Writer) Write(objType string, obj interface{}) error {
+	bw, ok := w.writers[objType]
+	if !ok {
+		err := fmt.Errorf(gmess.ERR_OBJECTNOTRECOGNIZED, objType)
+		lg.Error(err)
+		return err
+	}
+
+	jsonData, err := json.Marshal(obj)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	_, err = bw.Write(append(jsonData, '\n'))
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	info := w.manifest.Objects[objType]
+	info.Count++
+	w.manifest.Objects[objType] = info
+	return nil
+}
+
+func (w *Writer) closeFiles() error {
+	var closeErr error
+
+	for _, f := range w.files {
+		err := f.Close()
+		if err != nil && closeErr == nil {
+			lg.Error(err)
+			closeErr = err
+		}
+	}
+	return closeErr
+}
+
+// ReadManifest reads the manifest of a snapshot directory
+func ReadManifest(dir string) (*Manifest, error) {
+	lg.Debugw("starting ReadManifest()",
+		"dir", dir)
+	defer lg.Debug("finished ReadManifest()")
+
+	manifest := new(Manifest)
+
+	fileData, err := ioutil.ReadFile(filepath.Join(dir, MANIFESTFILE))
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	err = json.Unmarshal(fileData, manifest)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	if manifest.Version != FORMATVERSION {
+		err = fmt.Errorf(gmess.ERR_SNAPSHOTVERSION, manifest.Version, FORMATVERSION)
+		lg.Error(err)
+		return nil, err
+	}
+
+	if manifest.Error != "" || manifest.Completed.IsZero() {
+		err = fmt.Errorf(gmess.ERR_SNAPSHOTINCOMPLETE, dir, manifest.Error)
+		lg.Error(err)
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// Read reads the objects selected by filter from a snapshot directory
+func Read(dir string, filter Filter) (*Snapshot, error) {
+	lg.Debugw("starting Read()",
+		"dir", dir)
+	defer lg.Debug("finished Read()")
+
+	manifest, err := ReadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Snapshot{Manifest: manifest}
+	wanted, selected := filterTypes(filter)
+	group := strings.ToLower(filter.Group)
+	users := map[string]bool{}
+
+	if selected(ORGUNITS) {
+		err = readObjects(dir, manifest, ORGUNITS, func(data []byte) error {
+			ou := new(admin.OrgUnit)
+			err := json.Unmarshal(data, ou)
+			if err == nil && inOrgUnit(ou.OrgUnitPath, filter.OrgUnit) {
+				s.OrgUnits = append(s.OrgUnits, ou)
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Users are read if user aliases are wanted so that aliases can be filtered by orgunit
+	if (wanted[USERS] || wanted[ALIASES]) && filter.Group == "" {
+		err = readObjects(dir, manifest, USERS, func(data []byte) error {
+			user := new(admin.User)
+			err := json.Unmarshal(data, user)
+			if err == nil && inOrgUnit(user.OrgUnitPath, filter.OrgUnit) {
+				users[strings.ToLower(user.PrimaryEmail)] = true
+				if wanted[USERS] {
+					s.Users = append(s.Users, user)
+				}
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if selected(GROUPS) {
+		err = readObjects(dir, manifest, GROUPS, func(data []byte) error {
+			grp := new(admin.Group)
+			err := json.Unmarshal(data, grp)
+			if err == nil && (group == "" || strings.ToLower(grp.Email) == group) {
+				s.Groups = append(s.Groups, grp)
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if selected(GROUPSETTINGS) {
+		err = readObjects(dir, manifest, GROUPSETTINGS, func(data []byte) error {
+			settings := new(gset.Groups)
+			err := json.Unmarshal(data, settings)
+			if err == nil && (group == "" || strings.ToLower(settings.Email) == group) {
+				s.GroupSettings = append(s.GroupSettings, settings)
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if selected(MEMBERS) {
+		err = readObjects(dir, manifest, MEMBERS, func(data []byte) error {
+			var member Member
+			err := json.Unmarshal(data, &member)
+			if err == nil && member.Member != nil && (group == "" || strings.ToLower(member.Group) == group) {
+				s.Members = append(s.Members, member)
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if selected(ALIASES) {
+		err = readObjects(dir, manifest, ALIASES, func(data []byte) error {
+			var alias Alias
+			err := json.Unmarshal(data, &alias)
+			if err != nil {
+				return err
+			}
+
+			owner := strings.ToLower(alias.PrimaryEmail)
+			switch {
+			case alias.Type == ALIASTYPEGROUP && filter.OrgUnit == "" && (group == "" || owner == group):
+				s.Aliases = append(s.Aliases, alias)
+			case alias.Type == ALIASTYPEUSER && filter.Group == "" && users[owner]:
+				s.Aliases = append(s.Aliases, alias)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// ReadList reads the users, groups or orgunits in a file of JSON output from a gmin list command. User and
+// group aliases are taken from the aliases attribute of each user or group. filter selects objects in the
+// same way as for a snapshot directory.
+func ReadList(file string, filter Filter) (*Snapshot, error) {
+	lg.Debugw("starting ReadList()",
+		"file", file)
+	defer lg.Debug("finished ReadList()")
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	var list struct {
+		Groups   []*admin.Group   `json:"groups"`
+		OrgUnits []*admin.OrgUnit `json:"organizationUnits"`
+		Users    []*admin.User    `json:"users"`
+	}
+	err = json.Unmarshal(data, &list)
+	if err != nil || (list.Groups == nil && list.OrgUnits == nil && list.Users == nil) {
+		err = fmt.Errorf(gmess.ERR_NOTLISTOUTPUT, file)
+		lg.Error(err)
+		return nil, err
+	}
+
+	s := new(Snapshot)
+	wanted, selected := filterTypes(filter)
+	group := strings.ToLower(filter.Group)
+
+	if selected(ORGUNITS) {
+		for _, ou := range list.OrgUnits {
+			if inOrgUnit(ou.OrgUnitPath, filter.OrgUnit) {
+				s.OrgUnits = append(s.OrgUnits, ou)
+			}
+		}
+	}
+
+	if filter.Group == "" {
+		for _, user := range list.Users {
+			if !inOrgUnit(user.OrgUnitPath, filter.OrgUnit) {
+				continue
+			}
+			if wanted[USERS] {
+				s.Users = append(s.Users, user)
+			}
+			if wanted[ALIASES] {
+				for _, alias := range user.Aliases {
+					s.Aliases = append(s.Aliases, Alias{Alias: alias, PrimaryEmail: user.PrimaryEmail, Type: ALIASTYPEUSER})
+				}
+			}
+		}
+	}
+
+	if filter.OrgUnit == "" {
+		for _, grp := range list.Groups {
+			if group != "" && strings.ToLower(grp.Email) != group {
+				continue
+			}
+			if wanted[GROUPS] {
+				s.Groups = append(s.Groups, grp)
+			}
+			if wanted[ALIASES] {
+				for _, alias := range grp.Aliases {
+					s.Aliases = append(s.Aliases, Alias{Alias: alias, PrimaryEmail: grp.Email, Type: ALIASTYPEGROUP})
+				}
+			}
+		}
+	}
+
+	return s, nil
+}
+
+// filterTypes returns the object types wanted by filter and a function that reports whether the objects
+// of a type are selected by filter
+func filterTypes(filter Filter) (map[string]bool, func(string) bool) {
+	types := filter.Types
+	if len(types) == 0 {
+		types = RestoreTypes
+	}
+	wanted := map[string]bool{}
+	for _, objType := range types {
+		wanted[objType] = true
+	}
+
+	selected := func(objType string) bool {
+		if !wanted[objType] {
+			return false
+		}
+		switch objType {
+		case GROUPS, GROUPSETTINGS, MEMBERS:
+			return filter.OrgUnit == ""
+		case ORGUNITS:
+			return filter.Group == ""
+		}
+		return true
+	}
+	return wanted, selected
+}
+
+// inOrgUnit checks whether an orgunit path is at or below parent. All paths are below an empty parent.
+func inOrgUnit(ouPath string, parent string) bool {
+	if parent == "" {
+		return true
+	}
+
+	ouPath = strings.ToLower(path.Clean("/" + ouPath))
+	parent = strings.ToLower(path.Clean("/" + parent))
+	return parent == "/" || ouPath == parent || strings.HasPrefix(ouPath, parent+"/")
+}
+
+// readObjects calls fn with each line of an object file
+func readObjects(dir string, manifest *Manifest, objType string, fn func([]byte) error) error {
+	lg.Debugw("starting readObjects()",
+		"objType", objType)
+	defer lg.Debug("finished readObjects()")
+
+	info, ok := manifest.Objects[objType]
+	if !ok {
+		return nil
+	}
+
+	f, err := os.Open(filepath.Join(dir, info.File))
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	// Users with many custom schema values can be long lines
+	scanner.Buffer(make([]byte, 64*1024), MAXLINESIZE)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		err = fn(scanner.Bytes())
+		if err != nil {
+			lg.Error(err)
+			return err
+		}
+	}
+	err = scanner.Err()
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,298 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package snapshot
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	lg "github.com/plusworx/gmin/utils/logging"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+func TestWriter(t *testing.T) {
+	lg.InitLogging("info")
+
+	dir := filepath.Join(t.TempDir(), "snapshot")
+
+	w, err := NewWriter(dir, "my_customer", "v0.8.3")
+	if err != nil {
+		t.Fatalf("Got error: %v - expected error: nil", err)
+	}
+	w.Write(USERS, &admin.User{PrimaryEmail: "mickey.mouse@disney.com"})
+	w.Write(USERS, &admin.User{PrimaryEmail: "donald.duck@disney.com"})
+	w.Write(MEMBERS, Member{Group: "cartoons@disney.com", Member: &admin.Member{Email: "mickey.mouse@disney.com"}})
+
+	err = w.Write("widgets", &admin.User{})
+	if err == nil {
+		t.Error("Got error: nil - expected error: widgets is not recognized")
+	}
+
+	err = w.Close()
+	if err != nil {
+		t.Fatalf("Got error: %v - expected error: nil", err)
+	}
+
+	manifest, err := ReadManifest(dir)
+	if err != nil {
+		t.Fatalf("Got error: %v - expected error: nil", err)
+	}
+	if manifest.CustomerID != "my_customer" || manifest.Version != FORMATVERSION || manifest.Completed.Before(manifest.Started) {
+		t.Errorf("Got manifest: %+v - expected manifest: customer my_customer, version %v", manifest, FORMATVERSION)
+	}
+
+	cases := []struct {
+		objType       string
+		expectedCount int
+		expectedData  string
+	}{
+		{
+			objType:       USERS,
+			expectedCount: 2,
+			expectedData:  "{\"primaryEmail\":\"mickey.mouse@disney.com\"}\n{\"primaryEmail\":\"donald.duck@disney.com\"}\n",
+		},
+		{
+			objType:       MEMBERS,
+			expectedCount: 1,
+			expectedData:  "{\"group\":\"cartoons@disney.com\",\"member\":{\"email\":\"mickey.mouse@disney.com\"}}\n",
+		},
+		{
+			objType: GROUPS,
+		},
+	}
+
+	for _, c := range cases {
+		info := manifest.Objects[c.objType]
+		if info.Count != c.expectedCount {
+			t.Errorf("Got %v count: %v - expected %v count: %v", c.objType, info.Count, c.objType, c.expectedCount)
+		}
+
+		fileData, err := ioutil.ReadFile(filepath.Join(dir, info.File))
+		if err != nil {
+			t.Fatalf("Got error: %v - expected error: nil", err)
+		}
+		if string(fileData) != c.expectedData {
+			t.Errorf("Got %v data: %v - expected %v data: %v", c.objType, string(fileData), c.objType, c.expectedData)
+		}
+	}
+
+	_, err = NewWriter(dir, "my_customer", "v0.8.3")
+	if err == nil || !strings.HasPrefix(err.Error(), "directory is not empty") {
+		t.Errorf("Got error: %v - expected error: directory is not empty: %v", err, dir)
+	}
+
+	abortDir := filepath.Join(t.TempDir(), "aborted")
+	w, err = NewWriter(abortDir, "my_customer", "v0.8.3")
+	if err != nil {
+		t.Fatalf("Got error: %v - expected error: nil", err)
+	}
+	w.Write(USERS, &admin.User{PrimaryEmail: "mickey.mouse@disney.com"})
+	err = w.Abort(errors.New("googleapi: Error 500: backend error"))
+	if err != nil {
+		t.Fatalf("Got error: %v - expected error: nil", err)
+	}
+	_, err = ReadManifest(abortDir)
+	expectedErr := "snapshot in " + abortDir + " is incomplete and cannot be used: googleapi: Error 500: backend error"
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf("Got error: %v - expected error: %v", err, expectedErr)
+	}
+
+	ioutil.WriteFile(filepath.Join(dir, MANIFESTFILE), []byte(`{"version":99}`), 0644)
+	_, err = ReadManifest(dir)
+	if err == nil || err.Error() != "snapshot version 99 is not supported - expected version 1" {
+		t.Errorf("Got error: %v - expected error: snapshot version 99 is not supported - expected version 1", err)
+	}
+}
+
+func TestRead(t *testing.T) {
+	lg.InitLogging("info")
+
+	dir := filepath.Join(t.TempDir(), "snapshot")
+
+	w, err := NewWriter(dir, "my_customer", "v0.8.3")
+	if err != nil {
+		t.Fatalf("Got error: %v - expected error: nil", err)
+	}
+	w.Write(ORGUNITS, &admin.OrgUnit{OrgUnitPath: "/Sales"})
+	w.Write(ORGUNITS, &admin.OrgUnit{OrgUnitPath: "/Sales/EMEA"})
+	w.Write(ORGUNITS, &admin.OrgUnit{OrgUnitPath: "/Salesforce"})
+	w.Write(USERS, &admin.User{PrimaryEmail: "a.person@mycompany.org", OrgUnitPath: "/Sales/EMEA"})
+	w.Write(USERS, &admin.User{PrimaryEmail: "b.person@mycompany.org", OrgUnitPath: "/"})
+	w.Write(GROUPS, &admin.Group{Email: "sales@mycompany.org"})
+	w.Write(GROUPS, &admin.Group{Email: "support@mycompany.org"})
+	w.Write(MEMBERS, Member{Group: "sales@mycompany.org", Member: &admin.Member{Email: "a.person@mycompany.org"}})
+	w.Write(MEMBERS, Member{Group: "support@mycompany.org", Member: &admin.Member{Email: "b.person@mycompany.org"}})
+	w.Write(ALIASES, Alias{Alias: "a@mycompany.org", PrimaryEmail: "a.person@mycompany.org", Type: ALIASTYPEUSER})
+	w.Write(ALIASES, Alias{Alias: "b@mycompany.org", PrimaryEmail: "b.person@mycompany.org", Type: ALIASTYPEUSER})
+	w.Write(ALIASES, Alias{Alias: "selling@mycompany.org", PrimaryEmail: "sales@mycompany.org", Type: ALIASTYPEGROUP})
+	w.Close()
+
+	cases := []struct {
+		filter           Filter
+		expectedAliases  int
+		expectedGroups   int
+		expectedMembers  int
+		expectedOrgUnits int
+		expectedUsers    int
+	}{
+		{
+			expectedAliases:  3,
+			expectedGroups:   2,
+			expectedMembers:  2,
+			expectedOrgUnits: 3,
+			expectedUsers:    2,
+		},
+		{
+			filter:          Filter{Group: "Sales@mycompany.org"},
+			expectedAliases: 1,
+			expectedGroups:  1,
+			expectedMembers: 1,
+		},
+		{
+			filter:           Filter{OrgUnit: "/Sales"},
+			expectedAliases:  1,
+			expectedOrgUnits: 2,
+			expectedUsers:    1,
+		},
+		{
+			filter:          Filter{Types: []string{ALIASES, MEMBERS}},
+			expectedAliases: 3,
+			expectedMembers: 2,
+		},
+	}
+
+	for _, c := range cases {
+		s, err := Read(dir, c.filter)
+		if err != nil {
+			t.Fatalf("Got error: %v - expected error: nil", err)
+		}
+
+		if len(s.Aliases) != c.expectedAliases {
+			t.Errorf("Got aliases: %v - expected aliases: %v", len(s.Aliases), c.expectedAliases)
+		}
+		if len(s.Groups) != c.expectedGroups {
+			t.Errorf("Got groups: %v - expected groups: %v", len(s.Groups), c.expectedGroups)
+		}
+		if len(s.Members) != c.expectedMembers {
+			t.Errorf("Got members: %v - expected members: %v", len(s.Members), c.expectedMembers)
+		}
+		if len(s.OrgUnits) != c.expectedOrgUnits {
+			t.Errorf("Got orgunits: %v - expected orgunits: %v", len(s.OrgUnits), c.expectedOrgUnits)
+		}
+		if len(s.Users) != c.expectedUsers {
+			t.Errorf("Got users: %v - expected users: %v", len(s.Users), c.expectedUsers)
+		}
+	}
+}
+
+func TestReadList(t *testing.T) {
+	lg.InitLogging("info")
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"devices.json": `{"chromeosdevices":[{"deviceId":"123"}]}`,
+		"groups.json":  `{"groups":[{"email":"sales@mycompany.org","aliases":["selling@mycompany.org"]},{"email":"support@mycompany.org"}]}`,
+		"ous.json":     `{"organizationUnits":[{"orgUnitPath":"/Sales"},{"orgUnitPath":"/Sales/EMEA"},{"orgUnitPath":"/Salesforce"}]}`,
+		"users.json": `{"users":[{"primaryEmail":"a.person@mycompany.org","orgUnitPath":"/Sales/EMEA","aliases":["a@mycompany.org"]},` +
+			`{"primaryEmail":"b.person@mycompany.org","orgUnitPath":"/"}]}`,
+	}
+	for name, content := range files {
+		ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+	}
+
+	cases := []struct {
+		expectedAliases  int
+		expectedErr      string
+		expectedGroups   int
+		expectedOrgUnits int
+		expectedUsers    int
+		file             string
+		filter           Filter
+	}{
+		{
+			expectedAliases: 1,
+			expectedUsers:   2,
+			file:            "users.json",
+		},
+		{
+			expectedAliases: 1,
+			expectedUsers:   1,
+			file:            "users.json",
+			filter:          Filter{OrgUnit: "/Sales"},
+		},
+		{
+			expectedUsers: 2,
+			file:          "users.json",
+			filter:        Filter{Types: []string{USERS}},
+		},
+		{
+			file:   "users.json",
+			filter: Filter{Group: "sales@mycompany.org"},
+		},
+		{
+			expectedAliases: 1,
+			expectedGroups:  1,
+			file:            "groups.json",
+			filter:          Filter{Group: "Sales@mycompany.org"},
+		},
+		{
+			expectedOrgUnits: 2,
+			file:             "ous.json",
+			filter:           Filter{OrgUnit: "/Sales"},
+		},
+		{
+			expectedErr: "devices.json is not json output of list users, list groups or list orgunits",
+			file:        "devices.json",
+		},
+	}
+
+	for _, c := range cases {
+		s, err := ReadList(filepath.Join(dir, c.file), c.filter)
+		if err != nil {
+			if c.expectedErr == "" || !strings.HasSuffix(err.Error(), c.expectedErr) {
+				t.Errorf("Got error: %v - expected error: %v", err, c.expectedErr)
+			}
+			continue
+		}
+		if c.expectedErr != "" {
+			t.Errorf("Got error: nil - expected error: %v", c.expectedErr)
+			continue
+		}
+
+		if len(s.Aliases) != c.expectedAliases {
+			t.Errorf("%v - got aliases: %v - expected aliases: %v", c.file, len(s.Aliases), c.expectedAliases)
+		}
+		if len(s.Groups) != c.expectedGroups {
+			t.Errorf("%v - got groups: %v - expected groups: %v", c.file, len(s.Groups), c.expectedGroups)
+		}
+		if len(s.OrgUnits) != c.expectedOrgUnits {
+			t.Errorf("%v - got orgunits: %v - expected orgunits: %v", c.file, len(s.OrgUnits), c.expectedOrgUnits)
+		}
+		if len(s.Users) != c.expectedUsers {
+			t.Errorf("%v - got users: %v - expected users: %v", c.file, len(s.Users), c.expectedUsers)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package users
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/jpeg"
+	_ "image/png" // register PNG decoder
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+const (
+	// PHOTOMAXSIZE is the maximum width and height in pixels of an uploaded user photo
+	PHOTOMAXSIZE int = 96
+	// PHOTOQUALITY is the JPEG quality used to encode uploaded user photos
+	PHOTOQUALITY int = 90
+)
+
+// PhotoAttrMap provides lowercase mappings to valid admin.UserPhoto attributes
+var PhotoAttrMap = map[string]string{
+	"etag":         "etag",
+	"height":       "height",
+	"id":           "id",
+	"kind":         "kind",
+	"mimetype":     "mimeType",
+	"photodata":    "photoData",
+	"primaryemail": "primaryEmail",
+	"width":        "width",
+}
+
+// ValidPhotoExts provide valid user photo file extensions
+var ValidPhotoExts = []string{
+	".jpeg",
+	".jpg",
+	".png",
+}
+
+var photoAttrs = []string{
+	"etag",
+	"height",
+	"id",
+	"kind",
+	"mimeType",
+	"photoData",
+	"primaryEmail",
+	"width",
+}
+
+// DoGetPhoto calls the .Do() function on the admin.UsersPhotosGetCall
+func DoGetPhoto(upgc *admin.UsersPhotosGetCall) (*admin.UserPhoto, error) {
+	lg.Debug("starting DoGetPhoto()")
+	defer lg.Debug("finished DoGetPhoto()")
+
+	photo, err := upgc.Do()
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	return photo, nil
+}
+
+// PhotoFromFile reads a JPEG or PNG image file and returns a user photo ready for upload.
+// Images larger than PHOTOMAXSIZE are scaled down, preserving aspect ratio, and all images
+// are encoded as web safe base64 JPEG data.
+func PhotoFromFile(path string) (*admin.UserPhoto, error) {
+	lg.Debugw("starting PhotoFromFile()",
+		"path", path)
+	defer lg.Debug("finished PhotoFromFile()")
+
+	fileData, err := ioutil.ReadFile(path)
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(fileData))
+	if err != nil {
+		err = fmt.Errorf(gmess.ERR_INVALIDPHOTO, path)
+		lg.Error(err)
+		return nil, err
+	}
+
+	img = resizeImage(img, PHOTOMAXSIZE)
+
+	var buf bytes.Buffer
+	err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: PHOTOQUALITY})
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	bounds := img.Bounds()
+	photo := &admin.UserPhoto{
+		Height:    int64(bounds.Dy()),
+		MimeType:  "image/jpeg",
+		PhotoData: base64.URLEncoding.EncodeToString(buf.Bytes()),
+		Width:     int64(bounds.Dx()),
+	}
+
+	return photo, nil
+}
+
+// PhotoUserKey returns the user email address from a photo file name such as mickey.mouse@disney.com.jpg
+func PhotoUserKey(fileName string) (string, bool) {
+	lg.Debugw("starting PhotoUserKey()",
+		"fileName", fileName)
+	defer lg.Debug("finished PhotoUserKey()")
+
+	ext := filepath.Ext(fileName)
+	for _, validExt := range ValidPhotoExts {
+		if strings.ToLower(ext) == validExt {
+			return strings.TrimSuffix(fileName, ext), true
+		}
+	}
+
+	return "", false
+}
+
+// SavePhoto decodes user photo data and writes the image to a file
+func SavePhoto(photo *admin.UserPhoto, path string) error {
+	lg.Debugw("starting SavePhoto()",
+		"path", path)
+	defer lg.Debug("finished SavePhoto()")
+
+	// Photo data is web safe base64 but padding is not always present
+	imgData, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(photo.PhotoData, "="))
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	err = ioutil.WriteFile(path, imgData, 0644)
+	if err != nil {
+		lg.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// ShowPhotoAttrs displays requested user photo attributes
+func ShowPhotoAttrs(filter string) {
+	lg.Debugw("starting ShowPhotoAttrs()",
+		"filter", filter)
+	defer lg.Debug("finished ShowPhotoAttrs()")
+
+	cmn.ShowAttrs(photoAttrs, PhotoAttrMap, filter)
+}
+
+// resizeImage scales an image down so that neither side is larger than maxSize by averaging
+// the source pixels that fall within each destination pixel
+func resizeImage(img image.Image, maxSize int) image.Image {
+	lg.Debugw("starting resizeImage()",
+		"maxSize", maxSize)
+	defer lg.Debug("finished resizeImage()")
+
+	bounds := img.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	if srcW <= maxSize && srcH <= maxSize {
+		return img
+	}
+
+	dstW, dstH := maxSize, maxSize
+	if srcW > srcH {
+		dstH = srcH * maxSize / srcW
+	} else {
+		dstW = srcW * maxSize / srcH
+	}
+	if dstW < 1 {
+		dstW = 1
+	}
+	if dstH < 1 {
+		dstH = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		y0 := bounds.Min.Y + y*srcH/dstH
+		y1 := bounds.Min.Y + (y+1)*srcH/dstH
+		for x := 0; x < dstW; x++ {
+			x0 := bounds.Min.X + x*srcW/dstW
+			x1 := bounds.Min.X + (x+1)*srcW/dstW
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					sr, sg, sb, sa := img.At(sx, sy).RGBA()
+					r += uint64(sr)
+					g += uint64(sg)
+					b += uint64(sb)
+					a += uint64(sa)
+					n++
+				}
+			}
+			offset := dst.PixOffset(x, y)
+			dst.Pix[offset] = uint8(r / n >> 8)
+			dst.Pix[offset+1] = uint8(g / n >> 8)
+			dst.Pix[offset+2] = uint8(b / n >> 8)
+			dst.Pix[offset+3] = uint8(a / n >> 8)
+		}
+	}
+
+	return dst
+}
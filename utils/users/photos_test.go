@@ -0,0 +1,163 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package users
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	tsts "github.com/plusworx/gmin/tests"
+	lg "github.com/plusworx/gmin/utils/logging"
+)
+
+func TestPhotoFromFile(t *testing.T) {
+	cases := []struct {
+		expectedErr    string
+		expectedHeight int64
+		expectedWidth  int64
+		fileName       string
+		height         int
+		width          int
+	}{
+		{
+			expectedHeight: 48,
+			expectedWidth:  96,
+			fileName:       "wide.png",
+			height:         100,
+			width:          200,
+		},
+		{
+			expectedHeight: 96,
+			expectedWidth:  72,
+			fileName:       "tall.png",
+			height:         400,
+			width:          300,
+		},
+		{
+			expectedHeight: 50,
+			expectedWidth:  40,
+			fileName:       "small.png",
+			height:         50,
+			width:          40,
+		},
+		{
+			expectedErr: "photo file must be a JPEG or PNG image: ",
+			fileName:    "notanimage.png",
+		},
+	}
+
+	tsts.InitConfig()
+	lg.InitLogging("info")
+
+	dir := t.TempDir()
+
+	for _, c := range cases {
+		path := filepath.Join(dir, c.fileName)
+
+		if c.width > 0 {
+			img := image.NewRGBA(image.Rect(0, 0, c.width, c.height))
+			for y := 0; y < c.height; y++ {
+				for x := 0; x < c.width; x++ {
+					img.Set(x, y, color.RGBA{R: 200, G: 50, B: 50, A: 255})
+				}
+			}
+			var buf bytes.Buffer
+			png.Encode(&buf, img)
+			ioutil.WriteFile(path, buf.Bytes(), 0644)
+		} else {
+			ioutil.WriteFile(path, []byte("not an image"), 0644)
+		}
+
+		photo, err := PhotoFromFile(path)
+		if c.expectedErr != "" {
+			if err == nil || err.Error() != c.expectedErr+path {
+				t.Errorf("Got error: %v - expected error: %v", err, c.expectedErr+path)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Got error: %v - expected error: nil", err)
+		}
+
+		if photo.Width != c.expectedWidth || photo.Height != c.expectedHeight {
+			t.Errorf("Got size: %vx%v - expected size: %vx%v", photo.Width, photo.Height, c.expectedWidth, c.expectedHeight)
+		}
+		if photo.MimeType != "image/jpeg" {
+			t.Errorf("Got mime type: %v - expected mime type: image/jpeg", photo.MimeType)
+		}
+
+		data, err := base64.URLEncoding.DecodeString(photo.PhotoData)
+		if err != nil {
+			t.Fatalf("Got photo data error: %v - expected web safe base64", err)
+		}
+		img, err := jpeg.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("Got decode error: %v - expected JPEG image", err)
+		}
+		if int64(img.Bounds().Dx()) != c.expectedWidth {
+			t.Errorf("Got JPEG width: %v - expected JPEG width: %v", img.Bounds().Dx(), c.expectedWidth)
+		}
+	}
+}
+
+func TestPhotoUserKey(t *testing.T) {
+	cases := []struct {
+		expectedKey string
+		expectedOK  bool
+		fileName    string
+	}{
+		{
+			expectedKey: "mickey.mouse@disney.com",
+			expectedOK:  true,
+			fileName:    "mickey.mouse@disney.com.jpg",
+		},
+		{
+			expectedKey: "donald.duck@disney.com",
+			expectedOK:  true,
+			fileName:    "donald.duck@disney.com.PNG",
+		},
+		{
+			fileName: "goofy@disney.com.gif",
+		},
+		{
+			fileName: "README",
+		},
+	}
+
+	tsts.InitConfig()
+	lg.InitLogging("info")
+
+	for _, c := range cases {
+		key, ok := PhotoUserKey(c.fileName)
+		if key != c.expectedKey || ok != c.expectedOK {
+			t.Errorf("Got key: %v %v - expected key: %v %v", key, ok, c.expectedKey, c.expectedOK)
+		}
+	}
+}
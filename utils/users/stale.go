@@ -0,0 +1,162 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package users
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	cmn "github.com/plusworx/gmin/utils/common"
+	gmess "github.com/plusworx/gmin/utils/gminmessages"
+	lg "github.com/plusworx/gmin/utils/logging"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+const (
+	// STALEACTIONDELETE writes a batch-delete users input file for stale users
+	STALEACTIONDELETE string = "delete"
+	// STALEACTIONSUSPEND writes a batch-update users input file that suspends stale users
+	STALEACTIONSUSPEND string = "suspend"
+	// STALEFIELDS are the user attributes that stale user checks need
+	STALEFIELDS string = "creationTime,isEnrolledIn2Sv,lastLoginTime,orgUnitPath,primaryEmail,suspended"
+	// STALEINACTIVE is the check for active users who have not logged in recently
+	STALEINACTIVE string = "inactive"
+	// STALENEVERLOGGEDIN is the check for active users who have never logged in since being created
+	STALENEVERLOGGEDIN string = "never-logged-in"
+	// STALENO2SV is the check for active users who are not enrolled in 2-Step Verification
+	STALENO2SV string = "no-2sv"
+	// STALESUSPENDED is the check for suspended users who have not been used for a long time
+	STALESUSPENDED string = "suspended"
+)
+
+// ValidStaleActions are the actions that stale users report batch input files can be written for
+var ValidStaleActions = []string{
+	STALEACTIONDELETE,
+	STALEACTIONSUSPEND,
+}
+
+// ValidStaleChecks are the checks that can be made on users for the stale users report
+var ValidStaleChecks = []string{
+	STALEINACTIVE,
+	STALENEVERLOGGEDIN,
+	STALENO2SV,
+	STALESUSPENDED,
+}
+
+// StaleOptions holds the checks and day thresholds used to find stale users
+type StaleOptions struct {
+	Checks            []string
+	InactiveDays      int
+	NeverLoggedInDays int
+	Now               time.Time
+	SuspendedDays     int
+}
+
+// StaleUser is a user found by one or more stale user checks
+type StaleUser struct {
+	CreationTime    string   `json:"creationTime,omitempty"`
+	IsEnrolledIn2Sv bool     `json:"isEnrolledIn2Sv"`
+	LastLoginTime   string   `json:"lastLoginTime,omitempty"`
+	OrgUnitPath     string   `json:"orgUnitPath,omitempty"`
+	PrimaryEmail    string   `json:"primaryEmail"`
+	Reasons         []string `json:"reasons"`
+	Suspended       bool     `json:"suspended"`
+}
+
+// StaleReasons returns the checks in opts that user fails
+//
+// The Directory API doesn't record when a user was suspended, so suspended users are reported when
+// their last login, or their creation if they never logged in, is older than SuspendedDays.
+func StaleReasons(user *admin.User, opts StaleOptions) []string {
+	lg.Debugw("starting StaleReasons()",
+		"user", user.PrimaryEmail)
+	defer lg.Debug("finished StaleReasons()")
+
+	reasons := []string{}
+
+	created, _ := time.Parse(time.RFC3339, user.CreationTime)
+	lastLogin, loggedIn := lastLoginTime(user)
+
+	lastUsed := created
+	if loggedIn {
+		lastUsed = lastLogin
+	}
+
+	for _, check := range ValidStaleChecks {
+		if !cmn.SliceContainsStr(opts.Checks, check) {
+			continue
+		}
+
+		var stale bool
+
+		switch check {
+		case STALEINACTIVE:
+			stale = !user.Suspended && loggedIn && olderThan(lastLogin, opts.Now, opts.InactiveDays)
+		case STALENEVERLOGGEDIN:
+			stale = !user.Suspended && !loggedIn && olderThan(created, opts.Now, opts.NeverLoggedInDays)
+		case STALENO2SV:
+			stale = !user.Suspended && !user.IsEnrolledIn2Sv
+		case STALESUSPENDED:
+			stale = user.Suspended && olderThan(lastUsed, opts.Now, opts.SuspendedDays)
+		}
+
+		if stale {
+			reasons = append(reasons, check)
+		}
+	}
+	return reasons
+}
+
+// ValidateStaleChecks checks that the checks given for the stale users report are valid
+func ValidateStaleChecks(checks []string) error {
+	lg.Debugw("starting ValidateStaleChecks()",
+		"checks", checks)
+	defer lg.Debug("finished ValidateStaleChecks()")
+
+	for _, check := range checks {
+		if !cmn.SliceContainsStr(ValidStaleChecks, check) {
+			err := fmt.Errorf(gmess.ERR_INVALIDSTALECHECK, check, strings.Join(ValidStaleChecks, ", "))
+			lg.Error(err)
+			return err
+		}
+	}
+	return nil
+}
+
+// lastLoginTime returns the time that user last logged in and false if they never have, in which
+// case the Directory API gives the Unix epoch
+func lastLoginTime(user *admin.User) (time.Time, bool) {
+	lastLogin, err := time.Parse(time.RFC3339, user.LastLoginTime)
+	if err != nil || lastLogin.Year() <= 1970 {
+		return time.Time{}, false
+	}
+	return lastLogin, true
+}
+
+func olderThan(tm time.Time, now time.Time, days int) bool {
+	if tm.IsZero() {
+		return false
+	}
+	return tm.Before(now.AddDate(0, 0, -days))
+}
@@ -0,0 +1,126 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package users
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	tsts "github.com/plusworx/gmin/tests"
+	lg "github.com/plusworx/gmin/utils/logging"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+func TestStaleReasons(t *testing.T) {
+	const neverLoggedIn = "1970-01-01T00:00:00.000Z"
+
+	opts := StaleOptions{
+		Checks:            ValidStaleChecks,
+		InactiveDays:      90,
+		NeverLoggedInDays: 30,
+		Now:               time.Date(2020, 10, 16, 0, 0, 0, 0, time.UTC),
+		SuspendedDays:     180,
+	}
+
+	cases := []struct {
+		checks          []string
+		expectedReasons []string
+		user            *admin.User
+	}{
+		{
+			user:            &admin.User{CreationTime: "2019-01-01T00:00:00.000Z", IsEnrolledIn2Sv: true, LastLoginTime: "2020-10-01T00:00:00.000Z"},
+			expectedReasons: []string{},
+		},
+		{
+			user:            &admin.User{CreationTime: "2019-01-01T00:00:00.000Z", LastLoginTime: "2020-06-01T00:00:00.000Z"},
+			expectedReasons: []string{STALEINACTIVE, STALENO2SV},
+		},
+		{
+			checks:          []string{STALEINACTIVE},
+			user:            &admin.User{CreationTime: "2019-01-01T00:00:00.000Z", LastLoginTime: "2020-06-01T00:00:00.000Z"},
+			expectedReasons: []string{STALEINACTIVE},
+		},
+		{
+			user:            &admin.User{CreationTime: "2020-08-01T00:00:00.000Z", IsEnrolledIn2Sv: true, LastLoginTime: neverLoggedIn},
+			expectedReasons: []string{STALENEVERLOGGEDIN},
+		},
+		{
+			user:            &admin.User{CreationTime: "2020-10-01T00:00:00.000Z", IsEnrolledIn2Sv: true, LastLoginTime: neverLoggedIn},
+			expectedReasons: []string{},
+		},
+		{
+			user:            &admin.User{CreationTime: "2019-01-01T00:00:00.000Z", LastLoginTime: "2020-03-01T00:00:00.000Z", Suspended: true},
+			expectedReasons: []string{STALESUSPENDED},
+		},
+		{
+			user:            &admin.User{CreationTime: "2020-06-01T00:00:00.000Z", LastLoginTime: neverLoggedIn, Suspended: true},
+			expectedReasons: []string{},
+		},
+	}
+
+	tsts.InitConfig()
+	lg.InitLogging("info")
+
+	for _, c := range cases {
+		o := opts
+		if c.checks != nil {
+			o.Checks = c.checks
+		}
+
+		reasons := StaleReasons(c.user, o)
+		if !reflect.DeepEqual(reasons, c.expectedReasons) {
+			t.Errorf("Got reasons: %v - expected reasons: %v", reasons, c.expectedReasons)
+		}
+	}
+}
+
+func TestValidateStaleChecks(t *testing.T) {
+	cases := []struct {
+		checks      []string
+		expectedErr string
+	}{
+		{
+			checks: []string{STALEINACTIVE, STALENO2SV},
+		},
+		{
+			checks:      []string{"dormant"},
+			expectedErr: "invalid stale user check: dormant - valid checks are: inactive, never-logged-in, no-2sv, suspended",
+		},
+	}
+
+	tsts.InitConfig()
+	lg.InitLogging("info")
+
+	for _, c := range cases {
+		err := ValidateStaleChecks(c.checks)
+
+		gotErr := ""
+		if err != nil {
+			gotErr = err.Error()
+		}
+		if gotErr != c.expectedErr {
+			t.Errorf("Got error: %v - expected error: %v", gotErr, c.expectedErr)
+		}
+	}
+}
@@ -23,8 +23,6 @@ THE SOFTWARE.
 package users
 
 import (
-	"crypto/sha1"
-	"encoding/hex"
 	"fmt"
 	"sort"
 	"strings"
@@ -39,10 +37,10 @@ import (
 const (
 	// ENDFIELD is List call attribute string terminator
 	ENDFIELD = ")"
-	// HASHFUNCTION specifies password hash function
-	HASHFUNCTION string = "SHA-1"
 	// KEYNAME is name of key for processing
 	KEYNAME string = "userKey"
+	// LISTKEY is name of List call results attribute
+	LISTKEY = "users"
 	// STARTUSERSFIELD is List call users attribute string prefix
 	STARTUSERSFIELD = "users("
 )
@@ -514,6 +512,13 @@ var validNotesContentTypes = []string{
 	"text_plain",
 }
 
+// ValidActions provide valid strings to be used for user actions
+var ValidActions = []string{
+	"make-admin",
+	"revoke-admin",
+	"signout",
+}
+
 // ValidOrderByStrs provide valid strings to be used to set admin.UsersListCall OrderBy
 var ValidOrderByStrs = []string{
 	"email",
@@ -680,6 +685,12 @@ func AddFields(callObj interface{}, attrs string) interface{} {
 		newUGC = ugc.Fields(fields)
 
 		return newUGC
+	case *admin.UsersPhotosGetCall:
+		var newUPGC *admin.UsersPhotosGetCall
+		upgc := callObj.(*admin.UsersPhotosGetCall)
+		newUPGC = upgc.Fields(fields)
+
+		return newUPGC
 	}
 
 	return nil
@@ -837,26 +848,6 @@ func DoList(ulc *admin.UsersListCall) (*admin.Users, error) {
 	return users, nil
 }
 
-// HashPassword creates a password hash
-func HashPassword(password string) (string, error) {
-	lg.Debugw("starting HashPassword()",
-		"password", password)
-	defer lg.Debug("finished HashPassword()")
-
-	hasher := sha1.New()
-
-	_, err := hasher.Write([]byte(password))
-	if err != nil {
-		lg.Error(err)
-		return "", err
-	}
-
-	hashedBytes := hasher.Sum(nil)
-	hexSha1 := hex.EncodeToString(hashedBytes)
-
-	return hexSha1, nil
-}
-
 // PopulateUndeleteUser is used in batch processing
 func PopulateUndeleteUser(undelUser *UndeleteUser, hdrMap map[int]string, objData []interface{}) error {
 	lg.Debugw("starting PopulateUndeleteUser()",
@@ -923,12 +914,10 @@ func PopulateUser(user *admin.User, hdrMap map[int]string, objData []interface{}
 				lg.Error(err)
 				return err
 			}
-			pwd, err := HashPassword(attrVal)
-			if err != nil {
-				return err
-			}
-			user.Password = pwd
-			user.HashFunction = HASHFUNCTION
+			user.Password = attrVal
+		}
+		if attrName == "hashFunction" {
+			user.HashFunction = attrVal
 		}
 		if attrName == "primaryEmail" {
 			if attrVal == "" {
@@ -1011,14 +1000,10 @@ func PopulateUserForUpdate(userParams *UserParams, hdrMap map[int]string, objDat
 			userParams.User.OrgUnitPath = attrVal
 		}
 		if attrName == "password" {
-			if attrVal != "" {
-				pwd, err := HashPassword(attrVal)
-				if err != nil {
-					return err
-				}
-				userParams.User.Password = pwd
-				userParams.User.HashFunction = HASHFUNCTION
-			}
+			userParams.User.Password = attrVal
+		}
+		if attrName == "hashFunction" {
+			userParams.User.HashFunction = attrVal
 		}
 		if attrName == "primaryEmail" {
 			if attrVal == "" {
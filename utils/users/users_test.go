@@ -337,16 +337,3 @@ func TestAddViewType(t *testing.T) {
 		}
 	}
 }
-
-func TestHashPassword(t *testing.T) {
-	tsts.InitConfig()
-	lg.InitLogging("info")
-
-	pwd := "MySuperStrongPassword"
-
-	hashedPwd, _ := HashPassword(pwd)
-
-	if hashedPwd != "e1f7c050db42a86e4d358e8c1dcef57e3b4f2fc0" {
-		t.Errorf("Expected user.Password to be %v but got %v", "e1f7c050db42a86e4d358e8c1dcef57e3b4f2fc0", hashedPwd)
-	}
-}
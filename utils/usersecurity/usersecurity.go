@@ -0,0 +1,225 @@
+/*
+Copyright © 2020 Chris Duncan <chris.duncan@plusworx.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package usersecurity
+
+import (
+	cmn "github.com/plusworx/gmin/utils/common"
+	lg "github.com/plusworx/gmin/utils/logging"
+	admin "google.golang.org/api/admin/directory/v1"
+	"google.golang.org/api/googleapi"
+)
+
+const (
+	// ENDFIELD is List call attribute string terminator
+	ENDFIELD string = ")"
+	// LISTKEY is name of token, app password and backup code List call results attribute
+	LISTKEY string = "items"
+	// STARTITEMSFIELD is List call attribute string prefix
+	STARTITEMSFIELD string = "items("
+)
+
+// AspAttrMap provides lowercase mappings to valid admin.Asp attributes
+var AspAttrMap = map[string]string{
+	"codeid":       "codeId",
+	"creationtime": "creationTime",
+	"etag":         "etag",
+	"kind":         "kind",
+	"lasttimeused": "lastTimeUsed",
+	"name":         "name",
+	"userkey":      "userKey",
+}
+
+// TokenAttrMap provides lowercase mappings to valid admin.Token attributes
+var TokenAttrMap = map[string]string{
+	"anonymous":   "anonymous",
+	"clientid":    "clientId",
+	"displaytext": "displayText",
+	"etag":        "etag",
+	"kind":        "kind",
+	"nativeapp":   "nativeApp",
+	"scopes":      "scopes",
+	"userkey":     "userKey",
+}
+
+// ValidBackupCodeActions provide valid strings to be used for backup code actions
+var ValidBackupCodeActions = []string{
+	"generate",
+	"invalidate",
+}
+
+// VerCodeAttrMap provides lowercase mappings to valid admin.VerificationCode attributes
+var VerCodeAttrMap = map[string]string{
+	"etag":             "etag",
+	"kind":             "kind",
+	"userid":           "userId",
+	"verificationcode": "verificationCode",
+}
+
+var aspAttrs = []string{
+	"codeId",
+	"creationTime",
+	"etag",
+	"kind",
+	"lastTimeUsed",
+	"name",
+	"userKey",
+}
+
+var tokenAttrs = []string{
+	"anonymous",
+	"clientId",
+	"displayText",
+	"etag",
+	"kind",
+	"nativeApp",
+	"scopes",
+	"userKey",
+}
+
+var verCodeAttrs = []string{
+	"etag",
+	"kind",
+	"userId",
+	"verificationCode",
+}
+
+// AddFields adds fields to be returned from admin calls
+func AddFields(callObj interface{}, attrs string) interface{} {
+	lg.Debugw("starting AddFields()",
+		"attrs", attrs)
+	defer lg.Debug("finished AddFields()")
+
+	var fields googleapi.Field = googleapi.Field(attrs)
+
+	switch callObj.(type) {
+	case *admin.AspsListCall:
+		var newALC *admin.AspsListCall
+		alc := callObj.(*admin.AspsListCall)
+		newALC = alc.Fields(fields)
+
+		return newALC
+	case *admin.TokensGetCall:
+		var newTGC *admin.TokensGetCall
+		tgc := callObj.(*admin.TokensGetCall)
+		newTGC = tgc.Fields(fields)
+
+		return newTGC
+	case *admin.TokensListCall:
+		var newTLC *admin.TokensListCall
+		tlc := callObj.(*admin.TokensListCall)
+		newTLC = tlc.Fields(fields)
+
+		return newTLC
+	case *admin.VerificationCodesListCall:
+		var newVCLC *admin.VerificationCodesListCall
+		vclc := callObj.(*admin.VerificationCodesListCall)
+		newVCLC = vclc.Fields(fields)
+
+		return newVCLC
+	}
+
+	return nil
+}
+
+// DoGetToken calls the .Do() function on the admin.TokensGetCall
+func DoGetToken(tgc *admin.TokensGetCall) (*admin.Token, error) {
+	lg.Debug("starting DoGetToken()")
+	defer lg.Debug("finished DoGetToken()")
+
+	token, err := tgc.Do()
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// DoListAsps calls the .Do() function on the admin.AspsListCall
+func DoListAsps(alc *admin.AspsListCall) (*admin.Asps, error) {
+	lg.Debug("starting DoListAsps()")
+	defer lg.Debug("finished DoListAsps()")
+
+	asps, err := alc.Do()
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	return asps, nil
+}
+
+// DoListTokens calls the .Do() function on the admin.TokensListCall
+func DoListTokens(tlc *admin.TokensListCall) (*admin.Tokens, error) {
+	lg.Debug("starting DoListTokens()")
+	defer lg.Debug("finished DoListTokens()")
+
+	tokens, err := tlc.Do()
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// DoListVerCodes calls the .Do() function on the admin.VerificationCodesListCall
+func DoListVerCodes(vclc *admin.VerificationCodesListCall) (*admin.VerificationCodes, error) {
+	lg.Debug("starting DoListVerCodes()")
+	defer lg.Debug("finished DoListVerCodes()")
+
+	codes, err := vclc.Do()
+	if err != nil {
+		lg.Error(err)
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// ShowAspAttrs displays requested app password attributes
+func ShowAspAttrs(filter string) {
+	lg.Debugw("starting ShowAspAttrs()",
+		"filter", filter)
+	defer lg.Debug("finished ShowAspAttrs()")
+
+	cmn.ShowAttrs(aspAttrs, AspAttrMap, filter)
+}
+
+// ShowTokenAttrs displays requested token attributes
+func ShowTokenAttrs(filter string) {
+	lg.Debugw("starting ShowTokenAttrs()",
+		"filter", filter)
+	defer lg.Debug("finished ShowTokenAttrs()")
+
+	cmn.ShowAttrs(tokenAttrs, TokenAttrMap, filter)
+}
+
+// ShowVerCodeAttrs displays requested backup code attributes
+func ShowVerCodeAttrs(filter string) {
+	lg.Debugw("starting ShowVerCodeAttrs()",
+		"filter", filter)
+	defer lg.Debug("finished ShowVerCodeAttrs()")
+
+	cmn.ShowAttrs(verCodeAttrs, VerCodeAttrMap, filter)
+}